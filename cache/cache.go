@@ -0,0 +1,20 @@
+// Package cache stores serialized query results per app. Entries expire after
+// their TTL, and every entry of an app can be dropped at once when its data
+// changes.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Cache interface {
+	// Get returns the value stored under key for the app, and false when there
+	// is none or it has expired.
+	Get(ctx context.Context, trackingID uuid.UUID, key string) ([]byte, bool, error)
+	Set(ctx context.Context, trackingID uuid.UUID, key string, value []byte, ttl time.Duration) error
+	// Invalidate drops every entry of the app.
+	Invalidate(ctx context.Context, trackingID uuid.UUID) error
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// CacheSuite checks the behaviour every Cache shares.
+type CacheSuite struct {
+	suite.Suite
+	cache Cache
+	ctx   context.Context
+}
+
+func (suite *CacheSuite) TestGetSet() {
+	trackingID := uuid.New()
+
+	_, found, err := suite.cache.Get(suite.ctx, trackingID, "overview")
+	suite.NoError(err)
+	suite.False(found)
+
+	suite.NoError(suite.cache.Set(suite.ctx, trackingID, "overview", []byte(`{"visitors":3}`), time.Minute))
+	value, found, err := suite.cache.Get(suite.ctx, trackingID, "overview")
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal(`{"visitors":3}`, string(value))
+
+	// keys are scoped to the app
+	_, found, err = suite.cache.Get(suite.ctx, uuid.New(), "overview")
+	suite.NoError(err)
+	suite.False(found)
+
+	suite.NoError(suite.cache.Set(suite.ctx, trackingID, "overview", []byte(`{"visitors":4}`), time.Minute))
+	value, _, err = suite.cache.Get(suite.ctx, trackingID, "overview")
+	suite.NoError(err)
+	suite.Equal(`{"visitors":4}`, string(value))
+}
+
+func (suite *CacheSuite) TestInvalidate() {
+	trackingID, other := uuid.New(), uuid.New()
+	suite.NoError(suite.cache.Set(suite.ctx, trackingID, "overview", []byte("a"), time.Minute))
+	suite.NoError(suite.cache.Set(suite.ctx, trackingID, "pages", []byte("b"), time.Minute))
+	suite.NoError(suite.cache.Set(suite.ctx, other, "overview", []byte("c"), time.Minute))
+
+	suite.NoError(suite.cache.Invalidate(suite.ctx, trackingID))
+
+	for _, key := range []string{"overview", "pages"} {
+		_, found, err := suite.cache.Get(suite.ctx, trackingID, key)
+		suite.NoError(err)
+		suite.False(found)
+	}
+	value, found, err := suite.cache.Get(suite.ctx, other, "overview")
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal("c", string(value))
+
+	// the app is cached again after it's invalidated
+	suite.NoError(suite.cache.Set(suite.ctx, trackingID, "overview", []byte("d"), time.Minute))
+	value, found, err = suite.cache.Get(suite.ctx, trackingID, "overview")
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal("d", string(value))
+}
+
+type LRUSuite struct {
+	CacheSuite
+	lru *LRU
+	now time.Time
+}
+
+func (suite *LRUSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.now = time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	suite.lru = NewLRU(3)
+	suite.lru.now = func() time.Time { return suite.now }
+	suite.cache = suite.lru
+}
+
+func (suite *LRUSuite) TestExpiry() {
+	trackingID := uuid.New()
+	suite.NoError(suite.lru.Set(suite.ctx, trackingID, "overview", []byte("a"), time.Minute))
+
+	suite.now = suite.now.Add(59 * time.Second)
+	_, found, _ := suite.lru.Get(suite.ctx, trackingID, "overview")
+	suite.True(found)
+
+	suite.now = suite.now.Add(time.Second)
+	_, found, _ = suite.lru.Get(suite.ctx, trackingID, "overview")
+	suite.False(found)
+	suite.Equal(0, suite.lru.order.Len())
+}
+
+func (suite *LRUSuite) TestEvictsLeastRecentlyUsed() {
+	trackingID := uuid.New()
+	for _, key := range []string{"a", "b", "c"} {
+		suite.NoError(suite.lru.Set(suite.ctx, trackingID, key, []byte(key), time.Minute))
+	}
+
+	// reading a makes b the least recently used
+	_, found, _ := suite.lru.Get(suite.ctx, trackingID, "a")
+	suite.True(found)
+	suite.NoError(suite.lru.Set(suite.ctx, trackingID, "d", []byte("d"), time.Minute))
+
+	for key, cached := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		_, found, _ := suite.lru.Get(suite.ctx, trackingID, key)
+		suite.Equal(cached, found, key)
+	}
+}
+
+func TestLRUSuite(t *testing.T) {
+	suite.Run(t, new(LRUSuite))
+}
+
+type RedisSuite struct {
+	CacheSuite
+	container testcontainers.Container
+	redis     *Redis
+}
+
+func (suite *RedisSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:7-alpine",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections"),
+	}
+	container, err := testcontainers.GenericContainer(suite.ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	suite.Require().NoError(err)
+	suite.container = container
+
+	port, err := container.MappedPort(suite.ctx, "6379/tcp")
+	suite.Require().NoError(err)
+
+	redis, err := NewRedis(fmt.Sprintf("redis://localhost:%s/0", port.Port()))
+	suite.Require().NoError(err)
+	suite.Require().NoError(redis.Ping(suite.ctx))
+	suite.redis = redis
+	suite.cache = redis
+}
+
+func (suite *RedisSuite) TearDownSuite() {
+	suite.NoError(suite.redis.Close())
+	suite.NoError(suite.container.Terminate(suite.ctx))
+}
+
+func (suite *RedisSuite) TestExpiry() {
+	trackingID := uuid.New()
+	suite.NoError(suite.redis.Set(suite.ctx, trackingID, "overview", []byte("a"), time.Minute))
+
+	ttl, err := suite.redis.client.TTL(suite.ctx, "minalytics:cache:"+trackingID.String()+":0:overview").Result()
+	suite.NoError(err)
+	suite.InDelta(time.Minute, ttl, float64(time.Second))
+}
+
+func TestRedisSuite(t *testing.T) {
+	suite.Run(t, new(RedisSuite))
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type lruEntry struct {
+	trackingID uuid.UUID
+	key        string
+	value      []byte
+	expires    time.Time
+}
+
+type lruKey struct {
+	trackingID uuid.UUID
+	key        string
+}
+
+// LRU is an in-memory cache holding up to a fixed number of entries, evicting
+// the least recently used one when full.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[lruKey]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		entries:  make(map[lruKey]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU) Get(_ context.Context, trackingID uuid.UUID, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[lruKey{trackingID, key}]
+	if !exists {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (c *LRU) Set(_ context.Context, trackingID uuid.UUID, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(ttl)
+	if element, exists := c.entries[lruKey{trackingID, key}]; exists {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[lruKey{trackingID, key}] = c.order.PushFront(&lruEntry{trackingID: trackingID, key: key, value: value, expires: expires})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Invalidate(_ context.Context, trackingID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*lruEntry).trackingID == trackingID {
+			c.remove(element)
+		}
+		element = next
+	}
+	return nil
+}
+
+func (c *LRU) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, lruKey{entry.trackingID, entry.key})
+}
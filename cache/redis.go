@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a cache shared by every server using the same Redis. Keys of an app
+// include a generation number, and invalidating the app bumps it, so old
+// entries are never read again and expire on their own.
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis connects to the Redis at url, such as redis://localhost:6379/0.
+func NewRedis(url string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{client: redis.NewClient(options), prefix: "minalytics:cache"}, nil
+}
+
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+func (c *Redis) Get(ctx context.Context, trackingID uuid.UUID, key string) ([]byte, bool, error) {
+	entryKey, err := c.entryKey(ctx, trackingID, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	value, err := c.client.Get(ctx, entryKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, trackingID uuid.UUID, key string, value []byte, ttl time.Duration) error {
+	entryKey, err := c.entryKey(ctx, trackingID, key)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, entryKey, value, ttl).Err()
+}
+
+func (c *Redis) Invalidate(ctx context.Context, trackingID uuid.UUID) error {
+	return c.client.Incr(ctx, c.generationKey(trackingID)).Err()
+}
+
+func (c *Redis) generationKey(trackingID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:generation", c.prefix, trackingID)
+}
+
+func (c *Redis) entryKey(ctx context.Context, trackingID uuid.UUID, key string) (string, error) {
+	generation, err := c.client.Get(ctx, c.generationKey(trackingID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s:%d:%s", c.prefix, trackingID, generation, key), nil
+}
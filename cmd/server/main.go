@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/ScMofeoluwa/minalytics/config"
 	"github.com/ScMofeoluwa/minalytics/server"
@@ -21,5 +22,20 @@ func main() {
 	}
 
 	server := server.New(cfg, logger)
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := server.Export(os.Args[2:], os.Stdout); err != nil {
+			logger.Fatal("Export failed", zap.Error(err))
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := server.Import(os.Args[2:], os.Stdout); err != nil {
+			logger.Fatal("Import failed", zap.Error(err))
+		}
+		return
+	}
+
 	server.Start()
 }
@@ -11,6 +11,9 @@ type Config struct {
 	GithubClientID          string `mapstructure:"GITHUB_CLIENT_ID"`
 	GithubClientSecret      string `mapstructure:"GITHUB_CLIENT_SECRET"`
 	GithubClientCallbackUrl string `mapstructure:"GITHUB_CLIENT_CALLBACK_URL"`
+	EventsCompressAfter     string `mapstructure:"EVENTS_COMPRESS_AFTER"`
+	EventsRetention         string `mapstructure:"EVENTS_RETENTION"`
+	RedisURL                string `mapstructure:"REDIS_URL"`
 }
 
 func LoadConfig() (config Config, err error) {
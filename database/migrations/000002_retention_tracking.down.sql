@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_events_persistent_id;
+
+ALTER TABLE events DROP COLUMN IF EXISTS persistent_id;
+
+ALTER TABLE apps DROP COLUMN IF EXISTS retention_tracking;
@@ -0,0 +1,5 @@
+ALTER TABLE apps ADD COLUMN retention_tracking BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE events ADD COLUMN persistent_id VARCHAR(64);
+
+CREATE INDEX idx_events_persistent_id ON events(tracking_id, persistent_id) WHERE persistent_id IS NOT NULL;
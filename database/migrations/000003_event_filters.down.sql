@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS event_matches_filters(events, JSONB);
+
+DROP FUNCTION IF EXISTS event_dimension(events, TEXT);
@@ -0,0 +1,35 @@
+-- event_dimension resolves a filterable dimension name to its value on an event.
+CREATE OR REPLACE FUNCTION event_dimension(e events, dimension TEXT) RETURNS TEXT AS $$
+  SELECT CASE
+    WHEN dimension = 'page' THEN COALESCE(NULLIF(substring(e.url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+    WHEN dimension = 'hostname' THEN substring(e.url from '^[a-zA-Z]+://([^/:?#]+)')
+    WHEN dimension = 'referrer' THEN e.referrer
+    WHEN dimension = 'country' THEN e.country
+    WHEN dimension = 'browser' THEN e.browser
+    WHEN dimension = 'device' THEN e.device
+    WHEN dimension = 'os' THEN e.operating_system
+    WHEN dimension = 'event' THEN e.event_type
+    WHEN dimension IN ('utm_source', 'utm_medium', 'utm_campaign', 'utm_term', 'utm_content')
+      THEN substring(e.url from '[?&]' || dimension || '=([^&#]*)')
+    WHEN dimension LIKE 'prop:%' THEN e.details ->> substring(dimension from 6)
+  END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- event_matches_filters reports whether an event satisfies every filter in a
+-- JSON array of {"dimension", "operator", "values"} objects. NULL matches all.
+CREATE OR REPLACE FUNCTION event_matches_filters(e events, filters JSONB) RETURNS BOOLEAN AS $$
+  SELECT filters IS NULL OR NOT EXISTS (
+    SELECT 1
+    FROM jsonb_array_elements(filters) f
+    WHERE NOT COALESCE(
+      CASE f->>'operator'
+        WHEN 'is' THEN event_dimension(e, f->>'dimension') = f->'values'->>0
+        WHEN 'is_not' THEN event_dimension(e, f->>'dimension') IS DISTINCT FROM f->'values'->>0
+        WHEN 'contains' THEN strpos(lower(event_dimension(e, f->>'dimension')), lower(f->'values'->>0)) > 0
+        WHEN 'regex' THEN event_dimension(e, f->>'dimension') ~ (f->'values'->>0)
+        WHEN 'any_of' THEN event_dimension(e, f->>'dimension') IN (SELECT jsonb_array_elements_text(f->'values'))
+      END,
+      FALSE
+    )
+  )
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1 @@
+ALTER TABLE apps ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
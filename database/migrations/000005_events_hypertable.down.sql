@@ -0,0 +1,58 @@
+DROP FUNCTION IF EXISTS set_events_retention_policy(INTERVAL);
+DROP FUNCTION IF EXISTS set_events_compression_policy(INTERVAL);
+
+SELECT remove_retention_policy('events', if_exists => TRUE);
+SELECT remove_compression_policy('events', if_exists => TRUE);
+
+-- a hypertable can't be turned back into a plain table, so the events are
+-- copied into a new one. The filter functions take the events row type and
+-- are dropped along with the hypertable, so they are recreated afterwards.
+CREATE TABLE events_plain (LIKE events INCLUDING DEFAULTS);
+INSERT INTO events_plain SELECT * FROM events;
+
+DROP FUNCTION IF EXISTS event_matches_filters(events, JSONB);
+DROP FUNCTION IF EXISTS event_dimension(events, TEXT);
+DROP TABLE events;
+
+ALTER TABLE events_plain RENAME TO events;
+ALTER TABLE events ADD PRIMARY KEY (id);
+ALTER TABLE events ADD CONSTRAINT fk_app FOREIGN KEY (tracking_id) REFERENCES apps(tracking_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_visitor_id ON events(visitor_id);
+CREATE INDEX idx_events_persistent_id ON events(tracking_id, persistent_id) WHERE persistent_id IS NOT NULL;
+
+-- event_dimension resolves a filterable dimension name to its value on an event.
+CREATE OR REPLACE FUNCTION event_dimension(e events, dimension TEXT) RETURNS TEXT AS $$
+  SELECT CASE
+    WHEN dimension = 'page' THEN COALESCE(NULLIF(substring(e.url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+    WHEN dimension = 'hostname' THEN substring(e.url from '^[a-zA-Z]+://([^/:?#]+)')
+    WHEN dimension = 'referrer' THEN e.referrer
+    WHEN dimension = 'country' THEN e.country
+    WHEN dimension = 'browser' THEN e.browser
+    WHEN dimension = 'device' THEN e.device
+    WHEN dimension = 'os' THEN e.operating_system
+    WHEN dimension = 'event' THEN e.event_type
+    WHEN dimension IN ('utm_source', 'utm_medium', 'utm_campaign', 'utm_term', 'utm_content')
+      THEN substring(e.url from '[?&]' || dimension || '=([^&#]*)')
+    WHEN dimension LIKE 'prop:%' THEN e.details ->> substring(dimension from 6)
+  END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- event_matches_filters reports whether an event satisfies every filter in a
+-- JSON array of {"dimension", "operator", "values"} objects. NULL matches all.
+CREATE OR REPLACE FUNCTION event_matches_filters(e events, filters JSONB) RETURNS BOOLEAN AS $$
+  SELECT filters IS NULL OR NOT EXISTS (
+    SELECT 1
+    FROM jsonb_array_elements(filters) f
+    WHERE NOT COALESCE(
+      CASE f->>'operator'
+        WHEN 'is' THEN event_dimension(e, f->>'dimension') = f->'values'->>0
+        WHEN 'is_not' THEN event_dimension(e, f->>'dimension') IS DISTINCT FROM f->'values'->>0
+        WHEN 'contains' THEN strpos(lower(event_dimension(e, f->>'dimension')), lower(f->'values'->>0)) > 0
+        WHEN 'regex' THEN event_dimension(e, f->>'dimension') ~ (f->'values'->>0)
+        WHEN 'any_of' THEN event_dimension(e, f->>'dimension') IN (SELECT jsonb_array_elements_text(f->'values'))
+      END,
+      FALSE
+    )
+  )
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,38 @@
+CREATE EXTENSION IF NOT EXISTS timescaledb;
+
+-- every unique constraint on a hypertable has to include the partitioning column
+ALTER TABLE events DROP CONSTRAINT events_pkey;
+ALTER TABLE events ADD PRIMARY KEY (id, timestamp);
+
+SELECT create_hypertable('events', 'timestamp', chunk_time_interval => INTERVAL '1 day', migrate_data => TRUE);
+
+CREATE INDEX idx_events_tracking_id_timestamp ON events(tracking_id, timestamp DESC);
+CREATE INDEX idx_events_tracking_id_visitor_id_timestamp ON events(tracking_id, visitor_id, timestamp DESC);
+
+ALTER TABLE events SET (
+  timescaledb.compress,
+  timescaledb.compress_segmentby = 'tracking_id',
+  timescaledb.compress_orderby = 'timestamp DESC'
+);
+
+-- set_events_compression_policy compresses chunks once they are older than
+-- compress_after, replacing any previous policy.
+CREATE OR REPLACE FUNCTION set_events_compression_policy(compress_after INTERVAL) RETURNS VOID AS $$
+BEGIN
+  PERFORM remove_compression_policy('events', if_exists => TRUE);
+  PERFORM add_compression_policy('events', compress_after);
+END
+$$ LANGUAGE plpgsql;
+
+-- set_events_retention_policy drops chunks once they are older than
+-- drop_after, replacing any previous policy. NULL keeps events forever.
+CREATE OR REPLACE FUNCTION set_events_retention_policy(drop_after INTERVAL) RETURNS VOID AS $$
+BEGIN
+  PERFORM remove_retention_policy('events', if_exists => TRUE);
+  IF drop_after IS NOT NULL THEN
+    PERFORM add_retention_policy('events', drop_after);
+  END IF;
+END
+$$ LANGUAGE plpgsql;
+
+SELECT set_events_compression_policy(INTERVAL '7 days');
@@ -0,0 +1,4 @@
+DROP VIEW IF EXISTS event_rollups;
+
+DROP MATERIALIZED VIEW IF EXISTS events_daily;
+DROP MATERIALIZED VIEW IF EXISTS events_hourly;
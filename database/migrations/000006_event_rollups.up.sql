@@ -0,0 +1,37 @@
+-- events_hourly keeps one row per visitor and combination of dimensions in
+-- each hour, so distinct visitors stay exact when rows are combined across
+-- buckets. Real-time aggregation fills in what hasn't been materialized yet.
+CREATE MATERIALIZED VIEW events_hourly
+WITH (timescaledb.continuous, timescaledb.materialized_only = FALSE) AS
+SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp) AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  SUM(CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END)::bigint AS pageviews,
+  SUM(CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END)::bigint AS events
+FROM events
+GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW events_daily
+WITH (timescaledb.continuous, timescaledb.materialized_only = FALSE) AS
+SELECT tracking_id, time_bucket(INTERVAL '1 day', bucket) AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  SUM(pageviews)::bigint AS pageviews,
+  SUM(events)::bigint AS events
+FROM events_hourly
+GROUP BY tracking_id, time_bucket(INTERVAL '1 day', bucket), visitor_id, url, referrer, country, browser, device, operating_system
+WITH NO DATA;
+
+CREATE INDEX idx_events_hourly_tracking_id_bucket ON events_hourly(tracking_id, bucket DESC);
+CREATE INDEX idx_events_daily_tracking_id_bucket ON events_daily(tracking_id, bucket DESC);
+
+SELECT add_continuous_aggregate_policy('events_hourly',
+  start_offset => INTERVAL '3 days', end_offset => INTERVAL '1 hour', schedule_interval => INTERVAL '30 minutes');
+SELECT add_continuous_aggregate_policy('events_daily',
+  start_offset => INTERVAL '7 days', end_offset => INTERVAL '1 day', schedule_interval => INTERVAL '1 hour');
+
+-- event_rollups lets one query read either rollup. The granularity condition
+-- is constant, so the planner only scans the requested one.
+CREATE VIEW event_rollups AS
+SELECT 'hour'::text AS granularity, * FROM events_hourly
+UNION ALL
+SELECT 'day'::text AS granularity, * FROM events_daily;
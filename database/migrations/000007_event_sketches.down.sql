@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sketch_watermark;
+DROP TABLE IF EXISTS event_sketches;
@@ -0,0 +1,25 @@
+-- event_sketches holds a HyperLogLog sketch of the visitors of every
+-- dimension value in each hour, serialized by the hll package, next to exact
+-- pageview and event counts. Sketches merge across hours and values, so
+-- approximate unique visitors over any range don't have to read visitor ids.
+-- The 'all' dimension has a single '' value covering every visitor.
+CREATE TABLE IF NOT EXISTS event_sketches (
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  bucket TIMESTAMPTZ NOT NULL,
+  dimension TEXT NOT NULL,
+  value TEXT NOT NULL,
+  visitors BYTEA NOT NULL,
+  pageviews BIGINT NOT NULL,
+  events BIGINT NOT NULL,
+  PRIMARY KEY (tracking_id, dimension, bucket, value)
+);
+
+-- sketch_watermark is the end of the last hour sketches were built for. Hours
+-- after it are read from events_hourly until the builder catches up.
+CREATE TABLE IF NOT EXISTS sketch_watermark (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  built_until TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO sketch_watermark (built_until)
+SELECT date_trunc('hour', COALESCE(MIN(timestamp), NOW())) FROM events;
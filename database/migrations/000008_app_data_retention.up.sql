@@ -0,0 +1,3 @@
+-- data_retention is how long raw events of the app are kept, as a Postgres
+-- interval such as '30 days' or '13 months'. NULL keeps them forever.
+ALTER TABLE apps ADD COLUMN data_retention TEXT;
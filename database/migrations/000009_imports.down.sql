@@ -0,0 +1,20 @@
+CREATE OR REPLACE FUNCTION event_dimension(e events, dimension TEXT) RETURNS TEXT AS $$
+  SELECT CASE
+    WHEN dimension = 'page' THEN COALESCE(NULLIF(substring(e.url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+    WHEN dimension = 'hostname' THEN substring(e.url from '^[a-zA-Z]+://([^/:?#]+)')
+    WHEN dimension = 'referrer' THEN e.referrer
+    WHEN dimension = 'country' THEN e.country
+    WHEN dimension = 'browser' THEN e.browser
+    WHEN dimension = 'device' THEN e.device
+    WHEN dimension = 'os' THEN e.operating_system
+    WHEN dimension = 'event' THEN e.event_type
+    WHEN dimension IN ('utm_source', 'utm_medium', 'utm_campaign', 'utm_term', 'utm_content')
+      THEN substring(e.url from '[?&]' || dimension || '=([^&#]*)')
+    WHEN dimension LIKE 'prop:%' THEN e.details ->> substring(dimension from 6)
+  END
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE apps DROP COLUMN IF EXISTS imported_since;
+DROP INDEX IF EXISTS idx_events_import_id;
+ALTER TABLE events DROP COLUMN IF EXISTS import_id;
+DROP TABLE IF EXISTS imports;
@@ -0,0 +1,50 @@
+-- imports are the imports of other tools' exports into the events of an app.
+-- The uploaded export is kept in file until the import finishes. progress goes
+-- from 0 to 1, and start_date and end_date bound the events imported so far.
+CREATE TABLE IF NOT EXISTS imports (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  source TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  file TEXT NOT NULL,
+  hostname TEXT,
+  website_id TEXT,
+  progress DOUBLE PRECISION NOT NULL DEFAULT 0,
+  imported_events BIGINT NOT NULL DEFAULT 0,
+  skipped_events BIGINT NOT NULL DEFAULT 0,
+  start_date TIMESTAMPTZ,
+  end_date TIMESTAMPTZ,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_imports_tracking_id ON imports(tracking_id, created_at DESC);
+
+-- import_id is the import an event came from, NULL for tracked events.
+ALTER TABLE events ADD COLUMN import_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_events_import_id ON events(import_id, timestamp) WHERE import_id IS NOT NULL;
+
+-- imported_since is the start of the earliest import into the app, which can
+-- be long before it was created.
+ALTER TABLE apps ADD COLUMN imported_since TIMESTAMPTZ;
+
+-- the imported dimension is 'true' for imported events and 'false' for
+-- tracked ones.
+CREATE OR REPLACE FUNCTION event_dimension(e events, dimension TEXT) RETURNS TEXT AS $$
+  SELECT CASE
+    WHEN dimension = 'page' THEN COALESCE(NULLIF(substring(e.url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+    WHEN dimension = 'hostname' THEN substring(e.url from '^[a-zA-Z]+://([^/:?#]+)')
+    WHEN dimension = 'referrer' THEN e.referrer
+    WHEN dimension = 'country' THEN e.country
+    WHEN dimension = 'browser' THEN e.browser
+    WHEN dimension = 'device' THEN e.device
+    WHEN dimension = 'os' THEN e.operating_system
+    WHEN dimension = 'event' THEN e.event_type
+    WHEN dimension = 'imported' THEN (e.import_id IS NOT NULL)::text
+    WHEN dimension IN ('utm_source', 'utm_medium', 'utm_campaign', 'utm_term', 'utm_content')
+      THEN substring(e.url from '[?&]' || dimension || '=([^&#]*)')
+    WHEN dimension LIKE 'prop:%' THEN e.details ->> substring(dimension from 6)
+  END
+$$ LANGUAGE SQL IMMUTABLE;
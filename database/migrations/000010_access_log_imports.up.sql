@@ -0,0 +1,3 @@
+-- log_format is the format of the lines of access_log imports, see the
+-- importer's ParseLogFormat. NULL reads the combined format.
+ALTER TABLE imports ADD COLUMN log_format TEXT;
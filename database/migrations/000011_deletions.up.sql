@@ -0,0 +1,26 @@
+-- deletions audit the deletions of an app's events: who asked for them, which
+-- events they cover and how many were deleted. Events match every criterion
+-- given: the range from start_date to end_date, the filters, as in the
+-- analytics endpoints, and visitor_id. Dry runs are recorded too, with the
+-- events they matched. first_event and last_event bound the matched events,
+-- which is the range the rollups and sketches are rebuilt over.
+CREATE TABLE IF NOT EXISTS deletions (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status TEXT NOT NULL,
+  start_date TIMESTAMPTZ,
+  end_date TIMESTAMPTZ,
+  filters JSONB,
+  visitor_id TEXT,
+  matched_events BIGINT NOT NULL DEFAULT 0,
+  matched_visitors BIGINT NOT NULL DEFAULT 0,
+  deleted_events BIGINT NOT NULL DEFAULT 0,
+  first_event TIMESTAMPTZ,
+  last_event TIMESTAMPTZ,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_deletions_tracking_id ON deletions(tracking_id, created_at DESC);
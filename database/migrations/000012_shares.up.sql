@@ -0,0 +1,18 @@
+-- shares are secret links granting read-only access to the stats of an app
+-- without an account. Only the SHA-256 hash of a link's token is stored, along
+-- with the bcrypt hash of its password, if it has one. endpoints lists the
+-- analytics endpoints the link opens up. Revoked links are kept for listing.
+CREATE TABLE IF NOT EXISTS shares (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  password_hash TEXT,
+  endpoints TEXT[] NOT NULL,
+  expires_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_tracking_id ON shares(tracking_id, created_at DESC);
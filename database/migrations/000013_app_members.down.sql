@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS app_invitations;
+DROP TABLE IF EXISTS app_members;
@@ -0,0 +1,31 @@
+-- app_members gives users access to apps with a role. Owners can do anything,
+-- admins manage the members, data and share links of the app, editors its
+-- settings and imports, and viewers read its stats. The owner is also the
+-- user_id of the app.
+CREATE TABLE IF NOT EXISTS app_members (
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'editor', 'viewer')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tracking_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_members_user_id ON app_members(user_id);
+
+INSERT INTO app_members (tracking_id, user_id, role, created_at)
+SELECT tracking_id, user_id, 'owner', created_at FROM apps
+ON CONFLICT DO NOTHING;
+
+-- app_invitations invite people to apps by email. The user signed in with
+-- that email accepts them, which makes them members with the role given.
+CREATE TABLE IF NOT EXISTS app_invitations (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  email VARCHAR(255) NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('admin', 'editor', 'viewer')),
+  invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (tracking_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_invitations_email ON app_invitations(email);
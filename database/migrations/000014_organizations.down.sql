@@ -0,0 +1,7 @@
+ALTER TABLE apps DROP CONSTRAINT IF EXISTS unique_org_app_name;
+ALTER TABLE apps ADD CONSTRAINT unique_user_app_name UNIQUE (user_id, name);
+ALTER TABLE apps DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+DROP FUNCTION IF EXISTS role_rank(TEXT);
@@ -0,0 +1,45 @@
+-- role_rank orders the roles of app and organization members, so the higher
+-- of the two roles a user has in an app wins.
+CREATE OR REPLACE FUNCTION role_rank(role TEXT) RETURNS INT AS $$
+  SELECT CASE role WHEN 'owner' THEN 4 WHEN 'admin' THEN 3 WHEN 'editor' THEN 2 WHEN 'viewer' THEN 1 ELSE 0 END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- organizations own apps, and their members have their role in every app of
+-- the organization. Each user has a personal organization, which their apps
+-- go into unless they pick another one.
+CREATE TABLE IF NOT EXISTS organizations (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  personal_user_id UUID UNIQUE REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+  org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'editor', 'viewer')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+
+INSERT INTO organizations (name, personal_user_id)
+SELECT 'Personal', id FROM users
+ON CONFLICT DO NOTHING;
+
+INSERT INTO organization_members (org_id, user_id, role)
+SELECT id, personal_user_id, 'owner' FROM organizations WHERE personal_user_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- existing apps move into the personal organization of their owner, and app
+-- names become unique per organization rather than per user
+ALTER TABLE apps ADD COLUMN IF NOT EXISTS org_id UUID REFERENCES organizations(id);
+
+UPDATE apps a SET org_id = o.id
+FROM organizations o
+WHERE o.personal_user_id = a.user_id AND a.org_id IS NULL;
+
+ALTER TABLE apps ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE apps DROP CONSTRAINT IF EXISTS unique_user_app_name;
+ALTER TABLE apps ADD CONSTRAINT unique_org_app_name UNIQUE (org_id, name);
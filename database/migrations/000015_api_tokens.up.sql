@@ -0,0 +1,18 @@
+-- api_tokens let scripts and BI tools call the API as a user, with the scopes
+-- given and, when tracking_ids isn't empty, only for those apps. Like share
+-- links, they are kept as the SHA-256 of their token.
+CREATE TABLE IF NOT EXISTS api_tokens (
+  id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT[] NOT NULL,
+  tracking_ids UUID[] NOT NULL DEFAULT '{}',
+  expires_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  last_used_ip TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id, created_at DESC);
@@ -0,0 +1,8 @@
+DELETE FROM deletions WHERE user_id IS NULL OR tracking_id NOT IN (SELECT tracking_id FROM apps);
+ALTER TABLE deletions DROP CONSTRAINT IF EXISTS deletions_user_id_fkey;
+ALTER TABLE deletions ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE deletions ADD CONSTRAINT deletions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE deletions ADD CONSTRAINT deletions_tracking_id_fkey FOREIGN KEY (tracking_id) REFERENCES apps(tracking_id) ON DELETE CASCADE;
+
+ALTER TABLE deletions DROP COLUMN IF EXISTS user_email;
+ALTER TABLE deletions DROP COLUMN IF EXISTS app_name;
@@ -0,0 +1,14 @@
+-- deletions outlive the apps and users they were asked for, as the audit of
+-- what was deleted. The app keeps its tracking ID, and the name of the app
+-- and the email of the user are kept alongside, as both may be gone.
+ALTER TABLE deletions ADD COLUMN IF NOT EXISTS app_name VARCHAR(255);
+ALTER TABLE deletions ADD COLUMN IF NOT EXISTS user_email VARCHAR(255);
+UPDATE deletions d SET app_name = a.name FROM apps a WHERE a.tracking_id = d.tracking_id;
+UPDATE deletions d SET user_email = u.email FROM users u WHERE u.id = d.user_id;
+ALTER TABLE deletions ALTER COLUMN app_name SET NOT NULL;
+ALTER TABLE deletions ALTER COLUMN user_email SET NOT NULL;
+
+ALTER TABLE deletions DROP CONSTRAINT IF EXISTS deletions_tracking_id_fkey;
+ALTER TABLE deletions DROP CONSTRAINT IF EXISTS deletions_user_id_fkey;
+ALTER TABLE deletions ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE deletions ADD CONSTRAINT deletions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+-- events_hourly and events_daily kept a row per visitor and combination of
+-- dimensions, close to one row per raw event. Per-dimension pageviews and
+-- events are already counted in event_sketches along with the visitors
+-- sketch, so the rollups are dropped and sketches read raw events instead.
+DROP VIEW IF EXISTS event_rollups;
+
+DROP MATERIALIZED VIEW IF EXISTS events_daily;
+DROP MATERIALIZED VIEW IF EXISTS events_hourly;
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS imported_values(UUID, TEXT, JSONB, TIMESTAMPTZ, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS imported_matches_filters(JSONB);
+DROP TABLE IF EXISTS imported_aggregates;
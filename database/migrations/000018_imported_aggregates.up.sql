@@ -0,0 +1,48 @@
+-- imported_aggregates hold the daily aggregates of Google Analytics and
+-- Plausible imports, which have no events. bucket is the start of the day in
+-- the app's timezone, dimension is named after the events column holding it,
+-- or 'all' for the totals of the day, whose value is ''. Reports add them to
+-- the counts of tracked events.
+CREATE TABLE IF NOT EXISTS imported_aggregates (
+  import_id UUID NOT NULL REFERENCES imports(id) ON DELETE CASCADE,
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  bucket TIMESTAMPTZ NOT NULL,
+  dimension TEXT NOT NULL,
+  value TEXT NOT NULL,
+  visitors BIGINT NOT NULL,
+  pageviews BIGINT NOT NULL,
+  PRIMARY KEY (import_id, dimension, bucket, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_imported_aggregates_tracking_id ON imported_aggregates(tracking_id, dimension, bucket);
+
+-- imported_matches_filters reports whether imported aggregates satisfy every
+-- filter, like event_matches_filters does for events. Aggregates only know
+-- they were imported, so only filters on the imported dimension can match.
+CREATE OR REPLACE FUNCTION imported_matches_filters(filters JSONB) RETURNS BOOLEAN AS $$
+  SELECT filters IS NULL OR NOT EXISTS (
+    SELECT 1
+    FROM jsonb_array_elements(filters) f
+    WHERE NOT COALESCE(
+      f->>'dimension' = 'imported' AND CASE f->>'operator'
+        WHEN 'is' THEN f->'values'->>0 = 'true'
+        WHEN 'is_not' THEN f->'values'->>0 IS DISTINCT FROM 'true'
+        WHEN 'contains' THEN strpos('true', lower(f->'values'->>0)) > 0
+        WHEN 'regex' THEN 'true' ~ (f->'values'->>0)
+        WHEN 'any_of' THEN 'true' IN (SELECT jsonb_array_elements_text(f->'values'))
+      END,
+      FALSE
+    )
+  )
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- imported_values returns the imported aggregates of dimension in an app
+-- whose days start in the range, when they match filters. Without a range,
+-- which stands for the last 24 hours, there are none.
+CREATE OR REPLACE FUNCTION imported_values(app UUID, dim TEXT, filters JSONB, start_date TIMESTAMPTZ, end_date TIMESTAMPTZ)
+RETURNS TABLE (bucket TIMESTAMPTZ, value TEXT, visitors BIGINT, pageviews BIGINT) AS $$
+  SELECT i.bucket, i.value, i.visitors, i.pageviews
+  FROM imported_aggregates i
+  WHERE i.tracking_id = app AND i.dimension = dim AND i.bucket >= start_date AND i.bucket < end_date AND
+    imported_matches_filters(filters)
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,3 @@
+ALTER TABLE imports DROP COLUMN IF EXISTS heartbeat_at;
+ALTER TABLE imports DROP COLUMN IF EXISTS locked_by;
+ALTER TABLE imports DROP COLUMN IF EXISTS file_host;
@@ -0,0 +1,9 @@
+-- file_host is the host whose filesystem holds the file of an import, the only
+-- one that can run it. NULL lets any host run it.
+ALTER TABLE imports ADD COLUMN file_host TEXT;
+
+-- locked_by is the process running an import, which renews heartbeat_at while
+-- it runs. Running imports whose heartbeat went stale were interrupted, and
+-- any process can undo and queue them again.
+ALTER TABLE imports ADD COLUMN locked_by TEXT;
+ALTER TABLE imports ADD COLUMN heartbeat_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+-- the dropped sketches only counted custom events, which pages leave out
@@ -0,0 +1,4 @@
+-- pages only count pageviews, so the pages sketched from custom events, which
+-- are stored with an empty URL, are dropped along with the events of pages.
+DELETE FROM event_sketches WHERE dimension = 'url' AND pageviews = 0;
+UPDATE event_sketches SET events = 0 WHERE dimension = 'url' AND events <> 0;
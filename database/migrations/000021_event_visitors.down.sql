@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION set_events_retention_policy(drop_after INTERVAL) RETURNS VOID AS $$
+BEGIN
+  PERFORM remove_retention_policy('events', if_exists => TRUE);
+  IF drop_after IS NOT NULL THEN
+    PERFORM add_retention_policy('events', drop_after);
+  END IF;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS event_visitors;
@@ -0,0 +1,54 @@
+-- event_visitors keeps a row per visitor of every dimension value in each
+-- hour, with the pageviews and events they made, so exact unique visitors over
+-- long ranges don't have to read every raw event. The sketch builder fills it
+-- along with event_sketches, and reports read it up to the sketch watermark.
+-- Dimensions and values are those of event_sketches.
+CREATE TABLE IF NOT EXISTS event_visitors (
+  tracking_id UUID NOT NULL REFERENCES apps(tracking_id) ON DELETE CASCADE,
+  bucket TIMESTAMPTZ NOT NULL,
+  dimension TEXT NOT NULL,
+  value TEXT NOT NULL,
+  visitor_id TEXT NOT NULL,
+  pageviews BIGINT NOT NULL,
+  events BIGINT NOT NULL,
+  PRIMARY KEY (tracking_id, dimension, bucket, value, visitor_id)
+);
+
+SELECT create_hypertable('event_visitors', 'bucket', chunk_time_interval => INTERVAL '1 day');
+
+INSERT INTO event_visitors (tracking_id, bucket, dimension, value, visitor_id, pageviews, events)
+SELECT i.tracking_id, i.bucket, d.dimension, d.value, i.visitor_id, SUM(d.pageviews), SUM(d.events)
+FROM (
+  SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp) AS bucket, visitor_id,
+    url, referrer, country, browser, device, operating_system,
+    COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+    COUNT(*) FILTER (WHERE event_type <> 'pageview') AS events
+  FROM events
+  WHERE timestamp < (SELECT built_until FROM sketch_watermark)
+  GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system
+) i
+CROSS JOIN LATERAL (VALUES
+  ('all', '', i.pageviews, i.events),
+  -- pages only count pageviews
+  ('url', CASE WHEN i.pageviews > 0 THEN i.url END, i.pageviews, 0),
+  ('referrer', i.referrer, i.pageviews, i.events),
+  ('country', i.country, i.pageviews, i.events),
+  ('browser', i.browser, i.pageviews, i.events),
+  ('device', i.device, i.pageviews, i.events),
+  ('operating_system', i.operating_system, i.pageviews, i.events)
+) AS d(dimension, value, pageviews, events)
+WHERE d.value IS NOT NULL
+GROUP BY i.tracking_id, i.bucket, d.dimension, d.value, i.visitor_id;
+
+-- the visitors of an hour are raw events as much as the events themselves,
+-- so EVENTS_RETENTION drops them too
+CREATE OR REPLACE FUNCTION set_events_retention_policy(drop_after INTERVAL) RETURNS VOID AS $$
+BEGIN
+  PERFORM remove_retention_policy('events', if_exists => TRUE);
+  PERFORM remove_retention_policy('event_visitors', if_exists => TRUE);
+  IF drop_after IS NOT NULL THEN
+    PERFORM add_retention_policy('events', drop_after);
+    PERFORM add_retention_policy('event_visitors', drop_after);
+  END IF;
+END
+$$ LANGUAGE plpgsql;
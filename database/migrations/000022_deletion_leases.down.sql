@@ -0,0 +1,2 @@
+ALTER TABLE deletions DROP COLUMN IF EXISTS heartbeat_at;
+ALTER TABLE deletions DROP COLUMN IF EXISTS locked_by;
@@ -0,0 +1,5 @@
+-- locked_by is the process running a deletion, which renews heartbeat_at while
+-- it runs. Running deletions whose heartbeat went stale were interrupted, and
+-- any process can take them over and run them again.
+ALTER TABLE deletions ADD COLUMN locked_by TEXT;
+ALTER TABLE deletions ADD COLUMN heartbeat_at TIMESTAMPTZ;
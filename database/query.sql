@@ -7,115 +7,1146 @@ SET email = EXCLUDED.email
 RETURNING id;
 
 -- name: CreateApp :one
-INSERT INTO apps (
-  name, user_id
-) VALUES ( $1, $2 )
-RETURNING *;
+WITH app AS (
+  INSERT INTO apps (
+    name, user_id, org_id
+  ) VALUES ( $1, $2, $3 )
+  RETURNING *
+), owner AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  SELECT tracking_id, user_id, 'owner' FROM app
+)
+SELECT * FROM app;
 
 -- name: CreateEvent :exec
 INSERT INTO events (
-  visitor_id, tracking_id, event_type, url, referrer, country, browser, device, operating_system, details
-) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 );
+  visitor_id, tracking_id, event_type, url, referrer, country, browser, device, operating_system, details, persistent_id
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11 );
 
 -- name: UpdateApp :one
 UPDATE apps
-SET name = $1
-WHERE tracking_id = $2
+SET name = COALESCE(sqlc.narg(name), name),
+  retention_tracking = COALESCE(sqlc.narg(retention_tracking), retention_tracking),
+  timezone = COALESCE(sqlc.narg(timezone), timezone),
+  data_retention = CASE
+    WHEN sqlc.narg(data_retention)::text IS NULL THEN data_retention
+    WHEN sqlc.narg(data_retention)::text = 'forever' THEN NULL
+    ELSE sqlc.narg(data_retention)::text
+  END
+WHERE tracking_id = sqlc.arg(tracking_id)
 RETURNING *;
 
 -- name: DeleteApp :exec
+WITH abandoned AS (
+  UPDATE deletions SET status = 'failed', error = 'the app was deleted', finished_at = NOW()
+  WHERE tracking_id = $1 AND status IN ('pending', 'running')
+)
 DELETE FROM apps WHERE tracking_id = $1;
 
 -- name: GetAppByTrackingID :one
 SELECT * FROM apps WHERE tracking_id = $1;
 
 -- name: CheckAppExists :one
-SELECT * FROM apps WHERE user_id = $1 AND name = $2;
+SELECT * FROM apps WHERE org_id = $1 AND name = $2;
 
 -- name: GetApps :many
-SELECT * FROM apps WHERE user_id = $1;
+SELECT a.*, r.role
+FROM apps a
+JOIN LATERAL (
+  SELECT role FROM (
+    SELECT m.role FROM app_members m WHERE m.tracking_id = a.tracking_id AND m.user_id = $1
+    UNION ALL
+    SELECT o.role FROM organization_members o WHERE o.org_id = a.org_id AND o.user_id = $1
+  ) roles
+  ORDER BY role_rank(role) DESC
+  LIMIT 1
+) r ON TRUE
+ORDER BY a.created_at;
 
 -- name: GetVisitors :many
-SELECT time_bucket($4, timestamp::timestamptz)::timestamptz AS time, COUNT(DISTINCT visitor_id) AS visitors
-FROM events WHERE tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
-GROUP BY time;
+-- imported days count in the bucket they start in. With rollup, the hours
+-- sketches were built for are read from event_visitors, which keeps a row per
+-- visitor in each hour, and only later hours from raw events.
+SELECT time, SUM(visitors)::bigint AS visitors
+FROM (
+  SELECT time_bucket_gapfill(sqlc.arg(time_bucket), timestamp, sqlc.arg(timezone)::text, sqlc.arg(start_date)::timestamptz, sqlc.arg(end_date)::timestamptz)::timestamptz AS time,
+    COALESCE(COUNT(DISTINCT visitor_id), 0)::bigint AS visitors
+  FROM (
+    SELECT timestamp, visitor_id
+    FROM events e WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+      timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date) AND
+      (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+    UNION ALL
+    SELECT bucket, visitor_id
+    FROM event_visitors
+    WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'all' AND
+      bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+  ) v
+  GROUP BY 1
+  UNION ALL
+  SELECT time_bucket(sqlc.arg(time_bucket), bucket, sqlc.arg(timezone)::text)::timestamptz, visitors
+  FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+) t
+GROUP BY time
+ORDER BY time;
 
 -- name: GetPageViews :many
-SELECT time_bucket($4, timestamp::timestamptz)::timestamptz AS time, COUNT(url) AS views
-FROM events WHERE tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
-GROUP BY time;
+SELECT time, SUM(views)::bigint AS views
+FROM (
+  SELECT time_bucket_gapfill(sqlc.arg(time_bucket), timestamp, sqlc.arg(timezone)::text, sqlc.arg(start_date)::timestamptz, sqlc.arg(end_date)::timestamptz)::timestamptz AS time,
+    COALESCE(COUNT(*) FILTER (WHERE event_type = 'pageview'), 0)::bigint AS views
+  FROM events e WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date)
+  GROUP BY 1
+  UNION ALL
+  SELECT time_bucket(sqlc.arg(time_bucket), bucket, sqlc.arg(timezone)::text)::timestamptz, pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+) t
+GROUP BY time
+ORDER BY time;
 
 -- name: GetReferrals :many
-SELECT referrer, COUNT(DISTINCT visitor_id) AS visitor_count
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE referrer IS NOT NULL AND a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT referrer, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE referrer IS NOT NULL AND a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'referrer' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS referrer, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'referrer', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT referrer, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT referrer, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY referrer
+    UNION ALL
+    SELECT referrer, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY referrer
+), ranked AS (
+  SELECT referrer, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN referrer END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN referrer END DESC,
+      visitors DESC, referrer) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.referrer = r.referrer
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.referrer = r.referrer
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
 )
-GROUP BY referrer
-ORDER BY visitor_count DESC;
+SELECT referrer, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  referrer = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT NULL, 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT NULL, o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
 
 -- name: GetPages :many
-SELECT url, COUNT(DISTINCT visitor_id) AS visitor_count
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE url IS NOT NULL AND e.event_type = 'pageview' AND a.tracking_id = $1 AND 
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT url, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  -- custom events are stored with an empty URL, so only pageviews make pages
+  WHERE url IS NOT NULL AND e.event_type = 'pageview' AND a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'url' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS url, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'url', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT url, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT url, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY url
+    UNION ALL
+    SELECT url, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY url
+), ranked AS (
+  SELECT url, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN url END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN url END DESC,
+      visitors DESC, url) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.url = r.url
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.url = r.url
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
 )
-GROUP BY url
-ORDER BY visitor_count DESC;
+SELECT url, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  url = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT NULL, 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT NULL, o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
 
 -- name: GetCountries :many
-SELECT country, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT country, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'country' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS country, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'country', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT country, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT country, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY country
+    UNION ALL
+    SELECT country, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY country
+), ranked AS (
+  SELECT country, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN country END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN country END DESC,
+      visitors DESC, country) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.country = r.country
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.country = r.country
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
 )
-GROUP BY country
-ORDER BY percentage DESC;
+SELECT country, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  country = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
 
 -- name: GetBrowsers :many
-SELECT browser, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT browser, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'browser' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS browser, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'browser', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT browser, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT browser, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY browser
+    UNION ALL
+    SELECT browser, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY browser
+), ranked AS (
+  SELECT browser, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN browser END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN browser END DESC,
+      visitors DESC, browser) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.browser = r.browser
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.browser = r.browser
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
 )
-GROUP BY browser
-ORDER BY percentage DESC;
+SELECT browser, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  browser = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
 
 -- name: GetDevices :many
-SELECT device, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT device, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'device' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS device, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'device', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT device, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT device, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY device
+    UNION ALL
+    SELECT device, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY device
+), ranked AS (
+  SELECT device, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN device END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN device END DESC,
+      visitors DESC, device) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.device = r.device
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.device = r.device
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
 )
-GROUP BY device
-ORDER BY percentage DESC;
+SELECT device, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  device = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
 
 -- name: GetOS :many
-SELECT operating_system, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+WITH scoped AS (
+  SELECT operating_system, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+    (
+      (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+    ) AND
+    (NOT sqlc.arg(rollup)::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE sqlc.arg(rollup)::boolean AND tracking_id = sqlc.arg(tracking_id) AND dimension = 'operating_system' AND
+    bucket >= sqlc.arg(start_date) AND bucket < LEAST(sqlc.arg(end_date), (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS operating_system, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'operating_system', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT operating_system, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT operating_system, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY operating_system
+    UNION ALL
+    SELECT operating_system, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY operating_system
+), ranked AS (
+  SELECT operating_system, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN sqlc.arg(sort)::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:asc' THEN operating_system END ASC,
+      CASE WHEN sqlc.arg(sort)::text = 'name:desc' THEN operating_system END DESC,
+      visitors DESC, operating_system) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.operating_system = r.operating_system
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.operating_system = r.operating_system
+    WHERE r.position > sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint
+  ) parts
+)
+SELECT operating_system, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE (sqlc.narg(keys)::text[] IS NULL AND position > sqlc.arg(page_offset)::bigint AND position <= sqlc.arg(page_offset)::bigint + sqlc.arg(page_limit)::bigint) OR
+  operating_system = ANY(sqlc.narg(keys)::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE sqlc.narg(keys)::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE sqlc.arg(include_other)::boolean AND o.position IS NOT NULL
+ORDER BY position;
+
+-- name: GetRetentionCohorts :many
+WITH activity AS (
+  SELECT DISTINCT persistent_id, date_trunc(sqlc.arg(period)::text, timestamp, sqlc.arg(timezone)::text) AS period_start
+  FROM events e
+  WHERE tracking_id = sqlc.arg(tracking_id) AND persistent_id IS NOT NULL AND event_matches_filters(e, sqlc.arg(filters)::jsonb)
+),
+cohorts AS (
+  SELECT persistent_id, MIN(period_start) AS cohort
+  FROM activity
+  GROUP BY persistent_id
+  HAVING MIN(period_start) BETWEEN sqlc.arg(start_date)::timestamptz AND sqlc.arg(end_date)::timestamptz
 )
-GROUP BY operating_system
-ORDER BY percentage DESC;
+SELECT c.cohort::timestamptz AS cohort,
+  (CASE WHEN sqlc.arg(period)::text = 'week'
+    THEN EXTRACT(DAY FROM a.period_start - c.cohort) / 7
+    ELSE EXTRACT(YEAR FROM age(a.period_start, c.cohort)) * 12 + EXTRACT(MONTH FROM age(a.period_start, c.cohort))
+  END)::int AS period_offset,
+  COUNT(DISTINCT a.persistent_id) AS visitors
+FROM cohorts c JOIN activity a ON a.persistent_id = c.persistent_id
+GROUP BY c.cohort, period_offset
+ORDER BY c.cohort, period_offset;
+
+-- name: GetUserFlow :many
+WITH steps AS (
+  SELECT visitor_id,
+    CASE WHEN event_type = 'pageview'
+      THEN COALESCE(NULLIF(substring(url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+      ELSE event_type
+    END AS step,
+    ROW_NUMBER() OVER (PARTITION BY visitor_id ORDER BY timestamp) AS position
+  FROM events e
+  WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+  (
+    (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+  )
+),
+anchors AS (
+  SELECT visitor_id, MIN(position) AS position
+  FROM steps
+  WHERE step = sqlc.arg(step)::text
+  GROUP BY visitor_id
+),
+paths AS (
+  SELECT s.visitor_id, s.step, (s.position - a.position)::int AS depth,
+    LAG(s.step) OVER (PARTITION BY s.visitor_id ORDER BY s.position) AS previous_step
+  FROM steps s JOIN anchors a ON a.visitor_id = s.visitor_id
+  WHERE s.position - a.position BETWEEN sqlc.arg(min_depth)::int AND sqlc.arg(max_depth)::int
+)
+SELECT depth, COALESCE(previous_step, '')::text AS previous_step, step::text AS step, COUNT(DISTINCT visitor_id) AS visitors
+FROM paths
+GROUP BY depth, previous_step, step
+ORDER BY depth, visitors DESC;
+
+-- name: GetEventTypes :many
+SELECT event_type, COUNT(DISTINCT visitor_id)::bigint AS visitors, COUNT(*)::bigint AS events
+FROM events e
+WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+  (
+    (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+  )
+GROUP BY event_type
+ORDER BY events DESC, event_type;
+
+-- name: GetOverview :one
+WITH scoped AS (
+  SELECT visitor_id, event_type, timestamp
+  FROM events e
+  WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.arg(filters)::jsonb) AND
+  (
+    (sqlc.arg(start_date)::timestamptz IS NULL AND sqlc.arg(end_date)::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date))
+  )
+),
+marked AS (
+  SELECT visitor_id, event_type, timestamp,
+    CASE WHEN timestamp - LAG(timestamp) OVER (PARTITION BY visitor_id ORDER BY timestamp) <= INTERVAL '30 minutes' THEN 0 ELSE 1 END AS starts_visit
+  FROM scoped
+),
+numbered AS (
+  SELECT visitor_id, event_type, timestamp,
+    SUM(starts_visit) OVER (PARTITION BY visitor_id ORDER BY timestamp) AS visit
+  FROM marked
+),
+visits AS (
+  SELECT visitor_id, visit,
+    COUNT(*) AS events,
+    COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+    EXTRACT(EPOCH FROM MAX(timestamp) - MIN(timestamp)) AS duration
+  FROM numbered
+  GROUP BY visitor_id, visit
+),
+imported AS (
+  SELECT COALESCE(SUM(visitors), 0)::bigint AS visitors, COALESCE(SUM(pageviews), 0)::bigint AS pageviews
+  FROM imported_values(sqlc.arg(tracking_id), 'all', sqlc.arg(filters)::jsonb, sqlc.arg(start_date), sqlc.arg(end_date))
+)
+SELECT COUNT(DISTINCT visitor_id) AS visitors,
+  COALESCE(SUM(pageviews), 0)::bigint AS pageviews,
+  COUNT(*) AS visits,
+  COUNT(*) FILTER (WHERE events = 1) AS bounces,
+  COALESCE(SUM(duration), 0)::bigint AS total_duration,
+  COALESCE(SUM(events - pageviews), 0)::bigint AS events,
+  (SELECT visitors FROM imported) AS imported_visitors,
+  (SELECT pageviews FROM imported) AS imported_pageviews
+FROM visits;
+
+-- name: GetActiveVisitors :one
+SELECT COUNT(DISTINCT visitor_id) AS visitors
+FROM events
+WHERE tracking_id = $1 AND timestamp >= $2;
+
+-- name: GetActiveVisitorIDs :many
+SELECT visitor_id, MAX(timestamp)::timestamptz AS last_seen
+FROM events
+WHERE tracking_id = $1 AND timestamp >= $2
+GROUP BY visitor_id;
+
+-- name: SetEventsCompressionPolicy :exec
+SELECT set_events_compression_policy(sqlc.arg(compress_after)::text::interval);
+
+-- name: SetEventsRetentionPolicy :exec
+SELECT set_events_retention_policy(sqlc.narg(drop_after)::text::interval);
+
+-- name: GetSketchWatermark :one
+SELECT built_until FROM sketch_watermark;
+
+-- name: SetSketchWatermark :exec
+UPDATE sketch_watermark SET built_until = sqlc.arg(built_until);
+
+-- name: GetSketchInputs :many
+SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp)::timestamptz AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+  COUNT(*) FILTER (WHERE event_type <> 'pageview') AS events
+FROM events
+WHERE timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date)
+GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system;
+
+-- name: GetAppSketchInputs :many
+SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp)::timestamptz AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+  COUNT(*) FILTER (WHERE event_type <> 'pageview') AS events
+FROM events
+WHERE tracking_id = sqlc.arg(tracking_id) AND timestamp >= sqlc.arg(start_date) AND timestamp < sqlc.arg(end_date)
+GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system;
+
+-- name: UpsertEventSketches :exec
+INSERT INTO event_sketches (tracking_id, bucket, dimension, value, visitors, pageviews, events)
+SELECT s.tracking_id, sqlc.arg(bucket)::timestamptz, s.dimension, s.value, s.visitors, s.pageviews, s.events
+FROM unnest(sqlc.arg(tracking_ids)::uuid[], sqlc.arg(dimensions)::text[], sqlc.arg(dimension_values)::text[],
+  sqlc.arg(sketches)::bytea[], sqlc.arg(pageviews)::bigint[], sqlc.arg(events)::bigint[])
+  AS s(tracking_id, dimension, value, visitors, pageviews, events)
+ON CONFLICT (tracking_id, dimension, bucket, value) DO UPDATE
+SET visitors = EXCLUDED.visitors, pageviews = EXCLUDED.pageviews, events = EXCLUDED.events;
+
+-- name: UpsertEventVisitors :exec
+INSERT INTO event_visitors (tracking_id, bucket, dimension, value, visitor_id, pageviews, events)
+SELECT v.tracking_id, sqlc.arg(bucket)::timestamptz, v.dimension, v.value, v.visitor_id, v.pageviews, v.events
+FROM unnest(sqlc.arg(tracking_ids)::uuid[], sqlc.arg(dimensions)::text[], sqlc.arg(dimension_values)::text[],
+  sqlc.arg(visitor_ids)::text[], sqlc.arg(pageviews)::bigint[], sqlc.arg(events)::bigint[])
+  AS v(tracking_id, dimension, value, visitor_id, pageviews, events)
+ON CONFLICT (tracking_id, dimension, bucket, value, visitor_id) DO UPDATE
+SET pageviews = EXCLUDED.pageviews, events = EXCLUDED.events;
+
+-- name: GetEventSketches :many
+SELECT bucket, value, visitors, pageviews, events
+FROM event_sketches
+WHERE tracking_id = sqlc.arg(tracking_id) AND dimension = sqlc.arg(dimension) AND
+  bucket >= sqlc.arg(start_date) AND bucket < sqlc.arg(end_date);
+
+-- name: GetRetentionCutoffs :many
+SELECT tracking_id, (NOW() - data_retention::interval)::timestamptz AS expires_before
+FROM apps
+WHERE data_retention IS NOT NULL;
+
+-- name: GetRetentionCutoff :one
+SELECT GREATEST(
+  -- the later of the app's data retention and the EVENTS_RETENTION policy,
+  -- NULL when neither drops raw events
+  NOW() - a.data_retention::interval,
+  NOW() - (
+    SELECT (j.config->>'drop_after')::interval FROM timescaledb_information.jobs j
+    WHERE j.proc_name = 'policy_retention' AND j.hypertable_name = 'events'
+    LIMIT 1
+  )
+)::timestamptz AS expires_before
+FROM apps a
+WHERE a.tracking_id = $1;
+
+-- name: DeleteExpiredEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events
+  WHERE tracking_id = sqlc.arg(tracking_id) AND timestamp < sqlc.arg(expires_before)
+  LIMIT sqlc.arg(batch_size)
+);
+
+-- name: DeleteExpiredEventVisitors :exec
+DELETE FROM event_visitors
+WHERE tracking_id = sqlc.arg(tracking_id) AND bucket < sqlc.arg(expires_before);
+
+-- name: CreateImport :one
+INSERT INTO imports (
+  tracking_id, source, status, file, hostname, website_id, log_format, file_host, locked_by, heartbeat_at
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $9::text IS NULL THEN NULL ELSE NOW() END )
+RETURNING *;
+
+-- name: GetImports :many
+SELECT * FROM imports WHERE tracking_id = $1 ORDER BY created_at DESC;
+
+-- name: GetImport :one
+SELECT * FROM imports WHERE id = $1 AND tracking_id = $2;
+
+-- name: ClaimPendingImport :one
+UPDATE imports
+SET status = 'running', locked_by = sqlc.arg(locked_by)::text, heartbeat_at = NOW()
+WHERE id = (
+  SELECT id FROM imports
+  WHERE status = 'pending' AND (file_host IS NULL OR file_host = sqlc.arg(file_host)::text)
+  ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ClaimStaleImports :many
+UPDATE imports
+SET locked_by = sqlc.arg(locked_by)::text, heartbeat_at = NOW()
+WHERE id IN (
+  SELECT id FROM imports
+  WHERE status = 'running' AND (heartbeat_at IS NULL OR heartbeat_at < NOW() - make_interval(secs => sqlc.arg(lease_seconds)::float8))
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: HeartbeatImport :execrows
+UPDATE imports
+SET heartbeat_at = NOW()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(locked_by)::text AND status = 'running';
+
+-- name: RequeueImport :exec
+UPDATE imports
+SET status = 'pending', progress = 0, imported_events = 0, skipped_events = 0, start_date = NULL, end_date = NULL,
+  locked_by = NULL, heartbeat_at = NULL
+WHERE id = $1;
+
+-- name: UpdateImportProgress :exec
+UPDATE imports
+SET progress = sqlc.arg(progress), imported_events = sqlc.arg(imported_events), skipped_events = sqlc.arg(skipped_events),
+  start_date = sqlc.narg(start_date), end_date = sqlc.narg(end_date)
+WHERE id = sqlc.arg(id);
+
+-- name: FinishImport :exec
+UPDATE imports
+SET status = sqlc.arg(status), error = sqlc.narg(error), finished_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: InsertImportedEvents :execrows
+INSERT INTO events (
+  import_id, tracking_id, visitor_id, event_type, url, referrer, country, browser, device, operating_system, timestamp
+)
+SELECT sqlc.arg(import_id)::uuid, sqlc.arg(tracking_id)::uuid, e.visitor_id, e.event_type, e.url, e.referrer,
+  e.country, e.browser, e.device, e.operating_system, e.timestamp
+FROM unnest(sqlc.arg(visitor_ids)::text[], sqlc.arg(event_types)::text[], sqlc.arg(urls)::text[], sqlc.arg(referrers)::text[],
+  sqlc.arg(countries)::text[], sqlc.arg(browsers)::text[], sqlc.arg(devices)::text[], sqlc.arg(operating_systems)::text[],
+  sqlc.arg(timestamps)::timestamptz[])
+  AS e(visitor_id, event_type, url, referrer, country, browser, device, operating_system, timestamp);
+
+-- name: InsertImportedAggregates :exec
+INSERT INTO imported_aggregates (import_id, tracking_id, bucket, dimension, value, visitors, pageviews)
+SELECT sqlc.arg(import_id), sqlc.arg(tracking_id), a.bucket, a.dimension, a.value, a.visitors, a.pageviews
+FROM unnest(sqlc.arg(buckets)::timestamptz[], sqlc.arg(dimensions)::text[], sqlc.arg(dimension_values)::text[],
+  sqlc.arg(visitors)::bigint[], sqlc.arg(pageviews)::bigint[])
+  AS a(bucket, dimension, value, visitors, pageviews);
+
+-- name: DeleteImportAggregates :exec
+DELETE FROM imported_aggregates WHERE import_id = $1;
+
+-- name: GetImportedAggregates :many
+SELECT bucket, value, visitors, pageviews
+FROM imported_values(sqlc.arg(tracking_id), sqlc.arg(dimension), NULL, sqlc.arg(start_date), sqlc.arg(end_date));
+
+-- name: DeleteImportEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events
+  WHERE import_id = sqlc.arg(import_id)::uuid
+  LIMIT sqlc.arg(batch_size)
+);
+
+-- name: DeleteAppSketches :exec
+WITH visitors AS (
+  DELETE FROM event_visitors
+  WHERE tracking_id = sqlc.arg(tracking_id) AND bucket >= sqlc.arg(start_date) AND bucket < sqlc.arg(end_date)
+)
+DELETE FROM event_sketches
+WHERE tracking_id = sqlc.arg(tracking_id) AND bucket >= sqlc.arg(start_date) AND bucket < sqlc.arg(end_date);
+
+-- name: GetImportHorizon :one
+SELECT GREATEST(NOW() - sqlc.narg(events_retention)::interval, NOW() - data_retention::interval)::timestamptz AS horizon
+FROM apps
+WHERE tracking_id = sqlc.arg(tracking_id);
+
+-- name: SetImportedSince :exec
+UPDATE apps
+SET imported_since = LEAST(imported_since, sqlc.arg(imported_since))
+WHERE tracking_id = sqlc.arg(tracking_id);
+
+-- name: CheckRegex :exec
+SELECT '' ~ sqlc.arg(pattern)::text;
+
+-- name: CountDeletionEvents :one
+SELECT COUNT(*)::bigint AS events, COUNT(DISTINCT visitor_id)::bigint AS visitors,
+  MIN(timestamp)::timestamptz AS first_event, MAX(timestamp)::timestamptz AS last_event
+FROM events e
+WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.narg(filters)::jsonb) AND
+  (sqlc.narg(start_date)::timestamptz IS NULL OR timestamp >= sqlc.narg(start_date)) AND
+  (sqlc.narg(end_date)::timestamptz IS NULL OR timestamp < sqlc.narg(end_date)) AND
+  (sqlc.narg(visitor_id)::text IS NULL OR visitor_id = sqlc.narg(visitor_id));
+
+-- name: CreateDeletion :one
+INSERT INTO deletions (
+  tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, first_event, last_event,
+  app_name, user_email
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+  (SELECT name FROM apps WHERE tracking_id = $1), (SELECT email FROM users WHERE id = $2)
+)
+RETURNING *;
+
+-- name: GetDeletions :many
+SELECT * FROM deletions WHERE tracking_id = $1 ORDER BY created_at DESC;
+
+-- name: GetDeletion :one
+SELECT * FROM deletions WHERE id = $1 AND tracking_id = $2;
+
+-- name: ClaimPendingDeletion :one
+UPDATE deletions
+SET status = 'running', locked_by = sqlc.arg(locked_by)::text, heartbeat_at = NOW()
+WHERE id = (
+  SELECT id FROM deletions WHERE status = 'pending' ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ClaimStaleDeletions :many
+UPDATE deletions
+SET locked_by = sqlc.arg(locked_by)::text, heartbeat_at = NOW()
+WHERE id IN (
+  SELECT id FROM deletions
+  WHERE status = 'running' AND (heartbeat_at IS NULL OR heartbeat_at < NOW() - make_interval(secs => sqlc.arg(lease_seconds)::float8))
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: HeartbeatDeletion :execrows
+UPDATE deletions
+SET heartbeat_at = NOW()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(locked_by)::text AND status = 'running';
+
+-- name: DeleteMatchingEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events e
+  WHERE tracking_id = sqlc.arg(tracking_id) AND event_matches_filters(e, sqlc.narg(filters)::jsonb) AND
+    (sqlc.narg(start_date)::timestamptz IS NULL OR timestamp >= sqlc.narg(start_date)) AND
+    (sqlc.narg(end_date)::timestamptz IS NULL OR timestamp < sqlc.narg(end_date)) AND
+    (sqlc.narg(visitor_id)::text IS NULL OR visitor_id = sqlc.narg(visitor_id))
+  LIMIT sqlc.arg(batch_size)
+);
+
+-- name: UpdateDeletionProgress :exec
+UPDATE deletions SET deleted_events = sqlc.arg(deleted_events) WHERE id = sqlc.arg(id);
+
+-- name: FinishDeletion :exec
+UPDATE deletions
+SET status = sqlc.arg(status), error = sqlc.narg(error), finished_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: CreateShare :one
+INSERT INTO shares (
+  tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at
+) VALUES ( $1, $2, $3, $4, $5, $6, $7 )
+RETURNING *;
+
+-- name: GetShares :many
+SELECT * FROM shares WHERE tracking_id = $1 ORDER BY created_at DESC;
+
+-- name: GetShareByTokenHash :one
+SELECT * FROM shares WHERE token_hash = $1;
+
+-- name: RevokeShare :one
+UPDATE shares SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 AND tracking_id = $2
+RETURNING *;
+
+-- name: GetAppMember :one
+SELECT role FROM (
+  SELECT m.role FROM app_members m WHERE m.tracking_id = $1 AND m.user_id = $2
+  UNION ALL
+  SELECT o.role FROM organization_members o
+  JOIN apps a ON a.org_id = o.org_id
+  WHERE a.tracking_id = $1 AND o.user_id = $2
+) roles
+ORDER BY role_rank(role) DESC
+LIMIT 1;
+
+-- name: GetAppMembers :many
+SELECT m.user_id, u.email, m.role, m.created_at
+FROM app_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.tracking_id = $1
+ORDER BY m.created_at;
+
+-- name: UpdateAppMemberRole :execrows
+UPDATE app_members SET role = sqlc.arg(role)
+WHERE tracking_id = sqlc.arg(tracking_id) AND user_id = sqlc.arg(user_id) AND role <> 'owner';
+
+-- name: DeleteAppMember :execrows
+DELETE FROM app_members
+WHERE tracking_id = $1 AND user_id = $2 AND role <> 'owner';
+
+-- name: TransferApp :one
+WITH demoted AS (
+  UPDATE app_members SET role = 'admin'
+  WHERE tracking_id = sqlc.arg(tracking_id) AND role = 'owner' AND user_id <> sqlc.arg(user_id)
+), promoted AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  VALUES ( sqlc.arg(tracking_id), sqlc.arg(user_id), 'owner' )
+  ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+  SET role = 'owner'
+)
+UPDATE apps SET user_id = sqlc.arg(user_id), org_id = sqlc.arg(org_id)
+WHERE tracking_id = sqlc.arg(tracking_id)
+RETURNING *;
+
+-- name: CreateAppInvitation :one
+INSERT INTO app_invitations (
+  tracking_id, email, role, invited_by
+) VALUES ( sqlc.arg(tracking_id), lower(sqlc.arg(email)), sqlc.arg(role), sqlc.arg(invited_by) )
+ON CONFLICT ( tracking_id, email ) DO UPDATE
+SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, created_at = NOW()
+RETURNING *;
+
+-- name: GetAppInvitations :many
+SELECT * FROM app_invitations WHERE tracking_id = $1 ORDER BY created_at DESC;
+
+-- name: DeleteAppInvitation :execrows
+DELETE FROM app_invitations WHERE id = $1 AND tracking_id = $2;
+
+-- name: GetUserInvitations :many
+SELECT i.*, a.name AS app_name
+FROM app_invitations i
+JOIN users u ON i.email = lower(u.email)
+JOIN apps a ON a.tracking_id = i.tracking_id
+WHERE u.id = $1
+ORDER BY i.created_at DESC;
+
+-- name: AcceptAppInvitation :one
+WITH invitation AS (
+  DELETE FROM app_invitations i
+  USING users u
+  WHERE i.id = sqlc.arg(id) AND u.id = sqlc.arg(user_id) AND i.email = lower(u.email)
+  RETURNING i.tracking_id, i.role
+)
+INSERT INTO app_members (tracking_id, user_id, role)
+SELECT tracking_id, sqlc.arg(user_id), role FROM invitation
+ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+SET role = CASE WHEN app_members.role = 'owner' THEN 'owner' ELSE EXCLUDED.role END
+RETURNING tracking_id;
+
+-- name: CreateOrganization :one
+WITH org AS (
+  INSERT INTO organizations (
+    name
+  ) VALUES ( sqlc.arg(name) )
+  RETURNING *
+), owner AS (
+  INSERT INTO organization_members (org_id, user_id, role)
+  SELECT id, sqlc.arg(user_id), 'owner' FROM org
+)
+SELECT * FROM org;
+
+-- name: GetOrCreatePersonalOrganization :one
+WITH org AS (
+  INSERT INTO organizations (
+    name, personal_user_id
+  ) VALUES ( 'Personal', sqlc.arg(user_id)::uuid )
+  ON CONFLICT ( personal_user_id ) DO UPDATE
+  SET personal_user_id = EXCLUDED.personal_user_id
+  RETURNING id
+), owner AS (
+  INSERT INTO organization_members (org_id, user_id, role)
+  SELECT id, sqlc.arg(user_id), 'owner' FROM org
+  ON CONFLICT DO NOTHING
+)
+SELECT id FROM org;
+
+-- name: GetOrganizations :many
+SELECT o.*, m.role
+FROM organizations o
+JOIN organization_members m ON m.org_id = o.id
+WHERE m.user_id = $1
+ORDER BY o.personal_user_id IS NULL, o.created_at;
+
+-- name: GetOrganizationMember :one
+SELECT role FROM organization_members WHERE org_id = $1 AND user_id = $2;
+
+-- name: GetOrganizationMembers :many
+SELECT m.user_id, u.email, m.role, m.created_at
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = $1
+ORDER BY m.created_at;
+
+-- name: CountOrganizationOwners :one
+SELECT COUNT(*) FROM organization_members WHERE org_id = $1 AND role = 'owner';
+
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE lower(email) = lower(sqlc.arg(email));
+
+-- name: UpsertOrganizationMember :one
+INSERT INTO organization_members (
+  org_id, user_id, role
+) VALUES ( $1, $2, $3 )
+ON CONFLICT ( org_id, user_id ) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: DeleteOrganizationMember :one
+WITH member AS (
+  DELETE FROM organization_members
+  WHERE org_id = sqlc.arg(org_id) AND user_id = sqlc.arg(user_id)
+  RETURNING user_id
+), heir AS (
+  SELECT user_id FROM organization_members
+  WHERE org_id = sqlc.arg(org_id) AND role = 'owner' AND user_id <> sqlc.arg(user_id)
+  ORDER BY created_at
+  LIMIT 1
+), access AS (
+  DELETE FROM app_members m
+  USING apps a, member
+  WHERE a.tracking_id = m.tracking_id AND a.org_id = sqlc.arg(org_id) AND m.user_id = member.user_id
+), handed AS (
+  UPDATE apps SET user_id = heir.user_id
+  FROM heir, member
+  WHERE apps.org_id = sqlc.arg(org_id) AND apps.user_id = member.user_id
+  RETURNING apps.tracking_id, apps.user_id
+), heir_access AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  SELECT tracking_id, user_id, 'owner' FROM handed
+  ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+  SET role = 'owner'
+)
+SELECT user_id FROM member;
+
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (
+  user_id, name, token_hash, scopes, tracking_ids, expires_at
+) VALUES ( $1, $2, $3, $4, $5, $6 )
+RETURNING *;
+
+-- name: GetAPITokens :many
+SELECT * FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: RevokeAPIToken :one
+UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: GetAPITokenByHash :one
+SELECT * FROM api_tokens
+WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW());
 
+-- name: TouchAPIToken :exec
+UPDATE api_tokens SET last_used_at = NOW(), last_used_ip = $2
+WHERE id = $1;
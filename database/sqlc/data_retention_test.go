@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (suite *DatabaseSuite) insertEventAt(trackingID uuid.UUID, visitorID string, timestamp time.Time) {
+	_, err := suite.db.Exec(suite.ctx, `
+		INSERT INTO events (tracking_id, visitor_id, event_type, url, country, browser, device, operating_system, details, timestamp)
+		VALUES ($1, $2, 'pageview', 'https://example.com/', 'Nigeria', 'Safari', 'iPhone', 'iOS', '{}', $3)`,
+		trackingID, visitorID, timestamp)
+	suite.NoError(err)
+}
+
+func (suite *DatabaseSuite) TestUpdateAppDataRetention() {
+	app := suite.createTestApp(suite.createTestUser())
+	suite.Nil(app.DataRetention)
+
+	updated, err := suite.querier.UpdateApp(suite.ctx, UpdateAppParams{TrackingID: app.TrackingID, DataRetention: stringPtr("30 days")})
+	suite.NoError(err)
+	suite.Equal("30 days", *updated.DataRetention)
+
+	// other settings leave the retention alone
+	updated, err = suite.querier.UpdateApp(suite.ctx, UpdateAppParams{TrackingID: app.TrackingID, Name: stringPtr("Renamed")})
+	suite.NoError(err)
+	suite.Equal("30 days", *updated.DataRetention)
+
+	cutoffs, err := suite.querier.GetRetentionCutoffs(suite.ctx)
+	suite.NoError(err)
+	found := false
+	for _, cutoff := range cutoffs {
+		if cutoff.TrackingID == app.TrackingID {
+			found = true
+			suite.WithinDuration(time.Now().AddDate(0, 0, -30), cutoff.ExpiresBefore.Time, time.Minute)
+		}
+	}
+	suite.True(found)
+	cutoff, err := suite.querier.GetRetentionCutoff(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now().AddDate(0, 0, -30), cutoff.Time, time.Minute)
+
+	updated, err = suite.querier.UpdateApp(suite.ctx, UpdateAppParams{TrackingID: app.TrackingID, DataRetention: stringPtr("forever")})
+	suite.NoError(err)
+	suite.Nil(updated.DataRetention)
+	cutoff, err = suite.querier.GetRetentionCutoff(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.False(cutoff.Valid)
+
+	// EVENTS_RETENTION drops the events of apps that keep them forever too
+	suite.NoError(suite.querier.SetEventsRetentionPolicy(suite.ctx, stringPtr("2 years")))
+	defer func() { suite.NoError(suite.querier.SetEventsRetentionPolicy(suite.ctx, nil)) }()
+	cutoff, err = suite.querier.GetRetentionCutoff(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now().AddDate(-2, 0, 0), cutoff.Time, time.Minute)
+}
+
+func (suite *DatabaseSuite) TestDeleteExpiredEvents() {
+	userID := suite.createTestUser()
+	app, other := suite.createTestApp(userID), suite.createTestApp(userID)
+	now := time.Now().UTC()
+	for i := range 3 {
+		suite.insertEventAt(app.TrackingID, "expired", now.AddDate(0, 0, -40-i))
+		suite.insertEventAt(other.TrackingID, "other", now.AddDate(0, 0, -40-i))
+	}
+	suite.insertEventAt(app.TrackingID, "recent", now.Add(-time.Hour))
+
+	cutoff := sql.NullTime{Time: now.AddDate(0, 0, -30), Valid: true}
+	deleted, err := suite.querier.DeleteExpiredEvents(suite.ctx, DeleteExpiredEventsParams{TrackingID: app.TrackingID, ExpiresBefore: cutoff, BatchSize: 2})
+	suite.NoError(err)
+	suite.Equal(int64(2), deleted)
+	deleted, err = suite.querier.DeleteExpiredEvents(suite.ctx, DeleteExpiredEventsParams{TrackingID: app.TrackingID, ExpiresBefore: cutoff, BatchSize: 2})
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+
+	var remaining int
+	suite.NoError(suite.db.QueryRow(suite.ctx, `SELECT COUNT(*) FROM events WHERE tracking_id = $1`, app.TrackingID).Scan(&remaining))
+	suite.Equal(1, remaining)
+	suite.NoError(suite.db.QueryRow(suite.ctx, `SELECT COUNT(*) FROM events WHERE tracking_id = $1`, other.TrackingID).Scan(&remaining))
+	suite.Equal(3, remaining)
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (suite *DatabaseSuite) TestDeletionLifecycle() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	other := suite.createTestApp(userID)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	suite.insertEventAt(app.TrackingID, "visitor-1", now.Add(-3*time.Hour))
+	suite.insertEventAt(app.TrackingID, "visitor-1", now.Add(-2*time.Hour))
+	suite.insertEventAt(app.TrackingID, "visitor-2", now.Add(-time.Hour))
+	suite.insertEventAt(other.TrackingID, "visitor-1", now.Add(-time.Hour))
+
+	visitorID := "visitor-1"
+	params := CountDeletionEventsParams{TrackingID: app.TrackingID, VisitorID: &visitorID}
+	matched, err := suite.querier.CountDeletionEvents(suite.ctx, params)
+	suite.NoError(err)
+	suite.Equal(int64(2), matched.Events)
+	suite.Equal(int64(1), matched.Visitors)
+	suite.True(matched.FirstEvent.Time.Equal(now.Add(-3 * time.Hour)))
+	suite.True(matched.LastEvent.Time.Equal(now.Add(-2 * time.Hour)))
+
+	// every criterion has to match
+	ranged, err := suite.querier.CountDeletionEvents(suite.ctx, CountDeletionEventsParams{
+		TrackingID: app.TrackingID,
+		Filters:    []byte(`[{"dimension":"country","operator":"is","values":["Nigeria"]}]`),
+		StartDate:  sql.NullTime{Time: now.Add(-150 * time.Minute), Valid: true},
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), ranged.Events)
+
+	deletion, err := suite.querier.CreateDeletion(suite.ctx, CreateDeletionParams{
+		TrackingID:      app.TrackingID,
+		UserID:          userID,
+		Status:          "pending",
+		VisitorID:       &visitorID,
+		MatchedEvents:   matched.Events,
+		MatchedVisitors: matched.Visitors,
+		FirstEvent:      matched.FirstEvent,
+		LastEvent:       matched.LastEvent,
+	})
+	suite.NoError(err)
+
+	claimed, err := suite.querier.ClaimPendingDeletion(suite.ctx, "web-1:7")
+	suite.NoError(err)
+	suite.Equal(deletion.ID, claimed.ID)
+	suite.Equal("running", claimed.Status)
+	suite.Equal("web-1:7", *claimed.LockedBy)
+	suite.True(claimed.HeartbeatAt.Valid)
+	_, err = suite.querier.ClaimPendingDeletion(suite.ctx, "web-2:7")
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	// leases that are renewed aren't taken over
+	held, err := suite.querier.HeartbeatDeletion(suite.ctx, HeartbeatDeletionParams{ID: deletion.ID, LockedBy: "web-1:7"})
+	suite.NoError(err)
+	suite.Equal(int64(1), held)
+	stale, err := suite.querier.ClaimStaleDeletions(suite.ctx, ClaimStaleDeletionsParams{LockedBy: "web-2:7", LeaseSeconds: 60})
+	suite.NoError(err)
+	suite.Empty(stale)
+
+	_, err = suite.db.Exec(suite.ctx, `UPDATE deletions SET heartbeat_at = NOW() - INTERVAL '2 minutes' WHERE id = $1`, deletion.ID)
+	suite.NoError(err)
+	stale, err = suite.querier.ClaimStaleDeletions(suite.ctx, ClaimStaleDeletionsParams{LockedBy: "web-2:7", LeaseSeconds: 60})
+	suite.NoError(err)
+	suite.Len(stale, 1)
+	suite.Equal(deletion.ID, stale[0].ID)
+	suite.Equal("running", stale[0].Status)
+	suite.Equal("web-2:7", *stale[0].LockedBy)
+
+	// the previous holder notices it lost the lease
+	held, err = suite.querier.HeartbeatDeletion(suite.ctx, HeartbeatDeletionParams{ID: deletion.ID, LockedBy: "web-1:7"})
+	suite.NoError(err)
+	suite.Equal(int64(0), held)
+
+	deleted, err := suite.querier.DeleteMatchingEvents(suite.ctx, DeleteMatchingEventsParams{TrackingID: app.TrackingID, VisitorID: &visitorID, BatchSize: 1})
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+	deleted, err = suite.querier.DeleteMatchingEvents(suite.ctx, DeleteMatchingEventsParams{TrackingID: app.TrackingID, VisitorID: &visitorID, BatchSize: 10})
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+
+	suite.NoError(suite.querier.UpdateDeletionProgress(suite.ctx, UpdateDeletionProgressParams{DeletedEvents: 2, ID: deletion.ID}))
+	suite.NoError(suite.querier.FinishDeletion(suite.ctx, FinishDeletionParams{ID: deletion.ID, Status: "completed"}))
+
+	deletions, err := suite.querier.GetDeletions(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.Len(deletions, 1)
+	suite.Equal("completed", deletions[0].Status)
+	suite.Equal(int64(2), deletions[0].DeletedEvents)
+	suite.Equal(uuid.NullUUID{UUID: userID, Valid: true}, deletions[0].UserID)
+	suite.Equal(app.Name, deletions[0].AppName)
+	suite.NotEmpty(deletions[0].UserEmail)
+	suite.True(deletions[0].FinishedAt.Valid)
+
+	// the visitor's events in other apps are kept
+	var remaining int
+	suite.NoError(suite.db.QueryRow(suite.ctx, `SELECT COUNT(*) FROM events WHERE visitor_id = $1`, visitorID).Scan(&remaining))
+	suite.Equal(1, remaining)
+
+	// the audit outlives the app and the user, and deleting the app fails
+	// the deletions still queued for it
+	queued, err := suite.querier.CreateDeletion(suite.ctx, CreateDeletionParams{TrackingID: app.TrackingID, UserID: userID, Status: "pending", VisitorID: &visitorID})
+	suite.NoError(err)
+	suite.NoError(suite.querier.DeleteApp(suite.ctx, app.TrackingID))
+	suite.NoError(suite.querier.DeleteApp(suite.ctx, other.TrackingID))
+	_, err = suite.db.Exec(suite.ctx, `DELETE FROM users WHERE id = $1`, userID)
+	suite.NoError(err)
+	deletions, err = suite.querier.GetDeletions(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.Len(deletions, 2)
+	suite.Equal(queued.ID, deletions[0].ID)
+	suite.Equal("failed", deletions[0].Status)
+	suite.False(deletions[0].UserID.Valid)
+	suite.Equal(app.Name, deletions[1].AppName)
+	suite.Equal(queued.UserEmail, deletions[1].UserEmail)
+}
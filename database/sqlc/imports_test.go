@@ -0,0 +1,263 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (suite *DatabaseSuite) TestImportLifecycle() {
+	app := suite.createTestApp(suite.createTestUser())
+	now := time.Now().UTC().Truncate(time.Second)
+
+	imp, err := suite.querier.CreateImport(suite.ctx, CreateImportParams{
+		TrackingID: app.TrackingID,
+		Source:     "plausible",
+		Status:     "pending",
+		File:       "/tmp/export.zip",
+		Hostname:   stringPtr("example.com"),
+	})
+	suite.NoError(err)
+	suite.Equal("pending", imp.Status)
+	suite.Nil(imp.LogFormat)
+
+	claim := ClaimPendingImportParams{LockedBy: "web-1:42", FileHost: "web-1"}
+	claimed, err := suite.querier.ClaimPendingImport(suite.ctx, claim)
+	suite.NoError(err)
+	suite.Equal(imp.ID, claimed.ID)
+	suite.Equal("running", claimed.Status)
+	suite.Equal("web-1:42", *claimed.LockedBy)
+	suite.True(claimed.HeartbeatAt.Valid)
+	_, err = suite.querier.ClaimPendingImport(suite.ctx, claim)
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	url, referrer := "https://example.com/pricing", ""
+	inserted, err := suite.querier.InsertImportedEvents(suite.ctx, InsertImportedEventsParams{
+		ImportID:         imp.ID,
+		TrackingID:       app.TrackingID,
+		VisitorIds:       []string{"imported-1", "imported-2"},
+		EventTypes:       []string{"pageview", "signup"},
+		Urls:             []*string{&url, nil},
+		Referrers:        []*string{&referrer, nil},
+		Countries:        []string{"Nigeria", "Ghana"},
+		Browsers:         []string{"Chrome", "Firefox"},
+		Devices:          []string{"desktop", "mobile"},
+		OperatingSystems: []string{"Windows", "Android"},
+		Timestamps:       []time.Time{now.AddDate(0, 0, -400), now.AddDate(0, 0, -399)},
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), inserted)
+	suite.insertEventAt(app.TrackingID, "tracked", now.Add(-time.Hour))
+
+	// the imported dimension tells imported events from tracked ones
+	for filters, expected := range map[string]int64{
+		``: 3,
+		`[{"dimension":"imported","operator":"is","values":["false"]}]`: 1,
+		`[{"dimension":"imported","operator":"is","values":["true"]}]`:  2,
+	} {
+		var raw []byte
+		if filters != "" {
+			raw = []byte(filters)
+		}
+		overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{
+			TrackingID: app.TrackingID,
+			Filters:    raw,
+			StartDate:  sql.NullTime{Time: now.AddDate(-2, 0, 0), Valid: true},
+			EndDate:    sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+		})
+		suite.NoError(err)
+		suite.Equal(expected, overview.Visitors, filters)
+	}
+
+	start := sql.NullTime{Time: now.AddDate(0, 0, -400), Valid: true}
+	suite.NoError(suite.querier.UpdateImportProgress(suite.ctx, UpdateImportProgressParams{
+		ID: imp.ID, Progress: 0.5, ImportedEvents: 2, SkippedEvents: 1, StartDate: start, EndDate: sql.NullTime{Time: now.AddDate(0, 0, -399), Valid: true},
+	}))
+	suite.NoError(suite.querier.SetImportedSince(suite.ctx, SetImportedSinceParams{ImportedSince: start, TrackingID: app.TrackingID}))
+	// a later import doesn't move imported_since forward
+	suite.NoError(suite.querier.SetImportedSince(suite.ctx, SetImportedSinceParams{ImportedSince: sql.NullTime{Time: now, Valid: true}, TrackingID: app.TrackingID}))
+	updated, err := suite.querier.GetAppByTrackingID(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.True(updated.ImportedSince.Time.Equal(start.Time))
+
+	failure := "broken export"
+	suite.NoError(suite.querier.FinishImport(suite.ctx, FinishImportParams{ID: imp.ID, Status: "failed", Error: &failure}))
+	imports, err := suite.querier.GetImports(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.Len(imports, 1)
+	suite.Equal("failed", imports[0].Status)
+	suite.Equal(int64(2), imports[0].ImportedEvents)
+	suite.Equal(failure, *imports[0].Error)
+	suite.True(imports[0].FinishedAt.Valid)
+
+	deleted, err := suite.querier.DeleteImportEvents(suite.ctx, DeleteImportEventsParams{ImportID: imp.ID, BatchSize: 1})
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+	deleted, err = suite.querier.DeleteImportEvents(suite.ctx, DeleteImportEventsParams{ImportID: imp.ID, BatchSize: 10})
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+
+	var remaining int
+	suite.NoError(suite.db.QueryRow(suite.ctx, `SELECT COUNT(*) FROM events WHERE tracking_id = $1`, app.TrackingID).Scan(&remaining))
+	suite.Equal(1, remaining)
+}
+
+func (suite *DatabaseSuite) TestImportLeases() {
+	app := suite.createTestApp(suite.createTestUser())
+	upload, err := suite.querier.CreateImport(suite.ctx, CreateImportParams{
+		TrackingID: app.TrackingID,
+		Source:     "umami",
+		Status:     "pending",
+		File:       "/tmp/minalytics-imports/dump.sql",
+		FileHost:   stringPtr("web-1"),
+	})
+	suite.NoError(err)
+	suite.Nil(upload.LockedBy)
+	suite.False(upload.HeartbeatAt.Valid)
+
+	// only the host holding the upload can claim it
+	_, err = suite.querier.ClaimPendingImport(suite.ctx, ClaimPendingImportParams{LockedBy: "web-2:7", FileHost: "web-2"})
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+	claimed, err := suite.querier.ClaimPendingImport(suite.ctx, ClaimPendingImportParams{LockedBy: "web-1:7", FileHost: "web-1"})
+	suite.NoError(err)
+	suite.Equal(upload.ID, claimed.ID)
+
+	// the CLI leases its imports as it creates them
+	cli, err := suite.querier.CreateImport(suite.ctx, CreateImportParams{
+		TrackingID: app.TrackingID,
+		Source:     "access_log",
+		Status:     "running",
+		File:       "/var/log/nginx/access.log",
+		FileHost:   stringPtr("batch-1"),
+		LockedBy:   stringPtr("batch-1:9"),
+	})
+	suite.NoError(err)
+	suite.True(cli.HeartbeatAt.Valid)
+
+	// leases that are renewed aren't taken over
+	held, err := suite.querier.HeartbeatImport(suite.ctx, HeartbeatImportParams{ID: upload.ID, LockedBy: "web-1:7"})
+	suite.NoError(err)
+	suite.Equal(int64(1), held)
+	stale, err := suite.querier.ClaimStaleImports(suite.ctx, ClaimStaleImportsParams{LockedBy: "web-2:7", LeaseSeconds: 60})
+	suite.NoError(err)
+	suite.Empty(stale)
+
+	_, err = suite.db.Exec(suite.ctx, `UPDATE imports SET heartbeat_at = NOW() - INTERVAL '2 minutes' WHERE id = $1`, cli.ID)
+	suite.NoError(err)
+	stale, err = suite.querier.ClaimStaleImports(suite.ctx, ClaimStaleImportsParams{LockedBy: "web-2:7", LeaseSeconds: 60})
+	suite.NoError(err)
+	suite.Len(stale, 1)
+	suite.Equal(cli.ID, stale[0].ID)
+	suite.Equal("web-2:7", *stale[0].LockedBy)
+
+	// the previous holder notices it lost the lease
+	held, err = suite.querier.HeartbeatImport(suite.ctx, HeartbeatImportParams{ID: cli.ID, LockedBy: "batch-1:9"})
+	suite.NoError(err)
+	suite.Equal(int64(0), held)
+
+	suite.NoError(suite.querier.RequeueImport(suite.ctx, cli.ID))
+	requeued, err := suite.querier.GetImport(suite.ctx, GetImportParams{ID: cli.ID, TrackingID: app.TrackingID})
+	suite.NoError(err)
+	suite.Equal("pending", requeued.Status)
+	suite.Nil(requeued.LockedBy)
+	suite.Equal("batch-1", *requeued.FileHost)
+}
+
+func (suite *DatabaseSuite) TestGetImportHorizon() {
+	app := suite.createTestApp(suite.createTestUser())
+
+	horizon, err := suite.querier.GetImportHorizon(suite.ctx, GetImportHorizonParams{TrackingID: app.TrackingID})
+	suite.NoError(err)
+	suite.False(horizon.Valid)
+
+	_, err = suite.querier.UpdateApp(suite.ctx, UpdateAppParams{TrackingID: app.TrackingID, DataRetention: stringPtr("30 days")})
+	suite.NoError(err)
+
+	// the later of the two horizons wins
+	horizon, err = suite.querier.GetImportHorizon(suite.ctx, GetImportHorizonParams{TrackingID: app.TrackingID, EventsRetention: stringPtr("1 year")})
+	suite.NoError(err)
+	suite.WithinDuration(time.Now().AddDate(0, 0, -30), horizon.Time, time.Minute)
+
+	horizon, err = suite.querier.GetImportHorizon(suite.ctx, GetImportHorizonParams{TrackingID: app.TrackingID, EventsRetention: stringPtr("7 days")})
+	suite.NoError(err)
+	suite.WithinDuration(time.Now().AddDate(0, 0, -7), horizon.Time, time.Minute)
+}
+
+func (suite *DatabaseSuite) TestImportedAggregates() {
+	app := suite.createTestApp(suite.createTestUser())
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	start := sql.NullTime{Time: day, Valid: true}
+	end := sql.NullTime{Time: day.AddDate(0, 0, 2), Valid: true}
+
+	imp, err := suite.querier.CreateImport(suite.ctx, CreateImportParams{
+		TrackingID: app.TrackingID,
+		Source:     "plausible",
+		Status:     "running",
+		File:       "/tmp/export.zip",
+	})
+	suite.NoError(err)
+
+	google := "https://google.com"
+	suite.NoError(suite.querier.InsertImportedAggregates(suite.ctx, InsertImportedAggregatesParams{
+		ImportID:        imp.ID,
+		TrackingID:      app.TrackingID,
+		Buckets:         []time.Time{day, day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2)},
+		Dimensions:      []string{"all", "referrer", "all", "all"},
+		DimensionValues: []string{"", google, "", ""},
+		Visitors:        []int64{10, 4, 20, 30},
+		Pageviews:       []int64{15, 6, 25, 35},
+	}))
+	suite.insertEventAt(app.TrackingID, "tracked", day.Add(time.Hour))
+
+	// days that start in the range are added to the tracked events
+	overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{TrackingID: app.TrackingID, StartDate: start, EndDate: end})
+	suite.NoError(err)
+	suite.Equal(int64(1), overview.Visitors)
+	suite.Equal(int64(30), overview.ImportedVisitors)
+	suite.Equal(int64(40), overview.ImportedPageviews)
+
+	visitors, err := suite.querier.GetVisitors(suite.ctx, GetVisitorsParams{
+		TimeBucket: "1 day", Timezone: "UTC", StartDate: start, EndDate: end, TrackingID: app.TrackingID,
+	})
+	suite.NoError(err)
+	suite.Len(visitors, 2)
+	suite.Equal(int64(11), visitors[0].Visitors)
+	suite.Equal(int64(20), visitors[1].Visitors)
+
+	referrals, err := suite.querier.GetReferrals(suite.ctx, GetReferralsParams{
+		TrackingID: app.TrackingID, StartDate: start, EndDate: end, Sort: "visitors:desc", PageLimit: 10,
+	})
+	suite.NoError(err)
+	// the tracked event has no referrer, so only the imported visitors count
+	suite.Equal(int64(30), referrals[0].TotalVisitors)
+	suite.Equal(google, *referrals[1].Referrer)
+	suite.Equal(int64(4), referrals[1].Visitors)
+
+	// aggregates have no event details, so filters other than imported leave them out
+	for filters, expected := range map[string]int64{
+		`[{"dimension":"country","operator":"is","values":["Nigeria"]}]`: 0,
+		`[{"dimension":"imported","operator":"is","values":["true"]}]`:   30,
+		`[{"dimension":"imported","operator":"is","values":["false"]}]`:  0,
+	} {
+		overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{
+			TrackingID: app.TrackingID, Filters: []byte(filters), StartDate: start, EndDate: end,
+		})
+		suite.NoError(err)
+		suite.Equal(expected, overview.ImportedVisitors, filters)
+	}
+
+	rows, err := suite.querier.GetImportedAggregates(suite.ctx, GetImportedAggregatesParams{
+		TrackingID: app.TrackingID, Dimension: "referrer", StartDate: start, EndDate: end,
+	})
+	suite.NoError(err)
+	suite.Equal([]GetImportedAggregatesRow{{Bucket: start, Value: google, Visitors: 4, Pageviews: 6}}, rows)
+
+	suite.NoError(suite.querier.DeleteImportAggregates(suite.ctx, imp.ID))
+	rows, err = suite.querier.GetImportedAggregates(suite.ctx, GetImportedAggregatesParams{
+		TrackingID: app.TrackingID, Dimension: "all", StartDate: start, EndDate: end,
+	})
+	suite.NoError(err)
+	suite.Empty(rows)
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (suite *DatabaseSuite) TestAppMembers() {
+	ownerID := suite.createTestUser()
+	app := suite.createTestApp(ownerID)
+
+	// apps are created with their owner as a member
+	role, err := suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: app.TrackingID, UserID: ownerID})
+	suite.NoError(err)
+	suite.Equal("owner", role)
+
+	email := "Ada.Lovelace@example.com"
+	memberID, err := suite.querier.GetOrCreateUser(suite.ctx, email)
+	suite.NoError(err)
+
+	// invitations match emails whatever their case
+	invitation, err := suite.querier.CreateAppInvitation(suite.ctx, CreateAppInvitationParams{
+		TrackingID: app.TrackingID,
+		Email:      email,
+		Role:       "viewer",
+		InvitedBy:  ownerID,
+	})
+	suite.NoError(err)
+	suite.Equal(strings.ToLower(email), invitation.Email)
+
+	invitations, err := suite.querier.GetUserInvitations(suite.ctx, memberID)
+	suite.NoError(err)
+	suite.Len(invitations, 1)
+	suite.Equal(app.Name, invitations[0].AppName)
+
+	// other users can't accept it
+	_, err = suite.querier.AcceptAppInvitation(suite.ctx, AcceptAppInvitationParams{ID: invitation.ID, UserID: ownerID})
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	trackingID, err := suite.querier.AcceptAppInvitation(suite.ctx, AcceptAppInvitationParams{ID: invitation.ID, UserID: memberID})
+	suite.NoError(err)
+	suite.Equal(app.TrackingID, trackingID)
+
+	invitations, err = suite.querier.GetUserInvitations(suite.ctx, memberID)
+	suite.NoError(err)
+	suite.Empty(invitations)
+
+	apps, err := suite.querier.GetApps(suite.ctx, memberID)
+	suite.NoError(err)
+	suite.Len(apps, 1)
+	suite.Equal("viewer", apps[0].Role)
+
+	updated, err := suite.querier.UpdateAppMemberRole(suite.ctx, UpdateAppMemberRoleParams{Role: "editor", TrackingID: app.TrackingID, UserID: memberID})
+	suite.NoError(err)
+	suite.Equal(int64(1), updated)
+
+	// the owner keeps their role until the app is transferred
+	updated, err = suite.querier.UpdateAppMemberRole(suite.ctx, UpdateAppMemberRoleParams{Role: "viewer", TrackingID: app.TrackingID, UserID: ownerID})
+	suite.NoError(err)
+	suite.Zero(updated)
+
+	personalID, err := suite.querier.GetOrCreatePersonalOrganization(suite.ctx, memberID)
+	suite.NoError(err)
+	transferred, err := suite.querier.TransferApp(suite.ctx, TransferAppParams{TrackingID: app.TrackingID, UserID: memberID, OrgID: personalID})
+	suite.NoError(err)
+	suite.Equal(memberID, transferred.UserID)
+	suite.Equal(personalID, transferred.OrgID)
+
+	// the previous owner no longer owns it through their organization
+	role, err = suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: app.TrackingID, UserID: ownerID})
+	suite.NoError(err)
+	suite.Equal("admin", role)
+
+	members, err := suite.querier.GetAppMembers(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.Len(members, 2)
+	roles := map[string]string{}
+	for _, member := range members {
+		roles[member.UserID.String()] = member.Role
+	}
+	suite.Equal("admin", roles[ownerID.String()])
+	suite.Equal("owner", roles[memberID.String()])
+
+	removed, err := suite.querier.DeleteAppMember(suite.ctx, DeleteAppMemberParams{TrackingID: app.TrackingID, UserID: memberID})
+	suite.NoError(err)
+	suite.Zero(removed)
+	removed, err = suite.querier.DeleteAppMember(suite.ctx, DeleteAppMemberParams{TrackingID: app.TrackingID, UserID: ownerID})
+	suite.NoError(err)
+	suite.Equal(int64(1), removed)
+}
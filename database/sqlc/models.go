@@ -10,14 +10,72 @@ import (
 	"github.com/google/uuid"
 )
 
+type ApiToken struct {
+	ID          uuid.UUID    `json:"id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	Name        string       `json:"name"`
+	TokenHash   string       `json:"token_hash"`
+	Scopes      []string     `json:"scopes"`
+	TrackingIds []uuid.UUID  `json:"tracking_ids"`
+	ExpiresAt   sql.NullTime `json:"expires_at"`
+	RevokedAt   sql.NullTime `json:"revoked_at"`
+	LastUsedAt  sql.NullTime `json:"last_used_at"`
+	LastUsedIp  *string      `json:"last_used_ip"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
 type App struct {
+	ID                uuid.UUID    `json:"id"`
+	TrackingID        uuid.UUID    `json:"tracking_id"`
+	UserID            uuid.UUID    `json:"user_id"`
+	Name              string       `json:"name"`
+	CreatedAt         sql.NullTime `json:"created_at"`
+	RetentionTracking bool         `json:"retention_tracking"`
+	Timezone          string       `json:"timezone"`
+	DataRetention     *string      `json:"data_retention"`
+	ImportedSince     sql.NullTime `json:"imported_since"`
+	OrgID             uuid.UUID    `json:"org_id"`
+}
+
+type AppInvitation struct {
 	ID         uuid.UUID    `json:"id"`
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Email      string       `json:"email"`
+	Role       string       `json:"role"`
+	InvitedBy  uuid.UUID    `json:"invited_by"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type AppMember struct {
 	TrackingID uuid.UUID    `json:"tracking_id"`
 	UserID     uuid.UUID    `json:"user_id"`
-	Name       string       `json:"name"`
+	Role       string       `json:"role"`
 	CreatedAt  sql.NullTime `json:"created_at"`
 }
 
+type Deletion struct {
+	ID              uuid.UUID     `json:"id"`
+	TrackingID      uuid.UUID     `json:"tracking_id"`
+	UserID          uuid.NullUUID `json:"user_id"`
+	Status          string        `json:"status"`
+	StartDate       sql.NullTime  `json:"start_date"`
+	EndDate         sql.NullTime  `json:"end_date"`
+	Filters         []byte        `json:"filters"`
+	VisitorID       *string       `json:"visitor_id"`
+	MatchedEvents   int64         `json:"matched_events"`
+	MatchedVisitors int64         `json:"matched_visitors"`
+	DeletedEvents   int64         `json:"deleted_events"`
+	FirstEvent      sql.NullTime  `json:"first_event"`
+	LastEvent       sql.NullTime  `json:"last_event"`
+	Error           *string       `json:"error"`
+	CreatedAt       sql.NullTime  `json:"created_at"`
+	FinishedAt      sql.NullTime  `json:"finished_at"`
+	AppName         string        `json:"app_name"`
+	UserEmail       string        `json:"user_email"`
+	LockedBy        *string       `json:"locked_by"`
+	HeartbeatAt     sql.NullTime  `json:"heartbeat_at"`
+}
+
 type Event struct {
 	ID              uuid.UUID              `json:"id"`
 	TrackingID      uuid.UUID              `json:"tracking_id"`
@@ -31,6 +89,82 @@ type Event struct {
 	OperatingSystem string                 `json:"operating_system"`
 	Details         map[string]interface{} `json:"details"`
 	Timestamp       sql.NullTime           `json:"timestamp"`
+	PersistentID    *string                `json:"persistent_id"`
+	ImportID        uuid.NullUUID          `json:"import_id"`
+}
+
+type EventSketch struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Bucket     sql.NullTime `json:"bucket"`
+	Dimension  string       `json:"dimension"`
+	Value      string       `json:"value"`
+	Visitors   []byte       `json:"visitors"`
+	Pageviews  int64        `json:"pageviews"`
+	Events     int64        `json:"events"`
+}
+
+type EventVisitor struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Bucket     sql.NullTime `json:"bucket"`
+	Dimension  string       `json:"dimension"`
+	Value      string       `json:"value"`
+	VisitorID  string       `json:"visitor_id"`
+	Pageviews  int64        `json:"pageviews"`
+	Events     int64        `json:"events"`
+}
+
+type Import struct {
+	ID             uuid.UUID    `json:"id"`
+	TrackingID     uuid.UUID    `json:"tracking_id"`
+	Source         string       `json:"source"`
+	Status         string       `json:"status"`
+	File           string       `json:"file"`
+	Hostname       *string      `json:"hostname"`
+	WebsiteID      *string      `json:"website_id"`
+	Progress       float64      `json:"progress"`
+	ImportedEvents int64        `json:"imported_events"`
+	SkippedEvents  int64        `json:"skipped_events"`
+	StartDate      sql.NullTime `json:"start_date"`
+	EndDate        sql.NullTime `json:"end_date"`
+	Error          *string      `json:"error"`
+	CreatedAt      sql.NullTime `json:"created_at"`
+	FinishedAt     sql.NullTime `json:"finished_at"`
+	LogFormat      *string      `json:"log_format"`
+	FileHost       *string      `json:"file_host"`
+	LockedBy       *string      `json:"locked_by"`
+	HeartbeatAt    sql.NullTime `json:"heartbeat_at"`
+}
+
+type Organization struct {
+	ID             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	PersonalUserID uuid.NullUUID `json:"personal_user_id"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+}
+
+type OrganizationMember struct {
+	OrgID     uuid.UUID    `json:"org_id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Role      string       `json:"role"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type Share struct {
+	ID           uuid.UUID    `json:"id"`
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	Name         string       `json:"name"`
+	TokenHash    string       `json:"token_hash"`
+	PasswordHash *string      `json:"password_hash"`
+	Endpoints    []string     `json:"endpoints"`
+	ExpiresAt    sql.NullTime `json:"expires_at"`
+	RevokedAt    sql.NullTime `json:"revoked_at"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
+
+type SketchWatermark struct {
+	ID         bool         `json:"id"`
+	BuiltUntil sql.NullTime `json:"built_until"`
 }
 
 type User struct {
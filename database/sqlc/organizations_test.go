@@ -0,0 +1,64 @@
+package database
+
+func (suite *DatabaseSuite) TestOrganizations() {
+	ownerID := suite.createTestUser()
+	memberID := suite.createTestUser()
+
+	personalID, err := suite.querier.GetOrCreatePersonalOrganization(suite.ctx, ownerID)
+	suite.NoError(err)
+	again, err := suite.querier.GetOrCreatePersonalOrganization(suite.ctx, ownerID)
+	suite.NoError(err)
+	suite.Equal(personalID, again)
+
+	org, err := suite.querier.CreateOrganization(suite.ctx, CreateOrganizationParams{Name: "Acme", UserID: ownerID})
+	suite.NoError(err)
+	orgs, err := suite.querier.GetOrganizations(suite.ctx, ownerID)
+	suite.NoError(err)
+	suite.Len(orgs, 2)
+	// the personal organization comes first
+	suite.Equal(personalID, orgs[0].ID)
+	suite.Equal("owner", orgs[1].Role)
+
+	// app names are unique per organization
+	personal, err := suite.querier.CreateApp(suite.ctx, CreateAppParams{Name: "Blog", UserID: ownerID, OrgID: personalID})
+	suite.NoError(err)
+	app, err := suite.querier.CreateApp(suite.ctx, CreateAppParams{Name: "Blog", UserID: ownerID, OrgID: org.ID})
+	suite.NoError(err)
+	_, err = suite.querier.CreateApp(suite.ctx, CreateAppParams{Name: "Blog", UserID: ownerID, OrgID: org.ID})
+	suite.Error(err)
+
+	// members of an organization have their role in its apps, and no others
+	_, err = suite.querier.UpsertOrganizationMember(suite.ctx, UpsertOrganizationMemberParams{OrgID: org.ID, UserID: memberID, Role: "editor"})
+	suite.NoError(err)
+	role, err := suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: app.TrackingID, UserID: memberID})
+	suite.NoError(err)
+	suite.Equal("editor", role)
+	apps, err := suite.querier.GetApps(suite.ctx, memberID)
+	suite.NoError(err)
+	suite.Len(apps, 1)
+	suite.Equal(app.TrackingID, apps[0].TrackingID)
+	suite.Equal("editor", apps[0].Role)
+	_, err = suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: personal.TrackingID, UserID: memberID})
+	suite.Error(err)
+
+	// apps of members who leave stay in the organization, with another owner
+	_, err = suite.querier.UpsertOrganizationMember(suite.ctx, UpsertOrganizationMemberParams{OrgID: org.ID, UserID: memberID, Role: "owner"})
+	suite.NoError(err)
+	owners, err := suite.querier.CountOrganizationOwners(suite.ctx, org.ID)
+	suite.NoError(err)
+	suite.Equal(int64(2), owners)
+	left, err := suite.querier.CreateApp(suite.ctx, CreateAppParams{Name: "Docs", UserID: memberID, OrgID: org.ID})
+	suite.NoError(err)
+
+	removed, err := suite.querier.DeleteOrganizationMember(suite.ctx, DeleteOrganizationMemberParams{OrgID: org.ID, UserID: memberID})
+	suite.NoError(err)
+	suite.Equal(memberID, removed)
+	handed, err := suite.querier.GetAppByTrackingID(suite.ctx, left.TrackingID)
+	suite.NoError(err)
+	suite.Equal(ownerID, handed.UserID)
+	role, err = suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: left.TrackingID, UserID: ownerID})
+	suite.NoError(err)
+	suite.Equal("owner", role)
+	_, err = suite.querier.GetAppMember(suite.ctx, GetAppMemberParams{TrackingID: left.TrackingID, UserID: memberID})
+	suite.Error(err)
+}
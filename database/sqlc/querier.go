@@ -6,27 +6,103 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptAppInvitation(ctx context.Context, arg AcceptAppInvitationParams) (uuid.UUID, error)
 	CheckAppExists(ctx context.Context, arg CheckAppExistsParams) (App, error)
+	CheckRegex(ctx context.Context, pattern string) error
+	ClaimPendingDeletion(ctx context.Context, lockedBy string) (Deletion, error)
+	ClaimPendingImport(ctx context.Context, arg ClaimPendingImportParams) (Import, error)
+	ClaimStaleDeletions(ctx context.Context, arg ClaimStaleDeletionsParams) ([]Deletion, error)
+	ClaimStaleImports(ctx context.Context, arg ClaimStaleImportsParams) ([]Import, error)
+	CountDeletionEvents(ctx context.Context, arg CountDeletionEventsParams) (CountDeletionEventsRow, error)
+	CountOrganizationOwners(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
 	CreateApp(ctx context.Context, arg CreateAppParams) (App, error)
+	CreateAppInvitation(ctx context.Context, arg CreateAppInvitationParams) (AppInvitation, error)
+	CreateDeletion(ctx context.Context, arg CreateDeletionParams) (Deletion, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) error
+	CreateImport(ctx context.Context, arg CreateImportParams) (Import, error)
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
+	CreateShare(ctx context.Context, arg CreateShareParams) (Share, error)
 	DeleteApp(ctx context.Context, trackingID uuid.UUID) error
+	DeleteAppInvitation(ctx context.Context, arg DeleteAppInvitationParams) (int64, error)
+	DeleteAppMember(ctx context.Context, arg DeleteAppMemberParams) (int64, error)
+	DeleteAppSketches(ctx context.Context, arg DeleteAppSketchesParams) error
+	DeleteExpiredEventVisitors(ctx context.Context, arg DeleteExpiredEventVisitorsParams) error
+	DeleteExpiredEvents(ctx context.Context, arg DeleteExpiredEventsParams) (int64, error)
+	DeleteImportAggregates(ctx context.Context, importID uuid.UUID) error
+	DeleteImportEvents(ctx context.Context, arg DeleteImportEventsParams) (int64, error)
+	DeleteMatchingEvents(ctx context.Context, arg DeleteMatchingEventsParams) (int64, error)
+	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) (uuid.UUID, error)
+	FinishDeletion(ctx context.Context, arg FinishDeletionParams) error
+	FinishImport(ctx context.Context, arg FinishImportParams) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error)
+	GetAPITokens(ctx context.Context, userID uuid.UUID) ([]ApiToken, error)
+	GetActiveVisitorIDs(ctx context.Context, arg GetActiveVisitorIDsParams) ([]GetActiveVisitorIDsRow, error)
+	GetActiveVisitors(ctx context.Context, arg GetActiveVisitorsParams) (int64, error)
 	GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID) (App, error)
-	GetApps(ctx context.Context, userID uuid.UUID) ([]App, error)
+	GetAppInvitations(ctx context.Context, trackingID uuid.UUID) ([]AppInvitation, error)
+	GetAppMember(ctx context.Context, arg GetAppMemberParams) (string, error)
+	GetAppMembers(ctx context.Context, trackingID uuid.UUID) ([]GetAppMembersRow, error)
+	GetAppSketchInputs(ctx context.Context, arg GetAppSketchInputsParams) ([]GetAppSketchInputsRow, error)
+	GetApps(ctx context.Context, userID uuid.UUID) ([]GetAppsRow, error)
 	GetBrowsers(ctx context.Context, arg GetBrowsersParams) ([]GetBrowsersRow, error)
 	GetCountries(ctx context.Context, arg GetCountriesParams) ([]GetCountriesRow, error)
+	GetDeletion(ctx context.Context, arg GetDeletionParams) (Deletion, error)
+	GetDeletions(ctx context.Context, trackingID uuid.UUID) ([]Deletion, error)
 	GetDevices(ctx context.Context, arg GetDevicesParams) ([]GetDevicesRow, error)
+	GetEventSketches(ctx context.Context, arg GetEventSketchesParams) ([]GetEventSketchesRow, error)
+	GetEventTypes(ctx context.Context, arg GetEventTypesParams) ([]GetEventTypesRow, error)
+	GetImport(ctx context.Context, arg GetImportParams) (Import, error)
+	GetImportHorizon(ctx context.Context, arg GetImportHorizonParams) (sql.NullTime, error)
+	GetImportedAggregates(ctx context.Context, arg GetImportedAggregatesParams) ([]GetImportedAggregatesRow, error)
+	GetImports(ctx context.Context, trackingID uuid.UUID) ([]Import, error)
 	GetOS(ctx context.Context, arg GetOSParams) ([]GetOSRow, error)
+	GetOrCreatePersonalOrganization(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
 	GetOrCreateUser(ctx context.Context, email string) (uuid.UUID, error)
+	GetOrganizationMember(ctx context.Context, arg GetOrganizationMemberParams) (string, error)
+	GetOrganizationMembers(ctx context.Context, orgID uuid.UUID) ([]GetOrganizationMembersRow, error)
+	GetOrganizations(ctx context.Context, userID uuid.UUID) ([]GetOrganizationsRow, error)
+	GetOverview(ctx context.Context, arg GetOverviewParams) (GetOverviewRow, error)
 	GetPageViews(ctx context.Context, arg GetPageViewsParams) ([]GetPageViewsRow, error)
 	GetPages(ctx context.Context, arg GetPagesParams) ([]GetPagesRow, error)
 	GetReferrals(ctx context.Context, arg GetReferralsParams) ([]GetReferralsRow, error)
+	GetRetentionCohorts(ctx context.Context, arg GetRetentionCohortsParams) ([]GetRetentionCohortsRow, error)
+	GetRetentionCutoff(ctx context.Context, trackingID uuid.UUID) (sql.NullTime, error)
+	GetRetentionCutoffs(ctx context.Context) ([]GetRetentionCutoffsRow, error)
+	GetShareByTokenHash(ctx context.Context, tokenHash string) (Share, error)
+	GetShares(ctx context.Context, trackingID uuid.UUID) ([]Share, error)
+	GetSketchInputs(ctx context.Context, arg GetSketchInputsParams) ([]GetSketchInputsRow, error)
+	GetSketchWatermark(ctx context.Context) (sql.NullTime, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserFlow(ctx context.Context, arg GetUserFlowParams) ([]GetUserFlowRow, error)
+	GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]GetUserInvitationsRow, error)
 	GetVisitors(ctx context.Context, arg GetVisitorsParams) ([]GetVisitorsRow, error)
+	HeartbeatDeletion(ctx context.Context, arg HeartbeatDeletionParams) (int64, error)
+	HeartbeatImport(ctx context.Context, arg HeartbeatImportParams) (int64, error)
+	InsertImportedAggregates(ctx context.Context, arg InsertImportedAggregatesParams) error
+	InsertImportedEvents(ctx context.Context, arg InsertImportedEventsParams) (int64, error)
+	RequeueImport(ctx context.Context, id uuid.UUID) error
+	RevokeAPIToken(ctx context.Context, arg RevokeAPITokenParams) (ApiToken, error)
+	RevokeShare(ctx context.Context, arg RevokeShareParams) (Share, error)
+	SetEventsCompressionPolicy(ctx context.Context, compressAfter string) error
+	SetEventsRetentionPolicy(ctx context.Context, dropAfter *string) error
+	SetImportedSince(ctx context.Context, arg SetImportedSinceParams) error
+	SetSketchWatermark(ctx context.Context, builtUntil sql.NullTime) error
+	TouchAPIToken(ctx context.Context, arg TouchAPITokenParams) error
+	TransferApp(ctx context.Context, arg TransferAppParams) (App, error)
 	UpdateApp(ctx context.Context, arg UpdateAppParams) (App, error)
+	UpdateAppMemberRole(ctx context.Context, arg UpdateAppMemberRoleParams) (int64, error)
+	UpdateDeletionProgress(ctx context.Context, arg UpdateDeletionProgressParams) error
+	UpdateImportProgress(ctx context.Context, arg UpdateImportProgressParams) error
+	UpsertEventSketches(ctx context.Context, arg UpsertEventSketchesParams) error
+	UpsertEventVisitors(ctx context.Context, arg UpsertEventVisitorsParams) error
+	UpsertOrganizationMember(ctx context.Context, arg UpsertOrganizationMemberParams) (OrganizationMember, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -22,6 +22,7 @@ import (
 type DatabaseSuite struct {
 	suite.Suite
 	container testcontainers.Container
+	db        *pgxpool.Pool
 	querier   Querier
 	ctx       context.Context
 }
@@ -31,6 +32,7 @@ func setupQuerier(suite *DatabaseSuite, dsn string) *Queries {
 	if err != nil {
 		suite.FailNowf("unable to create connection pool", err.Error())
 	}
+	suite.db = connPool
 
 	return New(connPool)
 }
@@ -98,9 +100,12 @@ func (suite *DatabaseSuite) createTestUser() uuid.UUID {
 }
 
 func (suite *DatabaseSuite) createTestApp(userID uuid.UUID) App {
+	orgID, err := suite.querier.GetOrCreatePersonalOrganization(suite.ctx, userID)
+	suite.NoError(err)
 	app, err := suite.querier.CreateApp(suite.ctx, CreateAppParams{
 		Name:   faker.Word(),
 		UserID: userID,
+		OrgID:  orgID,
 	})
 	suite.NoError(err)
 	return app
@@ -158,6 +163,7 @@ func (suite *DatabaseSuite) TestCreateApp() {
 	t := suite.T()
 
 	userID, _ := suite.querier.GetOrCreateUser(suite.ctx, faker.Email())
+	orgID, _ := suite.querier.GetOrCreatePersonalOrganization(suite.ctx, userID)
 
 	testCases := []struct {
 		name     string
@@ -170,14 +176,16 @@ func (suite *DatabaseSuite) TestCreateApp() {
 			arg: CreateAppParams{
 				Name:   "testapp",
 				UserID: userID,
+				OrgID:  orgID,
 			},
 			hasError: false,
 		},
 		{
-			name: "duplicate app name for same user",
+			name: "duplicate app name in the same organization",
 			arg: CreateAppParams{
 				Name:   "testapp",
 				UserID: userID,
+				OrgID:  orgID,
 			},
 			hasError: true,
 		},
@@ -186,6 +194,7 @@ func (suite *DatabaseSuite) TestCreateApp() {
 			arg: CreateAppParams{
 				Name:   faker.Name(),
 				UserID: uuid.New(),
+				OrgID:  orgID,
 			},
 			hasError: true,
 		},
@@ -217,16 +226,16 @@ func (suite *DatabaseSuite) TestCheckAppExists() {
 		{
 			name: "app exists",
 			arg: CheckAppExistsParams{
-				Name:   app.Name,
-				UserID: userID,
+				Name:  app.Name,
+				OrgID: app.OrgID,
 			},
 			expectedErr: nil,
 		},
 		{
 			name: "app doesn't exist",
 			arg: CheckAppExistsParams{
-				Name:   faker.Name(),
-				UserID: userID,
+				Name:  faker.Name(),
+				OrgID: app.OrgID,
 			},
 			expectedErr: pgx.ErrNoRows,
 		},
@@ -289,7 +298,7 @@ func (suite *DatabaseSuite) TestUpdateApp() {
 
 	app_, err := suite.querier.UpdateApp(suite.ctx, UpdateAppParams{
 		TrackingID: app.TrackingID,
-		Name:       "updated name",
+		Name:       stringPtr("updated name"),
 	})
 	suite.NoError(err)
 	suite.Equal(app.ID, app_.ID)
@@ -310,28 +319,53 @@ func (suite *DatabaseSuite) TestGetVisitors() {
 	suite.createTestEvent(app.TrackingID)
 
 	visitors, err := suite.querier.GetVisitors(suite.ctx, GetVisitorsParams{
+		Timezone:   "UTC",
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+		EndDate:    sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
 		TimeBucket: "1 hour",
 	})
 	suite.NoError(err)
-	suite.Greater(len(visitors), 0)
+
+	// empty hours are filled with zeros
+	suite.GreaterOrEqual(len(visitors), 25)
+	total := 0
+	for _, row := range visitors {
+		total += int(row.Visitors)
+	}
+	suite.Equal(1, total)
 }
 
 func (suite *DatabaseSuite) TestGetPageViews() {
 	userID := suite.createTestUser()
 	app := suite.createTestApp(userID)
 	suite.createTestEvent(app.TrackingID)
+	// custom events aren't pageviews, whatever their URL
+	err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+		VisitorID:  faker.Word(),
+		TrackingID: app.TrackingID,
+		EventType:  "signup",
+		Url:        stringPtr(""),
+		Country:    "Nigeria",
+		Browser:    "Safari",
+		Details:    map[string]interface{}{},
+	})
+	suite.NoError(err)
 
 	pageViews, err := suite.querier.GetPageViews(suite.ctx, GetPageViewsParams{
+		Timezone:   "UTC",
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+		EndDate:    sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
 		TimeBucket: "1 hour",
 	})
 	suite.NoError(err)
 	suite.Greater(len(pageViews), 0)
+	var total int64
+	for _, row := range pageViews {
+		total += row.Views
+	}
+	suite.Equal(int64(1), total)
 }
 
 func (suite *DatabaseSuite) TestGetReferrals() {
@@ -341,8 +375,9 @@ func (suite *DatabaseSuite) TestGetReferrals() {
 
 	referrals, err := suite.querier.GetReferrals(suite.ctx, GetReferralsParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
 	suite.Greater(len(referrals), 0)
@@ -353,13 +388,32 @@ func (suite *DatabaseSuite) TestGetPages() {
 	app := suite.createTestApp(userID)
 	suite.createTestEvent(app.TrackingID)
 
+	// custom events are stored with an empty URL, and aren't pages
+	err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+		VisitorID:       "custom-event-visitor",
+		TrackingID:      app.TrackingID,
+		EventType:       "signup",
+		Url:             stringPtr(""),
+		Country:         faker.GetCountryInfo().Name,
+		Browser:         "Safari",
+		Device:          "iPhone",
+		OperatingSystem: "iOS",
+		Details:         map[string]interface{}{},
+	})
+	suite.NoError(err)
+
 	pages, err := suite.querier.GetPages(suite.ctx, GetPagesParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
-	suite.Greater(len(pages), 0)
+	// the totals and the single page
+	suite.Len(pages, 2)
+	suite.Equal(int64(1), pages[0].TotalVisitors)
+	suite.NotEqual("", *pages[1].Url)
+	suite.Equal(int64(0), pages[1].Events)
 }
 
 func (suite *DatabaseSuite) TestGetCountries() {
@@ -369,8 +423,9 @@ func (suite *DatabaseSuite) TestGetCountries() {
 
 	countries, err := suite.querier.GetCountries(suite.ctx, GetCountriesParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
 	suite.Greater(len(countries), 0)
@@ -383,8 +438,9 @@ func (suite *DatabaseSuite) TestGetBrowsers() {
 
 	browsers, err := suite.querier.GetBrowsers(suite.ctx, GetBrowsersParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
 	suite.Greater(len(browsers), 0)
@@ -397,8 +453,9 @@ func (suite *DatabaseSuite) TestGetDevices() {
 
 	devices, err := suite.querier.GetDevices(suite.ctx, GetDevicesParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
 	suite.Greater(len(devices), 0)
@@ -411,13 +468,442 @@ func (suite *DatabaseSuite) TestGetOS() {
 
 	os, err := suite.querier.GetOS(suite.ctx, GetOSParams{
 		TrackingID: app.TrackingID,
-		Column2:    sql.NullTime{},
-		Column3:    sql.NullTime{},
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		PageLimit:  10,
 	})
 	suite.NoError(err)
 	suite.Greater(len(os), 0)
 }
 
+func (suite *DatabaseSuite) TestCheckRegex() {
+	suite.NoError(suite.querier.CheckRegex(suite.ctx, `\mblog\M`))
+	suite.Error(suite.querier.CheckRegex(suite.ctx, "(unclosed"))
+}
+
+func (suite *DatabaseSuite) TestGetEventTypes() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	suite.createTestEvent(app.TrackingID)
+
+	eventTypes, err := suite.querier.GetEventTypes(suite.ctx, GetEventTypesParams{TrackingID: app.TrackingID})
+	suite.NoError(err)
+	suite.Len(eventTypes, 1)
+	suite.Equal("pageview", eventTypes[0].EventType)
+	suite.Equal(int64(1), eventTypes[0].Events)
+	suite.Equal(int64(1), eventTypes[0].Visitors)
+}
+
+func (suite *DatabaseSuite) TestBreakdownPaging() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	// five referrers with 5, 4, 3, 2 and 1 visitors
+	for i := range 5 {
+		referrer := fmt.Sprintf("https://referrer-%d.com", i)
+		for visitor := range 5 - i {
+			err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+				VisitorID:       fmt.Sprintf("visitor-%d", visitor),
+				TrackingID:      app.TrackingID,
+				EventType:       "pageview",
+				Url:             stringPtr("https://example.com/"),
+				Referrer:        stringPtr(referrer),
+				Country:         faker.GetCountryInfo().Name,
+				Browser:         "Safari",
+				Device:          "iPhone",
+				OperatingSystem: "iOS",
+				Details:         map[string]interface{}{},
+			})
+			suite.NoError(err)
+		}
+	}
+
+	params := GetReferralsParams{
+		TrackingID:   app.TrackingID,
+		Sort:         "visitors:desc",
+		PageOffset:   2,
+		PageLimit:    2,
+		IncludeOther: true,
+	}
+	referrals, err := suite.querier.GetReferrals(suite.ctx, params)
+	suite.NoError(err)
+	suite.Len(referrals, 4)
+	// the totals row comes first
+	suite.Zero(referrals[0].Position)
+	suite.Equal(int64(5), referrals[0].TotalRows)
+	suite.Equal(int64(5), referrals[0].TotalVisitors)
+	suite.Equal("https://referrer-2.com", *referrals[1].Referrer)
+	suite.Equal("https://referrer-3.com", *referrals[2].Referrer)
+	suite.Equal(int64(5), referrals[1].TotalRows)
+	suite.Equal(int64(5), referrals[1].TotalVisitors)
+	suite.Equal(int64(3), referrals[1].Pageviews)
+	suite.Zero(referrals[1].Events)
+	suite.True(referrals[3].Other)
+	suite.Nil(referrals[3].Referrer)
+	suite.Equal(int64(1), referrals[3].Visitors)
+
+	// pages past the last row still have the totals
+	params.PageOffset = 10
+	referrals, err = suite.querier.GetReferrals(suite.ctx, params)
+	suite.NoError(err)
+	suite.Len(referrals, 1)
+	suite.Equal(int64(5), referrals[0].TotalRows)
+	suite.Equal(int64(5), referrals[0].TotalVisitors)
+
+	params.Sort = "name:desc"
+	params.PageOffset, params.IncludeOther = 0, false
+	referrals, err = suite.querier.GetReferrals(suite.ctx, params)
+	suite.NoError(err)
+	suite.Len(referrals, 3)
+	suite.Equal("https://referrer-4.com", *referrals[1].Referrer)
+
+	// rows picked by key have no totals row
+	params.Keys = []string{"https://referrer-0.com", "https://referrer-4.com"}
+	referrals, err = suite.querier.GetReferrals(suite.ctx, params)
+	suite.NoError(err)
+	suite.Len(referrals, 2)
+	suite.Equal(int64(5), referrals[1].Visitors)
+}
+
+func (suite *DatabaseSuite) TestUpdateAppRetentionTracking() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	suite.False(app.RetentionTracking)
+
+	enabled := true
+	app_, err := suite.querier.UpdateApp(suite.ctx, UpdateAppParams{
+		TrackingID:        app.TrackingID,
+		RetentionTracking: &enabled,
+	})
+	suite.NoError(err)
+	suite.Equal(app.Name, app_.Name)
+	suite.True(app_.RetentionTracking)
+}
+
+func (suite *DatabaseSuite) TestUpdateAppTimezone() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	suite.Equal("UTC", app.Timezone)
+
+	updated, err := suite.querier.UpdateApp(suite.ctx, UpdateAppParams{
+		TrackingID: app.TrackingID,
+		Timezone:   stringPtr("Africa/Lagos"),
+	})
+	suite.NoError(err)
+	suite.Equal("Africa/Lagos", updated.Timezone)
+	suite.Equal(app.Name, updated.Name)
+}
+
+func (suite *DatabaseSuite) TestEventFilters() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	events := []struct {
+		url      string
+		referrer string
+		device   string
+	}{
+		{url: "https://example.com/pricing?utm_source=newsletter", referrer: "https://twitter.com/post", device: "iPhone"},
+		{url: "https://example.com/pricing", referrer: "https://google.com", device: "iPhone"},
+		{url: "https://example.com/docs", referrer: "https://twitter.com/post", device: "Desktop"},
+	}
+	for _, event := range events {
+		err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+			VisitorID:       faker.UUIDDigit(),
+			TrackingID:      app.TrackingID,
+			EventType:       "pageview",
+			Url:             stringPtr(event.url),
+			Referrer:        stringPtr(event.referrer),
+			Country:         "Nigeria",
+			Browser:         "Safari",
+			Device:          event.device,
+			OperatingSystem: "iOS",
+			Details:         map[string]interface{}{"plan": "pro"},
+		})
+		suite.NoError(err)
+	}
+
+	testCases := []struct {
+		name     string
+		filters  string
+		expected int
+	}{
+		{
+			name:     "no filters",
+			filters:  "",
+			expected: 3,
+		},
+		{
+			name:     "page is",
+			filters:  `[{"dimension":"page","operator":"is","values":["/pricing"]}]`,
+			expected: 2,
+		},
+		{
+			name:     "combined filters",
+			filters:  `[{"dimension":"referrer","operator":"contains","values":["Twitter"]},{"dimension":"page","operator":"is","values":["/pricing"]},{"dimension":"device","operator":"is","values":["iPhone"]}]`,
+			expected: 1,
+		},
+		{
+			name:     "is not",
+			filters:  `[{"dimension":"page","operator":"is_not","values":["/pricing"]}]`,
+			expected: 1,
+		},
+		{
+			name:     "regex",
+			filters:  `[{"dimension":"referrer","operator":"regex","values":["^https://(twitter|x)\\.com"]}]`,
+			expected: 2,
+		},
+		{
+			name:     "any of",
+			filters:  `[{"dimension":"device","operator":"any_of","values":["Desktop","Android"]}]`,
+			expected: 1,
+		},
+		{
+			name:     "utm field",
+			filters:  `[{"dimension":"utm_source","operator":"is","values":["newsletter"]}]`,
+			expected: 1,
+		},
+		{
+			name:     "event property",
+			filters:  `[{"dimension":"prop:plan","operator":"is","values":["pro"]}]`,
+			expected: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			var filters []byte
+			if tc.filters != "" {
+				filters = []byte(tc.filters)
+			}
+
+			countries, err := suite.querier.GetCountries(suite.ctx, GetCountriesParams{
+				TrackingID: app.TrackingID,
+				Filters:    filters,
+				StartDate:  sql.NullTime{},
+				EndDate:    sql.NullTime{},
+				PageLimit:  10,
+			})
+			suite.NoError(err)
+
+			pageViews, err := suite.querier.GetPageViews(suite.ctx, GetPageViewsParams{
+				Timezone:   "UTC",
+				TimeBucket: "1 day",
+				TrackingID: app.TrackingID,
+				Filters:    filters,
+				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+				EndDate:    sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			})
+			suite.NoError(err)
+
+			views := 0
+			for _, row := range pageViews {
+				views += int(row.Views)
+			}
+			suite.Equal(tc.expected, views)
+			if tc.expected > 0 {
+				suite.Len(countries, 1)
+			}
+		})
+	}
+}
+
+func (suite *DatabaseSuite) TestGetRetentionCohorts() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	for _, persistentID := range []string{faker.UUIDDigit(), faker.UUIDDigit()} {
+		err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+			VisitorID:       faker.Word(),
+			TrackingID:      app.TrackingID,
+			EventType:       "pageview",
+			Url:             stringPtr(faker.URL()),
+			Country:         faker.GetCountryInfo().Name,
+			Browser:         "Safari",
+			Device:          "iPhone",
+			OperatingSystem: "iOS",
+			Details:         map[string]interface{}{},
+			PersistentID:    stringPtr(persistentID),
+		})
+		suite.NoError(err)
+	}
+
+	cohorts, err := suite.querier.GetRetentionCohorts(suite.ctx, GetRetentionCohortsParams{
+		Period:     "week",
+		Timezone:   "UTC",
+		TrackingID: app.TrackingID,
+		StartDate:  sql.NullTime{Time: time.Now().AddDate(0, 0, -14), Valid: true},
+		EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	suite.NoError(err)
+	suite.Len(cohorts, 1)
+	suite.Equal(int32(0), cohorts[0].PeriodOffset)
+	suite.Equal(int64(2), cohorts[0].Visitors)
+}
+
+func (suite *DatabaseSuite) TestGetUserFlow() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	for _, path := range []string{"/", "/pricing", "/signup"} {
+		err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+			VisitorID:       "flow-visitor",
+			TrackingID:      app.TrackingID,
+			EventType:       "pageview",
+			Url:             stringPtr("https://example.com" + path),
+			Country:         faker.GetCountryInfo().Name,
+			Browser:         "Safari",
+			Device:          "iPhone",
+			OperatingSystem: "iOS",
+			Details:         map[string]interface{}{},
+		})
+		suite.NoError(err)
+	}
+
+	flow, err := suite.querier.GetUserFlow(suite.ctx, GetUserFlowParams{
+		TrackingID: app.TrackingID,
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+		Step:       "/pricing",
+		MinDepth:   0,
+		MaxDepth:   3,
+	})
+	suite.NoError(err)
+	suite.Len(flow, 2)
+	suite.Equal("/pricing", flow[0].Step)
+	suite.Equal("/signup", flow[1].Step)
+	suite.Equal("/pricing", flow[1].PreviousStep)
+}
+
+func (suite *DatabaseSuite) TestGetOverview() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	events := []struct {
+		visitorID string
+		eventType string
+	}{
+		{"engaged-visitor", "pageview"},
+		{"engaged-visitor", "pageview"},
+		{"engaged-visitor", "signup"},
+		{"bounced-visitor", "pageview"},
+	}
+	for _, event := range events {
+		err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+			VisitorID:       event.visitorID,
+			TrackingID:      app.TrackingID,
+			EventType:       event.eventType,
+			Url:             stringPtr("https://example.com/"),
+			Country:         faker.GetCountryInfo().Name,
+			Browser:         "Safari",
+			Device:          "iPhone",
+			OperatingSystem: "iOS",
+			Details:         map[string]interface{}{},
+		})
+		suite.NoError(err)
+	}
+
+	overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{
+		TrackingID: app.TrackingID,
+		StartDate:  sql.NullTime{},
+		EndDate:    sql.NullTime{},
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), overview.Visitors)
+	suite.Equal(int64(3), overview.Pageviews)
+	suite.Equal(int64(2), overview.Visits)
+	suite.Equal(int64(1), overview.Bounces)
+	suite.Equal(int64(1), overview.Events)
+}
+
+func (suite *DatabaseSuite) TestRangeEndIsExclusive() {
+	app := suite.createTestApp(suite.createTestUser())
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	start := sql.NullTime{Time: day, Valid: true}
+	end := sql.NullTime{Time: day.AddDate(0, 0, 1), Valid: true}
+
+	// an event at the end of the range belongs to the next one, like in the
+	// series and breakdowns
+	suite.insertEventAt(app.TrackingID, "inside", day)
+	suite.insertEventAt(app.TrackingID, "boundary", end.Time)
+
+	overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{TrackingID: app.TrackingID, StartDate: start, EndDate: end})
+	suite.NoError(err)
+	suite.Equal(int64(1), overview.Visitors)
+
+	flow, err := suite.querier.GetUserFlow(suite.ctx, GetUserFlowParams{
+		TrackingID: app.TrackingID, StartDate: start, EndDate: end, Step: "/", MinDepth: 0, MaxDepth: 3,
+	})
+	suite.NoError(err)
+	suite.Len(flow, 1)
+	suite.Equal(int64(1), flow[0].Visitors)
+}
+
+func (suite *DatabaseSuite) TestGetActiveVisitors() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	for _, visitorID := range []string{"first-visitor", "second-visitor", "first-visitor"} {
+		err := suite.querier.CreateEvent(suite.ctx, CreateEventParams{
+			VisitorID:       visitorID,
+			TrackingID:      app.TrackingID,
+			EventType:       "pageview",
+			Url:             stringPtr("https://example.com/"),
+			Country:         faker.GetCountryInfo().Name,
+			Browser:         "Safari",
+			Device:          "iPhone",
+			OperatingSystem: "iOS",
+			Details:         map[string]interface{}{},
+		})
+		suite.NoError(err)
+	}
+
+	visitors, err := suite.querier.GetActiveVisitors(suite.ctx, GetActiveVisitorsParams{
+		TrackingID: app.TrackingID,
+		Timestamp:  sql.NullTime{Time: time.Now().Add(-5 * time.Minute), Valid: true},
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), visitors)
+
+	visitors, err = suite.querier.GetActiveVisitors(suite.ctx, GetActiveVisitorsParams{
+		TrackingID: app.TrackingID,
+		Timestamp:  sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	suite.NoError(err)
+	suite.Zero(visitors)
+
+	ids, err := suite.querier.GetActiveVisitorIDs(suite.ctx, GetActiveVisitorIDsParams{
+		TrackingID: app.TrackingID,
+		Timestamp:  sql.NullTime{Time: time.Now().Add(-5 * time.Minute), Valid: true},
+	})
+	suite.NoError(err)
+	suite.Len(ids, 2)
+	for _, id := range ids {
+		suite.True(id.LastSeen.Valid)
+	}
+}
+
+func (suite *DatabaseSuite) TestStoragePolicies() {
+	suite.NoError(suite.querier.SetEventsCompressionPolicy(suite.ctx, "30 days"))
+	suite.NoError(suite.querier.SetEventsCompressionPolicy(suite.ctx, "7 days"))
+	suite.Error(suite.querier.SetEventsCompressionPolicy(suite.ctx, "a while"))
+
+	suite.NoError(suite.querier.SetEventsRetentionPolicy(suite.ctx, stringPtr("2 years")))
+	suite.NoError(suite.querier.SetEventsRetentionPolicy(suite.ctx, nil))
+
+	// the hypertable still takes inserts and serves range queries
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	suite.createTestEvent(app.TrackingID)
+
+	referrals, err := suite.querier.GetReferrals(suite.ctx, GetReferralsParams{
+		TrackingID: app.TrackingID,
+		PageLimit:  10,
+	})
+	suite.NoError(err)
+	suite.Len(referrals, 1)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
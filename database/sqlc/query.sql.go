@@ -8,21 +8,48 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const acceptAppInvitation = `-- name: AcceptAppInvitation :one
+WITH invitation AS (
+  DELETE FROM app_invitations i
+  USING users u
+  WHERE i.id = $1 AND u.id = $2 AND i.email = lower(u.email)
+  RETURNING i.tracking_id, i.role
+)
+INSERT INTO app_members (tracking_id, user_id, role)
+SELECT tracking_id, $2, role FROM invitation
+ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+SET role = CASE WHEN app_members.role = 'owner' THEN 'owner' ELSE EXCLUDED.role END
+RETURNING tracking_id
+`
+
+type AcceptAppInvitationParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) AcceptAppInvitation(ctx context.Context, arg AcceptAppInvitationParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, acceptAppInvitation, arg.ID, arg.UserID)
+	var trackingID uuid.UUID
+	err := row.Scan(&trackingID)
+	return trackingID, err
+}
+
 const checkAppExists = `-- name: CheckAppExists :one
-SELECT id, tracking_id, user_id, name, created_at FROM apps WHERE user_id = $1 AND name = $2
+SELECT id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id FROM apps WHERE org_id = $1 AND name = $2
 `
 
 type CheckAppExistsParams struct {
-	UserID uuid.UUID `json:"user_id"`
-	Name   string    `json:"name"`
+	OrgID uuid.UUID `json:"org_id"`
+	Name  string    `json:"name"`
 }
 
 func (q *Queries) CheckAppExists(ctx context.Context, arg CheckAppExistsParams) (App, error) {
-	row := q.db.QueryRow(ctx, checkAppExists, arg.UserID, arg.Name)
+	row := q.db.QueryRow(ctx, checkAppExists, arg.OrgID, arg.Name)
 	var i App
 	err := row.Scan(
 		&i.ID,
@@ -30,115 +57,150 @@ func (q *Queries) CheckAppExists(ctx context.Context, arg CheckAppExistsParams)
 		&i.UserID,
 		&i.Name,
 		&i.CreatedAt,
+		&i.RetentionTracking,
+		&i.Timezone,
+		&i.DataRetention,
+		&i.ImportedSince,
+		&i.OrgID,
 	)
 	return i, err
 }
 
-const createApp = `-- name: CreateApp :one
-INSERT INTO apps (
-  name, user_id
-) VALUES ( $1, $2 )
-RETURNING id, tracking_id, user_id, name, created_at
+const checkRegex = `-- name: CheckRegex :exec
+SELECT '' ~ $1::text
 `
 
-type CreateAppParams struct {
-	Name   string    `json:"name"`
-	UserID uuid.UUID `json:"user_id"`
+func (q *Queries) CheckRegex(ctx context.Context, pattern string) error {
+	_, err := q.db.Exec(ctx, checkRegex, pattern)
+	return err
 }
 
-func (q *Queries) CreateApp(ctx context.Context, arg CreateAppParams) (App, error) {
-	row := q.db.QueryRow(ctx, createApp, arg.Name, arg.UserID)
-	var i App
+const claimPendingDeletion = `-- name: ClaimPendingDeletion :one
+UPDATE deletions
+SET status = 'running', locked_by = $1::text, heartbeat_at = NOW()
+WHERE id = (
+  SELECT id FROM deletions WHERE status = 'pending' ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, deleted_events, first_event, last_event, error, created_at, finished_at, app_name, user_email, locked_by, heartbeat_at
+`
+
+func (q *Queries) ClaimPendingDeletion(ctx context.Context, lockedBy string) (Deletion, error) {
+	row := q.db.QueryRow(ctx, claimPendingDeletion, lockedBy)
+	var i Deletion
 	err := row.Scan(
 		&i.ID,
 		&i.TrackingID,
 		&i.UserID,
-		&i.Name,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Filters,
+		&i.VisitorID,
+		&i.MatchedEvents,
+		&i.MatchedVisitors,
+		&i.DeletedEvents,
+		&i.FirstEvent,
+		&i.LastEvent,
+		&i.Error,
 		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.AppName,
+		&i.UserEmail,
+		&i.LockedBy,
+		&i.HeartbeatAt,
 	)
 	return i, err
 }
 
-const createEvent = `-- name: CreateEvent :exec
-INSERT INTO events (
-  visitor_id, tracking_id, event_type, url, referrer, country, browser, device, operating_system, details
-) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 )
-`
-
-type CreateEventParams struct {
-	VisitorID       string                 `json:"visitor_id"`
-	TrackingID      uuid.UUID              `json:"tracking_id"`
-	EventType       string                 `json:"event_type"`
-	Url             *string                `json:"url"`
-	Referrer        *string                `json:"referrer"`
-	Country         string                 `json:"country"`
-	Browser         string                 `json:"browser"`
-	Device          string                 `json:"device"`
-	OperatingSystem string                 `json:"operating_system"`
-	Details         map[string]interface{} `json:"details"`
-}
-
-func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error {
-	_, err := q.db.Exec(ctx, createEvent,
-		arg.VisitorID,
-		arg.TrackingID,
-		arg.EventType,
-		arg.Url,
-		arg.Referrer,
-		arg.Country,
-		arg.Browser,
-		arg.Device,
-		arg.OperatingSystem,
-		arg.Details,
-	)
-	return err
-}
-
-const deleteApp = `-- name: DeleteApp :exec
-DELETE FROM apps WHERE tracking_id = $1
+const claimPendingImport = `-- name: ClaimPendingImport :one
+UPDATE imports
+SET status = 'running', locked_by = $1::text, heartbeat_at = NOW()
+WHERE id = (
+  SELECT id FROM imports
+  WHERE status = 'pending' AND (file_host IS NULL OR file_host = $2::text)
+  ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tracking_id, source, status, file, hostname, website_id, progress, imported_events, skipped_events, start_date, end_date, error, created_at, finished_at, log_format, file_host, locked_by, heartbeat_at
 `
 
-func (q *Queries) DeleteApp(ctx context.Context, trackingID uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteApp, trackingID)
-	return err
+type ClaimPendingImportParams struct {
+	LockedBy string `json:"locked_by"`
+	FileHost string `json:"file_host"`
 }
 
-const getAppByTrackingID = `-- name: GetAppByTrackingID :one
-SELECT id, tracking_id, user_id, name, created_at FROM apps WHERE tracking_id = $1
-`
-
-func (q *Queries) GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID) (App, error) {
-	row := q.db.QueryRow(ctx, getAppByTrackingID, trackingID)
-	var i App
+func (q *Queries) ClaimPendingImport(ctx context.Context, arg ClaimPendingImportParams) (Import, error) {
+	row := q.db.QueryRow(ctx, claimPendingImport, arg.LockedBy, arg.FileHost)
+	var i Import
 	err := row.Scan(
 		&i.ID,
 		&i.TrackingID,
-		&i.UserID,
-		&i.Name,
+		&i.Source,
+		&i.Status,
+		&i.File,
+		&i.Hostname,
+		&i.WebsiteID,
+		&i.Progress,
+		&i.ImportedEvents,
+		&i.SkippedEvents,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Error,
 		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.LogFormat,
+		&i.FileHost,
+		&i.LockedBy,
+		&i.HeartbeatAt,
 	)
 	return i, err
 }
 
-const getApps = `-- name: GetApps :many
-SELECT id, tracking_id, user_id, name, created_at FROM apps WHERE user_id = $1
+const claimStaleDeletions = `-- name: ClaimStaleDeletions :many
+UPDATE deletions
+SET locked_by = $1::text, heartbeat_at = NOW()
+WHERE id IN (
+  SELECT id FROM deletions
+  WHERE status = 'running' AND (heartbeat_at IS NULL OR heartbeat_at < NOW() - make_interval(secs => $2::float8))
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, deleted_events, first_event, last_event, error, created_at, finished_at, app_name, user_email, locked_by, heartbeat_at
 `
 
-func (q *Queries) GetApps(ctx context.Context, userID uuid.UUID) ([]App, error) {
-	rows, err := q.db.Query(ctx, getApps, userID)
+type ClaimStaleDeletionsParams struct {
+	LockedBy     string  `json:"locked_by"`
+	LeaseSeconds float64 `json:"lease_seconds"`
+}
+
+func (q *Queries) ClaimStaleDeletions(ctx context.Context, arg ClaimStaleDeletionsParams) ([]Deletion, error) {
+	rows, err := q.db.Query(ctx, claimStaleDeletions, arg.LockedBy, arg.LeaseSeconds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []App{}
+	items := []Deletion{}
 	for rows.Next() {
-		var i App
+		var i Deletion
 		if err := rows.Scan(
 			&i.ID,
 			&i.TrackingID,
 			&i.UserID,
-			&i.Name,
+			&i.Status,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Filters,
+			&i.VisitorID,
+			&i.MatchedEvents,
+			&i.MatchedVisitors,
+			&i.DeletedEvents,
+			&i.FirstEvent,
+			&i.LastEvent,
+			&i.Error,
 			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.AppName,
+			&i.UserEmail,
+			&i.LockedBy,
+			&i.HeartbeatAt,
 		); err != nil {
 			return nil, err
 		}
@@ -150,39 +212,52 @@ func (q *Queries) GetApps(ctx context.Context, userID uuid.UUID) ([]App, error)
 	return items, nil
 }
 
-const getBrowsers = `-- name: GetBrowsers :many
-SELECT browser, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+const claimStaleImports = `-- name: ClaimStaleImports :many
+UPDATE imports
+SET locked_by = $1::text, heartbeat_at = NOW()
+WHERE id IN (
+  SELECT id FROM imports
+  WHERE status = 'running' AND (heartbeat_at IS NULL OR heartbeat_at < NOW() - make_interval(secs => $2::float8))
+  FOR UPDATE SKIP LOCKED
 )
-GROUP BY browser
-ORDER BY percentage DESC
+RETURNING id, tracking_id, source, status, file, hostname, website_id, progress, imported_events, skipped_events, start_date, end_date, error, created_at, finished_at, log_format, file_host, locked_by, heartbeat_at
 `
 
-type GetBrowsersParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
-}
-
-type GetBrowsersRow struct {
-	Browser    string `json:"browser"`
-	Percentage int    `json:"percentage"`
+type ClaimStaleImportsParams struct {
+	LockedBy     string  `json:"locked_by"`
+	LeaseSeconds float64 `json:"lease_seconds"`
 }
 
-func (q *Queries) GetBrowsers(ctx context.Context, arg GetBrowsersParams) ([]GetBrowsersRow, error) {
-	rows, err := q.db.Query(ctx, getBrowsers, arg.TrackingID, arg.Column2, arg.Column3)
+func (q *Queries) ClaimStaleImports(ctx context.Context, arg ClaimStaleImportsParams) ([]Import, error) {
+	rows, err := q.db.Query(ctx, claimStaleImports, arg.LockedBy, arg.LeaseSeconds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetBrowsersRow{}
+	items := []Import{}
 	for rows.Next() {
-		var i GetBrowsersRow
-		if err := rows.Scan(&i.Browser, &i.Percentage); err != nil {
+		var i Import
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.Source,
+			&i.Status,
+			&i.File,
+			&i.Hostname,
+			&i.WebsiteID,
+			&i.Progress,
+			&i.ImportedEvents,
+			&i.SkippedEvents,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Error,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.LogFormat,
+			&i.FileHost,
+			&i.LockedBy,
+			&i.HeartbeatAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -193,231 +268,2639 @@ func (q *Queries) GetBrowsers(ctx context.Context, arg GetBrowsersParams) ([]Get
 	return items, nil
 }
 
-const getCountries = `-- name: GetCountries :many
-SELECT country, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
-GROUP BY country
-ORDER BY percentage DESC
+const countDeletionEvents = `-- name: CountDeletionEvents :one
+SELECT COUNT(*)::bigint AS events, COUNT(DISTINCT visitor_id)::bigint AS visitors,
+  MIN(timestamp)::timestamptz AS first_event, MAX(timestamp)::timestamptz AS last_event
+FROM events e
+WHERE tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+  ($3::timestamptz IS NULL OR timestamp >= $3) AND
+  ($4::timestamptz IS NULL OR timestamp < $4) AND
+  ($5::text IS NULL OR visitor_id = $5)
 `
 
-type GetCountriesParams struct {
+type CountDeletionEventsParams struct {
 	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+	VisitorID  *string      `json:"visitor_id"`
 }
 
-type GetCountriesRow struct {
-	Country    string `json:"country"`
-	Percentage int    `json:"percentage"`
+type CountDeletionEventsRow struct {
+	Events     int64        `json:"events"`
+	Visitors   int64        `json:"visitors"`
+	FirstEvent sql.NullTime `json:"first_event"`
+	LastEvent  sql.NullTime `json:"last_event"`
 }
 
-func (q *Queries) GetCountries(ctx context.Context, arg GetCountriesParams) ([]GetCountriesRow, error) {
-	rows, err := q.db.Query(ctx, getCountries, arg.TrackingID, arg.Column2, arg.Column3)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetCountriesRow{}
-	for rows.Next() {
-		var i GetCountriesRow
-		if err := rows.Scan(&i.Country, &i.Percentage); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CountDeletionEvents(ctx context.Context, arg CountDeletionEventsParams) (CountDeletionEventsRow, error) {
+	row := q.db.QueryRow(ctx, countDeletionEvents,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.VisitorID,
+	)
+	var i CountDeletionEventsRow
+	err := row.Scan(
+		&i.Events,
+		&i.Visitors,
+		&i.FirstEvent,
+		&i.LastEvent,
+	)
+	return i, err
 }
 
-const getDevices = `-- name: GetDevices :many
-SELECT device, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
-GROUP BY device
-ORDER BY percentage DESC
+const countOrganizationOwners = `-- name: CountOrganizationOwners :one
+SELECT COUNT(*) FROM organization_members WHERE org_id = $1 AND role = 'owner'
 `
 
-type GetDevicesParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
+func (q *Queries) CountOrganizationOwners(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizationOwners, orgID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-type GetDevicesRow struct {
-	Device     string `json:"device"`
-	Percentage int    `json:"percentage"`
+const createAPIToken = `-- name: CreateAPIToken :one
+INSERT INTO api_tokens (
+  user_id, name, token_hash, scopes, tracking_ids, expires_at
+) VALUES ( $1, $2, $3, $4, $5, $6 )
+RETURNING id, user_id, name, token_hash, scopes, tracking_ids, expires_at, revoked_at, last_used_at, last_used_ip, created_at
+`
+
+type CreateAPITokenParams struct {
+	UserID      uuid.UUID    `json:"user_id"`
+	Name        string       `json:"name"`
+	TokenHash   string       `json:"token_hash"`
+	Scopes      []string     `json:"scopes"`
+	TrackingIds []uuid.UUID  `json:"tracking_ids"`
+	ExpiresAt   sql.NullTime `json:"expires_at"`
 }
 
-func (q *Queries) GetDevices(ctx context.Context, arg GetDevicesParams) ([]GetDevicesRow, error) {
-	rows, err := q.db.Query(ctx, getDevices, arg.TrackingID, arg.Column2, arg.Column3)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetDevicesRow{}
-	for rows.Next() {
-		var i GetDevicesRow
-		if err := rows.Scan(&i.Device, &i.Percentage); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error) {
+	row := q.db.QueryRow(ctx, createAPIToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.TrackingIds,
+		arg.ExpiresAt,
+	)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.TrackingIds,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const getOS = `-- name: GetOS :many
-SELECT operating_system, ROUND((COUNT(DISTINCT visitor_id) * 100.0) / SUM(COUNT(DISTINCT visitor_id)) OVER (), 0) as percentage
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+const createApp = `-- name: CreateApp :one
+WITH app AS (
+  INSERT INTO apps (
+    name, user_id, org_id
+  ) VALUES ( $1, $2, $3 )
+  RETURNING id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id
+), owner AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  SELECT tracking_id, user_id, 'owner' FROM app
 )
-GROUP BY operating_system
-ORDER BY percentage DESC
+SELECT id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id FROM app
 `
 
-type GetOSParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
-}
-
-type GetOSRow struct {
-	OperatingSystem string `json:"operating_system"`
-	Percentage      int    `json:"percentage"`
-}
-
-func (q *Queries) GetOS(ctx context.Context, arg GetOSParams) ([]GetOSRow, error) {
-	rows, err := q.db.Query(ctx, getOS, arg.TrackingID, arg.Column2, arg.Column3)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetOSRow{}
-	for rows.Next() {
-		var i GetOSRow
-		if err := rows.Scan(&i.OperatingSystem, &i.Percentage); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type CreateAppParams struct {
+	Name   string    `json:"name"`
+	UserID uuid.UUID `json:"user_id"`
+	OrgID  uuid.UUID `json:"org_id"`
 }
 
-const getOrCreateUser = `-- name: GetOrCreateUser :one
-INSERT INTO users (
-  email
-) VALUES ( $1 )
-ON CONFLICT ( email ) DO UPDATE
-SET email = EXCLUDED.email
-RETURNING id
-`
-
-func (q *Queries) GetOrCreateUser(ctx context.Context, email string) (uuid.UUID, error) {
-	row := q.db.QueryRow(ctx, getOrCreateUser, email)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) CreateApp(ctx context.Context, arg CreateAppParams) (App, error) {
+	row := q.db.QueryRow(ctx, createApp, arg.Name, arg.UserID, arg.OrgID)
+	var i App
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.RetentionTracking,
+		&i.Timezone,
+		&i.DataRetention,
+		&i.ImportedSince,
+		&i.OrgID,
+	)
+	return i, err
 }
 
-const getPageViews = `-- name: GetPageViews :many
-SELECT time_bucket($4, timestamp::timestamptz)::timestamptz AS time, COUNT(url) AS views
-FROM events WHERE tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
-GROUP BY time
+const createAppInvitation = `-- name: CreateAppInvitation :one
+INSERT INTO app_invitations (
+  tracking_id, email, role, invited_by
+) VALUES ( $1, lower($2), $3, $4 )
+ON CONFLICT ( tracking_id, email ) DO UPDATE
+SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, created_at = NOW()
+RETURNING id, tracking_id, email, role, invited_by, created_at
 `
 
-type GetPageViewsParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
-	TimeBucket interface{}  `json:"time_bucket"`
-}
-
-type GetPageViewsRow struct {
-	Time  sql.NullTime `json:"time"`
-	Views int64        `json:"views"`
+type CreateAppInvitationParams struct {
+	TrackingID uuid.UUID `json:"tracking_id"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	InvitedBy  uuid.UUID `json:"invited_by"`
 }
 
-func (q *Queries) GetPageViews(ctx context.Context, arg GetPageViewsParams) ([]GetPageViewsRow, error) {
-	rows, err := q.db.Query(ctx, getPageViews,
+func (q *Queries) CreateAppInvitation(ctx context.Context, arg CreateAppInvitationParams) (AppInvitation, error) {
+	row := q.db.QueryRow(ctx, createAppInvitation,
 		arg.TrackingID,
-		arg.Column2,
-		arg.Column3,
-		arg.TimeBucket,
+		arg.Email,
+		arg.Role,
+		arg.InvitedBy,
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetPageViewsRow{}
-	for rows.Next() {
-		var i GetPageViewsRow
-		if err := rows.Scan(&i.Time, &i.Views); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	var i AppInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.Email,
+		&i.Role,
+		&i.InvitedBy,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const getPages = `-- name: GetPages :many
-SELECT url, COUNT(DISTINCT visitor_id) AS visitor_count
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE url IS NOT NULL AND e.event_type = 'pageview' AND a.tracking_id = $1 AND 
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+const createDeletion = `-- name: CreateDeletion :one
+INSERT INTO deletions (
+  tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, first_event, last_event,
+  app_name, user_email
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+  (SELECT name FROM apps WHERE tracking_id = $1), (SELECT email FROM users WHERE id = $2)
 )
-GROUP BY url
-ORDER BY visitor_count DESC
+RETURNING id, tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, deleted_events, first_event, last_event, error, created_at, finished_at, app_name, user_email, locked_by, heartbeat_at
 `
 
-type GetPagesParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
+type CreateDeletionParams struct {
+	TrackingID      uuid.UUID    `json:"tracking_id"`
+	UserID          uuid.UUID    `json:"user_id"`
+	Status          string       `json:"status"`
+	StartDate       sql.NullTime `json:"start_date"`
+	EndDate         sql.NullTime `json:"end_date"`
+	Filters         []byte       `json:"filters"`
+	VisitorID       *string      `json:"visitor_id"`
+	MatchedEvents   int64        `json:"matched_events"`
+	MatchedVisitors int64        `json:"matched_visitors"`
+	FirstEvent      sql.NullTime `json:"first_event"`
+	LastEvent       sql.NullTime `json:"last_event"`
 }
 
-type GetPagesRow struct {
-	Url          *string `json:"url"`
-	VisitorCount int64   `json:"visitor_count"`
+func (q *Queries) CreateDeletion(ctx context.Context, arg CreateDeletionParams) (Deletion, error) {
+	row := q.db.QueryRow(ctx, createDeletion,
+		arg.TrackingID,
+		arg.UserID,
+		arg.Status,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Filters,
+		arg.VisitorID,
+		arg.MatchedEvents,
+		arg.MatchedVisitors,
+		arg.FirstEvent,
+		arg.LastEvent,
+	)
+	var i Deletion
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Filters,
+		&i.VisitorID,
+		&i.MatchedEvents,
+		&i.MatchedVisitors,
+		&i.DeletedEvents,
+		&i.FirstEvent,
+		&i.LastEvent,
+		&i.Error,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.AppName,
+		&i.UserEmail,
+		&i.LockedBy,
+		&i.HeartbeatAt,
+	)
+	return i, err
 }
 
-func (q *Queries) GetPages(ctx context.Context, arg GetPagesParams) ([]GetPagesRow, error) {
-	rows, err := q.db.Query(ctx, getPages, arg.TrackingID, arg.Column2, arg.Column3)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetPagesRow{}
-	for rows.Next() {
+const createEvent = `-- name: CreateEvent :exec
+INSERT INTO events (
+  visitor_id, tracking_id, event_type, url, referrer, country, browser, device, operating_system, details, persistent_id
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11 )
+`
+
+type CreateEventParams struct {
+	VisitorID       string                 `json:"visitor_id"`
+	TrackingID      uuid.UUID              `json:"tracking_id"`
+	EventType       string                 `json:"event_type"`
+	Url             *string                `json:"url"`
+	Referrer        *string                `json:"referrer"`
+	Country         string                 `json:"country"`
+	Browser         string                 `json:"browser"`
+	Device          string                 `json:"device"`
+	OperatingSystem string                 `json:"operating_system"`
+	Details         map[string]interface{} `json:"details"`
+	PersistentID    *string                `json:"persistent_id"`
+}
+
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error {
+	_, err := q.db.Exec(ctx, createEvent,
+		arg.VisitorID,
+		arg.TrackingID,
+		arg.EventType,
+		arg.Url,
+		arg.Referrer,
+		arg.Country,
+		arg.Browser,
+		arg.Device,
+		arg.OperatingSystem,
+		arg.Details,
+		arg.PersistentID,
+	)
+	return err
+}
+
+const createImport = `-- name: CreateImport :one
+INSERT INTO imports (
+  tracking_id, source, status, file, hostname, website_id, log_format, file_host, locked_by, heartbeat_at
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $9::text IS NULL THEN NULL ELSE NOW() END )
+RETURNING id, tracking_id, source, status, file, hostname, website_id, progress, imported_events, skipped_events, start_date, end_date, error, created_at, finished_at, log_format, file_host, locked_by, heartbeat_at
+`
+
+type CreateImportParams struct {
+	TrackingID uuid.UUID `json:"tracking_id"`
+	Source     string    `json:"source"`
+	Status     string    `json:"status"`
+	File       string    `json:"file"`
+	Hostname   *string   `json:"hostname"`
+	WebsiteID  *string   `json:"website_id"`
+	LogFormat  *string   `json:"log_format"`
+	FileHost   *string   `json:"file_host"`
+	LockedBy   *string   `json:"locked_by"`
+}
+
+func (q *Queries) CreateImport(ctx context.Context, arg CreateImportParams) (Import, error) {
+	row := q.db.QueryRow(ctx, createImport,
+		arg.TrackingID,
+		arg.Source,
+		arg.Status,
+		arg.File,
+		arg.Hostname,
+		arg.WebsiteID,
+		arg.LogFormat,
+		arg.FileHost,
+		arg.LockedBy,
+	)
+	var i Import
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.Source,
+		&i.Status,
+		&i.File,
+		&i.Hostname,
+		&i.WebsiteID,
+		&i.Progress,
+		&i.ImportedEvents,
+		&i.SkippedEvents,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Error,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.LogFormat,
+		&i.FileHost,
+		&i.LockedBy,
+		&i.HeartbeatAt,
+	)
+	return i, err
+}
+
+const createOrganization = `-- name: CreateOrganization :one
+WITH org AS (
+  INSERT INTO organizations (
+    name
+  ) VALUES ( $1 )
+  RETURNING id, name, personal_user_id, created_at
+), owner AS (
+  INSERT INTO organization_members (org_id, user_id, role)
+  SELECT id, $2, 'owner' FROM org
+)
+SELECT id, name, personal_user_id, created_at FROM org
+`
+
+type CreateOrganizationParams struct {
+	Name   string    `json:"name"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization, arg.Name, arg.UserID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.PersonalUserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createShare = `-- name: CreateShare :one
+INSERT INTO shares (
+  tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at
+) VALUES ( $1, $2, $3, $4, $5, $6, $7 )
+RETURNING id, tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at, revoked_at, created_at
+`
+
+type CreateShareParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	Name         string       `json:"name"`
+	TokenHash    string       `json:"token_hash"`
+	PasswordHash *string      `json:"password_hash"`
+	Endpoints    []string     `json:"endpoints"`
+	ExpiresAt    sql.NullTime `json:"expires_at"`
+}
+
+func (q *Queries) CreateShare(ctx context.Context, arg CreateShareParams) (Share, error) {
+	row := q.db.QueryRow(ctx, createShare,
+		arg.TrackingID,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.PasswordHash,
+		arg.Endpoints,
+		arg.ExpiresAt,
+	)
+	var i Share
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.PasswordHash,
+		&i.Endpoints,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteApp = `-- name: DeleteApp :exec
+WITH abandoned AS (
+  UPDATE deletions SET status = 'failed', error = 'the app was deleted', finished_at = NOW()
+  WHERE tracking_id = $1 AND status IN ('pending', 'running')
+)
+DELETE FROM apps WHERE tracking_id = $1
+`
+
+func (q *Queries) DeleteApp(ctx context.Context, trackingID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteApp, trackingID)
+	return err
+}
+
+const deleteAppInvitation = `-- name: DeleteAppInvitation :execrows
+DELETE FROM app_invitations WHERE id = $1 AND tracking_id = $2
+`
+
+type DeleteAppInvitationParams struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"tracking_id"`
+}
+
+func (q *Queries) DeleteAppInvitation(ctx context.Context, arg DeleteAppInvitationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAppInvitation, arg.ID, arg.TrackingID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAppMember = `-- name: DeleteAppMember :execrows
+DELETE FROM app_members
+WHERE tracking_id = $1 AND user_id = $2 AND role <> 'owner'
+`
+
+type DeleteAppMemberParams struct {
+	TrackingID uuid.UUID `json:"tracking_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteAppMember(ctx context.Context, arg DeleteAppMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAppMember, arg.TrackingID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAppSketches = `-- name: DeleteAppSketches :exec
+WITH visitors AS (
+  DELETE FROM event_visitors
+  WHERE tracking_id = $1 AND bucket >= $2 AND bucket < $3
+)
+DELETE FROM event_sketches
+WHERE tracking_id = $1 AND bucket >= $2 AND bucket < $3
+`
+
+type DeleteAppSketchesParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+func (q *Queries) DeleteAppSketches(ctx context.Context, arg DeleteAppSketchesParams) error {
+	_, err := q.db.Exec(ctx, deleteAppSketches, arg.TrackingID, arg.StartDate, arg.EndDate)
+	return err
+}
+
+const deleteExpiredEventVisitors = `-- name: DeleteExpiredEventVisitors :exec
+DELETE FROM event_visitors
+WHERE tracking_id = $1 AND bucket < $2
+`
+
+type DeleteExpiredEventVisitorsParams struct {
+	TrackingID    uuid.UUID    `json:"tracking_id"`
+	ExpiresBefore sql.NullTime `json:"expires_before"`
+}
+
+func (q *Queries) DeleteExpiredEventVisitors(ctx context.Context, arg DeleteExpiredEventVisitorsParams) error {
+	_, err := q.db.Exec(ctx, deleteExpiredEventVisitors, arg.TrackingID, arg.ExpiresBefore)
+	return err
+}
+
+const deleteExpiredEvents = `-- name: DeleteExpiredEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events
+  WHERE tracking_id = $1 AND timestamp < $2
+  LIMIT $3
+)
+`
+
+type DeleteExpiredEventsParams struct {
+	TrackingID    uuid.UUID    `json:"tracking_id"`
+	ExpiresBefore sql.NullTime `json:"expires_before"`
+	BatchSize     int32        `json:"batch_size"`
+}
+
+func (q *Queries) DeleteExpiredEvents(ctx context.Context, arg DeleteExpiredEventsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredEvents, arg.TrackingID, arg.ExpiresBefore, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteImportAggregates = `-- name: DeleteImportAggregates :exec
+DELETE FROM imported_aggregates WHERE import_id = $1
+`
+
+func (q *Queries) DeleteImportAggregates(ctx context.Context, importID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteImportAggregates, importID)
+	return err
+}
+
+const deleteImportEvents = `-- name: DeleteImportEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events
+  WHERE import_id = $1::uuid
+  LIMIT $2
+)
+`
+
+type DeleteImportEventsParams struct {
+	ImportID  uuid.UUID `json:"import_id"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+func (q *Queries) DeleteImportEvents(ctx context.Context, arg DeleteImportEventsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteImportEvents, arg.ImportID, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMatchingEvents = `-- name: DeleteMatchingEvents :execrows
+DELETE FROM events
+WHERE (id, timestamp) IN (
+  SELECT id, timestamp FROM events e
+  WHERE tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    ($3::timestamptz IS NULL OR timestamp >= $3) AND
+    ($4::timestamptz IS NULL OR timestamp < $4) AND
+    ($5::text IS NULL OR visitor_id = $5)
+  LIMIT $6
+)
+`
+
+type DeleteMatchingEventsParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+	VisitorID  *string      `json:"visitor_id"`
+	BatchSize  int32        `json:"batch_size"`
+}
+
+func (q *Queries) DeleteMatchingEvents(ctx context.Context, arg DeleteMatchingEventsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMatchingEvents,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.VisitorID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :one
+WITH member AS (
+  DELETE FROM organization_members
+  WHERE org_id = $1 AND user_id = $2
+  RETURNING user_id
+), heir AS (
+  SELECT user_id FROM organization_members
+  WHERE org_id = $1 AND role = 'owner' AND user_id <> $2
+  ORDER BY created_at
+  LIMIT 1
+), access AS (
+  DELETE FROM app_members m
+  USING apps a, member
+  WHERE a.tracking_id = m.tracking_id AND a.org_id = $1 AND m.user_id = member.user_id
+), handed AS (
+  UPDATE apps SET user_id = heir.user_id
+  FROM heir, member
+  WHERE apps.org_id = $1 AND apps.user_id = member.user_id
+  RETURNING apps.tracking_id, apps.user_id
+), heir_access AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  SELECT tracking_id, user_id, 'owner' FROM handed
+  ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+  SET role = 'owner'
+)
+SELECT user_id FROM member
+`
+
+type DeleteOrganizationMemberParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteOrganizationMember, arg.OrgID, arg.UserID)
+	var userID uuid.UUID
+	err := row.Scan(&userID)
+	return userID, err
+}
+
+const finishDeletion = `-- name: FinishDeletion :exec
+UPDATE deletions
+SET status = $1, error = $2, finished_at = NOW()
+WHERE id = $3
+`
+
+type FinishDeletionParams struct {
+	Status string    `json:"status"`
+	Error  *string   `json:"error"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) FinishDeletion(ctx context.Context, arg FinishDeletionParams) error {
+	_, err := q.db.Exec(ctx, finishDeletion, arg.Status, arg.Error, arg.ID)
+	return err
+}
+
+const finishImport = `-- name: FinishImport :exec
+UPDATE imports
+SET status = $1, error = $2, finished_at = NOW()
+WHERE id = $3
+`
+
+type FinishImportParams struct {
+	Status string    `json:"status"`
+	Error  *string   `json:"error"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) FinishImport(ctx context.Context, arg FinishImportParams) error {
+	_, err := q.db.Exec(ctx, finishImport, arg.Status, arg.Error, arg.ID)
+	return err
+}
+
+const getAPITokenByHash = `-- name: GetAPITokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, tracking_ids, expires_at, revoked_at, last_used_at, last_used_ip, created_at FROM api_tokens
+WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+`
+
+func (q *Queries) GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error) {
+	row := q.db.QueryRow(ctx, getAPITokenByHash, tokenHash)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.TrackingIds,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAPITokens = `-- name: GetAPITokens :many
+SELECT id, user_id, name, token_hash, scopes, tracking_ids, expires_at, revoked_at, last_used_at, last_used_ip, created_at FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAPITokens(ctx context.Context, userID uuid.UUID) ([]ApiToken, error) {
+	rows, err := q.db.Query(ctx, getAPITokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiToken{}
+	for rows.Next() {
+		var i ApiToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Scopes,
+			&i.TrackingIds,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+			&i.LastUsedIp,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveVisitorIDs = `-- name: GetActiveVisitorIDs :many
+SELECT visitor_id, MAX(timestamp)::timestamptz AS last_seen
+FROM events
+WHERE tracking_id = $1 AND timestamp >= $2
+GROUP BY visitor_id
+`
+
+type GetActiveVisitorIDsParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Timestamp  sql.NullTime `json:"timestamp"`
+}
+
+type GetActiveVisitorIDsRow struct {
+	VisitorID string       `json:"visitor_id"`
+	LastSeen  sql.NullTime `json:"last_seen"`
+}
+
+func (q *Queries) GetActiveVisitorIDs(ctx context.Context, arg GetActiveVisitorIDsParams) ([]GetActiveVisitorIDsRow, error) {
+	rows, err := q.db.Query(ctx, getActiveVisitorIDs, arg.TrackingID, arg.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActiveVisitorIDsRow{}
+	for rows.Next() {
+		var i GetActiveVisitorIDsRow
+		if err := rows.Scan(&i.VisitorID, &i.LastSeen); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveVisitors = `-- name: GetActiveVisitors :one
+SELECT COUNT(DISTINCT visitor_id) AS visitors
+FROM events
+WHERE tracking_id = $1 AND timestamp >= $2
+`
+
+type GetActiveVisitorsParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Timestamp  sql.NullTime `json:"timestamp"`
+}
+
+func (q *Queries) GetActiveVisitors(ctx context.Context, arg GetActiveVisitorsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getActiveVisitors, arg.TrackingID, arg.Timestamp)
+	var visitors int64
+	err := row.Scan(&visitors)
+	return visitors, err
+}
+
+const getAppByTrackingID = `-- name: GetAppByTrackingID :one
+SELECT id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id FROM apps WHERE tracking_id = $1
+`
+
+func (q *Queries) GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID) (App, error) {
+	row := q.db.QueryRow(ctx, getAppByTrackingID, trackingID)
+	var i App
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.RetentionTracking,
+		&i.Timezone,
+		&i.DataRetention,
+		&i.ImportedSince,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const getAppInvitations = `-- name: GetAppInvitations :many
+SELECT id, tracking_id, email, role, invited_by, created_at FROM app_invitations WHERE tracking_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAppInvitations(ctx context.Context, trackingID uuid.UUID) ([]AppInvitation, error) {
+	rows, err := q.db.Query(ctx, getAppInvitations, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AppInvitation{}
+	for rows.Next() {
+		var i AppInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.Email,
+			&i.Role,
+			&i.InvitedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAppMember = `-- name: GetAppMember :one
+SELECT role FROM (
+  SELECT m.role FROM app_members m WHERE m.tracking_id = $1 AND m.user_id = $2
+  UNION ALL
+  SELECT o.role FROM organization_members o
+  JOIN apps a ON a.org_id = o.org_id
+  WHERE a.tracking_id = $1 AND o.user_id = $2
+) roles
+ORDER BY role_rank(role) DESC
+LIMIT 1
+`
+
+type GetAppMemberParams struct {
+	TrackingID uuid.UUID `json:"tracking_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetAppMember(ctx context.Context, arg GetAppMemberParams) (string, error) {
+	row := q.db.QueryRow(ctx, getAppMember, arg.TrackingID, arg.UserID)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getAppMembers = `-- name: GetAppMembers :many
+SELECT m.user_id, u.email, m.role, m.created_at
+FROM app_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.tracking_id = $1
+ORDER BY m.created_at
+`
+
+type GetAppMembersRow struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	Email     string       `json:"email"`
+	Role      string       `json:"role"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+func (q *Queries) GetAppMembers(ctx context.Context, trackingID uuid.UUID) ([]GetAppMembersRow, error) {
+	rows, err := q.db.Query(ctx, getAppMembers, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAppMembersRow{}
+	for rows.Next() {
+		var i GetAppMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAppSketchInputs = `-- name: GetAppSketchInputs :many
+SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp)::timestamptz AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+  COUNT(*) FILTER (WHERE event_type <> 'pageview') AS events
+FROM events
+WHERE tracking_id = $1 AND timestamp >= $2 AND timestamp < $3
+GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system
+`
+
+type GetAppSketchInputsParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetAppSketchInputsRow struct {
+	TrackingID      uuid.UUID    `json:"tracking_id"`
+	Bucket          sql.NullTime `json:"bucket"`
+	VisitorID       string       `json:"visitor_id"`
+	Url             *string      `json:"url"`
+	Referrer        *string      `json:"referrer"`
+	Country         string       `json:"country"`
+	Browser         string       `json:"browser"`
+	Device          string       `json:"device"`
+	OperatingSystem string       `json:"operating_system"`
+	Pageviews       int64        `json:"pageviews"`
+	Events          int64        `json:"events"`
+}
+
+func (q *Queries) GetAppSketchInputs(ctx context.Context, arg GetAppSketchInputsParams) ([]GetAppSketchInputsRow, error) {
+	rows, err := q.db.Query(ctx, getAppSketchInputs, arg.TrackingID, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAppSketchInputsRow{}
+	for rows.Next() {
+		var i GetAppSketchInputsRow
+		if err := rows.Scan(
+			&i.TrackingID,
+			&i.Bucket,
+			&i.VisitorID,
+			&i.Url,
+			&i.Referrer,
+			&i.Country,
+			&i.Browser,
+			&i.Device,
+			&i.OperatingSystem,
+			&i.Pageviews,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getApps = `-- name: GetApps :many
+SELECT a.id, a.tracking_id, a.user_id, a.name, a.created_at, a.retention_tracking, a.timezone, a.data_retention, a.imported_since, a.org_id, r.role
+FROM apps a
+JOIN LATERAL (
+  SELECT role FROM (
+    SELECT m.role FROM app_members m WHERE m.tracking_id = a.tracking_id AND m.user_id = $1
+    UNION ALL
+    SELECT o.role FROM organization_members o WHERE o.org_id = a.org_id AND o.user_id = $1
+  ) roles
+  ORDER BY role_rank(role) DESC
+  LIMIT 1
+) r ON TRUE
+ORDER BY a.created_at
+`
+
+type GetAppsRow struct {
+	ID                uuid.UUID    `json:"id"`
+	TrackingID        uuid.UUID    `json:"tracking_id"`
+	UserID            uuid.UUID    `json:"user_id"`
+	Name              string       `json:"name"`
+	CreatedAt         sql.NullTime `json:"created_at"`
+	RetentionTracking bool         `json:"retention_tracking"`
+	Timezone          string       `json:"timezone"`
+	DataRetention     *string      `json:"data_retention"`
+	ImportedSince     sql.NullTime `json:"imported_since"`
+	OrgID             uuid.UUID    `json:"org_id"`
+	Role              string       `json:"role"`
+}
+
+func (q *Queries) GetApps(ctx context.Context, userID uuid.UUID) ([]GetAppsRow, error) {
+	rows, err := q.db.Query(ctx, getApps, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetAppsRow{}
+	for rows.Next() {
+		var i GetAppsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.RetentionTracking,
+			&i.Timezone,
+			&i.DataRetention,
+			&i.ImportedSince,
+			&i.OrgID,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBrowsers = `-- name: GetBrowsers :many
+WITH scoped AS (
+  SELECT browser, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'browser' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS browser, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'browser', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT browser, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT browser, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY browser
+    UNION ALL
+    SELECT browser, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY browser
+), ranked AS (
+  SELECT browser, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN browser END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN browser END DESC,
+      visitors DESC, browser) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.browser = r.browser
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.browser = r.browser
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT browser, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  browser = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetBrowsersParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetBrowsersRow struct {
+	Browser       string `json:"browser"`
+	Visitors      int64  `json:"visitors"`
+	Pageviews     int64  `json:"pageviews"`
+	Events        int64  `json:"events"`
+	TotalVisitors int64  `json:"total_visitors"`
+	TotalRows     int64  `json:"total_rows"`
+	Position      int64  `json:"position"`
+	Other         bool   `json:"other"`
+}
+
+func (q *Queries) GetBrowsers(ctx context.Context, arg GetBrowsersParams) ([]GetBrowsersRow, error) {
+	rows, err := q.db.Query(ctx, getBrowsers,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetBrowsersRow{}
+	for rows.Next() {
+		var i GetBrowsersRow
+		if err := rows.Scan(
+			&i.Browser,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCountries = `-- name: GetCountries :many
+WITH scoped AS (
+  SELECT country, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'country' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS country, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'country', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT country, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT country, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY country
+    UNION ALL
+    SELECT country, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY country
+), ranked AS (
+  SELECT country, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN country END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN country END DESC,
+      visitors DESC, country) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.country = r.country
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.country = r.country
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT country, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  country = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetCountriesParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetCountriesRow struct {
+	Country       string `json:"country"`
+	Visitors      int64  `json:"visitors"`
+	Pageviews     int64  `json:"pageviews"`
+	Events        int64  `json:"events"`
+	TotalVisitors int64  `json:"total_visitors"`
+	TotalRows     int64  `json:"total_rows"`
+	Position      int64  `json:"position"`
+	Other         bool   `json:"other"`
+}
+
+func (q *Queries) GetCountries(ctx context.Context, arg GetCountriesParams) ([]GetCountriesRow, error) {
+	rows, err := q.db.Query(ctx, getCountries,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetCountriesRow{}
+	for rows.Next() {
+		var i GetCountriesRow
+		if err := rows.Scan(
+			&i.Country,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletion = `-- name: GetDeletion :one
+SELECT id, tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, deleted_events, first_event, last_event, error, created_at, finished_at, app_name, user_email, locked_by, heartbeat_at FROM deletions WHERE id = $1 AND tracking_id = $2
+`
+
+type GetDeletionParams struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"tracking_id"`
+}
+
+func (q *Queries) GetDeletion(ctx context.Context, arg GetDeletionParams) (Deletion, error) {
+	row := q.db.QueryRow(ctx, getDeletion, arg.ID, arg.TrackingID)
+	var i Deletion
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Status,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Filters,
+		&i.VisitorID,
+		&i.MatchedEvents,
+		&i.MatchedVisitors,
+		&i.DeletedEvents,
+		&i.FirstEvent,
+		&i.LastEvent,
+		&i.Error,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.AppName,
+		&i.UserEmail,
+		&i.LockedBy,
+		&i.HeartbeatAt,
+	)
+	return i, err
+}
+
+const getDeletions = `-- name: GetDeletions :many
+SELECT id, tracking_id, user_id, status, start_date, end_date, filters, visitor_id, matched_events, matched_visitors, deleted_events, first_event, last_event, error, created_at, finished_at, app_name, user_email, locked_by, heartbeat_at FROM deletions WHERE tracking_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) GetDeletions(ctx context.Context, trackingID uuid.UUID) ([]Deletion, error) {
+	rows, err := q.db.Query(ctx, getDeletions, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Deletion{}
+	for rows.Next() {
+		var i Deletion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.UserID,
+			&i.Status,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Filters,
+			&i.VisitorID,
+			&i.MatchedEvents,
+			&i.MatchedVisitors,
+			&i.DeletedEvents,
+			&i.FirstEvent,
+			&i.LastEvent,
+			&i.Error,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.AppName,
+			&i.UserEmail,
+			&i.LockedBy,
+			&i.HeartbeatAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDevices = `-- name: GetDevices :many
+WITH scoped AS (
+  SELECT device, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'device' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS device, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'device', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT device, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT device, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY device
+    UNION ALL
+    SELECT device, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY device
+), ranked AS (
+  SELECT device, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN device END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN device END DESC,
+      visitors DESC, device) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.device = r.device
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.device = r.device
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT device, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  device = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetDevicesParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetDevicesRow struct {
+	Device        string `json:"device"`
+	Visitors      int64  `json:"visitors"`
+	Pageviews     int64  `json:"pageviews"`
+	Events        int64  `json:"events"`
+	TotalVisitors int64  `json:"total_visitors"`
+	TotalRows     int64  `json:"total_rows"`
+	Position      int64  `json:"position"`
+	Other         bool   `json:"other"`
+}
+
+func (q *Queries) GetDevices(ctx context.Context, arg GetDevicesParams) ([]GetDevicesRow, error) {
+	rows, err := q.db.Query(ctx, getDevices,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDevicesRow{}
+	for rows.Next() {
+		var i GetDevicesRow
+		if err := rows.Scan(
+			&i.Device,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventSketches = `-- name: GetEventSketches :many
+SELECT bucket, value, visitors, pageviews, events
+FROM event_sketches
+WHERE tracking_id = $1 AND dimension = $2 AND
+  bucket >= $3 AND bucket < $4
+`
+
+type GetEventSketchesParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Dimension  string       `json:"dimension"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetEventSketchesRow struct {
+	Bucket    sql.NullTime `json:"bucket"`
+	Value     string       `json:"value"`
+	Visitors  []byte       `json:"visitors"`
+	Pageviews int64        `json:"pageviews"`
+	Events    int64        `json:"events"`
+}
+
+func (q *Queries) GetEventSketches(ctx context.Context, arg GetEventSketchesParams) ([]GetEventSketchesRow, error) {
+	rows, err := q.db.Query(ctx, getEventSketches,
+		arg.TrackingID,
+		arg.Dimension,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetEventSketchesRow{}
+	for rows.Next() {
+		var i GetEventSketchesRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Value,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventTypes = `-- name: GetEventTypes :many
+SELECT event_type, COUNT(DISTINCT visitor_id)::bigint AS visitors, COUNT(*)::bigint AS events
+FROM events e
+WHERE tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+  (
+    ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= $3 AND timestamp < $4)
+  )
+GROUP BY event_type
+ORDER BY events DESC, event_type
+`
+
+type GetEventTypesParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetEventTypesRow struct {
+	EventType string `json:"event_type"`
+	Visitors  int64  `json:"visitors"`
+	Events    int64  `json:"events"`
+}
+
+func (q *Queries) GetEventTypes(ctx context.Context, arg GetEventTypesParams) ([]GetEventTypesRow, error) {
+	rows, err := q.db.Query(ctx, getEventTypes,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetEventTypesRow{}
+	for rows.Next() {
+		var i GetEventTypesRow
+		if err := rows.Scan(&i.EventType, &i.Visitors, &i.Events); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImport = `-- name: GetImport :one
+SELECT id, tracking_id, source, status, file, hostname, website_id, progress, imported_events, skipped_events, start_date, end_date, error, created_at, finished_at, log_format, file_host, locked_by, heartbeat_at FROM imports WHERE id = $1 AND tracking_id = $2
+`
+
+type GetImportParams struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"tracking_id"`
+}
+
+func (q *Queries) GetImport(ctx context.Context, arg GetImportParams) (Import, error) {
+	row := q.db.QueryRow(ctx, getImport, arg.ID, arg.TrackingID)
+	var i Import
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.Source,
+		&i.Status,
+		&i.File,
+		&i.Hostname,
+		&i.WebsiteID,
+		&i.Progress,
+		&i.ImportedEvents,
+		&i.SkippedEvents,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Error,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.LogFormat,
+		&i.FileHost,
+		&i.LockedBy,
+		&i.HeartbeatAt,
+	)
+	return i, err
+}
+
+const getImportHorizon = `-- name: GetImportHorizon :one
+SELECT GREATEST(NOW() - $1::interval, NOW() - data_retention::interval)::timestamptz AS horizon
+FROM apps
+WHERE tracking_id = $2
+`
+
+type GetImportHorizonParams struct {
+	EventsRetention *string   `json:"events_retention"`
+	TrackingID      uuid.UUID `json:"tracking_id"`
+}
+
+func (q *Queries) GetImportHorizon(ctx context.Context, arg GetImportHorizonParams) (sql.NullTime, error) {
+	row := q.db.QueryRow(ctx, getImportHorizon, arg.EventsRetention, arg.TrackingID)
+	var horizon sql.NullTime
+	err := row.Scan(&horizon)
+	return horizon, err
+}
+
+const getImportedAggregates = `-- name: GetImportedAggregates :many
+SELECT bucket, value, visitors, pageviews
+FROM imported_values($1, $2, NULL, $3, $4)
+`
+
+type GetImportedAggregatesParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Dimension  string       `json:"dimension"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetImportedAggregatesRow struct {
+	Bucket    sql.NullTime `json:"bucket"`
+	Value     string       `json:"value"`
+	Visitors  int64        `json:"visitors"`
+	Pageviews int64        `json:"pageviews"`
+}
+
+func (q *Queries) GetImportedAggregates(ctx context.Context, arg GetImportedAggregatesParams) ([]GetImportedAggregatesRow, error) {
+	rows, err := q.db.Query(ctx, getImportedAggregates,
+		arg.TrackingID,
+		arg.Dimension,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetImportedAggregatesRow{}
+	for rows.Next() {
+		var i GetImportedAggregatesRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Value,
+			&i.Visitors,
+			&i.Pageviews,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImports = `-- name: GetImports :many
+SELECT id, tracking_id, source, status, file, hostname, website_id, progress, imported_events, skipped_events, start_date, end_date, error, created_at, finished_at, log_format, file_host, locked_by, heartbeat_at FROM imports WHERE tracking_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) GetImports(ctx context.Context, trackingID uuid.UUID) ([]Import, error) {
+	rows, err := q.db.Query(ctx, getImports, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Import{}
+	for rows.Next() {
+		var i Import
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.Source,
+			&i.Status,
+			&i.File,
+			&i.Hostname,
+			&i.WebsiteID,
+			&i.Progress,
+			&i.ImportedEvents,
+			&i.SkippedEvents,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Error,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.LogFormat,
+			&i.FileHost,
+			&i.LockedBy,
+			&i.HeartbeatAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOS = `-- name: GetOS :many
+WITH scoped AS (
+  SELECT operating_system, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'operating_system' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS operating_system, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'operating_system', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT operating_system, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT operating_system, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY operating_system
+    UNION ALL
+    SELECT operating_system, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY operating_system
+), ranked AS (
+  SELECT operating_system, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN operating_system END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN operating_system END DESC,
+      visitors DESC, operating_system) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.operating_system = r.operating_system
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.operating_system = r.operating_system
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT operating_system, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  operating_system = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT '', 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT '', o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetOSParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetOSRow struct {
+	OperatingSystem string `json:"operating_system"`
+	Visitors        int64  `json:"visitors"`
+	Pageviews       int64  `json:"pageviews"`
+	Events          int64  `json:"events"`
+	TotalVisitors   int64  `json:"total_visitors"`
+	TotalRows       int64  `json:"total_rows"`
+	Position        int64  `json:"position"`
+	Other           bool   `json:"other"`
+}
+
+func (q *Queries) GetOS(ctx context.Context, arg GetOSParams) ([]GetOSRow, error) {
+	rows, err := q.db.Query(ctx, getOS,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetOSRow{}
+	for rows.Next() {
+		var i GetOSRow
+		if err := rows.Scan(
+			&i.OperatingSystem,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrCreatePersonalOrganization = `-- name: GetOrCreatePersonalOrganization :one
+WITH org AS (
+  INSERT INTO organizations (
+    name, personal_user_id
+  ) VALUES ( 'Personal', $1::uuid )
+  ON CONFLICT ( personal_user_id ) DO UPDATE
+  SET personal_user_id = EXCLUDED.personal_user_id
+  RETURNING id
+), owner AS (
+  INSERT INTO organization_members (org_id, user_id, role)
+  SELECT id, $1, 'owner' FROM org
+  ON CONFLICT DO NOTHING
+)
+SELECT id FROM org
+`
+
+func (q *Queries) GetOrCreatePersonalOrganization(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getOrCreatePersonalOrganization, userID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getOrCreateUser = `-- name: GetOrCreateUser :one
+INSERT INTO users (
+  email
+) VALUES ( $1 )
+ON CONFLICT ( email ) DO UPDATE
+SET email = EXCLUDED.email
+RETURNING id
+`
+
+func (q *Queries) GetOrCreateUser(ctx context.Context, email string) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getOrCreateUser, email)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getOrganizationMember = `-- name: GetOrganizationMember :one
+SELECT role FROM organization_members WHERE org_id = $1 AND user_id = $2
+`
+
+type GetOrganizationMemberParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetOrganizationMember(ctx context.Context, arg GetOrganizationMemberParams) (string, error) {
+	row := q.db.QueryRow(ctx, getOrganizationMember, arg.OrgID, arg.UserID)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getOrganizationMembers = `-- name: GetOrganizationMembers :many
+SELECT m.user_id, u.email, m.role, m.created_at
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = $1
+ORDER BY m.created_at
+`
+
+type GetOrganizationMembersRow struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	Email     string       `json:"email"`
+	Role      string       `json:"role"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+func (q *Queries) GetOrganizationMembers(ctx context.Context, orgID uuid.UUID) ([]GetOrganizationMembersRow, error) {
+	rows, err := q.db.Query(ctx, getOrganizationMembers, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetOrganizationMembersRow{}
+	for rows.Next() {
+		var i GetOrganizationMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrganizations = `-- name: GetOrganizations :many
+SELECT o.id, o.name, o.personal_user_id, o.created_at, m.role
+FROM organizations o
+JOIN organization_members m ON m.org_id = o.id
+WHERE m.user_id = $1
+ORDER BY o.personal_user_id IS NULL, o.created_at
+`
+
+type GetOrganizationsRow struct {
+	ID             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	PersonalUserID uuid.NullUUID `json:"personal_user_id"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+	Role           string        `json:"role"`
+}
+
+func (q *Queries) GetOrganizations(ctx context.Context, userID uuid.UUID) ([]GetOrganizationsRow, error) {
+	rows, err := q.db.Query(ctx, getOrganizations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetOrganizationsRow{}
+	for rows.Next() {
+		var i GetOrganizationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.PersonalUserID,
+			&i.CreatedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOverview = `-- name: GetOverview :one
+WITH scoped AS (
+  SELECT visitor_id, event_type, timestamp
+  FROM events e
+  WHERE tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+  (
+    ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= $3 AND timestamp < $4)
+  )
+),
+marked AS (
+  SELECT visitor_id, event_type, timestamp,
+    CASE WHEN timestamp - LAG(timestamp) OVER (PARTITION BY visitor_id ORDER BY timestamp) <= INTERVAL '30 minutes' THEN 0 ELSE 1 END AS starts_visit
+  FROM scoped
+),
+numbered AS (
+  SELECT visitor_id, event_type, timestamp,
+    SUM(starts_visit) OVER (PARTITION BY visitor_id ORDER BY timestamp) AS visit
+  FROM marked
+),
+visits AS (
+  SELECT visitor_id, visit,
+    COUNT(*) AS events,
+    COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+    EXTRACT(EPOCH FROM MAX(timestamp) - MIN(timestamp)) AS duration
+  FROM numbered
+  GROUP BY visitor_id, visit
+),
+imported AS (
+  SELECT COALESCE(SUM(visitors), 0)::bigint AS visitors, COALESCE(SUM(pageviews), 0)::bigint AS pageviews
+  FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+)
+SELECT COUNT(DISTINCT visitor_id) AS visitors,
+  COALESCE(SUM(pageviews), 0)::bigint AS pageviews,
+  COUNT(*) AS visits,
+  COUNT(*) FILTER (WHERE events = 1) AS bounces,
+  COALESCE(SUM(duration), 0)::bigint AS total_duration,
+  COALESCE(SUM(events - pageviews), 0)::bigint AS events,
+  (SELECT visitors FROM imported) AS imported_visitors,
+  (SELECT pageviews FROM imported) AS imported_pageviews
+FROM visits
+`
+
+type GetOverviewParams struct {
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetOverviewRow struct {
+	Visitors          int64 `json:"visitors"`
+	Pageviews         int64 `json:"pageviews"`
+	Visits            int64 `json:"visits"`
+	Bounces           int64 `json:"bounces"`
+	TotalDuration     int64 `json:"total_duration"`
+	Events            int64 `json:"events"`
+	ImportedVisitors  int64 `json:"imported_visitors"`
+	ImportedPageviews int64 `json:"imported_pageviews"`
+}
+
+func (q *Queries) GetOverview(ctx context.Context, arg GetOverviewParams) (GetOverviewRow, error) {
+	row := q.db.QueryRow(ctx, getOverview,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	var i GetOverviewRow
+	err := row.Scan(
+		&i.Visitors,
+		&i.Pageviews,
+		&i.Visits,
+		&i.Bounces,
+		&i.TotalDuration,
+		&i.Events,
+		&i.ImportedVisitors,
+		&i.ImportedPageviews,
+	)
+	return i, err
+}
+
+const getPageViews = `-- name: GetPageViews :many
+SELECT time, SUM(views)::bigint AS views
+FROM (
+  SELECT time_bucket_gapfill($1, timestamp, $2::text, $3::timestamptz, $4::timestamptz)::timestamptz AS time,
+    COALESCE(COUNT(*) FILTER (WHERE event_type = 'pageview'), 0)::bigint AS views
+  FROM events e WHERE tracking_id = $5 AND event_matches_filters(e, $6::jsonb) AND
+    timestamp >= $3 AND timestamp < $4
+  GROUP BY 1
+  UNION ALL
+  SELECT time_bucket($1, bucket, $2::text)::timestamptz, pageviews
+  FROM imported_values($5, 'all', $6::jsonb, $3, $4)
+) t
+GROUP BY time
+ORDER BY time
+`
+
+type GetPageViewsParams struct {
+	TimeBucket interface{}  `json:"time_bucket"`
+	Timezone   string       `json:"timezone"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+}
+
+type GetPageViewsRow struct {
+	Time  sql.NullTime `json:"time"`
+	Views int64        `json:"views"`
+}
+
+func (q *Queries) GetPageViews(ctx context.Context, arg GetPageViewsParams) ([]GetPageViewsRow, error) {
+	rows, err := q.db.Query(ctx, getPageViews,
+		arg.TimeBucket,
+		arg.Timezone,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TrackingID,
+		arg.Filters,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPageViewsRow{}
+	for rows.Next() {
+		var i GetPageViewsRow
+		if err := rows.Scan(&i.Time, &i.Views); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPages = `-- name: GetPages :many
+WITH scoped AS (
+  SELECT url, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  -- custom events are stored with an empty URL, so only pageviews make pages
+  WHERE url IS NOT NULL AND e.event_type = 'pageview' AND a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'url' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS url, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'url', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT url, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT url, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY url
+    UNION ALL
+    SELECT url, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY url
+), ranked AS (
+  SELECT url, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN url END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN url END DESC,
+      visitors DESC, url) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.url = r.url
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.url = r.url
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT url, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  url = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT NULL, 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT NULL, o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetPagesParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetPagesRow struct {
+	Url           *string `json:"url"`
+	Visitors      int64   `json:"visitors"`
+	Pageviews     int64   `json:"pageviews"`
+	Events        int64   `json:"events"`
+	TotalVisitors int64   `json:"total_visitors"`
+	TotalRows     int64   `json:"total_rows"`
+	Position      int64   `json:"position"`
+	Other         bool    `json:"other"`
+}
+
+func (q *Queries) GetPages(ctx context.Context, arg GetPagesParams) ([]GetPagesRow, error) {
+	rows, err := q.db.Query(ctx, getPages,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPagesRow{}
+	for rows.Next() {
 		var i GetPagesRow
-		if err := rows.Scan(&i.Url, &i.VisitorCount); err != nil {
+		if err := rows.Scan(
+			&i.Url,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReferrals = `-- name: GetReferrals :many
+WITH scoped AS (
+  SELECT referrer, visitor_id, CASE WHEN event_type = 'pageview' THEN 1 ELSE 0 END AS pageviews,
+    CASE WHEN event_type <> 'pageview' THEN 1 ELSE 0 END AS events
+  FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
+  WHERE referrer IS NOT NULL AND a.tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+    (
+      ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+      (timestamp >= $3 AND timestamp < $4)
+    ) AND
+    (NOT $5::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+  UNION ALL
+  -- the hours sketches were built for, one row per visitor of a value
+  SELECT value, visitor_id, pageviews, events
+  FROM event_visitors
+  WHERE $5::boolean AND tracking_id = $1 AND dimension = 'referrer' AND
+    bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+), imported AS (
+  SELECT value AS referrer, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews
+  FROM imported_values($1, 'referrer', $2::jsonb, $3, $4)
+  GROUP BY value
+), grouped AS (
+  -- imported visitors and pageviews of a value add to the tracked ones
+  SELECT referrer, SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events
+  FROM (
+    SELECT referrer, COUNT(DISTINCT visitor_id) AS visitors,
+      SUM(pageviews) AS pageviews,
+      SUM(events) AS events
+    FROM scoped
+    GROUP BY referrer
+    UNION ALL
+    SELECT referrer, visitors, pageviews, 0 FROM imported
+  ) g
+  GROUP BY referrer
+), ranked AS (
+  SELECT referrer, visitors, pageviews, events, COUNT(*) OVER () AS total_rows,
+    ROW_NUMBER() OVER (ORDER BY
+      CASE WHEN $6::text = 'visitors:asc' THEN visitors END ASC,
+      CASE WHEN $6::text = 'name:asc' THEN referrer END ASC,
+      CASE WHEN $6::text = 'name:desc' THEN referrer END DESC,
+      visitors DESC, referrer) AS position
+  FROM grouped
+), totals AS (
+  SELECT ((SELECT COUNT(DISTINCT visitor_id) FROM scoped) + (
+    SELECT COALESCE(SUM(visitors), 0)
+    FROM imported_values($1, 'all', $2::jsonb, $3, $4)
+  ))::bigint AS total_visitors
+), other AS (
+  SELECT SUM(visitors)::bigint AS visitors, SUM(pageviews)::bigint AS pageviews, SUM(events)::bigint AS events,
+    MAX(total_rows) AS total_rows, MIN(position) AS position
+  FROM (
+    SELECT COUNT(DISTINCT s.visitor_id) AS visitors,
+      SUM(s.pageviews) AS pageviews,
+      SUM(s.events) AS events,
+      MAX(r.total_rows) AS total_rows, MIN(r.position) AS position
+    FROM scoped s JOIN ranked r ON s.referrer = r.referrer
+    WHERE r.position > $7::bigint + $8::bigint
+    UNION ALL
+    SELECT SUM(i.visitors), SUM(i.pageviews), 0, MAX(r.total_rows), MIN(r.position)
+    FROM imported i JOIN ranked r ON i.referrer = r.referrer
+    WHERE r.position > $7::bigint + $8::bigint
+  ) parts
+)
+SELECT referrer, visitors, pageviews, events, total_visitors, total_rows, position, false AS other
+FROM ranked CROSS JOIN totals
+WHERE ($9::text[] IS NULL AND position > $7::bigint AND position <= $7::bigint + $8::bigint) OR
+  referrer = ANY($9::text[])
+UNION ALL
+-- the totals, even for a page past the last row
+SELECT NULL, 0, 0, 0, total_visitors, (SELECT COUNT(*) FROM grouped), 0, false
+FROM totals
+WHERE $9::text[] IS NULL
+UNION ALL
+SELECT NULL, o.visitors, o.pageviews, o.events, t.total_visitors, o.total_rows, o.position, true
+FROM other o CROSS JOIN totals t
+WHERE $10::boolean AND o.position IS NOT NULL
+ORDER BY position
+`
+
+type GetReferralsParams struct {
+	TrackingID   uuid.UUID    `json:"tracking_id"`
+	Filters      []byte       `json:"filters"`
+	StartDate    sql.NullTime `json:"start_date"`
+	EndDate      sql.NullTime `json:"end_date"`
+	Rollup       bool         `json:"rollup"`
+	Sort         string       `json:"sort"`
+	PageOffset   int64        `json:"page_offset"`
+	PageLimit    int64        `json:"page_limit"`
+	Keys         []string     `json:"keys"`
+	IncludeOther bool         `json:"include_other"`
+}
+
+type GetReferralsRow struct {
+	Referrer      *string `json:"referrer"`
+	Visitors      int64   `json:"visitors"`
+	Pageviews     int64   `json:"pageviews"`
+	Events        int64   `json:"events"`
+	TotalVisitors int64   `json:"total_visitors"`
+	TotalRows     int64   `json:"total_rows"`
+	Position      int64   `json:"position"`
+	Other         bool    `json:"other"`
+}
+
+func (q *Queries) GetReferrals(ctx context.Context, arg GetReferralsParams) ([]GetReferralsRow, error) {
+	rows, err := q.db.Query(ctx, getReferrals,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Rollup,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Keys,
+		arg.IncludeOther,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetReferralsRow{}
+	for rows.Next() {
+		var i GetReferralsRow
+		if err := rows.Scan(
+			&i.Referrer,
+			&i.Visitors,
+			&i.Pageviews,
+			&i.Events,
+			&i.TotalVisitors,
+			&i.TotalRows,
+			&i.Position,
+			&i.Other,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRetentionCohorts = `-- name: GetRetentionCohorts :many
+WITH activity AS (
+  SELECT DISTINCT persistent_id, date_trunc($1::text, timestamp, $2::text) AS period_start
+  FROM events e
+  WHERE tracking_id = $3 AND persistent_id IS NOT NULL AND event_matches_filters(e, $4::jsonb)
+),
+cohorts AS (
+  SELECT persistent_id, MIN(period_start) AS cohort
+  FROM activity
+  GROUP BY persistent_id
+  HAVING MIN(period_start) BETWEEN $5::timestamptz AND $6::timestamptz
+)
+SELECT c.cohort::timestamptz AS cohort,
+  (CASE WHEN $1::text = 'week'
+    THEN EXTRACT(DAY FROM a.period_start - c.cohort) / 7
+    ELSE EXTRACT(YEAR FROM age(a.period_start, c.cohort)) * 12 + EXTRACT(MONTH FROM age(a.period_start, c.cohort))
+  END)::int AS period_offset,
+  COUNT(DISTINCT a.persistent_id) AS visitors
+FROM cohorts c JOIN activity a ON a.persistent_id = c.persistent_id
+GROUP BY c.cohort, period_offset
+ORDER BY c.cohort, period_offset
+`
+
+type GetRetentionCohortsParams struct {
+	Period     string       `json:"period"`
+	Timezone   string       `json:"timezone"`
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+}
+
+type GetRetentionCohortsRow struct {
+	Cohort       sql.NullTime `json:"cohort"`
+	PeriodOffset int32        `json:"period_offset"`
+	Visitors     int64        `json:"visitors"`
+}
+
+func (q *Queries) GetRetentionCohorts(ctx context.Context, arg GetRetentionCohortsParams) ([]GetRetentionCohortsRow, error) {
+	rows, err := q.db.Query(ctx, getRetentionCohorts,
+		arg.Period,
+		arg.Timezone,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRetentionCohortsRow{}
+	for rows.Next() {
+		var i GetRetentionCohortsRow
+		if err := rows.Scan(&i.Cohort, &i.PeriodOffset, &i.Visitors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRetentionCutoff = `-- name: GetRetentionCutoff :one
+SELECT GREATEST(
+  -- the later of the app's data retention and the EVENTS_RETENTION policy,
+  -- NULL when neither drops raw events
+  NOW() - a.data_retention::interval,
+  NOW() - (
+    SELECT (j.config->>'drop_after')::interval FROM timescaledb_information.jobs j
+    WHERE j.proc_name = 'policy_retention' AND j.hypertable_name = 'events'
+    LIMIT 1
+  )
+)::timestamptz AS expires_before
+FROM apps a
+WHERE a.tracking_id = $1
+`
+
+func (q *Queries) GetRetentionCutoff(ctx context.Context, trackingID uuid.UUID) (sql.NullTime, error) {
+	row := q.db.QueryRow(ctx, getRetentionCutoff, trackingID)
+	var expiresBefore sql.NullTime
+	err := row.Scan(&expiresBefore)
+	return expiresBefore, err
+}
+
+const getRetentionCutoffs = `-- name: GetRetentionCutoffs :many
+SELECT tracking_id, (NOW() - data_retention::interval)::timestamptz AS expires_before
+FROM apps
+WHERE data_retention IS NOT NULL
+`
+
+type GetRetentionCutoffsRow struct {
+	TrackingID    uuid.UUID    `json:"tracking_id"`
+	ExpiresBefore sql.NullTime `json:"expires_before"`
+}
+
+func (q *Queries) GetRetentionCutoffs(ctx context.Context) ([]GetRetentionCutoffsRow, error) {
+	rows, err := q.db.Query(ctx, getRetentionCutoffs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRetentionCutoffsRow{}
+	for rows.Next() {
+		var i GetRetentionCutoffsRow
+		if err := rows.Scan(&i.TrackingID, &i.ExpiresBefore); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShareByTokenHash = `-- name: GetShareByTokenHash :one
+SELECT id, tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at, revoked_at, created_at FROM shares WHERE token_hash = $1
+`
+
+func (q *Queries) GetShareByTokenHash(ctx context.Context, tokenHash string) (Share, error) {
+	row := q.db.QueryRow(ctx, getShareByTokenHash, tokenHash)
+	var i Share
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.PasswordHash,
+		&i.Endpoints,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getShares = `-- name: GetShares :many
+SELECT id, tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at, revoked_at, created_at FROM shares WHERE tracking_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) GetShares(ctx context.Context, trackingID uuid.UUID) ([]Share, error) {
+	rows, err := q.db.Query(ctx, getShares, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Share{}
+	for rows.Next() {
+		var i Share
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.PasswordHash,
+			&i.Endpoints,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -428,39 +2911,209 @@ func (q *Queries) GetPages(ctx context.Context, arg GetPagesParams) ([]GetPagesR
 	return items, nil
 }
 
-const getReferrals = `-- name: GetReferrals :many
-SELECT referrer, COUNT(DISTINCT visitor_id) AS visitor_count
-FROM apps a JOIN events e ON a.tracking_id = e.tracking_id
-WHERE referrer IS NOT NULL AND a.tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
+const getSketchInputs = `-- name: GetSketchInputs :many
+SELECT tracking_id, time_bucket(INTERVAL '1 hour', timestamp)::timestamptz AS bucket, visitor_id,
+  url, referrer, country, browser, device, operating_system,
+  COUNT(*) FILTER (WHERE event_type = 'pageview') AS pageviews,
+  COUNT(*) FILTER (WHERE event_type <> 'pageview') AS events
+FROM events
+WHERE timestamp >= $1 AND timestamp < $2
+GROUP BY tracking_id, bucket, visitor_id, url, referrer, country, browser, device, operating_system
+`
+
+type GetSketchInputsParams struct {
+	StartDate sql.NullTime `json:"start_date"`
+	EndDate   sql.NullTime `json:"end_date"`
+}
+
+type GetSketchInputsRow struct {
+	TrackingID      uuid.UUID    `json:"tracking_id"`
+	Bucket          sql.NullTime `json:"bucket"`
+	VisitorID       string       `json:"visitor_id"`
+	Url             *string      `json:"url"`
+	Referrer        *string      `json:"referrer"`
+	Country         string       `json:"country"`
+	Browser         string       `json:"browser"`
+	Device          string       `json:"device"`
+	OperatingSystem string       `json:"operating_system"`
+	Pageviews       int64        `json:"pageviews"`
+	Events          int64        `json:"events"`
+}
+
+func (q *Queries) GetSketchInputs(ctx context.Context, arg GetSketchInputsParams) ([]GetSketchInputsRow, error) {
+	rows, err := q.db.Query(ctx, getSketchInputs, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSketchInputsRow{}
+	for rows.Next() {
+		var i GetSketchInputsRow
+		if err := rows.Scan(
+			&i.TrackingID,
+			&i.Bucket,
+			&i.VisitorID,
+			&i.Url,
+			&i.Referrer,
+			&i.Country,
+			&i.Browser,
+			&i.Device,
+			&i.OperatingSystem,
+			&i.Pageviews,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSketchWatermark = `-- name: GetSketchWatermark :one
+SELECT built_until FROM sketch_watermark
+`
+
+func (q *Queries) GetSketchWatermark(ctx context.Context) (sql.NullTime, error) {
+	row := q.db.QueryRow(ctx, getSketchWatermark)
+	var builtUntil sql.NullTime
+	err := row.Scan(&builtUntil)
+	return builtUntil, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, email FROM users WHERE lower(email) = lower($1)
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(&i.ID, &i.Email)
+	return i, err
+}
+
+const getUserFlow = `-- name: GetUserFlow :many
+WITH steps AS (
+  SELECT visitor_id,
+    CASE WHEN event_type = 'pageview'
+      THEN COALESCE(NULLIF(substring(url from '^[a-zA-Z]+://[^/]+(/[^?#]*)'), ''), '/')
+      ELSE event_type
+    END AS step,
+    ROW_NUMBER() OVER (PARTITION BY visitor_id ORDER BY timestamp) AS position
+  FROM events e
+  WHERE tracking_id = $1 AND event_matches_filters(e, $2::jsonb) AND
+  (
+    ($3::timestamptz IS NULL AND $4::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
+    (timestamp >= $3 AND timestamp < $4)
+  )
+),
+anchors AS (
+  SELECT visitor_id, MIN(position) AS position
+  FROM steps
+  WHERE step = $5::text
+  GROUP BY visitor_id
+),
+paths AS (
+  SELECT s.visitor_id, s.step, (s.position - a.position)::int AS depth,
+    LAG(s.step) OVER (PARTITION BY s.visitor_id ORDER BY s.position) AS previous_step
+  FROM steps s JOIN anchors a ON a.visitor_id = s.visitor_id
+  WHERE s.position - a.position BETWEEN $6::int AND $7::int
 )
-GROUP BY referrer
-ORDER BY visitor_count DESC
+SELECT depth, COALESCE(previous_step, '')::text AS previous_step, step::text AS step, COUNT(DISTINCT visitor_id) AS visitors
+FROM paths
+GROUP BY depth, previous_step, step
+ORDER BY depth, visitors DESC
 `
 
-type GetReferralsParams struct {
+type GetUserFlowParams struct {
 	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
+	Filters    []byte       `json:"filters"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+	Step       string       `json:"step"`
+	MinDepth   int32        `json:"min_depth"`
+	MaxDepth   int32        `json:"max_depth"`
 }
 
-type GetReferralsRow struct {
-	Referrer     *string `json:"referrer"`
-	VisitorCount int64   `json:"visitor_count"`
+type GetUserFlowRow struct {
+	Depth        int32  `json:"depth"`
+	PreviousStep string `json:"previous_step"`
+	Step         string `json:"step"`
+	Visitors     int64  `json:"visitors"`
 }
 
-func (q *Queries) GetReferrals(ctx context.Context, arg GetReferralsParams) ([]GetReferralsRow, error) {
-	rows, err := q.db.Query(ctx, getReferrals, arg.TrackingID, arg.Column2, arg.Column3)
+func (q *Queries) GetUserFlow(ctx context.Context, arg GetUserFlowParams) ([]GetUserFlowRow, error) {
+	rows, err := q.db.Query(ctx, getUserFlow,
+		arg.TrackingID,
+		arg.Filters,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Step,
+		arg.MinDepth,
+		arg.MaxDepth,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetReferralsRow{}
+	items := []GetUserFlowRow{}
 	for rows.Next() {
-		var i GetReferralsRow
-		if err := rows.Scan(&i.Referrer, &i.VisitorCount); err != nil {
+		var i GetUserFlowRow
+		if err := rows.Scan(
+			&i.Depth,
+			&i.PreviousStep,
+			&i.Step,
+			&i.Visitors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserInvitations = `-- name: GetUserInvitations :many
+SELECT i.id, i.tracking_id, i.email, i.role, i.invited_by, i.created_at, a.name AS app_name
+FROM app_invitations i
+JOIN users u ON i.email = lower(u.email)
+JOIN apps a ON a.tracking_id = i.tracking_id
+WHERE u.id = $1
+ORDER BY i.created_at DESC
+`
+
+type GetUserInvitationsRow struct {
+	ID         uuid.UUID    `json:"id"`
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Email      string       `json:"email"`
+	Role       string       `json:"role"`
+	InvitedBy  uuid.UUID    `json:"invited_by"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	AppName    string       `json:"app_name"`
+}
+
+func (q *Queries) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]GetUserInvitationsRow, error) {
+	rows, err := q.db.Query(ctx, getUserInvitations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserInvitationsRow{}
+	for rows.Next() {
+		var i GetUserInvitationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrackingID,
+			&i.Email,
+			&i.Role,
+			&i.InvitedBy,
+			&i.CreatedAt,
+			&i.AppName,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -472,20 +3125,41 @@ func (q *Queries) GetReferrals(ctx context.Context, arg GetReferralsParams) ([]G
 }
 
 const getVisitors = `-- name: GetVisitors :many
-SELECT time_bucket($4, timestamp::timestamptz)::timestamptz AS time, COUNT(DISTINCT visitor_id) AS visitors
-FROM events WHERE tracking_id = $1 AND
-(
-  ($2::timestamptz IS NULL AND $3::timestamptz IS NULL AND timestamp >= NOW() - INTERVAL '24 hours') OR
-  (timestamp BETWEEN $2 AND $3)
-)
+-- imported days count in the bucket they start in. With rollup, the hours
+-- sketches were built for are read from event_visitors, which keeps a row per
+-- visitor in each hour, and only later hours from raw events.
+SELECT time, SUM(visitors)::bigint AS visitors
+FROM (
+  SELECT time_bucket_gapfill($1, timestamp, $2::text, $3::timestamptz, $4::timestamptz)::timestamptz AS time,
+    COALESCE(COUNT(DISTINCT visitor_id), 0)::bigint AS visitors
+  FROM (
+    SELECT timestamp, visitor_id
+    FROM events e WHERE tracking_id = $5 AND event_matches_filters(e, $6::jsonb) AND
+      timestamp >= $3 AND timestamp < $4 AND
+      (NOT $7::boolean OR timestamp >= (SELECT built_until FROM sketch_watermark))
+    UNION ALL
+    SELECT bucket, visitor_id
+    FROM event_visitors
+    WHERE $7::boolean AND tracking_id = $5 AND dimension = 'all' AND
+      bucket >= $3 AND bucket < LEAST($4, (SELECT built_until FROM sketch_watermark))
+  ) v
+  GROUP BY 1
+  UNION ALL
+  SELECT time_bucket($1, bucket, $2::text)::timestamptz, visitors
+  FROM imported_values($5, 'all', $6::jsonb, $3, $4)
+) t
 GROUP BY time
+ORDER BY time
 `
 
 type GetVisitorsParams struct {
-	TrackingID uuid.UUID    `json:"tracking_id"`
-	Column2    sql.NullTime `json:"column_2"`
-	Column3    sql.NullTime `json:"column_3"`
 	TimeBucket interface{}  `json:"time_bucket"`
+	Timezone   string       `json:"timezone"`
+	StartDate  sql.NullTime `json:"start_date"`
+	EndDate    sql.NullTime `json:"end_date"`
+	TrackingID uuid.UUID    `json:"tracking_id"`
+	Filters    []byte       `json:"filters"`
+	Rollup     bool         `json:"rollup"`
 }
 
 type GetVisitorsRow struct {
@@ -495,10 +3169,13 @@ type GetVisitorsRow struct {
 
 func (q *Queries) GetVisitors(ctx context.Context, arg GetVisitorsParams) ([]GetVisitorsRow, error) {
 	rows, err := q.db.Query(ctx, getVisitors,
-		arg.TrackingID,
-		arg.Column2,
-		arg.Column3,
 		arg.TimeBucket,
+		arg.Timezone,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TrackingID,
+		arg.Filters,
+		arg.Rollup,
 	)
 	if err != nil {
 		return nil, err
@@ -518,20 +3195,319 @@ func (q *Queries) GetVisitors(ctx context.Context, arg GetVisitorsParams) ([]Get
 	return items, nil
 }
 
-const updateApp = `-- name: UpdateApp :one
+const heartbeatDeletion = `-- name: HeartbeatDeletion :execrows
+UPDATE deletions
+SET heartbeat_at = NOW()
+WHERE id = $1 AND locked_by = $2::text AND status = 'running'
+`
+
+type HeartbeatDeletionParams struct {
+	ID       uuid.UUID `json:"id"`
+	LockedBy string    `json:"locked_by"`
+}
+
+func (q *Queries) HeartbeatDeletion(ctx context.Context, arg HeartbeatDeletionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, heartbeatDeletion, arg.ID, arg.LockedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const heartbeatImport = `-- name: HeartbeatImport :execrows
+UPDATE imports
+SET heartbeat_at = NOW()
+WHERE id = $1 AND locked_by = $2::text AND status = 'running'
+`
+
+type HeartbeatImportParams struct {
+	ID       uuid.UUID `json:"id"`
+	LockedBy string    `json:"locked_by"`
+}
+
+func (q *Queries) HeartbeatImport(ctx context.Context, arg HeartbeatImportParams) (int64, error) {
+	result, err := q.db.Exec(ctx, heartbeatImport, arg.ID, arg.LockedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertImportedAggregates = `-- name: InsertImportedAggregates :exec
+INSERT INTO imported_aggregates (import_id, tracking_id, bucket, dimension, value, visitors, pageviews)
+SELECT $1, $2, a.bucket, a.dimension, a.value, a.visitors, a.pageviews
+FROM unnest($3::timestamptz[], $4::text[], $5::text[],
+  $6::bigint[], $7::bigint[])
+  AS a(bucket, dimension, value, visitors, pageviews)
+`
+
+type InsertImportedAggregatesParams struct {
+	ImportID        uuid.UUID   `json:"import_id"`
+	TrackingID      uuid.UUID   `json:"tracking_id"`
+	Buckets         []time.Time `json:"buckets"`
+	Dimensions      []string    `json:"dimensions"`
+	DimensionValues []string    `json:"dimension_values"`
+	Visitors        []int64     `json:"visitors"`
+	Pageviews       []int64     `json:"pageviews"`
+}
+
+func (q *Queries) InsertImportedAggregates(ctx context.Context, arg InsertImportedAggregatesParams) error {
+	_, err := q.db.Exec(ctx, insertImportedAggregates,
+		arg.ImportID,
+		arg.TrackingID,
+		arg.Buckets,
+		arg.Dimensions,
+		arg.DimensionValues,
+		arg.Visitors,
+		arg.Pageviews,
+	)
+	return err
+}
+
+const insertImportedEvents = `-- name: InsertImportedEvents :execrows
+INSERT INTO events (
+  import_id, tracking_id, visitor_id, event_type, url, referrer, country, browser, device, operating_system, timestamp
+)
+SELECT $1::uuid, $2::uuid, e.visitor_id, e.event_type, e.url, e.referrer,
+  e.country, e.browser, e.device, e.operating_system, e.timestamp
+FROM unnest($3::text[], $4::text[], $5::text[], $6::text[],
+  $7::text[], $8::text[], $9::text[], $10::text[],
+  $11::timestamptz[])
+  AS e(visitor_id, event_type, url, referrer, country, browser, device, operating_system, timestamp)
+`
+
+type InsertImportedEventsParams struct {
+	ImportID         uuid.UUID   `json:"import_id"`
+	TrackingID       uuid.UUID   `json:"tracking_id"`
+	VisitorIds       []string    `json:"visitor_ids"`
+	EventTypes       []string    `json:"event_types"`
+	Urls             []*string   `json:"urls"`
+	Referrers        []*string   `json:"referrers"`
+	Countries        []string    `json:"countries"`
+	Browsers         []string    `json:"browsers"`
+	Devices          []string    `json:"devices"`
+	OperatingSystems []string    `json:"operating_systems"`
+	Timestamps       []time.Time `json:"timestamps"`
+}
+
+func (q *Queries) InsertImportedEvents(ctx context.Context, arg InsertImportedEventsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertImportedEvents,
+		arg.ImportID,
+		arg.TrackingID,
+		arg.VisitorIds,
+		arg.EventTypes,
+		arg.Urls,
+		arg.Referrers,
+		arg.Countries,
+		arg.Browsers,
+		arg.Devices,
+		arg.OperatingSystems,
+		arg.Timestamps,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const requeueImport = `-- name: RequeueImport :exec
+UPDATE imports
+SET status = 'pending', progress = 0, imported_events = 0, skipped_events = 0, start_date = NULL, end_date = NULL,
+  locked_by = NULL, heartbeat_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) RequeueImport(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, requeueImport, id)
+	return err
+}
+
+const revokeAPIToken = `-- name: RevokeAPIToken :one
+UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, token_hash, scopes, tracking_ids, expires_at, revoked_at, last_used_at, last_used_ip, created_at
+`
+
+type RevokeAPITokenParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeAPIToken(ctx context.Context, arg RevokeAPITokenParams) (ApiToken, error) {
+	row := q.db.QueryRow(ctx, revokeAPIToken, arg.ID, arg.UserID)
+	var i ApiToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.TrackingIds,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const revokeShare = `-- name: RevokeShare :one
+UPDATE shares SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 AND tracking_id = $2
+RETURNING id, tracking_id, user_id, name, token_hash, password_hash, endpoints, expires_at, revoked_at, created_at
+`
+
+type RevokeShareParams struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"tracking_id"`
+}
+
+func (q *Queries) RevokeShare(ctx context.Context, arg RevokeShareParams) (Share, error) {
+	row := q.db.QueryRow(ctx, revokeShare, arg.ID, arg.TrackingID)
+	var i Share
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.PasswordHash,
+		&i.Endpoints,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const setEventsCompressionPolicy = `-- name: SetEventsCompressionPolicy :exec
+SELECT set_events_compression_policy($1::text::interval)
+`
+
+func (q *Queries) SetEventsCompressionPolicy(ctx context.Context, compressAfter string) error {
+	_, err := q.db.Exec(ctx, setEventsCompressionPolicy, compressAfter)
+	return err
+}
+
+const setEventsRetentionPolicy = `-- name: SetEventsRetentionPolicy :exec
+SELECT set_events_retention_policy($1::text::interval)
+`
+
+func (q *Queries) SetEventsRetentionPolicy(ctx context.Context, dropAfter *string) error {
+	_, err := q.db.Exec(ctx, setEventsRetentionPolicy, dropAfter)
+	return err
+}
+
+const setImportedSince = `-- name: SetImportedSince :exec
 UPDATE apps
-SET name = $1
+SET imported_since = LEAST(imported_since, $1)
 WHERE tracking_id = $2
-RETURNING id, tracking_id, user_id, name, created_at
 `
 
-type UpdateAppParams struct {
-	Name       string    `json:"name"`
+type SetImportedSinceParams struct {
+	ImportedSince sql.NullTime `json:"imported_since"`
+	TrackingID    uuid.UUID    `json:"tracking_id"`
+}
+
+func (q *Queries) SetImportedSince(ctx context.Context, arg SetImportedSinceParams) error {
+	_, err := q.db.Exec(ctx, setImportedSince, arg.ImportedSince, arg.TrackingID)
+	return err
+}
+
+const setSketchWatermark = `-- name: SetSketchWatermark :exec
+UPDATE sketch_watermark SET built_until = $1
+`
+
+func (q *Queries) SetSketchWatermark(ctx context.Context, builtUntil sql.NullTime) error {
+	_, err := q.db.Exec(ctx, setSketchWatermark, builtUntil)
+	return err
+}
+
+const touchAPIToken = `-- name: TouchAPIToken :exec
+UPDATE api_tokens SET last_used_at = NOW(), last_used_ip = $2
+WHERE id = $1
+`
+
+type TouchAPITokenParams struct {
+	ID         uuid.UUID `json:"id"`
+	LastUsedIp *string   `json:"last_used_ip"`
+}
+
+func (q *Queries) TouchAPIToken(ctx context.Context, arg TouchAPITokenParams) error {
+	_, err := q.db.Exec(ctx, touchAPIToken, arg.ID, arg.LastUsedIp)
+	return err
+}
+
+const transferApp = `-- name: TransferApp :one
+WITH demoted AS (
+  UPDATE app_members SET role = 'admin'
+  WHERE tracking_id = $1 AND role = 'owner' AND user_id <> $2
+), promoted AS (
+  INSERT INTO app_members (tracking_id, user_id, role)
+  VALUES ( $1, $2, 'owner' )
+  ON CONFLICT ( tracking_id, user_id ) DO UPDATE
+  SET role = 'owner'
+)
+UPDATE apps SET user_id = $2, org_id = $3
+WHERE tracking_id = $1
+RETURNING id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id
+`
+
+type TransferAppParams struct {
 	TrackingID uuid.UUID `json:"tracking_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	OrgID      uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) TransferApp(ctx context.Context, arg TransferAppParams) (App, error) {
+	row := q.db.QueryRow(ctx, transferApp, arg.TrackingID, arg.UserID, arg.OrgID)
+	var i App
+	err := row.Scan(
+		&i.ID,
+		&i.TrackingID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.RetentionTracking,
+		&i.Timezone,
+		&i.DataRetention,
+		&i.ImportedSince,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const updateApp = `-- name: UpdateApp :one
+UPDATE apps
+SET name = COALESCE($1, name),
+  retention_tracking = COALESCE($2, retention_tracking),
+  timezone = COALESCE($3, timezone),
+  data_retention = CASE
+    WHEN $4::text IS NULL THEN data_retention
+    WHEN $4::text = 'forever' THEN NULL
+    ELSE $4::text
+  END
+WHERE tracking_id = $5
+RETURNING id, tracking_id, user_id, name, created_at, retention_tracking, timezone, data_retention, imported_since, org_id
+`
+
+type UpdateAppParams struct {
+	Name              *string   `json:"name"`
+	RetentionTracking *bool     `json:"retention_tracking"`
+	Timezone          *string   `json:"timezone"`
+	DataRetention     *string   `json:"data_retention"`
+	TrackingID        uuid.UUID `json:"tracking_id"`
 }
 
 func (q *Queries) UpdateApp(ctx context.Context, arg UpdateAppParams) (App, error) {
-	row := q.db.QueryRow(ctx, updateApp, arg.Name, arg.TrackingID)
+	row := q.db.QueryRow(ctx, updateApp,
+		arg.Name,
+		arg.RetentionTracking,
+		arg.Timezone,
+		arg.DataRetention,
+		arg.TrackingID,
+	)
 	var i App
 	err := row.Scan(
 		&i.ID,
@@ -539,6 +3515,165 @@ func (q *Queries) UpdateApp(ctx context.Context, arg UpdateAppParams) (App, erro
 		&i.UserID,
 		&i.Name,
 		&i.CreatedAt,
+		&i.RetentionTracking,
+		&i.Timezone,
+		&i.DataRetention,
+		&i.ImportedSince,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const updateAppMemberRole = `-- name: UpdateAppMemberRole :execrows
+UPDATE app_members SET role = $1
+WHERE tracking_id = $2 AND user_id = $3 AND role <> 'owner'
+`
+
+type UpdateAppMemberRoleParams struct {
+	Role       string    `json:"role"`
+	TrackingID uuid.UUID `json:"tracking_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) UpdateAppMemberRole(ctx context.Context, arg UpdateAppMemberRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAppMemberRole, arg.Role, arg.TrackingID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateDeletionProgress = `-- name: UpdateDeletionProgress :exec
+UPDATE deletions SET deleted_events = $1 WHERE id = $2
+`
+
+type UpdateDeletionProgressParams struct {
+	DeletedEvents int64     `json:"deleted_events"`
+	ID            uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateDeletionProgress(ctx context.Context, arg UpdateDeletionProgressParams) error {
+	_, err := q.db.Exec(ctx, updateDeletionProgress, arg.DeletedEvents, arg.ID)
+	return err
+}
+
+const updateImportProgress = `-- name: UpdateImportProgress :exec
+UPDATE imports
+SET progress = $1, imported_events = $2, skipped_events = $3,
+  start_date = $4, end_date = $5
+WHERE id = $6
+`
+
+type UpdateImportProgressParams struct {
+	Progress       float64      `json:"progress"`
+	ImportedEvents int64        `json:"imported_events"`
+	SkippedEvents  int64        `json:"skipped_events"`
+	StartDate      sql.NullTime `json:"start_date"`
+	EndDate        sql.NullTime `json:"end_date"`
+	ID             uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) UpdateImportProgress(ctx context.Context, arg UpdateImportProgressParams) error {
+	_, err := q.db.Exec(ctx, updateImportProgress,
+		arg.Progress,
+		arg.ImportedEvents,
+		arg.SkippedEvents,
+		arg.StartDate,
+		arg.EndDate,
+		arg.ID,
+	)
+	return err
+}
+
+const upsertEventSketches = `-- name: UpsertEventSketches :exec
+INSERT INTO event_sketches (tracking_id, bucket, dimension, value, visitors, pageviews, events)
+SELECT s.tracking_id, $1::timestamptz, s.dimension, s.value, s.visitors, s.pageviews, s.events
+FROM unnest($2::uuid[], $3::text[], $4::text[],
+  $5::bytea[], $6::bigint[], $7::bigint[])
+  AS s(tracking_id, dimension, value, visitors, pageviews, events)
+ON CONFLICT (tracking_id, dimension, bucket, value) DO UPDATE
+SET visitors = EXCLUDED.visitors, pageviews = EXCLUDED.pageviews, events = EXCLUDED.events
+`
+
+type UpsertEventSketchesParams struct {
+	Bucket          sql.NullTime `json:"bucket"`
+	TrackingIds     []uuid.UUID  `json:"tracking_ids"`
+	Dimensions      []string     `json:"dimensions"`
+	DimensionValues []string     `json:"dimension_values"`
+	Sketches        [][]byte     `json:"sketches"`
+	Pageviews       []int64      `json:"pageviews"`
+	Events          []int64      `json:"events"`
+}
+
+func (q *Queries) UpsertEventSketches(ctx context.Context, arg UpsertEventSketchesParams) error {
+	_, err := q.db.Exec(ctx, upsertEventSketches,
+		arg.Bucket,
+		arg.TrackingIds,
+		arg.Dimensions,
+		arg.DimensionValues,
+		arg.Sketches,
+		arg.Pageviews,
+		arg.Events,
+	)
+	return err
+}
+
+const upsertEventVisitors = `-- name: UpsertEventVisitors :exec
+INSERT INTO event_visitors (tracking_id, bucket, dimension, value, visitor_id, pageviews, events)
+SELECT v.tracking_id, $1::timestamptz, v.dimension, v.value, v.visitor_id, v.pageviews, v.events
+FROM unnest($2::uuid[], $3::text[], $4::text[],
+  $5::text[], $6::bigint[], $7::bigint[])
+  AS v(tracking_id, dimension, value, visitor_id, pageviews, events)
+ON CONFLICT (tracking_id, dimension, bucket, value, visitor_id) DO UPDATE
+SET pageviews = EXCLUDED.pageviews, events = EXCLUDED.events
+`
+
+type UpsertEventVisitorsParams struct {
+	Bucket          sql.NullTime `json:"bucket"`
+	TrackingIds     []uuid.UUID  `json:"tracking_ids"`
+	Dimensions      []string     `json:"dimensions"`
+	DimensionValues []string     `json:"dimension_values"`
+	VisitorIds      []string     `json:"visitor_ids"`
+	Pageviews       []int64      `json:"pageviews"`
+	Events          []int64      `json:"events"`
+}
+
+func (q *Queries) UpsertEventVisitors(ctx context.Context, arg UpsertEventVisitorsParams) error {
+	_, err := q.db.Exec(ctx, upsertEventVisitors,
+		arg.Bucket,
+		arg.TrackingIds,
+		arg.Dimensions,
+		arg.DimensionValues,
+		arg.VisitorIds,
+		arg.Pageviews,
+		arg.Events,
+	)
+	return err
+}
+
+const upsertOrganizationMember = `-- name: UpsertOrganizationMember :one
+INSERT INTO organization_members (
+  org_id, user_id, role
+) VALUES ( $1, $2, $3 )
+ON CONFLICT ( org_id, user_id ) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING org_id, user_id, role, created_at
+`
+
+type UpsertOrganizationMemberParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Role   string    `json:"role"`
+}
+
+func (q *Queries) UpsertOrganizationMember(ctx context.Context, arg UpsertOrganizationMemberParams) (OrganizationMember, error) {
+	row := q.db.QueryRow(ctx, upsertOrganizationMember, arg.OrgID, arg.UserID, arg.Role)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.OrgID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
 	)
 	return i, err
 }
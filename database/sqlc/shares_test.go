@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (suite *DatabaseSuite) TestShareLifecycle() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+	other := suite.createTestApp(userID)
+	expiresAt := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+	passwordHash := "$2a$10$hash"
+
+	share, err := suite.querier.CreateShare(suite.ctx, CreateShareParams{
+		TrackingID:   app.TrackingID,
+		UserID:       userID,
+		Name:         "client",
+		TokenHash:    "token-hash",
+		PasswordHash: &passwordHash,
+		Endpoints:    []string{"overview", "pages"},
+		ExpiresAt:    sql.NullTime{Time: expiresAt, Valid: true},
+	})
+	suite.NoError(err)
+	suite.Equal([]string{"overview", "pages"}, share.Endpoints)
+	suite.False(share.RevokedAt.Valid)
+
+	found, err := suite.querier.GetShareByTokenHash(suite.ctx, "token-hash")
+	suite.NoError(err)
+	suite.Equal(share.ID, found.ID)
+	suite.True(found.ExpiresAt.Time.Equal(expiresAt))
+
+	// tokens are unique
+	_, err = suite.querier.CreateShare(suite.ctx, CreateShareParams{TrackingID: other.TrackingID, UserID: userID, Name: "copy", TokenHash: "token-hash", Endpoints: []string{"overview"}})
+	suite.Error(err)
+
+	// links are only revoked through their app
+	_, err = suite.querier.RevokeShare(suite.ctx, RevokeShareParams{ID: share.ID, TrackingID: other.TrackingID})
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	revoked, err := suite.querier.RevokeShare(suite.ctx, RevokeShareParams{ID: share.ID, TrackingID: app.TrackingID})
+	suite.NoError(err)
+	suite.True(revoked.RevokedAt.Valid)
+
+	// revoking again keeps the time it was first revoked
+	again, err := suite.querier.RevokeShare(suite.ctx, RevokeShareParams{ID: share.ID, TrackingID: app.TrackingID})
+	suite.NoError(err)
+	suite.True(again.RevokedAt.Time.Equal(revoked.RevokedAt.Time))
+
+	shares, err := suite.querier.GetShares(suite.ctx, app.TrackingID)
+	suite.NoError(err)
+	suite.Len(shares, 1)
+	suite.True(shares[0].RevokedAt.Valid)
+
+	_, err = suite.querier.GetShareByTokenHash(suite.ctx, "other-hash")
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+}
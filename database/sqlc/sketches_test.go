@@ -0,0 +1,202 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (suite *DatabaseSuite) TestSketchWatermark() {
+	watermark, err := suite.querier.GetSketchWatermark(suite.ctx)
+	suite.NoError(err)
+	suite.True(watermark.Valid)
+
+	next := sql.NullTime{Time: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Valid: true}
+	suite.NoError(suite.querier.SetSketchWatermark(suite.ctx, next))
+
+	watermark, err = suite.querier.GetSketchWatermark(suite.ctx)
+	suite.NoError(err)
+	suite.True(next.Time.Equal(watermark.Time))
+}
+
+func (suite *DatabaseSuite) TestEventSketches() {
+	app := suite.createTestApp(suite.createTestUser())
+	hour := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+
+	upsert := func(pageviews int64) {
+		err := suite.querier.UpsertEventSketches(suite.ctx, UpsertEventSketchesParams{
+			Bucket:          at(hour),
+			TrackingIds:     []uuid.UUID{app.TrackingID, app.TrackingID, app.TrackingID},
+			Dimensions:      []string{"country", "country", "browser"},
+			DimensionValues: []string{"Nigeria", "Ghana", "Safari"},
+			Sketches:        [][]byte{{1, 14}, {1, 14}, {1, 14}},
+			Pageviews:       []int64{pageviews, 1, 1},
+			Events:          []int64{0, 0, 0},
+		})
+		suite.NoError(err)
+	}
+	upsert(3)
+	// rebuilding an hour replaces its sketches
+	upsert(5)
+
+	sketches, err := suite.querier.GetEventSketches(suite.ctx, GetEventSketchesParams{
+		TrackingID: app.TrackingID,
+		Dimension:  "country",
+		StartDate:  at(hour),
+		EndDate:    at(hour.Add(time.Hour)),
+	})
+	suite.NoError(err)
+	suite.Len(sketches, 2)
+	for _, sketch := range sketches {
+		suite.True(hour.Equal(sketch.Bucket.Time))
+		suite.Equal([]byte{1, 14}, sketch.Visitors)
+		if sketch.Value == "Nigeria" {
+			suite.Equal(int64(5), sketch.Pageviews)
+		}
+	}
+
+	sketches, err = suite.querier.GetEventSketches(suite.ctx, GetEventSketchesParams{
+		TrackingID: app.TrackingID,
+		Dimension:  "country",
+		StartDate:  at(hour.Add(time.Hour)),
+		EndDate:    at(hour.Add(2 * time.Hour)),
+	})
+	suite.NoError(err)
+	suite.Empty(sketches)
+}
+
+// seedSketchEvents spreads events from a few visitors over the last 30 days,
+// with a few more in the last hour.
+func (suite *DatabaseSuite) seedSketchEvents(trackingID uuid.UUID) {
+	random := rand.New(rand.NewSource(42))
+	urls := []string{"https://example.com/", "https://example.com/pricing", "https://example.com/blog?page=2"}
+	referrers := []string{"https://google.com", "https://news.ycombinator.com"}
+	countries := []string{"Nigeria", "Ghana", "Kenya"}
+	browsers := []string{"Safari", "Firefox", "Chrome"}
+
+	insert := func(timestamp time.Time) {
+		eventType := "pageview"
+		if random.Intn(4) == 0 {
+			eventType = "signup"
+		}
+		var referrer *string
+		if random.Intn(2) == 0 {
+			referrer = &referrers[random.Intn(len(referrers))]
+		}
+
+		_, err := suite.db.Exec(suite.ctx, `
+			INSERT INTO events (tracking_id, visitor_id, event_type, url, referrer, country, browser, device, operating_system, details, timestamp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, 'iPhone', 'iOS', '{}', $8)`,
+			trackingID, fmt.Sprintf("visitor-%d", random.Intn(12)), eventType,
+			urls[random.Intn(len(urls))], referrer, countries[random.Intn(len(countries))],
+			browsers[random.Intn(len(browsers))], timestamp)
+		suite.NoError(err)
+	}
+
+	now := time.Now().UTC()
+	for range 400 {
+		insert(now.Add(-time.Duration(random.Int63n(int64(30 * 24 * time.Hour)))))
+	}
+
+	for range 20 {
+		insert(now.Add(-time.Duration(random.Int63n(int64(time.Hour)))))
+	}
+}
+
+func (suite *DatabaseSuite) TestSketchInputsMatchRawEvents() {
+	app := suite.createTestApp(suite.createTestUser())
+	suite.seedSketchEvents(app.TrackingID)
+
+	end := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+	start := end.AddDate(0, 0, -31)
+	rows, err := suite.querier.GetAppSketchInputs(suite.ctx, GetAppSketchInputsParams{
+		TrackingID: app.TrackingID,
+		StartDate:  sql.NullTime{Time: start, Valid: true},
+		EndDate:    sql.NullTime{Time: end, Valid: true},
+	})
+	suite.NoError(err)
+
+	visitors := make(map[string]bool)
+	var pageviews, events int64
+	for _, row := range rows {
+		visitors[row.VisitorID] = true
+		pageviews += row.Pageviews
+		events += row.Events
+	}
+
+	overview, err := suite.querier.GetOverview(suite.ctx, GetOverviewParams{
+		TrackingID: app.TrackingID,
+		StartDate:  sql.NullTime{Time: start, Valid: true},
+		EndDate:    sql.NullTime{Time: end, Valid: true},
+	})
+	suite.NoError(err)
+	suite.Equal(overview.Visitors, int64(len(visitors)))
+	suite.Equal(overview.Pageviews, pageviews)
+	suite.Equal(overview.Events, events)
+
+	// every app is read when building an hour
+	all, err := suite.querier.GetSketchInputs(suite.ctx, GetSketchInputsParams{
+		StartDate: sql.NullTime{Time: start, Valid: true},
+		EndDate:   sql.NullTime{Time: end, Valid: true},
+	})
+	suite.NoError(err)
+	suite.GreaterOrEqual(len(all), len(rows))
+}
+
+func (suite *DatabaseSuite) TestEventVisitors() {
+	app := suite.createTestApp(suite.createTestUser())
+	hour := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	count := func() int {
+		var visitors int
+		err := suite.db.QueryRow(suite.ctx, "SELECT COUNT(*) FROM event_visitors WHERE tracking_id = $1", app.TrackingID).Scan(&visitors)
+		suite.NoError(err)
+		return visitors
+	}
+
+	for _, bucket := range []time.Time{hour, hour.Add(time.Hour)} {
+		upsert := func(pageviews int64) {
+			err := suite.querier.UpsertEventVisitors(suite.ctx, UpsertEventVisitorsParams{
+				Bucket:          at(bucket),
+				TrackingIds:     []uuid.UUID{app.TrackingID, app.TrackingID, app.TrackingID},
+				Dimensions:      []string{"all", "country", "country"},
+				DimensionValues: []string{"", "Nigeria", "Nigeria"},
+				VisitorIds:      []string{"first", "first", "second"},
+				Pageviews:       []int64{pageviews, pageviews, 1},
+				Events:          []int64{0, 0, 1},
+			})
+			suite.NoError(err)
+		}
+		upsert(3)
+		// rebuilding an hour replaces its visitors
+		upsert(5)
+	}
+	suite.Equal(6, count())
+
+	var pageviews int64
+	err := suite.db.QueryRow(suite.ctx, `
+		SELECT pageviews FROM event_visitors
+		WHERE tracking_id = $1 AND dimension = 'all' AND bucket = $2 AND visitor_id = 'first'`, app.TrackingID, hour).Scan(&pageviews)
+	suite.NoError(err)
+	suite.Equal(int64(5), pageviews)
+
+	// rebuilding the sketches of a range deletes its visitors too
+	err = suite.querier.DeleteAppSketches(suite.ctx, DeleteAppSketchesParams{
+		TrackingID: app.TrackingID,
+		StartDate:  at(hour.Add(time.Hour)),
+		EndDate:    at(hour.Add(2 * time.Hour)),
+	})
+	suite.NoError(err)
+	suite.Equal(3, count())
+
+	err = suite.querier.DeleteExpiredEventVisitors(suite.ctx, DeleteExpiredEventVisitorsParams{
+		TrackingID:    app.TrackingID,
+		ExpiresBefore: at(hour.Add(time.Hour)),
+	})
+	suite.NoError(err)
+	suite.Equal(0, count())
+}
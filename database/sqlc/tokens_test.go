@@ -0,0 +1,56 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (suite *DatabaseSuite) TestAPITokenLifecycle() {
+	userID := suite.createTestUser()
+	app := suite.createTestApp(userID)
+
+	token, err := suite.querier.CreateAPIToken(suite.ctx, CreateAPITokenParams{
+		UserID:      userID,
+		Name:        "metabase",
+		TokenHash:   "live",
+		Scopes:      []string{"read-stats"},
+		TrackingIds: []uuid.UUID{app.TrackingID},
+	})
+	suite.NoError(err)
+	suite.Equal([]uuid.UUID{app.TrackingID}, token.TrackingIds)
+	_, err = suite.querier.CreateAPIToken(suite.ctx, CreateAPITokenParams{
+		UserID:      userID,
+		Name:        "expired",
+		TokenHash:   "expired",
+		Scopes:      []string{"ingest"},
+		TrackingIds: []uuid.UUID{},
+		ExpiresAt:   sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	suite.NoError(err)
+
+	ip := "203.0.113.7"
+	suite.NoError(suite.querier.TouchAPIToken(suite.ctx, TouchAPITokenParams{ID: token.ID, LastUsedIp: &ip}))
+	used, err := suite.querier.GetAPITokenByHash(suite.ctx, "live")
+	suite.NoError(err)
+	suite.True(used.LastUsedAt.Valid)
+	suite.Equal(ip, *used.LastUsedIp)
+	_, err = suite.querier.GetAPITokenByHash(suite.ctx, "expired")
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	// tokens of other users can't be revoked
+	_, err = suite.querier.RevokeAPIToken(suite.ctx, RevokeAPITokenParams{ID: token.ID, UserID: suite.createTestUser()})
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+	revoked, err := suite.querier.RevokeAPIToken(suite.ctx, RevokeAPITokenParams{ID: token.ID, UserID: userID})
+	suite.NoError(err)
+	suite.True(revoked.RevokedAt.Valid)
+	_, err = suite.querier.GetAPITokenByHash(suite.ctx, "live")
+	suite.True(errors.Is(err, pgx.ErrNoRows))
+
+	tokens, err := suite.querier.GetAPITokens(suite.ctx, userID)
+	suite.NoError(err)
+	suite.Len(tokens, 2)
+}
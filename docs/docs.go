@@ -42,16 +42,115 @@ const docTemplate = `{
                         "required": true
                     },
                     {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
                         "type": "string",
-                        "description": "start date",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -102,17 +201,116 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -164,16 +362,115 @@ const docTemplate = `{
                         "required": true
                     },
                     {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
                         "type": "string",
-                        "description": "start date",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -198,14 +495,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/analytics/os": {
+        "/analytics/events": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves operating system stats",
+                "description": "Retrieves the number of events of each type in a range, pageviews included, and the visitors who sent them, most frequent first",
                 "consumes": [
                     "application/json"
                 ],
@@ -215,7 +512,7 @@ const docTemplate = `{
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get OS",
+                "summary": "Get Event Types",
                 "parameters": [
                     {
                         "type": "string",
@@ -224,24 +521,60 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OSResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.EventTypesResponse"
                         }
                     },
                     "400": {
@@ -251,7 +584,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "failed to fetch operating systems",
+                        "description": "failed to fetch event types",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -259,24 +592,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/analytics/pages": {
+        "/analytics/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves page stats",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Downloads a zip with a CSV per report for the range and filters: overview, visitors and pageviews over time, and every row of the pages, referrers, countries, browsers, devices and operating systems breakdowns",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get Pages",
+                "summary": "Export Stats",
                 "parameters": [
                     {
                         "type": "string",
@@ -285,24 +615,73 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "time bucket size, defaults to one suited to the range",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "stats fetched successfully",
+                        "description": "zipped reports",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -312,7 +691,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "failed to fetch pages",
+                        "description": "failed to export stats",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -320,14 +699,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/analytics/pageviews": {
+        "/analytics/flow": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves page views",
+                "description": "Retrieves the most common steps visitors take after (or before) a page or event, as Sankey-ready nodes and links",
                 "consumes": [
                     "application/json"
                 ],
@@ -337,7 +716,7 @@ const docTemplate = `{
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get PageViews",
+                "summary": "Get User Flow",
                 "parameters": [
                     {
                         "type": "string",
@@ -348,22 +727,82 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "starting page path, e.g. /pricing",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "starting event name",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next or previous (default next)",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of steps to follow, 1 to 5 (default 3)",
+                        "name": "steps",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageViewResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.FlowResponse"
                         }
                     },
                     "400": {
@@ -373,7 +812,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "failed to fetch visitors",
+                        "description": "failed to fetch user flow",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -381,14 +820,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/analytics/referrals": {
+        "/analytics/os": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves referral stats",
+                "description": "Retrieves operating system stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -398,7 +837,7 @@ const docTemplate = `{
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get Referrals",
+                "summary": "Get OS",
                 "parameters": [
                     {
                         "type": "string",
@@ -407,78 +846,133 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "stats fetched successfully",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralResponse"
-                        }
                     },
-                    "400": {
-                        "description": "invalid request paramaters",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
-                        }
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "failed to fetch referrals",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
-                        }
-                    }
-                }
-            }
-        },
-        "/analytics/track": {
-            "get": {
-                "description": "Tracks an event based on encoded data",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Analytics"
-                ],
-                "summary": "Track an event",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Base64 encoded event data",
-                        "name": "data",
-                        "in": "query",
-                        "required": true
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Event tracked successfully",
+                        "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OSResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid base64 or JSON data",
+                        "description": "invalid request paramaters",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "500": {
-                        "description": "Failed to resolve geolocation or track event",
+                        "description": "failed to fetch operating systems",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -486,14 +980,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/analytics/visitors": {
+        "/analytics/overview": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves visitors",
+                "description": "Retrieves the headline numbers for a range: visitors, pageviews, visits, views per visit, bounce rate, visit duration and events",
                 "consumes": [
                     "application/json"
                 ],
@@ -503,7 +997,7 @@ const docTemplate = `{
                 "tags": [
                     "Analytics"
                 ],
-                "summary": "Get Visitors",
+                "summary": "Get Overview",
                 "parameters": [
                     {
                         "type": "string",
@@ -512,24 +1006,93 @@ const docTemplate = `{
                         "in": "query",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "start date",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
                         "name": "startDate",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "end date",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
                         "name": "endDate",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.VisitorResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OverviewResponse"
                         }
                     },
                     "400": {
@@ -539,7 +1102,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "failed to fetch visitors",
+                        "description": "failed to fetch overview",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -547,14 +1110,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/apps": {
+        "/analytics/pages": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves user apps",
+                "description": "Retrieves page stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -562,37 +1125,159 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Analytics"
+                ],
+                "summary": "Get Pages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get Apps",
                 "responses": {
                     "200": {
-                        "description": "apps fetched successfully",
+                        "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageResponse"
                         }
                     },
-                    "401": {
-                        "description": "userID not found in context",
+                    "400": {
+                        "description": "invalid request paramaters",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "500": {
-                        "description": "failed to fetch apps",
+                        "description": "failed to fetch pages",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/analytics/pageviews": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "creates an app",
+                "description": "Retrieves page views",
                 "consumes": [
                     "application/json"
                 ],
@@ -600,41 +1285,117 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Analytics"
                 ],
-                "summary": "Create App",
+                "summary": "Get PageViews",
                 "parameters": [
                     {
-                        "description": "app name",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CreateAppRequest"
-                        }
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "time bucket size, defaults to one suited to the range",
+                        "name": "interval",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "app created successfully",
+                        "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageViewResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
-                        }
-                    },
-                    "401": {
-                        "description": "userID not found in context",
+                        "description": "invalid request paramaters",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "500": {
-                        "description": "failed to create app",
+                        "description": "failed to fetch visitors",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -642,14 +1403,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/apps/{trackingID}": {
-            "delete": {
+        "/analytics/realtime": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates app by tracking ID",
+                "description": "Retrieves visitors active in the last five minutes with their top pages and sources",
                 "consumes": [
                     "application/json"
                 ],
@@ -657,52 +1418,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Analytics"
                 ],
-                "summary": "Delete App",
+                "summary": "Get Realtime",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tracking ID of the app to delete",
+                        "description": "app tracking ID",
                         "name": "trackingID",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "app successfully deleted",
+                    "200": {
+                        "description": "stats fetched successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.RealtimeResponse"
                         }
                     },
-                    "400": {
-                        "description": "invalid request body",
+                    "500": {
+                        "description": "failed to fetch realtime stats",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
-                    },
-                    "401": {
-                        "description": "userID not found in context",
+                    }
+                }
+            }
+        },
+        "/analytics/realtime/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams tracked events as Server-Sent Events. A \"realtime\" event with the current stats is sent first, then an \"event\" for every tracked event with the updated visitor count, and a \"ping\" every 15 seconds",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Stream Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stream of events",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.LiveUpdate"
                         }
                     },
                     "500": {
-                        "description": "failed to delete app",
+                        "description": "failed to fetch realtime stats",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/analytics/referrals": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates app by tracking ID",
+                "description": "Retrieves referral stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -710,48 +1501,247 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Apps"
+                    "Analytics"
                 ],
-                "summary": "Update App",
+                "summary": "Get Referrals",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tracking ID of the app to delete",
+                        "description": "app tracking ID",
                         "name": "trackingID",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "app name",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CreateAppRequest"
-                        }
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "rows per page, 1 to 1000 (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visitors:desc",
+                            "visitors:asc",
+                            "name:asc",
+                            "name:desc"
+                        ],
+                        "type": "string",
+                        "description": "row order (default visitors:desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add a row aggregating every row after the page",
+                        "name": "other",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "app name successfully changed",
+                        "description": "stats fetched successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid request body",
+                        "description": "invalid request paramaters",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
-                    "401": {
-                        "description": "userID not found in context",
+                    "500": {
+                        "description": "failed to fetch referrals",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/retention": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves visitor retention cohorts. Requires retention tracking to be enabled for the app",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Retention",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "cohort period (week or month)",
+                        "name": "cohort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stats fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.RetentionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request paramaters",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "500": {
-                        "description": "failed to update app",
+                        "description": "failed to fetch retention",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
@@ -759,9 +1749,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/{provider}": {
+        "/analytics/track": {
             "get": {
-                "description": "Initiates OAuth authentication with the specified provider and returns a JWT token upon successful login.",
+                "description": "Tracks an event based on encoded data",
                 "consumes": [
                     "application/json"
                 ],
@@ -769,237 +1759,3711 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Analytics"
                 ],
-                "summary": "User Sign-In",
+                "summary": "Track an event",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "OAuth provider (e.g., google, github)",
-                        "name": "provider",
-                        "in": "path",
+                        "description": "Base64 encoded event data",
+                        "name": "data",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT token",
+                        "description": "Event tracked successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "400": {
-                        "description": "Invalid provider or missing provider",
+                        "description": "Invalid base64 or JSON data",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to resolve geolocation or track event",
                         "schema": {
                             "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_ScMofeoluwa_minalytics_shared.APIStatus": {
+        },
+        "/analytics/visitors": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves visitors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Visitors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "realtime",
+                            "today",
+                            "yesterday",
+                            "7d",
+                            "30d",
+                            "month_to_date",
+                            "last_month",
+                            "12mo",
+                            "year_to_date",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "date range preset",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates and buckets, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "previous_period",
+                            "year_over_year",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "description": "comparison range",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison start date, required when compare is custom",
+                        "name": "compareStartDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comparison end date, required when compare is custom",
+                        "name": "compareEndDate",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "time bucket size, defaults to one suited to the range",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx"
+                        ],
+                        "type": "string",
+                        "description": "count unique visitors exactly or estimate them from sketches",
+                        "name": "precision",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stats fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.VisitorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request paramaters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch visitors",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves user apps",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Apps",
+                "responses": {
+                    "200": {
+                        "description": "apps fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch apps",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "creates an app in an organization the user is an admin of, or in their personal organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Create App",
+                "parameters": [
+                    {
+                        "description": "app name and organization",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CreateAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "app created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this organization does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "409": {
+                        "description": "app already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create app",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates app by tracking ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Delete App",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking ID of the app to delete",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "app successfully deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to delete app",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates app by tracking ID. data_retention, e.g. \"30 days\" or \"forever\", is how long raw events are kept, though never longer than the server's EVENTS_RETENTION",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Update App",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking ID of the app to delete",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "app settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.UpdateAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "app successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid data retention",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to update app",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/deletions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the deletions of an app's events, dry runs included, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Deletions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "deletions fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeletionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid trackingID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch deletions",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the events of an app in a date range, matching filters, of a visitor, or any combination of them, to honour erasure requests or clean up bad data. Deletions run in the background and are recorded for auditing with who asked for them and what they matched. A dry run only counts the events and visitors that would be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Delete Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "events to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeletionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry run completed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeletionResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "deletion queued",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to queue deletion",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/deletions/{deletionID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a deletion with its status and the number of events it deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Deletion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "deletion ID",
+                        "name": "deletionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "deletion fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid deletionID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "deletion not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch deletion",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams the raw events of an app as CSV, NDJSON or Parquet, oldest first. Errors after the export has started end the response early",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Export Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "export format (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated columns to export, defaults to all of id, timestamp, visitor_id, persistent_id, event_type, url, referrer, country, browser, device, operating_system, details",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start date, YYYY-MM-DD or RFC3339",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON array of filters, e.g. [{\\",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include imported data (default true)",
+                        "name": "imported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for dates, defaults to the app's timezone",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "exported events",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request paramaters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to export events",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/imports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the imports of an app, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Imports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "imports fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ImportsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid trackingID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch imports",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads the export of another analytics tool and imports it into the app in the background. GA4, Universal Analytics and Plausible exports are CSV files or zips of them, holding daily aggregates that are stored as imported aggregates and added to the tracked events in reports. Umami exports are plain PostgreSQL dumps of its database. Access logs of nginx or Apache, plain or gzipped, are read into pageviews, skipping static assets and bots. Poll the import for its progress",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Create Import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "export to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ga4",
+                            "universal_analytics",
+                            "plausible",
+                            "umami",
+                            "access_log"
+                        ],
+                        "type": "string",
+                        "description": "tool the export comes from",
+                        "name": "source",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "hostname of the pages, for exports that only have their paths",
+                        "name": "hostname",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "website to import from an Umami dump that holds several",
+                        "name": "website_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "format of access log lines: combined (default), common, or an nginx log_format or Apache LogFormat",
+                        "name": "log_format",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "import queued",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request parameters",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to queue import",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/imports/{importID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves an import with its status and progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "import ID",
+                        "name": "importID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid importID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "import not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch import",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the pending invitations to an app. Admins and owners only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get Invitations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "invitations fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.InvitationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid trackingID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch invitations",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invites someone to an app by email as an admin, editor or viewer. They become a member once they sign in with that email and accept. Inviting the same email again replaces the invitation. Admins and owners only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Invite Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "person to invite",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.InvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "invitation created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.InvitationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create invitation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/invitations/{invitationID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a pending invitation to an app. Admins and owners only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Revoke Invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "invitation ID",
+                        "name": "invitationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "invitation revoked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid invitationID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to revoke invitation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the members of an app with their roles: owner, admin, editor or viewer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get Members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "members fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.MembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid trackingID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch members",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/members/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member from an app. Admins and owners remove anyone but the owner, and other members only themselves",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Remove Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID of the member",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member removed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid userID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "member not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to remove member",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the role of a member of an app to admin, editor or viewer. The owner's role only changes by transferring the app. Admins and owners only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID of the member",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.UpdateMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member updated",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "member not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to update member",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the share links of an app, revoked ones included, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Get Share Links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share links fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.SharesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid trackingID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch share links",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a secret link granting read-only access to the stats of an app without an account, for making them public or sharing them with a client. The link opens up the selected analytics endpoints, or all of them, under /shared/{token}, optionally behind a password that starts a session at /shared/{token}/session and until it expires. The token is only returned here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Create Share Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "share link to create",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "share link created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create share link",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/shares/{shareID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a share link, which stops granting access right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Revoke Share Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "share link ID",
+                        "name": "shareID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share link revoked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid shareID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "share link not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to revoke share link",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{trackingID}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes a member the owner of an app and moves it into an organization they own, their personal one unless org_id is given. The previous owner stays on as an admin. Owners only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Transfer App",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app tracking ID",
+                        "name": "trackingID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member to transfer the app to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.TransferAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "app transferred",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this app does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "member not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "409": {
+                        "description": "app already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to transfer app",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/{provider}": {
+            "get": {
+                "description": "Initiates OAuth authentication with the specified provider and returns a JWT token upon successful login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User Sign-In",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OAuth provider (e.g., google, github)",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid provider or missing provider",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the pending invitations to apps for the email the user signed in with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get My Invitations",
+                "responses": {
+                    "200": {
+                        "description": "invitations fetched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.InvitationsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch invitations",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{invitationID}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts an invitation to an app, making the user a member of it with the role they were invited with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Accept Invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "invitation ID",
+                        "name": "invitationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "invitation accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.AppResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid invitationID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to accept invitation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the organizations the user is a member of, their personal organization first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Get Organizations",
+                "responses": {
+                    "200": {
+                        "description": "organizations fetched",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OrganizationsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch organizations",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an organization owned by the user. Organizations own apps, and their members have their role in every app of the organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Create Organization",
+                "parameters": [
+                    {
+                        "description": "organization name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "organization created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OrganizationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create organization",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{orgID}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the members of an organization and their roles",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Get Organization Members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "members fetched",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.MembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid orgID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch members",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a user who has signed in before to an organization, or changes their role in it. Admins and owners manage the members below owner, and only owners make or unmake owners",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Set Organization Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member and role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OrganizationMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member saved",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.MemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this organization does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "no user has signed in with this email",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "409": {
+                        "description": "an organization needs at least one owner",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save member",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{orgID}/members/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member from an organization along with their access to its apps. The apps they owned go to another owner of the organization. Admins and owners remove anyone below owner, owners also other owners, and other members only themselves",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Remove Organization Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "orgID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID of the member",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member removed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid userID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "your role in this organization does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "member not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "409": {
+                        "description": "an organization needs at least one owner",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to remove member",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/shared/{token}/session": {
+            "post": {
+                "description": "Checks the password of a share link and starts a session of it, which opens up the link for an hour, or until it expires, without sending the password again. The session is set as a cookie scoped to the link, and returned to be sent in the X-Share-Session header. Links without a password need no session. Too many wrong passwords lock the link for 15 minutes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Apps"
+                ],
+                "summary": "Start Share Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "password of the share link",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "share session started",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid share link password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "share link not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "410": {
+                        "description": "share link has expired",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "429": {
+                        "description": "too many wrong passwords for this share link, try again later",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to start share session",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists your API tokens, newest first, with when and from which IP they were last used",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Get API Tokens",
+                "responses": {
+                    "200": {
+                        "description": "API tokens fetched",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APITokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "the API token does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to fetch API tokens",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an API token for scripts and BI tools, sent as \"Authorization: Bearer mk_...\" in place of the JWT. Its scopes are read-stats, manage-apps and ingest, and it can be limited to some of your apps and set to expire. The token is only returned here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Create API Token",
+                "parameters": [
+                    {
+                        "description": "API token to create",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API token created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APITokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "the API token does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "app not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create API token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{tokenID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an API token, which stops working right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Revoke API Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API token ID",
+                        "name": "tokenID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API token revoked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APITokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid tokenID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "userID not found in context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "the API token does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "API token not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to revoke API token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIStatus"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_ScMofeoluwa_minalytics_shared.APIStatus": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.APIToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "description": "Token is the secret of the token, starting with mk_, only returned when\nit is created.",
+                    "type": "string"
+                },
+                "tracking_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.APITokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tracking_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.APITokenResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIToken"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.APITokensResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.APIToken"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.App": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data_retention": {
+                    "type": "string"
+                },
+                "imported_since": {
+                    "description": "ImportedSince is the start of the data imported into the app, when it\ngoes back further than CreatedAt.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "retention_tracking": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "Role is the role in the app of the user asking for it.",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "trackingID": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.AppResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.App"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_BrowserStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BrowserStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BrowserStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_CountryStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CountryStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CountryStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_DeviceStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeviceStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeviceStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_OSStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OSStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OSStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_PageStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_ReferralStats": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta"
+                },
+                "other": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralStats"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralStats"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.BreakdownMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "precision": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.BrowserResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_BrowserStats"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.BrowserStats": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string"
+                },
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Comparison": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "percentage_change": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.CountryResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_CountryStats"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.CountryStats": {
+            "type": "object",
+            "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.CreateAppRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "description": "OrgID is the organization the app goes into, the personal organization\nof the user when unset.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Deletion": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_events": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Filter"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "first_event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_event": {
+                    "type": "string"
+                },
+                "matched_events": {
+                    "type": "integer"
+                },
+                "matched_visitors": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "trackingID": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "visitor_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.DeletionRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Filter"
+                    }
+                },
+                "start_date": {
+                    "description": "StartDate and EndDate are YYYY-MM-DD dates, the end one included, or\nRFC3339 times, the end one excluded.",
+                    "type": "string"
+                },
+                "visitor_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.DeletionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Deletion"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.DeletionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Deletion"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.DeviceResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_DeviceStats"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.DeviceStats": {
+            "type": "object",
+            "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.EventTypeStats": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.EventTypesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.EventTypeStats"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Filter": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.FlowLink": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.FlowNode": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "step": {
+                    "type": "string"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.FlowResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.FlowStats"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.FlowStats": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.FlowLink"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.FlowNode"
+                    }
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Import": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imported_events": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "skipped_events": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "trackingID": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Import"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.ImportsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Import"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Invitation": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "description": "AppName is set on the invitations of the user asking for them.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "trackingID": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.InvitationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Invitation"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.InvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Invitation"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.LiveEvent": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "operating_system": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "referrer": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.LiveUpdate": {
+            "type": "object",
+            "properties": {
+                "current_visitors": {
+                    "type": "integer"
+                },
+                "event": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.LiveEvent"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Member": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.MemberResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Member"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.MembersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Member"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.OSResponse": {
             "type": "object",
             "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_OSStats"
+                },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.App": {
+        "github_com_ScMofeoluwa_minalytics_shared.OSStats": {
+            "type": "object",
+            "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "operating_system": {
+                    "type": "string"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.Organization": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
-                "trackingID": {
+                "personal": {
+                    "description": "Personal is set on the personal organization of the user asking for it,\nwhich their apps go into by default.",
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "Role is the role in the organization of the user asking for it.",
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.AppResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.OrganizationMemberRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.OrganizationResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.App"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Organization"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.BrowserResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.OrganizationsResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.BrowserStats"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Organization"
+                    }
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.BrowserStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.OverviewResponse": {
             "type": "object",
             "properties": {
-                "browser": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OverviewStats"
+                },
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.OverviewStats": {
+            "type": "object",
+            "properties": {
+                "bounce_rate": {
+                    "type": "number"
                 },
-                "percentage": {
+                "comparison": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                    }
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "views_per_visit": {
+                    "type": "number"
+                },
+                "visit_duration": {
+                    "type": "number"
+                },
+                "visitors": {
+                    "type": "integer"
+                },
+                "visits": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.CountryResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.PageResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.CountryStats"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_PageStats"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.CountryStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.PageStats": {
             "type": "object",
             "properties": {
-                "country": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "path": {
                     "type": "string"
                 },
                 "percentage": {
+                    "type": "number"
+                },
+                "visitors": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.CreateAppRequest": {
+        "github_com_ScMofeoluwa_minalytics_shared.PageViewResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "data": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageViewStats"
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.DeviceResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.PageViewStats": {
+            "type": "object",
+            "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.RealtimeResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.DeviceStats"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.RealtimeStats"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.DeviceStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.RealtimeStats": {
             "type": "object",
             "properties": {
-                "device": {
-                    "type": "string"
-                },
-                "percentage": {
+                "current_visitors": {
                     "type": "integer"
+                },
+                "pages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageStats"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralStats"
+                    }
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.OSResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.ReferralResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.OSStats"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Breakdown-github_com_ScMofeoluwa_minalytics_shared_ReferralStats"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.OSStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.ReferralStats": {
             "type": "object",
             "properties": {
-                "operating_system": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
+                "events": {
+                    "type": "integer"
+                },
+                "pageviews": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "referrer": {
+                    "type": "string"
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.RetentionCohort": {
+            "type": "object",
+            "properties": {
+                "cohort": {
                     "type": "string"
                 },
+                "retention": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.RetentionPeriod"
+                    }
+                },
+                "visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.RetentionPeriod": {
+            "type": "object",
+            "properties": {
                 "percentage": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "integer"
+                },
+                "visitors": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.PageResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.RetentionResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageStats"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.RetentionCohort"
+                    }
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.PageStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.Share": {
             "type": "object",
             "properties": {
-                "path": {
+                "created_at": {
                     "type": "string"
                 },
-                "visitor_count": {
-                    "type": "integer"
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password_protected": {
+                    "type": "boolean"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token is the secret of the link, only returned when it is created.",
+                    "type": "string"
+                },
+                "trackingID": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.PageViewResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.ShareRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.ShareResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.PageViewStats"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Share"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.PageViewStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.ShareSession": {
             "type": "object",
             "properties": {
-                "time": {
+                "expires_at": {
                     "type": "string"
                 },
-                "views": {
-                    "type": "integer"
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.ReferralResponse": {
+        "github_com_ScMofeoluwa_minalytics_shared.ShareSessionRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.ShareSessionResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ReferralStats"
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.ShareSession"
                 },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_ScMofeoluwa_minalytics_shared.ReferralStats": {
+        "github_com_ScMofeoluwa_minalytics_shared.SharesResponse": {
             "type": "object",
             "properties": {
-                "referrer": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Share"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.TransferAppRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "org_id": {
+                    "description": "OrgID is an organization the member owns to move the app into, their\npersonal organization when left out.",
                     "type": "string"
                 },
-                "visitor_count": {
-                    "type": "integer"
+                "user_id": {
+                    "description": "UserID is the member the app is transferred to.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.UpdateAppRequest": {
+            "type": "object",
+            "properties": {
+                "data_retention": {
+                    "description": "DataRetention is how long raw events are kept, such as \"30 days\" or\n\"13 months\", or \"forever\". EVENTS_RETENTION still drops older events, so\nforever is only as long as it.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "retention_tracking": {
+                    "type": "boolean"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_ScMofeoluwa_minalytics_shared.UpdateMemberRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
                 }
             }
         },
@@ -1017,6 +5481,9 @@ const docTemplate = `{
         "github_com_ScMofeoluwa_minalytics_shared.VisitorStats": {
             "type": "object",
             "properties": {
+                "comparison": {
+                    "$ref": "#/definitions/github_com_ScMofeoluwa_minalytics_shared.Comparison"
+                },
                 "time": {
                     "type": "string"
                 },
@@ -0,0 +1,199 @@
+// Package export streams the raw events of an app as CSV, NDJSON or Parquet.
+// Events are read through a server-side cursor a batch at a time, so exports
+// of any size run in constant memory.
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat validates an export format.
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatCSV, FormatNDJSON, FormatParquet:
+		return Format(format), nil
+	}
+	return "", fmt.Errorf("format must be csv, ndjson or parquet")
+}
+
+// ContentType is the media type of exports in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// column is an exportable events column. Everything but the timestamp is read
+// as text, so writers only deal with strings, NULLs and times.
+type column struct {
+	name string
+	expr string
+}
+
+var columns = []column{
+	{name: "id", expr: "id::text"},
+	{name: "timestamp", expr: "timestamp"},
+	{name: "visitor_id", expr: "visitor_id"},
+	{name: "persistent_id", expr: "persistent_id"},
+	{name: "event_type", expr: "event_type"},
+	{name: "url", expr: "url"},
+	{name: "referrer", expr: "referrer"},
+	{name: "country", expr: "country"},
+	{name: "browser", expr: "browser"},
+	{name: "device", expr: "device"},
+	{name: "operating_system", expr: "operating_system"},
+	{name: "details", expr: "details::text"},
+}
+
+// Columns lists every exportable column, in the order they are exported by default.
+func Columns() []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// ParseColumns validates a comma separated list of columns. An empty list
+// selects every column.
+func ParseColumns(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return Columns(), nil
+	}
+
+	known := Columns()
+	var selected []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown column %q, expect any of %s", name, strings.Join(known, ", "))
+		}
+		if slices.Contains(selected, name) {
+			return nil, fmt.Errorf("column %q is selected twice", name)
+		}
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+// DB begins the read only transaction the export cursor lives in. *pgxpool.Pool
+// implements it.
+type DB interface {
+	BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error)
+}
+
+type Request struct {
+	TrackingID uuid.UUID
+	// StartDate and EndDate bound the export, either can be left open.
+	StartDate sql.NullTime
+	EndDate   sql.NullTime
+	// Filters is a JSON array of filters understood by event_matches_filters,
+	// nil exports every event.
+	Filters []byte
+	Columns []string
+	Format  Format
+}
+
+// batchSize is how many events are fetched from the cursor at a time.
+const batchSize = 1000
+
+// Events writes the events of the request to w, oldest first, and returns how
+// many were written.
+func Events(ctx context.Context, db DB, request Request, w io.Writer) (int64, error) {
+	exprs := make([]string, len(request.Columns))
+	for i, name := range request.Columns {
+		index := slices.IndexFunc(columns, func(c column) bool { return c.name == name })
+		if index < 0 {
+			return 0, fmt.Errorf("unknown column %q", name)
+		}
+		exprs[i] = columns[index].expr
+	}
+
+	writer, err := newRowWriter(request.Format, request.Columns, w)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// columns are picked from the list above, so they are safe to interpolate
+	_, err = tx.Exec(ctx, `DECLARE export_events NO SCROLL CURSOR FOR
+		SELECT `+strings.Join(exprs, ", ")+` FROM events e
+		WHERE tracking_id = $1
+			AND ($2::timestamptz IS NULL OR timestamp >= $2)
+			AND ($3::timestamptz IS NULL OR timestamp < $3)
+			AND event_matches_filters(e, $4::jsonb)
+		ORDER BY timestamp`,
+		request.TrackingID, request.StartDate, request.EndDate, request.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var written int64
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM export_events", batchSize))
+		if err != nil {
+			return written, err
+		}
+
+		fetched := 0
+		for rows.Next() {
+			values, err := rows.Values()
+			if err != nil {
+				rows.Close()
+				return written, err
+			}
+			if err := writer.Write(values); err != nil {
+				rows.Close()
+				return written, err
+			}
+			fetched++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return written, err
+		}
+
+		written += int64(fetched)
+		if err := writer.Flush(); err != nil {
+			return written, err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if fetched < batchSize {
+			break
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return written, err
+	}
+	return written, tx.Commit(ctx)
+}
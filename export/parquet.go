@@ -0,0 +1,340 @@
+package export
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// Parquet files are written with just what exports need: a flat schema of
+// optional columns, one uncompressed PLAIN data page per column and row group,
+// and definition levels bit-packed. The file metadata and page headers are
+// Thrift structs in the compact protocol.
+//
+// https://parquet.apache.org/docs/file-format/
+
+// parquetRowGroupSize bounds how many bytes of values are buffered before
+// they are written out as a row group.
+const parquetRowGroupSize = 16 * 1024 * 1024
+
+var parquetMagic = []byte("PAR1")
+
+// Parquet enum values used in the metadata.
+const (
+	parquetInt64           = 2
+	parquetByteArray       = 6
+	parquetOptional        = 1
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetPlain           = 0
+	parquetRLE             = 3
+	parquetUncompressed    = 0
+	parquetDataPage        = 0
+)
+
+type parquetColumn struct {
+	name string
+	// int64 columns hold timestamps, every other column is a UTF-8 byte array
+	int64   bool
+	defined []bool
+	values  []byte
+}
+
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	size   int64
+	chunks []parquetChunk
+}
+
+// parquetWriter writes timestamps as microseconds since the epoch and every
+// other column as an optional UTF-8 string.
+type parquetWriter struct {
+	writer    io.Writer
+	offset    int64
+	columns   []*parquetColumn
+	rows      int64
+	buffered  int
+	rowGroups []parquetRowGroup
+}
+
+func newParquetWriter(columns []string, w io.Writer) (*parquetWriter, error) {
+	writer := &parquetWriter{writer: w}
+	for _, name := range columns {
+		writer.columns = append(writer.columns, &parquetColumn{name: name, int64: name == "timestamp"})
+	}
+	return writer, writer.write(parquetMagic)
+}
+
+func (w *parquetWriter) write(data []byte) error {
+	n, err := w.writer.Write(data)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *parquetWriter) Write(values []any) error {
+	for i, value := range values {
+		column := w.columns[i]
+		if value == nil {
+			column.defined = append(column.defined, false)
+			continue
+		}
+
+		column.defined = append(column.defined, true)
+		size := len(column.values)
+		if timestamp, ok := value.(time.Time); ok && column.int64 {
+			column.values = binary.LittleEndian.AppendUint64(column.values, uint64(timestamp.UnixMicro()))
+		} else {
+			text := formatValue(value)
+			column.values = binary.LittleEndian.AppendUint32(column.values, uint32(len(text)))
+			column.values = append(column.values, text...)
+		}
+		w.buffered += len(column.values) - size
+	}
+
+	w.rows++
+	if w.buffered >= parquetRowGroupSize {
+		return w.writeRowGroup()
+	}
+	return nil
+}
+
+// writeRowGroup writes the buffered rows as a row group, with a page per column.
+func (w *parquetWriter) writeRowGroup() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	rowGroup := parquetRowGroup{rows: w.rows}
+	for _, column := range w.columns {
+		levels := encodeDefinitionLevels(column.defined)
+		page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+		page = append(page, levels...)
+		page = append(page, column.values...)
+
+		var header compactWriter
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.structBegin(5)
+		header.i32(1, int32(w.rows))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.structEnd()
+		header.stop()
+
+		chunk := parquetChunk{offset: w.offset, size: int64(len(header.buf) + len(page))}
+		if err := w.write(header.buf); err != nil {
+			return err
+		}
+		if err := w.write(page); err != nil {
+			return err
+		}
+		rowGroup.chunks = append(rowGroup.chunks, chunk)
+		rowGroup.size += chunk.size
+
+		column.defined, column.values = column.defined[:0], column.values[:0]
+	}
+
+	w.rowGroups = append(w.rowGroups, rowGroup)
+	w.rows, w.buffered = 0, 0
+	return nil
+}
+
+// Flush does nothing, row groups are written as they fill up.
+func (w *parquetWriter) Flush() error {
+	return nil
+}
+
+// Close writes the last row group and the file metadata.
+func (w *parquetWriter) Close() error {
+	if err := w.writeRowGroup(); err != nil {
+		return err
+	}
+
+	var totalRows int64
+	for _, rowGroup := range w.rowGroups {
+		totalRows += rowGroup.rows
+	}
+
+	var footer compactWriter
+	footer.i32(1, 1)
+	footer.listBegin(2, compactStruct, len(w.columns)+1)
+	footer.elementBegin()
+	footer.binary(4, "schema")
+	footer.i32(5, int32(len(w.columns)))
+	footer.structEnd()
+	for _, column := range w.columns {
+		footer.elementBegin()
+		footer.i32(1, column.physicalType())
+		footer.i32(3, parquetOptional)
+		footer.binary(4, column.name)
+		footer.i32(6, column.convertedType())
+		footer.structEnd()
+	}
+	footer.i64(3, totalRows)
+	footer.listBegin(4, compactStruct, len(w.rowGroups))
+	for _, rowGroup := range w.rowGroups {
+		footer.elementBegin()
+		footer.listBegin(1, compactStruct, len(rowGroup.chunks))
+		for i, chunk := range rowGroup.chunks {
+			column := w.columns[i]
+			footer.elementBegin()
+			footer.i64(2, chunk.offset)
+			footer.structBegin(3)
+			footer.i32(1, column.physicalType())
+			footer.listBegin(2, compactI32, 2)
+			footer.listI32(parquetPlain)
+			footer.listI32(parquetRLE)
+			footer.listBegin(3, compactBinary, 1)
+			footer.listBinary(column.name)
+			footer.i32(4, parquetUncompressed)
+			footer.i64(5, rowGroup.rows)
+			footer.i64(6, chunk.size)
+			footer.i64(7, chunk.size)
+			footer.i64(9, chunk.offset)
+			footer.structEnd()
+			footer.structEnd()
+		}
+		footer.i64(2, rowGroup.size)
+		footer.i64(3, rowGroup.rows)
+		footer.structEnd()
+	}
+	footer.binary(6, "minalytics")
+	footer.stop()
+
+	if err := w.write(footer.buf); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer.buf)))); err != nil {
+		return err
+	}
+	return w.write(parquetMagic)
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	if c.int64 {
+		return parquetInt64
+	}
+	return parquetByteArray
+}
+
+func (c *parquetColumn) convertedType() int32 {
+	if c.int64 {
+		return parquetTimestampMicros
+	}
+	return parquetUTF8
+}
+
+// maxBitPackedGroups is how many groups of 8 levels go in one bit-packed run,
+// the most many readers accept.
+const maxBitPackedGroups = 63
+
+// encodeDefinitionLevels encodes levels of bit width 1 in the RLE/bit-packing
+// hybrid, as bit-packed runs. The last group is padded, readers stop after the
+// page's number of values.
+func encodeDefinitionLevels(defined []bool) []byte {
+	var encoded []byte
+	groups := (len(defined) + 7) / 8
+	for start := 0; start < groups; start += maxBitPackedGroups {
+		run := min(maxBitPackedGroups, groups-start)
+		encoded = binary.AppendUvarint(encoded, uint64(run)<<1|1)
+		for group := start; group < start+run; group++ {
+			var packed byte
+			for bit := range 8 {
+				if i := group*8 + bit; i < len(defined) && defined[i] {
+					packed |= 1 << bit
+				}
+			}
+			encoded = append(encoded, packed)
+		}
+	}
+	return encoded
+}
+
+// Thrift compact protocol types.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes Thrift structs in the compact protocol. Fields have to
+// be written in increasing id order.
+type compactWriter struct {
+	buf     []byte
+	lastID  int16
+	parents []int16
+}
+
+func (w *compactWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|fieldType)
+	} else {
+		w.buf = append(w.buf, fieldType)
+		w.buf = binary.AppendVarint(w.buf, int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) i32(id int16, value int32) {
+	w.fieldHeader(id, compactI32)
+	w.buf = binary.AppendVarint(w.buf, int64(value))
+}
+
+func (w *compactWriter) i64(id int16, value int64) {
+	w.fieldHeader(id, compactI64)
+	w.buf = binary.AppendVarint(w.buf, value)
+}
+
+func (w *compactWriter) binary(id int16, value string) {
+	w.fieldHeader(id, compactBinary)
+	w.listBinary(value)
+}
+
+func (w *compactWriter) structBegin(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.elementBegin()
+}
+
+// elementBegin starts a struct that is an element of a list.
+func (w *compactWriter) elementBegin() {
+	w.parents = append(w.parents, w.lastID)
+	w.lastID = 0
+}
+
+func (w *compactWriter) structEnd() {
+	w.stop()
+	w.lastID = w.parents[len(w.parents)-1]
+	w.parents = w.parents[:len(w.parents)-1]
+}
+
+func (w *compactWriter) stop() {
+	w.buf = append(w.buf, 0)
+}
+
+func (w *compactWriter) listBegin(id int16, elementType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elementType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elementType)
+		w.buf = binary.AppendUvarint(w.buf, uint64(size))
+	}
+}
+
+func (w *compactWriter) listI32(value int32) {
+	w.buf = binary.AppendVarint(w.buf, int64(value))
+}
+
+func (w *compactWriter) listBinary(value string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(value)))
+	w.buf = append(w.buf, value...)
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// rowWriter encodes rows of column values. Values are strings, times or nil.
+type rowWriter interface {
+	Write(values []any) error
+	// Flush hands the rows written so far to the underlying writer, where the
+	// format allows it.
+	Flush() error
+	// Close writes whatever the format needs after the last row.
+	Close() error
+}
+
+func newRowWriter(format Format, columns []string, w io.Writer) (rowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(columns, w)
+	case FormatNDJSON:
+		return newNDJSONWriter(columns, w), nil
+	case FormatParquet:
+		return newParquetWriter(columns, w)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// formatValue renders a value as text, times in UTC RFC 3339.
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(columns []string, w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (w *csvWriter) Write(values []any) error {
+	for i, value := range values {
+		w.record[i] = formatValue(value)
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+// ndjsonWriter writes a JSON object per row, with the keys in column order.
+// details is embedded as the JSON object it is stored as.
+type ndjsonWriter struct {
+	writer *bufio.Writer
+	keys   [][]byte
+	raw    []bool
+}
+
+func newNDJSONWriter(columns []string, w io.Writer) *ndjsonWriter {
+	keys := make([][]byte, len(columns))
+	raw := make([]bool, len(columns))
+	for i, column := range columns {
+		keys[i], _ = json.Marshal(column)
+		raw[i] = column == "details"
+	}
+	return &ndjsonWriter{writer: bufio.NewWriter(w), keys: keys, raw: raw}
+}
+
+func (w *ndjsonWriter) Write(values []any) error {
+	w.writer.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			w.writer.WriteByte(',')
+		}
+		w.writer.Write(w.keys[i])
+		w.writer.WriteByte(':')
+
+		var encoded []byte
+		switch {
+		case value == nil:
+			encoded = []byte("null")
+		case w.raw[i]:
+			encoded = []byte(formatValue(value))
+		default:
+			var err error
+			if encoded, err = json.Marshal(formatValue(value)); err != nil {
+				return err
+			}
+		}
+		w.writer.Write(encoded)
+	}
+	w.writer.WriteByte('}')
+	_, err := w.writer.Write([]byte("\n"))
+	return err
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,300 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	goparquet "github.com/xitongsys/parquet-go/reader"
+)
+
+type WriterSuite struct {
+	suite.Suite
+	timestamp time.Time
+}
+
+func (suite *WriterSuite) SetupSuite() {
+	suite.timestamp = time.Date(2025, 3, 31, 12, 30, 15, 123456000, time.FixedZone("WAT", 3600))
+}
+
+func (suite *WriterSuite) rows() [][]any {
+	return [][]any{
+		{suite.timestamp, "https://example.com/pricing", nil, `{"plan": "pro"}`},
+		{suite.timestamp.Add(time.Second), "https://example.com/a,\"b\"", "https://google.com", `{}`},
+	}
+}
+
+func (suite *WriterSuite) write(format Format, columns []string, rows [][]any) []byte {
+	var buf bytes.Buffer
+	writer, err := newRowWriter(format, columns, &buf)
+	suite.Require().NoError(err)
+	for _, row := range rows {
+		suite.Require().NoError(writer.Write(row))
+	}
+	suite.Require().NoError(writer.Close())
+	return buf.Bytes()
+}
+
+func (suite *WriterSuite) TestCSV() {
+	columns := []string{"timestamp", "url", "referrer", "details"}
+	records, err := csv.NewReader(bytes.NewReader(suite.write(FormatCSV, columns, suite.rows()))).ReadAll()
+	suite.NoError(err)
+	suite.Equal([][]string{
+		columns,
+		{"2025-03-31T11:30:15.123456Z", "https://example.com/pricing", "", `{"plan": "pro"}`},
+		{"2025-03-31T11:30:16.123456Z", "https://example.com/a,\"b\"", "https://google.com", `{}`},
+	}, records)
+}
+
+func (suite *WriterSuite) TestNDJSON() {
+	columns := []string{"timestamp", "url", "referrer", "details"}
+	lines := bytes.Split(bytes.TrimSuffix(suite.write(FormatNDJSON, columns, suite.rows()), []byte("\n")), []byte("\n"))
+	suite.Len(lines, 2)
+	suite.Equal(`{"timestamp":"2025-03-31T11:30:15.123456Z","url":"https://example.com/pricing","referrer":null,"details":{"plan": "pro"}}`, string(lines[0]))
+
+	var row map[string]any
+	suite.NoError(json.Unmarshal(lines[1], &row))
+	suite.Equal(map[string]any{
+		"timestamp": "2025-03-31T11:30:16.123456Z",
+		"url":       "https://example.com/a,\"b\"",
+		"referrer":  "https://google.com",
+		"details":   map[string]any{},
+	}, row)
+}
+
+func (suite *WriterSuite) TestEmptyExports() {
+	suite.Equal("url\n", string(suite.write(FormatCSV, []string{"url"}, nil)))
+	suite.Empty(suite.write(FormatNDJSON, []string{"url"}, nil))
+
+	file := readParquet(suite.T(), suite.write(FormatParquet, []string{"url"}, nil))
+	suite.Equal(int64(0), file.rows)
+	suite.Empty(file.rowGroups)
+}
+
+func (suite *WriterSuite) TestParquet() {
+	columns := []string{"timestamp", "url", "referrer", "details"}
+	file := readParquet(suite.T(), suite.write(FormatParquet, columns, suite.rows()))
+
+	suite.Equal(columns, file.columns)
+	suite.Equal(int64(2), file.rows)
+	suite.Len(file.rowGroups, 1)
+	suite.Equal([][]any{
+		{suite.timestamp.UnixMicro(), suite.timestamp.Add(time.Second).UnixMicro()},
+		{"https://example.com/pricing", "https://example.com/a,\"b\""},
+		{nil, "https://google.com"},
+		{`{"plan": "pro"}`, `{}`},
+	}, file.rowGroups[0])
+}
+
+func (suite *WriterSuite) TestParquetRowGroups() {
+	// rows of 1 MiB fill a row group every 16 rows, across several bit-packed runs
+	large := string(bytes.Repeat([]byte("x"), 1024*1024))
+	var rows [][]any
+	for i := range 600 {
+		value := any(nil)
+		if i%3 == 0 {
+			value = "small"
+		}
+		if i < 20 {
+			value = large
+		}
+		rows = append(rows, []any{value})
+	}
+
+	file := readParquet(suite.T(), suite.write(FormatParquet, []string{"url"}, rows))
+	suite.Equal(int64(600), file.rows)
+	suite.Len(file.rowGroups, 2)
+
+	var values []any
+	for _, rowGroup := range file.rowGroups {
+		values = append(values, rowGroup[0]...)
+	}
+	for i, row := range rows {
+		suite.Equal(row[0], values[i], "row %d", i)
+	}
+}
+
+func (suite *WriterSuite) TestParquetReadByParquetGo() {
+	// readParquet shares the writer's reading of the format, so the file is
+	// also checked against an independent implementation
+	columns := []string{"timestamp", "url", "referrer", "details"}
+	file, err := buffer.NewBufferFile(suite.write(FormatParquet, columns, suite.rows()))
+	suite.Require().NoError(err)
+	reader, err := goparquet.NewParquetColumnReader(file, 1)
+	suite.Require().NoError(err)
+	defer reader.ReadStop()
+
+	var names []string
+	for i := 1; i < len(reader.SchemaHandler.Infos); i++ {
+		names = append(names, reader.SchemaHandler.GetExName(i))
+	}
+	suite.Equal(columns, names)
+	suite.Equal(int64(2), reader.GetNumRows())
+
+	var values [][]any
+	for i := range columns {
+		column, _, _, err := reader.ReadColumnByIndex(int64(i), reader.GetNumRows())
+		suite.Require().NoError(err)
+		values = append(values, column)
+	}
+	suite.Equal([][]any{
+		{suite.timestamp.UnixMicro(), suite.timestamp.Add(time.Second).UnixMicro()},
+		{"https://example.com/pricing", "https://example.com/a,\"b\""},
+		{nil, "https://google.com"},
+		{`{"plan": "pro"}`, `{}`},
+	}, values)
+}
+
+func TestWriterSuite(t *testing.T) {
+	suite.Run(t, new(WriterSuite))
+}
+
+// parquetFile is what readParquet decodes: the column names, the number of
+// rows, and the values of each column of each row group.
+type parquetFile struct {
+	columns   []string
+	rows      int64
+	rowGroups [][][]any
+}
+
+// readParquet decodes the subset of Parquet the export writes.
+func readParquet(t *testing.T, data []byte) parquetFile {
+	t.Helper()
+	if !bytes.HasPrefix(data, parquetMagic) || !bytes.HasSuffix(data, parquetMagic) {
+		t.Fatalf("missing magic")
+	}
+	footerSize := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{buf: data[len(data)-8-footerSize : len(data)-8]}
+	metadata := footer.readStruct()
+
+	var file parquetFile
+	schema := metadata[2].([]any)
+	for _, element := range schema[1:] {
+		file.columns = append(file.columns, string(element.(map[int16]any)[4].([]byte)))
+	}
+	file.rows = metadata[3].(int64)
+
+	for _, rowGroup := range metadata[4].([]any) {
+		var columns [][]any
+		for _, chunk := range rowGroup.(map[int16]any)[1].([]any) {
+			columnMetadata := chunk.(map[int16]any)[3].(map[int16]any)
+			offset := columnMetadata[9].(int64)
+
+			page := &compactReader{buf: data[offset:]}
+			header := page.readStruct()
+			numValues := int(header[5].(map[int16]any)[1].(int64))
+			body := page.buf[page.pos : page.pos+int(header[2].(int64))]
+
+			levelsSize := int(binary.LittleEndian.Uint32(body))
+			defined := decodeBitPacked(t, body[4:4+levelsSize], numValues)
+			values := body[4+levelsSize:]
+
+			column := make([]any, numValues)
+			for i := range numValues {
+				if !defined[i] {
+					continue
+				}
+				if columnMetadata[1].(int64) == parquetInt64 {
+					column[i] = int64(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				} else {
+					size := binary.LittleEndian.Uint32(values)
+					column[i] = string(values[4 : 4+size])
+					values = values[4+size:]
+				}
+			}
+			columns = append(columns, column)
+		}
+		file.rowGroups = append(file.rowGroups, columns)
+	}
+	return file
+}
+
+func decodeBitPacked(t *testing.T, data []byte, count int) []bool {
+	var defined []bool
+	for len(data) > 0 {
+		header, n := binary.Uvarint(data)
+		if header&1 != 1 {
+			t.Fatalf("expect bit-packed runs only")
+		}
+		groups := int(header >> 1)
+		for _, packed := range data[n : n+groups] {
+			for bit := range 8 {
+				defined = append(defined, packed&(1<<bit) != 0)
+			}
+		}
+		data = data[n+groups:]
+	}
+	return defined[:count]
+}
+
+// compactReader decodes Thrift compact structs into maps of field ids to
+// values: int64 for integers, []byte for binaries, []any for lists and nested
+// maps for structs.
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) varint() int64 {
+	value, n := binary.Varint(r.buf[r.pos:])
+	r.pos += n
+	return value
+}
+
+func (r *compactReader) uvarint() uint64 {
+	value, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return value
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	fields := map[int16]any{}
+	var lastID int16
+	for {
+		header := r.buf[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.readValue(header & 0x0f)
+		lastID = id
+	}
+}
+
+func (r *compactReader) readValue(valueType byte) any {
+	switch valueType {
+	case compactI32, compactI64:
+		return r.varint()
+	case compactBinary:
+		size := int(r.uvarint())
+		r.pos += size
+		return r.buf[r.pos-size : r.pos]
+	case compactList:
+		header := r.buf[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case compactStruct:
+		return r.readStruct()
+	}
+	panic(fmt.Sprintf("unexpected compact type %d", valueType))
+}
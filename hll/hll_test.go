@@ -0,0 +1,99 @@
+package hll
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SketchSuite struct {
+	suite.Suite
+}
+
+func sketchOf(prefix string, n int) *Sketch {
+	s := New()
+	for i := range n {
+		s.Insert(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return s
+}
+
+func (suite *SketchSuite) TestEmpty() {
+	suite.Equal(uint64(0), New().Estimate())
+}
+
+func (suite *SketchSuite) TestSmallSetsAreNearlyExact() {
+	for _, n := range []int{1, 10, 100, 1000} {
+		suite.InDelta(n, sketchOf("visitor", n).Estimate(), math.Max(1, 0.01*float64(n)), "n=%d", n)
+	}
+}
+
+func (suite *SketchSuite) TestErrorBound() {
+	for _, n := range []int{50000, 250000} {
+		estimate := float64(sketchOf("visitor", n).Estimate())
+		// three standard errors
+		suite.InEpsilon(n, estimate, 3*1.04/math.Sqrt(registers), "n=%d", n)
+	}
+}
+
+func (suite *SketchSuite) TestDuplicatesAreCountedOnce() {
+	s := sketchOf("visitor", 500)
+	before := s.Estimate()
+	for range 3 {
+		s.Merge(sketchOf("visitor", 500))
+		s.Insert("visitor-0")
+	}
+	suite.Equal(before, s.Estimate())
+}
+
+func (suite *SketchSuite) TestMergeEstimatesUnion() {
+	union := New()
+	for _, s := range []*Sketch{sketchOf("a", 20000), sketchOf("b", 20000), sketchOf("a", 10000)} {
+		union.Merge(s)
+	}
+	suite.InEpsilon(40000, float64(union.Estimate()), 0.03)
+
+	// merging a small sketch into a dense one and the other way round agree
+	dense, sparse := sketchOf("a", 20000), sketchOf("b", 10)
+	suite.NotNil(dense.dense)
+	suite.Nil(sparse.dense)
+	left, right := New(), New()
+	left.Merge(dense)
+	left.Merge(sparse)
+	right.Merge(sparse)
+	right.Merge(dense)
+	suite.Equal(left.Estimate(), right.Estimate())
+}
+
+func (suite *SketchSuite) TestRoundTrip() {
+	for _, n := range []int{0, 3, 20000} {
+		s := sketchOf("visitor", n)
+		data, err := s.MarshalBinary()
+		suite.NoError(err)
+
+		parsed, err := Parse(data)
+		suite.NoError(err)
+		suite.Equal(s.Estimate(), parsed.Estimate(), "n=%d", n)
+	}
+
+	data, err := sketchOf("visitor", 3).MarshalBinary()
+	suite.NoError(err)
+	suite.Len(data, 2+3*sparseEntrySize)
+
+	data, err = sketchOf("visitor", 20000).MarshalBinary()
+	suite.NoError(err)
+	suite.Len(data, 2+registers)
+}
+
+func (suite *SketchSuite) TestParseInvalid() {
+	for _, data := range [][]byte{nil, {formatDense, Precision, 1}, {formatSparse, Precision, 0}, {formatSparse, 12}, {9, Precision}} {
+		_, err := Parse(data)
+		suite.ErrorIs(err, ErrInvalidSketch)
+	}
+}
+
+func TestSketchSuite(t *testing.T) {
+	suite.Run(t, new(SketchSuite))
+}
@@ -0,0 +1,482 @@
+package importer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mileusna/useragent"
+
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// Resolver derives the country of an IP address and the browser, device and
+// operating system of a user agent, the way the tracker does.
+type Resolver interface {
+	ResolveGeoLocation(string) (*types.GeoLocation, error)
+	ParseUserAgent(string) *types.UserAgentDetails
+}
+
+// namedLogFormats are the predefined formats of nginx and Apache, which write
+// the same lines.
+var namedLogFormats = map[string]string{
+	"combined": `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"`,
+	"common":   `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent`,
+}
+
+// Fields of a log line the importer reads.
+const (
+	fieldIP           = "ip"
+	fieldForwardedFor = "forwarded_for"
+	fieldTime         = "time"
+	fieldTimeISO      = "time_iso"
+	fieldRequest      = "request"
+	fieldMethod       = "method"
+	fieldURI          = "uri"
+	fieldPath         = "path"
+	fieldQuery        = "query"
+	fieldHost         = "host"
+	fieldStatus       = "status"
+	fieldReferrer     = "referrer"
+	fieldUserAgent    = "user_agent"
+)
+
+// nginxVariables maps the nginx log_format variables the importer reads to
+// their fields. Other variables are matched and ignored.
+var nginxVariables = map[string]string{
+	"remote_addr":          fieldIP,
+	"http_x_forwarded_for": fieldForwardedFor,
+	"time_local":           fieldTime,
+	"time_iso8601":         fieldTimeISO,
+	"request":              fieldRequest,
+	"request_method":       fieldMethod,
+	"request_uri":          fieldURI,
+	"uri":                  fieldPath,
+	"document_uri":         fieldPath,
+	"args":                 fieldQuery,
+	"query_string":         fieldQuery,
+	"host":                 fieldHost,
+	"http_host":            fieldHost,
+	"server_name":          fieldHost,
+	"status":               fieldStatus,
+	"http_referer":         fieldReferrer,
+	"http_user_agent":      fieldUserAgent,
+}
+
+// apacheDirectives maps the Apache LogFormat directives the importer reads to
+// their fields, and headers by their lower cased names.
+var apacheDirectives = map[string]string{
+	"h": fieldIP,
+	"a": fieldIP,
+	"t": fieldTime,
+	"r": fieldRequest,
+	"m": fieldMethod,
+	"U": fieldPath,
+	"q": fieldQuery,
+	"v": fieldHost,
+	"V": fieldHost,
+	"s": fieldStatus,
+}
+
+var apacheHeaders = map[string]string{
+	"x-forwarded-for": fieldForwardedFor,
+	"host":            fieldHost,
+	"referer":         fieldReferrer,
+	"user-agent":      fieldUserAgent,
+}
+
+var (
+	nginxVariable   = regexp.MustCompile(`\$(?:\{(\w+)\}|(\w+))`)
+	apacheDirective = regexp.MustCompile(`%[<>]?(?:\{([^}]*)\})?([a-zA-Z%])`)
+)
+
+// staticExtensions are the extensions of requests for assets rather than pages.
+var staticExtensions = map[string]bool{
+	".css": true, ".js": true, ".mjs": true, ".map": true, ".json": true, ".xml": true, ".txt": true, ".webmanifest": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true, ".avif": true, ".bmp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp4": true, ".webm": true, ".mp3": true, ".wav": true, ".ogg": true, ".pdf": true, ".zip": true, ".gz": true,
+}
+
+// botKeywords flag the user agents of crawlers, monitors and scripts the user
+// agent parser doesn't know as bots.
+var botKeywords = []string{"bot", "crawl", "spider", "slurp", "curl", "wget", "python", "go-http-client", "java/", "libwww", "httpclient", "headless", "lighthouse", "pingdom", "uptime"}
+
+// LogFormat is a compiled access log format.
+type LogFormat struct {
+	pattern *regexp.Regexp
+	// fields names the field of each group of pattern, "" for ignored ones.
+	fields []string
+	// userAgents is whether lines hold user agents, without them bots can't
+	// be told apart.
+	userAgents bool
+}
+
+// ParseLogFormat compiles an access log format: combined (the default) or
+// common, or a custom format in nginx log_format or Apache LogFormat syntax.
+func ParseLogFormat(format string) (*LogFormat, error) {
+	if format == "" {
+		format = "combined"
+	}
+	if named, exists := namedLogFormats[format]; exists {
+		format = named
+	}
+
+	var tokens [][]int
+	var field func(match []string) (string, error)
+	apache := false
+	switch {
+	case strings.Contains(format, "$"):
+		tokens = nginxVariable.FindAllStringSubmatchIndex(format, -1)
+		field = func(match []string) (string, error) {
+			return nginxVariables[match[1]+match[2]], nil
+		}
+	case strings.Contains(format, "%"):
+		apache = true
+		tokens = apacheDirective.FindAllStringSubmatchIndex(format, -1)
+		field = func(match []string) (string, error) {
+			switch match[2] {
+			case "i":
+				return apacheHeaders[strings.ToLower(match[1])], nil
+			case "t":
+				if match[1] != "" {
+					return "", fmt.Errorf("unsupported time format %%{%s}t, use %%t", match[1])
+				}
+			}
+			return apacheDirectives[match[2]], nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, expect combined, common, or an nginx or Apache format", format)
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	parsed := &LogFormat{}
+	seen := make(map[string]bool)
+	end := 0
+	for i, token := range tokens {
+		pattern.WriteString(regexp.QuoteMeta(format[end:token[0]]))
+		end = token[1]
+
+		match := make([]string, len(token)/2)
+		for j := range match {
+			if token[2*j] >= 0 {
+				match[j] = format[token[2*j]:token[2*j+1]]
+			}
+		}
+		if match[0] == "%%" {
+			pattern.WriteString("%")
+			continue
+		}
+		name, err := field(match)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			name = ""
+		}
+		seen[name] = name != ""
+
+		// values run up to the text that follows them, quoted ones can hold
+		// escaped quotes
+		next := ""
+		if i+1 < len(tokens) {
+			next = format[end:tokens[i+1][0]]
+		} else {
+			next = format[end:]
+		}
+		switch {
+		case apache && match[2] == "t":
+			pattern.WriteString(`\[([^\]]*)\]`)
+		case next == "":
+			pattern.WriteString(`(.*)`)
+		case next[0] == '"':
+			pattern.WriteString(`((?:[^"\\]|\\.)*)`)
+		default:
+			pattern.WriteString(`([^` + regexp.QuoteMeta(next[:1]) + `]*)`)
+		}
+		parsed.fields = append(parsed.fields, name)
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[end:]))
+
+	if !seen[fieldTime] && !seen[fieldTimeISO] {
+		return nil, errors.New("the log format has no time, like $time_local or %t")
+	}
+	if !seen[fieldRequest] && !seen[fieldURI] && !seen[fieldPath] {
+		return nil, errors.New("the log format has no request, like $request or %r")
+	}
+	if !seen[fieldIP] && !seen[fieldForwardedFor] {
+		return nil, errors.New("the log format has no client address, like $remote_addr or %h")
+	}
+
+	parsed.userAgents = seen[fieldUserAgent]
+
+	var err error
+	if parsed.pattern, err = regexp.Compile(pattern.String()); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// parse returns the fields of a line, or false when it doesn't match.
+func (f *LogFormat) parse(line string) (map[string]string, bool) {
+	match := f.pattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(f.fields))
+	for i, name := range f.fields {
+		value := match[i+1]
+		if name == "" || value == "-" {
+			continue
+		}
+		values[name] = strings.ReplaceAll(value, `\"`, `"`)
+	}
+	return values, true
+}
+
+// accessLogReader reads the pageviews of an access log.
+type accessLogReader struct {
+	format  *LogFormat
+	opts    Options
+	batcher *batcher
+	input   *countingReader
+	size    int64
+	matched int64
+	first   string
+	country map[string]string
+	agents  map[string]*types.UserAgentDetails
+}
+
+// resolverCacheSize bounds the countries and user agents kept resolved.
+const resolverCacheSize = 50000
+
+func readAccessLog(r io.ReaderAt, size int64, opts Options, b *batcher) error {
+	format, err := ParseLogFormat(opts.LogFormat)
+	if err != nil {
+		return err
+	}
+	if opts.Resolver == nil {
+		return errors.New("access logs need a resolver for countries and user agents")
+	}
+
+	a := &accessLogReader{
+		format:  format,
+		opts:    opts,
+		batcher: b,
+		input:   &countingReader{reader: io.NewSectionReader(r, 0, size)},
+		size:    size,
+		country: make(map[string]string),
+		agents:  make(map[string]*types.UserAgentDetails),
+	}
+
+	var input io.Reader = a.input
+	if isGzip(r) {
+		if input, err = gzip.NewReader(a.input); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if a.first == "" {
+			a.first = text
+		}
+		if err := a.addLine(text); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// a few malformed lines are skipped, a format that matches none is wrong
+	if a.first == "" {
+		return errors.New("found no log lines")
+	}
+	if a.matched == 0 {
+		return fmt.Errorf("no line matches the log format, lines look like %q", truncate(a.first, 200))
+	}
+	return nil
+}
+
+func (a *accessLogReader) addLine(line string) error {
+	values, ok := a.format.parse(line)
+	if !ok {
+		return nil
+	}
+	a.matched++
+
+	method, uri := values[fieldMethod], values[fieldURI]
+	if request := strings.Fields(values[fieldRequest]); len(request) >= 2 {
+		method, uri = request[0], request[1]
+	}
+	if uri == "" && values[fieldPath] != "" {
+		uri = values[fieldPath]
+		if query := strings.TrimPrefix(values[fieldQuery], "?"); query != "" {
+			uri += "?" + query
+		}
+	}
+	if uri == "" || (method != "" && method != "GET") {
+		return nil
+	}
+
+	// only pages that were served count, redirects and errors don't
+	if status, err := strconv.Atoi(values[fieldStatus]); err == nil && (status < 200 || status >= 300) && status != 304 {
+		return nil
+	}
+	if staticExtensions[strings.ToLower(path.Ext(strings.SplitN(uri, "?", 2)[0]))] {
+		return nil
+	}
+	userAgent := values[fieldUserAgent]
+	if a.format.userAgents && isBot(userAgent) {
+		return nil
+	}
+
+	timestamp, err := parseLogTime(values)
+	if err != nil {
+		return err
+	}
+
+	ip := values[fieldIP]
+	if forwarded := strings.TrimSpace(strings.Split(values[fieldForwardedFor], ",")[0]); net.ParseIP(forwarded) != nil {
+		ip = forwarded
+	}
+
+	host := values[fieldHost]
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	page, err := pageURL(uri, host, a.opts.Hostname)
+	if err != nil {
+		return err
+	}
+
+	// like the tracker, visits from the site itself have no referrer
+	referrer := ""
+	if source := values[fieldReferrer]; source != "" && !sameHost(source, page) {
+		referrer = truncate(source, 255)
+	}
+
+	agent := a.userAgent(userAgent)
+	return a.batcher.add(Event{
+		VisitorID: a.visitorID(timestamp, ip, userAgent),
+		Type:      "pageview",
+		URL:       &page,
+		Referrer:  &referrer,
+		Country:   truncate(a.countryOf(ip), 100),
+		Browser:   truncate(agent.Browser, 100),
+		Device:    truncate(agent.Device, 100),
+		OS:        truncate(agent.OperatingSystem, 100),
+		Timestamp: timestamp,
+	}, float64(a.input.read)/float64(max(a.size, 1)))
+}
+
+// visitorID derives the id of a visitor from its address and user agent. It
+// changes daily, like the ids of privacy friendly trackers, and is salted so
+// the ids of different apps can't be linked.
+func (a *accessLogReader) visitorID(timestamp time.Time, ip, userAgent string) string {
+	day := timestamp.In(a.opts.Location).Format(time.DateOnly)
+	hash := sha256.Sum256([]byte(a.opts.VisitorSalt + "|" + day + "|" + ip + "|" + userAgent))
+	return hex.EncodeToString(hash[:])
+}
+
+func (a *accessLogReader) countryOf(ip string) string {
+	if country, exists := a.country[ip]; exists {
+		return country
+	}
+	if len(a.country) >= resolverCacheSize {
+		clear(a.country)
+	}
+
+	country := ""
+	if location, err := a.opts.Resolver.ResolveGeoLocation(ip); err == nil {
+		country = location.Country
+	}
+	a.country[ip] = country
+	return country
+}
+
+func (a *accessLogReader) userAgent(userAgent string) *types.UserAgentDetails {
+	if details, exists := a.agents[userAgent]; exists {
+		return details
+	}
+	if len(a.agents) >= resolverCacheSize {
+		clear(a.agents)
+	}
+
+	details := a.opts.Resolver.ParseUserAgent(userAgent)
+	a.agents[userAgent] = details
+	return details
+}
+
+// isBot reports whether a request came from a crawler, monitor or script,
+// which includes requests without a user agent.
+func isBot(userAgent string) bool {
+	if userAgent == "" || useragent.Parse(userAgent).Bot {
+		return true
+	}
+	lower := strings.ToLower(userAgent)
+	for _, keyword := range botKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseLogTime(values map[string]string) (time.Time, error) {
+	if value, exists := values[fieldTime]; exists {
+		timestamp, err := time.Parse("02/Jan/2006:15:04:05 -0700", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", value)
+		}
+		return timestamp.UTC(), nil
+	}
+
+	value := values[fieldTimeISO]
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return timestamp.UTC(), nil
+}
+
+// sameHost reports whether referrer is a page of the site of page.
+func sameHost(referrer, page string) bool {
+	from, err := url.Parse(referrer)
+	if err != nil || from.Host == "" {
+		return false
+	}
+	to, err := url.Parse(page)
+	return err == nil && strings.EqualFold(from.Hostname(), to.Hostname())
+}
+
+// isGzip reports whether r starts with the signature of a gzip file, like
+// rotated logs are.
+func isGzip(r io.ReaderAt) bool {
+	header := make([]byte, 2)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return false
+	}
+	return header[0] == 0x1f && header[1] == 0x8b
+}
@@ -0,0 +1,418 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dimension is a dimension of aggregated exports.
+type dimension int
+
+const (
+	dimensionPage dimension = iota
+	dimensionReferrer
+	dimensionCountry
+	dimensionBrowser
+	dimensionDevice
+	dimensionOS
+	dimensionCount
+)
+
+// dimensionNames name the dimensions after the events columns holding them.
+var dimensionNames = [dimensionCount]string{
+	dimensionPage:     "url",
+	dimensionReferrer: "referrer",
+	dimensionCountry:  "country",
+	dimensionBrowser:  "browser",
+	dimensionDevice:   "device",
+	dimensionOS:       "operating_system",
+}
+
+// The columns of aggregated exports are recognized by their headers, lower
+// cased and with underscores as spaces. Each field lists the headers it goes
+// by in Google Analytics 4, Universal Analytics and Plausible exports, in
+// order of preference.
+var (
+	dateColumns      = []string{"date", "day index", "day"}
+	hostnameColumns  = []string{"hostname"}
+	visitorsColumns  = []string{"total users", "users", "active users", "visitors"}
+	pageviewsColumns = []string{"views", "pageviews", "screen page views", "page views"}
+	dimensionColumns = [dimensionCount][]string{
+		dimensionPage:     {"page path and screen class", "page path", "page path + query string", "page location", "page"},
+		dimensionReferrer: {"session source", "source", "first user source", "full referrer", "referrer", "page referrer"},
+		dimensionCountry:  {"country"},
+		dimensionBrowser:  {"browser"},
+		dimensionDevice:   {"device category", "device"},
+		dimensionOS:       {"operating system"},
+	}
+)
+
+// dateLayouts are the date formats of the date columns.
+var dateLayouts = []string{"20060102", "2006-01-02", "1/2/06", "1/2/2006", "Jan 2, 2006", "January 2, 2006"}
+
+type counts struct {
+	visitors  int64
+	pageviews int64
+}
+
+func (c *counts) add(other counts) {
+	c.visitors += other.visitors
+	c.pageviews += other.pageviews
+}
+
+// day holds the aggregates of one day: its totals, when the export has them,
+// and the counts of every value of each dimension.
+type day struct {
+	date     time.Time
+	total    counts
+	hasTotal bool
+	values   [dimensionCount]map[string]*counts
+}
+
+// aggregates are the days of an export, keyed by their date at UTC midnight.
+type aggregates struct {
+	opts Options
+	days map[time.Time]*day
+}
+
+func readAggregates(r io.ReaderAt, size int64, opts Options, emit AggregateFunc) error {
+	a := &aggregates{opts: opts, days: make(map[time.Time]*day)}
+	if isZip(r) {
+		archive, err := zip.NewReader(r, size)
+		if err != nil {
+			return err
+		}
+		for _, file := range archive.File {
+			if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".csv") {
+				continue
+			}
+			reader, err := file.Open()
+			if err != nil {
+				return err
+			}
+			err = a.readCSV(reader, file.Name)
+			reader.Close()
+			if err != nil {
+				return err
+			}
+		}
+	} else if err := a.readCSV(io.NewSectionReader(r, 0, size), "export"); err != nil {
+		return err
+	}
+
+	if len(a.days) == 0 {
+		return errors.New("found no daily data, exports need a date column next to visitors or pageviews")
+	}
+	return a.emit(emit)
+}
+
+// csvLayout is the position of every recognized column of a CSV file, -1
+// for the ones it doesn't have.
+type csvLayout struct {
+	date       int
+	hostname   int
+	visitors   int
+	pageviews  int
+	dimensions [dimensionCount]int
+}
+
+func newCSVLayout(header []string) csvLayout {
+	find := func(names []string) int {
+		for _, name := range names {
+			for i, column := range header {
+				if normalizeHeader(column) == name {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	layout := csvLayout{
+		date:      find(dateColumns),
+		hostname:  find(hostnameColumns),
+		visitors:  find(visitorsColumns),
+		pageviews: find(pageviewsColumns),
+	}
+	for dim, names := range dimensionColumns {
+		layout.dimensions[dim] = find(names)
+	}
+	return layout
+}
+
+// hasDimensions reports whether rows are broken down by a dimension, rows of
+// files without one are the totals of their day.
+func (l csvLayout) hasDimensions() bool {
+	for _, column := range l.dimensions {
+		if column >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isTotals reports whether record is a row of daily totals: every column
+// besides the date is a metric. Reports of something else, like the entry
+// pages of a Plausible export, are told apart by their text columns.
+func (l csvLayout) isTotals(record []string) bool {
+	for i, value := range record {
+		if i == l.date {
+			continue
+		}
+		value = strings.NewReplacer(",", "", "%", "", "$", "", ":", "").Replace(strings.TrimSpace(value))
+		if _, err := strconv.ParseFloat(value, 64); value != "" && err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeHeader(header string) string {
+	header = strings.TrimPrefix(header, "\ufeff")
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), "_", " ")
+}
+
+// readCSV adds the rows of a CSV report to the aggregates. Files that aren't
+// daily traffic reports are skipped.
+func (a *aggregates) readCSV(r io.Reader, name string) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	layout := newCSVLayout(header)
+	if layout.date < 0 || (layout.visitors < 0 && layout.pageviews < 0) {
+		return nil
+	}
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if first && !layout.hasDimensions() && !layout.isTotals(record) {
+			return nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err := a.addRow(layout, header, record); err != nil {
+			return fmt.Errorf("%s: line %d: %w", name, line, err)
+		}
+	}
+}
+
+func (a *aggregates) addRow(layout csvLayout, header, record []string) error {
+	field := func(column int) string {
+		if column < 0 || column >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[column])
+	}
+
+	// exports end with a row of totals without a date, and some repeat the header
+	rawDate := field(layout.date)
+	if rawDate == "" || normalizeHeader(rawDate) == normalizeHeader(header[layout.date]) {
+		return nil
+	}
+	date, err := parseDate(rawDate)
+	if err != nil {
+		return err
+	}
+
+	var row counts
+	if row.visitors, err = parseCount(field(layout.visitors)); err != nil {
+		return err
+	}
+	if row.pageviews, err = parseCount(field(layout.pageviews)); err != nil {
+		return err
+	}
+
+	d, exists := a.days[date]
+	if !exists {
+		d = &day{date: date}
+		a.days[date] = d
+	}
+
+	if !layout.hasDimensions() {
+		d.total.add(row)
+		d.hasTotal = true
+		return nil
+	}
+	for dim, column := range layout.dimensions {
+		if column < 0 {
+			continue
+		}
+		value, err := a.dimensionValue(dimension(dim), field(column), field(layout.hostname))
+		if err != nil {
+			return err
+		}
+		if d.values[dim] == nil {
+			d.values[dim] = make(map[string]*counts)
+		}
+		if _, exists := d.values[dim][value]; !exists {
+			d.values[dim][value] = &counts{}
+		}
+		d.values[dim][value].add(row)
+	}
+	return nil
+}
+
+// dimensionValue normalizes a value of an export to how the tracker stores it.
+// Pages become URLs and unknown values are "", which is also a direct visit.
+func (a *aggregates) dimensionValue(dim dimension, value, hostname string) (string, error) {
+	if isNotSet(value) {
+		return "", nil
+	}
+
+	switch dim {
+	case dimensionPage:
+		return pageURL(value, hostname, a.opts.Hostname)
+	case dimensionReferrer:
+		return referrer(value), nil
+	case dimensionCountry:
+		return truncate(countryName(value), 100), nil
+	case dimensionBrowser:
+		return truncate(browserName(value), 100), nil
+	case dimensionOS:
+		return truncate(osName(value), 100), nil
+	}
+	return truncate(value, 100), nil
+}
+
+// pageURL turns a page of an export into a URL, completing paths with the
+// hostname of the row or of the import.
+func pageURL(page, hostname, defaultHostname string) (string, error) {
+	if strings.Contains(page, "://") {
+		return truncate(page, 255), nil
+	}
+	if hostname == "" || isNotSet(hostname) {
+		hostname = defaultHostname
+	}
+	if hostname == "" {
+		return "", fmt.Errorf("page %q has no hostname, set the hostname of the import", page)
+	}
+	if !strings.HasPrefix(page, "/") {
+		page = "/" + page
+	}
+	if !strings.Contains(hostname, "://") {
+		hostname = "https://" + hostname
+	}
+	return truncate(strings.TrimSuffix(hostname, "/")+page, 255), nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func parseCount(value string) (int64, error) {
+	value = strings.ReplaceAll(value, ",", "")
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.ParseFloat(value, 64)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid count %q", value)
+	}
+	return int64(math.Round(count)), nil
+}
+
+// emit passes the aggregates of every day to emit, in order.
+func (a *aggregates) emit(emit AggregateFunc) error {
+	dates := make([]time.Time, 0, len(a.days))
+	for date := range a.days {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	for i, date := range dates {
+		progress := float64(i+1) / float64(len(dates))
+		if err := emit(a.days[date].aggregates(a.opts), progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// totals returns the visitors and pageviews of the day. Without totals in the
+// export, they are the most any dimension adds up to; visitors of pages aren't
+// added up since a visitor views several pages. Every visitor has at least one
+// pageview.
+func (d *day) totals() (int64, int64) {
+	visitors, pageviews := d.total.visitors, d.total.pageviews
+	for dim, values := range d.values {
+		var sum counts
+		var mostVisitors int64
+		for _, value := range values {
+			sum.add(*value)
+			mostVisitors = max(mostVisitors, value.visitors)
+		}
+		if dimension(dim) == dimensionPage {
+			sum.visitors = mostVisitors
+		}
+		if !d.hasTotal || d.total.visitors == 0 {
+			visitors = max(visitors, sum.visitors)
+		}
+		if !d.hasTotal || d.total.pageviews == 0 {
+			pageviews = max(pageviews, sum.pageviews)
+		}
+	}
+
+	if visitors == 0 {
+		visitors = pageviews
+	}
+	return visitors, max(pageviews, visitors)
+}
+
+// aggregates returns the totals of the day and the counts of every value of
+// each dimension, as the export has them. Days without pageviews have none.
+func (d *day) aggregates(opts Options) []Aggregate {
+	visitors, pageviews := d.totals()
+	if pageviews == 0 {
+		return nil
+	}
+
+	date := time.Date(d.date.Year(), d.date.Month(), d.date.Day(), 0, 0, 0, 0, opts.Location)
+	aggregates := []Aggregate{{Date: date, Dimension: DimensionAll, Visitors: visitors, Pageviews: pageviews}}
+	for dim, values := range d.values {
+		names := make([]string, 0, len(values))
+		for value := range values {
+			names = append(names, value)
+		}
+		sort.Strings(names)
+		for _, value := range names {
+			aggregates = append(aggregates, Aggregate{
+				Date:      date,
+				Dimension: dimensionNames[dim],
+				Value:     value,
+				Visitors:  values[value].visitors,
+				Pageviews: values[value].pageviews,
+			})
+		}
+	}
+	return aggregates
+}
@@ -0,0 +1,177 @@
+// Package importer reads the exports of other analytics tools. Umami dumps
+// and access logs hold the events themselves, which Read returns. Google
+// Analytics and Plausible only export daily aggregates, which ReadAggregates
+// returns as they are: the visitors and pageviews of each day and of every
+// value of each dimension. Combinations of dimensions, like the browsers of a
+// country, aren't in the exports, and a visitor of several days counts as a
+// different visitor each day.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+type Source string
+
+const (
+	SourceGA4                Source = "ga4"
+	SourceUniversalAnalytics Source = "universal_analytics"
+	SourcePlausible          Source = "plausible"
+	SourceUmami              Source = "umami"
+	SourceAccessLog          Source = "access_log"
+)
+
+// Aggregated reports whether exports of the source hold daily aggregates, read
+// with ReadAggregates, rather than events.
+func (s Source) Aggregated() bool {
+	return s == SourceGA4 || s == SourceUniversalAnalytics || s == SourcePlausible
+}
+
+// ParseSource validates the name of a source.
+func ParseSource(value string) (Source, error) {
+	switch source := Source(value); source {
+	case SourceGA4, SourceUniversalAnalytics, SourcePlausible, SourceUmami, SourceAccessLog:
+		return source, nil
+	}
+	return "", fmt.Errorf("unsupported import source %q, expect ga4, universal_analytics, plausible, umami or access_log", value)
+}
+
+// Event is an imported event, with the columns the tracker fills in.
+type Event struct {
+	VisitorID string
+	Type      string
+	URL       *string
+	Referrer  *string
+	Country   string
+	Browser   string
+	Device    string
+	OS        string
+	Timestamp time.Time
+}
+
+// Options describe how an export maps onto an app.
+type Options struct {
+	// Location is the timezone of the dates in aggregated exports.
+	Location *time.Location
+	// Hostname completes page paths the export has no hostname for.
+	Hostname string
+	// WebsiteID picks the website of an Umami dump that holds several.
+	WebsiteID string
+	// LogFormat is the format of access log lines, see ParseLogFormat.
+	LogFormat string
+	// Resolver enriches access log lines with countries and user agents.
+	Resolver Resolver
+	// VisitorSalt salts the visitor ids derived from access log lines.
+	VisitorSalt string
+}
+
+// DimensionAll is the dimension of the totals of a day, whose value is "".
+const DimensionAll = "all"
+
+// Aggregate is the visitors and pageviews of a day of an aggregated export,
+// in total or for a value of a dimension. Dimensions are named after the
+// events columns holding them, and unknown values are "".
+type Aggregate struct {
+	// Date is the start of the day in Options.Location.
+	Date      time.Time
+	Dimension string
+	Value     string
+	Visitors  int64
+	Pageviews int64
+}
+
+// AggregateFunc receives the aggregates of an export a day at a time, with
+// the fraction of the export read so far.
+type AggregateFunc func(aggregates []Aggregate, progress float64) error
+
+// BatchSize is how many events are passed to emit at once.
+const BatchSize = 1000
+
+// EmitFunc receives the events of an export a batch at a time, with the
+// fraction of the export read so far.
+type EmitFunc func(events []Event, progress float64) error
+
+// Read reads the events of the export of source in r, a plain PostgreSQL dump
+// for Umami, and a plain or gzipped nginx or Apache log for access logs.
+func Read(source Source, r io.ReaderAt, size int64, opts Options, emit EmitFunc) error {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	batcher := &batcher{emit: emit}
+	var err error
+	switch {
+	case source == SourceUmami:
+		err = readUmami(io.NewSectionReader(r, 0, size), size, opts, batcher)
+	case source == SourceAccessLog:
+		err = readAccessLog(r, size, opts, batcher)
+	case source.Aggregated():
+		err = fmt.Errorf("%s exports hold aggregates, not events", source)
+	default:
+		err = fmt.Errorf("unsupported import source %q", source)
+	}
+	if err != nil {
+		return err
+	}
+	return batcher.flush(1)
+}
+
+// ReadAggregates reads the daily aggregates of the export of an aggregated
+// source in r, a CSV file or a zip of CSV files. Dates are read in
+// opts.Location.
+func ReadAggregates(source Source, r io.ReaderAt, size int64, opts Options, emit AggregateFunc) error {
+	if !source.Aggregated() {
+		return fmt.Errorf("%s exports hold events, not aggregates", source)
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return readAggregates(r, size, opts, emit)
+}
+
+// batcher groups events into batches of BatchSize.
+type batcher struct {
+	emit   EmitFunc
+	events []Event
+}
+
+func (b *batcher) add(event Event, progress float64) error {
+	b.events = append(b.events, event)
+	if len(b.events) < BatchSize {
+		return nil
+	}
+	return b.flush(progress)
+}
+
+func (b *batcher) flush(progress float64) error {
+	if len(b.events) == 0 {
+		return nil
+	}
+	err := b.emit(b.events, progress)
+	b.events = b.events[:0]
+	return err
+}
+
+// isZip reports whether r starts with the signature of a zip file.
+func isZip(r io.ReaderAt) bool {
+	header := make([]byte, 4)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(header, []byte("PK\x03\x04"))
+}
+
+// truncate cuts value to the size of its column, at a rune boundary.
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
@@ -0,0 +1,390 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+type ImporterSuite struct {
+	suite.Suite
+	lagos *time.Location
+}
+
+func (suite *ImporterSuite) SetupSuite() {
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	suite.Require().NoError(err)
+	suite.lagos = lagos
+}
+
+// read imports data and returns every event, checking that progress only
+// goes up and ends at 1.
+func (suite *ImporterSuite) read(source Source, data []byte, opts Options) ([]Event, error) {
+	var events []Event
+	var progress float64
+	err := Read(source, bytes.NewReader(data), int64(len(data)), opts, func(batch []Event, done float64) error {
+		suite.LessOrEqual(len(batch), BatchSize)
+		suite.GreaterOrEqual(done, progress)
+		progress = done
+		events = append(events, batch...)
+		return nil
+	})
+	if err == nil {
+		suite.Equal(1.0, progress)
+	}
+	return events, err
+}
+
+func zipOf(files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, _ := archive.Create(name)
+		file.Write([]byte(content))
+	}
+	archive.Close()
+	return buf.Bytes()
+}
+
+// readAggregates imports an aggregated export and returns every aggregate,
+// checking that progress only goes up and ends at 1.
+func (suite *ImporterSuite) readAggregates(source Source, data []byte, opts Options) ([]Aggregate, error) {
+	var aggregates []Aggregate
+	var progress float64
+	err := ReadAggregates(source, bytes.NewReader(data), int64(len(data)), opts, func(day []Aggregate, done float64) error {
+		suite.GreaterOrEqual(done, progress)
+		progress = done
+		aggregates = append(aggregates, day...)
+		return nil
+	})
+	if err == nil {
+		suite.Equal(1.0, progress)
+	}
+	return aggregates, err
+}
+
+// countsOf returns the visitors and pageviews of every value of dimension on
+// the day starting at date.
+func countsOf(aggregates []Aggregate, dimension string, date time.Time) map[string][2]int64 {
+	values := map[string][2]int64{}
+	for _, aggregate := range aggregates {
+		if aggregate.Dimension == dimension && aggregate.Date.Equal(date) {
+			values[aggregate.Value] = [2]int64{aggregate.Visitors, aggregate.Pageviews}
+		}
+	}
+	return values
+}
+
+func (suite *ImporterSuite) TestGA4() {
+	export := `# ----------------------------------------
+# Pages and screens: Page path and screen class
+# Account: Example
+# Start date: 20250301
+# End date: 20250302
+# ----------------------------------------
+
+Page path and screen class,Date,Views,Total users
+/,20250301,"1,200",500
+/pricing,20250301,300,120
+(not set),20250301,4,3
+/,20250302,10,4
+`
+	aggregates, err := suite.readAggregates(SourceGA4, []byte(export), Options{Location: suite.lagos, Hostname: "example.com"})
+	suite.Require().NoError(err)
+
+	// days start at midnight in the app's timezone, and without totals in the
+	// export a visitor is counted once however many pages they viewed
+	first := time.Date(2025, 3, 1, 0, 0, 0, 0, suite.lagos)
+	suite.Equal(map[string][2]int64{"": {500, 1504}}, countsOf(aggregates, DimensionAll, first))
+	suite.Equal(map[string][2]int64{
+		"https://example.com/":        {500, 1200},
+		"https://example.com/pricing": {120, 300},
+		"":                            {3, 4},
+	}, countsOf(aggregates, "url", first))
+
+	second := first.AddDate(0, 0, 1)
+	suite.Equal(map[string][2]int64{"": {4, 10}}, countsOf(aggregates, DimensionAll, second))
+	suite.Len(aggregates, 6)
+}
+
+func (suite *ImporterSuite) TestUniversalAnalytics() {
+	export := `# ----------------------------------------
+# All Web Site Data
+# Audience Overview
+# 20200101-20200102
+# ----------------------------------------
+
+Day Index,Users,Pageviews
+1/1/20,"1,000","2,500"
+1/2/20,10,10
+
+Day Index,Users
+,"1,010"
+`
+	aggregates, err := suite.readAggregates(SourceUniversalAnalytics, []byte(export), Options{})
+	suite.Require().NoError(err)
+
+	// without pages there are only totals, and no hostname is needed
+	suite.Equal([]Aggregate{
+		{Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Dimension: DimensionAll, Visitors: 1000, Pageviews: 2500},
+		{Date: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Dimension: DimensionAll, Visitors: 10, Pageviews: 10},
+	}, aggregates)
+}
+
+func (suite *ImporterSuite) TestPlausible() {
+	export := zipOf(map[string]string{
+		"imported_visitors_20250301_20250301.csv":          "date,visitors,pageviews,bounces,visits,visit_duration\n2025-03-01,10,25,4,12,640\n",
+		"imported_pages_20250301_20250301.csv":             "date,hostname,page,visits,visitors,pageviews,exits,time_on_page\n2025-03-01,example.com,/,8,8,15,5,100\n2025-03-01,example.com,/blog,3,3,10,2,200\n",
+		"imported_sources_20250301_20250301.csv":           "date,source,referrer,utm_source,utm_medium,utm_campaign,utm_content,utm_term,visitors,visits,visit_duration,bounces\n2025-03-01,Google,google.com,,,,,,6,7,300,2\n2025-03-01,Direct / None,,,,,,,3,3,100,1\n",
+		"imported_locations_20250301_20250301.csv":         "date,country,region,city,visitors,visits,visit_duration,bounces\n2025-03-01,NG,NG-LA,2332459,7,8,100,1\n2025-03-01,GB,,0,5,5,100,1\n",
+		"imported_browsers_20250301_20250301.csv":          "date,browser,browser_version,visitors,visits,visit_duration,bounces\n2025-03-01,Chrome,120,10,12,640,4\n",
+		"imported_operating_systems_20250301_20250301.csv": "date,operating_system,operating_system_version,visitors,visits,visit_duration,bounces\n2025-03-01,Mac,14,4,4,100,1\n2025-03-01,GNU/Linux,,6,8,540,3\n",
+		"imported_entry_pages_20250301_20250301.csv":       "date,entry_page,visitors,entrances,visit_duration,bounces\n2025-03-01,/,100,100,0,0\n",
+	})
+
+	aggregates, err := suite.readAggregates(SourcePlausible, export, Options{})
+	suite.Require().NoError(err)
+
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	suite.Equal(map[string][2]int64{"": {10, 25}}, countsOf(aggregates, DimensionAll, day))
+	suite.Equal(map[string][2]int64{"https://example.com/": {8, 15}, "https://example.com/blog": {3, 10}}, countsOf(aggregates, "url", day))
+	// values are kept as exported, even when they add up to more or less
+	// than the totals, and reports without pageviews have none
+	suite.Equal(map[string][2]int64{"Google": {6, 0}, "": {3, 0}}, countsOf(aggregates, "referrer", day))
+	suite.Equal(map[string][2]int64{"Nigeria": {7, 0}, "United Kingdom": {5, 0}}, countsOf(aggregates, "country", day))
+	suite.Equal(map[string][2]int64{"Chrome": {10, 0}}, countsOf(aggregates, "browser", day))
+	suite.Equal(map[string][2]int64{"Linux": {6, 0}, "macOS": {4, 0}}, countsOf(aggregates, "operating_system", day))
+	suite.Empty(countsOf(aggregates, "device", day))
+}
+
+func (suite *ImporterSuite) TestAggregateErrors() {
+	_, err := suite.readAggregates(SourceGA4, []byte("Date,Page path,Views\n20250301,/,3\n"), Options{})
+	suite.ErrorContains(err, "line 2: page \"/\" has no hostname")
+
+	_, err = suite.readAggregates(SourceGA4, []byte("Date,Views\n2025/03/01,3\n"), Options{})
+	suite.ErrorContains(err, `invalid date "2025/03/01"`)
+
+	_, err = suite.readAggregates(SourceGA4, []byte("Page path,Views\n/,3\n"), Options{})
+	suite.ErrorContains(err, "found no daily data")
+
+	// aggregated and event sources are read with their own functions
+	_, err = suite.read(SourcePlausible, []byte("date,visitors\n2025-03-01,3\n"), Options{})
+	suite.ErrorContains(err, "plausible exports hold aggregates")
+	_, err = suite.readAggregates(SourceUmami, []byte(umamiDump), Options{})
+	suite.ErrorContains(err, "umami exports hold events")
+}
+
+const umamiDump = `--
+-- PostgreSQL database dump
+--
+
+COPY public.session (session_id, website_id, hostname, browser, os, device, screen, language, country, subdivision1, subdivision2, city, created_at) FROM stdin;
+5a1f6f5e-0000-4000-8000-000000000001	8d1b4c2e-0000-4000-8000-000000000001	example.com	chrome	Windows 10	desktop	1920x1080	en-US	NG	\N	\N	Lagos	2024-05-01 09:59:00.000+00
+5a1f6f5e-0000-4000-8000-000000000002	8d1b4c2e-0000-4000-8000-000000000001	example.com	ios	iOS	mobile	390x844	en-GB	GB	\N	\N	\N	2024-05-01 10:00:00+00
+\.
+
+
+COPY public.website_event (event_id, website_id, session_id, created_at, url_path, url_query, referrer_path, referrer_query, referrer_domain, page_title, event_type, event_name) FROM stdin;
+e0000000-0000-4000-8000-000000000001	8d1b4c2e-0000-4000-8000-000000000001	5a1f6f5e-0000-4000-8000-000000000001	2024-05-01 10:00:00.123+00	/	\N	/search	q=minalytics	www.google.com	Home\tpage	1	\N
+e0000000-0000-4000-8000-000000000002	8d1b4c2e-0000-4000-8000-000000000001	5a1f6f5e-0000-4000-8000-000000000001	2024-05-01 10:01:00+00	/pricing	plan=pro	/	\N	example.com	Pricing	1	\N
+e0000000-0000-4000-8000-000000000003	8d1b4c2e-0000-4000-8000-000000000001	5a1f6f5e-0000-4000-8000-000000000002	2024-05-01 10:02:00+00	/pricing	\N	\N	\N	\N	Pricing	2	signup
+`
+
+func (suite *ImporterSuite) TestUmami() {
+	events, err := suite.read(SourceUmami, []byte(umamiDump+"\\.\n"), Options{})
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 3)
+
+	url, referrer := "https://example.com/", "https://www.google.com/search?q=minalytics"
+	suite.Equal(Event{
+		VisitorID: "umami-5a1f6f5e-0000-4000-8000-000000000001",
+		Type:      "pageview",
+		URL:       &url,
+		Referrer:  &referrer,
+		Country:   "Nigeria",
+		Browser:   "Chrome",
+		Device:    "desktop",
+		OS:        "Windows",
+		Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 123000000, time.UTC),
+	}, events[0])
+
+	// visits from the site itself have no referrer
+	suite.Equal("https://example.com/pricing?plan=pro", *events[1].URL)
+	suite.Equal("", *events[1].Referrer)
+
+	suite.Equal("signup", events[2].Type)
+	suite.Equal("United Kingdom", events[2].Country)
+	suite.Equal("Safari", events[2].Browser)
+	suite.Equal("iOS", events[2].OS)
+}
+
+func (suite *ImporterSuite) TestUmamiWebsites() {
+	other := "e0000000-0000-4000-8000-000000000004\t9d1b4c2e-0000-4000-8000-000000000002\t5a1f6f5e-0000-4000-8000-000000000002\t2024-05-01 11:00:00+00\t/\t\\N\t\\N\t\\N\t\\N\tHome\t1\t\\N\n"
+	dump := []byte(umamiDump + other + "\\.\n")
+
+	_, err := suite.read(SourceUmami, dump, Options{})
+	suite.ErrorContains(err, "the dump holds several websites")
+
+	events, err := suite.read(SourceUmami, dump, Options{WebsiteID: "9d1b4c2e-0000-4000-8000-000000000002"})
+	suite.Require().NoError(err)
+	suite.Len(events, 1)
+
+	_, err = suite.read(SourceUmami, []byte("CREATE TABLE session ();\n"), Options{})
+	suite.ErrorContains(err, "found no website_event data")
+}
+
+func (suite *ImporterSuite) TestUnescapeCopy() {
+	suite.Equal("a\tb\nc\\d", unescapeCopy(`a\tb\nc\\d`))
+	suite.Equal("é!x", unescapeCopy(`\xc3\xa9\041\x`))
+}
+
+// fakeResolver resolves the addresses and user agents of the access log tests.
+type fakeResolver struct {
+	lookups int
+}
+
+func (r *fakeResolver) ResolveGeoLocation(ip string) (*types.GeoLocation, error) {
+	r.lookups++
+	if ip == "203.0.113.9" {
+		return &types.GeoLocation{Country: "Nigeria"}, nil
+	}
+	return nil, errors.New("address not found")
+}
+
+func (r *fakeResolver) ParseUserAgent(userAgent string) *types.UserAgentDetails {
+	if strings.Contains(userAgent, "iPhone") {
+		return &types.UserAgentDetails{Browser: "Safari", Device: "mobile", OperatingSystem: "iOS"}
+	}
+	return &types.UserAgentDetails{Browser: "Chrome", Device: "desktop", OperatingSystem: "Windows"}
+}
+
+const (
+	chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	iphone = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+)
+
+var accessLog = strings.Join([]string{
+	`203.0.113.9 - - [01/May/2024:10:00:00 +0100] "GET /?utm_source=news HTTP/1.1" 200 512 "https://www.google.com/" "` + chrome + `"`,
+	`203.0.113.9 - - [01/May/2024:10:00:01 +0100] "GET /assets/app.css HTTP/1.1" 200 512 "https://example.com/" "` + chrome + `"`,
+	`203.0.113.9 - - [01/May/2024:10:01:00 +0100] "GET /pricing HTTP/1.1" 304 0 "https://example.com/" "` + chrome + `"`,
+	`198.51.100.7 - - [01/May/2024:10:02:00 +0100] "GET /blog/hello HTTP/2.0" 200 2048 "-" "` + iphone + `"`,
+	`198.51.100.7 - - [01/May/2024:10:02:30 +0100] "POST /contact HTTP/2.0" 200 12 "-" "` + iphone + `"`,
+	`198.51.100.7 - - [01/May/2024:10:03:00 +0100] "GET /missing HTTP/2.0" 404 12 "-" "` + iphone + `"`,
+	`66.249.66.1 - - [01/May/2024:10:04:00 +0100] "GET /pricing HTTP/1.1" 200 512 "-" "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"`,
+	`192.0.2.1 - - [01/May/2024:10:05:00 +0100] "GET / HTTP/1.1" 200 512 "-" "curl/8.4.0"`,
+	`192.0.2.1 - - [01/May/2024:10:05:00 +0100] "GET / HTTP/1.1" 200 512 "-" "-"`,
+	`this line is not a request`,
+}, "\n") + "\n"
+
+func (suite *ImporterSuite) TestAccessLog() {
+	resolver := &fakeResolver{}
+	opts := Options{Location: suite.lagos, Hostname: "example.com", Resolver: resolver, VisitorSalt: "salt"}
+	events, err := suite.read(SourceAccessLog, []byte(accessLog), opts)
+	suite.Require().NoError(err)
+	// assets, other methods, errors, bots and malformed lines are skipped
+	suite.Require().Len(events, 3)
+
+	url, referrer := "https://example.com/?utm_source=news", "https://www.google.com/"
+	suite.Equal(Event{
+		VisitorID: events[0].VisitorID,
+		Type:      "pageview",
+		URL:       &url,
+		Referrer:  &referrer,
+		Country:   "Nigeria",
+		Browser:   "Chrome",
+		Device:    "desktop",
+		OS:        "Windows",
+		Timestamp: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+	}, events[0])
+	suite.Len(events[0].VisitorID, 64)
+
+	// visits from the site itself have no referrer
+	suite.Equal("https://example.com/pricing", *events[1].URL)
+	suite.Equal("", *events[1].Referrer)
+	suite.Equal(events[0].VisitorID, events[1].VisitorID)
+
+	suite.Equal("https://example.com/blog/hello", *events[2].URL)
+	suite.Equal("", *events[2].Referrer)
+	suite.Equal("", events[2].Country)
+	suite.Equal("Safari", events[2].Browser)
+	suite.NotEqual(events[0].VisitorID, events[2].VisitorID)
+
+	// addresses are resolved once
+	suite.Equal(2, resolver.lookups)
+
+	// visitor ids are salted per app
+	opts.VisitorSalt = "other"
+	salted, err := suite.read(SourceAccessLog, []byte(accessLog), opts)
+	suite.Require().NoError(err)
+	suite.NotEqual(events[0].VisitorID, salted[0].VisitorID)
+}
+
+func (suite *ImporterSuite) TestAccessLogGzip() {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(accessLog))
+	writer.Close()
+
+	events, err := suite.read(SourceAccessLog, buf.Bytes(), Options{Hostname: "example.com", Resolver: &fakeResolver{}})
+	suite.Require().NoError(err)
+	suite.Len(events, 3)
+}
+
+func (suite *ImporterSuite) TestAccessLogFormats() {
+	for name, test := range map[string]struct {
+		format string
+		line   string
+	}{
+		// the common format has no user agents, so no line reads as a bot
+		"common": {
+			format: "common",
+			line:   `203.0.113.9 - frank [01/May/2024:10:00:00 +0100] "GET /pricing HTTP/1.1" 200 512`,
+		},
+		"nginx": {
+			format: `"$http_x_forwarded_for" - [$time_iso8601] $host "$request_method $request_uri" $status "$http_user_agent" $request_time`,
+			line:   `"203.0.113.9, 10.0.0.1" - [2024-05-01T10:00:00+01:00] example.com:443 "GET /pricing" 200 "` + chrome + `" 0.012`,
+		},
+		"apache": {
+			format: `%v %h %l %u %t "%m %U%q %H" %>s %b "%{Referer}i" "%{User-agent}i"`,
+			line:   `example.com 203.0.113.9 - - [01/May/2024:10:00:00 +0100] "GET /pricing HTTP/1.1" 200 512 "-" "` + chrome + `"`,
+		},
+	} {
+		events, err := suite.read(SourceAccessLog, []byte(test.line+"\n"), Options{Hostname: "example.com", LogFormat: test.format, Resolver: &fakeResolver{}})
+		suite.Require().NoError(err, name)
+		suite.Require().Len(events, 1, name)
+		suite.Equal("https://example.com/pricing", *events[0].URL, name)
+		suite.Equal("Nigeria", events[0].Country, name)
+		suite.Equal(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), events[0].Timestamp, name)
+	}
+}
+
+func (suite *ImporterSuite) TestAccessLogErrors() {
+	for format, message := range map[string]string{
+		"json":                       "unsupported log format",
+		`$remote_addr "$request"`:    "the log format has no time",
+		`$remote_addr [$time_local]`: "the log format has no request",
+		`[$time_local] "$request"`:   "the log format has no client address",
+		`%h %{%d/%b/%Y}t "%r"`:       "unsupported time format",
+	} {
+		_, err := ParseLogFormat(format)
+		suite.ErrorContains(err, message, format)
+	}
+
+	_, err := suite.read(SourceAccessLog, []byte("not a log\n"), Options{Resolver: &fakeResolver{}})
+	suite.ErrorContains(err, "no line matches the log format")
+
+	_, err = suite.read(SourceAccessLog, []byte("\n"), Options{Resolver: &fakeResolver{}})
+	suite.ErrorContains(err, "found no log lines")
+}
+
+func TestImporterSuite(t *testing.T) {
+	suite.Run(t, new(ImporterSuite))
+}
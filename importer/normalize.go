@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/mileusna/useragent"
+)
+
+// isNotSet reports whether an export has no value for a dimension.
+func isNotSet(value string) bool {
+	switch strings.ToLower(value) {
+	case "", "(not set)", "(none)", "(unknown)":
+		return true
+	}
+	return false
+}
+
+// referrer returns the referrer of a source, "" for direct visits like the
+// tracker stores them.
+func referrer(source string) string {
+	switch strings.ToLower(source) {
+	case "(direct)", "direct", "direct / none", "(direct) / (none)":
+		return ""
+	}
+	return truncate(source, 255)
+}
+
+// countryName names the country of an ISO code, and keeps names as they are.
+func countryName(value string) string {
+	if name, exists := countries[strings.ToUpper(value)]; exists && len(value) == 2 {
+		return name
+	}
+	return value
+}
+
+// browsers names browsers like the user agent parser of the tracker does, by
+// how exports name them lower cased. Umami names them by id.
+var browsers = map[string]string{
+	"chrome":            useragent.Chrome,
+	"crios":             useragent.Chrome,
+	"chromium-webview":  useragent.Chrome,
+	"firefox":           useragent.Firefox,
+	"fxios":             useragent.Firefox,
+	"safari":            useragent.Safari,
+	"ios":               useragent.Safari,
+	"ios-webview":       useragent.Safari,
+	"mobile safari":     useragent.MobileSafari,
+	"safari (in-app)":   useragent.Safari,
+	"edge":              useragent.Edge,
+	"edge-chromium":     useragent.Edge,
+	"edge-ios":          useragent.Edge,
+	"microsoft edge":    useragent.Edge,
+	"opera":             useragent.Opera,
+	"opera-mini":        useragent.OperaMini,
+	"opera mini":        useragent.OperaMini,
+	"samsung":           useragent.SamsungBrowser,
+	"samsung internet":  useragent.SamsungBrowser,
+	"ie":                useragent.InternetExplorer,
+	"internet explorer": useragent.InternetExplorer,
+	"vivaldi":           useragent.Vivaldi,
+	"facebook":          useragent.FacebookApp,
+	"instagram":         useragent.InstagramApp,
+}
+
+func browserName(value string) string {
+	if name, exists := browsers[strings.ToLower(value)]; exists {
+		return name
+	}
+	return value
+}
+
+// operatingSystems names operating systems like the user agent parser of the
+// tracker does, by the lower cased start of how exports name them, so versions
+// like Umami's "Windows 10" are dropped.
+var operatingSystems = []struct {
+	prefix string
+	name   string
+}{
+	{"windows phone", useragent.WindowsPhone},
+	{"windows", useragent.Windows},
+	{"mac", useragent.MacOS},
+	{"os x", useragent.MacOS},
+	{"ios", useragent.IOS},
+	{"android", useragent.Android},
+	{"chrome os", useragent.ChromeOS},
+	{"chromeos", useragent.ChromeOS},
+	{"gnu/linux", useragent.Linux},
+	{"linux", useragent.Linux},
+	{"ubuntu", useragent.Linux},
+	{"freebsd", useragent.FreeBSD},
+}
+
+func osName(value string) string {
+	lower := strings.ToLower(value)
+	for _, os := range operatingSystems {
+		if strings.HasPrefix(lower, os.prefix) {
+			return os.name
+		}
+	}
+	return value
+}
@@ -0,0 +1,297 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// copyHeader matches the statement starting the data of a table in a plain
+// PostgreSQL dump, capturing the table and its columns.
+var copyHeader = regexp.MustCompile(`^COPY (?:"?\w+"?\.)?"?(\w+)"? \((.*)\) FROM stdin;`)
+
+// umamiTimeLayouts are the formats of timestamps in dumps, UTC when they have
+// no offset.
+var umamiTimeLayouts = []string{"2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999"}
+
+// Umami event types.
+const (
+	umamiPageview    = "1"
+	umamiCustomEvent = "2"
+)
+
+type umamiSession struct {
+	hostname string
+	browser  string
+	os       string
+	device   string
+	country  string
+}
+
+// umamiReader reads the sessions and events of an Umami v2 dump. Sessions
+// come before events in dumps, since tables are dumped by name.
+type umamiReader struct {
+	opts     Options
+	batcher  *batcher
+	input    *countingReader
+	size     int64
+	sessions map[string]umamiSession
+	website  string
+	events   bool
+	imported int64
+}
+
+func readUmami(r io.Reader, size int64, opts Options, b *batcher) error {
+	u := &umamiReader{
+		opts:     opts,
+		batcher:  b,
+		input:    &countingReader{reader: r},
+		size:     size,
+		sessions: make(map[string]umamiSession),
+		website:  opts.WebsiteID,
+	}
+	reader := bufio.NewReaderSize(u.input, 64*1024)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if match := copyHeader.FindStringSubmatch(line); match != nil {
+			columns := strings.Split(match[2], ", ")
+			for i, column := range columns {
+				columns[i] = strings.Trim(column, `"`)
+			}
+
+			switch match[1] {
+			case "session":
+				if err := readCopy(reader, columns, u.addSession); err != nil {
+					return err
+				}
+			case "website_event":
+				u.events = true
+				if err := readCopy(reader, columns, u.addEvent); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if !u.events {
+		return errors.New("found no website_event data, expect a plain PostgreSQL dump of Umami v2")
+	}
+	if u.imported == 0 && u.opts.WebsiteID != "" {
+		return fmt.Errorf("found no events of website %s", u.opts.WebsiteID)
+	}
+	return nil
+}
+
+func (u *umamiReader) addSession(row map[string]*string) error {
+	value := func(column string) string {
+		if v := row[column]; v != nil {
+			return *v
+		}
+		return ""
+	}
+
+	u.sessions[value("session_id")] = umamiSession{
+		hostname: value("hostname"),
+		browser:  truncate(browserName(value("browser")), 100),
+		os:       truncate(osName(value("os")), 100),
+		device:   truncate(value("device"), 100),
+		country:  truncate(countryName(value("country")), 100),
+	}
+	return nil
+}
+
+func (u *umamiReader) addEvent(row map[string]*string) error {
+	value := func(column string) string {
+		if v := row[column]; v != nil {
+			return *v
+		}
+		return ""
+	}
+
+	website := value("website_id")
+	if u.website == "" {
+		u.website = website
+	} else if website != u.website {
+		if u.opts.WebsiteID != "" {
+			return nil
+		}
+		return fmt.Errorf("the dump holds several websites, pick one with website_id: %s, %s", u.website, website)
+	}
+
+	var eventType string
+	switch value("event_type") {
+	case umamiPageview:
+		eventType = "pageview"
+	case umamiCustomEvent:
+		eventType = truncate(value("event_name"), 50)
+		if eventType == "" {
+			eventType = "event"
+		}
+	default:
+		return nil
+	}
+
+	timestamp, err := parseUmamiTime(value("created_at"))
+	if err != nil {
+		return err
+	}
+
+	session := u.sessions[value("session_id")]
+	hostname := value("hostname")
+	if hostname == "" {
+		hostname = session.hostname
+	}
+
+	page := value("url_path")
+	if query := value("url_query"); query != "" {
+		page += "?" + query
+	}
+	url, err := pageURL(page, hostname, u.opts.Hostname)
+	if err != nil {
+		return err
+	}
+
+	// like the tracker, visits from the site itself have no referrer
+	referrer := ""
+	if domain := value("referrer_domain"); domain != "" && domain != hostname {
+		referrer = "https://" + domain + value("referrer_path")
+		if query := value("referrer_query"); query != "" {
+			referrer += "?" + query
+		}
+		referrer = truncate(referrer, 255)
+	}
+
+	u.imported++
+	return u.batcher.add(Event{
+		VisitorID: "umami-" + value("session_id"),
+		Type:      eventType,
+		URL:       &url,
+		Referrer:  &referrer,
+		Country:   session.country,
+		Browser:   session.browser,
+		Device:    session.device,
+		OS:        session.os,
+		Timestamp: timestamp,
+	}, float64(u.input.read)/float64(max(u.size, 1)))
+}
+
+func parseUmamiTime(value string) (time.Time, error) {
+	for _, layout := range umamiTimeLayouts {
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// readCopy passes the rows of a COPY block to add, by column, with nil for
+// NULL values.
+func readCopy(reader *bufio.Reader, columns []string, add func(map[string]*string) error) error {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return fmt.Errorf("unterminated COPY block: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == `\.` {
+			return nil
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != len(columns) {
+			return fmt.Errorf("COPY row has %d values for %d columns", len(fields), len(columns))
+		}
+		row := make(map[string]*string, len(columns))
+		for i, field := range fields {
+			if field != `\N` {
+				value := unescapeCopy(field)
+				row[columns[i]] = &value
+			}
+		}
+		if err := add(row); err != nil {
+			return err
+		}
+	}
+}
+
+// unescapeCopy decodes the backslash escapes of the COPY text format.
+func unescapeCopy(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i+1 == len(field) {
+			out.WriteByte(field[i])
+			continue
+		}
+
+		i++
+		switch c := field[i]; c {
+		case 'b':
+			out.WriteByte('\b')
+		case 'f':
+			out.WriteByte('\f')
+		case 'n':
+			out.WriteByte('\n')
+		case 'r':
+			out.WriteByte('\r')
+		case 't':
+			out.WriteByte('\t')
+		case 'v':
+			out.WriteByte('\v')
+		case 'x':
+			end := i + 1
+			for end < len(field) && end < i+3 && strings.ContainsRune("0123456789abcdefABCDEF", rune(field[end])) {
+				end++
+			}
+			if end == i+1 {
+				out.WriteByte(c)
+				continue
+			}
+			value, _ := strconv.ParseUint(field[i+1:end], 16, 8)
+			out.WriteByte(byte(value))
+			i = end - 1
+		default:
+			if c >= '0' && c <= '7' {
+				end := i
+				for end < len(field) && end < i+3 && field[end] >= '0' && field[end] <= '7' {
+					end++
+				}
+				value, _ := strconv.ParseUint(field[i:end], 8, 8)
+				out.WriteByte(byte(value))
+				i = end - 1
+			} else {
+				out.WriteByte(c)
+			}
+		}
+	}
+	return out.String()
+}
+
+// countingReader counts the bytes read through it, for progress.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
@@ -8,6 +8,8 @@ import (
 	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -24,26 +26,28 @@ func (_m *Querier) EXPECT() *Querier_Expecter {
 	return &Querier_Expecter{mock: &_m.Mock}
 }
 
-// CheckAppExists provides a mock function with given fields: ctx, arg
-func (_m *Querier) CheckAppExists(ctx context.Context, arg database.CheckAppExistsParams) (database.App, error) {
+// AcceptAppInvitation provides a mock function with given fields: ctx, arg
+func (_m *Querier) AcceptAppInvitation(ctx context.Context, arg database.AcceptAppInvitationParams) (uuid.UUID, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckAppExists")
+		panic("no return value specified for AcceptAppInvitation")
 	}
 
-	var r0 database.App
+	var r0 uuid.UUID
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.CheckAppExistsParams) (database.App, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.AcceptAppInvitationParams) (uuid.UUID, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.CheckAppExistsParams) database.App); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.AcceptAppInvitationParams) uuid.UUID); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(database.App)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.CheckAppExistsParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.AcceptAppInvitationParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -52,55 +56,55 @@ func (_m *Querier) CheckAppExists(ctx context.Context, arg database.CheckAppExis
 	return r0, r1
 }
 
-// Querier_CheckAppExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckAppExists'
-type Querier_CheckAppExists_Call struct {
+// Querier_AcceptAppInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcceptAppInvitation'
+type Querier_AcceptAppInvitation_Call struct {
 	*mock.Call
 }
 
-// CheckAppExists is a helper method to define mock.On call
+// AcceptAppInvitation is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.CheckAppExistsParams
-func (_e *Querier_Expecter) CheckAppExists(ctx interface{}, arg interface{}) *Querier_CheckAppExists_Call {
-	return &Querier_CheckAppExists_Call{Call: _e.mock.On("CheckAppExists", ctx, arg)}
+//   - arg database.AcceptAppInvitationParams
+func (_e *Querier_Expecter) AcceptAppInvitation(ctx interface{}, arg interface{}) *Querier_AcceptAppInvitation_Call {
+	return &Querier_AcceptAppInvitation_Call{Call: _e.mock.On("AcceptAppInvitation", ctx, arg)}
 }
 
-func (_c *Querier_CheckAppExists_Call) Run(run func(ctx context.Context, arg database.CheckAppExistsParams)) *Querier_CheckAppExists_Call {
+func (_c *Querier_AcceptAppInvitation_Call) Run(run func(ctx context.Context, arg database.AcceptAppInvitationParams)) *Querier_AcceptAppInvitation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.CheckAppExistsParams))
+		run(args[0].(context.Context), args[1].(database.AcceptAppInvitationParams))
 	})
 	return _c
 }
 
-func (_c *Querier_CheckAppExists_Call) Return(_a0 database.App, _a1 error) *Querier_CheckAppExists_Call {
+func (_c *Querier_AcceptAppInvitation_Call) Return(_a0 uuid.UUID, _a1 error) *Querier_AcceptAppInvitation_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_CheckAppExists_Call) RunAndReturn(run func(context.Context, database.CheckAppExistsParams) (database.App, error)) *Querier_CheckAppExists_Call {
+func (_c *Querier_AcceptAppInvitation_Call) RunAndReturn(run func(context.Context, database.AcceptAppInvitationParams) (uuid.UUID, error)) *Querier_AcceptAppInvitation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateApp provides a mock function with given fields: ctx, arg
-func (_m *Querier) CreateApp(ctx context.Context, arg database.CreateAppParams) (database.App, error) {
+// CheckAppExists provides a mock function with given fields: ctx, arg
+func (_m *Querier) CheckAppExists(ctx context.Context, arg database.CheckAppExistsParams) (database.App, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateApp")
+		panic("no return value specified for CheckAppExists")
 	}
 
 	var r0 database.App
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppParams) (database.App, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CheckAppExistsParams) (database.App, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppParams) database.App); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CheckAppExistsParams) database.App); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Get(0).(database.App)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.CreateAppParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CheckAppExistsParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -109,46 +113,46 @@ func (_m *Querier) CreateApp(ctx context.Context, arg database.CreateAppParams)
 	return r0, r1
 }
 
-// Querier_CreateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateApp'
-type Querier_CreateApp_Call struct {
+// Querier_CheckAppExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckAppExists'
+type Querier_CheckAppExists_Call struct {
 	*mock.Call
 }
 
-// CreateApp is a helper method to define mock.On call
+// CheckAppExists is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.CreateAppParams
-func (_e *Querier_Expecter) CreateApp(ctx interface{}, arg interface{}) *Querier_CreateApp_Call {
-	return &Querier_CreateApp_Call{Call: _e.mock.On("CreateApp", ctx, arg)}
+//   - arg database.CheckAppExistsParams
+func (_e *Querier_Expecter) CheckAppExists(ctx interface{}, arg interface{}) *Querier_CheckAppExists_Call {
+	return &Querier_CheckAppExists_Call{Call: _e.mock.On("CheckAppExists", ctx, arg)}
 }
 
-func (_c *Querier_CreateApp_Call) Run(run func(ctx context.Context, arg database.CreateAppParams)) *Querier_CreateApp_Call {
+func (_c *Querier_CheckAppExists_Call) Run(run func(ctx context.Context, arg database.CheckAppExistsParams)) *Querier_CheckAppExists_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.CreateAppParams))
+		run(args[0].(context.Context), args[1].(database.CheckAppExistsParams))
 	})
 	return _c
 }
 
-func (_c *Querier_CreateApp_Call) Return(_a0 database.App, _a1 error) *Querier_CreateApp_Call {
+func (_c *Querier_CheckAppExists_Call) Return(_a0 database.App, _a1 error) *Querier_CheckAppExists_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_CreateApp_Call) RunAndReturn(run func(context.Context, database.CreateAppParams) (database.App, error)) *Querier_CreateApp_Call {
+func (_c *Querier_CheckAppExists_Call) RunAndReturn(run func(context.Context, database.CheckAppExistsParams) (database.App, error)) *Querier_CheckAppExists_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateEvent provides a mock function with given fields: ctx, arg
-func (_m *Querier) CreateEvent(ctx context.Context, arg database.CreateEventParams) error {
-	ret := _m.Called(ctx, arg)
+// CheckRegex provides a mock function with given fields: ctx, pattern
+func (_m *Querier) CheckRegex(ctx context.Context, pattern string) error {
+	ret := _m.Called(ctx, pattern)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateEvent")
+		panic("no return value specified for CheckRegex")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.CreateEventParams) error); ok {
-		r0 = rf(ctx, arg)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, pattern)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -156,103 +160,113 @@ func (_m *Querier) CreateEvent(ctx context.Context, arg database.CreateEventPara
 	return r0
 }
 
-// Querier_CreateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvent'
-type Querier_CreateEvent_Call struct {
+// Querier_CheckRegex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckRegex'
+type Querier_CheckRegex_Call struct {
 	*mock.Call
 }
 
-// CreateEvent is a helper method to define mock.On call
+// CheckRegex is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.CreateEventParams
-func (_e *Querier_Expecter) CreateEvent(ctx interface{}, arg interface{}) *Querier_CreateEvent_Call {
-	return &Querier_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, arg)}
+//   - pattern string
+func (_e *Querier_Expecter) CheckRegex(ctx interface{}, pattern interface{}) *Querier_CheckRegex_Call {
+	return &Querier_CheckRegex_Call{Call: _e.mock.On("CheckRegex", ctx, pattern)}
 }
 
-func (_c *Querier_CreateEvent_Call) Run(run func(ctx context.Context, arg database.CreateEventParams)) *Querier_CreateEvent_Call {
+func (_c *Querier_CheckRegex_Call) Run(run func(ctx context.Context, pattern string)) *Querier_CheckRegex_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.CreateEventParams))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *Querier_CreateEvent_Call) Return(_a0 error) *Querier_CreateEvent_Call {
+func (_c *Querier_CheckRegex_Call) Return(_a0 error) *Querier_CheckRegex_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Querier_CreateEvent_Call) RunAndReturn(run func(context.Context, database.CreateEventParams) error) *Querier_CreateEvent_Call {
+func (_c *Querier_CheckRegex_Call) RunAndReturn(run func(context.Context, string) error) *Querier_CheckRegex_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteApp provides a mock function with given fields: ctx, trackingID
-func (_m *Querier) DeleteApp(ctx context.Context, trackingID uuid.UUID) error {
-	ret := _m.Called(ctx, trackingID)
+// ClaimPendingDeletion provides a mock function with given fields: ctx, lockedBy
+func (_m *Querier) ClaimPendingDeletion(ctx context.Context, lockedBy string) (database.Deletion, error) {
+	ret := _m.Called(ctx, lockedBy)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteApp")
+		panic("no return value specified for ClaimPendingDeletion")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, trackingID)
+	var r0 database.Deletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (database.Deletion, error)); ok {
+		return rf(ctx, lockedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) database.Deletion); ok {
+		r0 = rf(ctx, lockedBy)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(database.Deletion)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, lockedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Querier_DeleteApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteApp'
-type Querier_DeleteApp_Call struct {
+// Querier_ClaimPendingDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPendingDeletion'
+type Querier_ClaimPendingDeletion_Call struct {
 	*mock.Call
 }
 
-// DeleteApp is a helper method to define mock.On call
+// ClaimPendingDeletion is a helper method to define mock.On call
 //   - ctx context.Context
-//   - trackingID uuid.UUID
-func (_e *Querier_Expecter) DeleteApp(ctx interface{}, trackingID interface{}) *Querier_DeleteApp_Call {
-	return &Querier_DeleteApp_Call{Call: _e.mock.On("DeleteApp", ctx, trackingID)}
+//   - lockedBy string
+func (_e *Querier_Expecter) ClaimPendingDeletion(ctx interface{}, lockedBy interface{}) *Querier_ClaimPendingDeletion_Call {
+	return &Querier_ClaimPendingDeletion_Call{Call: _e.mock.On("ClaimPendingDeletion", ctx, lockedBy)}
 }
 
-func (_c *Querier_DeleteApp_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_DeleteApp_Call {
+func (_c *Querier_ClaimPendingDeletion_Call) Run(run func(ctx context.Context, lockedBy string)) *Querier_ClaimPendingDeletion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *Querier_DeleteApp_Call) Return(_a0 error) *Querier_DeleteApp_Call {
-	_c.Call.Return(_a0)
+func (_c *Querier_ClaimPendingDeletion_Call) Return(_a0 database.Deletion, _a1 error) *Querier_ClaimPendingDeletion_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_DeleteApp_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *Querier_DeleteApp_Call {
+func (_c *Querier_ClaimPendingDeletion_Call) RunAndReturn(run func(context.Context, string) (database.Deletion, error)) *Querier_ClaimPendingDeletion_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAppByTrackingID provides a mock function with given fields: ctx, trackingID
-func (_m *Querier) GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID) (database.App, error) {
-	ret := _m.Called(ctx, trackingID)
+// ClaimPendingImport provides a mock function with given fields: ctx, arg
+func (_m *Querier) ClaimPendingImport(ctx context.Context, arg database.ClaimPendingImportParams) (database.Import, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAppByTrackingID")
+		panic("no return value specified for ClaimPendingImport")
 	}
 
-	var r0 database.App
+	var r0 database.Import
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (database.App, error)); ok {
-		return rf(ctx, trackingID)
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimPendingImportParams) (database.Import, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) database.App); ok {
-		r0 = rf(ctx, trackingID)
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimPendingImportParams) database.Import); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(database.App)
+		r0 = ret.Get(0).(database.Import)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, trackingID)
+	if rf, ok := ret.Get(1).(func(context.Context, database.ClaimPendingImportParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -260,58 +274,58 @@ func (_m *Querier) GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID)
 	return r0, r1
 }
 
-// Querier_GetAppByTrackingID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppByTrackingID'
-type Querier_GetAppByTrackingID_Call struct {
+// Querier_ClaimPendingImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPendingImport'
+type Querier_ClaimPendingImport_Call struct {
 	*mock.Call
 }
 
-// GetAppByTrackingID is a helper method to define mock.On call
+// ClaimPendingImport is a helper method to define mock.On call
 //   - ctx context.Context
-//   - trackingID uuid.UUID
-func (_e *Querier_Expecter) GetAppByTrackingID(ctx interface{}, trackingID interface{}) *Querier_GetAppByTrackingID_Call {
-	return &Querier_GetAppByTrackingID_Call{Call: _e.mock.On("GetAppByTrackingID", ctx, trackingID)}
+//   - arg database.ClaimPendingImportParams
+func (_e *Querier_Expecter) ClaimPendingImport(ctx interface{}, arg interface{}) *Querier_ClaimPendingImport_Call {
+	return &Querier_ClaimPendingImport_Call{Call: _e.mock.On("ClaimPendingImport", ctx, arg)}
 }
 
-func (_c *Querier_GetAppByTrackingID_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetAppByTrackingID_Call {
+func (_c *Querier_ClaimPendingImport_Call) Run(run func(ctx context.Context, arg database.ClaimPendingImportParams)) *Querier_ClaimPendingImport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(database.ClaimPendingImportParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetAppByTrackingID_Call) Return(_a0 database.App, _a1 error) *Querier_GetAppByTrackingID_Call {
+func (_c *Querier_ClaimPendingImport_Call) Return(_a0 database.Import, _a1 error) *Querier_ClaimPendingImport_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetAppByTrackingID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (database.App, error)) *Querier_GetAppByTrackingID_Call {
+func (_c *Querier_ClaimPendingImport_Call) RunAndReturn(run func(context.Context, database.ClaimPendingImportParams) (database.Import, error)) *Querier_ClaimPendingImport_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetApps provides a mock function with given fields: ctx, userID
-func (_m *Querier) GetApps(ctx context.Context, userID uuid.UUID) ([]database.App, error) {
-	ret := _m.Called(ctx, userID)
+// ClaimStaleDeletions provides a mock function with given fields: ctx, arg
+func (_m *Querier) ClaimStaleDeletions(ctx context.Context, arg database.ClaimStaleDeletionsParams) ([]database.Deletion, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetApps")
+		panic("no return value specified for ClaimStaleDeletions")
 	}
 
-	var r0 []database.App
+	var r0 []database.Deletion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.App, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimStaleDeletionsParams) ([]database.Deletion, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.App); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimStaleDeletionsParams) []database.Deletion); ok {
+		r0 = rf(ctx, arg)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.App)
+			r0 = ret.Get(0).([]database.Deletion)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, database.ClaimStaleDeletionsParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -319,57 +333,57 @@ func (_m *Querier) GetApps(ctx context.Context, userID uuid.UUID) ([]database.Ap
 	return r0, r1
 }
 
-// Querier_GetApps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetApps'
-type Querier_GetApps_Call struct {
+// Querier_ClaimStaleDeletions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimStaleDeletions'
+type Querier_ClaimStaleDeletions_Call struct {
 	*mock.Call
 }
 
-// GetApps is a helper method to define mock.On call
+// ClaimStaleDeletions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userID uuid.UUID
-func (_e *Querier_Expecter) GetApps(ctx interface{}, userID interface{}) *Querier_GetApps_Call {
-	return &Querier_GetApps_Call{Call: _e.mock.On("GetApps", ctx, userID)}
+//   - arg database.ClaimStaleDeletionsParams
+func (_e *Querier_Expecter) ClaimStaleDeletions(ctx interface{}, arg interface{}) *Querier_ClaimStaleDeletions_Call {
+	return &Querier_ClaimStaleDeletions_Call{Call: _e.mock.On("ClaimStaleDeletions", ctx, arg)}
 }
 
-func (_c *Querier_GetApps_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetApps_Call {
+func (_c *Querier_ClaimStaleDeletions_Call) Run(run func(ctx context.Context, arg database.ClaimStaleDeletionsParams)) *Querier_ClaimStaleDeletions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(database.ClaimStaleDeletionsParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetApps_Call) Return(_a0 []database.App, _a1 error) *Querier_GetApps_Call {
+func (_c *Querier_ClaimStaleDeletions_Call) Return(_a0 []database.Deletion, _a1 error) *Querier_ClaimStaleDeletions_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetApps_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.App, error)) *Querier_GetApps_Call {
+func (_c *Querier_ClaimStaleDeletions_Call) RunAndReturn(run func(context.Context, database.ClaimStaleDeletionsParams) ([]database.Deletion, error)) *Querier_ClaimStaleDeletions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetBrowsers provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetBrowsers(ctx context.Context, arg database.GetBrowsersParams) ([]database.GetBrowsersRow, error) {
+// ClaimStaleImports provides a mock function with given fields: ctx, arg
+func (_m *Querier) ClaimStaleImports(ctx context.Context, arg database.ClaimStaleImportsParams) ([]database.Import, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBrowsers")
+		panic("no return value specified for ClaimStaleImports")
 	}
 
-	var r0 []database.GetBrowsersRow
+	var r0 []database.Import
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetBrowsersParams) ([]database.GetBrowsersRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimStaleImportsParams) ([]database.Import, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetBrowsersParams) []database.GetBrowsersRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.ClaimStaleImportsParams) []database.Import); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetBrowsersRow)
+			r0 = ret.Get(0).([]database.Import)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetBrowsersParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.ClaimStaleImportsParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -378,57 +392,55 @@ func (_m *Querier) GetBrowsers(ctx context.Context, arg database.GetBrowsersPara
 	return r0, r1
 }
 
-// Querier_GetBrowsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBrowsers'
-type Querier_GetBrowsers_Call struct {
+// Querier_ClaimStaleImports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimStaleImports'
+type Querier_ClaimStaleImports_Call struct {
 	*mock.Call
 }
 
-// GetBrowsers is a helper method to define mock.On call
+// ClaimStaleImports is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetBrowsersParams
-func (_e *Querier_Expecter) GetBrowsers(ctx interface{}, arg interface{}) *Querier_GetBrowsers_Call {
-	return &Querier_GetBrowsers_Call{Call: _e.mock.On("GetBrowsers", ctx, arg)}
+//   - arg database.ClaimStaleImportsParams
+func (_e *Querier_Expecter) ClaimStaleImports(ctx interface{}, arg interface{}) *Querier_ClaimStaleImports_Call {
+	return &Querier_ClaimStaleImports_Call{Call: _e.mock.On("ClaimStaleImports", ctx, arg)}
 }
 
-func (_c *Querier_GetBrowsers_Call) Run(run func(ctx context.Context, arg database.GetBrowsersParams)) *Querier_GetBrowsers_Call {
+func (_c *Querier_ClaimStaleImports_Call) Run(run func(ctx context.Context, arg database.ClaimStaleImportsParams)) *Querier_ClaimStaleImports_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetBrowsersParams))
+		run(args[0].(context.Context), args[1].(database.ClaimStaleImportsParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetBrowsers_Call) Return(_a0 []database.GetBrowsersRow, _a1 error) *Querier_GetBrowsers_Call {
+func (_c *Querier_ClaimStaleImports_Call) Return(_a0 []database.Import, _a1 error) *Querier_ClaimStaleImports_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetBrowsers_Call) RunAndReturn(run func(context.Context, database.GetBrowsersParams) ([]database.GetBrowsersRow, error)) *Querier_GetBrowsers_Call {
+func (_c *Querier_ClaimStaleImports_Call) RunAndReturn(run func(context.Context, database.ClaimStaleImportsParams) ([]database.Import, error)) *Querier_ClaimStaleImports_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCountries provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetCountries(ctx context.Context, arg database.GetCountriesParams) ([]database.GetCountriesRow, error) {
+// CountDeletionEvents provides a mock function with given fields: ctx, arg
+func (_m *Querier) CountDeletionEvents(ctx context.Context, arg database.CountDeletionEventsParams) (database.CountDeletionEventsRow, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCountries")
+		panic("no return value specified for CountDeletionEvents")
 	}
 
-	var r0 []database.GetCountriesRow
+	var r0 database.CountDeletionEventsRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetCountriesParams) ([]database.GetCountriesRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CountDeletionEventsParams) (database.CountDeletionEventsRow, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetCountriesParams) []database.GetCountriesRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CountDeletionEventsParams) database.CountDeletionEventsRow); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetCountriesRow)
-		}
+		r0 = ret.Get(0).(database.CountDeletionEventsRow)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetCountriesParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CountDeletionEventsParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -437,58 +449,56 @@ func (_m *Querier) GetCountries(ctx context.Context, arg database.GetCountriesPa
 	return r0, r1
 }
 
-// Querier_GetCountries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountries'
-type Querier_GetCountries_Call struct {
+// Querier_CountDeletionEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountDeletionEvents'
+type Querier_CountDeletionEvents_Call struct {
 	*mock.Call
 }
 
-// GetCountries is a helper method to define mock.On call
+// CountDeletionEvents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetCountriesParams
-func (_e *Querier_Expecter) GetCountries(ctx interface{}, arg interface{}) *Querier_GetCountries_Call {
-	return &Querier_GetCountries_Call{Call: _e.mock.On("GetCountries", ctx, arg)}
+//   - arg database.CountDeletionEventsParams
+func (_e *Querier_Expecter) CountDeletionEvents(ctx interface{}, arg interface{}) *Querier_CountDeletionEvents_Call {
+	return &Querier_CountDeletionEvents_Call{Call: _e.mock.On("CountDeletionEvents", ctx, arg)}
 }
 
-func (_c *Querier_GetCountries_Call) Run(run func(ctx context.Context, arg database.GetCountriesParams)) *Querier_GetCountries_Call {
+func (_c *Querier_CountDeletionEvents_Call) Run(run func(ctx context.Context, arg database.CountDeletionEventsParams)) *Querier_CountDeletionEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetCountriesParams))
+		run(args[0].(context.Context), args[1].(database.CountDeletionEventsParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetCountries_Call) Return(_a0 []database.GetCountriesRow, _a1 error) *Querier_GetCountries_Call {
+func (_c *Querier_CountDeletionEvents_Call) Return(_a0 database.CountDeletionEventsRow, _a1 error) *Querier_CountDeletionEvents_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetCountries_Call) RunAndReturn(run func(context.Context, database.GetCountriesParams) ([]database.GetCountriesRow, error)) *Querier_GetCountries_Call {
+func (_c *Querier_CountDeletionEvents_Call) RunAndReturn(run func(context.Context, database.CountDeletionEventsParams) (database.CountDeletionEventsRow, error)) *Querier_CountDeletionEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetDevices provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetDevices(ctx context.Context, arg database.GetDevicesParams) ([]database.GetDevicesRow, error) {
-	ret := _m.Called(ctx, arg)
+// CountOrganizationOwners provides a mock function with given fields: ctx, orgID
+func (_m *Querier) CountOrganizationOwners(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, orgID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetDevices")
+		panic("no return value specified for CountOrganizationOwners")
 	}
 
-	var r0 []database.GetDevicesRow
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetDevicesParams) ([]database.GetDevicesRow, error)); ok {
-		return rf(ctx, arg)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, orgID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetDevicesParams) []database.GetDevicesRow); ok {
-		r0 = rf(ctx, arg)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, orgID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetDevicesRow)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetDevicesParams) error); ok {
-		r1 = rf(ctx, arg)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orgID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -496,57 +506,55 @@ func (_m *Querier) GetDevices(ctx context.Context, arg database.GetDevicesParams
 	return r0, r1
 }
 
-// Querier_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
-type Querier_GetDevices_Call struct {
+// Querier_CountOrganizationOwners_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountOrganizationOwners'
+type Querier_CountOrganizationOwners_Call struct {
 	*mock.Call
 }
 
-// GetDevices is a helper method to define mock.On call
+// CountOrganizationOwners is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetDevicesParams
-func (_e *Querier_Expecter) GetDevices(ctx interface{}, arg interface{}) *Querier_GetDevices_Call {
-	return &Querier_GetDevices_Call{Call: _e.mock.On("GetDevices", ctx, arg)}
+//   - orgID uuid.UUID
+func (_e *Querier_Expecter) CountOrganizationOwners(ctx interface{}, orgID interface{}) *Querier_CountOrganizationOwners_Call {
+	return &Querier_CountOrganizationOwners_Call{Call: _e.mock.On("CountOrganizationOwners", ctx, orgID)}
 }
 
-func (_c *Querier_GetDevices_Call) Run(run func(ctx context.Context, arg database.GetDevicesParams)) *Querier_GetDevices_Call {
+func (_c *Querier_CountOrganizationOwners_Call) Run(run func(ctx context.Context, orgID uuid.UUID)) *Querier_CountOrganizationOwners_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetDevicesParams))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *Querier_GetDevices_Call) Return(_a0 []database.GetDevicesRow, _a1 error) *Querier_GetDevices_Call {
+func (_c *Querier_CountOrganizationOwners_Call) Return(_a0 int64, _a1 error) *Querier_CountOrganizationOwners_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetDevices_Call) RunAndReturn(run func(context.Context, database.GetDevicesParams) ([]database.GetDevicesRow, error)) *Querier_GetDevices_Call {
+func (_c *Querier_CountOrganizationOwners_Call) RunAndReturn(run func(context.Context, uuid.UUID) (int64, error)) *Querier_CountOrganizationOwners_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOS provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetOS(ctx context.Context, arg database.GetOSParams) ([]database.GetOSRow, error) {
+// CreateAPIToken provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateAPIToken(ctx context.Context, arg database.CreateAPITokenParams) (database.ApiToken, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOS")
+		panic("no return value specified for CreateAPIToken")
 	}
 
-	var r0 []database.GetOSRow
+	var r0 database.ApiToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetOSParams) ([]database.GetOSRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAPITokenParams) (database.ApiToken, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetOSParams) []database.GetOSRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAPITokenParams) database.ApiToken); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetOSRow)
-		}
+		r0 = ret.Get(0).(database.ApiToken)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetOSParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateAPITokenParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -555,116 +563,112 @@ func (_m *Querier) GetOS(ctx context.Context, arg database.GetOSParams) ([]datab
 	return r0, r1
 }
 
-// Querier_GetOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOS'
-type Querier_GetOS_Call struct {
+// Querier_CreateAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIToken'
+type Querier_CreateAPIToken_Call struct {
 	*mock.Call
 }
 
-// GetOS is a helper method to define mock.On call
+// CreateAPIToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetOSParams
-func (_e *Querier_Expecter) GetOS(ctx interface{}, arg interface{}) *Querier_GetOS_Call {
-	return &Querier_GetOS_Call{Call: _e.mock.On("GetOS", ctx, arg)}
+//   - arg database.CreateAPITokenParams
+func (_e *Querier_Expecter) CreateAPIToken(ctx interface{}, arg interface{}) *Querier_CreateAPIToken_Call {
+	return &Querier_CreateAPIToken_Call{Call: _e.mock.On("CreateAPIToken", ctx, arg)}
 }
 
-func (_c *Querier_GetOS_Call) Run(run func(ctx context.Context, arg database.GetOSParams)) *Querier_GetOS_Call {
+func (_c *Querier_CreateAPIToken_Call) Run(run func(ctx context.Context, arg database.CreateAPITokenParams)) *Querier_CreateAPIToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetOSParams))
+		run(args[0].(context.Context), args[1].(database.CreateAPITokenParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetOS_Call) Return(_a0 []database.GetOSRow, _a1 error) *Querier_GetOS_Call {
+func (_c *Querier_CreateAPIToken_Call) Return(_a0 database.ApiToken, _a1 error) *Querier_CreateAPIToken_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetOS_Call) RunAndReturn(run func(context.Context, database.GetOSParams) ([]database.GetOSRow, error)) *Querier_GetOS_Call {
+func (_c *Querier_CreateAPIToken_Call) RunAndReturn(run func(context.Context, database.CreateAPITokenParams) (database.ApiToken, error)) *Querier_CreateAPIToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrCreateUser provides a mock function with given fields: ctx, email
-func (_m *Querier) GetOrCreateUser(ctx context.Context, email string) (uuid.UUID, error) {
-	ret := _m.Called(ctx, email)
+// CreateApp provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateApp(ctx context.Context, arg database.CreateAppParams) (database.App, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrCreateUser")
+		panic("no return value specified for CreateApp")
 	}
 
-	var r0 uuid.UUID
+	var r0 database.App
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
-		return rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppParams) (database.App, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppParams) database.App); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(uuid.UUID)
-		}
+		r0 = ret.Get(0).(database.App)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, email)
-	} else {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateAppParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
 
-// Querier_GetOrCreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrCreateUser'
-type Querier_GetOrCreateUser_Call struct {
+// Querier_CreateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateApp'
+type Querier_CreateApp_Call struct {
 	*mock.Call
 }
 
-// GetOrCreateUser is a helper method to define mock.On call
+// CreateApp is a helper method to define mock.On call
 //   - ctx context.Context
-//   - email string
-func (_e *Querier_Expecter) GetOrCreateUser(ctx interface{}, email interface{}) *Querier_GetOrCreateUser_Call {
-	return &Querier_GetOrCreateUser_Call{Call: _e.mock.On("GetOrCreateUser", ctx, email)}
+//   - arg database.CreateAppParams
+func (_e *Querier_Expecter) CreateApp(ctx interface{}, arg interface{}) *Querier_CreateApp_Call {
+	return &Querier_CreateApp_Call{Call: _e.mock.On("CreateApp", ctx, arg)}
 }
 
-func (_c *Querier_GetOrCreateUser_Call) Run(run func(ctx context.Context, email string)) *Querier_GetOrCreateUser_Call {
+func (_c *Querier_CreateApp_Call) Run(run func(ctx context.Context, arg database.CreateAppParams)) *Querier_CreateApp_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(database.CreateAppParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetOrCreateUser_Call) Return(_a0 uuid.UUID, _a1 error) *Querier_GetOrCreateUser_Call {
+func (_c *Querier_CreateApp_Call) Return(_a0 database.App, _a1 error) *Querier_CreateApp_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetOrCreateUser_Call) RunAndReturn(run func(context.Context, string) (uuid.UUID, error)) *Querier_GetOrCreateUser_Call {
+func (_c *Querier_CreateApp_Call) RunAndReturn(run func(context.Context, database.CreateAppParams) (database.App, error)) *Querier_CreateApp_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPageViews provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetPageViews(ctx context.Context, arg database.GetPageViewsParams) ([]database.GetPageViewsRow, error) {
+// CreateAppInvitation provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateAppInvitation(ctx context.Context, arg database.CreateAppInvitationParams) (database.AppInvitation, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPageViews")
+		panic("no return value specified for CreateAppInvitation")
 	}
 
-	var r0 []database.GetPageViewsRow
+	var r0 database.AppInvitation
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetPageViewsParams) ([]database.GetPageViewsRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppInvitationParams) (database.AppInvitation, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetPageViewsParams) []database.GetPageViewsRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateAppInvitationParams) database.AppInvitation); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetPageViewsRow)
-		}
+		r0 = ret.Get(0).(database.AppInvitation)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetPageViewsParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateAppInvitationParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -673,57 +677,55 @@ func (_m *Querier) GetPageViews(ctx context.Context, arg database.GetPageViewsPa
 	return r0, r1
 }
 
-// Querier_GetPageViews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageViews'
-type Querier_GetPageViews_Call struct {
+// Querier_CreateAppInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAppInvitation'
+type Querier_CreateAppInvitation_Call struct {
 	*mock.Call
 }
 
-// GetPageViews is a helper method to define mock.On call
+// CreateAppInvitation is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetPageViewsParams
-func (_e *Querier_Expecter) GetPageViews(ctx interface{}, arg interface{}) *Querier_GetPageViews_Call {
-	return &Querier_GetPageViews_Call{Call: _e.mock.On("GetPageViews", ctx, arg)}
+//   - arg database.CreateAppInvitationParams
+func (_e *Querier_Expecter) CreateAppInvitation(ctx interface{}, arg interface{}) *Querier_CreateAppInvitation_Call {
+	return &Querier_CreateAppInvitation_Call{Call: _e.mock.On("CreateAppInvitation", ctx, arg)}
 }
 
-func (_c *Querier_GetPageViews_Call) Run(run func(ctx context.Context, arg database.GetPageViewsParams)) *Querier_GetPageViews_Call {
+func (_c *Querier_CreateAppInvitation_Call) Run(run func(ctx context.Context, arg database.CreateAppInvitationParams)) *Querier_CreateAppInvitation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetPageViewsParams))
+		run(args[0].(context.Context), args[1].(database.CreateAppInvitationParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetPageViews_Call) Return(_a0 []database.GetPageViewsRow, _a1 error) *Querier_GetPageViews_Call {
+func (_c *Querier_CreateAppInvitation_Call) Return(_a0 database.AppInvitation, _a1 error) *Querier_CreateAppInvitation_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetPageViews_Call) RunAndReturn(run func(context.Context, database.GetPageViewsParams) ([]database.GetPageViewsRow, error)) *Querier_GetPageViews_Call {
+func (_c *Querier_CreateAppInvitation_Call) RunAndReturn(run func(context.Context, database.CreateAppInvitationParams) (database.AppInvitation, error)) *Querier_CreateAppInvitation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPages provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetPages(ctx context.Context, arg database.GetPagesParams) ([]database.GetPagesRow, error) {
+// CreateDeletion provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateDeletion(ctx context.Context, arg database.CreateDeletionParams) (database.Deletion, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPages")
+		panic("no return value specified for CreateDeletion")
 	}
 
-	var r0 []database.GetPagesRow
+	var r0 database.Deletion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetPagesParams) ([]database.GetPagesRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateDeletionParams) (database.Deletion, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetPagesParams) []database.GetPagesRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateDeletionParams) database.Deletion); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetPagesRow)
-		}
+		r0 = ret.Get(0).(database.Deletion)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetPagesParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateDeletionParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -732,57 +734,102 @@ func (_m *Querier) GetPages(ctx context.Context, arg database.GetPagesParams) ([
 	return r0, r1
 }
 
-// Querier_GetPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPages'
-type Querier_GetPages_Call struct {
+// Querier_CreateDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeletion'
+type Querier_CreateDeletion_Call struct {
 	*mock.Call
 }
 
-// GetPages is a helper method to define mock.On call
+// CreateDeletion is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetPagesParams
-func (_e *Querier_Expecter) GetPages(ctx interface{}, arg interface{}) *Querier_GetPages_Call {
-	return &Querier_GetPages_Call{Call: _e.mock.On("GetPages", ctx, arg)}
+//   - arg database.CreateDeletionParams
+func (_e *Querier_Expecter) CreateDeletion(ctx interface{}, arg interface{}) *Querier_CreateDeletion_Call {
+	return &Querier_CreateDeletion_Call{Call: _e.mock.On("CreateDeletion", ctx, arg)}
 }
 
-func (_c *Querier_GetPages_Call) Run(run func(ctx context.Context, arg database.GetPagesParams)) *Querier_GetPages_Call {
+func (_c *Querier_CreateDeletion_Call) Run(run func(ctx context.Context, arg database.CreateDeletionParams)) *Querier_CreateDeletion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetPagesParams))
+		run(args[0].(context.Context), args[1].(database.CreateDeletionParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetPages_Call) Return(_a0 []database.GetPagesRow, _a1 error) *Querier_GetPages_Call {
+func (_c *Querier_CreateDeletion_Call) Return(_a0 database.Deletion, _a1 error) *Querier_CreateDeletion_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetPages_Call) RunAndReturn(run func(context.Context, database.GetPagesParams) ([]database.GetPagesRow, error)) *Querier_GetPages_Call {
+func (_c *Querier_CreateDeletion_Call) RunAndReturn(run func(context.Context, database.CreateDeletionParams) (database.Deletion, error)) *Querier_CreateDeletion_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetReferrals provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetReferrals(ctx context.Context, arg database.GetReferralsParams) ([]database.GetReferralsRow, error) {
+// CreateEvent provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateEvent(ctx context.Context, arg database.CreateEventParams) error {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetReferrals")
+		panic("no return value specified for CreateEvent")
 	}
 
-	var r0 []database.GetReferralsRow
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateEventParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_CreateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvent'
+type Querier_CreateEvent_Call struct {
+	*mock.Call
+}
+
+// CreateEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.CreateEventParams
+func (_e *Querier_Expecter) CreateEvent(ctx interface{}, arg interface{}) *Querier_CreateEvent_Call {
+	return &Querier_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, arg)}
+}
+
+func (_c *Querier_CreateEvent_Call) Run(run func(ctx context.Context, arg database.CreateEventParams)) *Querier_CreateEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.CreateEventParams))
+	})
+	return _c
+}
+
+func (_c *Querier_CreateEvent_Call) Return(_a0 error) *Querier_CreateEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_CreateEvent_Call) RunAndReturn(run func(context.Context, database.CreateEventParams) error) *Querier_CreateEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateImport provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateImport(ctx context.Context, arg database.CreateImportParams) (database.Import, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateImport")
+	}
+
+	var r0 database.Import
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetReferralsParams) ([]database.GetReferralsRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateImportParams) (database.Import, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetReferralsParams) []database.GetReferralsRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateImportParams) database.Import); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetReferralsRow)
-		}
+		r0 = ret.Get(0).(database.Import)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetReferralsParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateImportParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -791,57 +838,55 @@ func (_m *Querier) GetReferrals(ctx context.Context, arg database.GetReferralsPa
 	return r0, r1
 }
 
-// Querier_GetReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferrals'
-type Querier_GetReferrals_Call struct {
+// Querier_CreateImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImport'
+type Querier_CreateImport_Call struct {
 	*mock.Call
 }
 
-// GetReferrals is a helper method to define mock.On call
+// CreateImport is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetReferralsParams
-func (_e *Querier_Expecter) GetReferrals(ctx interface{}, arg interface{}) *Querier_GetReferrals_Call {
-	return &Querier_GetReferrals_Call{Call: _e.mock.On("GetReferrals", ctx, arg)}
+//   - arg database.CreateImportParams
+func (_e *Querier_Expecter) CreateImport(ctx interface{}, arg interface{}) *Querier_CreateImport_Call {
+	return &Querier_CreateImport_Call{Call: _e.mock.On("CreateImport", ctx, arg)}
 }
 
-func (_c *Querier_GetReferrals_Call) Run(run func(ctx context.Context, arg database.GetReferralsParams)) *Querier_GetReferrals_Call {
+func (_c *Querier_CreateImport_Call) Run(run func(ctx context.Context, arg database.CreateImportParams)) *Querier_CreateImport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetReferralsParams))
+		run(args[0].(context.Context), args[1].(database.CreateImportParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetReferrals_Call) Return(_a0 []database.GetReferralsRow, _a1 error) *Querier_GetReferrals_Call {
+func (_c *Querier_CreateImport_Call) Return(_a0 database.Import, _a1 error) *Querier_CreateImport_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetReferrals_Call) RunAndReturn(run func(context.Context, database.GetReferralsParams) ([]database.GetReferralsRow, error)) *Querier_GetReferrals_Call {
+func (_c *Querier_CreateImport_Call) RunAndReturn(run func(context.Context, database.CreateImportParams) (database.Import, error)) *Querier_CreateImport_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetVisitors provides a mock function with given fields: ctx, arg
-func (_m *Querier) GetVisitors(ctx context.Context, arg database.GetVisitorsParams) ([]database.GetVisitorsRow, error) {
+// CreateOrganization provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateOrganization(ctx context.Context, arg database.CreateOrganizationParams) (database.Organization, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetVisitors")
+		panic("no return value specified for CreateOrganization")
 	}
 
-	var r0 []database.GetVisitorsRow
+	var r0 database.Organization
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetVisitorsParams) ([]database.GetVisitorsRow, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateOrganizationParams) (database.Organization, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.GetVisitorsParams) []database.GetVisitorsRow); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateOrganizationParams) database.Organization); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.GetVisitorsRow)
-		}
+		r0 = ret.Get(0).(database.Organization)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.GetVisitorsParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateOrganizationParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -850,55 +895,55 @@ func (_m *Querier) GetVisitors(ctx context.Context, arg database.GetVisitorsPara
 	return r0, r1
 }
 
-// Querier_GetVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVisitors'
-type Querier_GetVisitors_Call struct {
+// Querier_CreateOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrganization'
+type Querier_CreateOrganization_Call struct {
 	*mock.Call
 }
 
-// GetVisitors is a helper method to define mock.On call
+// CreateOrganization is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.GetVisitorsParams
-func (_e *Querier_Expecter) GetVisitors(ctx interface{}, arg interface{}) *Querier_GetVisitors_Call {
-	return &Querier_GetVisitors_Call{Call: _e.mock.On("GetVisitors", ctx, arg)}
+//   - arg database.CreateOrganizationParams
+func (_e *Querier_Expecter) CreateOrganization(ctx interface{}, arg interface{}) *Querier_CreateOrganization_Call {
+	return &Querier_CreateOrganization_Call{Call: _e.mock.On("CreateOrganization", ctx, arg)}
 }
 
-func (_c *Querier_GetVisitors_Call) Run(run func(ctx context.Context, arg database.GetVisitorsParams)) *Querier_GetVisitors_Call {
+func (_c *Querier_CreateOrganization_Call) Run(run func(ctx context.Context, arg database.CreateOrganizationParams)) *Querier_CreateOrganization_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.GetVisitorsParams))
+		run(args[0].(context.Context), args[1].(database.CreateOrganizationParams))
 	})
 	return _c
 }
 
-func (_c *Querier_GetVisitors_Call) Return(_a0 []database.GetVisitorsRow, _a1 error) *Querier_GetVisitors_Call {
+func (_c *Querier_CreateOrganization_Call) Return(_a0 database.Organization, _a1 error) *Querier_CreateOrganization_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_GetVisitors_Call) RunAndReturn(run func(context.Context, database.GetVisitorsParams) ([]database.GetVisitorsRow, error)) *Querier_GetVisitors_Call {
+func (_c *Querier_CreateOrganization_Call) RunAndReturn(run func(context.Context, database.CreateOrganizationParams) (database.Organization, error)) *Querier_CreateOrganization_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateApp provides a mock function with given fields: ctx, arg
-func (_m *Querier) UpdateApp(ctx context.Context, arg database.UpdateAppParams) (database.App, error) {
+// CreateShare provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateShare(ctx context.Context, arg database.CreateShareParams) (database.Share, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateApp")
+		panic("no return value specified for CreateShare")
 	}
 
-	var r0 database.App
+	var r0 database.Share
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppParams) (database.App, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateShareParams) (database.Share, error)); ok {
 		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppParams) database.App); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, database.CreateShareParams) database.Share); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(database.App)
+		r0 = ret.Get(0).(database.Share)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, database.UpdateAppParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, database.CreateShareParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -907,31 +952,4137 @@ func (_m *Querier) UpdateApp(ctx context.Context, arg database.UpdateAppParams)
 	return r0, r1
 }
 
-// Querier_UpdateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateApp'
-type Querier_UpdateApp_Call struct {
+// Querier_CreateShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShare'
+type Querier_CreateShare_Call struct {
 	*mock.Call
 }
 
-// UpdateApp is a helper method to define mock.On call
+// CreateShare is a helper method to define mock.On call
 //   - ctx context.Context
-//   - arg database.UpdateAppParams
-func (_e *Querier_Expecter) UpdateApp(ctx interface{}, arg interface{}) *Querier_UpdateApp_Call {
-	return &Querier_UpdateApp_Call{Call: _e.mock.On("UpdateApp", ctx, arg)}
+//   - arg database.CreateShareParams
+func (_e *Querier_Expecter) CreateShare(ctx interface{}, arg interface{}) *Querier_CreateShare_Call {
+	return &Querier_CreateShare_Call{Call: _e.mock.On("CreateShare", ctx, arg)}
 }
 
-func (_c *Querier_UpdateApp_Call) Run(run func(ctx context.Context, arg database.UpdateAppParams)) *Querier_UpdateApp_Call {
+func (_c *Querier_CreateShare_Call) Run(run func(ctx context.Context, arg database.CreateShareParams)) *Querier_CreateShare_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(database.UpdateAppParams))
+		run(args[0].(context.Context), args[1].(database.CreateShareParams))
 	})
 	return _c
 }
 
-func (_c *Querier_UpdateApp_Call) Return(_a0 database.App, _a1 error) *Querier_UpdateApp_Call {
+func (_c *Querier_CreateShare_Call) Return(_a0 database.Share, _a1 error) *Querier_CreateShare_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Querier_UpdateApp_Call) RunAndReturn(run func(context.Context, database.UpdateAppParams) (database.App, error)) *Querier_UpdateApp_Call {
+func (_c *Querier_CreateShare_Call) RunAndReturn(run func(context.Context, database.CreateShareParams) (database.Share, error)) *Querier_CreateShare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteApp provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) DeleteApp(ctx context.Context, trackingID uuid.UUID) error {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteApp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_DeleteApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteApp'
+type Querier_DeleteApp_Call struct {
+	*mock.Call
+}
+
+// DeleteApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) DeleteApp(ctx interface{}, trackingID interface{}) *Querier_DeleteApp_Call {
+	return &Querier_DeleteApp_Call{Call: _e.mock.On("DeleteApp", ctx, trackingID)}
+}
+
+func (_c *Querier_DeleteApp_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_DeleteApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteApp_Call) Return(_a0 error) *Querier_DeleteApp_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_DeleteApp_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *Querier_DeleteApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAppInvitation provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteAppInvitation(ctx context.Context, arg database.DeleteAppInvitationParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAppInvitation")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteAppInvitationParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteAppInvitationParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteAppInvitationParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteAppInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAppInvitation'
+type Querier_DeleteAppInvitation_Call struct {
+	*mock.Call
+}
+
+// DeleteAppInvitation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteAppInvitationParams
+func (_e *Querier_Expecter) DeleteAppInvitation(ctx interface{}, arg interface{}) *Querier_DeleteAppInvitation_Call {
+	return &Querier_DeleteAppInvitation_Call{Call: _e.mock.On("DeleteAppInvitation", ctx, arg)}
+}
+
+func (_c *Querier_DeleteAppInvitation_Call) Run(run func(ctx context.Context, arg database.DeleteAppInvitationParams)) *Querier_DeleteAppInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteAppInvitationParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteAppInvitation_Call) Return(_a0 int64, _a1 error) *Querier_DeleteAppInvitation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteAppInvitation_Call) RunAndReturn(run func(context.Context, database.DeleteAppInvitationParams) (int64, error)) *Querier_DeleteAppInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAppMember provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteAppMember(ctx context.Context, arg database.DeleteAppMemberParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAppMember")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteAppMemberParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteAppMemberParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteAppMemberParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteAppMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAppMember'
+type Querier_DeleteAppMember_Call struct {
+	*mock.Call
+}
+
+// DeleteAppMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteAppMemberParams
+func (_e *Querier_Expecter) DeleteAppMember(ctx interface{}, arg interface{}) *Querier_DeleteAppMember_Call {
+	return &Querier_DeleteAppMember_Call{Call: _e.mock.On("DeleteAppMember", ctx, arg)}
+}
+
+func (_c *Querier_DeleteAppMember_Call) Run(run func(ctx context.Context, arg database.DeleteAppMemberParams)) *Querier_DeleteAppMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteAppMemberParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteAppMember_Call) Return(_a0 int64, _a1 error) *Querier_DeleteAppMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteAppMember_Call) RunAndReturn(run func(context.Context, database.DeleteAppMemberParams) (int64, error)) *Querier_DeleteAppMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAppSketches provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteAppSketches(ctx context.Context, arg database.DeleteAppSketchesParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAppSketches")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteAppSketchesParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_DeleteAppSketches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAppSketches'
+type Querier_DeleteAppSketches_Call struct {
+	*mock.Call
+}
+
+// DeleteAppSketches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteAppSketchesParams
+func (_e *Querier_Expecter) DeleteAppSketches(ctx interface{}, arg interface{}) *Querier_DeleteAppSketches_Call {
+	return &Querier_DeleteAppSketches_Call{Call: _e.mock.On("DeleteAppSketches", ctx, arg)}
+}
+
+func (_c *Querier_DeleteAppSketches_Call) Run(run func(ctx context.Context, arg database.DeleteAppSketchesParams)) *Querier_DeleteAppSketches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteAppSketchesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteAppSketches_Call) Return(_a0 error) *Querier_DeleteAppSketches_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_DeleteAppSketches_Call) RunAndReturn(run func(context.Context, database.DeleteAppSketchesParams) error) *Querier_DeleteAppSketches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredEventVisitors provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteExpiredEventVisitors(ctx context.Context, arg database.DeleteExpiredEventVisitorsParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredEventVisitors")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteExpiredEventVisitorsParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_DeleteExpiredEventVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredEventVisitors'
+type Querier_DeleteExpiredEventVisitors_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredEventVisitors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteExpiredEventVisitorsParams
+func (_e *Querier_Expecter) DeleteExpiredEventVisitors(ctx interface{}, arg interface{}) *Querier_DeleteExpiredEventVisitors_Call {
+	return &Querier_DeleteExpiredEventVisitors_Call{Call: _e.mock.On("DeleteExpiredEventVisitors", ctx, arg)}
+}
+
+func (_c *Querier_DeleteExpiredEventVisitors_Call) Run(run func(ctx context.Context, arg database.DeleteExpiredEventVisitorsParams)) *Querier_DeleteExpiredEventVisitors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteExpiredEventVisitorsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteExpiredEventVisitors_Call) Return(_a0 error) *Querier_DeleteExpiredEventVisitors_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_DeleteExpiredEventVisitors_Call) RunAndReturn(run func(context.Context, database.DeleteExpiredEventVisitorsParams) error) *Querier_DeleteExpiredEventVisitors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredEvents provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteExpiredEvents(ctx context.Context, arg database.DeleteExpiredEventsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteExpiredEventsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteExpiredEventsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteExpiredEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteExpiredEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredEvents'
+type Querier_DeleteExpiredEvents_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteExpiredEventsParams
+func (_e *Querier_Expecter) DeleteExpiredEvents(ctx interface{}, arg interface{}) *Querier_DeleteExpiredEvents_Call {
+	return &Querier_DeleteExpiredEvents_Call{Call: _e.mock.On("DeleteExpiredEvents", ctx, arg)}
+}
+
+func (_c *Querier_DeleteExpiredEvents_Call) Run(run func(ctx context.Context, arg database.DeleteExpiredEventsParams)) *Querier_DeleteExpiredEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteExpiredEventsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteExpiredEvents_Call) Return(_a0 int64, _a1 error) *Querier_DeleteExpiredEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteExpiredEvents_Call) RunAndReturn(run func(context.Context, database.DeleteExpiredEventsParams) (int64, error)) *Querier_DeleteExpiredEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteImportAggregates provides a mock function with given fields: ctx, importID
+func (_m *Querier) DeleteImportAggregates(ctx context.Context, importID uuid.UUID) error {
+	ret := _m.Called(ctx, importID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImportAggregates")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, importID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_DeleteImportAggregates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteImportAggregates'
+type Querier_DeleteImportAggregates_Call struct {
+	*mock.Call
+}
+
+// DeleteImportAggregates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - importID uuid.UUID
+func (_e *Querier_Expecter) DeleteImportAggregates(ctx interface{}, importID interface{}) *Querier_DeleteImportAggregates_Call {
+	return &Querier_DeleteImportAggregates_Call{Call: _e.mock.On("DeleteImportAggregates", ctx, importID)}
+}
+
+func (_c *Querier_DeleteImportAggregates_Call) Run(run func(ctx context.Context, importID uuid.UUID)) *Querier_DeleteImportAggregates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteImportAggregates_Call) Return(_a0 error) *Querier_DeleteImportAggregates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_DeleteImportAggregates_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *Querier_DeleteImportAggregates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteImportEvents provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteImportEvents(ctx context.Context, arg database.DeleteImportEventsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImportEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteImportEventsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteImportEventsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteImportEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteImportEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteImportEvents'
+type Querier_DeleteImportEvents_Call struct {
+	*mock.Call
+}
+
+// DeleteImportEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteImportEventsParams
+func (_e *Querier_Expecter) DeleteImportEvents(ctx interface{}, arg interface{}) *Querier_DeleteImportEvents_Call {
+	return &Querier_DeleteImportEvents_Call{Call: _e.mock.On("DeleteImportEvents", ctx, arg)}
+}
+
+func (_c *Querier_DeleteImportEvents_Call) Run(run func(ctx context.Context, arg database.DeleteImportEventsParams)) *Querier_DeleteImportEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteImportEventsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteImportEvents_Call) Return(_a0 int64, _a1 error) *Querier_DeleteImportEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteImportEvents_Call) RunAndReturn(run func(context.Context, database.DeleteImportEventsParams) (int64, error)) *Querier_DeleteImportEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMatchingEvents provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteMatchingEvents(ctx context.Context, arg database.DeleteMatchingEventsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMatchingEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteMatchingEventsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteMatchingEventsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteMatchingEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteMatchingEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMatchingEvents'
+type Querier_DeleteMatchingEvents_Call struct {
+	*mock.Call
+}
+
+// DeleteMatchingEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteMatchingEventsParams
+func (_e *Querier_Expecter) DeleteMatchingEvents(ctx interface{}, arg interface{}) *Querier_DeleteMatchingEvents_Call {
+	return &Querier_DeleteMatchingEvents_Call{Call: _e.mock.On("DeleteMatchingEvents", ctx, arg)}
+}
+
+func (_c *Querier_DeleteMatchingEvents_Call) Run(run func(ctx context.Context, arg database.DeleteMatchingEventsParams)) *Querier_DeleteMatchingEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteMatchingEventsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteMatchingEvents_Call) Return(_a0 int64, _a1 error) *Querier_DeleteMatchingEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteMatchingEvents_Call) RunAndReturn(run func(context.Context, database.DeleteMatchingEventsParams) (int64, error)) *Querier_DeleteMatchingEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteOrganizationMember provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) (uuid.UUID, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOrganizationMember")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteOrganizationMemberParams) (uuid.UUID, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.DeleteOrganizationMemberParams) uuid.UUID); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.DeleteOrganizationMemberParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DeleteOrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteOrganizationMember'
+type Querier_DeleteOrganizationMember_Call struct {
+	*mock.Call
+}
+
+// DeleteOrganizationMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.DeleteOrganizationMemberParams
+func (_e *Querier_Expecter) DeleteOrganizationMember(ctx interface{}, arg interface{}) *Querier_DeleteOrganizationMember_Call {
+	return &Querier_DeleteOrganizationMember_Call{Call: _e.mock.On("DeleteOrganizationMember", ctx, arg)}
+}
+
+func (_c *Querier_DeleteOrganizationMember_Call) Run(run func(ctx context.Context, arg database.DeleteOrganizationMemberParams)) *Querier_DeleteOrganizationMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.DeleteOrganizationMemberParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DeleteOrganizationMember_Call) Return(_a0 uuid.UUID, _a1 error) *Querier_DeleteOrganizationMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DeleteOrganizationMember_Call) RunAndReturn(run func(context.Context, database.DeleteOrganizationMemberParams) (uuid.UUID, error)) *Querier_DeleteOrganizationMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FinishDeletion provides a mock function with given fields: ctx, arg
+func (_m *Querier) FinishDeletion(ctx context.Context, arg database.FinishDeletionParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.FinishDeletionParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_FinishDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishDeletion'
+type Querier_FinishDeletion_Call struct {
+	*mock.Call
+}
+
+// FinishDeletion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.FinishDeletionParams
+func (_e *Querier_Expecter) FinishDeletion(ctx interface{}, arg interface{}) *Querier_FinishDeletion_Call {
+	return &Querier_FinishDeletion_Call{Call: _e.mock.On("FinishDeletion", ctx, arg)}
+}
+
+func (_c *Querier_FinishDeletion_Call) Run(run func(ctx context.Context, arg database.FinishDeletionParams)) *Querier_FinishDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.FinishDeletionParams))
+	})
+	return _c
+}
+
+func (_c *Querier_FinishDeletion_Call) Return(_a0 error) *Querier_FinishDeletion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_FinishDeletion_Call) RunAndReturn(run func(context.Context, database.FinishDeletionParams) error) *Querier_FinishDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FinishImport provides a mock function with given fields: ctx, arg
+func (_m *Querier) FinishImport(ctx context.Context, arg database.FinishImportParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishImport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.FinishImportParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_FinishImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FinishImport'
+type Querier_FinishImport_Call struct {
+	*mock.Call
+}
+
+// FinishImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.FinishImportParams
+func (_e *Querier_Expecter) FinishImport(ctx interface{}, arg interface{}) *Querier_FinishImport_Call {
+	return &Querier_FinishImport_Call{Call: _e.mock.On("FinishImport", ctx, arg)}
+}
+
+func (_c *Querier_FinishImport_Call) Run(run func(ctx context.Context, arg database.FinishImportParams)) *Querier_FinishImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.FinishImportParams))
+	})
+	return _c
+}
+
+func (_c *Querier_FinishImport_Call) Return(_a0 error) *Querier_FinishImport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_FinishImport_Call) RunAndReturn(run func(context.Context, database.FinishImportParams) error) *Querier_FinishImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPITokenByHash provides a mock function with given fields: ctx, tokenHash
+func (_m *Querier) GetAPITokenByHash(ctx context.Context, tokenHash string) (database.ApiToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPITokenByHash")
+	}
+
+	var r0 database.ApiToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (database.ApiToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) database.ApiToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(database.ApiToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAPITokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPITokenByHash'
+type Querier_GetAPITokenByHash_Call struct {
+	*mock.Call
+}
+
+// GetAPITokenByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *Querier_Expecter) GetAPITokenByHash(ctx interface{}, tokenHash interface{}) *Querier_GetAPITokenByHash_Call {
+	return &Querier_GetAPITokenByHash_Call{Call: _e.mock.On("GetAPITokenByHash", ctx, tokenHash)}
+}
+
+func (_c *Querier_GetAPITokenByHash_Call) Run(run func(ctx context.Context, tokenHash string)) *Querier_GetAPITokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAPITokenByHash_Call) Return(_a0 database.ApiToken, _a1 error) *Querier_GetAPITokenByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAPITokenByHash_Call) RunAndReturn(run func(context.Context, string) (database.ApiToken, error)) *Querier_GetAPITokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPITokens provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetAPITokens(ctx context.Context, userID uuid.UUID) ([]database.ApiToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPITokens")
+	}
+
+	var r0 []database.ApiToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.ApiToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.ApiToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.ApiToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAPITokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPITokens'
+type Querier_GetAPITokens_Call struct {
+	*mock.Call
+}
+
+// GetAPITokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetAPITokens(ctx interface{}, userID interface{}) *Querier_GetAPITokens_Call {
+	return &Querier_GetAPITokens_Call{Call: _e.mock.On("GetAPITokens", ctx, userID)}
+}
+
+func (_c *Querier_GetAPITokens_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetAPITokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAPITokens_Call) Return(_a0 []database.ApiToken, _a1 error) *Querier_GetAPITokens_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAPITokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.ApiToken, error)) *Querier_GetAPITokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActiveVisitorIDs provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetActiveVisitorIDs(ctx context.Context, arg database.GetActiveVisitorIDsParams) ([]database.GetActiveVisitorIDsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveVisitorIDs")
+	}
+
+	var r0 []database.GetActiveVisitorIDsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetActiveVisitorIDsParams) ([]database.GetActiveVisitorIDsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetActiveVisitorIDsParams) []database.GetActiveVisitorIDsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetActiveVisitorIDsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetActiveVisitorIDsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetActiveVisitorIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActiveVisitorIDs'
+type Querier_GetActiveVisitorIDs_Call struct {
+	*mock.Call
+}
+
+// GetActiveVisitorIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetActiveVisitorIDsParams
+func (_e *Querier_Expecter) GetActiveVisitorIDs(ctx interface{}, arg interface{}) *Querier_GetActiveVisitorIDs_Call {
+	return &Querier_GetActiveVisitorIDs_Call{Call: _e.mock.On("GetActiveVisitorIDs", ctx, arg)}
+}
+
+func (_c *Querier_GetActiveVisitorIDs_Call) Run(run func(ctx context.Context, arg database.GetActiveVisitorIDsParams)) *Querier_GetActiveVisitorIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetActiveVisitorIDsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetActiveVisitorIDs_Call) Return(_a0 []database.GetActiveVisitorIDsRow, _a1 error) *Querier_GetActiveVisitorIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetActiveVisitorIDs_Call) RunAndReturn(run func(context.Context, database.GetActiveVisitorIDsParams) ([]database.GetActiveVisitorIDsRow, error)) *Querier_GetActiveVisitorIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetActiveVisitors provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetActiveVisitors(ctx context.Context, arg database.GetActiveVisitorsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveVisitors")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetActiveVisitorsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetActiveVisitorsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetActiveVisitorsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetActiveVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetActiveVisitors'
+type Querier_GetActiveVisitors_Call struct {
+	*mock.Call
+}
+
+// GetActiveVisitors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetActiveVisitorsParams
+func (_e *Querier_Expecter) GetActiveVisitors(ctx interface{}, arg interface{}) *Querier_GetActiveVisitors_Call {
+	return &Querier_GetActiveVisitors_Call{Call: _e.mock.On("GetActiveVisitors", ctx, arg)}
+}
+
+func (_c *Querier_GetActiveVisitors_Call) Run(run func(ctx context.Context, arg database.GetActiveVisitorsParams)) *Querier_GetActiveVisitors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetActiveVisitorsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetActiveVisitors_Call) Return(_a0 int64, _a1 error) *Querier_GetActiveVisitors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetActiveVisitors_Call) RunAndReturn(run func(context.Context, database.GetActiveVisitorsParams) (int64, error)) *Querier_GetActiveVisitors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppByTrackingID provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetAppByTrackingID(ctx context.Context, trackingID uuid.UUID) (database.App, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppByTrackingID")
+	}
+
+	var r0 database.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (database.App, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) database.App); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		r0 = ret.Get(0).(database.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAppByTrackingID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppByTrackingID'
+type Querier_GetAppByTrackingID_Call struct {
+	*mock.Call
+}
+
+// GetAppByTrackingID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetAppByTrackingID(ctx interface{}, trackingID interface{}) *Querier_GetAppByTrackingID_Call {
+	return &Querier_GetAppByTrackingID_Call{Call: _e.mock.On("GetAppByTrackingID", ctx, trackingID)}
+}
+
+func (_c *Querier_GetAppByTrackingID_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetAppByTrackingID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAppByTrackingID_Call) Return(_a0 database.App, _a1 error) *Querier_GetAppByTrackingID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAppByTrackingID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (database.App, error)) *Querier_GetAppByTrackingID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppInvitations provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetAppInvitations(ctx context.Context, trackingID uuid.UUID) ([]database.AppInvitation, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppInvitations")
+	}
+
+	var r0 []database.AppInvitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.AppInvitation, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.AppInvitation); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.AppInvitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAppInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppInvitations'
+type Querier_GetAppInvitations_Call struct {
+	*mock.Call
+}
+
+// GetAppInvitations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetAppInvitations(ctx interface{}, trackingID interface{}) *Querier_GetAppInvitations_Call {
+	return &Querier_GetAppInvitations_Call{Call: _e.mock.On("GetAppInvitations", ctx, trackingID)}
+}
+
+func (_c *Querier_GetAppInvitations_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetAppInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAppInvitations_Call) Return(_a0 []database.AppInvitation, _a1 error) *Querier_GetAppInvitations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAppInvitations_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.AppInvitation, error)) *Querier_GetAppInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppMember provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetAppMember(ctx context.Context, arg database.GetAppMemberParams) (string, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppMember")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetAppMemberParams) (string, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetAppMemberParams) string); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetAppMemberParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAppMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppMember'
+type Querier_GetAppMember_Call struct {
+	*mock.Call
+}
+
+// GetAppMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetAppMemberParams
+func (_e *Querier_Expecter) GetAppMember(ctx interface{}, arg interface{}) *Querier_GetAppMember_Call {
+	return &Querier_GetAppMember_Call{Call: _e.mock.On("GetAppMember", ctx, arg)}
+}
+
+func (_c *Querier_GetAppMember_Call) Run(run func(ctx context.Context, arg database.GetAppMemberParams)) *Querier_GetAppMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetAppMemberParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAppMember_Call) Return(_a0 string, _a1 error) *Querier_GetAppMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAppMember_Call) RunAndReturn(run func(context.Context, database.GetAppMemberParams) (string, error)) *Querier_GetAppMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppMembers provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetAppMembers(ctx context.Context, trackingID uuid.UUID) ([]database.GetAppMembersRow, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppMembers")
+	}
+
+	var r0 []database.GetAppMembersRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.GetAppMembersRow, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.GetAppMembersRow); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetAppMembersRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAppMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppMembers'
+type Querier_GetAppMembers_Call struct {
+	*mock.Call
+}
+
+// GetAppMembers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetAppMembers(ctx interface{}, trackingID interface{}) *Querier_GetAppMembers_Call {
+	return &Querier_GetAppMembers_Call{Call: _e.mock.On("GetAppMembers", ctx, trackingID)}
+}
+
+func (_c *Querier_GetAppMembers_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetAppMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAppMembers_Call) Return(_a0 []database.GetAppMembersRow, _a1 error) *Querier_GetAppMembers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAppMembers_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.GetAppMembersRow, error)) *Querier_GetAppMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAppSketchInputs provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetAppSketchInputs(ctx context.Context, arg database.GetAppSketchInputsParams) ([]database.GetAppSketchInputsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAppSketchInputs")
+	}
+
+	var r0 []database.GetAppSketchInputsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetAppSketchInputsParams) ([]database.GetAppSketchInputsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetAppSketchInputsParams) []database.GetAppSketchInputsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetAppSketchInputsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetAppSketchInputsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetAppSketchInputs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAppSketchInputs'
+type Querier_GetAppSketchInputs_Call struct {
+	*mock.Call
+}
+
+// GetAppSketchInputs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetAppSketchInputsParams
+func (_e *Querier_Expecter) GetAppSketchInputs(ctx interface{}, arg interface{}) *Querier_GetAppSketchInputs_Call {
+	return &Querier_GetAppSketchInputs_Call{Call: _e.mock.On("GetAppSketchInputs", ctx, arg)}
+}
+
+func (_c *Querier_GetAppSketchInputs_Call) Run(run func(ctx context.Context, arg database.GetAppSketchInputsParams)) *Querier_GetAppSketchInputs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetAppSketchInputsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetAppSketchInputs_Call) Return(_a0 []database.GetAppSketchInputsRow, _a1 error) *Querier_GetAppSketchInputs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetAppSketchInputs_Call) RunAndReturn(run func(context.Context, database.GetAppSketchInputsParams) ([]database.GetAppSketchInputsRow, error)) *Querier_GetAppSketchInputs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetApps provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetApps(ctx context.Context, userID uuid.UUID) ([]database.GetAppsRow, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetApps")
+	}
+
+	var r0 []database.GetAppsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.GetAppsRow, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.GetAppsRow); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetAppsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetApps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetApps'
+type Querier_GetApps_Call struct {
+	*mock.Call
+}
+
+// GetApps is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetApps(ctx interface{}, userID interface{}) *Querier_GetApps_Call {
+	return &Querier_GetApps_Call{Call: _e.mock.On("GetApps", ctx, userID)}
+}
+
+func (_c *Querier_GetApps_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetApps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetApps_Call) Return(_a0 []database.GetAppsRow, _a1 error) *Querier_GetApps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetApps_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.GetAppsRow, error)) *Querier_GetApps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBrowsers provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetBrowsers(ctx context.Context, arg database.GetBrowsersParams) ([]database.GetBrowsersRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBrowsers")
+	}
+
+	var r0 []database.GetBrowsersRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetBrowsersParams) ([]database.GetBrowsersRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetBrowsersParams) []database.GetBrowsersRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetBrowsersRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetBrowsersParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetBrowsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBrowsers'
+type Querier_GetBrowsers_Call struct {
+	*mock.Call
+}
+
+// GetBrowsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetBrowsersParams
+func (_e *Querier_Expecter) GetBrowsers(ctx interface{}, arg interface{}) *Querier_GetBrowsers_Call {
+	return &Querier_GetBrowsers_Call{Call: _e.mock.On("GetBrowsers", ctx, arg)}
+}
+
+func (_c *Querier_GetBrowsers_Call) Run(run func(ctx context.Context, arg database.GetBrowsersParams)) *Querier_GetBrowsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetBrowsersParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetBrowsers_Call) Return(_a0 []database.GetBrowsersRow, _a1 error) *Querier_GetBrowsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetBrowsers_Call) RunAndReturn(run func(context.Context, database.GetBrowsersParams) ([]database.GetBrowsersRow, error)) *Querier_GetBrowsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCountries provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetCountries(ctx context.Context, arg database.GetCountriesParams) ([]database.GetCountriesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCountries")
+	}
+
+	var r0 []database.GetCountriesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetCountriesParams) ([]database.GetCountriesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetCountriesParams) []database.GetCountriesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetCountriesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetCountriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetCountries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountries'
+type Querier_GetCountries_Call struct {
+	*mock.Call
+}
+
+// GetCountries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetCountriesParams
+func (_e *Querier_Expecter) GetCountries(ctx interface{}, arg interface{}) *Querier_GetCountries_Call {
+	return &Querier_GetCountries_Call{Call: _e.mock.On("GetCountries", ctx, arg)}
+}
+
+func (_c *Querier_GetCountries_Call) Run(run func(ctx context.Context, arg database.GetCountriesParams)) *Querier_GetCountries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetCountriesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetCountries_Call) Return(_a0 []database.GetCountriesRow, _a1 error) *Querier_GetCountries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetCountries_Call) RunAndReturn(run func(context.Context, database.GetCountriesParams) ([]database.GetCountriesRow, error)) *Querier_GetCountries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeletion provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetDeletion(ctx context.Context, arg database.GetDeletionParams) (database.Deletion, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletion")
+	}
+
+	var r0 database.Deletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetDeletionParams) (database.Deletion, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetDeletionParams) database.Deletion); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.Deletion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetDeletionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeletion'
+type Querier_GetDeletion_Call struct {
+	*mock.Call
+}
+
+// GetDeletion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetDeletionParams
+func (_e *Querier_Expecter) GetDeletion(ctx interface{}, arg interface{}) *Querier_GetDeletion_Call {
+	return &Querier_GetDeletion_Call{Call: _e.mock.On("GetDeletion", ctx, arg)}
+}
+
+func (_c *Querier_GetDeletion_Call) Run(run func(ctx context.Context, arg database.GetDeletionParams)) *Querier_GetDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetDeletionParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetDeletion_Call) Return(_a0 database.Deletion, _a1 error) *Querier_GetDeletion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetDeletion_Call) RunAndReturn(run func(context.Context, database.GetDeletionParams) (database.Deletion, error)) *Querier_GetDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeletions provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetDeletions(ctx context.Context, trackingID uuid.UUID) ([]database.Deletion, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletions")
+	}
+
+	var r0 []database.Deletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.Deletion, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.Deletion); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Deletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetDeletions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeletions'
+type Querier_GetDeletions_Call struct {
+	*mock.Call
+}
+
+// GetDeletions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetDeletions(ctx interface{}, trackingID interface{}) *Querier_GetDeletions_Call {
+	return &Querier_GetDeletions_Call{Call: _e.mock.On("GetDeletions", ctx, trackingID)}
+}
+
+func (_c *Querier_GetDeletions_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetDeletions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetDeletions_Call) Return(_a0 []database.Deletion, _a1 error) *Querier_GetDeletions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetDeletions_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.Deletion, error)) *Querier_GetDeletions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDevices provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetDevices(ctx context.Context, arg database.GetDevicesParams) ([]database.GetDevicesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDevices")
+	}
+
+	var r0 []database.GetDevicesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetDevicesParams) ([]database.GetDevicesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetDevicesParams) []database.GetDevicesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetDevicesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetDevicesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
+type Querier_GetDevices_Call struct {
+	*mock.Call
+}
+
+// GetDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetDevicesParams
+func (_e *Querier_Expecter) GetDevices(ctx interface{}, arg interface{}) *Querier_GetDevices_Call {
+	return &Querier_GetDevices_Call{Call: _e.mock.On("GetDevices", ctx, arg)}
+}
+
+func (_c *Querier_GetDevices_Call) Run(run func(ctx context.Context, arg database.GetDevicesParams)) *Querier_GetDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetDevicesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetDevices_Call) Return(_a0 []database.GetDevicesRow, _a1 error) *Querier_GetDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetDevices_Call) RunAndReturn(run func(context.Context, database.GetDevicesParams) ([]database.GetDevicesRow, error)) *Querier_GetDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEventSketches provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetEventSketches(ctx context.Context, arg database.GetEventSketchesParams) ([]database.GetEventSketchesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventSketches")
+	}
+
+	var r0 []database.GetEventSketchesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetEventSketchesParams) ([]database.GetEventSketchesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetEventSketchesParams) []database.GetEventSketchesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetEventSketchesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetEventSketchesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetEventSketches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventSketches'
+type Querier_GetEventSketches_Call struct {
+	*mock.Call
+}
+
+// GetEventSketches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetEventSketchesParams
+func (_e *Querier_Expecter) GetEventSketches(ctx interface{}, arg interface{}) *Querier_GetEventSketches_Call {
+	return &Querier_GetEventSketches_Call{Call: _e.mock.On("GetEventSketches", ctx, arg)}
+}
+
+func (_c *Querier_GetEventSketches_Call) Run(run func(ctx context.Context, arg database.GetEventSketchesParams)) *Querier_GetEventSketches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetEventSketchesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetEventSketches_Call) Return(_a0 []database.GetEventSketchesRow, _a1 error) *Querier_GetEventSketches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetEventSketches_Call) RunAndReturn(run func(context.Context, database.GetEventSketchesParams) ([]database.GetEventSketchesRow, error)) *Querier_GetEventSketches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEventTypes provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetEventTypes(ctx context.Context, arg database.GetEventTypesParams) ([]database.GetEventTypesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventTypes")
+	}
+
+	var r0 []database.GetEventTypesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetEventTypesParams) ([]database.GetEventTypesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetEventTypesParams) []database.GetEventTypesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetEventTypesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetEventTypesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetEventTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventTypes'
+type Querier_GetEventTypes_Call struct {
+	*mock.Call
+}
+
+// GetEventTypes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetEventTypesParams
+func (_e *Querier_Expecter) GetEventTypes(ctx interface{}, arg interface{}) *Querier_GetEventTypes_Call {
+	return &Querier_GetEventTypes_Call{Call: _e.mock.On("GetEventTypes", ctx, arg)}
+}
+
+func (_c *Querier_GetEventTypes_Call) Run(run func(ctx context.Context, arg database.GetEventTypesParams)) *Querier_GetEventTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetEventTypesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetEventTypes_Call) Return(_a0 []database.GetEventTypesRow, _a1 error) *Querier_GetEventTypes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetEventTypes_Call) RunAndReturn(run func(context.Context, database.GetEventTypesParams) ([]database.GetEventTypesRow, error)) *Querier_GetEventTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImport provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetImport(ctx context.Context, arg database.GetImportParams) (database.Import, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImport")
+	}
+
+	var r0 database.Import
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportParams) (database.Import, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportParams) database.Import); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.Import)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetImportParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImport'
+type Querier_GetImport_Call struct {
+	*mock.Call
+}
+
+// GetImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetImportParams
+func (_e *Querier_Expecter) GetImport(ctx interface{}, arg interface{}) *Querier_GetImport_Call {
+	return &Querier_GetImport_Call{Call: _e.mock.On("GetImport", ctx, arg)}
+}
+
+func (_c *Querier_GetImport_Call) Run(run func(ctx context.Context, arg database.GetImportParams)) *Querier_GetImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetImportParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetImport_Call) Return(_a0 database.Import, _a1 error) *Querier_GetImport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetImport_Call) RunAndReturn(run func(context.Context, database.GetImportParams) (database.Import, error)) *Querier_GetImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImportHorizon provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetImportHorizon(ctx context.Context, arg database.GetImportHorizonParams) (sql.NullTime, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportHorizon")
+	}
+
+	var r0 sql.NullTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportHorizonParams) (sql.NullTime, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportHorizonParams) sql.NullTime); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sql.NullTime)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetImportHorizonParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetImportHorizon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportHorizon'
+type Querier_GetImportHorizon_Call struct {
+	*mock.Call
+}
+
+// GetImportHorizon is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetImportHorizonParams
+func (_e *Querier_Expecter) GetImportHorizon(ctx interface{}, arg interface{}) *Querier_GetImportHorizon_Call {
+	return &Querier_GetImportHorizon_Call{Call: _e.mock.On("GetImportHorizon", ctx, arg)}
+}
+
+func (_c *Querier_GetImportHorizon_Call) Run(run func(ctx context.Context, arg database.GetImportHorizonParams)) *Querier_GetImportHorizon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetImportHorizonParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetImportHorizon_Call) Return(_a0 sql.NullTime, _a1 error) *Querier_GetImportHorizon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetImportHorizon_Call) RunAndReturn(run func(context.Context, database.GetImportHorizonParams) (sql.NullTime, error)) *Querier_GetImportHorizon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImportedAggregates provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetImportedAggregates(ctx context.Context, arg database.GetImportedAggregatesParams) ([]database.GetImportedAggregatesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportedAggregates")
+	}
+
+	var r0 []database.GetImportedAggregatesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportedAggregatesParams) ([]database.GetImportedAggregatesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetImportedAggregatesParams) []database.GetImportedAggregatesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetImportedAggregatesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetImportedAggregatesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetImportedAggregates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImportedAggregates'
+type Querier_GetImportedAggregates_Call struct {
+	*mock.Call
+}
+
+// GetImportedAggregates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetImportedAggregatesParams
+func (_e *Querier_Expecter) GetImportedAggregates(ctx interface{}, arg interface{}) *Querier_GetImportedAggregates_Call {
+	return &Querier_GetImportedAggregates_Call{Call: _e.mock.On("GetImportedAggregates", ctx, arg)}
+}
+
+func (_c *Querier_GetImportedAggregates_Call) Run(run func(ctx context.Context, arg database.GetImportedAggregatesParams)) *Querier_GetImportedAggregates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetImportedAggregatesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetImportedAggregates_Call) Return(_a0 []database.GetImportedAggregatesRow, _a1 error) *Querier_GetImportedAggregates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetImportedAggregates_Call) RunAndReturn(run func(context.Context, database.GetImportedAggregatesParams) ([]database.GetImportedAggregatesRow, error)) *Querier_GetImportedAggregates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImports provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetImports(ctx context.Context, trackingID uuid.UUID) ([]database.Import, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImports")
+	}
+
+	var r0 []database.Import
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.Import, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.Import); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Import)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetImports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImports'
+type Querier_GetImports_Call struct {
+	*mock.Call
+}
+
+// GetImports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetImports(ctx interface{}, trackingID interface{}) *Querier_GetImports_Call {
+	return &Querier_GetImports_Call{Call: _e.mock.On("GetImports", ctx, trackingID)}
+}
+
+func (_c *Querier_GetImports_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetImports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetImports_Call) Return(_a0 []database.Import, _a1 error) *Querier_GetImports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetImports_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.Import, error)) *Querier_GetImports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOS provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetOS(ctx context.Context, arg database.GetOSParams) ([]database.GetOSRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOS")
+	}
+
+	var r0 []database.GetOSRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOSParams) ([]database.GetOSRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOSParams) []database.GetOSRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetOSRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetOSParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOS'
+type Querier_GetOS_Call struct {
+	*mock.Call
+}
+
+// GetOS is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetOSParams
+func (_e *Querier_Expecter) GetOS(ctx interface{}, arg interface{}) *Querier_GetOS_Call {
+	return &Querier_GetOS_Call{Call: _e.mock.On("GetOS", ctx, arg)}
+}
+
+func (_c *Querier_GetOS_Call) Run(run func(ctx context.Context, arg database.GetOSParams)) *Querier_GetOS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetOSParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOS_Call) Return(_a0 []database.GetOSRow, _a1 error) *Querier_GetOS_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOS_Call) RunAndReturn(run func(context.Context, database.GetOSParams) ([]database.GetOSRow, error)) *Querier_GetOS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrCreatePersonalOrganization provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetOrCreatePersonalOrganization(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreatePersonalOrganization")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (uuid.UUID, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) uuid.UUID); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOrCreatePersonalOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrCreatePersonalOrganization'
+type Querier_GetOrCreatePersonalOrganization_Call struct {
+	*mock.Call
+}
+
+// GetOrCreatePersonalOrganization is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetOrCreatePersonalOrganization(ctx interface{}, userID interface{}) *Querier_GetOrCreatePersonalOrganization_Call {
+	return &Querier_GetOrCreatePersonalOrganization_Call{Call: _e.mock.On("GetOrCreatePersonalOrganization", ctx, userID)}
+}
+
+func (_c *Querier_GetOrCreatePersonalOrganization_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetOrCreatePersonalOrganization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOrCreatePersonalOrganization_Call) Return(_a0 uuid.UUID, _a1 error) *Querier_GetOrCreatePersonalOrganization_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOrCreatePersonalOrganization_Call) RunAndReturn(run func(context.Context, uuid.UUID) (uuid.UUID, error)) *Querier_GetOrCreatePersonalOrganization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrCreateUser provides a mock function with given fields: ctx, email
+func (_m *Querier) GetOrCreateUser(ctx context.Context, email string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateUser")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOrCreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrCreateUser'
+type Querier_GetOrCreateUser_Call struct {
+	*mock.Call
+}
+
+// GetOrCreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *Querier_Expecter) GetOrCreateUser(ctx interface{}, email interface{}) *Querier_GetOrCreateUser_Call {
+	return &Querier_GetOrCreateUser_Call{Call: _e.mock.On("GetOrCreateUser", ctx, email)}
+}
+
+func (_c *Querier_GetOrCreateUser_Call) Run(run func(ctx context.Context, email string)) *Querier_GetOrCreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOrCreateUser_Call) Return(_a0 uuid.UUID, _a1 error) *Querier_GetOrCreateUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOrCreateUser_Call) RunAndReturn(run func(context.Context, string) (uuid.UUID, error)) *Querier_GetOrCreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganizationMember provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetOrganizationMember(ctx context.Context, arg database.GetOrganizationMemberParams) (string, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizationMember")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOrganizationMemberParams) (string, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOrganizationMemberParams) string); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetOrganizationMemberParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganizationMember'
+type Querier_GetOrganizationMember_Call struct {
+	*mock.Call
+}
+
+// GetOrganizationMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetOrganizationMemberParams
+func (_e *Querier_Expecter) GetOrganizationMember(ctx interface{}, arg interface{}) *Querier_GetOrganizationMember_Call {
+	return &Querier_GetOrganizationMember_Call{Call: _e.mock.On("GetOrganizationMember", ctx, arg)}
+}
+
+func (_c *Querier_GetOrganizationMember_Call) Run(run func(ctx context.Context, arg database.GetOrganizationMemberParams)) *Querier_GetOrganizationMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetOrganizationMemberParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOrganizationMember_Call) Return(_a0 string, _a1 error) *Querier_GetOrganizationMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOrganizationMember_Call) RunAndReturn(run func(context.Context, database.GetOrganizationMemberParams) (string, error)) *Querier_GetOrganizationMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganizationMembers provides a mock function with given fields: ctx, orgID
+func (_m *Querier) GetOrganizationMembers(ctx context.Context, orgID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	ret := _m.Called(ctx, orgID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizationMembers")
+	}
+
+	var r0 []database.GetOrganizationMembersRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.GetOrganizationMembersRow, error)); ok {
+		return rf(ctx, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.GetOrganizationMembersRow); ok {
+		r0 = rf(ctx, orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetOrganizationMembersRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOrganizationMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganizationMembers'
+type Querier_GetOrganizationMembers_Call struct {
+	*mock.Call
+}
+
+// GetOrganizationMembers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orgID uuid.UUID
+func (_e *Querier_Expecter) GetOrganizationMembers(ctx interface{}, orgID interface{}) *Querier_GetOrganizationMembers_Call {
+	return &Querier_GetOrganizationMembers_Call{Call: _e.mock.On("GetOrganizationMembers", ctx, orgID)}
+}
+
+func (_c *Querier_GetOrganizationMembers_Call) Run(run func(ctx context.Context, orgID uuid.UUID)) *Querier_GetOrganizationMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOrganizationMembers_Call) Return(_a0 []database.GetOrganizationMembersRow, _a1 error) *Querier_GetOrganizationMembers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOrganizationMembers_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.GetOrganizationMembersRow, error)) *Querier_GetOrganizationMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganizations provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetOrganizations(ctx context.Context, userID uuid.UUID) ([]database.GetOrganizationsRow, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizations")
+	}
+
+	var r0 []database.GetOrganizationsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.GetOrganizationsRow, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.GetOrganizationsRow); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetOrganizationsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOrganizations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganizations'
+type Querier_GetOrganizations_Call struct {
+	*mock.Call
+}
+
+// GetOrganizations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetOrganizations(ctx interface{}, userID interface{}) *Querier_GetOrganizations_Call {
+	return &Querier_GetOrganizations_Call{Call: _e.mock.On("GetOrganizations", ctx, userID)}
+}
+
+func (_c *Querier_GetOrganizations_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetOrganizations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOrganizations_Call) Return(_a0 []database.GetOrganizationsRow, _a1 error) *Querier_GetOrganizations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOrganizations_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.GetOrganizationsRow, error)) *Querier_GetOrganizations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOverview provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetOverview(ctx context.Context, arg database.GetOverviewParams) (database.GetOverviewRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverview")
+	}
+
+	var r0 database.GetOverviewRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOverviewParams) (database.GetOverviewRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetOverviewParams) database.GetOverviewRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.GetOverviewRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetOverviewParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetOverview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOverview'
+type Querier_GetOverview_Call struct {
+	*mock.Call
+}
+
+// GetOverview is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetOverviewParams
+func (_e *Querier_Expecter) GetOverview(ctx interface{}, arg interface{}) *Querier_GetOverview_Call {
+	return &Querier_GetOverview_Call{Call: _e.mock.On("GetOverview", ctx, arg)}
+}
+
+func (_c *Querier_GetOverview_Call) Run(run func(ctx context.Context, arg database.GetOverviewParams)) *Querier_GetOverview_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetOverviewParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetOverview_Call) Return(_a0 database.GetOverviewRow, _a1 error) *Querier_GetOverview_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetOverview_Call) RunAndReturn(run func(context.Context, database.GetOverviewParams) (database.GetOverviewRow, error)) *Querier_GetOverview_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPageViews provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetPageViews(ctx context.Context, arg database.GetPageViewsParams) ([]database.GetPageViewsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageViews")
+	}
+
+	var r0 []database.GetPageViewsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetPageViewsParams) ([]database.GetPageViewsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetPageViewsParams) []database.GetPageViewsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetPageViewsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetPageViewsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetPageViews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageViews'
+type Querier_GetPageViews_Call struct {
+	*mock.Call
+}
+
+// GetPageViews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetPageViewsParams
+func (_e *Querier_Expecter) GetPageViews(ctx interface{}, arg interface{}) *Querier_GetPageViews_Call {
+	return &Querier_GetPageViews_Call{Call: _e.mock.On("GetPageViews", ctx, arg)}
+}
+
+func (_c *Querier_GetPageViews_Call) Run(run func(ctx context.Context, arg database.GetPageViewsParams)) *Querier_GetPageViews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetPageViewsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetPageViews_Call) Return(_a0 []database.GetPageViewsRow, _a1 error) *Querier_GetPageViews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetPageViews_Call) RunAndReturn(run func(context.Context, database.GetPageViewsParams) ([]database.GetPageViewsRow, error)) *Querier_GetPageViews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPages provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetPages(ctx context.Context, arg database.GetPagesParams) ([]database.GetPagesRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPages")
+	}
+
+	var r0 []database.GetPagesRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetPagesParams) ([]database.GetPagesRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetPagesParams) []database.GetPagesRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetPagesRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetPagesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPages'
+type Querier_GetPages_Call struct {
+	*mock.Call
+}
+
+// GetPages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetPagesParams
+func (_e *Querier_Expecter) GetPages(ctx interface{}, arg interface{}) *Querier_GetPages_Call {
+	return &Querier_GetPages_Call{Call: _e.mock.On("GetPages", ctx, arg)}
+}
+
+func (_c *Querier_GetPages_Call) Run(run func(ctx context.Context, arg database.GetPagesParams)) *Querier_GetPages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetPagesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetPages_Call) Return(_a0 []database.GetPagesRow, _a1 error) *Querier_GetPages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetPages_Call) RunAndReturn(run func(context.Context, database.GetPagesParams) ([]database.GetPagesRow, error)) *Querier_GetPages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReferrals provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetReferrals(ctx context.Context, arg database.GetReferralsParams) ([]database.GetReferralsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReferrals")
+	}
+
+	var r0 []database.GetReferralsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetReferralsParams) ([]database.GetReferralsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetReferralsParams) []database.GetReferralsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetReferralsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetReferralsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferrals'
+type Querier_GetReferrals_Call struct {
+	*mock.Call
+}
+
+// GetReferrals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetReferralsParams
+func (_e *Querier_Expecter) GetReferrals(ctx interface{}, arg interface{}) *Querier_GetReferrals_Call {
+	return &Querier_GetReferrals_Call{Call: _e.mock.On("GetReferrals", ctx, arg)}
+}
+
+func (_c *Querier_GetReferrals_Call) Run(run func(ctx context.Context, arg database.GetReferralsParams)) *Querier_GetReferrals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetReferralsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetReferrals_Call) Return(_a0 []database.GetReferralsRow, _a1 error) *Querier_GetReferrals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetReferrals_Call) RunAndReturn(run func(context.Context, database.GetReferralsParams) ([]database.GetReferralsRow, error)) *Querier_GetReferrals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRetentionCohorts provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetRetentionCohorts(ctx context.Context, arg database.GetRetentionCohortsParams) ([]database.GetRetentionCohortsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRetentionCohorts")
+	}
+
+	var r0 []database.GetRetentionCohortsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetRetentionCohortsParams) ([]database.GetRetentionCohortsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetRetentionCohortsParams) []database.GetRetentionCohortsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetRetentionCohortsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetRetentionCohortsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetRetentionCohorts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRetentionCohorts'
+type Querier_GetRetentionCohorts_Call struct {
+	*mock.Call
+}
+
+// GetRetentionCohorts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetRetentionCohortsParams
+func (_e *Querier_Expecter) GetRetentionCohorts(ctx interface{}, arg interface{}) *Querier_GetRetentionCohorts_Call {
+	return &Querier_GetRetentionCohorts_Call{Call: _e.mock.On("GetRetentionCohorts", ctx, arg)}
+}
+
+func (_c *Querier_GetRetentionCohorts_Call) Run(run func(ctx context.Context, arg database.GetRetentionCohortsParams)) *Querier_GetRetentionCohorts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetRetentionCohortsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetRetentionCohorts_Call) Return(_a0 []database.GetRetentionCohortsRow, _a1 error) *Querier_GetRetentionCohorts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetRetentionCohorts_Call) RunAndReturn(run func(context.Context, database.GetRetentionCohortsParams) ([]database.GetRetentionCohortsRow, error)) *Querier_GetRetentionCohorts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRetentionCutoff provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetRetentionCutoff(ctx context.Context, trackingID uuid.UUID) (sql.NullTime, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRetentionCutoff")
+	}
+
+	var r0 sql.NullTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (sql.NullTime, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) sql.NullTime); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		r0 = ret.Get(0).(sql.NullTime)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetRetentionCutoff_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRetentionCutoff'
+type Querier_GetRetentionCutoff_Call struct {
+	*mock.Call
+}
+
+// GetRetentionCutoff is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetRetentionCutoff(ctx interface{}, trackingID interface{}) *Querier_GetRetentionCutoff_Call {
+	return &Querier_GetRetentionCutoff_Call{Call: _e.mock.On("GetRetentionCutoff", ctx, trackingID)}
+}
+
+func (_c *Querier_GetRetentionCutoff_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetRetentionCutoff_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetRetentionCutoff_Call) Return(_a0 sql.NullTime, _a1 error) *Querier_GetRetentionCutoff_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetRetentionCutoff_Call) RunAndReturn(run func(context.Context, uuid.UUID) (sql.NullTime, error)) *Querier_GetRetentionCutoff_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRetentionCutoffs provides a mock function with given fields: ctx
+func (_m *Querier) GetRetentionCutoffs(ctx context.Context) ([]database.GetRetentionCutoffsRow, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRetentionCutoffs")
+	}
+
+	var r0 []database.GetRetentionCutoffsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]database.GetRetentionCutoffsRow, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []database.GetRetentionCutoffsRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetRetentionCutoffsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetRetentionCutoffs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRetentionCutoffs'
+type Querier_GetRetentionCutoffs_Call struct {
+	*mock.Call
+}
+
+// GetRetentionCutoffs is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Querier_Expecter) GetRetentionCutoffs(ctx interface{}) *Querier_GetRetentionCutoffs_Call {
+	return &Querier_GetRetentionCutoffs_Call{Call: _e.mock.On("GetRetentionCutoffs", ctx)}
+}
+
+func (_c *Querier_GetRetentionCutoffs_Call) Run(run func(ctx context.Context)) *Querier_GetRetentionCutoffs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Querier_GetRetentionCutoffs_Call) Return(_a0 []database.GetRetentionCutoffsRow, _a1 error) *Querier_GetRetentionCutoffs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetRetentionCutoffs_Call) RunAndReturn(run func(context.Context) ([]database.GetRetentionCutoffsRow, error)) *Querier_GetRetentionCutoffs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShareByTokenHash provides a mock function with given fields: ctx, tokenHash
+func (_m *Querier) GetShareByTokenHash(ctx context.Context, tokenHash string) (database.Share, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShareByTokenHash")
+	}
+
+	var r0 database.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (database.Share, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) database.Share); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(database.Share)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetShareByTokenHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShareByTokenHash'
+type Querier_GetShareByTokenHash_Call struct {
+	*mock.Call
+}
+
+// GetShareByTokenHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *Querier_Expecter) GetShareByTokenHash(ctx interface{}, tokenHash interface{}) *Querier_GetShareByTokenHash_Call {
+	return &Querier_GetShareByTokenHash_Call{Call: _e.mock.On("GetShareByTokenHash", ctx, tokenHash)}
+}
+
+func (_c *Querier_GetShareByTokenHash_Call) Run(run func(ctx context.Context, tokenHash string)) *Querier_GetShareByTokenHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Querier_GetShareByTokenHash_Call) Return(_a0 database.Share, _a1 error) *Querier_GetShareByTokenHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetShareByTokenHash_Call) RunAndReturn(run func(context.Context, string) (database.Share, error)) *Querier_GetShareByTokenHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShares provides a mock function with given fields: ctx, trackingID
+func (_m *Querier) GetShares(ctx context.Context, trackingID uuid.UUID) ([]database.Share, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShares")
+	}
+
+	var r0 []database.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.Share, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.Share); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetShares_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShares'
+type Querier_GetShares_Call struct {
+	*mock.Call
+}
+
+// GetShares is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uuid.UUID
+func (_e *Querier_Expecter) GetShares(ctx interface{}, trackingID interface{}) *Querier_GetShares_Call {
+	return &Querier_GetShares_Call{Call: _e.mock.On("GetShares", ctx, trackingID)}
+}
+
+func (_c *Querier_GetShares_Call) Run(run func(ctx context.Context, trackingID uuid.UUID)) *Querier_GetShares_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetShares_Call) Return(_a0 []database.Share, _a1 error) *Querier_GetShares_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetShares_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.Share, error)) *Querier_GetShares_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSketchInputs provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetSketchInputs(ctx context.Context, arg database.GetSketchInputsParams) ([]database.GetSketchInputsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSketchInputs")
+	}
+
+	var r0 []database.GetSketchInputsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetSketchInputsParams) ([]database.GetSketchInputsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetSketchInputsParams) []database.GetSketchInputsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetSketchInputsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetSketchInputsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetSketchInputs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSketchInputs'
+type Querier_GetSketchInputs_Call struct {
+	*mock.Call
+}
+
+// GetSketchInputs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetSketchInputsParams
+func (_e *Querier_Expecter) GetSketchInputs(ctx interface{}, arg interface{}) *Querier_GetSketchInputs_Call {
+	return &Querier_GetSketchInputs_Call{Call: _e.mock.On("GetSketchInputs", ctx, arg)}
+}
+
+func (_c *Querier_GetSketchInputs_Call) Run(run func(ctx context.Context, arg database.GetSketchInputsParams)) *Querier_GetSketchInputs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetSketchInputsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetSketchInputs_Call) Return(_a0 []database.GetSketchInputsRow, _a1 error) *Querier_GetSketchInputs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetSketchInputs_Call) RunAndReturn(run func(context.Context, database.GetSketchInputsParams) ([]database.GetSketchInputsRow, error)) *Querier_GetSketchInputs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSketchWatermark provides a mock function with given fields: ctx
+func (_m *Querier) GetSketchWatermark(ctx context.Context) (sql.NullTime, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSketchWatermark")
+	}
+
+	var r0 sql.NullTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (sql.NullTime, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) sql.NullTime); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(sql.NullTime)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetSketchWatermark_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSketchWatermark'
+type Querier_GetSketchWatermark_Call struct {
+	*mock.Call
+}
+
+// GetSketchWatermark is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Querier_Expecter) GetSketchWatermark(ctx interface{}) *Querier_GetSketchWatermark_Call {
+	return &Querier_GetSketchWatermark_Call{Call: _e.mock.On("GetSketchWatermark", ctx)}
+}
+
+func (_c *Querier_GetSketchWatermark_Call) Run(run func(ctx context.Context)) *Querier_GetSketchWatermark_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Querier_GetSketchWatermark_Call) Return(_a0 sql.NullTime, _a1 error) *Querier_GetSketchWatermark_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetSketchWatermark_Call) RunAndReturn(run func(context.Context) (sql.NullTime, error)) *Querier_GetSketchWatermark_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *Querier) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 database.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (database.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) database.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(database.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type Querier_GetUserByEmail_Call struct {
+	*mock.Call
+}
+
+// GetUserByEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *Querier_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *Querier_GetUserByEmail_Call {
+	return &Querier_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+}
+
+func (_c *Querier_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *Querier_GetUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Querier_GetUserByEmail_Call) Return(_a0 database.User, _a1 error) *Querier_GetUserByEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (database.User, error)) *Querier_GetUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserFlow provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetUserFlow(ctx context.Context, arg database.GetUserFlowParams) ([]database.GetUserFlowRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserFlow")
+	}
+
+	var r0 []database.GetUserFlowRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetUserFlowParams) ([]database.GetUserFlowRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetUserFlowParams) []database.GetUserFlowRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetUserFlowRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetUserFlowParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetUserFlow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserFlow'
+type Querier_GetUserFlow_Call struct {
+	*mock.Call
+}
+
+// GetUserFlow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetUserFlowParams
+func (_e *Querier_Expecter) GetUserFlow(ctx interface{}, arg interface{}) *Querier_GetUserFlow_Call {
+	return &Querier_GetUserFlow_Call{Call: _e.mock.On("GetUserFlow", ctx, arg)}
+}
+
+func (_c *Querier_GetUserFlow_Call) Run(run func(ctx context.Context, arg database.GetUserFlowParams)) *Querier_GetUserFlow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetUserFlowParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetUserFlow_Call) Return(_a0 []database.GetUserFlowRow, _a1 error) *Querier_GetUserFlow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetUserFlow_Call) RunAndReturn(run func(context.Context, database.GetUserFlowParams) ([]database.GetUserFlowRow, error)) *Querier_GetUserFlow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserInvitations provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]database.GetUserInvitationsRow, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserInvitations")
+	}
+
+	var r0 []database.GetUserInvitationsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]database.GetUserInvitationsRow, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []database.GetUserInvitationsRow); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetUserInvitationsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetUserInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserInvitations'
+type Querier_GetUserInvitations_Call struct {
+	*mock.Call
+}
+
+// GetUserInvitations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetUserInvitations(ctx interface{}, userID interface{}) *Querier_GetUserInvitations_Call {
+	return &Querier_GetUserInvitations_Call{Call: _e.mock.On("GetUserInvitations", ctx, userID)}
+}
+
+func (_c *Querier_GetUserInvitations_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetUserInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetUserInvitations_Call) Return(_a0 []database.GetUserInvitationsRow, _a1 error) *Querier_GetUserInvitations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetUserInvitations_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]database.GetUserInvitationsRow, error)) *Querier_GetUserInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVisitors provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetVisitors(ctx context.Context, arg database.GetVisitorsParams) ([]database.GetVisitorsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVisitors")
+	}
+
+	var r0 []database.GetVisitorsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetVisitorsParams) ([]database.GetVisitorsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.GetVisitorsParams) []database.GetVisitorsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.GetVisitorsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.GetVisitorsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVisitors'
+type Querier_GetVisitors_Call struct {
+	*mock.Call
+}
+
+// GetVisitors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.GetVisitorsParams
+func (_e *Querier_Expecter) GetVisitors(ctx interface{}, arg interface{}) *Querier_GetVisitors_Call {
+	return &Querier_GetVisitors_Call{Call: _e.mock.On("GetVisitors", ctx, arg)}
+}
+
+func (_c *Querier_GetVisitors_Call) Run(run func(ctx context.Context, arg database.GetVisitorsParams)) *Querier_GetVisitors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.GetVisitorsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetVisitors_Call) Return(_a0 []database.GetVisitorsRow, _a1 error) *Querier_GetVisitors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetVisitors_Call) RunAndReturn(run func(context.Context, database.GetVisitorsParams) ([]database.GetVisitorsRow, error)) *Querier_GetVisitors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HeartbeatDeletion provides a mock function with given fields: ctx, arg
+func (_m *Querier) HeartbeatDeletion(ctx context.Context, arg database.HeartbeatDeletionParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HeartbeatDeletion")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.HeartbeatDeletionParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.HeartbeatDeletionParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.HeartbeatDeletionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_HeartbeatDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HeartbeatDeletion'
+type Querier_HeartbeatDeletion_Call struct {
+	*mock.Call
+}
+
+// HeartbeatDeletion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.HeartbeatDeletionParams
+func (_e *Querier_Expecter) HeartbeatDeletion(ctx interface{}, arg interface{}) *Querier_HeartbeatDeletion_Call {
+	return &Querier_HeartbeatDeletion_Call{Call: _e.mock.On("HeartbeatDeletion", ctx, arg)}
+}
+
+func (_c *Querier_HeartbeatDeletion_Call) Run(run func(ctx context.Context, arg database.HeartbeatDeletionParams)) *Querier_HeartbeatDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.HeartbeatDeletionParams))
+	})
+	return _c
+}
+
+func (_c *Querier_HeartbeatDeletion_Call) Return(_a0 int64, _a1 error) *Querier_HeartbeatDeletion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_HeartbeatDeletion_Call) RunAndReturn(run func(context.Context, database.HeartbeatDeletionParams) (int64, error)) *Querier_HeartbeatDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HeartbeatImport provides a mock function with given fields: ctx, arg
+func (_m *Querier) HeartbeatImport(ctx context.Context, arg database.HeartbeatImportParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HeartbeatImport")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.HeartbeatImportParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.HeartbeatImportParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.HeartbeatImportParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_HeartbeatImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HeartbeatImport'
+type Querier_HeartbeatImport_Call struct {
+	*mock.Call
+}
+
+// HeartbeatImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.HeartbeatImportParams
+func (_e *Querier_Expecter) HeartbeatImport(ctx interface{}, arg interface{}) *Querier_HeartbeatImport_Call {
+	return &Querier_HeartbeatImport_Call{Call: _e.mock.On("HeartbeatImport", ctx, arg)}
+}
+
+func (_c *Querier_HeartbeatImport_Call) Run(run func(ctx context.Context, arg database.HeartbeatImportParams)) *Querier_HeartbeatImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.HeartbeatImportParams))
+	})
+	return _c
+}
+
+func (_c *Querier_HeartbeatImport_Call) Return(_a0 int64, _a1 error) *Querier_HeartbeatImport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_HeartbeatImport_Call) RunAndReturn(run func(context.Context, database.HeartbeatImportParams) (int64, error)) *Querier_HeartbeatImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertImportedAggregates provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertImportedAggregates(ctx context.Context, arg database.InsertImportedAggregatesParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertImportedAggregates")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.InsertImportedAggregatesParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_InsertImportedAggregates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertImportedAggregates'
+type Querier_InsertImportedAggregates_Call struct {
+	*mock.Call
+}
+
+// InsertImportedAggregates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.InsertImportedAggregatesParams
+func (_e *Querier_Expecter) InsertImportedAggregates(ctx interface{}, arg interface{}) *Querier_InsertImportedAggregates_Call {
+	return &Querier_InsertImportedAggregates_Call{Call: _e.mock.On("InsertImportedAggregates", ctx, arg)}
+}
+
+func (_c *Querier_InsertImportedAggregates_Call) Run(run func(ctx context.Context, arg database.InsertImportedAggregatesParams)) *Querier_InsertImportedAggregates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.InsertImportedAggregatesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_InsertImportedAggregates_Call) Return(_a0 error) *Querier_InsertImportedAggregates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_InsertImportedAggregates_Call) RunAndReturn(run func(context.Context, database.InsertImportedAggregatesParams) error) *Querier_InsertImportedAggregates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertImportedEvents provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertImportedEvents(ctx context.Context, arg database.InsertImportedEventsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertImportedEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.InsertImportedEventsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.InsertImportedEventsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.InsertImportedEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_InsertImportedEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertImportedEvents'
+type Querier_InsertImportedEvents_Call struct {
+	*mock.Call
+}
+
+// InsertImportedEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.InsertImportedEventsParams
+func (_e *Querier_Expecter) InsertImportedEvents(ctx interface{}, arg interface{}) *Querier_InsertImportedEvents_Call {
+	return &Querier_InsertImportedEvents_Call{Call: _e.mock.On("InsertImportedEvents", ctx, arg)}
+}
+
+func (_c *Querier_InsertImportedEvents_Call) Run(run func(ctx context.Context, arg database.InsertImportedEventsParams)) *Querier_InsertImportedEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.InsertImportedEventsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_InsertImportedEvents_Call) Return(_a0 int64, _a1 error) *Querier_InsertImportedEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_InsertImportedEvents_Call) RunAndReturn(run func(context.Context, database.InsertImportedEventsParams) (int64, error)) *Querier_InsertImportedEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueImport provides a mock function with given fields: ctx, id
+func (_m *Querier) RequeueImport(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueImport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_RequeueImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueImport'
+type Querier_RequeueImport_Call struct {
+	*mock.Call
+}
+
+// RequeueImport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *Querier_Expecter) RequeueImport(ctx interface{}, id interface{}) *Querier_RequeueImport_Call {
+	return &Querier_RequeueImport_Call{Call: _e.mock.On("RequeueImport", ctx, id)}
+}
+
+func (_c *Querier_RequeueImport_Call) Run(run func(ctx context.Context, id uuid.UUID)) *Querier_RequeueImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_RequeueImport_Call) Return(_a0 error) *Querier_RequeueImport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_RequeueImport_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *Querier_RequeueImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAPIToken provides a mock function with given fields: ctx, arg
+func (_m *Querier) RevokeAPIToken(ctx context.Context, arg database.RevokeAPITokenParams) (database.ApiToken, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIToken")
+	}
+
+	var r0 database.ApiToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.RevokeAPITokenParams) (database.ApiToken, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.RevokeAPITokenParams) database.ApiToken); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.ApiToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.RevokeAPITokenParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_RevokeAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIToken'
+type Querier_RevokeAPIToken_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.RevokeAPITokenParams
+func (_e *Querier_Expecter) RevokeAPIToken(ctx interface{}, arg interface{}) *Querier_RevokeAPIToken_Call {
+	return &Querier_RevokeAPIToken_Call{Call: _e.mock.On("RevokeAPIToken", ctx, arg)}
+}
+
+func (_c *Querier_RevokeAPIToken_Call) Run(run func(ctx context.Context, arg database.RevokeAPITokenParams)) *Querier_RevokeAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.RevokeAPITokenParams))
+	})
+	return _c
+}
+
+func (_c *Querier_RevokeAPIToken_Call) Return(_a0 database.ApiToken, _a1 error) *Querier_RevokeAPIToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_RevokeAPIToken_Call) RunAndReturn(run func(context.Context, database.RevokeAPITokenParams) (database.ApiToken, error)) *Querier_RevokeAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeShare provides a mock function with given fields: ctx, arg
+func (_m *Querier) RevokeShare(ctx context.Context, arg database.RevokeShareParams) (database.Share, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeShare")
+	}
+
+	var r0 database.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.RevokeShareParams) (database.Share, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.RevokeShareParams) database.Share); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.Share)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.RevokeShareParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_RevokeShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeShare'
+type Querier_RevokeShare_Call struct {
+	*mock.Call
+}
+
+// RevokeShare is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.RevokeShareParams
+func (_e *Querier_Expecter) RevokeShare(ctx interface{}, arg interface{}) *Querier_RevokeShare_Call {
+	return &Querier_RevokeShare_Call{Call: _e.mock.On("RevokeShare", ctx, arg)}
+}
+
+func (_c *Querier_RevokeShare_Call) Run(run func(ctx context.Context, arg database.RevokeShareParams)) *Querier_RevokeShare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.RevokeShareParams))
+	})
+	return _c
+}
+
+func (_c *Querier_RevokeShare_Call) Return(_a0 database.Share, _a1 error) *Querier_RevokeShare_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_RevokeShare_Call) RunAndReturn(run func(context.Context, database.RevokeShareParams) (database.Share, error)) *Querier_RevokeShare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEventsCompressionPolicy provides a mock function with given fields: ctx, compressAfter
+func (_m *Querier) SetEventsCompressionPolicy(ctx context.Context, compressAfter string) error {
+	ret := _m.Called(ctx, compressAfter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEventsCompressionPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, compressAfter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_SetEventsCompressionPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEventsCompressionPolicy'
+type Querier_SetEventsCompressionPolicy_Call struct {
+	*mock.Call
+}
+
+// SetEventsCompressionPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - compressAfter string
+func (_e *Querier_Expecter) SetEventsCompressionPolicy(ctx interface{}, compressAfter interface{}) *Querier_SetEventsCompressionPolicy_Call {
+	return &Querier_SetEventsCompressionPolicy_Call{Call: _e.mock.On("SetEventsCompressionPolicy", ctx, compressAfter)}
+}
+
+func (_c *Querier_SetEventsCompressionPolicy_Call) Run(run func(ctx context.Context, compressAfter string)) *Querier_SetEventsCompressionPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Querier_SetEventsCompressionPolicy_Call) Return(_a0 error) *Querier_SetEventsCompressionPolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_SetEventsCompressionPolicy_Call) RunAndReturn(run func(context.Context, string) error) *Querier_SetEventsCompressionPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEventsRetentionPolicy provides a mock function with given fields: ctx, dropAfter
+func (_m *Querier) SetEventsRetentionPolicy(ctx context.Context, dropAfter *string) error {
+	ret := _m.Called(ctx, dropAfter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEventsRetentionPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) error); ok {
+		r0 = rf(ctx, dropAfter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_SetEventsRetentionPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEventsRetentionPolicy'
+type Querier_SetEventsRetentionPolicy_Call struct {
+	*mock.Call
+}
+
+// SetEventsRetentionPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dropAfter *string
+func (_e *Querier_Expecter) SetEventsRetentionPolicy(ctx interface{}, dropAfter interface{}) *Querier_SetEventsRetentionPolicy_Call {
+	return &Querier_SetEventsRetentionPolicy_Call{Call: _e.mock.On("SetEventsRetentionPolicy", ctx, dropAfter)}
+}
+
+func (_c *Querier_SetEventsRetentionPolicy_Call) Run(run func(ctx context.Context, dropAfter *string)) *Querier_SetEventsRetentionPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*string))
+	})
+	return _c
+}
+
+func (_c *Querier_SetEventsRetentionPolicy_Call) Return(_a0 error) *Querier_SetEventsRetentionPolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_SetEventsRetentionPolicy_Call) RunAndReturn(run func(context.Context, *string) error) *Querier_SetEventsRetentionPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetImportedSince provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetImportedSince(ctx context.Context, arg database.SetImportedSinceParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetImportedSince")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.SetImportedSinceParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_SetImportedSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetImportedSince'
+type Querier_SetImportedSince_Call struct {
+	*mock.Call
+}
+
+// SetImportedSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.SetImportedSinceParams
+func (_e *Querier_Expecter) SetImportedSince(ctx interface{}, arg interface{}) *Querier_SetImportedSince_Call {
+	return &Querier_SetImportedSince_Call{Call: _e.mock.On("SetImportedSince", ctx, arg)}
+}
+
+func (_c *Querier_SetImportedSince_Call) Run(run func(ctx context.Context, arg database.SetImportedSinceParams)) *Querier_SetImportedSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.SetImportedSinceParams))
+	})
+	return _c
+}
+
+func (_c *Querier_SetImportedSince_Call) Return(_a0 error) *Querier_SetImportedSince_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_SetImportedSince_Call) RunAndReturn(run func(context.Context, database.SetImportedSinceParams) error) *Querier_SetImportedSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetSketchWatermark provides a mock function with given fields: ctx, builtUntil
+func (_m *Querier) SetSketchWatermark(ctx context.Context, builtUntil sql.NullTime) error {
+	ret := _m.Called(ctx, builtUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSketchWatermark")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sql.NullTime) error); ok {
+		r0 = rf(ctx, builtUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_SetSketchWatermark_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSketchWatermark'
+type Querier_SetSketchWatermark_Call struct {
+	*mock.Call
+}
+
+// SetSketchWatermark is a helper method to define mock.On call
+//   - ctx context.Context
+//   - builtUntil sql.NullTime
+func (_e *Querier_Expecter) SetSketchWatermark(ctx interface{}, builtUntil interface{}) *Querier_SetSketchWatermark_Call {
+	return &Querier_SetSketchWatermark_Call{Call: _e.mock.On("SetSketchWatermark", ctx, builtUntil)}
+}
+
+func (_c *Querier_SetSketchWatermark_Call) Run(run func(ctx context.Context, builtUntil sql.NullTime)) *Querier_SetSketchWatermark_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sql.NullTime))
+	})
+	return _c
+}
+
+func (_c *Querier_SetSketchWatermark_Call) Return(_a0 error) *Querier_SetSketchWatermark_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_SetSketchWatermark_Call) RunAndReturn(run func(context.Context, sql.NullTime) error) *Querier_SetSketchWatermark_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TouchAPIToken provides a mock function with given fields: ctx, arg
+func (_m *Querier) TouchAPIToken(ctx context.Context, arg database.TouchAPITokenParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.TouchAPITokenParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_TouchAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchAPIToken'
+type Querier_TouchAPIToken_Call struct {
+	*mock.Call
+}
+
+// TouchAPIToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.TouchAPITokenParams
+func (_e *Querier_Expecter) TouchAPIToken(ctx interface{}, arg interface{}) *Querier_TouchAPIToken_Call {
+	return &Querier_TouchAPIToken_Call{Call: _e.mock.On("TouchAPIToken", ctx, arg)}
+}
+
+func (_c *Querier_TouchAPIToken_Call) Run(run func(ctx context.Context, arg database.TouchAPITokenParams)) *Querier_TouchAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.TouchAPITokenParams))
+	})
+	return _c
+}
+
+func (_c *Querier_TouchAPIToken_Call) Return(_a0 error) *Querier_TouchAPIToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_TouchAPIToken_Call) RunAndReturn(run func(context.Context, database.TouchAPITokenParams) error) *Querier_TouchAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransferApp provides a mock function with given fields: ctx, arg
+func (_m *Querier) TransferApp(ctx context.Context, arg database.TransferAppParams) (database.App, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferApp")
+	}
+
+	var r0 database.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.TransferAppParams) (database.App, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.TransferAppParams) database.App); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.TransferAppParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_TransferApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferApp'
+type Querier_TransferApp_Call struct {
+	*mock.Call
+}
+
+// TransferApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.TransferAppParams
+func (_e *Querier_Expecter) TransferApp(ctx interface{}, arg interface{}) *Querier_TransferApp_Call {
+	return &Querier_TransferApp_Call{Call: _e.mock.On("TransferApp", ctx, arg)}
+}
+
+func (_c *Querier_TransferApp_Call) Run(run func(ctx context.Context, arg database.TransferAppParams)) *Querier_TransferApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.TransferAppParams))
+	})
+	return _c
+}
+
+func (_c *Querier_TransferApp_Call) Return(_a0 database.App, _a1 error) *Querier_TransferApp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_TransferApp_Call) RunAndReturn(run func(context.Context, database.TransferAppParams) (database.App, error)) *Querier_TransferApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateApp provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateApp(ctx context.Context, arg database.UpdateAppParams) (database.App, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateApp")
+	}
+
+	var r0 database.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppParams) (database.App, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppParams) database.App); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.App)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.UpdateAppParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_UpdateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateApp'
+type Querier_UpdateApp_Call struct {
+	*mock.Call
+}
+
+// UpdateApp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpdateAppParams
+func (_e *Querier_Expecter) UpdateApp(ctx interface{}, arg interface{}) *Querier_UpdateApp_Call {
+	return &Querier_UpdateApp_Call{Call: _e.mock.On("UpdateApp", ctx, arg)}
+}
+
+func (_c *Querier_UpdateApp_Call) Run(run func(ctx context.Context, arg database.UpdateAppParams)) *Querier_UpdateApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpdateAppParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpdateApp_Call) Return(_a0 database.App, _a1 error) *Querier_UpdateApp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_UpdateApp_Call) RunAndReturn(run func(context.Context, database.UpdateAppParams) (database.App, error)) *Querier_UpdateApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAppMemberRole provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateAppMemberRole(ctx context.Context, arg database.UpdateAppMemberRoleParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAppMemberRole")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppMemberRoleParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateAppMemberRoleParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.UpdateAppMemberRoleParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_UpdateAppMemberRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAppMemberRole'
+type Querier_UpdateAppMemberRole_Call struct {
+	*mock.Call
+}
+
+// UpdateAppMemberRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpdateAppMemberRoleParams
+func (_e *Querier_Expecter) UpdateAppMemberRole(ctx interface{}, arg interface{}) *Querier_UpdateAppMemberRole_Call {
+	return &Querier_UpdateAppMemberRole_Call{Call: _e.mock.On("UpdateAppMemberRole", ctx, arg)}
+}
+
+func (_c *Querier_UpdateAppMemberRole_Call) Run(run func(ctx context.Context, arg database.UpdateAppMemberRoleParams)) *Querier_UpdateAppMemberRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpdateAppMemberRoleParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpdateAppMemberRole_Call) Return(_a0 int64, _a1 error) *Querier_UpdateAppMemberRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_UpdateAppMemberRole_Call) RunAndReturn(run func(context.Context, database.UpdateAppMemberRoleParams) (int64, error)) *Querier_UpdateAppMemberRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDeletionProgress provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateDeletionProgress(ctx context.Context, arg database.UpdateDeletionProgressParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeletionProgress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateDeletionProgressParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_UpdateDeletionProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDeletionProgress'
+type Querier_UpdateDeletionProgress_Call struct {
+	*mock.Call
+}
+
+// UpdateDeletionProgress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpdateDeletionProgressParams
+func (_e *Querier_Expecter) UpdateDeletionProgress(ctx interface{}, arg interface{}) *Querier_UpdateDeletionProgress_Call {
+	return &Querier_UpdateDeletionProgress_Call{Call: _e.mock.On("UpdateDeletionProgress", ctx, arg)}
+}
+
+func (_c *Querier_UpdateDeletionProgress_Call) Run(run func(ctx context.Context, arg database.UpdateDeletionProgressParams)) *Querier_UpdateDeletionProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpdateDeletionProgressParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpdateDeletionProgress_Call) Return(_a0 error) *Querier_UpdateDeletionProgress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_UpdateDeletionProgress_Call) RunAndReturn(run func(context.Context, database.UpdateDeletionProgressParams) error) *Querier_UpdateDeletionProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateImportProgress provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateImportProgress(ctx context.Context, arg database.UpdateImportProgressParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateImportProgress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpdateImportProgressParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_UpdateImportProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateImportProgress'
+type Querier_UpdateImportProgress_Call struct {
+	*mock.Call
+}
+
+// UpdateImportProgress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpdateImportProgressParams
+func (_e *Querier_Expecter) UpdateImportProgress(ctx interface{}, arg interface{}) *Querier_UpdateImportProgress_Call {
+	return &Querier_UpdateImportProgress_Call{Call: _e.mock.On("UpdateImportProgress", ctx, arg)}
+}
+
+func (_c *Querier_UpdateImportProgress_Call) Run(run func(ctx context.Context, arg database.UpdateImportProgressParams)) *Querier_UpdateImportProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpdateImportProgressParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpdateImportProgress_Call) Return(_a0 error) *Querier_UpdateImportProgress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_UpdateImportProgress_Call) RunAndReturn(run func(context.Context, database.UpdateImportProgressParams) error) *Querier_UpdateImportProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertEventSketches provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpsertEventSketches(ctx context.Context, arg database.UpsertEventSketchesParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertEventSketches")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpsertEventSketchesParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_UpsertEventSketches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertEventSketches'
+type Querier_UpsertEventSketches_Call struct {
+	*mock.Call
+}
+
+// UpsertEventSketches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpsertEventSketchesParams
+func (_e *Querier_Expecter) UpsertEventSketches(ctx interface{}, arg interface{}) *Querier_UpsertEventSketches_Call {
+	return &Querier_UpsertEventSketches_Call{Call: _e.mock.On("UpsertEventSketches", ctx, arg)}
+}
+
+func (_c *Querier_UpsertEventSketches_Call) Run(run func(ctx context.Context, arg database.UpsertEventSketchesParams)) *Querier_UpsertEventSketches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpsertEventSketchesParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpsertEventSketches_Call) Return(_a0 error) *Querier_UpsertEventSketches_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_UpsertEventSketches_Call) RunAndReturn(run func(context.Context, database.UpsertEventSketchesParams) error) *Querier_UpsertEventSketches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertEventVisitors provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpsertEventVisitors(ctx context.Context, arg database.UpsertEventVisitorsParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertEventVisitors")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpsertEventVisitorsParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Querier_UpsertEventVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertEventVisitors'
+type Querier_UpsertEventVisitors_Call struct {
+	*mock.Call
+}
+
+// UpsertEventVisitors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpsertEventVisitorsParams
+func (_e *Querier_Expecter) UpsertEventVisitors(ctx interface{}, arg interface{}) *Querier_UpsertEventVisitors_Call {
+	return &Querier_UpsertEventVisitors_Call{Call: _e.mock.On("UpsertEventVisitors", ctx, arg)}
+}
+
+func (_c *Querier_UpsertEventVisitors_Call) Run(run func(ctx context.Context, arg database.UpsertEventVisitorsParams)) *Querier_UpsertEventVisitors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpsertEventVisitorsParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpsertEventVisitors_Call) Return(_a0 error) *Querier_UpsertEventVisitors_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Querier_UpsertEventVisitors_Call) RunAndReturn(run func(context.Context, database.UpsertEventVisitorsParams) error) *Querier_UpsertEventVisitors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertOrganizationMember provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpsertOrganizationMember(ctx context.Context, arg database.UpsertOrganizationMemberParams) (database.OrganizationMember, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertOrganizationMember")
+	}
+
+	var r0 database.OrganizationMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpsertOrganizationMemberParams) (database.OrganizationMember, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.UpsertOrganizationMemberParams) database.OrganizationMember); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(database.OrganizationMember)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.UpsertOrganizationMemberParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_UpsertOrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertOrganizationMember'
+type Querier_UpsertOrganizationMember_Call struct {
+	*mock.Call
+}
+
+// UpsertOrganizationMember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg database.UpsertOrganizationMemberParams
+func (_e *Querier_Expecter) UpsertOrganizationMember(ctx interface{}, arg interface{}) *Querier_UpsertOrganizationMember_Call {
+	return &Querier_UpsertOrganizationMember_Call{Call: _e.mock.On("UpsertOrganizationMember", ctx, arg)}
+}
+
+func (_c *Querier_UpsertOrganizationMember_Call) Run(run func(ctx context.Context, arg database.UpsertOrganizationMemberParams)) *Querier_UpsertOrganizationMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(database.UpsertOrganizationMemberParams))
+	})
+	return _c
+}
+
+func (_c *Querier_UpsertOrganizationMember_Call) Return(_a0 database.OrganizationMember, _a1 error) *Querier_UpsertOrganizationMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_UpsertOrganizationMember_Call) RunAndReturn(run func(context.Context, database.UpsertOrganizationMemberParams) (database.OrganizationMember, error)) *Querier_UpsertOrganizationMember_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -4,10 +4,12 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
-	server "github.com/ScMofeoluwa/minalytics/shared"
 	mock "github.com/stretchr/testify/mock"
 
+	server "github.com/ScMofeoluwa/minalytics/shared"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -24,20 +26,20 @@ func (_m *AnalyticsService) EXPECT() *AnalyticsService_Expecter {
 	return &AnalyticsService_Expecter{mock: &_m.Mock}
 }
 
-// CreateApp provides a mock function with given fields: _a0, _a1, _a2
-func (_m *AnalyticsService) CreateApp(_a0 context.Context, _a1 uuid.UUID, _a2 string) (*server.App, error) {
+// AcceptInvitation provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) AcceptInvitation(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) (*server.App, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateApp")
+		panic("no return value specified for AcceptInvitation")
 	}
 
 	var r0 *server.App
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (*server.App, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*server.App, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *server.App); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *server.App); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
@@ -45,7 +47,7 @@ func (_m *AnalyticsService) CreateApp(_a0 context.Context, _a1 uuid.UUID, _a2 st
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -54,106 +56,118 @@ func (_m *AnalyticsService) CreateApp(_a0 context.Context, _a1 uuid.UUID, _a2 st
 	return r0, r1
 }
 
-// AnalyticsService_CreateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateApp'
-type AnalyticsService_CreateApp_Call struct {
+// AnalyticsService_AcceptInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AcceptInvitation'
+type AnalyticsService_AcceptInvitation_Call struct {
 	*mock.Call
 }
 
-// CreateApp is a helper method to define mock.On call
+// AcceptInvitation is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 uuid.UUID
-//   - _a2 string
-func (_e *AnalyticsService_Expecter) CreateApp(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_CreateApp_Call {
-	return &AnalyticsService_CreateApp_Call{Call: _e.mock.On("CreateApp", _a0, _a1, _a2)}
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) AcceptInvitation(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_AcceptInvitation_Call {
+	return &AnalyticsService_AcceptInvitation_Call{Call: _e.mock.On("AcceptInvitation", _a0, _a1, _a2)}
 }
 
-func (_c *AnalyticsService_CreateApp_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 string)) *AnalyticsService_CreateApp_Call {
+func (_c *AnalyticsService_AcceptInvitation_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_AcceptInvitation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_CreateApp_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_CreateApp_Call {
+func (_c *AnalyticsService_AcceptInvitation_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_AcceptInvitation_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_CreateApp_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (*server.App, error)) *AnalyticsService_CreateApp_Call {
+func (_c *AnalyticsService_AcceptInvitation_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) (*server.App, error)) *AnalyticsService_AcceptInvitation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteApp provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) DeleteApp(_a0 context.Context, _a1 server.AppPayload) error {
+// CreateAPIToken provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) CreateAPIToken(_a0 context.Context, _a1 server.APITokenPayload) (*server.APIToken, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteApp")
+		panic("no return value specified for CreateAPIToken")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.AppPayload) error); ok {
+	var r0 *server.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.APITokenPayload) (*server.APIToken, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.APITokenPayload) *server.APIToken); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.APIToken)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, server.APITokenPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// AnalyticsService_DeleteApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteApp'
-type AnalyticsService_DeleteApp_Call struct {
+// AnalyticsService_CreateAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAPIToken'
+type AnalyticsService_CreateAPIToken_Call struct {
 	*mock.Call
 }
 
-// DeleteApp is a helper method to define mock.On call
+// CreateAPIToken is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.AppPayload
-func (_e *AnalyticsService_Expecter) DeleteApp(_a0 interface{}, _a1 interface{}) *AnalyticsService_DeleteApp_Call {
-	return &AnalyticsService_DeleteApp_Call{Call: _e.mock.On("DeleteApp", _a0, _a1)}
+//   - _a1 server.APITokenPayload
+func (_e *AnalyticsService_Expecter) CreateAPIToken(_a0 interface{}, _a1 interface{}) *AnalyticsService_CreateAPIToken_Call {
+	return &AnalyticsService_CreateAPIToken_Call{Call: _e.mock.On("CreateAPIToken", _a0, _a1)}
 }
 
-func (_c *AnalyticsService_DeleteApp_Call) Run(run func(_a0 context.Context, _a1 server.AppPayload)) *AnalyticsService_DeleteApp_Call {
+func (_c *AnalyticsService_CreateAPIToken_Call) Run(run func(_a0 context.Context, _a1 server.APITokenPayload)) *AnalyticsService_CreateAPIToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.AppPayload))
+		run(args[0].(context.Context), args[1].(server.APITokenPayload))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_DeleteApp_Call) Return(_a0 error) *AnalyticsService_DeleteApp_Call {
-	_c.Call.Return(_a0)
+func (_c *AnalyticsService_CreateAPIToken_Call) Return(_a0 *server.APIToken, _a1 error) *AnalyticsService_CreateAPIToken_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_DeleteApp_Call) RunAndReturn(run func(context.Context, server.AppPayload) error) *AnalyticsService_DeleteApp_Call {
+func (_c *AnalyticsService_CreateAPIToken_Call) RunAndReturn(run func(context.Context, server.APITokenPayload) (*server.APIToken, error)) *AnalyticsService_CreateAPIToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetApps provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetApps(_a0 context.Context, _a1 uuid.UUID) ([]server.App, error) {
-	ret := _m.Called(_a0, _a1)
+// CreateApp provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) CreateApp(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 string) (*server.App, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetApps")
+		panic("no return value specified for CreateApp")
 	}
 
-	var r0 []server.App
+	var r0 *server.App
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]server.App, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (*server.App, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []server.App); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) *server.App); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.App)
+			r0 = ret.Get(0).(*server.App)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -161,57 +175,59 @@ func (_m *AnalyticsService) GetApps(_a0 context.Context, _a1 uuid.UUID) ([]serve
 	return r0, r1
 }
 
-// AnalyticsService_GetApps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetApps'
-type AnalyticsService_GetApps_Call struct {
+// AnalyticsService_CreateApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateApp'
+type AnalyticsService_CreateApp_Call struct {
 	*mock.Call
 }
 
-// GetApps is a helper method to define mock.On call
+// CreateApp is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 uuid.UUID
-func (_e *AnalyticsService_Expecter) GetApps(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetApps_Call {
-	return &AnalyticsService_GetApps_Call{Call: _e.mock.On("GetApps", _a0, _a1)}
+//   - _a2 uuid.UUID
+//   - _a3 string
+func (_e *AnalyticsService_Expecter) CreateApp(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_CreateApp_Call {
+	return &AnalyticsService_CreateApp_Call{Call: _e.mock.On("CreateApp", _a0, _a1, _a2, _a3)}
 }
 
-func (_c *AnalyticsService_GetApps_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetApps_Call {
+func (_c *AnalyticsService_CreateApp_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 string)) *AnalyticsService_CreateApp_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetApps_Call) Return(_a0 []server.App, _a1 error) *AnalyticsService_GetApps_Call {
+func (_c *AnalyticsService_CreateApp_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_CreateApp_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetApps_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]server.App, error)) *AnalyticsService_GetApps_Call {
+func (_c *AnalyticsService_CreateApp_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, string) (*server.App, error)) *AnalyticsService_CreateApp_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetBrowsers provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetBrowsers(_a0 context.Context, _a1 server.RequestPayload) ([]server.BrowserStats, error) {
+// CreateDeletion provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) CreateDeletion(_a0 context.Context, _a1 server.DeletionPayload) (*server.Deletion, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBrowsers")
+		panic("no return value specified for CreateDeletion")
 	}
 
-	var r0 []server.BrowserStats
+	var r0 *server.Deletion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.BrowserStats, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.DeletionPayload) (*server.Deletion, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.BrowserStats); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.DeletionPayload) *server.Deletion); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.BrowserStats)
+			r0 = ret.Get(0).(*server.Deletion)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, server.DeletionPayload) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -220,58 +236,58 @@ func (_m *AnalyticsService) GetBrowsers(_a0 context.Context, _a1 server.RequestP
 	return r0, r1
 }
 
-// AnalyticsService_GetBrowsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBrowsers'
-type AnalyticsService_GetBrowsers_Call struct {
+// AnalyticsService_CreateDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeletion'
+type AnalyticsService_CreateDeletion_Call struct {
 	*mock.Call
 }
 
-// GetBrowsers is a helper method to define mock.On call
+// CreateDeletion is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetBrowsers(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetBrowsers_Call {
-	return &AnalyticsService_GetBrowsers_Call{Call: _e.mock.On("GetBrowsers", _a0, _a1)}
+//   - _a1 server.DeletionPayload
+func (_e *AnalyticsService_Expecter) CreateDeletion(_a0 interface{}, _a1 interface{}) *AnalyticsService_CreateDeletion_Call {
+	return &AnalyticsService_CreateDeletion_Call{Call: _e.mock.On("CreateDeletion", _a0, _a1)}
 }
 
-func (_c *AnalyticsService_GetBrowsers_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetBrowsers_Call {
+func (_c *AnalyticsService_CreateDeletion_Call) Run(run func(_a0 context.Context, _a1 server.DeletionPayload)) *AnalyticsService_CreateDeletion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(server.DeletionPayload))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetBrowsers_Call) Return(_a0 []server.BrowserStats, _a1 error) *AnalyticsService_GetBrowsers_Call {
+func (_c *AnalyticsService_CreateDeletion_Call) Return(_a0 *server.Deletion, _a1 error) *AnalyticsService_CreateDeletion_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetBrowsers_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.BrowserStats, error)) *AnalyticsService_GetBrowsers_Call {
+func (_c *AnalyticsService_CreateDeletion_Call) RunAndReturn(run func(context.Context, server.DeletionPayload) (*server.Deletion, error)) *AnalyticsService_CreateDeletion_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCountries provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetCountries(_a0 context.Context, _a1 server.RequestPayload) ([]server.CountryStats, error) {
-	ret := _m.Called(_a0, _a1)
+// CreateImport provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) CreateImport(_a0 context.Context, _a1 server.ImportPayload, _a2 io.Reader) (*server.Import, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCountries")
+		panic("no return value specified for CreateImport")
 	}
 
-	var r0 []server.CountryStats
+	var r0 *server.Import
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.CountryStats, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, server.ImportPayload, io.Reader) (*server.Import, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.CountryStats); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, server.ImportPayload, io.Reader) *server.Import); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.CountryStats)
+			r0 = ret.Get(0).(*server.Import)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, server.ImportPayload, io.Reader) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -279,57 +295,58 @@ func (_m *AnalyticsService) GetCountries(_a0 context.Context, _a1 server.Request
 	return r0, r1
 }
 
-// AnalyticsService_GetCountries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountries'
-type AnalyticsService_GetCountries_Call struct {
+// AnalyticsService_CreateImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateImport'
+type AnalyticsService_CreateImport_Call struct {
 	*mock.Call
 }
 
-// GetCountries is a helper method to define mock.On call
+// CreateImport is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetCountries(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetCountries_Call {
-	return &AnalyticsService_GetCountries_Call{Call: _e.mock.On("GetCountries", _a0, _a1)}
+//   - _a1 server.ImportPayload
+//   - _a2 io.Reader
+func (_e *AnalyticsService_Expecter) CreateImport(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_CreateImport_Call {
+	return &AnalyticsService_CreateImport_Call{Call: _e.mock.On("CreateImport", _a0, _a1, _a2)}
 }
 
-func (_c *AnalyticsService_GetCountries_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetCountries_Call {
+func (_c *AnalyticsService_CreateImport_Call) Run(run func(_a0 context.Context, _a1 server.ImportPayload, _a2 io.Reader)) *AnalyticsService_CreateImport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(server.ImportPayload), args[2].(io.Reader))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetCountries_Call) Return(_a0 []server.CountryStats, _a1 error) *AnalyticsService_GetCountries_Call {
+func (_c *AnalyticsService_CreateImport_Call) Return(_a0 *server.Import, _a1 error) *AnalyticsService_CreateImport_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetCountries_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.CountryStats, error)) *AnalyticsService_GetCountries_Call {
+func (_c *AnalyticsService_CreateImport_Call) RunAndReturn(run func(context.Context, server.ImportPayload, io.Reader) (*server.Import, error)) *AnalyticsService_CreateImport_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetDevices provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetDevices(_a0 context.Context, _a1 server.RequestPayload) ([]server.DeviceStats, error) {
+// CreateInvitation provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) CreateInvitation(_a0 context.Context, _a1 server.InvitationPayload) (*server.Invitation, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetDevices")
+		panic("no return value specified for CreateInvitation")
 	}
 
-	var r0 []server.DeviceStats
+	var r0 *server.Invitation
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.DeviceStats, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.InvitationPayload) (*server.Invitation, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.DeviceStats); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.InvitationPayload) *server.Invitation); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.DeviceStats)
+			r0 = ret.Get(0).(*server.Invitation)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, server.InvitationPayload) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -338,58 +355,58 @@ func (_m *AnalyticsService) GetDevices(_a0 context.Context, _a1 server.RequestPa
 	return r0, r1
 }
 
-// AnalyticsService_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
-type AnalyticsService_GetDevices_Call struct {
+// AnalyticsService_CreateInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateInvitation'
+type AnalyticsService_CreateInvitation_Call struct {
 	*mock.Call
 }
 
-// GetDevices is a helper method to define mock.On call
+// CreateInvitation is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetDevices(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetDevices_Call {
-	return &AnalyticsService_GetDevices_Call{Call: _e.mock.On("GetDevices", _a0, _a1)}
+//   - _a1 server.InvitationPayload
+func (_e *AnalyticsService_Expecter) CreateInvitation(_a0 interface{}, _a1 interface{}) *AnalyticsService_CreateInvitation_Call {
+	return &AnalyticsService_CreateInvitation_Call{Call: _e.mock.On("CreateInvitation", _a0, _a1)}
 }
 
-func (_c *AnalyticsService_GetDevices_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetDevices_Call {
+func (_c *AnalyticsService_CreateInvitation_Call) Run(run func(_a0 context.Context, _a1 server.InvitationPayload)) *AnalyticsService_CreateInvitation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(server.InvitationPayload))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetDevices_Call) Return(_a0 []server.DeviceStats, _a1 error) *AnalyticsService_GetDevices_Call {
+func (_c *AnalyticsService_CreateInvitation_Call) Return(_a0 *server.Invitation, _a1 error) *AnalyticsService_CreateInvitation_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetDevices_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.DeviceStats, error)) *AnalyticsService_GetDevices_Call {
+func (_c *AnalyticsService_CreateInvitation_Call) RunAndReturn(run func(context.Context, server.InvitationPayload) (*server.Invitation, error)) *AnalyticsService_CreateInvitation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOS provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetOS(_a0 context.Context, _a1 server.RequestPayload) ([]server.OSStats, error) {
-	ret := _m.Called(_a0, _a1)
+// CreateOrganization provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) CreateOrganization(_a0 context.Context, _a1 uuid.UUID, _a2 string) (*server.Organization, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOS")
+		panic("no return value specified for CreateOrganization")
 	}
 
-	var r0 []server.OSStats
+	var r0 *server.Organization
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.OSStats, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (*server.Organization, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.OSStats); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *server.Organization); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.OSStats)
+			r0 = ret.Get(0).(*server.Organization)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -397,57 +414,58 @@ func (_m *AnalyticsService) GetOS(_a0 context.Context, _a1 server.RequestPayload
 	return r0, r1
 }
 
-// AnalyticsService_GetOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOS'
-type AnalyticsService_GetOS_Call struct {
+// AnalyticsService_CreateOrganization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrganization'
+type AnalyticsService_CreateOrganization_Call struct {
 	*mock.Call
 }
 
-// GetOS is a helper method to define mock.On call
+// CreateOrganization is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetOS(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetOS_Call {
-	return &AnalyticsService_GetOS_Call{Call: _e.mock.On("GetOS", _a0, _a1)}
+//   - _a1 uuid.UUID
+//   - _a2 string
+func (_e *AnalyticsService_Expecter) CreateOrganization(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_CreateOrganization_Call {
+	return &AnalyticsService_CreateOrganization_Call{Call: _e.mock.On("CreateOrganization", _a0, _a1, _a2)}
 }
 
-func (_c *AnalyticsService_GetOS_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetOS_Call {
+func (_c *AnalyticsService_CreateOrganization_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 string)) *AnalyticsService_CreateOrganization_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetOS_Call) Return(_a0 []server.OSStats, _a1 error) *AnalyticsService_GetOS_Call {
+func (_c *AnalyticsService_CreateOrganization_Call) Return(_a0 *server.Organization, _a1 error) *AnalyticsService_CreateOrganization_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetOS_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.OSStats, error)) *AnalyticsService_GetOS_Call {
+func (_c *AnalyticsService_CreateOrganization_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (*server.Organization, error)) *AnalyticsService_CreateOrganization_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPageViews provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetPageViews(_a0 context.Context, _a1 server.RequestPayload) ([]server.PageViewStats, error) {
+// CreateShare provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) CreateShare(_a0 context.Context, _a1 server.SharePayload) (*server.Share, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPageViews")
+		panic("no return value specified for CreateShare")
 	}
 
-	var r0 []server.PageViewStats
+	var r0 *server.Share
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.PageViewStats, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.SharePayload) (*server.Share, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.PageViewStats); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, server.SharePayload) *server.Share); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.PageViewStats)
+			r0 = ret.Get(0).(*server.Share)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, server.SharePayload) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -456,58 +474,58 @@ func (_m *AnalyticsService) GetPageViews(_a0 context.Context, _a1 server.Request
 	return r0, r1
 }
 
-// AnalyticsService_GetPageViews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageViews'
-type AnalyticsService_GetPageViews_Call struct {
+// AnalyticsService_CreateShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShare'
+type AnalyticsService_CreateShare_Call struct {
 	*mock.Call
 }
 
-// GetPageViews is a helper method to define mock.On call
+// CreateShare is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetPageViews(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetPageViews_Call {
-	return &AnalyticsService_GetPageViews_Call{Call: _e.mock.On("GetPageViews", _a0, _a1)}
+//   - _a1 server.SharePayload
+func (_e *AnalyticsService_Expecter) CreateShare(_a0 interface{}, _a1 interface{}) *AnalyticsService_CreateShare_Call {
+	return &AnalyticsService_CreateShare_Call{Call: _e.mock.On("CreateShare", _a0, _a1)}
 }
 
-func (_c *AnalyticsService_GetPageViews_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetPageViews_Call {
+func (_c *AnalyticsService_CreateShare_Call) Run(run func(_a0 context.Context, _a1 server.SharePayload)) *AnalyticsService_CreateShare_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(server.SharePayload))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetPageViews_Call) Return(_a0 []server.PageViewStats, _a1 error) *AnalyticsService_GetPageViews_Call {
+func (_c *AnalyticsService_CreateShare_Call) Return(_a0 *server.Share, _a1 error) *AnalyticsService_CreateShare_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetPageViews_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.PageViewStats, error)) *AnalyticsService_GetPageViews_Call {
+func (_c *AnalyticsService_CreateShare_Call) RunAndReturn(run func(context.Context, server.SharePayload) (*server.Share, error)) *AnalyticsService_CreateShare_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPages provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetPages(_a0 context.Context, _a1 server.RequestPayload) ([]server.PageStats, error) {
-	ret := _m.Called(_a0, _a1)
+// CreateShareSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) CreateShareSession(_a0 context.Context, _a1 string, _a2 string) (*server.ShareSession, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPages")
+		panic("no return value specified for CreateShareSession")
 	}
 
-	var r0 []server.PageStats
+	var r0 *server.ShareSession
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.PageStats, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*server.ShareSession, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.PageStats); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *server.ShareSession); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.PageStats)
+			r0 = ret.Get(0).(*server.ShareSession)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -515,58 +533,104 @@ func (_m *AnalyticsService) GetPages(_a0 context.Context, _a1 server.RequestPayl
 	return r0, r1
 }
 
-// AnalyticsService_GetPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPages'
-type AnalyticsService_GetPages_Call struct {
+// AnalyticsService_CreateShareSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShareSession'
+type AnalyticsService_CreateShareSession_Call struct {
 	*mock.Call
 }
 
-// GetPages is a helper method to define mock.On call
+// CreateShareSession is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetPages(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetPages_Call {
-	return &AnalyticsService_GetPages_Call{Call: _e.mock.On("GetPages", _a0, _a1)}
+//   - _a1 string
+//   - _a2 string
+func (_e *AnalyticsService_Expecter) CreateShareSession(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_CreateShareSession_Call {
+	return &AnalyticsService_CreateShareSession_Call{Call: _e.mock.On("CreateShareSession", _a0, _a1, _a2)}
 }
 
-func (_c *AnalyticsService_GetPages_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetPages_Call {
+func (_c *AnalyticsService_CreateShareSession_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *AnalyticsService_CreateShareSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetPages_Call) Return(_a0 []server.PageStats, _a1 error) *AnalyticsService_GetPages_Call {
+func (_c *AnalyticsService_CreateShareSession_Call) Return(_a0 *server.ShareSession, _a1 error) *AnalyticsService_CreateShareSession_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetPages_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.PageStats, error)) *AnalyticsService_GetPages_Call {
+func (_c *AnalyticsService_CreateShareSession_Call) RunAndReturn(run func(context.Context, string, string) (*server.ShareSession, error)) *AnalyticsService_CreateShareSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetReferrals provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetReferrals(_a0 context.Context, _a1 server.RequestPayload) ([]server.ReferralStats, error) {
+// DeleteApp provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) DeleteApp(_a0 context.Context, _a1 server.AppPayload) error {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetReferrals")
+		panic("no return value specified for DeleteApp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.AppPayload) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_DeleteApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteApp'
+type AnalyticsService_DeleteApp_Call struct {
+	*mock.Call
+}
+
+// DeleteApp is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.AppPayload
+func (_e *AnalyticsService_Expecter) DeleteApp(_a0 interface{}, _a1 interface{}) *AnalyticsService_DeleteApp_Call {
+	return &AnalyticsService_DeleteApp_Call{Call: _e.mock.On("DeleteApp", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_DeleteApp_Call) Run(run func(_a0 context.Context, _a1 server.AppPayload)) *AnalyticsService_DeleteApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.AppPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_DeleteApp_Call) Return(_a0 error) *AnalyticsService_DeleteApp_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AnalyticsService_DeleteApp_Call) RunAndReturn(run func(context.Context, server.AppPayload) error) *AnalyticsService_DeleteApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportEvents provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) ExportEvents(_a0 context.Context, _a1 server.ExportPayload, _a2 io.Writer) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportEvents")
 	}
 
-	var r0 []server.ReferralStats
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.ReferralStats, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, server.ExportPayload, io.Writer) (int64, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.ReferralStats); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, server.ExportPayload, io.Writer) int64); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.ReferralStats)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, server.ExportPayload, io.Writer) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -574,57 +638,58 @@ func (_m *AnalyticsService) GetReferrals(_a0 context.Context, _a1 server.Request
 	return r0, r1
 }
 
-// AnalyticsService_GetReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferrals'
-type AnalyticsService_GetReferrals_Call struct {
+// AnalyticsService_ExportEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportEvents'
+type AnalyticsService_ExportEvents_Call struct {
 	*mock.Call
 }
 
-// GetReferrals is a helper method to define mock.On call
+// ExportEvents is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetReferrals(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetReferrals_Call {
-	return &AnalyticsService_GetReferrals_Call{Call: _e.mock.On("GetReferrals", _a0, _a1)}
+//   - _a1 server.ExportPayload
+//   - _a2 io.Writer
+func (_e *AnalyticsService_Expecter) ExportEvents(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_ExportEvents_Call {
+	return &AnalyticsService_ExportEvents_Call{Call: _e.mock.On("ExportEvents", _a0, _a1, _a2)}
 }
 
-func (_c *AnalyticsService_GetReferrals_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetReferrals_Call {
+func (_c *AnalyticsService_ExportEvents_Call) Run(run func(_a0 context.Context, _a1 server.ExportPayload, _a2 io.Writer)) *AnalyticsService_ExportEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(server.ExportPayload), args[2].(io.Writer))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetReferrals_Call) Return(_a0 []server.ReferralStats, _a1 error) *AnalyticsService_GetReferrals_Call {
+func (_c *AnalyticsService_ExportEvents_Call) Return(_a0 int64, _a1 error) *AnalyticsService_ExportEvents_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetReferrals_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.ReferralStats, error)) *AnalyticsService_GetReferrals_Call {
+func (_c *AnalyticsService_ExportEvents_Call) RunAndReturn(run func(context.Context, server.ExportPayload, io.Writer) (int64, error)) *AnalyticsService_ExportEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetVisitors provides a mock function with given fields: _a0, _a1
-func (_m *AnalyticsService) GetVisitors(_a0 context.Context, _a1 server.RequestPayload) ([]server.VisitorStats, error) {
+// GetAPITokens provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetAPITokens(_a0 context.Context, _a1 uuid.UUID) ([]server.APIToken, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetVisitors")
+		panic("no return value specified for GetAPITokens")
 	}
 
-	var r0 []server.VisitorStats
+	var r0 []server.APIToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.VisitorStats, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]server.APIToken, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.VisitorStats); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []server.APIToken); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]server.VisitorStats)
+			r0 = ret.Get(0).([]server.APIToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -633,31 +698,1457 @@ func (_m *AnalyticsService) GetVisitors(_a0 context.Context, _a1 server.RequestP
 	return r0, r1
 }
 
-// AnalyticsService_GetVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVisitors'
-type AnalyticsService_GetVisitors_Call struct {
+// AnalyticsService_GetAPITokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPITokens'
+type AnalyticsService_GetAPITokens_Call struct {
 	*mock.Call
 }
 
-// GetVisitors is a helper method to define mock.On call
+// GetAPITokens is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 server.RequestPayload
-func (_e *AnalyticsService_Expecter) GetVisitors(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetVisitors_Call {
-	return &AnalyticsService_GetVisitors_Call{Call: _e.mock.On("GetVisitors", _a0, _a1)}
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetAPITokens(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetAPITokens_Call {
+	return &AnalyticsService_GetAPITokens_Call{Call: _e.mock.On("GetAPITokens", _a0, _a1)}
 }
 
-func (_c *AnalyticsService_GetVisitors_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetVisitors_Call {
+func (_c *AnalyticsService_GetAPITokens_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetAPITokens_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(server.RequestPayload))
+		run(args[0].(context.Context), args[1].(uuid.UUID))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_GetVisitors_Call) Return(_a0 []server.VisitorStats, _a1 error) *AnalyticsService_GetVisitors_Call {
+func (_c *AnalyticsService_GetAPITokens_Call) Return(_a0 []server.APIToken, _a1 error) *AnalyticsService_GetAPITokens_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_GetVisitors_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.VisitorStats, error)) *AnalyticsService_GetVisitors_Call {
+func (_c *AnalyticsService_GetAPITokens_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]server.APIToken, error)) *AnalyticsService_GetAPITokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetApps provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetApps(_a0 context.Context, _a1 uuid.UUID) ([]server.App, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetApps")
+	}
+
+	var r0 []server.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]server.App, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []server.App); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.App)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetApps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetApps'
+type AnalyticsService_GetApps_Call struct {
+	*mock.Call
+}
+
+// GetApps is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetApps(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetApps_Call {
+	return &AnalyticsService_GetApps_Call{Call: _e.mock.On("GetApps", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetApps_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetApps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetApps_Call) Return(_a0 []server.App, _a1 error) *AnalyticsService_GetApps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetApps_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]server.App, error)) *AnalyticsService_GetApps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBrowsers provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetBrowsers(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.BrowserStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBrowsers")
+	}
+
+	var r0 *server.Breakdown[server.BrowserStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.BrowserStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.BrowserStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.BrowserStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetBrowsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBrowsers'
+type AnalyticsService_GetBrowsers_Call struct {
+	*mock.Call
+}
+
+// GetBrowsers is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetBrowsers(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetBrowsers_Call {
+	return &AnalyticsService_GetBrowsers_Call{Call: _e.mock.On("GetBrowsers", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetBrowsers_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetBrowsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetBrowsers_Call) Return(_a0 *server.Breakdown[server.BrowserStats], _a1 error) *AnalyticsService_GetBrowsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetBrowsers_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.BrowserStats], error)) *AnalyticsService_GetBrowsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCountries provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetCountries(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.CountryStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCountries")
+	}
+
+	var r0 *server.Breakdown[server.CountryStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.CountryStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.CountryStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.CountryStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetCountries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountries'
+type AnalyticsService_GetCountries_Call struct {
+	*mock.Call
+}
+
+// GetCountries is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetCountries(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetCountries_Call {
+	return &AnalyticsService_GetCountries_Call{Call: _e.mock.On("GetCountries", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetCountries_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetCountries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetCountries_Call) Return(_a0 *server.Breakdown[server.CountryStats], _a1 error) *AnalyticsService_GetCountries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetCountries_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.CountryStats], error)) *AnalyticsService_GetCountries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeletion provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) GetDeletion(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) (*server.Deletion, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletion")
+	}
+
+	var r0 *server.Deletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Deletion, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) *server.Deletion); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Deletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeletion'
+type AnalyticsService_GetDeletion_Call struct {
+	*mock.Call
+}
+
+// GetDeletion is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetDeletion(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_GetDeletion_Call {
+	return &AnalyticsService_GetDeletion_Call{Call: _e.mock.On("GetDeletion", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_GetDeletion_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_GetDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetDeletion_Call) Return(_a0 *server.Deletion, _a1 error) *AnalyticsService_GetDeletion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetDeletion_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Deletion, error)) *AnalyticsService_GetDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeletions provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetDeletions(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Deletion, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletions")
+	}
+
+	var r0 []server.Deletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Deletion, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Deletion); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Deletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetDeletions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeletions'
+type AnalyticsService_GetDeletions_Call struct {
+	*mock.Call
+}
+
+// GetDeletions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetDeletions(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetDeletions_Call {
+	return &AnalyticsService_GetDeletions_Call{Call: _e.mock.On("GetDeletions", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetDeletions_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetDeletions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetDeletions_Call) Return(_a0 []server.Deletion, _a1 error) *AnalyticsService_GetDeletions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetDeletions_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Deletion, error)) *AnalyticsService_GetDeletions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDevices provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetDevices(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.DeviceStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDevices")
+	}
+
+	var r0 *server.Breakdown[server.DeviceStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.DeviceStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.DeviceStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.DeviceStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDevices'
+type AnalyticsService_GetDevices_Call struct {
+	*mock.Call
+}
+
+// GetDevices is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetDevices(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetDevices_Call {
+	return &AnalyticsService_GetDevices_Call{Call: _e.mock.On("GetDevices", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetDevices_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetDevices_Call) Return(_a0 *server.Breakdown[server.DeviceStats], _a1 error) *AnalyticsService_GetDevices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetDevices_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.DeviceStats], error)) *AnalyticsService_GetDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEventTypes provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetEventTypes(_a0 context.Context, _a1 server.RequestPayload) ([]server.EventTypeStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventTypes")
+	}
+
+	var r0 []server.EventTypeStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.EventTypeStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.EventTypeStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.EventTypeStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetEventTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventTypes'
+type AnalyticsService_GetEventTypes_Call struct {
+	*mock.Call
+}
+
+// GetEventTypes is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.RequestPayload
+func (_e *AnalyticsService_Expecter) GetEventTypes(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetEventTypes_Call {
+	return &AnalyticsService_GetEventTypes_Call{Call: _e.mock.On("GetEventTypes", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetEventTypes_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetEventTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.RequestPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetEventTypes_Call) Return(_a0 []server.EventTypeStats, _a1 error) *AnalyticsService_GetEventTypes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetEventTypes_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.EventTypeStats, error)) *AnalyticsService_GetEventTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImport provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) GetImport(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) (*server.Import, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImport")
+	}
+
+	var r0 *server.Import
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Import, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) *server.Import); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Import)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImport'
+type AnalyticsService_GetImport_Call struct {
+	*mock.Call
+}
+
+// GetImport is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetImport(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_GetImport_Call {
+	return &AnalyticsService_GetImport_Call{Call: _e.mock.On("GetImport", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_GetImport_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_GetImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetImport_Call) Return(_a0 *server.Import, _a1 error) *AnalyticsService_GetImport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetImport_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Import, error)) *AnalyticsService_GetImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetImports provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetImports(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Import, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImports")
+	}
+
+	var r0 []server.Import
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Import, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Import); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Import)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetImports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetImports'
+type AnalyticsService_GetImports_Call struct {
+	*mock.Call
+}
+
+// GetImports is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetImports(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetImports_Call {
+	return &AnalyticsService_GetImports_Call{Call: _e.mock.On("GetImports", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetImports_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetImports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetImports_Call) Return(_a0 []server.Import, _a1 error) *AnalyticsService_GetImports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetImports_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Import, error)) *AnalyticsService_GetImports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInvitations provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetInvitations(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Invitation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInvitations")
+	}
+
+	var r0 []server.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Invitation, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Invitation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInvitations'
+type AnalyticsService_GetInvitations_Call struct {
+	*mock.Call
+}
+
+// GetInvitations is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetInvitations(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetInvitations_Call {
+	return &AnalyticsService_GetInvitations_Call{Call: _e.mock.On("GetInvitations", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetInvitations_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetInvitations_Call) Return(_a0 []server.Invitation, _a1 error) *AnalyticsService_GetInvitations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetInvitations_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Invitation, error)) *AnalyticsService_GetInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMembers provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetMembers(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Member, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMembers")
+	}
+
+	var r0 []server.Member
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Member, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Member); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Member)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMembers'
+type AnalyticsService_GetMembers_Call struct {
+	*mock.Call
+}
+
+// GetMembers is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetMembers(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetMembers_Call {
+	return &AnalyticsService_GetMembers_Call{Call: _e.mock.On("GetMembers", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetMembers_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetMembers_Call) Return(_a0 []server.Member, _a1 error) *AnalyticsService_GetMembers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetMembers_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Member, error)) *AnalyticsService_GetMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOS provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetOS(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.OSStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOS")
+	}
+
+	var r0 *server.Breakdown[server.OSStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.OSStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.OSStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.OSStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOS'
+type AnalyticsService_GetOS_Call struct {
+	*mock.Call
+}
+
+// GetOS is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetOS(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetOS_Call {
+	return &AnalyticsService_GetOS_Call{Call: _e.mock.On("GetOS", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetOS_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetOS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetOS_Call) Return(_a0 *server.Breakdown[server.OSStats], _a1 error) *AnalyticsService_GetOS_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetOS_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.OSStats], error)) *AnalyticsService_GetOS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganizationMembers provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetOrganizationMembers(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Member, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizationMembers")
+	}
+
+	var r0 []server.Member
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Member, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Member); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Member)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetOrganizationMembers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganizationMembers'
+type AnalyticsService_GetOrganizationMembers_Call struct {
+	*mock.Call
+}
+
+// GetOrganizationMembers is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetOrganizationMembers(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetOrganizationMembers_Call {
+	return &AnalyticsService_GetOrganizationMembers_Call{Call: _e.mock.On("GetOrganizationMembers", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetOrganizationMembers_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetOrganizationMembers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetOrganizationMembers_Call) Return(_a0 []server.Member, _a1 error) *AnalyticsService_GetOrganizationMembers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetOrganizationMembers_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Member, error)) *AnalyticsService_GetOrganizationMembers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrganizations provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetOrganizations(_a0 context.Context, _a1 uuid.UUID) ([]server.Organization, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizations")
+	}
+
+	var r0 []server.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]server.Organization, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []server.Organization); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Organization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetOrganizations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrganizations'
+type AnalyticsService_GetOrganizations_Call struct {
+	*mock.Call
+}
+
+// GetOrganizations is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetOrganizations(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetOrganizations_Call {
+	return &AnalyticsService_GetOrganizations_Call{Call: _e.mock.On("GetOrganizations", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetOrganizations_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetOrganizations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetOrganizations_Call) Return(_a0 []server.Organization, _a1 error) *AnalyticsService_GetOrganizations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetOrganizations_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]server.Organization, error)) *AnalyticsService_GetOrganizations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOverview provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetOverview(_a0 context.Context, _a1 server.RequestPayload) (*server.OverviewStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverview")
+	}
+
+	var r0 *server.OverviewStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) (*server.OverviewStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) *server.OverviewStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.OverviewStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetOverview_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOverview'
+type AnalyticsService_GetOverview_Call struct {
+	*mock.Call
+}
+
+// GetOverview is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.RequestPayload
+func (_e *AnalyticsService_Expecter) GetOverview(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetOverview_Call {
+	return &AnalyticsService_GetOverview_Call{Call: _e.mock.On("GetOverview", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetOverview_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetOverview_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.RequestPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetOverview_Call) Return(_a0 *server.OverviewStats, _a1 error) *AnalyticsService_GetOverview_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetOverview_Call) RunAndReturn(run func(context.Context, server.RequestPayload) (*server.OverviewStats, error)) *AnalyticsService_GetOverview_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPageViews provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetPageViews(_a0 context.Context, _a1 server.RequestPayload) ([]server.PageViewStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageViews")
+	}
+
+	var r0 []server.PageViewStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.PageViewStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.PageViewStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.PageViewStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetPageViews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageViews'
+type AnalyticsService_GetPageViews_Call struct {
+	*mock.Call
+}
+
+// GetPageViews is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.RequestPayload
+func (_e *AnalyticsService_Expecter) GetPageViews(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetPageViews_Call {
+	return &AnalyticsService_GetPageViews_Call{Call: _e.mock.On("GetPageViews", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetPageViews_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetPageViews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.RequestPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetPageViews_Call) Return(_a0 []server.PageViewStats, _a1 error) *AnalyticsService_GetPageViews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetPageViews_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.PageViewStats, error)) *AnalyticsService_GetPageViews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPages provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetPages(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.PageStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPages")
+	}
+
+	var r0 *server.Breakdown[server.PageStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.PageStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.PageStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.PageStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetPages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPages'
+type AnalyticsService_GetPages_Call struct {
+	*mock.Call
+}
+
+// GetPages is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetPages(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetPages_Call {
+	return &AnalyticsService_GetPages_Call{Call: _e.mock.On("GetPages", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetPages_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetPages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetPages_Call) Return(_a0 *server.Breakdown[server.PageStats], _a1 error) *AnalyticsService_GetPages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetPages_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.PageStats], error)) *AnalyticsService_GetPages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRealtime provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetRealtime(_a0 context.Context, _a1 uuid.UUID) (*server.RealtimeStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRealtime")
+	}
+
+	var r0 *server.RealtimeStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*server.RealtimeStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *server.RealtimeStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.RealtimeStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetRealtime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRealtime'
+type AnalyticsService_GetRealtime_Call struct {
+	*mock.Call
+}
+
+// GetRealtime is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetRealtime(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetRealtime_Call {
+	return &AnalyticsService_GetRealtime_Call{Call: _e.mock.On("GetRealtime", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetRealtime_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetRealtime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetRealtime_Call) Return(_a0 *server.RealtimeStats, _a1 error) *AnalyticsService_GetRealtime_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetRealtime_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*server.RealtimeStats, error)) *AnalyticsService_GetRealtime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReferrals provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetReferrals(_a0 context.Context, _a1 server.BreakdownPayload) (*server.Breakdown[server.ReferralStats], error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReferrals")
+	}
+
+	var r0 *server.Breakdown[server.ReferralStats]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.ReferralStats], error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.BreakdownPayload) *server.Breakdown[server.ReferralStats]); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Breakdown[server.ReferralStats])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.BreakdownPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetReferrals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferrals'
+type AnalyticsService_GetReferrals_Call struct {
+	*mock.Call
+}
+
+// GetReferrals is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.BreakdownPayload
+func (_e *AnalyticsService_Expecter) GetReferrals(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetReferrals_Call {
+	return &AnalyticsService_GetReferrals_Call{Call: _e.mock.On("GetReferrals", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetReferrals_Call) Run(run func(_a0 context.Context, _a1 server.BreakdownPayload)) *AnalyticsService_GetReferrals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.BreakdownPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetReferrals_Call) Return(_a0 *server.Breakdown[server.ReferralStats], _a1 error) *AnalyticsService_GetReferrals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetReferrals_Call) RunAndReturn(run func(context.Context, server.BreakdownPayload) (*server.Breakdown[server.ReferralStats], error)) *AnalyticsService_GetReferrals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRetention provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetRetention(_a0 context.Context, _a1 server.RetentionPayload) ([]server.RetentionCohort, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRetention")
+	}
+
+	var r0 []server.RetentionCohort
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.RetentionPayload) ([]server.RetentionCohort, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.RetentionPayload) []server.RetentionCohort); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.RetentionCohort)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.RetentionPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetRetention_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRetention'
+type AnalyticsService_GetRetention_Call struct {
+	*mock.Call
+}
+
+// GetRetention is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.RetentionPayload
+func (_e *AnalyticsService_Expecter) GetRetention(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetRetention_Call {
+	return &AnalyticsService_GetRetention_Call{Call: _e.mock.On("GetRetention", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetRetention_Call) Run(run func(_a0 context.Context, _a1 server.RetentionPayload)) *AnalyticsService_GetRetention_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.RetentionPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetRetention_Call) Return(_a0 []server.RetentionCohort, _a1 error) *AnalyticsService_GetRetention_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetRetention_Call) RunAndReturn(run func(context.Context, server.RetentionPayload) ([]server.RetentionCohort, error)) *AnalyticsService_GetRetention_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShares provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) GetShares(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) ([]server.Share, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShares")
+	}
+
+	var r0 []server.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]server.Share, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []server.Share); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetShares_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShares'
+type AnalyticsService_GetShares_Call struct {
+	*mock.Call
+}
+
+// GetShares is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetShares(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_GetShares_Call {
+	return &AnalyticsService_GetShares_Call{Call: _e.mock.On("GetShares", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_GetShares_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_GetShares_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetShares_Call) Return(_a0 []server.Share, _a1 error) *AnalyticsService_GetShares_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetShares_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) ([]server.Share, error)) *AnalyticsService_GetShares_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserFlow provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetUserFlow(_a0 context.Context, _a1 server.FlowPayload) (*server.FlowStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserFlow")
+	}
+
+	var r0 *server.FlowStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.FlowPayload) (*server.FlowStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.FlowPayload) *server.FlowStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.FlowStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.FlowPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetUserFlow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserFlow'
+type AnalyticsService_GetUserFlow_Call struct {
+	*mock.Call
+}
+
+// GetUserFlow is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.FlowPayload
+func (_e *AnalyticsService_Expecter) GetUserFlow(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetUserFlow_Call {
+	return &AnalyticsService_GetUserFlow_Call{Call: _e.mock.On("GetUserFlow", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetUserFlow_Call) Run(run func(_a0 context.Context, _a1 server.FlowPayload)) *AnalyticsService_GetUserFlow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.FlowPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetUserFlow_Call) Return(_a0 *server.FlowStats, _a1 error) *AnalyticsService_GetUserFlow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetUserFlow_Call) RunAndReturn(run func(context.Context, server.FlowPayload) (*server.FlowStats, error)) *AnalyticsService_GetUserFlow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserInvitations provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetUserInvitations(_a0 context.Context, _a1 uuid.UUID) ([]server.Invitation, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserInvitations")
+	}
+
+	var r0 []server.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]server.Invitation, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []server.Invitation); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetUserInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserInvitations'
+type AnalyticsService_GetUserInvitations_Call struct {
+	*mock.Call
+}
+
+// GetUserInvitations is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) GetUserInvitations(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetUserInvitations_Call {
+	return &AnalyticsService_GetUserInvitations_Call{Call: _e.mock.On("GetUserInvitations", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetUserInvitations_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_GetUserInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetUserInvitations_Call) Return(_a0 []server.Invitation, _a1 error) *AnalyticsService_GetUserInvitations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetUserInvitations_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]server.Invitation, error)) *AnalyticsService_GetUserInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVisitors provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) GetVisitors(_a0 context.Context, _a1 server.RequestPayload) ([]server.VisitorStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVisitors")
+	}
+
+	var r0 []server.VisitorStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) ([]server.VisitorStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.RequestPayload) []server.VisitorStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]server.VisitorStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.RequestPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_GetVisitors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVisitors'
+type AnalyticsService_GetVisitors_Call struct {
+	*mock.Call
+}
+
+// GetVisitors is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.RequestPayload
+func (_e *AnalyticsService_Expecter) GetVisitors(_a0 interface{}, _a1 interface{}) *AnalyticsService_GetVisitors_Call {
+	return &AnalyticsService_GetVisitors_Call{Call: _e.mock.On("GetVisitors", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_GetVisitors_Call) Run(run func(_a0 context.Context, _a1 server.RequestPayload)) *AnalyticsService_GetVisitors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(server.RequestPayload))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_GetVisitors_Call) Return(_a0 []server.VisitorStats, _a1 error) *AnalyticsService_GetVisitors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_GetVisitors_Call) RunAndReturn(run func(context.Context, server.RequestPayload) ([]server.VisitorStats, error)) *AnalyticsService_GetVisitors_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -710,6 +2201,104 @@ func (_c *AnalyticsService_ParseUserAgent_Call) RunAndReturn(run func(string) *s
 	return _c
 }
 
+// RemoveMember provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) RemoveMember(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_RemoveMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveMember'
+type AnalyticsService_RemoveMember_Call struct {
+	*mock.Call
+}
+
+// RemoveMember is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) RemoveMember(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_RemoveMember_Call {
+	return &AnalyticsService_RemoveMember_Call{Call: _e.mock.On("RemoveMember", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_RemoveMember_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_RemoveMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_RemoveMember_Call) Return(_a0 error) *AnalyticsService_RemoveMember_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AnalyticsService_RemoveMember_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error) *AnalyticsService_RemoveMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveOrganizationMember provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) RemoveOrganizationMember(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveOrganizationMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_RemoveOrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveOrganizationMember'
+type AnalyticsService_RemoveOrganizationMember_Call struct {
+	*mock.Call
+}
+
+// RemoveOrganizationMember is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) RemoveOrganizationMember(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_RemoveOrganizationMember_Call {
+	return &AnalyticsService_RemoveOrganizationMember_Call{Call: _e.mock.On("RemoveOrganizationMember", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_RemoveOrganizationMember_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_RemoveOrganizationMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_RemoveOrganizationMember_Call) Return(_a0 error) *AnalyticsService_RemoveOrganizationMember_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AnalyticsService_RemoveOrganizationMember_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error) *AnalyticsService_RemoveOrganizationMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ResolveGeoLocation provides a mock function with given fields: _a0
 func (_m *AnalyticsService) ResolveGeoLocation(_a0 string) (*server.GeoLocation, error) {
 	ret := _m.Called(_a0)
@@ -751,19 +2340,248 @@ func (_e *AnalyticsService_Expecter) ResolveGeoLocation(_a0 interface{}) *Analyt
 	return &AnalyticsService_ResolveGeoLocation_Call{Call: _e.mock.On("ResolveGeoLocation", _a0)}
 }
 
-func (_c *AnalyticsService_ResolveGeoLocation_Call) Run(run func(_a0 string)) *AnalyticsService_ResolveGeoLocation_Call {
+func (_c *AnalyticsService_ResolveGeoLocation_Call) Run(run func(_a0 string)) *AnalyticsService_ResolveGeoLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_ResolveGeoLocation_Call) Return(_a0 *server.GeoLocation, _a1 error) *AnalyticsService_ResolveGeoLocation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_ResolveGeoLocation_Call) RunAndReturn(run func(string) (*server.GeoLocation, error)) *AnalyticsService_ResolveGeoLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAPIToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) RevokeAPIToken(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) (*server.APIToken, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIToken")
+	}
+
+	var r0 *server.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*server.APIToken, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *server.APIToken); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.APIToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_RevokeAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIToken'
+type AnalyticsService_RevokeAPIToken_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+func (_e *AnalyticsService_Expecter) RevokeAPIToken(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_RevokeAPIToken_Call {
+	return &AnalyticsService_RevokeAPIToken_Call{Call: _e.mock.On("RevokeAPIToken", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_RevokeAPIToken_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID)) *AnalyticsService_RevokeAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeAPIToken_Call) Return(_a0 *server.APIToken, _a1 error) *AnalyticsService_RevokeAPIToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeAPIToken_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) (*server.APIToken, error)) *AnalyticsService_RevokeAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeInvitation provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) RevokeInvitation(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_RevokeInvitation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeInvitation'
+type AnalyticsService_RevokeInvitation_Call struct {
+	*mock.Call
+}
+
+// RevokeInvitation is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) RevokeInvitation(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_RevokeInvitation_Call {
+	return &AnalyticsService_RevokeInvitation_Call{Call: _e.mock.On("RevokeInvitation", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_RevokeInvitation_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_RevokeInvitation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeInvitation_Call) Return(_a0 error) *AnalyticsService_RevokeInvitation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeInvitation_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error) *AnalyticsService_RevokeInvitation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeShare provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) RevokeShare(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID) (*server.Share, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeShare")
+	}
+
+	var r0 *server.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Share, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) *server.Share); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_RevokeShare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeShare'
+type AnalyticsService_RevokeShare_Call struct {
+	*mock.Call
+}
+
+// RevokeShare is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+func (_e *AnalyticsService_Expecter) RevokeShare(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_RevokeShare_Call {
+	return &AnalyticsService_RevokeShare_Call{Call: _e.mock.On("RevokeShare", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_RevokeShare_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID)) *AnalyticsService_RevokeShare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeShare_Call) Return(_a0 *server.Share, _a1 error) *AnalyticsService_RevokeShare_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_RevokeShare_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*server.Share, error)) *AnalyticsService_RevokeShare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetOrganizationMember provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) SetOrganizationMember(_a0 context.Context, _a1 server.OrganizationMemberPayload) (*server.Member, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetOrganizationMember")
+	}
+
+	var r0 *server.Member
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, server.OrganizationMemberPayload) (*server.Member, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, server.OrganizationMemberPayload) *server.Member); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.Member)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, server.OrganizationMemberPayload) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_SetOrganizationMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetOrganizationMember'
+type AnalyticsService_SetOrganizationMember_Call struct {
+	*mock.Call
+}
+
+// SetOrganizationMember is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 server.OrganizationMemberPayload
+func (_e *AnalyticsService_Expecter) SetOrganizationMember(_a0 interface{}, _a1 interface{}) *AnalyticsService_SetOrganizationMember_Call {
+	return &AnalyticsService_SetOrganizationMember_Call{Call: _e.mock.On("SetOrganizationMember", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_SetOrganizationMember_Call) Run(run func(_a0 context.Context, _a1 server.OrganizationMemberPayload)) *AnalyticsService_SetOrganizationMember_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(server.OrganizationMemberPayload))
 	})
 	return _c
 }
 
-func (_c *AnalyticsService_ResolveGeoLocation_Call) Return(_a0 *server.GeoLocation, _a1 error) *AnalyticsService_ResolveGeoLocation_Call {
+func (_c *AnalyticsService_SetOrganizationMember_Call) Return(_a0 *server.Member, _a1 error) *AnalyticsService_SetOrganizationMember_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *AnalyticsService_ResolveGeoLocation_Call) RunAndReturn(run func(string) (*server.GeoLocation, error)) *AnalyticsService_ResolveGeoLocation_Call {
+func (_c *AnalyticsService_SetOrganizationMember_Call) RunAndReturn(run func(context.Context, server.OrganizationMemberPayload) (*server.Member, error)) *AnalyticsService_SetOrganizationMember_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -825,6 +2643,74 @@ func (_c *AnalyticsService_SignIn_Call) RunAndReturn(run func(context.Context, s
 	return _c
 }
 
+// SubscribeEvents provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) SubscribeEvents(_a0 context.Context, _a1 uuid.UUID) (<-chan server.LiveUpdate, func(), error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeEvents")
+	}
+
+	var r0 <-chan server.LiveUpdate
+	var r1 func()
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (<-chan server.LiveUpdate, func(), error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) <-chan server.LiveUpdate); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan server.LiveUpdate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) func()); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AnalyticsService_SubscribeEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscribeEvents'
+type AnalyticsService_SubscribeEvents_Call struct {
+	*mock.Call
+}
+
+// SubscribeEvents is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+func (_e *AnalyticsService_Expecter) SubscribeEvents(_a0 interface{}, _a1 interface{}) *AnalyticsService_SubscribeEvents_Call {
+	return &AnalyticsService_SubscribeEvents_Call{Call: _e.mock.On("SubscribeEvents", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_SubscribeEvents_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID)) *AnalyticsService_SubscribeEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_SubscribeEvents_Call) Return(_a0 <-chan server.LiveUpdate, _a1 func(), _a2 error) *AnalyticsService_SubscribeEvents_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *AnalyticsService_SubscribeEvents_Call) RunAndReturn(run func(context.Context, uuid.UUID) (<-chan server.LiveUpdate, func(), error)) *AnalyticsService_SubscribeEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TrackEvent provides a mock function with given fields: _a0, _a1
 func (_m *AnalyticsService) TrackEvent(_a0 context.Context, _a1 server.EventPayload) error {
 	ret := _m.Called(_a0, _a1)
@@ -872,6 +2758,68 @@ func (_c *AnalyticsService_TrackEvent_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// TransferApp provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *AnalyticsService) TransferApp(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID, _a4 uuid.UUID) (*server.App, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferApp")
+	}
+
+	var r0 *server.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, uuid.UUID) (*server.App, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, uuid.UUID) *server.App); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.App)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_TransferApp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransferApp'
+type AnalyticsService_TransferApp_Call struct {
+	*mock.Call
+}
+
+// TransferApp is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+//   - _a4 uuid.UUID
+func (_e *AnalyticsService_Expecter) TransferApp(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *AnalyticsService_TransferApp_Call {
+	return &AnalyticsService_TransferApp_Call{Call: _e.mock.On("TransferApp", _a0, _a1, _a2, _a3, _a4)}
+}
+
+func (_c *AnalyticsService_TransferApp_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID, _a4 uuid.UUID)) *AnalyticsService_TransferApp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID), args[4].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_TransferApp_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_TransferApp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_TransferApp_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, uuid.UUID) (*server.App, error)) *AnalyticsService_TransferApp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateApp provides a mock function with given fields: _a0, _a1
 func (_m *AnalyticsService) UpdateApp(_a0 context.Context, _a1 server.AppPayload) (*server.App, error) {
 	ret := _m.Called(_a0, _a1)
@@ -931,22 +2879,144 @@ func (_c *AnalyticsService_UpdateApp_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// UpdateMember provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *AnalyticsService) UpdateMember(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID, _a4 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_UpdateMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMember'
+type AnalyticsService_UpdateMember_Call struct {
+	*mock.Call
+}
+
+// UpdateMember is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 uuid.UUID
+//   - _a2 uuid.UUID
+//   - _a3 uuid.UUID
+//   - _a4 string
+func (_e *AnalyticsService_Expecter) UpdateMember(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *AnalyticsService_UpdateMember_Call {
+	return &AnalyticsService_UpdateMember_Call{Call: _e.mock.On("UpdateMember", _a0, _a1, _a2, _a3, _a4)}
+}
+
+func (_c *AnalyticsService_UpdateMember_Call) Run(run func(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID, _a3 uuid.UUID, _a4 string)) *AnalyticsService_UpdateMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_UpdateMember_Call) Return(_a0 error) *AnalyticsService_UpdateMember_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AnalyticsService_UpdateMember_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, string) error) *AnalyticsService_UpdateMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateAPIToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AnalyticsService) ValidateAPIToken(_a0 context.Context, _a1 string, _a2 string) (*server.APIToken, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAPIToken")
+	}
+
+	var r0 *server.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*server.APIToken, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *server.APIToken); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.APIToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_ValidateAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateAPIToken'
+type AnalyticsService_ValidateAPIToken_Call struct {
+	*mock.Call
+}
+
+// ValidateAPIToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *AnalyticsService_Expecter) ValidateAPIToken(_a0 interface{}, _a1 interface{}, _a2 interface{}) *AnalyticsService_ValidateAPIToken_Call {
+	return &AnalyticsService_ValidateAPIToken_Call{Call: _e.mock.On("ValidateAPIToken", _a0, _a1, _a2)}
+}
+
+func (_c *AnalyticsService_ValidateAPIToken_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *AnalyticsService_ValidateAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateAPIToken_Call) Return(_a0 *server.APIToken, _a1 error) *AnalyticsService_ValidateAPIToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateAPIToken_Call) RunAndReturn(run func(context.Context, string, string) (*server.APIToken, error)) *AnalyticsService_ValidateAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ValidateAppAccess provides a mock function with given fields: _a0, _a1, _a2
-func (_m *AnalyticsService) ValidateAppAccess(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) error {
+func (_m *AnalyticsService) ValidateAppAccess(_a0 context.Context, _a1 uuid.UUID, _a2 uuid.UUID) (*server.App, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ValidateAppAccess")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+	var r0 *server.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*server.App, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *server.App); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.App)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // AnalyticsService_ValidateAppAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateAppAccess'
@@ -969,12 +3039,120 @@ func (_c *AnalyticsService_ValidateAppAccess_Call) Run(run func(_a0 context.Cont
 	return _c
 }
 
-func (_c *AnalyticsService_ValidateAppAccess_Call) Return(_a0 error) *AnalyticsService_ValidateAppAccess_Call {
+func (_c *AnalyticsService_ValidateAppAccess_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_ValidateAppAccess_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateAppAccess_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) (*server.App, error)) *AnalyticsService_ValidateAppAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateRegexFilters provides a mock function with given fields: _a0, _a1
+func (_m *AnalyticsService) ValidateRegexFilters(_a0 context.Context, _a1 []server.Filter) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateRegexFilters")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []server.Filter) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AnalyticsService_ValidateRegexFilters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateRegexFilters'
+type AnalyticsService_ValidateRegexFilters_Call struct {
+	*mock.Call
+}
+
+// ValidateRegexFilters is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 []server.Filter
+func (_e *AnalyticsService_Expecter) ValidateRegexFilters(_a0 interface{}, _a1 interface{}) *AnalyticsService_ValidateRegexFilters_Call {
+	return &AnalyticsService_ValidateRegexFilters_Call{Call: _e.mock.On("ValidateRegexFilters", _a0, _a1)}
+}
+
+func (_c *AnalyticsService_ValidateRegexFilters_Call) Run(run func(_a0 context.Context, _a1 []server.Filter)) *AnalyticsService_ValidateRegexFilters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]server.Filter))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateRegexFilters_Call) Return(_a0 error) *AnalyticsService_ValidateRegexFilters_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *AnalyticsService_ValidateAppAccess_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) error) *AnalyticsService_ValidateAppAccess_Call {
+func (_c *AnalyticsService_ValidateRegexFilters_Call) RunAndReturn(run func(context.Context, []server.Filter) error) *AnalyticsService_ValidateRegexFilters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateShareAccess provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *AnalyticsService) ValidateShareAccess(_a0 context.Context, _a1 string, _a2 string, _a3 string) (*server.App, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateShareAccess")
+	}
+
+	var r0 *server.App
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*server.App, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *server.App); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*server.App)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AnalyticsService_ValidateShareAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateShareAccess'
+type AnalyticsService_ValidateShareAccess_Call struct {
+	*mock.Call
+}
+
+// ValidateShareAccess is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+//   - _a3 string
+func (_e *AnalyticsService_Expecter) ValidateShareAccess(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *AnalyticsService_ValidateShareAccess_Call {
+	return &AnalyticsService_ValidateShareAccess_Call{Call: _e.mock.On("ValidateShareAccess", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *AnalyticsService_ValidateShareAccess_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string, _a3 string)) *AnalyticsService_ValidateShareAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateShareAccess_Call) Return(_a0 *server.App, _a1 error) *AnalyticsService_ValidateShareAccess_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AnalyticsService_ValidateShareAccess_Call) RunAndReturn(run func(context.Context, string, string, string) (*server.App, error)) *AnalyticsService_ValidateShareAccess_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// realtimeWindow is how long a visitor counts as current after their last event.
+const realtimeWindow = 5 * time.Minute
+
+// subscriberBuffer is how many updates a slow subscriber can fall behind before
+// updates are dropped for it, so ingestion never waits on a dashboard.
+const subscriberBuffer = 32
+
+// eventBroker fans out tracked events to live subscribers of an app. Only apps
+// with subscribers are tracked, each under its own lock, so events of apps
+// nobody is watching cost a map lookup and leave nothing behind.
+type eventBroker struct {
+	mu   sync.RWMutex
+	apps map[uuid.UUID]*appStream
+}
+
+// appStream is the live state of an app with subscribers. It keeps the last
+// time each visitor was seen, so the current visitor count can be pushed with
+// every event without querying the database.
+type appStream struct {
+	mu          sync.Mutex
+	subscribers map[chan types.LiveUpdate]struct{}
+	lastSeen    map[string]time.Time
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{apps: make(map[uuid.UUID]*appStream)}
+}
+
+// Subscribe returns a channel of updates for an app and a function that must
+// be called to stop receiving them. The app is forgotten once its last
+// subscriber is gone.
+func (b *eventBroker) Subscribe(trackingID uuid.UUID) (<-chan types.LiveUpdate, func()) {
+	ch := make(chan types.LiveUpdate, subscriberBuffer)
+
+	b.mu.Lock()
+	stream := b.apps[trackingID]
+	if stream == nil {
+		stream = &appStream{
+			subscribers: make(map[chan types.LiveUpdate]struct{}),
+			lastSeen:    make(map[string]time.Time),
+		}
+		b.apps[trackingID] = stream
+	}
+	stream.mu.Lock()
+	stream.subscribers[ch] = struct{}{}
+	stream.mu.Unlock()
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			stream.mu.Lock()
+			delete(stream.subscribers, ch)
+			if len(stream.subscribers) == 0 {
+				delete(b.apps, trackingID)
+			}
+			stream.mu.Unlock()
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Seed records visitors seen before the app was subscribed to, keeping the
+// later time for visitors already seen since.
+func (b *eventBroker) Seed(trackingID uuid.UUID, lastSeen map[string]time.Time) {
+	stream := b.stream(trackingID)
+	if stream == nil {
+		return
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	for visitorID, seen := range lastSeen {
+		if seen.After(stream.lastSeen[visitorID]) {
+			stream.lastSeen[visitorID] = seen
+		}
+	}
+}
+
+// Publish records the visitor as active and sends the event to every
+// subscriber of the app. Events of apps without subscribers are dropped.
+func (b *eventBroker) Publish(trackingID uuid.UUID, visitorID string, event types.LiveEvent) {
+	stream := b.stream(trackingID)
+	if stream == nil {
+		return
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	stream.lastSeen[visitorID] = event.Timestamp
+	for id, seen := range stream.lastSeen {
+		if event.Timestamp.Sub(seen) > realtimeWindow {
+			delete(stream.lastSeen, id)
+		}
+	}
+
+	update := types.LiveUpdate{Event: event, CurrentVisitors: len(stream.lastSeen)}
+	for ch := range stream.subscribers {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
+
+func (b *eventBroker) stream(trackingID uuid.UUID) *appStream {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.apps[trackingID]
+}
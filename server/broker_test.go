@@ -0,0 +1,118 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type BrokerSuite struct {
+	suite.Suite
+	broker *eventBroker
+}
+
+func (suite *BrokerSuite) SetupTest() {
+	suite.broker = newEventBroker()
+}
+
+func (suite *BrokerSuite) TestPublishToSubscribers() {
+	trackingID := uuid.New()
+	updates, unsubscribe := suite.broker.Subscribe(trackingID)
+	defer unsubscribe()
+
+	otherUpdates, unsubscribeOther := suite.broker.Subscribe(uuid.New())
+	defer unsubscribeOther()
+
+	suite.broker.Publish(trackingID, "visitor", types.LiveEvent{Type: "pageview", Timestamp: time.Now()})
+
+	update := <-updates
+	suite.Equal("pageview", update.Event.Type)
+	suite.Equal(1, update.CurrentVisitors)
+	suite.Empty(otherUpdates)
+}
+
+func (suite *BrokerSuite) TestCurrentVisitors() {
+	trackingID := uuid.New()
+	updates, unsubscribe := suite.broker.Subscribe(trackingID)
+	defer unsubscribe()
+
+	start := time.Now()
+	suite.broker.Publish(trackingID, "first", types.LiveEvent{Timestamp: start})
+	suite.broker.Publish(trackingID, "second", types.LiveEvent{Timestamp: start.Add(time.Minute)})
+	suite.broker.Publish(trackingID, "first", types.LiveEvent{Timestamp: start.Add(2 * time.Minute)})
+	// "second" was last seen more than five minutes before this event
+	suite.broker.Publish(trackingID, "third", types.LiveEvent{Timestamp: start.Add(7 * time.Minute)})
+
+	counts := []int{}
+	for range 4 {
+		counts = append(counts, (<-updates).CurrentVisitors)
+	}
+	suite.Equal([]int{1, 2, 2, 2}, counts)
+}
+
+func (suite *BrokerSuite) TestSlowSubscriberDoesNotBlock() {
+	trackingID := uuid.New()
+	updates, unsubscribe := suite.broker.Subscribe(trackingID)
+	defer unsubscribe()
+
+	for range subscriberBuffer + 5 {
+		suite.broker.Publish(trackingID, "visitor", types.LiveEvent{Timestamp: time.Now()})
+	}
+	suite.Len(updates, subscriberBuffer)
+}
+
+func (suite *BrokerSuite) TestUnsubscribe() {
+	trackingID := uuid.New()
+	updates, unsubscribe := suite.broker.Subscribe(trackingID)
+
+	unsubscribe()
+	unsubscribe()
+
+	_, open := <-updates
+	suite.False(open)
+	suite.NotPanics(func() {
+		suite.broker.Publish(trackingID, "visitor", types.LiveEvent{Timestamp: time.Now()})
+	})
+}
+
+func (suite *BrokerSuite) TestAppsWithoutSubscribersAreNotTracked() {
+	trackingID := uuid.New()
+	suite.broker.Publish(trackingID, "visitor", types.LiveEvent{Timestamp: time.Now()})
+	suite.Empty(suite.broker.apps)
+
+	_, unsubscribe := suite.broker.Subscribe(trackingID)
+	_, unsubscribeAgain := suite.broker.Subscribe(trackingID)
+	suite.broker.Publish(trackingID, "visitor", types.LiveEvent{Timestamp: time.Now()})
+	unsubscribe()
+	suite.Len(suite.broker.apps, 1)
+
+	// the app and its visitors are forgotten with the last subscriber
+	unsubscribeAgain()
+	suite.Empty(suite.broker.apps)
+}
+
+func (suite *BrokerSuite) TestSeed() {
+	trackingID := uuid.New()
+	updates, unsubscribe := suite.broker.Subscribe(trackingID)
+	defer unsubscribe()
+
+	now := time.Now()
+	suite.broker.Publish(trackingID, "first", types.LiveEvent{Timestamp: now})
+	// seeding keeps the later of the two times
+	suite.broker.Seed(trackingID, map[string]time.Time{"first": now.Add(-4 * time.Minute), "second": now.Add(-4 * time.Minute)})
+	suite.broker.Publish(trackingID, "third", types.LiveEvent{Timestamp: now.Add(2 * time.Minute)})
+
+	<-updates
+	suite.Equal(2, (<-updates).CurrentVisitors)
+
+	// apps nobody subscribes to aren't seeded
+	suite.broker.Seed(uuid.New(), map[string]time.Time{"first": now})
+	suite.Len(suite.broker.apps, 1)
+}
+
+func TestBrokerSuite(t *testing.T) {
+	suite.Run(t, new(BrokerSuite))
+}
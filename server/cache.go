@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+)
+
+// defaultCacheSize is how many results the in-memory cache holds when no Redis
+// is configured.
+const defaultCacheSize = 10000
+
+// liveCacheTTL is how long results of ranges that include now are reused, and
+// closedCacheTTL how long those of ranges already over are. Closed ranges only
+// change when data is deleted or imported, which invalidates the app's entries.
+const (
+	liveCacheTTL   = time.Minute
+	closedCacheTTL = 24 * time.Hour
+)
+
+// liveRangeGrace is how long after its end a range still counts as live, since
+// events can arrive a little late and the sketches catch up on them.
+const liveRangeGrace = 5 * time.Minute
+
+// includesNow reports whether the range of the request is still receiving events.
+func includesNow(data types.RequestPayload, now time.Time) bool {
+	return !data.EndDate.Valid || data.EndDate.Time.After(now.Add(-liveRangeGrace))
+}
+
+func cacheTTL(data types.RequestPayload, now time.Time) time.Duration {
+	if includesNow(data, now) {
+		return liveCacheTTL
+	}
+	return closedCacheTTL
+}
+
+// normalizeRequest rewrites a request so equivalent ones share a cache key:
+// filters and their values are sorted, since their order doesn't change the
+// result, and live ranges, which end at the time of the request, are truncated
+// to the minute.
+func normalizeRequest(data types.RequestPayload, now time.Time) types.RequestPayload {
+	if len(data.Filters) > 0 {
+		filters := make([]types.Filter, len(data.Filters))
+		for i, filter := range data.Filters {
+			filter.Values = slices.Clone(filter.Values)
+			slices.Sort(filter.Values)
+			filters[i] = filter
+		}
+		slices.SortFunc(filters, func(a, b types.Filter) int {
+			return strings.Compare(filterKey(a), filterKey(b))
+		})
+		data.Filters = filters
+	}
+
+	if includesNow(data, now) {
+		data.StartDate.Time = data.StartDate.Time.Truncate(time.Minute)
+		data.EndDate.Time = data.EndDate.Time.Truncate(time.Minute)
+	}
+	return data
+}
+
+func filterKey(filter types.Filter) string {
+	return filter.Dimension + "\x00" + filter.Operator + "\x00" + strings.Join(filter.Values, "\x00")
+}
+
+// cacheKey hashes the name of a report with its normalized request and options.
+func cacheKey(name string, request any) string {
+	encoded, _ := json.Marshal(struct {
+		Name    string
+		Request any
+	}{name, request})
+	sum := sha256.Sum256(encoded)
+	return name + ":" + hex.EncodeToString(sum[:])
+}
+
+// cachedAnalyticsService serves reports from a cache, computing and storing
+// them on a miss. Cache errors only cost the cached result: the report is
+// computed as if there was no cache.
+type cachedAnalyticsService struct {
+	types.AnalyticsService
+	cache cache.Cache
+	now   func() time.Time
+}
+
+func withCache[T any](ctx context.Context, s *cachedAnalyticsService, name string, data types.RequestPayload, request any, compute func() (T, error)) (T, error) {
+	return withCacheTTL(ctx, s, name, data.TrackingID, cacheTTL(data, s.now()), request, compute)
+}
+
+// withCacheTTL is withCache for reports that can change after their range is
+// over, whose results are stored for ttl whatever their range.
+func withCacheTTL[T any](ctx context.Context, s *cachedAnalyticsService, name string, trackingID uuid.UUID, ttl time.Duration, request any, compute func() (T, error)) (T, error) {
+	key := cacheKey(name, request)
+
+	if encoded, found, err := s.cache.Get(ctx, trackingID, key); err == nil && found {
+		var result T
+		if json.Unmarshal(encoded, &result) == nil {
+			return result, nil
+		}
+	}
+
+	result, err := compute()
+	if err != nil {
+		return result, err
+	}
+	if encoded, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, trackingID, key, encoded, ttl)
+	}
+	return result, nil
+}
+
+func (s *cachedAnalyticsService) DeleteApp(ctx context.Context, data types.AppPayload) error {
+	if err := s.AnalyticsService.DeleteApp(ctx, data); err != nil {
+		return err
+	}
+	_ = s.cache.Invalidate(ctx, data.TrackingID)
+	return nil
+}
+
+func (s *cachedAnalyticsService) normalizeBreakdown(data types.BreakdownPayload) types.BreakdownPayload {
+	data.RequestPayload = normalizeRequest(data.RequestPayload, s.now())
+	return data
+}
+
+func (s *cachedAnalyticsService) GetReferrals(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.ReferralStats], error) {
+	return withCache(ctx, s, "referrals", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.ReferralStats], error) {
+		return s.AnalyticsService.GetReferrals(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetPages(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.PageStats], error) {
+	return withCache(ctx, s, "pages", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.PageStats], error) {
+		return s.AnalyticsService.GetPages(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetBrowsers(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.BrowserStats], error) {
+	return withCache(ctx, s, "browsers", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.BrowserStats], error) {
+		return s.AnalyticsService.GetBrowsers(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetCountries(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.CountryStats], error) {
+	return withCache(ctx, s, "countries", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.CountryStats], error) {
+		return s.AnalyticsService.GetCountries(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetDevices(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.DeviceStats], error) {
+	return withCache(ctx, s, "devices", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.DeviceStats], error) {
+		return s.AnalyticsService.GetDevices(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetOS(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.OSStats], error) {
+	return withCache(ctx, s, "os", data.RequestPayload, s.normalizeBreakdown(data), func() (*types.Breakdown[types.OSStats], error) {
+		return s.AnalyticsService.GetOS(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetVisitors(ctx context.Context, data types.RequestPayload) ([]types.VisitorStats, error) {
+	return withCache(ctx, s, "visitors", data, normalizeRequest(data, s.now()), func() ([]types.VisitorStats, error) {
+		return s.AnalyticsService.GetVisitors(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetPageViews(ctx context.Context, data types.RequestPayload) ([]types.PageViewStats, error) {
+	return withCache(ctx, s, "pageviews", data, normalizeRequest(data, s.now()), func() ([]types.PageViewStats, error) {
+		return s.AnalyticsService.GetPageViews(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetOverview(ctx context.Context, data types.RequestPayload) (*types.OverviewStats, error) {
+	return withCache(ctx, s, "overview", data, normalizeRequest(data, s.now()), func() (*types.OverviewStats, error) {
+		return s.AnalyticsService.GetOverview(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetEventTypes(ctx context.Context, data types.RequestPayload) ([]types.EventTypeStats, error) {
+	return withCache(ctx, s, "event_types", data, normalizeRequest(data, s.now()), func() ([]types.EventTypeStats, error) {
+		return s.AnalyticsService.GetEventTypes(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetRetention(ctx context.Context, data types.RetentionPayload) ([]types.RetentionCohort, error) {
+	normalized := data
+	normalized.RequestPayload = normalizeRequest(data.RequestPayload, s.now())
+	// cohorts keep returning after the range is over, so their retention is
+	// only reused as long as a live range's
+	return withCacheTTL(ctx, s, "retention", data.TrackingID, liveCacheTTL, normalized, func() ([]types.RetentionCohort, error) {
+		return s.AnalyticsService.GetRetention(ctx, data)
+	})
+}
+
+func (s *cachedAnalyticsService) GetUserFlow(ctx context.Context, data types.FlowPayload) (*types.FlowStats, error) {
+	normalized := data
+	normalized.RequestPayload = normalizeRequest(data.RequestPayload, s.now())
+	return withCache(ctx, s, "flow", data.RequestPayload, normalized, func() (*types.FlowStats, error) {
+		return s.AnalyticsService.GetUserFlow(ctx, data)
+	})
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	"github.com/ScMofeoluwa/minalytics/mocks"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *ServiceSuite) newCachedService(now time.Time) (*cachedAnalyticsService, *mocks.AnalyticsService) {
+	inner := mocks.NewAnalyticsService(suite.T())
+	return &cachedAnalyticsService{AnalyticsService: inner, cache: cache.NewLRU(100), now: func() time.Time { return now }}, inner
+}
+
+func (suite *ServiceSuite) TestCachedReports() {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	service, inner := suite.newCachedService(now)
+	trackingID := uuid.New()
+	closed := types.RequestPayload{
+		TrackingID: trackingID,
+		BucketSize: "1 day",
+		StartDate:  sql.NullTime{Time: now.AddDate(0, -1, 0), Valid: true},
+		EndDate:    sql.NullTime{Time: now.AddDate(0, 0, -1), Valid: true},
+		Filters: []types.Filter{
+			{Dimension: "country", Operator: "is", Values: []string{"Nigeria", "Ghana"}},
+			{Dimension: "browser", Operator: "is_not", Values: []string{"Safari"}},
+		},
+	}
+
+	inner.EXPECT().GetOverview(mock.Anything, closed).Return(&types.OverviewStats{Visitors: 7}, nil).Once()
+	stats, err := service.GetOverview(suite.ctx, closed)
+	suite.NoError(err)
+	suite.Equal(7, stats.Visitors)
+
+	// the same filters in another order share the cached result
+	reordered := closed
+	reordered.Filters = []types.Filter{
+		{Dimension: "browser", Operator: "is_not", Values: []string{"Safari"}},
+		{Dimension: "country", Operator: "is", Values: []string{"Ghana", "Nigeria"}},
+	}
+	stats, err = service.GetOverview(suite.ctx, reordered)
+	suite.NoError(err)
+	suite.Equal(7, stats.Visitors)
+
+	// other filters and other reports are computed
+	other := closed
+	other.Filters = closed.Filters[:1]
+	inner.EXPECT().GetOverview(mock.Anything, other).Return(&types.OverviewStats{Visitors: 9}, nil).Once()
+	stats, err = service.GetOverview(suite.ctx, other)
+	suite.NoError(err)
+	suite.Equal(9, stats.Visitors)
+
+	breakdown := types.BreakdownPayload{RequestPayload: closed, Limit: 10, Page: 1}
+	inner.EXPECT().GetCountries(mock.Anything, breakdown).Return(&types.Breakdown[types.CountryStats]{Results: []types.CountryStats{{Country: "Nigeria"}}}, nil).Once()
+	for range 2 {
+		countries, err := service.GetCountries(suite.ctx, breakdown)
+		suite.NoError(err)
+		suite.Equal("Nigeria", countries.Results[0].Country)
+	}
+
+	// errors aren't cached
+	inner.EXPECT().GetPageViews(mock.Anything, closed).Return(nil, errors.New("connection reset")).Once()
+	_, err = service.GetPageViews(suite.ctx, closed)
+	suite.Error(err)
+	inner.EXPECT().GetPageViews(mock.Anything, closed).Return([]types.PageViewStats{}, nil).Once()
+	_, err = service.GetPageViews(suite.ctx, closed)
+	suite.NoError(err)
+
+	// deleting the app drops its results
+	payload := types.AppPayload{UserID: uuid.New(), TrackingID: trackingID}
+	inner.EXPECT().DeleteApp(mock.Anything, payload).Return(nil).Once()
+	suite.NoError(service.DeleteApp(suite.ctx, payload))
+	inner.EXPECT().GetOverview(mock.Anything, closed).Return(&types.OverviewStats{}, nil).Once()
+	_, err = service.GetOverview(suite.ctx, closed)
+	suite.NoError(err)
+}
+
+func (suite *ServiceSuite) TestCacheTTL() {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+
+	testCases := []struct {
+		name string
+		end  sql.NullTime
+		ttl  time.Duration
+	}{
+		{name: "open ended range", end: sql.NullTime{}, ttl: liveCacheTTL},
+		{name: "range ending now", end: at(now), ttl: liveCacheTTL},
+		{name: "range ending tomorrow", end: at(now.AddDate(0, 0, 1)), ttl: liveCacheTTL},
+		{name: "range that just ended", end: at(now.Add(-time.Minute)), ttl: liveCacheTTL},
+		{name: "closed range", end: at(now.AddDate(0, 0, -1)), ttl: closedCacheTTL},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.ttl, cacheTTL(types.RequestPayload{EndDate: tc.end}, now))
+		})
+	}
+}
+
+func (suite *ServiceSuite) TestLiveRangesShareKeysWithinAMinute() {
+	now := time.Date(2025, 3, 31, 12, 0, 30, 0, time.UTC)
+	request := func(end time.Time) types.RequestPayload {
+		return types.RequestPayload{StartDate: sql.NullTime{Time: end.AddDate(0, 0, -7), Valid: true}, EndDate: sql.NullTime{Time: end, Valid: true}}
+	}
+
+	key := cacheKey("visitors", normalizeRequest(request(now), now))
+	suite.Equal(key, cacheKey("visitors", normalizeRequest(request(now.Add(20*time.Second)), now)))
+	suite.NotEqual(key, cacheKey("visitors", normalizeRequest(request(now.Add(time.Minute)), now)))
+	suite.NotEqual(key, cacheKey("pageviews", normalizeRequest(request(now), now)))
+
+	// closed ranges keep their exact bounds
+	closed := request(now.AddDate(0, 0, -1))
+	suite.Equal(closed, normalizeRequest(closed, now))
+}
+
+// ttlRecorder is a cache remembering the TTL each result was stored with.
+type ttlRecorder struct {
+	cache.Cache
+	ttls map[string]time.Duration
+}
+
+func (r *ttlRecorder) Set(ctx context.Context, trackingID uuid.UUID, key string, value []byte, ttl time.Duration) error {
+	r.ttls[strings.SplitN(key, ":", 2)[0]] = ttl
+	return r.Cache.Set(ctx, trackingID, key, value, ttl)
+}
+
+func (suite *ServiceSuite) TestRetentionOfClosedRangesIsCachedAsLive() {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	service, inner := suite.newCachedService(now)
+	recorder := &ttlRecorder{Cache: service.cache, ttls: map[string]time.Duration{}}
+	service.cache = recorder
+	closed := types.RequestPayload{
+		TrackingID: uuid.New(),
+		StartDate:  sql.NullTime{Time: now.AddDate(0, -1, 0), Valid: true},
+		EndDate:    sql.NullTime{Time: now.AddDate(0, 0, -7), Valid: true},
+	}
+
+	// cohorts of a closed range keep returning, unlike its other reports
+	retention := types.RetentionPayload{RequestPayload: closed, Period: "week"}
+	inner.EXPECT().GetRetention(mock.Anything, retention).Return([]types.RetentionCohort{}, nil).Once()
+	_, err := service.GetRetention(suite.ctx, retention)
+	suite.NoError(err)
+	inner.EXPECT().GetOverview(mock.Anything, closed).Return(&types.OverviewStats{}, nil).Once()
+	_, err = service.GetOverview(suite.ctx, closed)
+	suite.NoError(err)
+
+	suite.Equal(liveCacheTTL, recorder.ttls["retention"])
+	suite.Equal(closedCacheTTL, recorder.ttls["overview"])
+}
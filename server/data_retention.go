@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+)
+
+// forever is the data retention of apps that keep their raw events.
+const forever = "forever"
+
+// retentionInterval is how often expired events are looked for.
+const retentionInterval = time.Hour
+
+// retentionBatchSize bounds how many events one delete removes, so expiring a
+// large backlog doesn't hold one long transaction.
+const retentionBatchSize = 10000
+
+func dataRetention(retention *string) string {
+	if retention == nil {
+		return forever
+	}
+	return *retention
+}
+
+// dataRetentionJob deletes the raw events of each app, and the hourly visitors
+// made from them, once they are older than its data retention. Expired data
+// stays available as hourly sketches and counts, so only hours the sketch
+// builder has covered are deleted.
+//
+// The job keeps no state of its own: what is left to delete is read
+// from the database on every run, so a run interrupted by a restart is finished
+// by the next one.
+type dataRetentionJob struct {
+	querier database.Querier
+	logger  *zap.Logger
+	// cache, if set, has the entries of apps that lost events invalidated.
+	cache cache.Cache
+}
+
+func newDataRetentionJob(querier database.Querier, logger *zap.Logger, resultCache cache.Cache) *dataRetentionJob {
+	return &dataRetentionJob{querier: querier, logger: logger, cache: resultCache}
+}
+
+func (j *dataRetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.enforce(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("failed to enforce data retention", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *dataRetentionJob) enforce(ctx context.Context) error {
+	cutoffs, err := j.querier.GetRetentionCutoffs(ctx)
+	if err != nil || len(cutoffs) == 0 {
+		return err
+	}
+
+	watermark, err := j.querier.GetSketchWatermark(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, cutoff := range cutoffs {
+		if cutoff.ExpiresBefore.Time.After(watermark.Time) {
+			cutoff.ExpiresBefore.Time = watermark.Time
+		}
+		if err := j.expire(ctx, cutoff); err != nil {
+			j.logger.Error("failed to expire app events", zap.String("trackingID", cutoff.TrackingID.String()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (j *dataRetentionJob) expire(ctx context.Context, cutoff database.GetRetentionCutoffsRow) error {
+	var total int64
+	for {
+		deleted, err := j.querier.DeleteExpiredEvents(ctx, database.DeleteExpiredEventsParams{
+			TrackingID:    cutoff.TrackingID,
+			ExpiresBefore: cutoff.ExpiresBefore,
+			BatchSize:     retentionBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		total += deleted
+		if deleted < retentionBatchSize {
+			break
+		}
+	}
+
+	err := j.querier.DeleteExpiredEventVisitors(ctx, database.DeleteExpiredEventVisitorsParams{
+		TrackingID:    cutoff.TrackingID,
+		ExpiresBefore: cutoff.ExpiresBefore,
+	})
+	if err != nil {
+		return err
+	}
+
+	if j.cache != nil && total > 0 {
+		return j.cache.Invalidate(ctx, cutoff.TrackingID)
+	}
+	return nil
+}
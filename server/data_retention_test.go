@@ -0,0 +1,99 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func (suite *ServiceSuite) TestDataRetentionJob() {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	expired, failing := uuid.New(), uuid.New()
+	cutoff := at(now.AddDate(0, 0, -30))
+
+	suite.mockRepo.EXPECT().GetRetentionCutoffs(mock.Anything).Return([]database.GetRetentionCutoffsRow{
+		{TrackingID: failing, ExpiresBefore: cutoff},
+		{TrackingID: expired, ExpiresBefore: cutoff},
+	}, nil).Once()
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(now), nil).Once()
+
+	// one app failing doesn't stop the others
+	suite.mockRepo.EXPECT().DeleteExpiredEvents(mock.Anything, database.DeleteExpiredEventsParams{
+		TrackingID: failing, ExpiresBefore: cutoff, BatchSize: retentionBatchSize,
+	}).Return(0, errors.New("connection reset")).Once()
+
+	// deletes run in batches until one comes back short
+	suite.mockRepo.EXPECT().DeleteExpiredEvents(mock.Anything, database.DeleteExpiredEventsParams{
+		TrackingID: expired, ExpiresBefore: cutoff, BatchSize: retentionBatchSize,
+	}).Return(retentionBatchSize, nil).Once()
+	suite.mockRepo.EXPECT().DeleteExpiredEvents(mock.Anything, database.DeleteExpiredEventsParams{
+		TrackingID: expired, ExpiresBefore: cutoff, BatchSize: retentionBatchSize,
+	}).Return(42, nil).Once()
+	// along with the hourly visitors made from them
+	suite.mockRepo.EXPECT().DeleteExpiredEventVisitors(mock.Anything, database.DeleteExpiredEventVisitorsParams{
+		TrackingID: expired, ExpiresBefore: cutoff,
+	}).Return(nil).Once()
+
+	resultCache := cache.NewLRU(10)
+	suite.NoError(resultCache.Set(suite.ctx, expired, "overview", []byte("{}"), time.Hour))
+	suite.NoError(resultCache.Set(suite.ctx, failing, "overview", []byte("{}"), time.Hour))
+
+	suite.NoError(newDataRetentionJob(suite.mockRepo, zap.NewNop(), resultCache).enforce(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+
+	// only the app that lost events has its reports recomputed
+	_, found, _ := resultCache.Get(suite.ctx, expired, "overview")
+	suite.False(found)
+	_, found, _ = resultCache.Get(suite.ctx, failing, "overview")
+	suite.True(found)
+}
+
+func (suite *ServiceSuite) TestDataRetentionJobWaitsForSketches() {
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	trackingID := uuid.New()
+	watermark := at(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	suite.mockRepo.EXPECT().GetRetentionCutoffs(mock.Anything).Return([]database.GetRetentionCutoffsRow{
+		{TrackingID: trackingID, ExpiresBefore: at(watermark.Time.AddDate(0, 0, 2))},
+	}, nil).Once()
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(watermark, nil).Once()
+	suite.mockRepo.EXPECT().DeleteExpiredEvents(mock.Anything, database.DeleteExpiredEventsParams{
+		TrackingID: trackingID, ExpiresBefore: watermark, BatchSize: retentionBatchSize,
+	}).Return(0, nil).Once()
+	suite.mockRepo.EXPECT().DeleteExpiredEventVisitors(mock.Anything, database.DeleteExpiredEventVisitorsParams{
+		TrackingID: trackingID, ExpiresBefore: watermark,
+	}).Return(nil).Once()
+
+	suite.NoError(newDataRetentionJob(suite.mockRepo, zap.NewNop(), nil).enforce(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestUpdateAppDataRetention() {
+	userID, trackingID := uuid.New(), uuid.New()
+	retention := "30 days"
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Once()
+	suite.mockRepo.EXPECT().UpdateApp(mock.Anything, mock.MatchedBy(func(arg database.UpdateAppParams) bool {
+		return arg.DataRetention != nil && *arg.DataRetention == retention
+	})).Return(database.App{TrackingID: trackingID, DataRetention: &retention}, nil).Once()
+
+	app, err := suite.service.UpdateApp(suite.ctx, types.AppPayload{UserID: userID, TrackingID: trackingID, DataRetention: &retention})
+	suite.NoError(err)
+	suite.Equal(retention, app.DataRetention)
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Once()
+	suite.mockRepo.EXPECT().UpdateApp(mock.Anything, mock.Anything).Return(database.App{TrackingID: trackingID}, nil).Once()
+
+	app, err = suite.service.UpdateApp(suite.ctx, types.AppPayload{UserID: userID, TrackingID: trackingID, DataRetention: stringPtr(forever)})
+	suite.NoError(err)
+	suite.Equal(forever, app.DataRetention)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
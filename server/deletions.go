@@ -0,0 +1,340 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var (
+	ErrDeletionNotFound   = errors.New("deletion not found")
+	ErrNoDeletionCriteria = errors.New("specify a date range, filters or a visitor ID of the events to delete")
+)
+
+// Statuses of a deletion. Deletions start out pending and are running while
+// the job deletes their events. Dry runs only record what they matched.
+const (
+	deletionDryRun    = "dry_run"
+	deletionPending   = "pending"
+	deletionCompleted = "completed"
+	deletionFailed    = "failed"
+)
+
+// deletionInterval is how often the deletion job looks for pending deletions.
+const deletionInterval = 5 * time.Second
+
+// deletionHeartbeat is how often a running deletion renews its lease, and
+// deletionLease how long the lease holds without being renewed. Running
+// deletions whose lease lapsed were interrupted, and are run again.
+const (
+	deletionHeartbeat = 15 * time.Second
+	deletionLease     = time.Minute
+)
+
+// errDeletionLeaseLost stops a deletion whose lease was taken over, after its
+// heartbeat went stale, so that only the new holder finishes it.
+var errDeletionLeaseLost = errors.New("deletion lease lost")
+
+func newDeletion(row database.Deletion) (types.Deletion, error) {
+	deletion := types.Deletion{
+		ID:              row.ID,
+		TrackingID:      row.TrackingID,
+		AppName:         row.AppName,
+		UserEmail:       row.UserEmail,
+		Status:          row.Status,
+		StartDate:       nullTime(row.StartDate),
+		EndDate:         nullTime(row.EndDate),
+		MatchedEvents:   row.MatchedEvents,
+		MatchedVisitors: row.MatchedVisitors,
+		DeletedEvents:   row.DeletedEvents,
+		FirstEvent:      nullTime(row.FirstEvent),
+		LastEvent:       nullTime(row.LastEvent),
+		CreatedAt:       row.CreatedAt.Time,
+		FinishedAt:      nullTime(row.FinishedAt),
+	}
+	if row.Filters != nil {
+		if err := json.Unmarshal(row.Filters, &deletion.Filters); err != nil {
+			return types.Deletion{}, err
+		}
+	}
+	if row.UserID.Valid {
+		deletion.UserID = &row.UserID.UUID
+	}
+	if row.VisitorID != nil {
+		deletion.VisitorID = *row.VisitorID
+	}
+	if row.Error != nil {
+		deletion.Error = *row.Error
+	}
+	return deletion, nil
+}
+
+// CreateDeletion records a deletion of the events of an app along with the
+// events it matches, and queues it for the deletion job. Dry runs are recorded
+// but delete nothing.
+func (s *analyticsService) CreateDeletion(ctx context.Context, data types.DeletionPayload) (*types.Deletion, error) {
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	// deleting every event of an app has to be asked for explicitly
+	if !data.StartDate.Valid && !data.EndDate.Valid && len(data.Filters) == 0 && data.VisitorID == "" {
+		return nil, ErrNoDeletionCriteria
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.Querier.CountDeletionEvents(ctx, database.CountDeletionEventsParams{
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+		VisitorID:  optionalString(data.VisitorID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := deletionPending
+	if data.DryRun {
+		status = deletionDryRun
+	}
+	row, err := s.Querier.CreateDeletion(ctx, database.CreateDeletionParams{
+		TrackingID:      data.TrackingID,
+		UserID:          data.UserID,
+		Status:          status,
+		StartDate:       data.StartDate,
+		EndDate:         data.EndDate,
+		Filters:         filters,
+		VisitorID:       optionalString(data.VisitorID),
+		MatchedEvents:   matched.Events,
+		MatchedVisitors: matched.Visitors,
+		FirstEvent:      matched.FirstEvent,
+		LastEvent:       matched.LastEvent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deletion, err := newDeletion(row)
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (s *analyticsService) GetDeletions(ctx context.Context, userID, trackingID uuid.UUID) ([]types.Deletion, error) {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetDeletions(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	deletions := make([]types.Deletion, 0, len(rows))
+	for _, row := range rows {
+		deletion, err := newDeletion(row)
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, deletion)
+	}
+	return deletions, nil
+}
+
+func (s *analyticsService) GetDeletion(ctx context.Context, userID, trackingID, deletionID uuid.UUID) (*types.Deletion, error) {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	row, err := s.Querier.GetDeletion(ctx, database.GetDeletionParams{ID: deletionID, TrackingID: trackingID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDeletionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deletion, err := newDeletion(row)
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+// deletionJob runs queued deletions one at a time. A deletion deletes the
+// events it matched when it was asked for, up to its last matched event, so
+// events tracked since are kept. Once they are gone the sketches and cached
+// reports are rebuilt over the range of the deleted events.
+//
+// Several servers can run the job. A deletion is leased to the process
+// running it, which renews the lease until it is done. Deleting the same
+// events twice deletes nothing the second time, so a deletion whose lease
+// lapsed, as the process running it stopped, is just run again by any server.
+type deletionJob struct {
+	querier database.Querier
+	logger  *zap.Logger
+	// eventsRetention is the EVENTS_RETENTION horizon, if set.
+	eventsRetention *string
+	// cache, if set, has the entries of apps that lost events invalidated.
+	cache cache.Cache
+	// holder names this process, see leaseHolder.
+	holder string
+	// heartbeat is how often leases are renewed, deletionHeartbeat but in tests.
+	heartbeat time.Duration
+}
+
+func newDeletionJob(querier database.Querier, logger *zap.Logger, eventsRetention *string, resultCache cache.Cache) *deletionJob {
+	return &deletionJob{
+		querier:         querier,
+		logger:          logger,
+		eventsRetention: eventsRetention,
+		cache:           resultCache,
+		holder:          leaseHolder(),
+		heartbeat:       deletionHeartbeat,
+	}
+}
+
+func (j *deletionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(deletionInterval)
+	defer ticker.Stop()
+
+	for {
+		// other servers can stop at any time, not only this one
+		if err := j.resume(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("failed to resume interrupted deletions", zap.Error(err))
+		}
+		if err := j.runPending(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("failed to run deletions", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// resume takes over the running deletions whose lease lapsed, as the process
+// running them stopped, and runs them again.
+func (j *deletionJob) resume(ctx context.Context) error {
+	deletions, err := j.querier.ClaimStaleDeletions(ctx, database.ClaimStaleDeletionsParams{
+		LockedBy:     j.holder,
+		LeaseSeconds: deletionLease.Seconds(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, deletion := range deletions {
+		if err := j.run(ctx, deletion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hold renews the lease of deletion until release is called. The returned
+// context is cancelled with errDeletionLeaseLost once another process took
+// the lease.
+func (j *deletionJob) hold(ctx context.Context, deletion database.Deletion) (context.Context, func()) {
+	renew := func(ctx context.Context) (int64, error) {
+		return j.querier.HeartbeatDeletion(ctx, database.HeartbeatDeletionParams{ID: deletion.ID, LockedBy: j.holder})
+	}
+	return holdLease(ctx, j.heartbeat, renew, errDeletionLeaseLost, func(err error) {
+		j.logger.Warn("failed to renew deletion lease", zap.String("deletionID", deletion.ID.String()), zap.Error(err))
+	})
+}
+
+func (j *deletionJob) runPending(ctx context.Context) error {
+	for {
+		deletion, err := j.querier.ClaimPendingDeletion(ctx, j.holder)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := j.run(ctx, deletion); err != nil {
+			return err
+		}
+	}
+}
+
+// run runs deletion, which this process holds the lease of.
+func (j *deletionJob) run(ctx context.Context, deletion database.Deletion) error {
+	ctx, release := j.hold(ctx, deletion)
+	defer release()
+
+	err := j.delete(ctx, deletion)
+	if err == nil {
+		err = settleEvents(ctx, j.querier, j.eventsRetention, j.cache, deletion.TrackingID, deletion.FirstEvent, deletion.LastEvent)
+	}
+	if ctx.Err() != nil {
+		// still running, so whoever takes the lease over runs it again
+		return context.Cause(ctx)
+	}
+
+	params := database.FinishDeletionParams{ID: deletion.ID, Status: deletionCompleted}
+	if err != nil {
+		j.logger.Warn("deletion failed", zap.String("deletionID", deletion.ID.String()), zap.Error(err))
+		message := err.Error()
+		params.Status, params.Error = deletionFailed, &message
+	}
+	return j.querier.FinishDeletion(ctx, params)
+}
+
+// delete deletes the matched events of deletion in batches, recording how many
+// are gone as it goes.
+func (j *deletionJob) delete(ctx context.Context, deletion database.Deletion) error {
+	if !deletion.LastEvent.Valid {
+		return nil
+	}
+
+	// the matched events end before the end of the range too, and timestamps
+	// are stored to the microsecond
+	end := deletion.LastEvent
+	end.Time = end.Time.Add(time.Microsecond)
+
+	var total int64
+	for {
+		deleted, err := j.querier.DeleteMatchingEvents(ctx, database.DeleteMatchingEventsParams{
+			TrackingID: deletion.TrackingID,
+			Filters:    deletion.Filters,
+			StartDate:  deletion.StartDate,
+			EndDate:    end,
+			VisitorID:  deletion.VisitorID,
+			BatchSize:  retentionBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		total += deleted
+		err = j.querier.UpdateDeletionProgress(ctx, database.UpdateDeletionProgressParams{
+			DeletedEvents: total,
+			ID:            deletion.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted < retentionBatchSize {
+			return nil
+		}
+	}
+}
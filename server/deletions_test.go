@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func (suite *ServiceSuite) TestCreateDeletion() {
+	userID, trackingID := uuid.New(), uuid.New()
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	filters := []types.Filter{{Dimension: "referrer", Operator: "contains", Values: []string{"spam"}}}
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Times(3)
+
+	// an app's events aren't all deleted by accident
+	_, err := suite.service.CreateDeletion(suite.ctx, types.DeletionPayload{UserID: userID, TrackingID: trackingID})
+	suite.ErrorIs(err, ErrNoDeletionCriteria)
+
+	suite.mockRepo.EXPECT().CountDeletionEvents(mock.Anything, database.CountDeletionEventsParams{
+		TrackingID: trackingID,
+		Filters:    []byte(`[{"dimension":"referrer","operator":"contains","values":["spam"]}]`),
+	}).Return(database.CountDeletionEventsRow{Events: 12, Visitors: 3, FirstEvent: at(first), LastEvent: at(first.Add(time.Hour))}, nil).Twice()
+	suite.mockRepo.EXPECT().CreateDeletion(mock.Anything, mock.MatchedBy(func(arg database.CreateDeletionParams) bool {
+		return arg.Status == deletionDryRun && arg.UserID == userID && arg.MatchedEvents == 12 && arg.MatchedVisitors == 3
+	})).RunAndReturn(func(_ context.Context, arg database.CreateDeletionParams) (database.Deletion, error) {
+		return database.Deletion{
+			ID: uuid.New(), TrackingID: trackingID, UserID: uuid.NullUUID{UUID: userID, Valid: true}, Status: arg.Status, Filters: arg.Filters,
+			MatchedEvents: arg.MatchedEvents, AppName: "Blog", UserEmail: "ada@example.com",
+		}, nil
+	}).Once()
+
+	deletion, err := suite.service.CreateDeletion(suite.ctx, types.DeletionPayload{UserID: userID, TrackingID: trackingID, Filters: filters, DryRun: true})
+	suite.NoError(err)
+	suite.Equal(&userID, deletion.UserID)
+	suite.Equal("ada@example.com", deletion.UserEmail)
+	suite.Equal("Blog", deletion.AppName)
+	suite.Equal(deletionDryRun, deletion.Status)
+	suite.Equal(filters, deletion.Filters)
+	suite.Equal(int64(12), deletion.MatchedEvents)
+
+	suite.mockRepo.EXPECT().CreateDeletion(mock.Anything, mock.MatchedBy(func(arg database.CreateDeletionParams) bool {
+		return arg.Status == deletionPending && arg.FirstEvent.Time.Equal(first) && arg.VisitorID == nil
+	})).Return(database.Deletion{ID: uuid.New(), TrackingID: trackingID, Status: deletionPending}, nil).Once()
+
+	deletion, err = suite.service.CreateDeletion(suite.ctx, types.DeletionPayload{UserID: userID, TrackingID: trackingID, Filters: filters})
+	suite.NoError(err)
+	suite.Equal(deletionPending, deletion.Status)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetDeletion() {
+	userID, trackingID, deletionID := uuid.New(), uuid.New(), uuid.New()
+	visitorID := "visitor-1"
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Twice()
+	suite.mockRepo.EXPECT().GetDeletion(mock.Anything, database.GetDeletionParams{ID: deletionID, TrackingID: trackingID}).Return(database.Deletion{
+		ID: deletionID, TrackingID: trackingID, Status: deletionCompleted, VisitorID: &visitorID, DeletedEvents: 4,
+	}, nil).Once()
+
+	deletion, err := suite.service.GetDeletion(suite.ctx, userID, trackingID, deletionID)
+	suite.NoError(err)
+	suite.Equal(visitorID, deletion.VisitorID)
+	suite.Equal(int64(4), deletion.DeletedEvents)
+	suite.Nil(deletion.Filters)
+	// the user who asked for it is gone
+	suite.Nil(deletion.UserID)
+
+	suite.mockRepo.EXPECT().GetDeletion(mock.Anything, mock.Anything).Return(database.Deletion{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.GetDeletion(suite.ctx, userID, trackingID, uuid.New())
+	suite.ErrorIs(err, ErrDeletionNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestDeletionJob() {
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	first := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	last := first.Add(90 * time.Minute)
+	visitorID := "visitor-1"
+	deletion := database.Deletion{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Status:     "running",
+		VisitorID:  &visitorID,
+		FirstEvent: at(first),
+		LastEvent:  at(last),
+	}
+
+	suite.mockRepo.EXPECT().ClaimPendingDeletion(mock.Anything, leaseHolder()).Return(deletion, nil).Once()
+	suite.mockRepo.EXPECT().ClaimPendingDeletion(mock.Anything, leaseHolder()).Return(database.Deletion{}, pgx.ErrNoRows).Once()
+
+	// events tracked since the deletion was asked for are kept
+	params := database.DeleteMatchingEventsParams{
+		TrackingID: deletion.TrackingID,
+		EndDate:    at(last.Add(time.Microsecond)),
+		VisitorID:  &visitorID,
+		BatchSize:  retentionBatchSize,
+	}
+	suite.mockRepo.EXPECT().DeleteMatchingEvents(mock.Anything, params).Return(retentionBatchSize, nil).Once()
+	suite.mockRepo.EXPECT().UpdateDeletionProgress(mock.Anything, database.UpdateDeletionProgressParams{DeletedEvents: retentionBatchSize, ID: deletion.ID}).Return(nil).Once()
+	suite.mockRepo.EXPECT().DeleteMatchingEvents(mock.Anything, params).Return(3, nil).Once()
+	suite.mockRepo.EXPECT().UpdateDeletionProgress(mock.Anything, database.UpdateDeletionProgressParams{DeletedEvents: retentionBatchSize + 3, ID: deletion.ID}).Return(nil).Once()
+
+	// the sketches of the deleted range are rebuilt over whole hours
+	suite.mockRepo.EXPECT().GetImportHorizon(mock.Anything, database.GetImportHorizonParams{TrackingID: deletion.TrackingID}).Return(sql.NullTime{}, nil).Once()
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(first.AddDate(0, 0, 1)), nil).Once()
+	suite.mockRepo.EXPECT().DeleteAppSketches(mock.Anything, database.DeleteAppSketchesParams{
+		TrackingID: deletion.TrackingID, StartDate: at(first.Truncate(time.Hour)), EndDate: at(last.Truncate(time.Hour).Add(time.Hour)),
+	}).Return(nil).Once()
+	suite.mockRepo.EXPECT().GetAppSketchInputs(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	suite.mockRepo.EXPECT().FinishDeletion(mock.Anything, database.FinishDeletionParams{ID: deletion.ID, Status: deletionCompleted}).Return(nil).Once()
+
+	resultCache := cache.NewLRU(10)
+	suite.NoError(resultCache.Set(suite.ctx, deletion.TrackingID, "overview", []byte("{}"), time.Hour))
+
+	suite.NoError(newDeletionJob(suite.mockRepo, zap.NewNop(), nil, resultCache).runPending(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+
+	_, found, _ := resultCache.Get(suite.ctx, deletion.TrackingID, "overview")
+	suite.False(found)
+}
+
+func (suite *ServiceSuite) TestDeletionJobNothingMatched() {
+	deletion := database.Deletion{ID: uuid.New(), TrackingID: uuid.New(), Status: "running"}
+
+	suite.mockRepo.EXPECT().FinishDeletion(mock.Anything, database.FinishDeletionParams{ID: deletion.ID, Status: deletionCompleted}).Return(nil).Once()
+
+	suite.NoError(newDeletionJob(suite.mockRepo, zap.NewNop(), nil, nil).run(suite.ctx, deletion))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestDeletionJobResumes() {
+	deletion := database.Deletion{ID: uuid.New(), TrackingID: uuid.New(), Status: "running"}
+
+	// a deletion whose lease lapsed is run again by whoever takes it over
+	suite.mockRepo.EXPECT().ClaimStaleDeletions(mock.Anything, database.ClaimStaleDeletionsParams{
+		LockedBy: leaseHolder(), LeaseSeconds: deletionLease.Seconds(),
+	}).Return([]database.Deletion{deletion}, nil).Once()
+	suite.mockRepo.EXPECT().FinishDeletion(mock.Anything, database.FinishDeletionParams{ID: deletion.ID, Status: deletionCompleted}).Return(nil).Once()
+
+	suite.NoError(newDeletionJob(suite.mockRepo, zap.NewNop(), nil, nil).resume(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestDeletionJobLosesLease() {
+	deletion := database.Deletion{ID: uuid.New(), TrackingID: uuid.New(), Status: "running"}
+	job := newDeletionJob(suite.mockRepo, zap.NewNop(), nil, nil)
+	job.heartbeat = time.Millisecond
+
+	// the lease is renewed while it holds, and the deletion stops once another
+	// process took it over
+	lease := database.HeartbeatDeletionParams{ID: deletion.ID, LockedBy: leaseHolder()}
+	suite.mockRepo.EXPECT().HeartbeatDeletion(mock.Anything, lease).Return(1, nil).Once()
+	suite.mockRepo.EXPECT().HeartbeatDeletion(mock.Anything, lease).Return(0, nil).Once()
+
+	ctx, release := job.hold(suite.ctx, deletion)
+	defer release()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		suite.Fail("lease not lost")
+	}
+	suite.ErrorIs(context.Cause(ctx), errDeletionLeaseLost)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestDeletionJobFails() {
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	start, end := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	deletion := database.Deletion{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Status:     "running",
+		StartDate:  at(start),
+		EndDate:    at(end),
+		FirstEvent: at(start.Add(time.Hour)),
+		LastEvent:  at(end.Add(-time.Minute)),
+	}
+
+	suite.mockRepo.EXPECT().DeleteMatchingEvents(mock.Anything, mock.MatchedBy(func(arg database.DeleteMatchingEventsParams) bool {
+		return arg.StartDate == deletion.StartDate && arg.EndDate.Time.Equal(end.Add(-time.Minute).Add(time.Microsecond))
+	})).Return(0, errors.New("connection reset")).Once()
+	suite.mockRepo.EXPECT().FinishDeletion(mock.Anything, mock.MatchedBy(func(arg database.FinishDeletionParams) bool {
+		return arg.ID == deletion.ID && arg.Status == deletionFailed && *arg.Error == "connection reset"
+	})).Return(nil).Once()
+
+	suite.NoError(newDeletionJob(suite.mockRepo, zap.NewNop(), nil, nil).run(suite.ctx, deletion))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/export"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// Export runs the export command, which writes the raw events of an app like
+// GET /apps/:trackingID/export does, to a file or stdout.
+func (s *Server) Export(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	app := flags.String("app", "", "tracking ID of the app to export")
+	format := flags.String("format", string(export.FormatCSV), "export format: csv, ndjson or parquet")
+	columns := flags.String("columns", "", "comma separated columns to export, all by default")
+	startDate := flags.String("start", "", "start date, YYYY-MM-DD or RFC3339")
+	endDate := flags.String("end", "", "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339")
+	filters := flags.String("filters", "", "JSON array of filters, as in the API")
+	timezone := flags.String("tz", "", "IANA timezone for dates, defaults to the app's timezone")
+	output := flags.String("o", "", "file to write to, stdout by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	trackingID, err := uuid.Parse(*app)
+	if err != nil {
+		return errors.New("-app must be the tracking ID of an app")
+	}
+
+	ctx := context.Background()
+	connPool, err := pgxpool.New(ctx, s.config.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	querier := database.New(connPool)
+	details, err := querier.GetAppByTrackingID(ctx, trackingID)
+	if err != nil {
+		return fmt.Errorf("failed to find app: %w", err)
+	}
+
+	if *timezone == "" {
+		*timezone = details.Timezone
+	}
+	location, err := loadTimezone(*timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q", *timezone)
+	}
+
+	payload, err := createRequestPayload(trackingID, *startDate, *endDate, location)
+	if err != nil {
+		return err
+	}
+	if payload.Filters, err = parseFilters(*filters); err != nil {
+		return err
+	}
+	if err := validateRegexFilters(ctx, querier, payload.Filters); err != nil {
+		return err
+	}
+	exportFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+	exportColumns, err := export.ParseColumns(*columns)
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := bufio.NewWriter(out)
+
+	service := NewAnalyticsService(querier, nil, nil, connPool)
+	written, err := service.ExportEvents(ctx, types.ExportPayload{
+		RequestPayload: payload,
+		Format:         string(exportFormat),
+		Columns:        exportColumns,
+	}, writer)
+	if err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	s.logger.Info("events exported", zap.String("trackingID", trackingID.String()), zap.Int64("events", written))
+	return nil
+}
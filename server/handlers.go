@@ -1,13 +1,21 @@
 package server
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ScMofeoluwa/minalytics/export"
+	"github.com/ScMofeoluwa/minalytics/importer"
 	types "github.com/ScMofeoluwa/minalytics/shared"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,6 +23,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// streamHeartbeat is how often an idle event stream is pinged to keep proxies from closing it.
+const streamHeartbeat = 15 * time.Second
+
 type AnalyticsHandler struct {
 	service types.AnalyticsService
 	logger  *zap.Logger
@@ -102,15 +113,18 @@ func (h *AnalyticsHandler) TrackEvent(ctx *gin.Context) types.APIResponse {
 }
 
 // @Summary Create App
-// @Description creates an app
+// @Description creates an app in an organization the user is an admin of, or in their personal organization
 // @Tags Apps
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
-// @Param request body types.CreateAppRequest true "app name"
+// @Param request body types.CreateAppRequest true "app name and organization"
 // @Success 200 {object} types.AppResponse "app created successfully"
 // @Failure 400 {object} types.APIStatus "invalid request body"
 // @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this organization does not allow this"
+// @Failure 404 {object} types.APIStatus "organization not found"
+// @Failure 409 {object} types.APIStatus "app already exists"
 // @Failure 500 {object} types.APIStatus "failed to create app"
 // @Router /apps [post]
 func (h *AnalyticsHandler) CreateApp(ctx *gin.Context) types.APIResponse {
@@ -127,8 +141,17 @@ func (h *AnalyticsHandler) CreateApp(ctx *gin.Context) types.APIResponse {
 		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
 	}
 
-	app, err := h.service.CreateApp(ctx, user, req.Name)
+	app, err := h.service.CreateApp(ctx, user, req.OrgID, req.Name)
 	if err != nil {
+		if errors.Is(err, ErrOrganizationForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrOrganizationNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, ErrAppExists) {
+			return types.NewErrorResponse(http.StatusConflict, err.Error())
+		}
 		h.logger.Error("failed to create app", zap.Error(err))
 		return types.NewErrorResponse(http.StatusInternalServerError, "failed to create app")
 	}
@@ -164,17 +187,20 @@ func (h *AnalyticsHandler) GetApps(ctx *gin.Context) types.APIResponse {
 }
 
 // @Summary Update App
-// @Description Updates app by tracking ID
+// @Description Updates app by tracking ID. data_retention, e.g. "30 days" or "forever", is how long raw events are kept, though never longer than the server's EVENTS_RETENTION
 // @Tags Apps
 // @Accept  json
 // @Produce  json
 // @Security BearerAuth
 // @Param trackingID path string true "Tracking ID of the app to delete"
-// @Param request body types.CreateAppRequest true "app name"
-// @Success 200 {object} types.AppResponse "app name successfully changed"
+// @Param request body types.UpdateAppRequest true "app settings"
+// @Success 200 {object} types.AppResponse "app successfully updated"
 // @Failure 400 {object} types.APIStatus "trackingID is required"
 // @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 400 {object} types.APIStatus "invalid data retention"
 // @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
 // @Failure 500 {object} types.APIStatus "failed to update app"
 // @Router /apps/{trackingID} [patch]
 func (h *AnalyticsHandler) UpdateApp(ctx *gin.Context) types.APIResponse {
@@ -192,15 +218,47 @@ func (h *AnalyticsHandler) UpdateApp(ctx *gin.Context) types.APIResponse {
 
 	trackingID := uuid.MustParse(trackingID_)
 
-	var req types.CreateAppRequest
+	var req types.UpdateAppRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("invalid request body", zap.Error(err))
 		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
 	}
 
-	payload := createAppPayload(req.Name, user, trackingID)
+	if (req.Name == nil && req.RetentionTracking == nil && req.Timezone == nil && req.DataRetention == nil) || (req.Name != nil && *req.Name == "") {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	if req.Timezone != nil {
+		if _, err := loadTimezone(*req.Timezone); err != nil {
+			return types.NewErrorResponse(http.StatusBadRequest, "invalid timezone")
+		}
+	}
+
+	if req.DataRetention != nil {
+		retention, err := parseDataRetention(*req.DataRetention)
+		if err != nil {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		req.DataRetention = &retention
+	}
+
+	var name string
+	if req.Name != nil {
+		name = *req.Name
+	}
+
+	payload := createAppPayload(name, user, trackingID)
+	payload.RetentionTracking = req.RetentionTracking
+	payload.Timezone = req.Timezone
+	payload.DataRetention = req.DataRetention
 	app, err := h.service.UpdateApp(ctx, payload)
 	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
 		h.logger.Error("failed to update app", zap.Error(err))
 		return types.NewErrorResponse(http.StatusInternalServerError, "failed to update app")
 	}
@@ -219,6 +277,8 @@ func (h *AnalyticsHandler) UpdateApp(ctx *gin.Context) types.APIResponse {
 // @Failure 400 {object} types.APIStatus "trackingID is required"
 // @Failure 400 {object} types.APIStatus "invalid request body"
 // @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
 // @Failure 500 {object} types.APIStatus "failed to delete app"
 // @Router /apps/{trackingID} [delete]
 func (h *AnalyticsHandler) DeleteApp(ctx *gin.Context) types.APIResponse {
@@ -238,6 +298,12 @@ func (h *AnalyticsHandler) DeleteApp(ctx *gin.Context) types.APIResponse {
 
 	payload := createAppPayload("", user, trackingID)
 	if err := h.service.DeleteApp(ctx, payload); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
 		h.logger.Error("failed to delete app", zap.Error(err))
 		return types.NewErrorResponse(http.StatusInternalServerError, "failed to delete app")
 	}
@@ -245,277 +311,1659 @@ func (h *AnalyticsHandler) DeleteApp(ctx *gin.Context) types.APIResponse {
 	return types.NewSuccessResponse(nil, http.StatusNoContent, "app successfully updated")
 }
 
-// @Summary Get Referrals
-// @Description Retrieves referral stats
-// @Tags Analytics
-// @Accept  json
-// @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
+// @Summary Export Events
+// @Description Streams the raw events of an app as CSV, NDJSON or Parquet, oldest first. Errors after the export has started end the response early
+// @Tags Apps
+// @Produce  text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param trackingID path string true "app tracking ID"
+// @Param format query string false "export format (default csv)" Enums(csv, ndjson, parquet)
+// @Param columns query string false "comma separated columns to export, defaults to all of id, timestamp, visitor_id, persistent_id, event_type, url, referrer, country, browser, device, operating_system, details"
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates, defaults to the app's timezone"
 // @Security BearerAuth
-// @Success 200 {object} types.ReferralResponse "stats fetched successfully"
+// @Success 200 {file} file "exported events"
 // @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch referrals"
-// @Router /analytics/referrals [get]
-func (h *AnalyticsHandler) GetReferrals(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to export events"
+// @Router /apps/{trackingID}/export [get]
+func (h *AnalyticsHandler) ExportEvents(ctx *gin.Context) {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		ctx.JSON(http.StatusUnauthorized, types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context"))
+		return
 	}
-	trackingID := trackingID_.(uuid.UUID)
+	user := userID.(uuid.UUID)
 
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID"))
+		return
+	}
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	app, err := h.service.ValidateAppAccess(ctx, user, trackingID)
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
-		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		ctx.JSON(http.StatusNotFound, types.NewErrorResponse(http.StatusNotFound, err.Error()))
+		return
 	}
+	ctx.Set("timezone", app.Timezone)
 
-	stats, err := h.service.GetReferrals(ctx, payload)
+	payload, err := h.parseExportPayload(ctx, trackingID)
 	if err != nil {
-		h.logger.Error("failed to fetch referrals", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch referrals")
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		return
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	format := export.Format(payload.Format)
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="events-%s.%s"`, trackingID, format))
+	ctx.Header("X-Accel-Buffering", "no")
+
+	written, err := h.service.ExportEvents(ctx, payload, ctx.Writer)
+	if err != nil {
+		h.logger.Error("failed to export events", zap.Int64("written", written), zap.Error(err))
+		// once the export has started its status is sent, the response just ends early
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Type")
+			ctx.Writer.Header().Del("Content-Disposition")
+			ctx.JSON(http.StatusInternalServerError, types.NewErrorResponse(http.StatusInternalServerError, "failed to export events"))
+		}
+		return
+	}
+	ctx.Status(http.StatusOK)
 }
 
-// @Summary Get Pages
-// @Description Retrieves page stats
-// @Tags Analytics
-// @Accept  json
+// @Summary Create Import
+// @Description Uploads the export of another analytics tool and imports it into the app in the background. GA4, Universal Analytics and Plausible exports are CSV files or zips of them, holding daily aggregates that are stored as imported aggregates and added to the tracked events in reports. Umami exports are plain PostgreSQL dumps of its database. Access logs of nginx or Apache, plain or gzipped, are read into pageviews, skipping static assets and bots. Poll the import for its progress
+// @Tags Apps
+// @Accept  multipart/form-data
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.PageResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch pages"
-// @Router /analytics/pages [get]
-func (h *AnalyticsHandler) GetPages(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Param file formData file true "export to import"
+// @Param source formData string true "tool the export comes from" Enums(ga4, universal_analytics, plausible, umami, access_log)
+// @Param hostname formData string false "hostname of the pages, for exports that only have their paths"
+// @Param website_id formData string false "website to import from an Umami dump that holds several"
+// @Param log_format formData string false "format of access log lines: combined (default), common, or an nginx log_format or Apache LogFormat"
+// @Success 202 {object} types.ImportResponse "import queued"
+// @Failure 400 {object} types.APIStatus "invalid request parameters"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to queue import"
+// @Router /apps/{trackingID}/imports [post]
+func (h *AnalyticsHandler) CreateImport(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
 	}
-	trackingID := trackingID_.(uuid.UUID)
+	user := userID.(uuid.UUID)
 
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	source := ctx.PostForm("source")
+	parsed, err := importer.ParseSource(source)
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
 		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
 	}
+	logFormat := ctx.PostForm("log_format")
+	if parsed == importer.SourceAccessLog {
+		if _, err := importer.ParseLogFormat(logFormat); err != nil {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+	}
 
-	stats, err := h.service.GetPages(ctx, payload)
+	header, err := ctx.FormFile("file")
 	if err != nil {
-		h.logger.Error("failed to fetch pages", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch pages")
+		return types.NewErrorResponse(http.StatusBadRequest, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.logger.Error("failed to open uploaded file", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid file")
 	}
+	defer file.Close()
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	payload := types.ImportPayload{
+		UserID:     user,
+		TrackingID: trackingID,
+		Source:     source,
+		Hostname:   ctx.PostForm("hostname"),
+		WebsiteID:  ctx.PostForm("website_id"),
+		LogFormat:  logFormat,
+	}
+	imp, err := h.service.CreateImport(ctx, payload, file)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to queue import", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to queue import")
+	}
+
+	return types.NewSuccessResponse(imp, http.StatusAccepted, "import queued")
 }
 
-// @Summary Get Browsers
-// @Description Retrieves browser stats
-// @Tags Analytics
-// @Accept  json
+// @Summary Get Imports
+// @Description Lists the imports of an app, newest first
+// @Tags Apps
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.BrowserResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch browsers"
-// @Router /analytics/browsers [get]
-func (h *AnalyticsHandler) GetBrowsers(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Success 200 {object} types.ImportsResponse "imports fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid trackingID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch imports"
+// @Router /apps/{trackingID}/imports [get]
+func (h *AnalyticsHandler) GetImports(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
 	}
-	trackingID := trackingID_.(uuid.UUID)
-
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	user := userID.(uuid.UUID)
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
-		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
 	}
 
-	stats, err := h.service.GetBrowsers(ctx, payload)
+	imports, err := h.service.GetImports(ctx, user, trackingID)
 	if err != nil {
-		h.logger.Error("failed to fetch browsers", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch browsers")
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch imports", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch imports")
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	return types.NewSuccessResponse(imports, http.StatusOK, "imports fetched successfully")
 }
 
-// @Summary Get Countries
-// @Description Retrieves country stats
-// @Tags Analytics
-// @Accept  json
+// @Summary Get Import
+// @Description Retrieves an import with its status and progress
+// @Tags Apps
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.CountryResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch countries"
-// @Router /analytics/countries [get]
-func (h *AnalyticsHandler) GetCountries(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Param importID path string true "import ID"
+// @Success 200 {object} types.ImportResponse "import fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid importID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 404 {object} types.APIStatus "import not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch import"
+// @Router /apps/{trackingID}/imports/{importID} [get]
+func (h *AnalyticsHandler) GetImport(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
 	}
-	trackingID := trackingID_.(uuid.UUID)
-
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	user := userID.(uuid.UUID)
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
-		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	importID, err := uuid.Parse(ctx.Param("importID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid importID")
 	}
 
-	stats, err := h.service.GetCountries(ctx, payload)
+	imp, err := h.service.GetImport(ctx, user, trackingID, importID)
 	if err != nil {
-		h.logger.Error("failed to fetch countries", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch countries")
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrImportNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch import", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch import")
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	return types.NewSuccessResponse(imp, http.StatusOK, "import fetched successfully")
 }
 
-// @Summary Get Devices
-// @Description Retrieves device stats
-// @Tags Analytics
+// @Summary Delete Events
+// @Description Deletes the events of an app in a date range, matching filters, of a visitor, or any combination of them, to honour erasure requests or clean up bad data. Deletions run in the background and are recorded for auditing with who asked for them and what they matched. A dry run only counts the events and visitors that would be deleted
+// @Tags Apps
 // @Accept  json
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.DeviceResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch devices"
-// @Router /analytics/devices [get]
-func (h *AnalyticsHandler) GetDevices(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Param request body types.DeletionRequest true "events to delete"
+// @Param tz query string false "IANA timezone for dates, defaults to the app's timezone"
+// @Success 200 {object} types.DeletionResponse "dry run completed"
+// @Success 202 {object} types.DeletionResponse "deletion queued"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to queue deletion"
+// @Router /apps/{trackingID}/deletions [post]
+func (h *AnalyticsHandler) CreateDeletion(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
 	}
-	trackingID := trackingID_.(uuid.UUID)
 
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	var req types.DeletionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	app, err := h.service.ValidateAppAccess(ctx, user, trackingID)
+	if err != nil {
+		return types.NewErrorResponse(http.StatusNotFound, err.Error())
+	}
+	ctx.Set("timezone", app.Timezone)
+	location, err := parseTimezone(ctx)
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
 		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
 	}
 
-	stats, err := h.service.GetDevices(ctx, payload)
+	dates, err := createRequestPayload(trackingID, req.StartDate, req.EndDate, location)
 	if err != nil {
-		h.logger.Error("failed to fetch devices", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch devices")
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+	if err := validateFilters(req.Filters); err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+	if hasRegexFilter(req.Filters) {
+		if err := h.service.ValidateRegexFilters(ctx, req.Filters); err != nil {
+			if errors.Is(err, ErrInvalidRegex) {
+				return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+			}
+			h.logger.Error("failed to validate filters", zap.Error(err))
+			return types.NewErrorResponse(http.StatusInternalServerError, "failed to validate filters")
+		}
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	deletion, err := h.service.CreateDeletion(ctx, types.DeletionPayload{
+		UserID:     user,
+		TrackingID: trackingID,
+		StartDate:  dates.StartDate,
+		EndDate:    dates.EndDate,
+		Filters:    req.Filters,
+		VisitorID:  req.VisitorID,
+		DryRun:     req.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, ErrNoDeletionCriteria) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to queue deletion", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to queue deletion")
+	}
+
+	if req.DryRun {
+		return types.NewSuccessResponse(deletion, http.StatusOK, "dry run completed")
+	}
+	return types.NewSuccessResponse(deletion, http.StatusAccepted, "deletion queued")
 }
 
-// @Summary Get OS
-// @Description Retrieves operating system stats
-// @Tags Analytics
-// @Accept  json
+// @Summary Get Deletions
+// @Description Lists the deletions of an app's events, dry runs included, newest first
+// @Tags Apps
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.OSResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch operating systems"
-// @Router /analytics/os [get]
-func (h *AnalyticsHandler) GetOS(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Success 200 {object} types.DeletionsResponse "deletions fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid trackingID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch deletions"
+// @Router /apps/{trackingID}/deletions [get]
+func (h *AnalyticsHandler) GetDeletions(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
 	}
-	trackingID := trackingID_.(uuid.UUID)
-
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	user := userID.(uuid.UUID)
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
-		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
 	}
 
-	stats, err := h.service.GetOS(ctx, payload)
+	deletions, err := h.service.GetDeletions(ctx, user, trackingID)
 	if err != nil {
-		h.logger.Error("failed to fetch operating systems", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch operating systems")
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch deletions", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch deletions")
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	return types.NewSuccessResponse(deletions, http.StatusOK, "deletions fetched successfully")
 }
 
-// @Summary Get Visitors
-// @Description Retrieves visitors
-// @Tags Analytics
-// @Accept  json
+// @Summary Get Deletion
+// @Description Retrieves a deletion with its status and the number of events it deleted
+// @Tags Apps
 // @Produce  json
-// @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
 // @Security BearerAuth
-// @Success 200 {object} types.VisitorResponse "stats fetched successfully"
-// @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch visitors"
-// @Router /analytics/visitors [get]
-func (h *AnalyticsHandler) GetVisitors(ctx *gin.Context) types.APIResponse {
-	trackingID_, exists := ctx.Get("trackingID")
+// @Param trackingID path string true "app tracking ID"
+// @Param deletionID path string true "deletion ID"
+// @Success 200 {object} types.DeletionResponse "deletion fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid deletionID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "deletion not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch deletion"
+// @Router /apps/{trackingID}/deletions/{deletionID} [get]
+func (h *AnalyticsHandler) GetDeletion(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
 	if !exists {
-		h.logger.Warn("trackingID not found in context")
-		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
 	}
-	trackingID := trackingID_.(uuid.UUID)
-
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	user := userID.(uuid.UUID)
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
 	if err != nil {
-		h.logger.Error("invalid request parameters", zap.Error(err))
-		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	deletionID, err := uuid.Parse(ctx.Param("deletionID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid deletionID")
 	}
 
-	stats, err := h.service.GetVisitors(ctx, payload)
+	deletion, err := h.service.GetDeletion(ctx, user, trackingID, deletionID)
 	if err != nil {
-		h.logger.Error("failed to fetch visitors", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch visitors")
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrDeletionNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch deletion", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch deletion")
 	}
 
-	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+	return types.NewSuccessResponse(deletion, http.StatusOK, "deletion fetched successfully")
+}
+
+// @Summary Create Share Link
+// @Description Creates a secret link granting read-only access to the stats of an app without an account, for making them public or sharing them with a client. The link opens up the selected analytics endpoints, or all of them, under /shared/{token}, optionally behind a password that starts a session at /shared/{token}/session and until it expires. The token is only returned here
+// @Tags Apps
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param request body types.ShareRequest true "share link to create"
+// @Success 201 {object} types.ShareResponse "share link created"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to create share link"
+// @Router /apps/{trackingID}/shares [post]
+func (h *AnalyticsHandler) CreateShare(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	var req types.ShareRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	share, err := h.service.CreateShare(ctx, types.SharePayload{
+		UserID:     user,
+		TrackingID: trackingID,
+		Name:       req.Name,
+		Endpoints:  req.Endpoints,
+		Password:   req.Password,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedEndpoint) || errors.Is(err, ErrInvalidShareExpiry) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to create share link", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to create share link")
+	}
+
+	return types.NewSuccessResponse(share, http.StatusCreated, "share link created")
+}
+
+// @Summary Start Share Session
+// @Description Checks the password of a share link and starts a session of it, which opens up the link for an hour, or until it expires, without sending the password again. The session is set as a cookie scoped to the link, and returned to be sent in the X-Share-Session header. Links without a password need no session. Too many wrong passwords lock the link for 15 minutes
+// @Tags Apps
+// @Accept  json
+// @Produce  json
+// @Param token path string true "share link token"
+// @Param request body types.ShareSessionRequest true "password of the share link"
+// @Success 201 {object} types.ShareSessionResponse "share session started"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "invalid share link password"
+// @Failure 404 {object} types.APIStatus "share link not found"
+// @Failure 410 {object} types.APIStatus "share link has expired"
+// @Failure 429 {object} types.APIStatus "too many wrong passwords for this share link, try again later"
+// @Failure 500 {object} types.APIStatus "failed to start share session"
+// @Router /shared/{token}/session [post]
+func (h *AnalyticsHandler) CreateShareSession(ctx *gin.Context) types.APIResponse {
+	var req types.ShareSessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	token := ctx.Param("token")
+	session, err := h.service.CreateShareSession(ctx, token, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrShareNotFound):
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		case errors.Is(err, ErrShareExpired):
+			return types.NewErrorResponse(http.StatusGone, err.Error())
+		case errors.Is(err, ErrInvalidSharePassword):
+			return types.NewErrorResponse(http.StatusUnauthorized, err.Error())
+		case errors.Is(err, ErrTooManyShareAttempts):
+			return types.NewErrorResponse(http.StatusTooManyRequests, err.Error())
+		}
+		h.logger.Error("failed to start share session", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to start share session")
+	}
+
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	maxAge := int(time.Until(session.ExpiresAt).Seconds())
+	ctx.SetCookie(shareSessionCookie, session.Token, maxAge, "/shared/"+token, "", ctx.Request.TLS != nil, true)
+	return types.NewSuccessResponse(session, http.StatusCreated, "share session started")
+}
+
+// @Summary Get Share Links
+// @Description Lists the share links of an app, revoked ones included, newest first
+// @Tags Apps
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Success 200 {object} types.SharesResponse "share links fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid trackingID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch share links"
+// @Router /apps/{trackingID}/shares [get]
+func (h *AnalyticsHandler) GetShares(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	shares, err := h.service.GetShares(ctx, user, trackingID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch share links", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch share links")
+	}
+
+	return types.NewSuccessResponse(shares, http.StatusOK, "share links fetched successfully")
+}
+
+// @Summary Revoke Share Link
+// @Description Revokes a share link, which stops granting access right away
+// @Tags Apps
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param shareID path string true "share link ID"
+// @Success 200 {object} types.ShareResponse "share link revoked"
+// @Failure 400 {object} types.APIStatus "invalid shareID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "share link not found"
+// @Failure 500 {object} types.APIStatus "failed to revoke share link"
+// @Router /apps/{trackingID}/shares/{shareID} [delete]
+func (h *AnalyticsHandler) RevokeShare(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	shareID, err := uuid.Parse(ctx.Param("shareID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid shareID")
+	}
+
+	share, err := h.service.RevokeShare(ctx, user, trackingID, shareID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrShareNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to revoke share link", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to revoke share link")
+	}
+
+	return types.NewSuccessResponse(share, http.StatusOK, "share link revoked")
+}
+
+// @Summary Get Members
+// @Description Lists the members of an app with their roles: owner, admin, editor or viewer
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Success 200 {object} types.MembersResponse "members fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid trackingID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch members"
+// @Router /apps/{trackingID}/members [get]
+func (h *AnalyticsHandler) GetMembers(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	members, err := h.service.GetMembers(ctx, user, trackingID)
+	if err != nil {
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch members", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch members")
+	}
+
+	return types.NewSuccessResponse(members, http.StatusOK, "members fetched successfully")
+}
+
+// @Summary Update Member
+// @Description Changes the role of a member of an app to admin, editor or viewer. The owner's role only changes by transferring the app. Admins and owners only
+// @Tags Members
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param userID path string true "user ID of the member"
+// @Param request body types.UpdateMemberRequest true "new role"
+// @Success 200 {object} types.APIStatus "member updated"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "member not found"
+// @Failure 500 {object} types.APIStatus "failed to update member"
+// @Router /apps/{trackingID}/members/{userID} [patch]
+func (h *AnalyticsHandler) UpdateMember(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	memberID, err := uuid.Parse(ctx.Param("userID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid userID")
+	}
+
+	var req types.UpdateMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.service.UpdateMember(ctx, user, trackingID, memberID, req.Role); err != nil {
+		if errors.Is(err, ErrInvalidRole) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrMemberNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to update member", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to update member")
+	}
+
+	return types.NewSuccessResponse(nil, http.StatusOK, "member updated")
+}
+
+// @Summary Remove Member
+// @Description Removes a member from an app. Admins and owners remove anyone but the owner, and other members only themselves
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param userID path string true "user ID of the member"
+// @Success 200 {object} types.APIStatus "member removed"
+// @Failure 400 {object} types.APIStatus "invalid userID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "member not found"
+// @Failure 500 {object} types.APIStatus "failed to remove member"
+// @Router /apps/{trackingID}/members/{userID} [delete]
+func (h *AnalyticsHandler) RemoveMember(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	memberID, err := uuid.Parse(ctx.Param("userID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid userID")
+	}
+
+	if err := h.service.RemoveMember(ctx, user, trackingID, memberID); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrMemberNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to remove member", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to remove member")
+	}
+
+	return types.NewSuccessResponse(nil, http.StatusOK, "member removed")
+}
+
+// @Summary Transfer App
+// @Description Makes a member the owner of an app and moves it into an organization they own, their personal one unless org_id is given. The previous owner stays on as an admin. Owners only
+// @Tags Members
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param request body types.TransferAppRequest true "member to transfer the app to"
+// @Success 200 {object} types.AppResponse "app transferred"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "member not found"
+// @Failure 409 {object} types.APIStatus "app already exists"
+// @Failure 500 {object} types.APIStatus "failed to transfer app"
+// @Router /apps/{trackingID}/transfer [post]
+func (h *AnalyticsHandler) TransferApp(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	var req types.TransferAppRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	app, err := h.service.TransferApp(ctx, user, trackingID, req.UserID, req.OrgID)
+	if err != nil {
+		if errors.Is(err, ErrTransferOrganization) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrAppExists) {
+			return types.NewErrorResponse(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrMemberNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to transfer app", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to transfer app")
+	}
+
+	return types.NewSuccessResponse(app, http.StatusOK, "app transferred")
+}
+
+// @Summary Invite Member
+// @Description Invites someone to an app by email as an admin, editor or viewer. They become a member once they sign in with that email and accept. Inviting the same email again replaces the invitation. Admins and owners only
+// @Tags Members
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param request body types.InvitationRequest true "person to invite"
+// @Success 201 {object} types.InvitationResponse "invitation created"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to create invitation"
+// @Router /apps/{trackingID}/invitations [post]
+func (h *AnalyticsHandler) CreateInvitation(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	var req types.InvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	invitation, err := h.service.CreateInvitation(ctx, types.InvitationPayload{
+		UserID:     user,
+		TrackingID: trackingID,
+		Email:      req.Email,
+		Role:       req.Role,
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidRole) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to create invitation", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to create invitation")
+	}
+
+	return types.NewSuccessResponse(invitation, http.StatusCreated, "invitation created")
+}
+
+// @Summary Get Invitations
+// @Description Lists the pending invitations to an app. Admins and owners only
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Success 200 {object} types.InvitationsResponse "invitations fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid trackingID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch invitations"
+// @Router /apps/{trackingID}/invitations [get]
+func (h *AnalyticsHandler) GetInvitations(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+
+	invitations, err := h.service.GetInvitations(ctx, user, trackingID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch invitations", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch invitations")
+	}
+
+	return types.NewSuccessResponse(invitations, http.StatusOK, "invitations fetched successfully")
+}
+
+// @Summary Revoke Invitation
+// @Description Revokes a pending invitation to an app. Admins and owners only
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Param trackingID path string true "app tracking ID"
+// @Param invitationID path string true "invitation ID"
+// @Success 200 {object} types.APIStatus "invitation revoked"
+// @Failure 400 {object} types.APIStatus "invalid invitationID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this app does not allow this"
+// @Failure 404 {object} types.APIStatus "invitation not found"
+// @Failure 500 {object} types.APIStatus "failed to revoke invitation"
+// @Router /apps/{trackingID}/invitations/{invitationID} [delete]
+func (h *AnalyticsHandler) RevokeInvitation(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	trackingID, err := uuid.Parse(ctx.Param("trackingID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid trackingID")
+	}
+	invitationID, err := uuid.Parse(ctx.Param("invitationID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid invitationID")
+	}
+
+	if err := h.service.RevokeInvitation(ctx, user, trackingID, invitationID); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) || errors.Is(err, ErrInvitationNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to revoke invitation", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to revoke invitation")
+	}
+
+	return types.NewSuccessResponse(nil, http.StatusOK, "invitation revoked")
+}
+
+// @Summary Get My Invitations
+// @Description Lists the pending invitations to apps for the email the user signed in with
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} types.InvitationsResponse "invitations fetched successfully"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 500 {object} types.APIStatus "failed to fetch invitations"
+// @Router /invitations [get]
+func (h *AnalyticsHandler) GetUserInvitations(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	invitations, err := h.service.GetUserInvitations(ctx, user)
+	if err != nil {
+		h.logger.Error("failed to fetch invitations", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch invitations")
+	}
+
+	return types.NewSuccessResponse(invitations, http.StatusOK, "invitations fetched successfully")
+}
+
+// @Summary Accept Invitation
+// @Description Accepts an invitation to an app, making the user a member of it with the role they were invited with
+// @Tags Members
+// @Produce  json
+// @Security BearerAuth
+// @Param invitationID path string true "invitation ID"
+// @Success 200 {object} types.AppResponse "invitation accepted"
+// @Failure 400 {object} types.APIStatus "invalid invitationID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 404 {object} types.APIStatus "invitation not found"
+// @Failure 500 {object} types.APIStatus "failed to accept invitation"
+// @Router /invitations/{invitationID}/accept [post]
+func (h *AnalyticsHandler) AcceptInvitation(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	invitationID, err := uuid.Parse(ctx.Param("invitationID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid invitationID")
+	}
+
+	app, err := h.service.AcceptInvitation(ctx, user, invitationID)
+	if err != nil {
+		if errors.Is(err, ErrInvitationNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to accept invitation", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to accept invitation")
+	}
+
+	return types.NewSuccessResponse(app, http.StatusOK, "invitation accepted")
+}
+
+// @Summary Create Organization
+// @Description Creates an organization owned by the user. Organizations own apps, and their members have their role in every app of the organization
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param request body types.CreateOrganizationRequest true "organization name"
+// @Success 200 {object} types.OrganizationResponse "organization created"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 500 {object} types.APIStatus "failed to create organization"
+// @Router /organizations [post]
+func (h *AnalyticsHandler) CreateOrganization(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	var req types.CreateOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	org, err := h.service.CreateOrganization(ctx, user, req.Name)
+	if err != nil {
+		h.logger.Error("failed to create organization", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to create organization")
+	}
+
+	return types.NewSuccessResponse(org, http.StatusOK, "organization created")
+}
+
+// @Summary Get Organizations
+// @Description Lists the organizations the user is a member of, their personal organization first
+// @Tags Organizations
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} types.OrganizationsResponse "organizations fetched"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 500 {object} types.APIStatus "failed to fetch organizations"
+// @Router /organizations [get]
+func (h *AnalyticsHandler) GetOrganizations(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	orgs, err := h.service.GetOrganizations(ctx, user)
+	if err != nil {
+		h.logger.Error("failed to fetch organizations", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch organizations")
+	}
+
+	return types.NewSuccessResponse(orgs, http.StatusOK, "organizations fetched")
+}
+
+// @Summary Get Organization Members
+// @Description Lists the members of an organization and their roles
+// @Tags Organizations
+// @Produce  json
+// @Security BearerAuth
+// @Param orgID path string true "organization ID"
+// @Success 200 {object} types.MembersResponse "members fetched"
+// @Failure 400 {object} types.APIStatus "invalid orgID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 404 {object} types.APIStatus "organization not found"
+// @Failure 500 {object} types.APIStatus "failed to fetch members"
+// @Router /organizations/{orgID}/members [get]
+func (h *AnalyticsHandler) GetOrganizationMembers(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	orgID, err := uuid.Parse(ctx.Param("orgID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid orgID")
+	}
+
+	members, err := h.service.GetOrganizationMembers(ctx, user, orgID)
+	if err != nil {
+		if errors.Is(err, ErrOrganizationNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to fetch members", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch members")
+	}
+
+	return types.NewSuccessResponse(members, http.StatusOK, "members fetched")
+}
+
+// @Summary Set Organization Member
+// @Description Adds a user who has signed in before to an organization, or changes their role in it. Admins and owners manage the members below owner, and only owners make or unmake owners
+// @Tags Organizations
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param orgID path string true "organization ID"
+// @Param request body types.OrganizationMemberRequest true "member and role"
+// @Success 200 {object} types.MemberResponse "member saved"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this organization does not allow this"
+// @Failure 404 {object} types.APIStatus "no user has signed in with this email"
+// @Failure 409 {object} types.APIStatus "an organization needs at least one owner"
+// @Failure 500 {object} types.APIStatus "failed to save member"
+// @Router /organizations/{orgID}/members [put]
+func (h *AnalyticsHandler) SetOrganizationMember(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	orgID, err := uuid.Parse(ctx.Param("orgID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid orgID")
+	}
+
+	var req types.OrganizationMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	member, err := h.service.SetOrganizationMember(ctx, types.OrganizationMemberPayload{
+		UserID: user,
+		OrgID:  orgID,
+		Email:  req.Email,
+		Role:   req.Role,
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidOrganizationRole) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrOrganizationForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrOrganizationNotFound) || errors.Is(err, ErrUserNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, ErrLastOwner) {
+			return types.NewErrorResponse(http.StatusConflict, err.Error())
+		}
+		h.logger.Error("failed to save member", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to save member")
+	}
+
+	return types.NewSuccessResponse(member, http.StatusOK, "member saved")
+}
+
+// @Summary Remove Organization Member
+// @Description Removes a member from an organization along with their access to its apps. The apps they owned go to another owner of the organization. Admins and owners remove anyone below owner, owners also other owners, and other members only themselves
+// @Tags Organizations
+// @Produce  json
+// @Security BearerAuth
+// @Param orgID path string true "organization ID"
+// @Param userID path string true "user ID of the member"
+// @Success 200 {object} types.APIStatus "member removed"
+// @Failure 400 {object} types.APIStatus "invalid userID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "your role in this organization does not allow this"
+// @Failure 404 {object} types.APIStatus "member not found"
+// @Failure 409 {object} types.APIStatus "an organization needs at least one owner"
+// @Failure 500 {object} types.APIStatus "failed to remove member"
+// @Router /organizations/{orgID}/members/{userID} [delete]
+func (h *AnalyticsHandler) RemoveOrganizationMember(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	orgID, err := uuid.Parse(ctx.Param("orgID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid orgID")
+	}
+	memberID, err := uuid.Parse(ctx.Param("userID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid userID")
+	}
+
+	if err := h.service.RemoveOrganizationMember(ctx, user, orgID, memberID); err != nil {
+		if errors.Is(err, ErrOrganizationForbidden) {
+			return types.NewErrorResponse(http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, ErrOrganizationNotFound) || errors.Is(err, ErrMemberNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, ErrLastOwner) {
+			return types.NewErrorResponse(http.StatusConflict, err.Error())
+		}
+		h.logger.Error("failed to remove member", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to remove member")
+	}
+
+	return types.NewSuccessResponse(nil, http.StatusOK, "member removed")
+}
+
+// @Summary Create API Token
+// @Description Creates an API token for scripts and BI tools, sent as "Authorization: Bearer mk_..." in place of the JWT. Its scopes are read-stats, manage-apps and ingest, and it can be limited to some of your apps and set to expire. The token is only returned here
+// @Tags API Tokens
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param request body types.APITokenRequest true "API token to create"
+// @Success 201 {object} types.APITokenResponse "API token created"
+// @Failure 400 {object} types.APIStatus "invalid request body"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "the API token does not allow this"
+// @Failure 404 {object} types.APIStatus "app not found"
+// @Failure 500 {object} types.APIStatus "failed to create API token"
+// @Router /tokens [post]
+func (h *AnalyticsHandler) CreateAPIToken(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	var req types.APITokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("invalid request body", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid request body")
+	}
+
+	token, err := h.service.CreateAPIToken(ctx, types.APITokenPayload{
+		UserID:      user,
+		Name:        req.Name,
+		Scopes:      req.Scopes,
+		TrackingIDs: req.TrackingIDs,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedScope) || errors.Is(err, ErrInvalidTokenExpiry) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, ErrAppNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to create API token", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to create API token")
+	}
+
+	return types.NewSuccessResponse(token, http.StatusCreated, "API token created")
+}
+
+// @Summary Get API Tokens
+// @Description Lists your API tokens, newest first, with when and from which IP they were last used
+// @Tags API Tokens
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} types.APITokensResponse "API tokens fetched"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "the API token does not allow this"
+// @Failure 500 {object} types.APIStatus "failed to fetch API tokens"
+// @Router /tokens [get]
+func (h *AnalyticsHandler) GetAPITokens(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	tokens, err := h.service.GetAPITokens(ctx, user)
+	if err != nil {
+		h.logger.Error("failed to fetch API tokens", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch API tokens")
+	}
+
+	return types.NewSuccessResponse(tokens, http.StatusOK, "API tokens fetched")
+}
+
+// @Summary Revoke API Token
+// @Description Revokes an API token, which stops working right away
+// @Tags API Tokens
+// @Produce  json
+// @Security BearerAuth
+// @Param tokenID path string true "API token ID"
+// @Success 200 {object} types.APITokenResponse "API token revoked"
+// @Failure 400 {object} types.APIStatus "invalid tokenID"
+// @Failure 401 {object} types.APIStatus "userID not found in context"
+// @Failure 403 {object} types.APIStatus "the API token does not allow this"
+// @Failure 404 {object} types.APIStatus "API token not found"
+// @Failure 500 {object} types.APIStatus "failed to revoke API token"
+// @Router /tokens/{tokenID} [delete]
+func (h *AnalyticsHandler) RevokeAPIToken(ctx *gin.Context) types.APIResponse {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		h.logger.Warn("userID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "userID not found in context")
+	}
+	user := userID.(uuid.UUID)
+
+	tokenID, err := uuid.Parse(ctx.Param("tokenID"))
+	if err != nil {
+		return types.NewErrorResponse(http.StatusBadRequest, "invalid tokenID")
+	}
+
+	token, err := h.service.RevokeAPIToken(ctx, user, tokenID)
+	if err != nil {
+		if errors.Is(err, ErrAPITokenNotFound) {
+			return types.NewErrorResponse(http.StatusNotFound, err.Error())
+		}
+		h.logger.Error("failed to revoke API token", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to revoke API token")
+	}
+
+	return types.NewSuccessResponse(token, http.StatusOK, "API token revoked")
+}
+
+// @Summary Get Referrals
+// @Description Retrieves referral stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.ReferralResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch referrals"
+// @Router /analytics/referrals [get]
+func (h *AnalyticsHandler) GetReferrals(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetReferrals(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch referrals", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch referrals")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Pages
+// @Description Retrieves page stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.PageResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch pages"
+// @Router /analytics/pages [get]
+func (h *AnalyticsHandler) GetPages(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetPages(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch pages", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch pages")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Browsers
+// @Description Retrieves browser stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.BrowserResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch browsers"
+// @Router /analytics/browsers [get]
+func (h *AnalyticsHandler) GetBrowsers(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetBrowsers(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch browsers", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch browsers")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Countries
+// @Description Retrieves country stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.CountryResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch countries"
+// @Router /analytics/countries [get]
+func (h *AnalyticsHandler) GetCountries(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetCountries(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch countries", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch countries")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Devices
+// @Description Retrieves device stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.DeviceResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch devices"
+// @Router /analytics/devices [get]
+func (h *AnalyticsHandler) GetDevices(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetDevices(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch devices", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch devices")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get OS
+// @Description Retrieves operating system stats
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param limit query int false "rows per page, 1 to 1000 (default 100)"
+// @Param page query int false "page number (default 1)"
+// @Param sort query string false "row order (default visitors:desc)" Enums(visitors:desc, visitors:asc, name:asc, name:desc)
+// @Param other query bool false "add a row aggregating every row after the page"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.OSResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch operating systems"
+// @Router /analytics/os [get]
+func (h *AnalyticsHandler) GetOS(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseBreakdownPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetOS(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch operating systems", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch operating systems")
+	}
+
+	setCacheControl(ctx, payload.RequestPayload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Visitors
+// @Description Retrieves visitors
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param interval query string false "time bucket size, defaults to one suited to the range" Enums(minute, hour, day, week, month)
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Security BearerAuth
+// @Success 200 {object} types.VisitorResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch visitors"
+// @Router /analytics/visitors [get]
+func (h *AnalyticsHandler) GetVisitors(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetVisitors(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch visitors", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch visitors")
+	}
+
+	setCacheControl(ctx, payload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
 }
 
 // @Summary Get PageViews
@@ -524,14 +1972,288 @@ func (h *AnalyticsHandler) GetVisitors(ctx *gin.Context) types.APIResponse {
 // @Accept  json
 // @Produce  json
 // @Param trackingID query string true "app tracking ID"
-// @Param startDate query string false "start date"
-// @Param endDate query string false "end date"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Param interval query string false "time bucket size, defaults to one suited to the range" Enums(minute, hour, day, week, month)
+// @Security BearerAuth
+// @Success 200 {object} types.PageViewResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch visitors"
+// @Router /analytics/pageviews [get]
+func (h *AnalyticsHandler) GetPageViews(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetPageViews(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch page views", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch page views")
+	}
+
+	setCacheControl(ctx, payload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Overview
+// @Description Retrieves the headline numbers for a range: visitors, pageviews, visits, views per visit, bounce rate, visit duration and events
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Param precision query string false "count unique visitors exactly or estimate them from sketches" Enums(exact, approx)
+// @Param compare query string false "comparison range" Enums(previous_period, year_over_year, custom)
+// @Param compareStartDate query string false "comparison start date, required when compare is custom"
+// @Param compareEndDate query string false "comparison end date, required when compare is custom"
+// @Security BearerAuth
+// @Success 200 {object} types.OverviewResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch overview"
+// @Router /analytics/overview [get]
+func (h *AnalyticsHandler) GetOverview(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetOverview(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch overview", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch overview")
+	}
+
+	setCacheControl(ctx, payload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Event Types
+// @Description Retrieves the number of events of each type in a range, pageviews included, and the visitors who sent them, most frequent first
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Security BearerAuth
+// @Success 200 {object} types.EventTypesResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch event types"
+// @Router /analytics/events [get]
+func (h *AnalyticsHandler) GetEventTypes(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetEventTypes(ctx, payload)
+	if err != nil {
+		h.logger.Error("failed to fetch event types", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch event types")
+	}
+
+	setCacheControl(ctx, payload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get Realtime
+// @Description Retrieves visitors active in the last five minutes with their top pages and sources
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
 // @Security BearerAuth
-// @Success 200 {object} types.PageViewResponse "stats fetched successfully"
+// @Success 200 {object} types.RealtimeResponse "stats fetched successfully"
+// @Failure 500 {object} types.APIStatus "failed to fetch realtime stats"
+// @Router /analytics/realtime [get]
+func (h *AnalyticsHandler) GetRealtime(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	stats, err := h.service.GetRealtime(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("failed to fetch realtime stats", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch realtime stats")
+	}
+
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Export Stats
+// @Description Downloads a zip with a CSV per report for the range and filters: overview, visitors and pageviews over time, and every row of the pages, referrers, countries, browsers, devices and operating systems breakdowns
+// @Tags Analytics
+// @Produce  application/zip
+// @Param trackingID query string true "app tracking ID"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param interval query string false "time bucket size, defaults to one suited to the range" Enums(minute, hour, day, week, month)
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Security BearerAuth
+// @Success 200 {file} file "zipped reports"
 // @Failure 400 {object} types.APIStatus "invalid request paramaters"
-// @Failure 500 {object} types.APIStatus "failed to fetch visitors"
-// @Router /analytics/pageviews [get]
-func (h *AnalyticsHandler) GetPageViews(ctx *gin.Context) types.APIResponse {
+// @Failure 500 {object} types.APIStatus "failed to export stats"
+// @Router /analytics/export [get]
+func (h *AnalyticsHandler) ExportStats(ctx *gin.Context) {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		ctx.JSON(http.StatusUnauthorized, types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context"))
+		return
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// the reports are small, so the zip is built before anything is sent and
+	// failures still get an error response
+	var archive bytes.Buffer
+	if err := writeStatsZip(ctx, h.service, payload, &archive); err != nil {
+		h.logger.Error("failed to export stats", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, types.NewErrorResponse(http.StatusInternalServerError, "failed to export stats"))
+		return
+	}
+
+	filename := fmt.Sprintf("stats-%s.zip", trackingID)
+	if payload.StartDate.Valid && payload.EndDate.Valid {
+		location, _ := time.LoadLocation(payload.Timezone)
+		// the end date is exclusive, the file is named after the last day it covers
+		lastDay := payload.EndDate.Time.In(location).Add(-time.Nanosecond)
+		filename = fmt.Sprintf("stats-%s-%s-%s.zip", trackingID, payload.StartDate.Time.In(location).Format("2006-01-02"), lastDay.Format("2006-01-02"))
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// @Summary Stream Events
+// @Description Streams tracked events as Server-Sent Events. A "realtime" event with the current stats is sent first, then an "event" for every tracked event with the updated visitor count, and a "ping" every 15 seconds
+// @Tags Analytics
+// @Produce  text/event-stream
+// @Param trackingID query string true "app tracking ID"
+// @Security BearerAuth
+// @Success 200 {object} types.LiveUpdate "stream of events"
+// @Failure 500 {object} types.APIStatus "failed to fetch realtime stats"
+// @Router /analytics/realtime/stream [get]
+func (h *AnalyticsHandler) StreamEvents(ctx *gin.Context) {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		ctx.JSON(http.StatusUnauthorized, types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context"))
+		return
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	// subscribe before taking the snapshot so no event falls between the two
+	updates, unsubscribe, err := h.service.SubscribeEvents(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("failed to subscribe to events", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, types.NewErrorResponse(http.StatusInternalServerError, "failed to subscribe to events"))
+		return
+	}
+	defer unsubscribe()
+
+	stats, err := h.service.GetRealtime(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("failed to fetch realtime stats", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch realtime stats"))
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("realtime", stats)
+	ctx.Writer.Flush()
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			ctx.SSEvent("event", update)
+		case now := <-heartbeat.C:
+			ctx.SSEvent("ping", now.UTC().Format(time.RFC3339))
+		case <-ctx.Request.Context().Done():
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// @Summary Get Retention
+// @Description Retrieves visitor retention cohorts. Requires retention tracking to be enabled for the app
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param cohort query string false "cohort period (week or month)"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Security BearerAuth
+// @Success 200 {object} types.RetentionResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch retention"
+// @Router /analytics/retention [get]
+func (h *AnalyticsHandler) GetRetention(ctx *gin.Context) types.APIResponse {
 	trackingID_, exists := ctx.Get("trackingID")
 	if !exists {
 		h.logger.Warn("trackingID not found in context")
@@ -539,25 +2261,389 @@ func (h *AnalyticsHandler) GetPageViews(ctx *gin.Context) types.APIResponse {
 	}
 	trackingID := trackingID_.(uuid.UUID)
 
-	startDate := ctx.Query("startDate")
-	endDate := ctx.Query("endDate")
+	period := ctx.DefaultQuery("cohort", "week")
+	if period != "week" && period != "month" {
+		return types.NewErrorResponse(http.StatusBadRequest, "cohort must be either week or month")
+	}
 
-	payload, err := createRequestPayload(trackingID, startDate, endDate)
+	payload, err := h.parseRequestPayload(ctx, trackingID)
 	if err != nil {
 		h.logger.Error("invalid request parameters", zap.Error(err))
 		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
 	}
 
-	stats, err := h.service.GetPageViews(ctx, payload)
+	stats, err := h.service.GetRetention(ctx, types.RetentionPayload{RequestPayload: payload, Period: period})
 	if err != nil {
-		h.logger.Error("failed to fetch page views", zap.Error(err))
-		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch page views")
+		if errors.Is(err, ErrRetentionDisabled) {
+			return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		h.logger.Error("failed to fetch retention", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch retention")
+	}
+
+	setCacheControl(ctx, payload)
+	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
+}
+
+// @Summary Get User Flow
+// @Description Retrieves the most common steps visitors take after (or before) a page or event, as Sankey-ready nodes and links
+// @Tags Analytics
+// @Accept  json
+// @Produce  json
+// @Param trackingID query string true "app tracking ID"
+// @Param page query string false "starting page path, e.g. /pricing"
+// @Param event query string false "starting event name"
+// @Param direction query string false "next or previous (default next)"
+// @Param steps query int false "number of steps to follow, 1 to 5 (default 3)"
+// @Param period query string false "date range preset" Enums(realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date, all)
+// @Param startDate query string false "start date, YYYY-MM-DD or RFC3339"
+// @Param endDate query string false "end date, inclusive when YYYY-MM-DD, exclusive when RFC3339"
+// @Param filters query string false "JSON array of filters, e.g. [{\"dimension\":\"country\",\"operator\":\"is\",\"values\":[\"Nigeria\"]}]"
+// @Param imported query boolean false "include imported data (default true)"
+// @Param tz query string false "IANA timezone for dates and buckets, defaults to the app's timezone"
+// @Security BearerAuth
+// @Success 200 {object} types.FlowResponse "stats fetched successfully"
+// @Failure 400 {object} types.APIStatus "invalid request paramaters"
+// @Failure 500 {object} types.APIStatus "failed to fetch user flow"
+// @Router /analytics/flow [get]
+func (h *AnalyticsHandler) GetUserFlow(ctx *gin.Context) types.APIResponse {
+	trackingID_, exists := ctx.Get("trackingID")
+	if !exists {
+		h.logger.Warn("trackingID not found in context")
+		return types.NewErrorResponse(http.StatusUnauthorized, "trackingID not found in context")
+	}
+	trackingID := trackingID_.(uuid.UUID)
+
+	page := ctx.Query("page")
+	event := ctx.Query("event")
+	if (page == "") == (event == "") {
+		return types.NewErrorResponse(http.StatusBadRequest, "specify either page or event")
+	}
+
+	step := page
+	if event != "" {
+		step = event
+	}
+
+	direction := ctx.DefaultQuery("direction", "next")
+	if direction != "next" && direction != "previous" {
+		return types.NewErrorResponse(http.StatusBadRequest, "direction must be either next or previous")
+	}
+
+	steps, err := strconv.Atoi(ctx.DefaultQuery("steps", "3"))
+	if err != nil || steps < 1 || steps > 5 {
+		return types.NewErrorResponse(http.StatusBadRequest, "steps must be between 1 and 5")
+	}
+
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		h.logger.Error("invalid request parameters", zap.Error(err))
+		return types.NewErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.service.GetUserFlow(ctx, types.FlowPayload{
+		RequestPayload: payload,
+		Step:           step,
+		Direction:      direction,
+		Steps:          steps,
+	})
+	if err != nil {
+		h.logger.Error("failed to fetch user flow", zap.Error(err))
+		return types.NewErrorResponse(http.StatusInternalServerError, "failed to fetch user flow")
 	}
 
+	setCacheControl(ctx, payload)
 	return types.NewSuccessResponse(stats, http.StatusOK, "stats fetched successfully")
 }
 
-func createRequestPayload(trackingID uuid.UUID, startDateStr, endDateStr string) (types.RequestPayload, error) {
+// parseRequestPayload builds the payload shared by all stats endpoints from the query string.
+func (h *AnalyticsHandler) parseRequestPayload(ctx *gin.Context, trackingID uuid.UUID) (types.RequestPayload, error) {
+	location, err := parseTimezone(ctx)
+	if err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	var payload types.RequestPayload
+	if period := ctx.Query("period"); period != "" {
+		if ctx.Query("startDate") != "" || ctx.Query("endDate") != "" {
+			return types.RequestPayload{}, fmt.Errorf("specify either period or startDate and endDate, not both")
+		}
+		payload, err = createPeriodPayload(trackingID, period, time.Now(), ctx.GetTime("createdAt"), location)
+	} else {
+		payload, err = createRequestPayload(trackingID, ctx.Query("startDate"), ctx.Query("endDate"), location)
+	}
+	if err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	filters, err := parseFilters(ctx.Query("filters"))
+	if err != nil {
+		return types.RequestPayload{}, err
+	}
+	if hasRegexFilter(filters) {
+		if err := h.service.ValidateRegexFilters(ctx, filters); err != nil {
+			return types.RequestPayload{}, err
+		}
+	}
+
+	payload.Filters = filters
+	if err := parseImported(&payload, ctx.Query("imported")); err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	if err := parseInterval(&payload, ctx.Query("interval")); err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	if err := parseComparison(&payload, ctx.Query("compare"), ctx.Query("compareStartDate"), ctx.Query("compareEndDate")); err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	payload.Precision = ctx.DefaultQuery("precision", precisionExact)
+	if payload.Precision != precisionExact && payload.Precision != precisionApprox {
+		return types.RequestPayload{}, fmt.Errorf("precision must be exact or approx")
+	}
+
+	return payload, nil
+}
+
+// setCacheControl lets browsers reuse a report for as long as the service
+// caches it: a minute for ranges that include now, while reports of closed
+// ranges are revalidated with their ETag on every use.
+func setCacheControl(ctx *gin.Context, payload types.RequestPayload) {
+	if includesNow(payload, time.Now()) {
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(liveCacheTTL.Seconds())))
+		return
+	}
+	ctx.Header("Cache-Control", "private, no-cache")
+}
+
+// parseExportPayload reads the range, filters, format and columns of an export.
+func (h *AnalyticsHandler) parseExportPayload(ctx *gin.Context, trackingID uuid.UUID) (types.ExportPayload, error) {
+	location, err := parseTimezone(ctx)
+	if err != nil {
+		return types.ExportPayload{}, err
+	}
+
+	payload, err := createRequestPayload(trackingID, ctx.Query("startDate"), ctx.Query("endDate"), location)
+	if err != nil {
+		return types.ExportPayload{}, err
+	}
+
+	if payload.Filters, err = parseFilters(ctx.Query("filters")); err != nil {
+		return types.ExportPayload{}, err
+	}
+	if hasRegexFilter(payload.Filters) {
+		if err := h.service.ValidateRegexFilters(ctx, payload.Filters); err != nil {
+			return types.ExportPayload{}, err
+		}
+	}
+	if err := parseImported(&payload, ctx.Query("imported")); err != nil {
+		return types.ExportPayload{}, err
+	}
+
+	format, err := export.ParseFormat(ctx.DefaultQuery("format", string(export.FormatCSV)))
+	if err != nil {
+		return types.ExportPayload{}, err
+	}
+
+	columns, err := export.ParseColumns(ctx.Query("columns"))
+	if err != nil {
+		return types.ExportPayload{}, err
+	}
+
+	return types.ExportPayload{RequestPayload: payload, Format: string(format), Columns: columns}, nil
+}
+
+// defaultBreakdownLimit and maxBreakdownLimit bound how many rows a page of a breakdown returns.
+const (
+	defaultBreakdownLimit = 100
+	maxBreakdownLimit     = 1000
+)
+
+var breakdownSorts = map[string]bool{
+	"visitors:desc": true,
+	"visitors:asc":  true,
+	"name:asc":      true,
+	"name:desc":     true,
+}
+
+// parseBreakdownPayload adds the paging, sort and other options of the breakdown endpoints to the request payload.
+func (h *AnalyticsHandler) parseBreakdownPayload(ctx *gin.Context, trackingID uuid.UUID) (types.BreakdownPayload, error) {
+	payload, err := h.parseRequestPayload(ctx, trackingID)
+	if err != nil {
+		return types.BreakdownPayload{}, err
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultBreakdownLimit)))
+	if err != nil || limit < 1 || limit > maxBreakdownLimit {
+		return types.BreakdownPayload{}, fmt.Errorf("limit must be between 1 and %d", maxBreakdownLimit)
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return types.BreakdownPayload{}, fmt.Errorf("page must be a positive number")
+	}
+
+	sort := ctx.DefaultQuery("sort", "visitors:desc")
+	if !breakdownSorts[sort] {
+		return types.BreakdownPayload{}, fmt.Errorf("sort must be one of visitors:desc, visitors:asc, name:asc or name:desc")
+	}
+
+	other, err := strconv.ParseBool(ctx.DefaultQuery("other", "false"))
+	if err != nil {
+		return types.BreakdownPayload{}, fmt.Errorf("other must be true or false")
+	}
+
+	return types.BreakdownPayload{
+		RequestPayload: payload,
+		Limit:          limit,
+		Page:           page,
+		Sort:           sort,
+		Other:          other,
+	}, nil
+}
+
+// maxTimeBuckets caps how many buckets a time series may return, so a small
+// interval can't be combined with a long range.
+const maxTimeBuckets = 1500
+
+type timeInterval struct {
+	bucket string
+	length time.Duration
+}
+
+// timeIntervals maps the interval parameter to a time_bucket width. Month
+// lengths vary, 31 days is only used to bound the number of buckets.
+var timeIntervals = map[string]timeInterval{
+	"minute": {bucket: "1 minute", length: time.Minute},
+	"hour":   {bucket: "1 hour", length: time.Hour},
+	"day":    {bucket: "1 day", length: 24 * time.Hour},
+	"week":   {bucket: "1 week", length: 7 * 24 * time.Hour},
+	"month":  {bucket: "1 month", length: 31 * 24 * time.Hour},
+}
+
+// parseImported excludes imported events when imported is false. It's a filter
+// on the imported dimension, so like other filters it's answered from raw
+// events.
+func parseImported(payload *types.RequestPayload, imported string) error {
+	if imported == "" {
+		return nil
+	}
+	include, err := strconv.ParseBool(imported)
+	if err != nil {
+		return fmt.Errorf("imported must be true or false")
+	}
+	if !include {
+		payload.Filters = append(payload.Filters, types.Filter{Dimension: "imported", Operator: "is", Values: []string{"false"}})
+	}
+	return nil
+}
+
+// parseInterval overrides the default bucket size, checking the interval isn't
+// too fine for the requested range.
+func parseInterval(payload *types.RequestPayload, interval string) error {
+	if interval == "" {
+		return nil
+	}
+
+	selected, ok := timeIntervals[interval]
+	if !ok {
+		return fmt.Errorf("interval must be one of minute, hour, day, week or month")
+	}
+
+	rangeLength := 24 * time.Hour
+	if payload.StartDate.Valid && payload.EndDate.Valid {
+		rangeLength = payload.EndDate.Time.Sub(payload.StartDate.Time)
+	}
+	if rangeLength/selected.length > maxTimeBuckets {
+		return fmt.Errorf("interval %q is too small for the requested range, choose a larger interval", interval)
+	}
+
+	payload.BucketSize = selected.bucket
+	return nil
+}
+
+// parseComparison validates the compare option. A custom comparison takes its
+// own date range, the other options derive it from the requested one.
+func parseComparison(payload *types.RequestPayload, compare, startDateStr, endDateStr string) error {
+	if compare != "custom" && (startDateStr != "" || endDateStr != "") {
+		return fmt.Errorf("compareStartDate and compareEndDate require compare=custom")
+	}
+
+	switch compare {
+	case "":
+		return nil
+	case "previous_period", "year_over_year":
+	case "custom":
+		if startDateStr == "" || endDateStr == "" {
+			return fmt.Errorf("compare=custom requires compareStartDate and compareEndDate")
+		}
+		location, err := time.LoadLocation(payload.Timezone)
+		if err != nil {
+			return err
+		}
+		comparison, err := createRequestPayload(payload.TrackingID, startDateStr, endDateStr, location)
+		if err != nil {
+			return err
+		}
+		payload.CompareStartDate = comparison.StartDate
+		payload.CompareEndDate = comparison.EndDate
+	default:
+		return fmt.Errorf("compare must be one of previous_period, year_over_year or custom")
+	}
+
+	payload.Compare = compare
+	return nil
+}
+
+// parseTimezone returns the tz override if one was given, otherwise the app's timezone.
+func parseTimezone(ctx *gin.Context) (*time.Location, error) {
+	timezone := ctx.Query("tz")
+	if timezone == "" {
+		timezone = ctx.GetString("timezone")
+	}
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := loadTimezone(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	return location, nil
+}
+
+// loadTimezone loads an IANA timezone Postgres knows too. time.LoadLocation
+// also takes "" and "Local", which Postgres rejects.
+func loadTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", timezone)
+	}
+	return time.LoadLocation(timezone)
+}
+
+// dataRetentionPattern matches retention periods such as "30 days" or "13 months".
+var dataRetentionPattern = regexp.MustCompile(`^([1-9][0-9]{0,3}) (day|week|month|year)s?$`)
+
+// parseDataRetention validates a retention period and normalizes it to an
+// interval Postgres accepts, keeping "forever" as is.
+func parseDataRetention(retention string) (string, error) {
+	if retention == forever {
+		return retention, nil
+	}
+
+	match := dataRetentionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(retention)))
+	if match == nil {
+		return "", fmt.Errorf("data_retention must be forever or a number of days, weeks, months or years, e.g. 30 days")
+	}
+	if match[1] == "1" {
+		return match[1] + " " + match[2], nil
+	}
+	return match[1] + " " + match[2] + "s", nil
+}
+
+func createRequestPayload(trackingID uuid.UUID, startDateStr, endDateStr string, location *time.Location) (types.RequestPayload, error) {
 	if (startDateStr == "" && endDateStr != "") || (startDateStr != "" && endDateStr == "") {
 		return types.RequestPayload{}, fmt.Errorf("either specify both startDate and endDate, or specify neither")
 	}
@@ -568,14 +2654,24 @@ func createRequestPayload(trackingID uuid.UUID, startDateStr, endDateStr string)
 			BucketSize: "1 hour",
 			StartDate:  sql.NullTime{},
 			EndDate:    sql.NullTime{},
+			Timezone:   location.String(),
 		}, nil
 	}
-	parsedTimes, err := parseDates(startDateStr, endDateStr)
+
+	startDate, _, err := parseDate(startDateStr, location)
+	if err != nil {
+		return types.RequestPayload{}, err
+	}
+
+	endDate, dateOnly, err := parseDate(endDateStr, location)
 	if err != nil {
 		return types.RequestPayload{}, err
 	}
 
-	startDate, endDate := parsedTimes[0], parsedTimes[1]
+	// a plain end date includes the whole day
+	if dateOnly {
+		endDate = endDate.AddDate(0, 0, 1)
+	}
 
 	if startDate.After(endDate) {
 		return types.RequestPayload{}, fmt.Errorf("startDate cannot be after endDate")
@@ -587,12 +2683,68 @@ func createRequestPayload(trackingID uuid.UUID, startDateStr, endDateStr string)
 
 	return types.RequestPayload{
 		TrackingID: trackingID,
-		BucketSize: "1 day",
+		BucketSize: defaultBucketSize(endDate.Sub(startDate)),
+		StartDate:  sql.NullTime{Time: startDate, Valid: true},
+		EndDate:    sql.NullTime{Time: endDate, Valid: true},
+		Timezone:   location.String(),
+	}, nil
+}
+
+// createPeriodPayload resolves a period preset to a range ending now in location.
+// The "all" preset starts from the month the app was created.
+func createPeriodPayload(trackingID uuid.UUID, period string, now, createdAt time.Time, location *time.Location) (types.RequestPayload, error) {
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+
+	var startDate, endDate time.Time
+	var bucketSize string
+	switch period {
+	case "realtime":
+		startDate, endDate, bucketSize = now.Add(-30*time.Minute), now, "1 minute"
+	case "today":
+		startDate, endDate, bucketSize = today, now, "1 hour"
+	case "yesterday":
+		startDate, endDate, bucketSize = today.AddDate(0, 0, -1), today, "1 hour"
+	case "7d":
+		startDate, endDate, bucketSize = today.AddDate(0, 0, -6), now, "1 day"
+	case "30d":
+		startDate, endDate, bucketSize = today.AddDate(0, 0, -29), now, "1 day"
+	case "month_to_date":
+		startDate, endDate, bucketSize = monthStart, now, "1 day"
+	case "last_month":
+		startDate, endDate, bucketSize = monthStart.AddDate(0, -1, 0), monthStart, "1 day"
+	case "12mo":
+		startDate, endDate, bucketSize = monthStart.AddDate(0, -11, 0), now, "1 month"
+	case "year_to_date":
+		startDate, endDate, bucketSize = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, location), now, "1 month"
+	case "all":
+		if createdAt.IsZero() {
+			return types.RequestPayload{}, fmt.Errorf("app creation date is unknown")
+		}
+		createdAt = createdAt.In(location)
+		startDate, endDate, bucketSize = time.Date(createdAt.Year(), createdAt.Month(), 1, 0, 0, 0, 0, location), now, "1 month"
+	default:
+		return types.RequestPayload{}, fmt.Errorf("period must be one of realtime, today, yesterday, 7d, 30d, month_to_date, last_month, 12mo, year_to_date or all")
+	}
+
+	return types.RequestPayload{
+		TrackingID: trackingID,
+		BucketSize: bucketSize,
 		StartDate:  sql.NullTime{Time: startDate, Valid: true},
-		EndDate:    sql.NullTime{Time: endDate.Add(24 * time.Hour), Valid: true},
+		EndDate:    sql.NullTime{Time: endDate, Valid: true},
+		Timezone:   location.String(),
 	}, nil
 }
 
+// defaultBucketSize picks hourly buckets for ranges of up to two days and daily ones otherwise.
+func defaultBucketSize(rangeLength time.Duration) string {
+	if rangeLength <= 48*time.Hour {
+		return "1 hour"
+	}
+	return "1 day"
+}
+
 func createAppPayload(name string, userID, trackingID uuid.UUID) types.AppPayload {
 	return types.AppPayload{
 		Name:       name,
@@ -601,17 +2753,81 @@ func createAppPayload(name string, userID, trackingID uuid.UUID) types.AppPayloa
 	}
 }
 
-func parseDates(dateStrings ...string) ([]time.Time, error) {
-	const layout = "2006-01-02"
-	parsedTimes := make([]time.Time, 0, len(dateStrings))
+// parseDate accepts a plain date, taken as midnight in location, or an RFC3339
+// datetime. It reports whether the value was a plain date.
+func parseDate(value string, location *time.Location) (time.Time, bool, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+		return parsed, true, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.In(location), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date format for %q, expect format: YYYY-MM-DD or RFC3339", value)
+}
+
+var filterDimensions = map[string]bool{
+	"page":         true,
+	"hostname":     true,
+	"referrer":     true,
+	"country":      true,
+	"browser":      true,
+	"device":       true,
+	"os":           true,
+	"event":        true,
+	"imported":     true,
+	"utm_source":   true,
+	"utm_medium":   true,
+	"utm_campaign": true,
+	"utm_term":     true,
+	"utm_content":  true,
+}
 
-	for _, dateString := range dateStrings {
-		parsedTime, err := time.Parse(layout, dateString)
-		if err != nil {
-			return nil, fmt.Errorf("invalid date format for %q, expect format: YY-MM-DD", dateString)
+var filterOperators = map[string]bool{
+	"is":       true,
+	"is_not":   true,
+	"contains": true,
+	"regex":    true,
+	"any_of":   true,
+}
+
+// parseFilters decodes the JSON filters query parameter. Event properties are
+// filtered with a "prop:<name>" dimension.
+func parseFilters(raw string) ([]types.Filter, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var filters []types.Filter
+	if err := json.Unmarshal([]byte(raw), &filters); err != nil {
+		return nil, fmt.Errorf("invalid filters, expect a JSON array of {dimension, operator, values}")
+	}
+	if err := validateFilters(filters); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// hasRegexFilter reports whether filters have a regex pattern to validate.
+func hasRegexFilter(filters []types.Filter) bool {
+	return slices.ContainsFunc(filters, func(filter types.Filter) bool { return filter.Operator == "regex" })
+}
+
+// validateFilters checks the dimension, operator and values of each filter.
+// Regex patterns are left to ValidateRegexFilters, as Postgres runs them.
+func validateFilters(filters []types.Filter) error {
+	for _, filter := range filters {
+		isProperty := strings.HasPrefix(filter.Dimension, "prop:") && len(filter.Dimension) > len("prop:")
+		if !filterDimensions[filter.Dimension] && !isProperty {
+			return fmt.Errorf("unsupported filter dimension %q", filter.Dimension)
+		}
+		if !filterOperators[filter.Operator] {
+			return fmt.Errorf("unsupported filter operator %q", filter.Operator)
+		}
+		if len(filter.Values) == 0 {
+			return fmt.Errorf("filter on %q requires at least one value", filter.Dimension)
 		}
-		parsedTimes = append(parsedTimes, parsedTime)
 	}
 
-	return parsedTimes, nil
+	return nil
 }
@@ -1,14 +1,23 @@
 package server
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ScMofeoluwa/minalytics/mocks"
 	types "github.com/ScMofeoluwa/minalytics/shared"
@@ -125,6 +134,7 @@ func (suite *HandlerSuite) TestTrackEvent() {
 }
 
 func (suite *HandlerSuite) TestCreateApp() {
+	orgID := uuid.New()
 	testCases := []struct {
 		name       string
 		mockSetup  func()
@@ -145,17 +155,39 @@ func (suite *HandlerSuite) TestCreateApp() {
 		{
 			name: "failed to create app",
 			mockSetup: func() {
-				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, mock.Anything).Return(&types.App{}, fmt.Errorf("failed to create app")).Once()
+				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, uuid.Nil, "test app").Return(&types.App{}, fmt.Errorf("failed to create app")).Once()
 			},
 			req: types.CreateAppRequest{
 				Name: "test app",
 			},
 			statusCode: http.StatusInternalServerError,
 		},
+		{
+			name: "not an admin of the organization",
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, orgID, "test app").Return(nil, ErrOrganizationForbidden).Once()
+			},
+			req: types.CreateAppRequest{
+				Name:  "test app",
+				OrgID: orgID,
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "name taken in the organization",
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, orgID, "test app").Return(nil, ErrAppExists).Once()
+			},
+			req: types.CreateAppRequest{
+				Name:  "test app",
+				OrgID: orgID,
+			},
+			statusCode: http.StatusConflict,
+		},
 		{
 			name: "app created successfully",
 			mockSetup: func() {
-				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, mock.Anything).Return(&types.App{}, nil).Once()
+				suite.mockService.EXPECT().CreateApp(mock.Anything, mock.Anything, uuid.Nil, "test app").Return(&types.App{}, nil).Once()
 			},
 			req: types.CreateAppRequest{
 				Name: "test app",
@@ -242,22 +274,35 @@ func (suite *HandlerSuite) TestGetApps() {
 
 func (suite *HandlerSuite) TestUpdateApp() {
 	trackingID := uuid.New()
+	name := "Updated App"
+	empty := ""
+	enabled := true
+	timezone := "America/Los_Angeles"
+	invalidTimezone := "Mars/Olympus_Mons"
+	local := "Local"
+	retention, invalidRetention, keepForever := "13 Months", "13 fortnights", "forever"
 	testCases := []struct {
 		name       string
 		mockSetup  func()
-		req        types.CreateAppRequest
+		req        types.UpdateAppRequest
 		statusCode int
 	}{
 		{
 			name:       "userID not found in context",
 			mockSetup:  func() {},
-			req:        types.CreateAppRequest{Name: "Updated App"},
+			req:        types.UpdateAppRequest{Name: &name},
 			statusCode: http.StatusUnauthorized,
 		},
 		{
 			name:       "invalid request body",
 			mockSetup:  func() {},
-			req:        types.CreateAppRequest{},
+			req:        types.UpdateAppRequest{Name: &empty},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "empty request body",
+			mockSetup:  func() {},
+			req:        types.UpdateAppRequest{},
 			statusCode: http.StatusBadRequest,
 		},
 		{
@@ -265,7 +310,7 @@ func (suite *HandlerSuite) TestUpdateApp() {
 			mockSetup: func() {
 				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to update app")).Once()
 			},
-			req:        types.CreateAppRequest{Name: "Updated App"},
+			req:        types.UpdateAppRequest{Name: &name},
 			statusCode: http.StatusInternalServerError,
 		},
 		{
@@ -273,7 +318,71 @@ func (suite *HandlerSuite) TestUpdateApp() {
 			mockSetup: func() {
 				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.Anything).Return(&types.App{}, nil).Once()
 			},
-			req:        types.CreateAppRequest{Name: "Updated App"},
+			req:        types.UpdateAppRequest{Name: &name},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "retention tracking enabled",
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.MatchedBy(func(payload types.AppPayload) bool {
+					return payload.RetentionTracking != nil && *payload.RetentionTracking
+				})).Return(&types.App{RetentionTracking: true}, nil).Once()
+			},
+			req:        types.UpdateAppRequest{RetentionTracking: &enabled},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "invalid timezone",
+			mockSetup:  func() {},
+			req:        types.UpdateAppRequest{Timezone: &invalidTimezone},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "empty timezone",
+			mockSetup:  func() {},
+			req:        types.UpdateAppRequest{Timezone: &empty},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "server local timezone",
+			mockSetup:  func() {},
+			req:        types.UpdateAppRequest{Timezone: &local},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "timezone updated",
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.MatchedBy(func(payload types.AppPayload) bool {
+					return payload.Timezone != nil && *payload.Timezone == timezone
+				})).Return(&types.App{Timezone: timezone}, nil).Once()
+			},
+			req:        types.UpdateAppRequest{Timezone: &timezone},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "invalid data retention",
+			mockSetup:  func() {},
+			req:        types.UpdateAppRequest{DataRetention: &invalidRetention},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "data retention updated",
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.MatchedBy(func(payload types.AppPayload) bool {
+					return payload.DataRetention != nil && *payload.DataRetention == "13 months"
+				})).Return(&types.App{DataRetention: "13 months"}, nil).Once()
+			},
+			req:        types.UpdateAppRequest{DataRetention: &retention},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "data retention removed",
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateApp(mock.Anything, mock.MatchedBy(func(payload types.AppPayload) bool {
+					return payload.DataRetention != nil && *payload.DataRetention == forever
+				})).Return(&types.App{DataRetention: forever}, nil).Once()
+			},
+			req:        types.UpdateAppRequest{DataRetention: &keepForever},
 			statusCode: http.StatusOK,
 		},
 	}
@@ -358,126 +467,2238 @@ func (suite *HandlerSuite) TestDeleteApp() {
 	}
 }
 
-func (suite *HandlerSuite) TestGetAnalyticsEndpoints() {
-	type analyticsTest struct {
+func (suite *HandlerSuite) TestExportEvents() {
+	trackingID := uuid.New()
+	app := &types.App{TrackingID: trackingID, Timezone: "Africa/Lagos"}
+	testCases := []struct {
 		name       string
-		startDate  string
-		endDate    string
+		trackingID string
+		query      string
 		mockSetup  func()
 		statusCode int
-	}
-
-	testEndpoint := func(endpoint string, funcName string, handler func(*gin.Context) types.APIResponse, mockResult interface{}) {
-		testCases := []analyticsTest{
-			{
-				name:       "trackingID not found in context",
-				startDate:  "",
-				endDate:    "",
-				mockSetup:  func() {},
-				statusCode: http.StatusUnauthorized,
-			},
-			{
-				name:       "only start date provided",
-				startDate:  "2025-03-01",
-				mockSetup:  func() {},
-				statusCode: http.StatusBadRequest,
-			},
-			{
-				name:       "only end date provided",
-				endDate:    "2025-03-06",
-				mockSetup:  func() {},
-				statusCode: http.StatusBadRequest,
+		body       string
+	}{
+		{
+			name:       "invalid trackingID",
+			trackingID: "not-a-uuid",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "app not found",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(nil, ErrAppNotFound).Once()
 			},
-			{
-				name:       "invalid date format",
-				startDate:  "2025/03/01",
-				endDate:    "2025/03/06",
-				mockSetup:  func() {},
-				statusCode: http.StatusBadRequest,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:  "unsupported format",
+			query: "?format=xlsx",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
 			},
-			{
-				name:       "start date after end date",
-				startDate:  "2025-03-06",
-				endDate:    "2025-03-01",
-				mockSetup:  func() {},
-				statusCode: http.StatusBadRequest,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown column",
+			query: "?columns=url,password",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
 			},
-			{
-				name:       "same dates",
-				startDate:  "2025-03-01",
-				endDate:    "2025-03-01",
-				mockSetup:  func() {},
-				statusCode: http.StatusBadRequest,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed to export events",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().ExportEvents(mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("database error")).Once()
 			},
-			{
-				name:      "failed to fetch" + endpoint,
-				startDate: "2025-03-01",
-				endDate:   "2025-03-06",
-				mockSetup: func() {
-					suite.mockService.On(funcName, mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
-				},
-				statusCode: http.StatusInternalServerError,
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "failure after the export started",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().ExportEvents(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, _ types.ExportPayload, w io.Writer) (int64, error) {
+					w.Write([]byte("url\n"))
+					return 0, errors.New("connection reset")
+				}).Once()
 			},
-			{
-				name:      "successful stats retrieval",
-				startDate: "2025-03-01",
-				endDate:   "2025-03-06",
-				mockSetup: func() {
-					suite.mockService.On(funcName, mock.Anything, mock.Anything).Return(mockResult, nil).Once()
-				},
-				statusCode: http.StatusOK,
+			statusCode: http.StatusOK,
+			body:       "url\n",
+		},
+		{
+			name:  "successful export",
+			query: "?format=ndjson&columns=timestamp,url&startDate=2025-03-01&endDate=2025-03-31&filters=" + neturl.QueryEscape(`[{"dimension":"country","operator":"is","values":["Nigeria"]}]`),
+			mockSetup: func() {
+				lagos, _ := time.LoadLocation("Africa/Lagos")
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().ExportEvents(mock.Anything, mock.MatchedBy(func(payload types.ExportPayload) bool {
+					return payload.TrackingID == trackingID && payload.Format == "ndjson" &&
+						slices.Equal(payload.Columns, []string{"timestamp", "url"}) &&
+						payload.StartDate.Time.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, lagos)) &&
+						payload.EndDate.Time.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, lagos)) &&
+						len(payload.Filters) == 1 && payload.Filters[0].Dimension == "country"
+				}), mock.Anything).RunAndReturn(func(_ context.Context, _ types.ExportPayload, w io.Writer) (int64, error) {
+					w.Write([]byte(`{"timestamp":"2025-03-02T10:00:00Z","url":"https://example.com/"}` + "\n"))
+					return 1, nil
+				}).Once()
 			},
-		}
+			statusCode: http.StatusOK,
+			body:       `{"timestamp":"2025-03-02T10:00:00Z","url":"https://example.com/"}` + "\n",
+		},
+	}
 
-		for _, tc := range testCases {
-			suite.Run(endpoint+" - "+tc.name, func() {
-				tc.mockSetup()
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
 
-				rr := httptest.NewRecorder()
-				url := "/analytics/" + endpoint
+			if tc.trackingID == "" {
+				tc.trackingID = trackingID.String()
+			}
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/apps/"+tc.trackingID+"/export"+tc.query, nil)
 
-				// Add query parameters if provided
-				if tc.startDate != "" || tc.endDate != "" {
-					url += "?"
-					if tc.startDate != "" {
-						url += "startDate=" + tc.startDate
-					}
-					if tc.endDate != "" {
-						if tc.startDate != "" {
-							url += "&"
-						}
-						url += "endDate=" + tc.endDate
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: tc.trackingID}}
+
+			suite.handler.ExportEvents(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.body != "" {
+				suite.Equal(tc.body, rr.Body.String())
+			}
+			if tc.name == "successful export" {
+				suite.Equal("application/x-ndjson", rr.Header().Get("Content-Type"))
+				suite.Equal(`attachment; filename="events-`+trackingID.String()+`.ndjson"`, rr.Header().Get("Content-Disposition"))
+			}
+			if tc.statusCode == http.StatusInternalServerError {
+				suite.Contains(rr.Header().Get("Content-Type"), "application/json")
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateImport() {
+	trackingID := uuid.New()
+	testCases := []struct {
+		name       string
+		fields     map[string]string
+		file       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "unsupported source",
+			fields:     map[string]string{"source": "matomo"},
+			file:       "date,visitors\n",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid log format",
+			fields:     map[string]string{"source": "access_log", "log_format": "$remote_addr"},
+			file:       "203.0.113.9\n",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "missing file",
+			fields:     map[string]string{"source": "ga4"},
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "app not found",
+			fields: map[string]string{"source": "ga4"},
+			file:   "date,visitors\n",
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateImport(mock.Anything, mock.Anything, mock.Anything).Return(nil, ErrAppNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:   "import queued",
+			fields: map[string]string{"source": "umami", "hostname": "example.com", "website_id": "site"},
+			file:   "COPY public.website_event",
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateImport(mock.Anything, types.ImportPayload{
+					TrackingID: trackingID, Source: "umami", Hostname: "example.com", WebsiteID: "site",
+				}, mock.Anything).RunAndReturn(func(_ context.Context, payload types.ImportPayload, file io.Reader) (*types.Import, error) {
+					content, err := io.ReadAll(file)
+					if err != nil || string(content) != "COPY public.website_event" {
+						return nil, errors.New("unexpected upload")
 					}
-				}
+					return &types.Import{TrackingID: payload.TrackingID, Source: payload.Source, Status: "pending"}, nil
+				}).Once()
+			},
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:   "access log queued",
+			fields: map[string]string{"source": "access_log", "log_format": "common"},
+			file:   "203.0.113.9 - - [01/May/2024:10:00:00 +0000] \"GET / HTTP/1.1\" 200 512\n",
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateImport(mock.Anything, types.ImportPayload{
+					TrackingID: trackingID, Source: "access_log", LogFormat: "common",
+				}, mock.Anything).Return(&types.Import{TrackingID: trackingID, Source: "access_log", Status: "pending"}, nil).Once()
+			},
+			statusCode: http.StatusAccepted,
+		},
+	}
 
-				req := httptest.NewRequest(http.MethodGet, url, nil)
-				req.Header.Add("Content-Type", "application/json")
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
 
-				ctx := createGinContext(req, rr)
-				if tc.statusCode != http.StatusUnauthorized {
-					ctx.Set("trackingID", uuid.New())
-				}
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for key, value := range tc.fields {
+				form.WriteField(key, value)
+			}
+			if tc.file != "" {
+				part, _ := form.CreateFormFile("file", "export.csv")
+				part.Write([]byte(tc.file))
+			}
+			form.Close()
 
-				handlerFunc := WrapHandler(handler)
-				handlerFunc(ctx)
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+trackingID.String()+"/imports", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
 
-				suite.Equal(tc.statusCode, rr.Code)
-				suite.mockService.AssertExpectations(suite.T())
-			})
-		}
-	}
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.Nil)
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
 
-	// Test all analytics endpoints
-	testEndpoint("referrals", "GetReferrals", suite.handler.GetReferrals, []types.ReferralStats{})
-	testEndpoint("pages", "GetPages", suite.handler.GetPages, []types.PageStats{})
-	testEndpoint("browsers", "GetBrowsers", suite.handler.GetBrowsers, []types.BrowserStats{})
-	testEndpoint("countries", "GetCountries", suite.handler.GetCountries, []types.CountryStats{})
-	testEndpoint("devices", "GetDevices", suite.handler.GetDevices, []types.DeviceStats{})
-	testEndpoint("os", "GetOS", suite.handler.GetOS, []types.OSStats{})
-	testEndpoint("visitors", "GetVisitors", suite.handler.GetVisitors, []types.VisitorStats{})
-	testEndpoint("pageviews", "GetPageViews", suite.handler.GetPageViews, []types.PageViewStats{})
+			WrapHandler(suite.handler.CreateImport)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
 }
 
-func TestHandlerSuite(t *testing.T) {
-	suite.Run(t, new(HandlerSuite))
+func (suite *HandlerSuite) TestGetImport() {
+	trackingID, importID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		importID   string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid importID",
+			importID:   "not-a-uuid",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "import not found",
+			importID: importID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetImport(mock.Anything, mock.Anything, trackingID, importID).Return(nil, ErrImportNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:     "failed to fetch import",
+			importID: importID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetImport(mock.Anything, mock.Anything, trackingID, importID).Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "import fetched",
+			importID: importID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetImport(mock.Anything, mock.Anything, trackingID, importID).Return(&types.Import{ID: importID, Status: "running", Progress: 0.4}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/apps/"+trackingID.String()+"/imports/"+tc.importID, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}, {Key: "importID", Value: tc.importID}}
+
+			WrapHandler(suite.handler.GetImport)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusOK {
+				suite.Contains(rr.Body.String(), `"progress":0.4`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+
+	suite.Run("imports listed", func() {
+		suite.mockService.EXPECT().GetImports(mock.Anything, mock.Anything, trackingID).Return([]types.Import{{ID: importID}}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/apps/"+trackingID.String()+"/imports", nil)
+		ctx := createGinContext(req, rr)
+		ctx.Set("userID", uuid.New())
+		ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+		WrapHandler(suite.handler.GetImports)(ctx)
+
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Contains(rr.Body.String(), importID.String())
+		suite.mockService.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *HandlerSuite) TestCreateDeletion() {
+	trackingID := uuid.New()
+	app := &types.App{TrackingID: trackingID, Timezone: "Africa/Lagos"}
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid request body",
+			body:       `{"dry_run": "yes"}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "app not found",
+			body: `{"visitor_id": "visitor-1"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(nil, ErrAppNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "start date without end date",
+			body: `{"start_date": "2024-05-01"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid filter",
+			body: `{"filters": [{"dimension": "password", "operator": "is", "values": ["x"]}]}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "no criteria",
+			body: `{}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().CreateDeletion(mock.Anything, mock.Anything).Return(nil, ErrNoDeletionCriteria).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "dry run",
+			body: `{"filters": [{"dimension": "referrer", "operator": "contains", "values": ["spam"]}], "dry_run": true}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().CreateDeletion(mock.Anything, mock.MatchedBy(func(payload types.DeletionPayload) bool {
+					return payload.DryRun && len(payload.Filters) == 1 && !payload.StartDate.Valid
+				})).Return(&types.Deletion{Status: "dry_run", MatchedEvents: 12}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "deletion queued",
+			body: `{"start_date": "2024-05-01", "end_date": "2024-05-01", "visitor_id": "visitor-1"}`,
+			mockSetup: func() {
+				lagos, _ := time.LoadLocation("Africa/Lagos")
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, mock.Anything, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().CreateDeletion(mock.Anything, mock.MatchedBy(func(payload types.DeletionPayload) bool {
+					// dates are read in the app's timezone, a plain end date includes the day
+					return !payload.DryRun && payload.VisitorID == "visitor-1" &&
+						payload.StartDate.Time.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, lagos)) &&
+						payload.EndDate.Time.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, lagos))
+				})).Return(&types.Deletion{Status: "pending"}, nil).Once()
+			},
+			statusCode: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+trackingID.String()+"/deletions", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+			WrapHandler(suite.handler.CreateDeletion)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusOK {
+				suite.Contains(rr.Body.String(), `"matched_events":12`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetDeletion() {
+	trackingID, deletionID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		deletionID string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid deletionID",
+			deletionID: "not-a-uuid",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "deletion not found",
+			deletionID: deletionID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetDeletion(mock.Anything, mock.Anything, trackingID, deletionID).Return(nil, ErrDeletionNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "deletion fetched",
+			deletionID: deletionID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetDeletion(mock.Anything, mock.Anything, trackingID, deletionID).Return(&types.Deletion{ID: deletionID, Status: "completed", DeletedEvents: 7}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/apps/"+trackingID.String()+"/deletions/"+tc.deletionID, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}, {Key: "deletionID", Value: tc.deletionID}}
+
+			WrapHandler(suite.handler.GetDeletion)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusOK {
+				suite.Contains(rr.Body.String(), `"deleted_events":7`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+
+	suite.Run("deletions listed", func() {
+		suite.mockService.EXPECT().GetDeletions(mock.Anything, mock.Anything, trackingID).Return([]types.Deletion{{ID: deletionID}}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/apps/"+trackingID.String()+"/deletions", nil)
+		ctx := createGinContext(req, rr)
+		ctx.Set("userID", uuid.New())
+		ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+		WrapHandler(suite.handler.GetDeletions)(ctx)
+
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Contains(rr.Body.String(), deletionID.String())
+		suite.mockService.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *HandlerSuite) TestCreateShare() {
+	trackingID := uuid.New()
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "missing name",
+			body:       `{"endpoints": ["overview"]}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported endpoint",
+			body: `{"name": "client", "endpoints": ["goals"]}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShare(mock.Anything, mock.Anything).Return(nil, ErrUnsupportedEndpoint).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "app not found",
+			body: `{"name": "client"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShare(mock.Anything, mock.Anything).Return(nil, ErrAppNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "share link created",
+			body: `{"name": "client", "endpoints": ["overview", "pages"], "password": "hunter2", "expires_at": "2030-01-01T00:00:00Z"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShare(mock.Anything, mock.MatchedBy(func(payload types.SharePayload) bool {
+					return payload.TrackingID == trackingID && payload.Name == "client" && len(payload.Endpoints) == 2 &&
+						payload.Password == "hunter2" && payload.ExpiresAt.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(&types.Share{Name: "client", Token: "secret", PasswordProtected: true}, nil).Once()
+			},
+			statusCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+trackingID.String()+"/shares", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+			WrapHandler(suite.handler.CreateShare)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusCreated {
+				suite.Contains(rr.Body.String(), `"token":"secret"`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestRevokeShare() {
+	trackingID, shareID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		shareID    string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid shareID",
+			shareID:    "not-a-uuid",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "share link not found",
+			shareID: shareID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().RevokeShare(mock.Anything, mock.Anything, trackingID, shareID).Return(nil, ErrShareNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:    "share link revoked",
+			shareID: shareID.String(),
+			mockSetup: func() {
+				revokedAt := time.Now()
+				suite.mockService.EXPECT().RevokeShare(mock.Anything, mock.Anything, trackingID, shareID).Return(&types.Share{ID: shareID, RevokedAt: &revokedAt}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/apps/"+trackingID.String()+"/shares/"+tc.shareID, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}, {Key: "shareID", Value: tc.shareID}}
+
+			WrapHandler(suite.handler.RevokeShare)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusOK {
+				suite.Contains(rr.Body.String(), `"revoked_at"`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+
+	suite.Run("share links listed", func() {
+		suite.mockService.EXPECT().GetShares(mock.Anything, mock.Anything, trackingID).Return([]types.Share{{ID: shareID}}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/apps/"+trackingID.String()+"/shares", nil)
+		ctx := createGinContext(req, rr)
+		ctx.Set("userID", uuid.New())
+		ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+		WrapHandler(suite.handler.GetShares)(ctx)
+
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Contains(rr.Body.String(), shareID.String())
+		suite.NotContains(rr.Body.String(), `"token"`)
+		suite.mockService.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *HandlerSuite) TestShareMiddleware() {
+	trackingID := uuid.New()
+	app := &types.App{TrackingID: trackingID, Timezone: "Africa/Lagos"}
+
+	router := gin.New()
+	shared := router.Group("shared/:token")
+	shared.Use(ShareMiddleware(suite.mockService))
+	registerAnalyticsRoutes(shared, suite.handler)
+
+	testCases := []struct {
+		name       string
+		path       string
+		session    string
+		cookie     string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name: "unknown link",
+			path: "/shared/unknown/overview",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "unknown", "", "overview").Return(nil, ErrShareNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "expired link",
+			path: "/shared/secret/overview",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "secret", "", "overview").Return(nil, ErrShareExpired).Once()
+			},
+			statusCode: http.StatusGone,
+		},
+		{
+			name: "missing session",
+			path: "/shared/secret/overview",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "secret", "", "overview").Return(nil, ErrShareSessionRequired).Once()
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "session cookie",
+			path:   "/shared/secret/realtime",
+			cookie: "session",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "secret", "session", "realtime").Return(app, nil).Once()
+				suite.mockService.EXPECT().GetRealtime(mock.Anything, trackingID).Return(&types.RealtimeStats{CurrentVisitors: 3}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "endpoint not shared",
+			path: "/shared/secret/realtime/stream",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "secret", "", "realtime/stream").Return(nil, ErrEndpointNotShared).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:    "stats of the shared app",
+			path:    "/shared/secret/realtime",
+			session: "session",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateShareAccess(mock.Anything, "secret", "session", "realtime").Return(app, nil).Once()
+				suite.mockService.EXPECT().GetRealtime(mock.Anything, trackingID).Return(&types.RealtimeStats{CurrentVisitors: 3}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.session != "" {
+				req.Header.Set("X-Share-Session", tc.session)
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: shareSessionCookie, Value: tc.cookie})
+			}
+
+			router.ServeHTTP(rr, req)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateShareSession() {
+	expiresAt := time.Now().Add(shareSessionTTL)
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid body",
+			body:       `{`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "wrong password",
+			body: `{"password": "hunter3"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShareSession(mock.Anything, "secret", "hunter3").Return(nil, ErrInvalidSharePassword).Once()
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "too many wrong passwords",
+			body: `{"password": "hunter4"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShareSession(mock.Anything, "secret", "hunter4").Return(nil, ErrTooManyShareAttempts).Once()
+			},
+			statusCode: http.StatusTooManyRequests,
+		},
+		{
+			name: "session started",
+			body: `{"password": "hunter2"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateShareSession(mock.Anything, "secret", "hunter2").Return(&types.ShareSession{Token: "session", ExpiresAt: expiresAt}, nil).Once()
+			},
+			statusCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/shared/secret/session", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Params = gin.Params{{Key: "token", Value: "secret"}}
+
+			WrapHandler(suite.handler.CreateShareSession)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusCreated {
+				cookie := rr.Result().Cookies()[0]
+				suite.Equal(shareSessionCookie, cookie.Name)
+				suite.Equal("session", cookie.Value)
+				suite.Equal("/shared/secret", cookie.Path)
+				suite.True(cookie.HttpOnly)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestUpdateMember() {
+	trackingID, memberID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "missing role",
+			body:       `{}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid role",
+			body: `{"role": "owner"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateMember(mock.Anything, mock.Anything, trackingID, memberID, "owner").Return(ErrInvalidRole).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "role not allowed to",
+			body: `{"role": "admin"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateMember(mock.Anything, mock.Anything, trackingID, memberID, "admin").Return(ErrForbidden).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "member not found",
+			body: `{"role": "viewer"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateMember(mock.Anything, mock.Anything, trackingID, memberID, "viewer").Return(ErrMemberNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "member updated",
+			body: `{"role": "editor"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().UpdateMember(mock.Anything, mock.Anything, trackingID, memberID, "editor").Return(nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/apps/"+trackingID.String()+"/members/"+memberID.String(), strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}, {Key: "userID", Value: memberID.String()}}
+
+			WrapHandler(suite.handler.UpdateMember)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestTransferApp() {
+	trackingID, memberID, orgID := uuid.New(), uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "missing user_id",
+			body:       `{}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "not the owner",
+			body: `{"user_id": "` + memberID.String() + `"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().TransferApp(mock.Anything, mock.Anything, trackingID, memberID, uuid.Nil).Return(nil, ErrForbidden).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "organization not owned by the member",
+			body: `{"user_id": "` + memberID.String() + `", "org_id": "` + orgID.String() + `"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().TransferApp(mock.Anything, mock.Anything, trackingID, memberID, orgID).Return(nil, ErrTransferOrganization).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "name taken in the organization",
+			body: `{"user_id": "` + memberID.String() + `", "org_id": "` + orgID.String() + `"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().TransferApp(mock.Anything, mock.Anything, trackingID, memberID, orgID).Return(nil, ErrAppExists).Once()
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name: "app transferred",
+			body: `{"user_id": "` + memberID.String() + `"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().TransferApp(mock.Anything, mock.Anything, trackingID, memberID, uuid.Nil).Return(&types.App{TrackingID: trackingID, Role: "admin"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+trackingID.String()+"/transfer", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+			WrapHandler(suite.handler.TransferApp)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateInvitation() {
+	trackingID := uuid.New()
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid email",
+			body:       `{"email": "ada", "role": "viewer"}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid role",
+			body: `{"email": "ada@example.com", "role": "owner"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateInvitation(mock.Anything, mock.Anything).Return(nil, ErrInvalidRole).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "role not allowed to",
+			body: `{"email": "ada@example.com", "role": "viewer"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateInvitation(mock.Anything, mock.Anything).Return(nil, ErrForbidden).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "invitation created",
+			body: `{"email": "ada@example.com", "role": "editor"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateInvitation(mock.Anything, mock.MatchedBy(func(payload types.InvitationPayload) bool {
+					return payload.TrackingID == trackingID && payload.Email == "ada@example.com" && payload.Role == "editor"
+				})).Return(&types.Invitation{Email: "ada@example.com", Role: "editor"}, nil).Once()
+			},
+			statusCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/apps/"+trackingID.String()+"/invitations", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "trackingID", Value: trackingID.String()}}
+
+			WrapHandler(suite.handler.CreateInvitation)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestAcceptInvitation() {
+	invitationID, trackingID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name         string
+		invitationID string
+		mockSetup    func()
+		statusCode   int
+	}{
+		{
+			name:         "invalid invitationID",
+			invitationID: "not-a-uuid",
+			mockSetup:    func() {},
+			statusCode:   http.StatusBadRequest,
+		},
+		{
+			name:         "invitation not found",
+			invitationID: invitationID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().AcceptInvitation(mock.Anything, mock.Anything, invitationID).Return(nil, ErrInvitationNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:         "invitation accepted",
+			invitationID: invitationID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().AcceptInvitation(mock.Anything, mock.Anything, invitationID).Return(&types.App{TrackingID: trackingID, Role: "viewer"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/invitations/"+tc.invitationID+"/accept", nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "invitationID", Value: tc.invitationID}}
+
+			WrapHandler(suite.handler.AcceptInvitation)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusOK {
+				suite.Contains(rr.Body.String(), `"role":"viewer"`)
+			}
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetAnalyticsEndpoints() {
+	type analyticsTest struct {
+		name       string
+		startDate  string
+		endDate    string
+		filters    string
+		query      string
+		mockSetup  func()
+		statusCode int
+	}
+
+	testEndpoint := func(endpoint string, funcName string, handler func(*gin.Context) types.APIResponse, mockResult interface{}) {
+		testCases := []analyticsTest{
+			{
+				name:       "trackingID not found in context",
+				startDate:  "",
+				endDate:    "",
+				mockSetup:  func() {},
+				statusCode: http.StatusUnauthorized,
+			},
+			{
+				name:       "only start date provided",
+				startDate:  "2025-03-01",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "only end date provided",
+				endDate:    "2025-03-06",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "invalid date format",
+				startDate:  "2025/03/01",
+				endDate:    "2025/03/06",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "start date after end date",
+				startDate:  "2025-03-06",
+				endDate:    "2025-03-01",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "same dates cover the whole day",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-01",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.EndDate.Time.Sub(payload.StartDate.Time) == 24*time.Hour && payload.BucketSize == "1 hour"
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "same datetimes",
+				startDate:  "2025-03-01T10:00:00Z",
+				endDate:    "2025-03-01T10:00:00Z",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "datetime range",
+				startDate: "2025-03-01T10:00:00Z",
+				endDate:   "2025-03-01T12:30:00-01:00",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.StartDate.Time.Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)) &&
+							payload.EndDate.Time.Equal(time.Date(2025, 3, 1, 13, 30, 0, 0, time.UTC))
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "unsupported period",
+				query:      "period=fortnight",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "period with dates",
+				startDate:  "2025-03-01",
+				endDate:    "2025-03-06",
+				query:      "period=7d",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:  "period preset",
+				query: "period=7d",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.StartDate.Valid && payload.EndDate.Valid && payload.BucketSize == "1 day"
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:  "all time period",
+				query: "period=all",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.StartDate.Time.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "invalid filters JSON",
+				filters:    `{"dimension":"country"}`,
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "unsupported filter dimension",
+				filters:    `[{"dimension":"city","operator":"is","values":["Lagos"]}]`,
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "unsupported filter operator",
+				filters:    `[{"dimension":"country","operator":"like","values":["Nigeria"]}]`,
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "filter without values",
+				filters:    `[{"dimension":"country","operator":"is","values":[]}]`,
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:    "invalid filter regex",
+				filters: `[{"dimension":"page","operator":"regex","values":["(unclosed"]}]`,
+				mockSetup: func() {
+					suite.mockService.EXPECT().ValidateRegexFilters(mock.Anything, mock.Anything).Return(fmt.Errorf("%w for \"page\" filter", ErrInvalidRegex)).Once()
+				},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				// Go's regexp doesn't take word boundaries like this, but Postgres does
+				name:    "filter regex Postgres compiles",
+				filters: `[{"dimension":"page","operator":"regex","values":["\\mblog\\M"]}]`,
+				mockSetup: func() {
+					suite.mockService.EXPECT().ValidateRegexFilters(mock.Anything, mock.Anything).Return(nil).Once()
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return len(payload.Filters) == 1 && payload.Filters[0].Values[0] == `\mblog\M`
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:    "successful filtered stats retrieval",
+				filters: `[{"dimension":"referrer","operator":"contains","values":["twitter"]},{"dimension":"prop:plan","operator":"any_of","values":["pro","team"]}]`,
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return len(payload.Filters) == 2 && payload.Filters[1].Dimension == "prop:plan"
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "invalid timezone",
+				query:      "tz=Mars/Olympus_Mons",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "server local timezone",
+				query:      "tz=Local",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "dates resolved in the requested timezone",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				query:     "tz=Africa/Lagos",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						// midnight in Lagos is 23:00 UTC the day before
+						return payload.Timezone == "Africa/Lagos" &&
+							payload.StartDate.Time.Equal(time.Date(2025, 2, 28, 23, 0, 0, 0, time.UTC)) &&
+							payload.EndDate.Time.Equal(time.Date(2025, 3, 6, 23, 0, 0, 0, time.UTC))
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "unsupported interval",
+				query:      "interval=second",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "interval too small for range",
+				startDate:  "2025-01-01",
+				endDate:    "2025-03-06",
+				query:      "interval=minute",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "successful stats retrieval with interval",
+				startDate: "2025-01-01",
+				endDate:   "2025-03-06",
+				query:     "interval=week",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.BucketSize == "1 week"
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "unsupported compare option",
+				query:      "compare=last_week",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "custom compare without dates",
+				startDate:  "2025-03-01",
+				endDate:    "2025-03-06",
+				query:      "compare=custom&compareStartDate=2025-02-01",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:       "compare dates without custom compare",
+				query:      "compare=previous_period&compareStartDate=2025-02-01&compareEndDate=2025-02-06",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "successful custom comparison",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				query:     "compare=custom&compareStartDate=2025-02-01&compareEndDate=2025-02-06",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.Compare == "custom" &&
+							payload.CompareStartDate.Time.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) &&
+							payload.CompareEndDate.Time.Equal(time.Date(2025, 2, 7, 0, 0, 0, 0, time.UTC))
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:      "successful year over year comparison",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				query:     "compare=year_over_year",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return payload.Compare == "year_over_year" && !payload.CompareStartDate.Valid
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:       "invalid imported toggle",
+				startDate:  "2025-03-01",
+				endDate:    "2025-03-06",
+				query:      "imported=maybe",
+				mockSetup:  func() {},
+				statusCode: http.StatusBadRequest,
+			},
+			{
+				name:      "imported data excluded",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				filters:   `[{"dimension":"country","operator":"is","values":["Nigeria"]}]`,
+				query:     "imported=false",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, matchRequestPayload(func(payload types.RequestPayload) bool {
+						return len(payload.Filters) == 2 && payload.Filters[1].Dimension == "imported" &&
+							payload.Filters[1].Operator == "is" && slices.Equal(payload.Filters[1].Values, []string{"false"})
+					})).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+			{
+				name:      "failed to fetch" + endpoint,
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+				},
+				statusCode: http.StatusInternalServerError,
+			},
+			{
+				name:      "successful stats retrieval",
+				startDate: "2025-03-01",
+				endDate:   "2025-03-06",
+				mockSetup: func() {
+					suite.mockService.On(funcName, mock.Anything, mock.Anything).Return(mockResult, nil).Once()
+				},
+				statusCode: http.StatusOK,
+			},
+		}
+
+		for _, tc := range testCases {
+			suite.Run(endpoint+" - "+tc.name, func() {
+				tc.mockSetup()
+
+				rr := httptest.NewRecorder()
+				url := "/analytics/" + endpoint
+
+				// Add query parameters if provided
+				if tc.startDate != "" || tc.endDate != "" {
+					url += "?"
+					if tc.startDate != "" {
+						url += "startDate=" + tc.startDate
+					}
+					if tc.endDate != "" {
+						if tc.startDate != "" {
+							url += "&"
+						}
+						url += "endDate=" + tc.endDate
+					}
+				}
+
+				if tc.filters != "" {
+					if strings.Contains(url, "?") {
+						url += "&"
+					} else {
+						url += "?"
+					}
+					url += "filters=" + neturl.QueryEscape(tc.filters)
+				}
+
+				if tc.query != "" {
+					if strings.Contains(url, "?") {
+						url += "&"
+					} else {
+						url += "?"
+					}
+					url += tc.query
+				}
+
+				req := httptest.NewRequest(http.MethodGet, url, nil)
+				req.Header.Add("Content-Type", "application/json")
+
+				ctx := createGinContext(req, rr)
+				if tc.statusCode != http.StatusUnauthorized {
+					ctx.Set("trackingID", uuid.New())
+					ctx.Set("createdAt", time.Date(2024, 6, 15, 9, 0, 0, 0, time.UTC))
+				}
+
+				handlerFunc := WrapHandler(handler)
+				handlerFunc(ctx)
+
+				suite.Equal(tc.statusCode, rr.Code)
+				suite.mockService.AssertExpectations(suite.T())
+			})
+		}
+	}
+
+	// Test all analytics endpoints
+	testEndpoint("referrals", "GetReferrals", suite.handler.GetReferrals, &types.Breakdown[types.ReferralStats]{})
+	testEndpoint("pages", "GetPages", suite.handler.GetPages, &types.Breakdown[types.PageStats]{})
+	testEndpoint("browsers", "GetBrowsers", suite.handler.GetBrowsers, &types.Breakdown[types.BrowserStats]{})
+	testEndpoint("countries", "GetCountries", suite.handler.GetCountries, &types.Breakdown[types.CountryStats]{})
+	testEndpoint("devices", "GetDevices", suite.handler.GetDevices, &types.Breakdown[types.DeviceStats]{})
+	testEndpoint("os", "GetOS", suite.handler.GetOS, &types.Breakdown[types.OSStats]{})
+	testEndpoint("visitors", "GetVisitors", suite.handler.GetVisitors, []types.VisitorStats{})
+	testEndpoint("pageviews", "GetPageViews", suite.handler.GetPageViews, []types.PageViewStats{})
+	testEndpoint("overview", "GetOverview", suite.handler.GetOverview, &types.OverviewStats{})
+	testEndpoint("events", "GetEventTypes", suite.handler.GetEventTypes, []types.EventTypeStats{})
+}
+
+func (suite *HandlerSuite) TestGetBreakdownPaging() {
+	testCases := []struct {
+		name       string
+		query      string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "limit too small",
+			query:      "limit=0",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			query:      "limit=1001",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid page",
+			query:      "page=0",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported sort",
+			query:      "sort=bounce_rate:desc",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid other flag",
+			query:      "other=maybe",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported precision",
+			query:      "precision=high",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "defaults",
+			query: "",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(payload types.BreakdownPayload) bool {
+					return payload.Limit == defaultBreakdownLimit && payload.Page == 1 && payload.Sort == "visitors:desc" && !payload.Other &&
+						payload.Precision == precisionExact
+				})).Return(&types.Breakdown[types.ReferralStats]{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "approximate visitors",
+			query: "precision=approx",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(payload types.BreakdownPayload) bool {
+					return payload.Precision == precisionApprox
+				})).Return(&types.Breakdown[types.ReferralStats]{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "paging, sort and other row",
+			query: "limit=5&page=3&sort=name:asc&other=true",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(payload types.BreakdownPayload) bool {
+					return payload.Limit == 5 && payload.Page == 3 && payload.Sort == "name:asc" && payload.Other
+				})).Return(&types.Breakdown[types.ReferralStats]{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/analytics/referrals?"+tc.query, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("trackingID", uuid.New())
+
+			handlerFunc := WrapHandler(suite.handler.GetReferrals)
+			handlerFunc(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestExportStats() {
+	trackingID := uuid.New()
+	emptyBreakdowns := func() {
+		suite.mockService.EXPECT().GetReferrals(mock.Anything, mock.Anything).Return(&types.Breakdown[types.ReferralStats]{}, nil).Once()
+		suite.mockService.EXPECT().GetCountries(mock.Anything, mock.Anything).Return(&types.Breakdown[types.CountryStats]{}, nil).Once()
+		suite.mockService.EXPECT().GetBrowsers(mock.Anything, mock.Anything).Return(&types.Breakdown[types.BrowserStats]{}, nil).Once()
+		suite.mockService.EXPECT().GetDevices(mock.Anything, mock.Anything).Return(&types.Breakdown[types.DeviceStats]{}, nil).Once()
+		suite.mockService.EXPECT().GetOS(mock.Anything, mock.Anything).Return(&types.Breakdown[types.OSStats]{}, nil).Once()
+	}
+	testCases := []struct {
+		name       string
+		query      string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid date range",
+			query:      "?startDate=2025-03-31&endDate=2025-03-01",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "failed to build a report",
+			query: "?startDate=2025-03-01&endDate=2025-03-31",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "successful export",
+			query: "?startDate=2025-03-01&endDate=2025-03-02&interval=day&compare=previous_period",
+			mockSetup: func() {
+				withoutComparison := matchRequestPayload(func(payload types.RequestPayload) bool {
+					return payload.TrackingID == trackingID && payload.Compare == "" && !payload.CompareStartDate.Valid
+				})
+				suite.mockService.EXPECT().GetOverview(mock.Anything, withoutComparison).Return(&types.OverviewStats{
+					Visitors: 3, PageViews: 5, Visits: 4, ViewsPerVisit: 1.25, BounceRate: 50, VisitDuration: 30.5, Events: 6,
+				}, nil).Once()
+				suite.mockService.EXPECT().GetVisitors(mock.Anything, withoutComparison).Return([]types.VisitorStats{
+					{Time: "2025-03-01", Visitors: 2}, {Time: "2025-03-02", Visitors: 1},
+				}, nil).Once()
+				suite.mockService.EXPECT().GetPageViews(mock.Anything, withoutComparison).Return([]types.PageViewStats{
+					{Time: "2025-03-01", Views: 4}, {Time: "2025-03-02", Views: 1},
+				}, nil).Once()
+				suite.mockService.EXPECT().GetEventTypes(mock.Anything, withoutComparison).Return([]types.EventTypeStats{
+					{EventType: "pageview", Visitors: 3, Events: 5}, {EventType: "signup", Visitors: 1, Events: 1},
+				}, nil).Once()
+				// a full first page is followed by the next one
+				firstPage := make([]types.PageStats, maxBreakdownLimit)
+				for i := range firstPage {
+					firstPage[i] = types.PageStats{Path: fmt.Sprintf("/%d", i), BreakdownStats: types.BreakdownStats{Visitors: 1}}
+				}
+				suite.mockService.EXPECT().GetPages(mock.Anything, mock.MatchedBy(func(payload types.BreakdownPayload) bool {
+					return payload.Page == 1 && payload.Limit == maxBreakdownLimit
+				})).Return(&types.Breakdown[types.PageStats]{Results: firstPage, Meta: types.BreakdownMeta{Total: maxBreakdownLimit + 1}}, nil).Once()
+				suite.mockService.EXPECT().GetPages(mock.Anything, mock.MatchedBy(func(payload types.BreakdownPayload) bool {
+					return payload.Page == 2
+				})).Return(&types.Breakdown[types.PageStats]{
+					Results: []types.PageStats{{Path: "/last", BreakdownStats: types.BreakdownStats{Visitors: 1, Pageviews: 2, Percentage: 33.3}}},
+					Meta:    types.BreakdownMeta{Total: maxBreakdownLimit + 1},
+				}, nil).Once()
+				emptyBreakdowns()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/analytics/export"+tc.query, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("trackingID", trackingID)
+			ctx.Set("timezone", "UTC")
+
+			suite.handler.ExportStats(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+			if tc.statusCode != http.StatusOK {
+				suite.Contains(rr.Header().Get("Content-Type"), "application/json")
+				return
+			}
+
+			suite.Equal("application/zip", rr.Header().Get("Content-Type"))
+			suite.Equal(`attachment; filename="stats-`+trackingID.String()+`-2025-03-01-2025-03-02.zip"`, rr.Header().Get("Content-Disposition"))
+
+			archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+			suite.Require().NoError(err)
+			files := map[string][][]string{}
+			var names []string
+			for _, file := range archive.File {
+				reader, err := file.Open()
+				suite.Require().NoError(err)
+				records, err := csv.NewReader(reader).ReadAll()
+				suite.Require().NoError(err)
+				reader.Close()
+				names = append(names, file.Name)
+				files[file.Name] = records
+			}
+
+			suite.Equal([]string{"overview.csv", "visitors.csv", "events.csv", "pages.csv", "referrers.csv", "countries.csv", "browsers.csv", "devices.csv", "operating_systems.csv"}, names)
+			suite.Equal([][]string{
+				{"metric", "value"},
+				{"visitors", "3"},
+				{"pageviews", "5"},
+				{"visits", "4"},
+				{"views_per_visit", "1.25"},
+				{"bounce_rate", "50"},
+				{"visit_duration", "30.5"},
+				{"events", "6"},
+			}, files["overview.csv"])
+			suite.Equal([][]string{{"time", "visitors", "pageviews"}, {"2025-03-01", "2", "4"}, {"2025-03-02", "1", "1"}}, files["visitors.csv"])
+			suite.Equal([][]string{{"event_type", "visitors", "events"}, {"pageview", "3", "5"}, {"signup", "1", "1"}}, files["events.csv"])
+			suite.Len(files["pages.csv"], maxBreakdownLimit+2)
+			suite.Equal([]string{"path", "visitors", "pageviews", "events", "percentage"}, files["pages.csv"][0])
+			suite.Equal([]string{"/last", "1", "2", "0", "33.3"}, files["pages.csv"][maxBreakdownLimit+1])
+			suite.Equal([][]string{{"operating_system", "visitors", "pageviews", "events", "percentage"}}, files["operating_systems.csv"])
+		})
+	}
+}
+
+// matchRequestPayload matches the request payload of both plain and breakdown stats calls.
+func matchRequestPayload(fn func(types.RequestPayload) bool) interface{} {
+	return mock.MatchedBy(func(payload interface{}) bool {
+		switch payload := payload.(type) {
+		case types.RequestPayload:
+			return fn(payload)
+		case types.BreakdownPayload:
+			return fn(payload.RequestPayload)
+		}
+		return false
+	})
+}
+
+func (suite *HandlerSuite) TestGetRetention() {
+	testCases := []struct {
+		name       string
+		query      string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "trackingID not found in context",
+			mockSetup:  func() {},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid cohort period",
+			query:      "?cohort=day",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "retention tracking disabled",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetRetention(mock.Anything, mock.Anything).Return(nil, ErrRetentionDisabled).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "failed to fetch retention",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetRetention(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "successful retention retrieval",
+			query: "?cohort=month&startDate=2025-01-01&endDate=2025-06-30",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetRetention(mock.Anything, mock.MatchedBy(func(payload types.RetentionPayload) bool {
+					return payload.Period == "month" && payload.StartDate.Valid
+				})).Return([]types.RetentionCohort{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, "/analytics/retention"+tc.query, nil)
+			req.Header.Add("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			if tc.statusCode != http.StatusUnauthorized {
+				ctx.Set("trackingID", uuid.New())
+			}
+
+			handlerFunc := WrapHandler(suite.handler.GetRetention)
+			handlerFunc(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestGetUserFlow() {
+	testCases := []struct {
+		name       string
+		query      string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "trackingID not found in context",
+			query:      "?page=/pricing",
+			mockSetup:  func() {},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing starting point",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "both page and event provided",
+			query:      "?page=/pricing&event=signup",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid direction",
+			query:      "?page=/pricing&direction=sideways",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "too many steps",
+			query:      "?page=/pricing&steps=10",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "failed to fetch user flow",
+			query: "?event=signup",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetUserFlow(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "successful user flow retrieval",
+			query: "?page=/pricing&direction=previous&steps=2",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetUserFlow(mock.Anything, mock.MatchedBy(func(payload types.FlowPayload) bool {
+					return payload.Step == "/pricing" && payload.Direction == "previous" && payload.Steps == 2
+				})).Return(&types.FlowStats{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, "/analytics/flow"+tc.query, nil)
+			req.Header.Add("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			if tc.statusCode != http.StatusUnauthorized {
+				ctx.Set("trackingID", uuid.New())
+			}
+
+			handlerFunc := WrapHandler(suite.handler.GetUserFlow)
+			handlerFunc(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCacheHeaders() {
+	request := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/analytics/overview"+query, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		ctx := createGinContext(req, rr)
+		ctx.Set("trackingID", uuid.New())
+		ctx.Set("createdAt", time.Now().AddDate(-1, 0, 0))
+		WrapHandler(suite.handler.GetOverview)(ctx)
+		return rr
+	}
+
+	suite.Run("closed range is revalidated", func() {
+		suite.mockService.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(&types.OverviewStats{Visitors: 3}, nil).Times(2)
+
+		rr := request("?startDate=2024-01-01&endDate=2024-01-31", "")
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Equal("private, no-cache", rr.Header().Get("Cache-Control"))
+		etag := rr.Header().Get("ETag")
+		suite.NotEmpty(etag)
+
+		rr = request("?startDate=2024-01-01&endDate=2024-01-31", `"stale", W/`+etag)
+		suite.Equal(http.StatusNotModified, rr.Code)
+		suite.Empty(rr.Body.Bytes())
+		suite.Equal(etag, rr.Header().Get("ETag"))
+	})
+
+	suite.Run("live range is reused for a minute", func() {
+		suite.mockService.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(&types.OverviewStats{}, nil).Once()
+
+		rr := request("?period=7d", `"stale"`)
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Equal("private, max-age=60", rr.Header().Get("Cache-Control"))
+		suite.NotEmpty(rr.Header().Get("ETag"))
+	})
+
+	suite.Run("errors carry no cache headers", func() {
+		suite.mockService.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+
+		rr := request("?period=7d", "")
+		suite.Equal(http.StatusInternalServerError, rr.Code)
+		suite.Empty(rr.Header().Get("Cache-Control"))
+		suite.Empty(rr.Header().Get("ETag"))
+	})
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *HandlerSuite) TestGetRealtime() {
+	testCases := []struct {
+		name       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "trackingID not found in context",
+			mockSetup:  func() {},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "failed to fetch realtime stats",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetRealtime(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "successful realtime retrieval",
+			mockSetup: func() {
+				suite.mockService.EXPECT().GetRealtime(mock.Anything, mock.Anything).Return(&types.RealtimeStats{CurrentVisitors: 3}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/analytics/realtime", nil)
+
+			ctx := createGinContext(req, rr)
+			if tc.statusCode != http.StatusUnauthorized {
+				ctx.Set("trackingID", uuid.New())
+			}
+
+			handlerFunc := WrapHandler(suite.handler.GetRealtime)
+			handlerFunc(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestStreamEvents() {
+	suite.Run("trackingID not found in context", func() {
+		rr := httptest.NewRecorder()
+		ctx := createGinContext(httptest.NewRequest(http.MethodGet, "/analytics/realtime/stream", nil), rr)
+
+		suite.handler.StreamEvents(ctx)
+		suite.Equal(http.StatusUnauthorized, rr.Code)
+	})
+
+	suite.Run("failed to subscribe", func() {
+		suite.mockService.EXPECT().SubscribeEvents(mock.Anything, mock.Anything).Return(nil, nil, errors.New("database error")).Once()
+
+		rr := httptest.NewRecorder()
+		ctx := createGinContext(httptest.NewRequest(http.MethodGet, "/analytics/realtime/stream", nil), rr)
+		ctx.Set("trackingID", uuid.New())
+
+		suite.handler.StreamEvents(ctx)
+		suite.Equal(http.StatusInternalServerError, rr.Code)
+		suite.mockService.AssertExpectations(suite.T())
+	})
+
+	suite.Run("failed to fetch realtime stats", func() {
+		unsubscribed := false
+		suite.mockService.EXPECT().SubscribeEvents(mock.Anything, mock.Anything).Return(make(chan types.LiveUpdate), func() { unsubscribed = true }, nil).Once()
+		suite.mockService.EXPECT().GetRealtime(mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Once()
+
+		rr := httptest.NewRecorder()
+		ctx := createGinContext(httptest.NewRequest(http.MethodGet, "/analytics/realtime/stream", nil), rr)
+		ctx.Set("trackingID", uuid.New())
+
+		suite.handler.StreamEvents(ctx)
+		suite.Equal(http.StatusInternalServerError, rr.Code)
+		suite.True(unsubscribed)
+		suite.mockService.AssertExpectations(suite.T())
+	})
+
+	suite.Run("snapshot followed by live events", func() {
+		updates := make(chan types.LiveUpdate, 1)
+		updates <- types.LiveUpdate{Event: types.LiveEvent{Type: "pageview", Path: "/pricing"}, CurrentVisitors: 4}
+		close(updates)
+
+		suite.mockService.EXPECT().SubscribeEvents(mock.Anything, mock.Anything).Return(updates, func() {}, nil).Once()
+		suite.mockService.EXPECT().GetRealtime(mock.Anything, mock.Anything).Return(&types.RealtimeStats{CurrentVisitors: 3}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		ctx := createGinContext(httptest.NewRequest(http.MethodGet, "/analytics/realtime/stream", nil), rr)
+		ctx.Set("trackingID", uuid.New())
+
+		suite.handler.StreamEvents(ctx)
+
+		body := rr.Body.String()
+		suite.Equal(http.StatusOK, rr.Code)
+		suite.Contains(rr.Header().Get("Content-Type"), "text/event-stream")
+		suite.Contains(body, "event:realtime\ndata:{\"current_visitors\":3")
+		suite.Contains(body, "event:event\ndata:{\"event\":{\"type\":\"pageview\",\"path\":\"/pricing\"")
+		suite.Less(strings.Index(body, "event:realtime"), strings.Index(body, "event:event"))
+		suite.mockService.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *HandlerSuite) TestCreatePeriodPayload() {
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	suite.Require().NoError(err)
+
+	now := time.Date(2025, 3, 10, 15, 20, 0, 0, lagos)
+	createdAt := time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, lagos)
+	}
+
+	testCases := []struct {
+		period     string
+		startDate  time.Time
+		endDate    time.Time
+		bucketSize string
+	}{
+		{period: "realtime", startDate: now.Add(-30 * time.Minute), endDate: now, bucketSize: "1 minute"},
+		{period: "today", startDate: date(2025, 3, 10), endDate: now, bucketSize: "1 hour"},
+		{period: "yesterday", startDate: date(2025, 3, 9), endDate: date(2025, 3, 10), bucketSize: "1 hour"},
+		{period: "7d", startDate: date(2025, 3, 4), endDate: now, bucketSize: "1 day"},
+		{period: "30d", startDate: date(2025, 2, 9), endDate: now, bucketSize: "1 day"},
+		{period: "month_to_date", startDate: date(2025, 3, 1), endDate: now, bucketSize: "1 day"},
+		{period: "last_month", startDate: date(2025, 2, 1), endDate: date(2025, 3, 1), bucketSize: "1 day"},
+		{period: "12mo", startDate: date(2024, 4, 1), endDate: now, bucketSize: "1 month"},
+		{period: "year_to_date", startDate: date(2025, 1, 1), endDate: now, bucketSize: "1 month"},
+		{period: "all", startDate: date(2024, 11, 1), endDate: now, bucketSize: "1 month"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.period, func() {
+			payload, err := createPeriodPayload(uuid.New(), tc.period, now, createdAt, lagos)
+			suite.NoError(err)
+			suite.True(tc.startDate.Equal(payload.StartDate.Time), "start %s, got %s", tc.startDate, payload.StartDate.Time)
+			suite.True(tc.endDate.Equal(payload.EndDate.Time), "end %s, got %s", tc.endDate, payload.EndDate.Time)
+			suite.Equal(tc.bucketSize, payload.BucketSize)
+			suite.Equal("Africa/Lagos", payload.Timezone)
+		})
+	}
+}
+
+func TestHandlerSuite(t *testing.T) {
+	suite.Run(t, new(HandlerSuite))
+}
+
+func (suite *HandlerSuite) TestSetOrganizationMember() {
+	orgID := uuid.New()
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid email",
+			body:       `{"email": "ada", "role": "viewer"}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "only owners make owners",
+			body: `{"email": "ada@example.com", "role": "owner"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().SetOrganizationMember(mock.Anything, mock.Anything).Return(nil, ErrOrganizationForbidden).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "user never signed in",
+			body: `{"email": "ada@example.com", "role": "viewer"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().SetOrganizationMember(mock.Anything, mock.Anything).Return(nil, ErrUserNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "last owner steps down",
+			body: `{"email": "ada@example.com", "role": "admin"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().SetOrganizationMember(mock.Anything, mock.Anything).Return(nil, ErrLastOwner).Once()
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name: "member saved",
+			body: `{"email": "ada@example.com", "role": "editor"}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().SetOrganizationMember(mock.Anything, mock.MatchedBy(func(data types.OrganizationMemberPayload) bool {
+					return data.OrgID == orgID && data.Email == "ada@example.com" && data.Role == "editor"
+				})).Return(&types.Member{Email: "ada@example.com", Role: "editor"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/organizations/"+orgID.String()+"/members", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "orgID", Value: orgID.String()}}
+
+			WrapHandler(suite.handler.SetOrganizationMember)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestRemoveOrganizationMember() {
+	orgID, memberID := uuid.New(), uuid.New()
+	testCases := []struct {
+		name       string
+		memberID   string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "invalid userID",
+			memberID:   "not-a-uuid",
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "not a member of the organization",
+			memberID: memberID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().RemoveOrganizationMember(mock.Anything, mock.Anything, orgID, memberID).Return(ErrOrganizationNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:     "last owner leaves",
+			memberID: memberID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().RemoveOrganizationMember(mock.Anything, mock.Anything, orgID, memberID).Return(ErrLastOwner).Once()
+			},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:     "member removed",
+			memberID: memberID.String(),
+			mockSetup: func() {
+				suite.mockService.EXPECT().RemoveOrganizationMember(mock.Anything, mock.Anything, orgID, memberID).Return(nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/organizations/"+orgID.String()+"/members/"+tc.memberID, nil)
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+			ctx.Params = gin.Params{{Key: "orgID", Value: orgID.String()}, {Key: "userID", Value: tc.memberID}}
+
+			WrapHandler(suite.handler.RemoveOrganizationMember)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestAPITokenMiddleware() {
+	userID, trackingID := uuid.New(), uuid.New()
+	app := &types.App{TrackingID: trackingID, Timezone: "UTC"}
+	token := func(scopes []string, trackingIDs ...uuid.UUID) *types.APIToken {
+		return &types.APIToken{UserID: userID, Scopes: scopes, TrackingIDs: trackingIDs}
+	}
+
+	router := gin.New()
+	analytics := router.Group("analytics")
+	analytics.Use(JWTMiddleware(suite.mockService), ScopeMiddleware(scopeReadStats), AppAccessMiddleware(suite.mockService))
+	registerAnalyticsRoutes(analytics, suite.handler)
+	tokens := router.Group("tokens")
+	tokens.Use(JWTMiddleware(suite.mockService), ScopeMiddleware())
+	tokens.GET("/", WrapHandler(suite.handler.GetAPITokens))
+
+	realtime := "/analytics/realtime?trackingID=" + trackingID.String()
+	testCases := []struct {
+		name       string
+		path       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name: "revoked token",
+			path: realtime,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAPIToken(mock.Anything, "mk_secret", mock.Anything).Return(nil, ErrInvalidAPIToken).Once()
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "token without the scope",
+			path: realtime,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAPIToken(mock.Anything, "mk_secret", mock.Anything).Return(token([]string{scopeIngest}), nil).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "token limited to other apps",
+			path: realtime,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAPIToken(mock.Anything, "mk_secret", mock.Anything).Return(token([]string{scopeReadStats}, uuid.New()), nil).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "stats of an app of the token",
+			path: realtime,
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAPIToken(mock.Anything, "mk_secret", mock.Anything).Return(token([]string{scopeReadStats}, trackingID), nil).Once()
+				suite.mockService.EXPECT().ValidateAppAccess(mock.Anything, userID, trackingID).Return(app, nil).Once()
+				suite.mockService.EXPECT().GetRealtime(mock.Anything, trackingID).Return(&types.RealtimeStats{CurrentVisitors: 3}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "tokens can't manage tokens",
+			path: "/tokens/",
+			mockSetup: func() {
+				suite.mockService.EXPECT().ValidateAPIToken(mock.Anything, "mk_secret", mock.Anything).Return(token([]string{scopeManageApps}), nil).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer mk_secret")
+
+			router.ServeHTTP(rr, req)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *HandlerSuite) TestCreateAPIToken() {
+	testCases := []struct {
+		name       string
+		body       string
+		mockSetup  func()
+		statusCode int
+	}{
+		{
+			name:       "missing scopes",
+			body:       `{"name": "metabase", "scopes": []}`,
+			mockSetup:  func() {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported scope",
+			body: `{"name": "metabase", "scopes": ["admin"]}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateAPIToken(mock.Anything, mock.Anything).Return(nil, ErrUnsupportedScope).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "app of someone else",
+			body: `{"name": "metabase", "scopes": ["read-stats"], "tracking_ids": ["` + uuid.NewString() + `"]}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateAPIToken(mock.Anything, mock.Anything).Return(nil, ErrAppNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "token created",
+			body: `{"name": "metabase", "scopes": ["read-stats"]}`,
+			mockSetup: func() {
+				suite.mockService.EXPECT().CreateAPIToken(mock.Anything, mock.MatchedBy(func(data types.APITokenPayload) bool {
+					return data.Name == "metabase" && slices.Equal(data.Scopes, []string{"read-stats"})
+				})).Return(&types.APIToken{Name: "metabase", Token: "mk_secret"}, nil).Once()
+			},
+			statusCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := createGinContext(req, rr)
+			ctx.Set("userID", uuid.New())
+
+			WrapHandler(suite.handler.CreateAPIToken)(ctx)
+
+			suite.Equal(tc.statusCode, rr.Code)
+			suite.mockService.AssertExpectations(suite.T())
+		})
+	}
 }
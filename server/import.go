@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oschwald/geoip2-golang"
+	"go.uber.org/zap"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/importer"
+)
+
+// Import runs the import command, which imports files into an app like
+// POST /apps/:trackingID/imports does, but right away and in place. It is
+// meant for access logs too large to upload, of sites that never had a tracker.
+func (s *Server) Import(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	app := flags.String("app", "", "tracking ID of the app to import into")
+	source := flags.String("source", string(importer.SourceAccessLog), "tool the files come from: access_log, ga4, universal_analytics, plausible or umami")
+	logFormat := flags.String("log-format", "", "format of access log lines: combined (default), common, or an nginx log_format or Apache LogFormat")
+	hostname := flags.String("hostname", "", "hostname of the pages, for files that only have their paths")
+	websiteID := flags.String("website-id", "", "website to import from an Umami dump that holds several")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	trackingID, err := uuid.Parse(*app)
+	if err != nil {
+		return errors.New("-app must be the tracking ID of an app")
+	}
+	if flags.NArg() == 0 {
+		return errors.New("no files to import")
+	}
+	parsed, err := importer.ParseSource(*source)
+	if err != nil {
+		return err
+	}
+	if parsed == importer.SourceAccessLog {
+		if _, err := importer.ParseLogFormat(*logFormat); err != nil {
+			return err
+		}
+	}
+
+	geoDB, err := geoip2.Open("database/GeoLite2-City.mmdb")
+	if err != nil {
+		return fmt.Errorf("failed to open GeoIP2 database: %w", err)
+	}
+	defer geoDB.Close()
+
+	ctx := context.Background()
+	connPool, err := pgxpool.New(ctx, s.config.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	querier := database.New(connPool)
+	if _, err := querier.GetAppByTrackingID(ctx, trackingID); err != nil {
+		return fmt.Errorf("failed to find app: %w", err)
+	}
+
+	resultCache, err := s.newCache(ctx)
+	if err != nil {
+		return err
+	}
+
+	var eventsRetention *string
+	if s.config.EventsRetention != "" {
+		eventsRetention = &s.config.EventsRetention
+	}
+	service := NewAnalyticsService(querier, geoDB, nil, connPool)
+	job := newImportJob(querier, s.logger, eventsRetention, resultCache, service)
+
+	for _, name := range flags.Args() {
+		file, err := filepath.Abs(name)
+		if err != nil {
+			return err
+		}
+
+		// the import is leased to the command from the start, like the job
+		// claims the imports it runs, so servers leave it alone while it runs
+		imp, err := querier.CreateImport(ctx, database.CreateImportParams{
+			TrackingID: trackingID,
+			Source:     string(parsed),
+			Status:     importRunning,
+			File:       file,
+			Hostname:   optionalString(*hostname),
+			WebsiteID:  optionalString(*websiteID),
+			LogFormat:  optionalString(*logFormat),
+			FileHost:   optionalString(job.host),
+			LockedBy:   &job.holder,
+		})
+		if err != nil {
+			return err
+		}
+		if err := job.run(ctx, imp); err != nil {
+			return err
+		}
+
+		imp, err = querier.GetImport(ctx, database.GetImportParams{ID: imp.ID, TrackingID: trackingID})
+		if err != nil {
+			return err
+		}
+		if imp.Status == importFailed && imp.Error != nil {
+			return fmt.Errorf("failed to import %s: %s", name, *imp.Error)
+		}
+		s.logger.Info("file imported",
+			zap.String("file", file),
+			zap.String("importID", imp.ID.String()),
+			zap.Int64("imported", imp.ImportedEvents),
+			zap.Int64("skipped", imp.SkippedEvents),
+		)
+	}
+	return nil
+}
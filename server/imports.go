@@ -0,0 +1,632 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/importer"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var ErrImportNotFound = errors.New("import not found")
+
+// Statuses of an import. Uploaded imports start out pending until the job
+// claims them, the CLI runs its imports right away.
+const (
+	importPending   = "pending"
+	importRunning   = "running"
+	importCompleted = "completed"
+	importFailed    = "failed"
+)
+
+// importInterval is how often the import job looks for pending imports.
+const importInterval = 5 * time.Second
+
+// importHeartbeat is how often a running import renews its lease, and
+// importLease how long the lease holds without being renewed. Running imports
+// whose lease lapsed were interrupted, and are undone and queued again.
+const (
+	importHeartbeat = 15 * time.Second
+	importLease     = time.Minute
+)
+
+// errImportLeaseLost stops an import whose lease was taken over, after its
+// heartbeat went stale, so that only the new holder finishes or undoes it.
+var errImportLeaseLost = errors.New("import lease lost")
+
+// importsDir holds uploaded exports until their import finishes. It is local
+// to the host, so uploads are only run by the host that received them.
+func importsDir() string {
+	return filepath.Join(os.TempDir(), "minalytics-imports")
+}
+
+// importHost names the host whose filesystem holds the files it imports.
+func importHost() string {
+	host, _ := os.Hostname()
+	return host
+}
+
+// leaseHolder names this process as the holder of import and deletion leases.
+func leaseHolder() string {
+	return fmt.Sprintf("%s:%d", importHost(), os.Getpid())
+}
+
+// visitorSalt salts the visitor ids of an app's access log imports. Tracking
+// IDs are public, so the salt is keyed with the token secret: salted with the
+// tracking ID alone, the ids could be hashed back to the addresses they hide.
+func visitorSalt(trackingID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(viper.GetString("TokenSecret")))
+	mac.Write([]byte(trackingID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func newImport(row database.Import) types.Import {
+	imp := types.Import{
+		ID:             row.ID,
+		TrackingID:     row.TrackingID,
+		Source:         row.Source,
+		Status:         row.Status,
+		Progress:       row.Progress,
+		ImportedEvents: row.ImportedEvents,
+		SkippedEvents:  row.SkippedEvents,
+		StartDate:      nullTime(row.StartDate),
+		EndDate:        nullTime(row.EndDate),
+		CreatedAt:      row.CreatedAt.Time,
+		FinishedAt:     nullTime(row.FinishedAt),
+	}
+	if row.Error != nil {
+		imp.Error = *row.Error
+	}
+	return imp
+}
+
+// CreateImport stores the uploaded export and queues its import, which the
+// import job picks up in the background.
+func (s *analyticsService) CreateImport(ctx context.Context, data types.ImportPayload, file io.Reader) (*types.Import, error) {
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleEditor); err != nil {
+		return nil, err
+	}
+
+	source, err := importer.ParseSource(data.Source)
+	if err != nil {
+		return nil, err
+	}
+	if source == importer.SourceAccessLog {
+		if _, err := importer.ParseLogFormat(data.LogFormat); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(importsDir(), 0o700); err != nil {
+		return nil, err
+	}
+	upload, err := os.CreateTemp(importsDir(), data.TrackingID.String()+"-*")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(upload, file)
+	if closeErr := upload.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(upload.Name())
+		return nil, err
+	}
+
+	row, err := s.Querier.CreateImport(ctx, database.CreateImportParams{
+		TrackingID: data.TrackingID,
+		Source:     string(source),
+		Status:     importPending,
+		File:       upload.Name(),
+		Hostname:   optionalString(data.Hostname),
+		WebsiteID:  optionalString(data.WebsiteID),
+		LogFormat:  optionalString(data.LogFormat),
+		FileHost:   optionalString(importHost()),
+	})
+	if err != nil {
+		os.Remove(upload.Name())
+		return nil, err
+	}
+
+	imp := newImport(row)
+	return &imp, nil
+}
+
+func (s *analyticsService) GetImports(ctx context.Context, userID, trackingID uuid.UUID) ([]types.Import, error) {
+	if _, err := s.ValidateAppAccess(ctx, userID, trackingID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetImports(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make([]types.Import, 0, len(rows))
+	for _, row := range rows {
+		imports = append(imports, newImport(row))
+	}
+	return imports, nil
+}
+
+func (s *analyticsService) GetImport(ctx context.Context, userID, trackingID, importID uuid.UUID) (*types.Import, error) {
+	if _, err := s.ValidateAppAccess(ctx, userID, trackingID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.Querier.GetImport(ctx, database.GetImportParams{ID: importID, TrackingID: trackingID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	imp := newImport(row)
+	return &imp, nil
+}
+
+// importJob runs queued imports one at a time. Imported events and aggregates
+// are tagged with their import, so a failed import is undone by deleting
+// them. Events land in hours the sketches have already covered, so once an
+// import is done or undone those are rebuilt over its range.
+//
+// Several servers can run the job. An import is leased to the process running
+// it, which renews the lease until it is done, and only runs on the host
+// holding its file.
+//
+// Events older than the EVENTS_RETENTION horizon or the app's data retention
+// are skipped: the first would be dropped right away, and the sketches of the
+// second can't be rebuilt, since their raw events are gone.
+type importJob struct {
+	querier database.Querier
+	logger  *zap.Logger
+	// eventsRetention is the EVENTS_RETENTION horizon, if set.
+	eventsRetention *string
+	// cache, if set, has the entries of apps that got events invalidated.
+	cache cache.Cache
+	// resolver enriches the pageviews of access logs like tracked ones.
+	resolver importer.Resolver
+	// holder and host name this process and its host, see leaseHolder and
+	// importHost.
+	holder, host string
+	// heartbeat is how often leases are renewed, importHeartbeat but in tests.
+	heartbeat time.Duration
+}
+
+func newImportJob(querier database.Querier, logger *zap.Logger, eventsRetention *string, resultCache cache.Cache, resolver importer.Resolver) *importJob {
+	return &importJob{
+		querier:         querier,
+		logger:          logger,
+		eventsRetention: eventsRetention,
+		cache:           resultCache,
+		resolver:        resolver,
+		holder:          leaseHolder(),
+		host:            importHost(),
+		heartbeat:       importHeartbeat,
+	}
+}
+
+func (j *importJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(importInterval)
+	defer ticker.Stop()
+
+	for {
+		// other servers can stop at any time, not only this one
+		if err := j.resume(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("failed to resume interrupted imports", zap.Error(err))
+		}
+		if err := j.runPending(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("failed to run imports", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// resume takes over the running imports whose lease lapsed, as the process
+// running them stopped, and queues them again after undoing what they
+// imported so far.
+func (j *importJob) resume(ctx context.Context) error {
+	imports, err := j.querier.ClaimStaleImports(ctx, database.ClaimStaleImportsParams{
+		LockedBy:     j.holder,
+		LeaseSeconds: importLease.Seconds(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range imports {
+		if err := j.requeue(ctx, imp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *importJob) requeue(ctx context.Context, imp database.Import) error {
+	ctx, release := j.hold(ctx, imp)
+	defer release()
+
+	if err := j.rollback(ctx, imp); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return j.querier.RequeueImport(ctx, imp.ID)
+}
+
+// hold renews the lease of imp until release is called. The returned context
+// is cancelled with errImportLeaseLost once another process took the lease.
+func (j *importJob) hold(ctx context.Context, imp database.Import) (context.Context, func()) {
+	renew := func(ctx context.Context) (int64, error) {
+		return j.querier.HeartbeatImport(ctx, database.HeartbeatImportParams{ID: imp.ID, LockedBy: j.holder})
+	}
+	return holdLease(ctx, j.heartbeat, renew, errImportLeaseLost, func(err error) {
+		j.logger.Warn("failed to renew import lease", zap.String("importID", imp.ID.String()), zap.Error(err))
+	})
+}
+
+// holdLease calls renew every heartbeat until release is called, reporting
+// failed renewals to warn. renew returns how many leases it renewed, and the
+// returned context is cancelled with lost once it renewed none, as another
+// process took the lease over.
+func holdLease(ctx context.Context, heartbeat time.Duration, renew func(context.Context) (int64, error), lost error, warn func(error)) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			held, err := renew(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					warn(err)
+				}
+				continue
+			}
+			if held == 0 {
+				cancel(lost)
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			close(done)
+			cancel(nil)
+		})
+	}
+}
+
+func (j *importJob) runPending(ctx context.Context) error {
+	for {
+		imp, err := j.querier.ClaimPendingImport(ctx, database.ClaimPendingImportParams{
+			LockedBy: j.holder,
+			FileHost: j.host,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := j.run(ctx, imp); err != nil {
+			return err
+		}
+	}
+}
+
+// run runs imp, which this process holds the lease of.
+func (j *importJob) run(ctx context.Context, imp database.Import) error {
+	ctx, release := j.hold(ctx, imp)
+	defer release()
+
+	err := j.load(ctx, &imp)
+	if err == nil {
+		err = j.settle(ctx, imp)
+	}
+	if err == nil && imp.StartDate.Valid {
+		err = j.querier.SetImportedSince(ctx, database.SetImportedSinceParams{
+			ImportedSince: imp.StartDate,
+			TrackingID:    imp.TrackingID,
+		})
+	}
+	if ctx.Err() != nil {
+		// still running, so whoever takes the lease over resumes it
+		return context.Cause(ctx)
+	}
+
+	params := database.FinishImportParams{ID: imp.ID, Status: importCompleted}
+	if err != nil {
+		j.logger.Warn("import failed", zap.String("importID", imp.ID.String()), zap.Error(err))
+		if err := j.rollback(ctx, imp); err != nil {
+			return err
+		}
+		message := err.Error()
+		params.Status, params.Error = importFailed, &message
+	}
+	if err := j.querier.FinishImport(ctx, params); err != nil {
+		return err
+	}
+
+	// uploads are removed, the logs the CLI imports in place are kept
+	if filepath.Dir(imp.File) != importsDir() {
+		return nil
+	}
+	if err := os.Remove(imp.File); err != nil && !errors.Is(err, os.ErrNotExist) {
+		j.logger.Warn("failed to remove imported file", zap.String("file", imp.File), zap.Error(err))
+	}
+	return nil
+}
+
+// load reads the export of imp into events, or into imported aggregates for
+// aggregated sources, recording its progress as it goes.
+func (j *importJob) load(ctx context.Context, imp *database.Import) error {
+	app, err := j.querier.GetAppByTrackingID(ctx, imp.TrackingID)
+	if err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(app.Timezone)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(imp.File)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	opts := importer.Options{
+		Location:    location,
+		Resolver:    j.resolver,
+		VisitorSalt: visitorSalt(imp.TrackingID),
+	}
+	if imp.Hostname != nil {
+		opts.Hostname = *imp.Hostname
+	}
+	if imp.WebsiteID != nil {
+		opts.WebsiteID = *imp.WebsiteID
+	}
+	if imp.LogFormat != nil {
+		opts.LogFormat = *imp.LogFormat
+	}
+
+	now := time.Now()
+	if source := importer.Source(imp.Source); source.Aggregated() {
+		return importer.ReadAggregates(source, file, info.Size(), opts, func(aggregates []importer.Aggregate, progress float64) error {
+			return j.loadAggregates(ctx, imp, aggregates, progress, now)
+		})
+	}
+
+	horizon, err := j.querier.GetImportHorizon(ctx, database.GetImportHorizonParams{
+		EventsRetention: j.eventsRetention,
+		TrackingID:      imp.TrackingID,
+	})
+	if err != nil {
+		return err
+	}
+	return importer.Read(importer.Source(imp.Source), file, info.Size(), opts, func(events []importer.Event, progress float64) error {
+		params := database.InsertImportedEventsParams{ImportID: imp.ID, TrackingID: imp.TrackingID}
+		for _, event := range events {
+			if (horizon.Valid && event.Timestamp.Before(horizon.Time)) || event.Timestamp.After(now) {
+				imp.SkippedEvents++
+				continue
+			}
+
+			params.VisitorIds = append(params.VisitorIds, event.VisitorID)
+			params.EventTypes = append(params.EventTypes, event.Type)
+			params.Urls = append(params.Urls, event.URL)
+			params.Referrers = append(params.Referrers, event.Referrer)
+			params.Countries = append(params.Countries, event.Country)
+			params.Browsers = append(params.Browsers, event.Browser)
+			params.Devices = append(params.Devices, event.Device)
+			params.OperatingSystems = append(params.OperatingSystems, event.OS)
+			params.Timestamps = append(params.Timestamps, event.Timestamp)
+
+			if !imp.StartDate.Valid || event.Timestamp.Before(imp.StartDate.Time) {
+				imp.StartDate = sql.NullTime{Time: event.Timestamp, Valid: true}
+			}
+			if !imp.EndDate.Valid || event.Timestamp.After(imp.EndDate.Time) {
+				imp.EndDate = sql.NullTime{Time: event.Timestamp, Valid: true}
+			}
+		}
+
+		if len(params.Timestamps) > 0 {
+			inserted, err := j.querier.InsertImportedEvents(ctx, params)
+			if err != nil {
+				return err
+			}
+			imp.ImportedEvents += inserted
+		}
+
+		imp.Progress = progress
+		return j.querier.UpdateImportProgress(ctx, database.UpdateImportProgressParams{
+			Progress:       imp.Progress,
+			ImportedEvents: imp.ImportedEvents,
+			SkippedEvents:  imp.SkippedEvents,
+			StartDate:      imp.StartDate,
+			EndDate:        imp.EndDate,
+			ID:             imp.ID,
+		})
+	})
+}
+
+// loadAggregates stores the aggregates of a day of an aggregated export. They
+// have no visitor ids, so days past the data retention are kept like their
+// sketches would be, and only days still to come are skipped. The pageviews
+// of a day count as its imported events.
+func (j *importJob) loadAggregates(ctx context.Context, imp *database.Import, aggregates []importer.Aggregate, progress float64, now time.Time) error {
+	params := database.InsertImportedAggregatesParams{ImportID: imp.ID, TrackingID: imp.TrackingID}
+	for _, aggregate := range aggregates {
+		if aggregate.Date.After(now) {
+			if aggregate.Dimension == importer.DimensionAll {
+				imp.SkippedEvents += aggregate.Pageviews
+			}
+			continue
+		}
+
+		params.Buckets = append(params.Buckets, aggregate.Date)
+		params.Dimensions = append(params.Dimensions, aggregate.Dimension)
+		params.DimensionValues = append(params.DimensionValues, aggregate.Value)
+		params.Visitors = append(params.Visitors, aggregate.Visitors)
+		params.Pageviews = append(params.Pageviews, aggregate.Pageviews)
+
+		if aggregate.Dimension != importer.DimensionAll {
+			continue
+		}
+		imp.ImportedEvents += aggregate.Pageviews
+		if !imp.StartDate.Valid || aggregate.Date.Before(imp.StartDate.Time) {
+			imp.StartDate = sql.NullTime{Time: aggregate.Date, Valid: true}
+		}
+		if !imp.EndDate.Valid || aggregate.Date.After(imp.EndDate.Time) {
+			imp.EndDate = sql.NullTime{Time: aggregate.Date, Valid: true}
+		}
+	}
+
+	if len(params.Buckets) > 0 {
+		if err := j.querier.InsertImportedAggregates(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	imp.Progress = progress
+	return j.querier.UpdateImportProgress(ctx, database.UpdateImportProgressParams{
+		Progress:       imp.Progress,
+		ImportedEvents: imp.ImportedEvents,
+		SkippedEvents:  imp.SkippedEvents,
+		StartDate:      imp.StartDate,
+		EndDate:        imp.EndDate,
+		ID:             imp.ID,
+	})
+}
+
+// rollback deletes the events or aggregates of imp and settles its range.
+func (j *importJob) rollback(ctx context.Context, imp database.Import) error {
+	if importer.Source(imp.Source).Aggregated() {
+		if err := j.querier.DeleteImportAggregates(ctx, imp.ID); err != nil {
+			return err
+		}
+		return j.settle(ctx, imp)
+	}
+
+	for {
+		deleted, err := j.querier.DeleteImportEvents(ctx, database.DeleteImportEventsParams{
+			ImportID:  imp.ID,
+			BatchSize: retentionBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted < retentionBatchSize {
+			break
+		}
+	}
+	return j.settle(ctx, imp)
+}
+
+// settle brings the sketches and cached reports of the app in line
+// with its events over the range of imp. Aggregates are added to reports as
+// they are read, so only the cached reports of aggregated imports change.
+func (j *importJob) settle(ctx context.Context, imp database.Import) error {
+	if importer.Source(imp.Source).Aggregated() {
+		if j.cache != nil {
+			return j.cache.Invalidate(ctx, imp.TrackingID)
+		}
+		return nil
+	}
+	return settleEvents(ctx, j.querier, j.eventsRetention, j.cache, imp.TrackingID, imp.StartDate, imp.EndDate)
+}
+
+// settleEvents brings the sketches and cached reports of an app in line with
+// its events from start to end, both included, once events were added or
+// removed in hours they already covered.
+func settleEvents(ctx context.Context, querier database.Querier, eventsRetention *string, resultCache cache.Cache, trackingID uuid.UUID, start, end sql.NullTime) error {
+	if !start.Valid || !end.Valid {
+		return nil
+	}
+
+	horizon, err := querier.GetImportHorizon(ctx, database.GetImportHorizonParams{
+		EventsRetention: eventsRetention,
+		TrackingID:      trackingID,
+	})
+	if err != nil {
+		return err
+	}
+
+	hourlyStart, hourlyEnd := refreshRange(start.Time, end.Time, time.Hour, horizon)
+	if err := rebuildAppSketches(ctx, querier, trackingID, hourlyStart, hourlyEnd); err != nil {
+		return err
+	}
+
+	if resultCache != nil {
+		return resultCache.Invalidate(ctx, trackingID)
+	}
+	return nil
+}
+
+// refreshRange widens the range from start to end, both included, to whole
+// UTC buckets of size, like the sketches bucket them. Buckets that started
+// before horizon lost raw events and are left out, rebuilding them would drop
+// what the sketches still hold of them.
+func refreshRange(start, end time.Time, size time.Duration, horizon sql.NullTime) (time.Time, time.Time) {
+	start, end = start.Truncate(size), end.Truncate(size).Add(size)
+	if horizon.Valid && start.Before(horizon.Time) {
+		start = horizon.Time.Truncate(size)
+		if start.Before(horizon.Time) {
+			start = start.Add(size)
+		}
+	}
+	return start, end
+}
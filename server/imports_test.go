@@ -0,0 +1,336 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ScMofeoluwa/minalytics/cache"
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// writeImportFile writes an upload where CreateImport would.
+func (suite *ServiceSuite) writeImportFile(content string) string {
+	suite.Require().NoError(os.MkdirAll(importsDir(), 0o700))
+	file, err := os.CreateTemp(importsDir(), "test-*")
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { os.Remove(file.Name()) })
+	_, err = file.WriteString(content)
+	suite.Require().NoError(err)
+	suite.Require().NoError(file.Close())
+	return file.Name()
+}
+
+// stubResolver resolves every address to Nigeria.
+type stubResolver struct{}
+
+func (stubResolver) ResolveGeoLocation(string) (*types.GeoLocation, error) {
+	return &types.GeoLocation{Country: "Nigeria"}, nil
+}
+
+func (stubResolver) ParseUserAgent(string) *types.UserAgentDetails {
+	return &types.UserAgentDetails{Browser: "Chrome", Device: "desktop", OperatingSystem: "Windows"}
+}
+
+func (suite *ServiceSuite) TestImportJob() {
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Source:     "plausible",
+		File:       suite.writeImportFile("date,visitors,pageviews\n2024-05-01,2,3\n2999-01-01,4,5\n"),
+	}
+
+	// only uploads received on this host can be read here
+	claim := database.ClaimPendingImportParams{LockedBy: leaseHolder(), FileHost: importHost()}
+	suite.mockRepo.EXPECT().ClaimPendingImport(mock.Anything, claim).Return(imp, nil).Once()
+	suite.mockRepo.EXPECT().ClaimPendingImport(mock.Anything, claim).Return(database.Import{}, pgx.ErrNoRows).Once()
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, imp.TrackingID).Return(database.App{TrackingID: imp.TrackingID, Timezone: "UTC"}, nil).Once()
+
+	// aggregates are stored as they are, days still to come are skipped
+	suite.mockRepo.EXPECT().InsertImportedAggregates(mock.Anything, database.InsertImportedAggregatesParams{
+		ImportID:        imp.ID,
+		TrackingID:      imp.TrackingID,
+		Buckets:         []time.Time{day},
+		Dimensions:      []string{"all"},
+		DimensionValues: []string{""},
+		Visitors:        []int64{2},
+		Pageviews:       []int64{3},
+	}).Return(nil).Once()
+	// progress is recorded a day at a time
+	suite.mockRepo.EXPECT().UpdateImportProgress(mock.Anything, mock.MatchedBy(func(arg database.UpdateImportProgressParams) bool {
+		return arg.ID == imp.ID && arg.ImportedEvents == 3 && arg.SkippedEvents == 0 && arg.StartDate.Time.Equal(day)
+	})).Return(nil).Once()
+	suite.mockRepo.EXPECT().UpdateImportProgress(mock.Anything, mock.MatchedBy(func(arg database.UpdateImportProgressParams) bool {
+		return arg.ID == imp.ID && arg.Progress == 1 && arg.ImportedEvents == 3 && arg.SkippedEvents == 5 &&
+			arg.StartDate.Time.Equal(day) && arg.EndDate.Time.Equal(day)
+	})).Return(nil).Once()
+
+	suite.mockRepo.EXPECT().SetImportedSince(mock.Anything, database.SetImportedSinceParams{ImportedSince: at(day), TrackingID: imp.TrackingID}).Return(nil).Once()
+	suite.mockRepo.EXPECT().FinishImport(mock.Anything, database.FinishImportParams{ID: imp.ID, Status: importCompleted}).Return(nil).Once()
+
+	resultCache := cache.NewLRU(10)
+	suite.NoError(resultCache.Set(suite.ctx, imp.TrackingID, "overview", []byte("{}"), time.Hour))
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), nil, resultCache, nil).runPending(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+
+	_, found, _ := resultCache.Get(suite.ctx, imp.TrackingID, "overview")
+	suite.False(found)
+	suite.NoFileExists(imp.File)
+}
+
+func (suite *ServiceSuite) TestImportJobSkipsExpiredEvents() {
+	eventsRetention := "30 days"
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Source:     "access_log",
+		File:       suite.writeImportFile(`203.0.113.9 - - [01/May/2024:10:00:00 +0000] "GET /pricing HTTP/1.1" 200 512 "-" "` + userAgent + `"` + "\n"),
+		Hostname:   stringPtr("example.com"),
+	}
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, imp.TrackingID).Return(database.App{TrackingID: imp.TrackingID, Timezone: "Africa/Lagos"}, nil).Once()
+	suite.mockRepo.EXPECT().GetImportHorizon(mock.Anything, database.GetImportHorizonParams{
+		EventsRetention: &eventsRetention, TrackingID: imp.TrackingID,
+	}).Return(sql.NullTime{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}, nil).Once()
+	// nothing is inserted, so there's nothing to refresh
+	suite.mockRepo.EXPECT().UpdateImportProgress(mock.Anything, mock.MatchedBy(func(arg database.UpdateImportProgressParams) bool {
+		return arg.ID == imp.ID && arg.ImportedEvents == 0 && arg.SkippedEvents == 1 && !arg.StartDate.Valid
+	})).Return(nil).Once()
+	suite.mockRepo.EXPECT().FinishImport(mock.Anything, database.FinishImportParams{ID: imp.ID, Status: importCompleted}).Return(nil).Once()
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), &eventsRetention, nil, stubResolver{}).run(suite.ctx, imp))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestImportJobFails() {
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Source:     "umami",
+		File:       suite.writeImportFile("-- not a dump\n"),
+	}
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, imp.TrackingID).Return(database.App{TrackingID: imp.TrackingID, Timezone: "UTC"}, nil).Once()
+	suite.mockRepo.EXPECT().GetImportHorizon(mock.Anything, mock.Anything).Return(sql.NullTime{}, nil).Once()
+	suite.mockRepo.EXPECT().DeleteImportEvents(mock.Anything, database.DeleteImportEventsParams{ImportID: imp.ID, BatchSize: retentionBatchSize}).Return(0, nil).Once()
+	suite.mockRepo.EXPECT().FinishImport(mock.Anything, mock.MatchedBy(func(arg database.FinishImportParams) bool {
+		return arg.ID == imp.ID && arg.Status == importFailed && arg.Error != nil && strings.Contains(*arg.Error, "website_event")
+	})).Return(nil).Once()
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), nil, nil, nil).run(suite.ctx, imp))
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.NoFileExists(imp.File)
+}
+
+func (suite *ServiceSuite) TestImportJobAccessLog() {
+	userAgent := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	// logs imported with the CLI are read in place and kept
+	file := filepath.Join(suite.T().TempDir(), "access.log")
+	suite.Require().NoError(os.WriteFile(file, []byte(strings.Join([]string{
+		`203.0.113.9 - - [01/May/2024:10:00:00 +0000] "GET /pricing HTTP/1.1" 200 512 "-" "` + userAgent + `"`,
+		`203.0.113.9 - - [01/May/2024:10:00:01 +0000] "GET /app.js HTTP/1.1" 200 512 "-" "` + userAgent + `"`,
+	}, "\n")), 0o600))
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Source:     "access_log",
+		File:       file,
+		Hostname:   stringPtr("example.com"),
+	}
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, imp.TrackingID).Return(database.App{TrackingID: imp.TrackingID, Timezone: "UTC"}, nil).Once()
+	suite.mockRepo.EXPECT().GetImportHorizon(mock.Anything, mock.Anything).Return(sql.NullTime{}, nil).Twice()
+	suite.mockRepo.EXPECT().InsertImportedEvents(mock.Anything, mock.MatchedBy(func(arg database.InsertImportedEventsParams) bool {
+		return len(arg.Timestamps) == 1 && *arg.Urls[0] == "https://example.com/pricing" && arg.EventTypes[0] == "pageview" &&
+			arg.Countries[0] == "Nigeria" && arg.Browsers[0] == "Chrome" && len(arg.VisitorIds[0]) == 64
+	})).Return(1, nil).Once()
+	suite.mockRepo.EXPECT().UpdateImportProgress(mock.Anything, mock.Anything).Return(nil).Once()
+	// the builder hasn't reached the day yet, so there are no sketches to rebuild
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(sql.NullTime{}, nil).Once()
+	suite.mockRepo.EXPECT().SetImportedSince(mock.Anything, mock.Anything).Return(nil).Once()
+	suite.mockRepo.EXPECT().FinishImport(mock.Anything, database.FinishImportParams{ID: imp.ID, Status: importCompleted}).Return(nil).Once()
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), nil, nil, stubResolver{}).run(suite.ctx, imp))
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.FileExists(file)
+}
+
+func (suite *ServiceSuite) TestVisitorSalt() {
+	trackingID := uuid.New()
+	previous := viper.GetString("TokenSecret")
+	defer viper.Set("TokenSecret", previous)
+
+	// the public tracking ID alone doesn't give the salt away
+	viper.Set("TokenSecret", "secret")
+	salt := visitorSalt(trackingID)
+	suite.NotContains(salt, trackingID.String())
+	suite.Equal(salt, visitorSalt(trackingID))
+	suite.NotEqual(salt, visitorSalt(uuid.New()))
+
+	viper.Set("TokenSecret", "other")
+	suite.NotEqual(salt, visitorSalt(trackingID))
+}
+
+func (suite *ServiceSuite) TestImportJobResumes() {
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	start := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Status:     "running",
+		StartDate:  at(start),
+		EndDate:    at(start.Add(2 * time.Hour)),
+	}
+
+	suite.mockRepo.EXPECT().ClaimStaleImports(mock.Anything, database.ClaimStaleImportsParams{
+		LockedBy: leaseHolder(), LeaseSeconds: importLease.Seconds(),
+	}).Return([]database.Import{imp}, nil).Once()
+	// the events imported before the restart are deleted in batches
+	suite.mockRepo.EXPECT().DeleteImportEvents(mock.Anything, database.DeleteImportEventsParams{ImportID: imp.ID, BatchSize: retentionBatchSize}).Return(retentionBatchSize, nil).Once()
+	suite.mockRepo.EXPECT().DeleteImportEvents(mock.Anything, database.DeleteImportEventsParams{ImportID: imp.ID, BatchSize: retentionBatchSize}).Return(5, nil).Once()
+
+	// the horizon falls inside the first hour, whose sketches are left alone
+	suite.mockRepo.EXPECT().GetImportHorizon(mock.Anything, mock.Anything).Return(at(start.Add(-time.Minute)), nil).Once()
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(start.Add(48*time.Hour)), nil).Once()
+	suite.mockRepo.EXPECT().DeleteAppSketches(mock.Anything, database.DeleteAppSketchesParams{
+		TrackingID: imp.TrackingID, StartDate: at(start.Truncate(time.Hour).Add(time.Hour)), EndDate: at(start.Truncate(time.Hour).Add(3 * time.Hour)),
+	}).Return(nil).Once()
+	suite.mockRepo.EXPECT().GetAppSketchInputs(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	suite.mockRepo.EXPECT().RequeueImport(mock.Anything, imp.ID).Return(nil).Once()
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), nil, nil, nil).resume(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestImportJobResumesAggregates() {
+	imp := database.Import{
+		ID:         uuid.New(),
+		TrackingID: uuid.New(),
+		Source:     "ga4",
+		Status:     "running",
+	}
+
+	suite.mockRepo.EXPECT().ClaimStaleImports(mock.Anything, database.ClaimStaleImportsParams{
+		LockedBy: leaseHolder(), LeaseSeconds: importLease.Seconds(),
+	}).Return([]database.Import{imp}, nil).Once()
+	// aggregates are deleted at once, and have no sketches to rebuild
+	suite.mockRepo.EXPECT().DeleteImportAggregates(mock.Anything, imp.ID).Return(nil).Once()
+	suite.mockRepo.EXPECT().RequeueImport(mock.Anything, imp.ID).Return(nil).Once()
+
+	resultCache := cache.NewLRU(10)
+	suite.NoError(resultCache.Set(suite.ctx, imp.TrackingID, "overview", []byte("{}"), time.Hour))
+
+	suite.NoError(newImportJob(suite.mockRepo, zap.NewNop(), nil, resultCache, nil).resume(suite.ctx))
+	suite.mockRepo.AssertExpectations(suite.T())
+
+	_, found, _ := resultCache.Get(suite.ctx, imp.TrackingID, "overview")
+	suite.False(found)
+}
+
+func (suite *ServiceSuite) TestImportJobLosesLease() {
+	imp := database.Import{ID: uuid.New(), TrackingID: uuid.New(), Status: "running"}
+	job := newImportJob(suite.mockRepo, zap.NewNop(), nil, nil, nil)
+	job.heartbeat = time.Millisecond
+
+	// the lease is renewed while it holds, and the import stops once another
+	// process took it over
+	lease := database.HeartbeatImportParams{ID: imp.ID, LockedBy: leaseHolder()}
+	suite.mockRepo.EXPECT().HeartbeatImport(mock.Anything, lease).Return(1, nil).Once()
+	suite.mockRepo.EXPECT().HeartbeatImport(mock.Anything, lease).Return(0, nil).Once()
+
+	ctx, release := job.hold(suite.ctx, imp)
+	defer release()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		suite.Fail("lease not lost")
+	}
+	suite.ErrorIs(context.Cause(ctx), errImportLeaseLost)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestRefreshRange() {
+	start := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 3, 23, 59, 0, 0, time.UTC)
+
+	from, to := refreshRange(start, end, 24*time.Hour, sql.NullTime{})
+	suite.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), from)
+	suite.Equal(time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = refreshRange(start, end, time.Hour, sql.NullTime{Time: start, Valid: true})
+	suite.Equal(time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC), from)
+	suite.Equal(time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC), to)
+
+	// a horizon on a bucket boundary keeps that bucket
+	from, _ = refreshRange(start, end, 24*time.Hour, sql.NullTime{Time: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), Valid: true})
+	suite.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), from)
+}
+
+func (suite *ServiceSuite) TestCreateImport() {
+	userID, trackingID := uuid.New(), uuid.New()
+	hostname := "example.com"
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Once()
+	var file string
+	suite.mockRepo.EXPECT().CreateImport(mock.Anything, mock.MatchedBy(func(arg database.CreateImportParams) bool {
+		content, err := os.ReadFile(arg.File)
+		file = arg.File
+		return err == nil && string(content) == "date,visitors\n" && arg.Source == "ga4" &&
+			arg.Status == importPending && *arg.Hostname == hostname && arg.WebsiteID == nil && arg.LogFormat == nil &&
+			*arg.FileHost == importHost() && arg.LockedBy == nil
+	})).Return(database.Import{TrackingID: trackingID, Source: "ga4", Status: "pending"}, nil).Once()
+
+	imp, err := suite.service.CreateImport(suite.ctx, types.ImportPayload{
+		UserID: userID, TrackingID: trackingID, Source: "ga4", Hostname: hostname,
+	}, strings.NewReader("date,visitors\n"))
+	suite.NoError(err)
+	suite.Equal("pending", imp.Status)
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.NoError(os.Remove(file))
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Once()
+	_, err = suite.service.CreateImport(suite.ctx, types.ImportPayload{UserID: userID, TrackingID: trackingID, Source: "matomo"}, strings.NewReader(""))
+	suite.ErrorContains(err, "unsupported import source")
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Once()
+	_, err = suite.service.CreateImport(suite.ctx, types.ImportPayload{UserID: userID, TrackingID: trackingID, Source: "access_log", LogFormat: "json"}, strings.NewReader(""))
+	suite.ErrorContains(err, "unsupported log format")
+}
+
+func (suite *ServiceSuite) TestGetImport() {
+	userID, trackingID, importID := uuid.New(), uuid.New(), uuid.New()
+	failure := "found no daily data"
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Twice()
+	suite.mockRepo.EXPECT().GetImport(mock.Anything, database.GetImportParams{ID: importID, TrackingID: trackingID}).Return(database.Import{
+		ID: importID, TrackingID: trackingID, Status: importFailed, Progress: 0.5, Error: &failure,
+	}, nil).Once()
+
+	imp, err := suite.service.GetImport(suite.ctx, userID, trackingID, importID)
+	suite.NoError(err)
+	suite.Equal(failure, imp.Error)
+	suite.Nil(imp.StartDate)
+
+	suite.mockRepo.EXPECT().GetImport(mock.Anything, mock.Anything).Return(database.Import{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.GetImport(suite.ctx, userID, trackingID, uuid.New())
+	suite.ErrorIs(err, ErrImportNotFound)
+
+	// apps of other users, and unknown ones, aren't found
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.GetImports(suite.ctx, userID, trackingID)
+	suite.ErrorIs(err, ErrAppNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
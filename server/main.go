@@ -14,6 +14,7 @@ import (
 	"github.com/oschwald/geoip2-golang"
 	"go.uber.org/zap"
 
+	"github.com/ScMofeoluwa/minalytics/cache"
 	"github.com/ScMofeoluwa/minalytics/config"
 	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
 	_ "github.com/ScMofeoluwa/minalytics/docs"
@@ -21,6 +22,10 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// defaultEventsCompressAfter is how old events get before their chunks are
+// compressed, unless EVENTS_COMPRESS_AFTER says otherwise.
+const defaultEventsCompressAfter = "7 days"
+
 type Server struct {
 	router *gin.Engine
 	config config.Config
@@ -73,7 +78,27 @@ func (s *Server) Start() {
 	}
 
 	querier := database.New(connPool)
-	analyticsService := NewAnalyticsService(querier, geoDB)
+	if err := s.applyStoragePolicies(context.Background(), querier); err != nil {
+		s.logger.Fatal("Failed to apply events storage policies", zap.Error(err))
+	}
+
+	go newSketchBuilder(querier, s.logger).Run(context.Background())
+
+	var eventsRetention *string
+	if s.config.EventsRetention != "" {
+		eventsRetention = &s.config.EventsRetention
+	}
+	resultCache, err := s.newCache(context.Background())
+	if err != nil {
+		s.logger.Fatal("Failed to connect to Redis", zap.Error(err))
+	}
+
+	analyticsService := NewAnalyticsService(querier, geoDB, resultCache, connPool)
+
+	go newDataRetentionJob(querier, s.logger, resultCache).Run(context.Background())
+	go newImportJob(querier, s.logger, eventsRetention, resultCache, analyticsService).Run(context.Background())
+	go newDeletionJob(querier, s.logger, eventsRetention, resultCache).Run(context.Background())
+
 	analyticsHandler := NewAnalyticsHandler(analyticsService, s.logger)
 
 	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -85,34 +110,117 @@ func (s *Server) Start() {
 		auth.GET(":provider/callback", WrapHandler(analyticsHandler.Callback))
 	}
 
+	// API tokens are allowed the routes of their scopes, and only the routes
+	// for their apps when they are limited to apps
+	readStats := ScopeMiddleware(scopeReadStats)
+	manageApps := ScopeMiddleware(scopeManageApps)
+	ingest := ScopeMiddleware(scopeIngest)
+
 	apps := s.router.Group("apps")
-	apps.Use(JWTMiddleware())
+	apps.Use(JWTMiddleware(analyticsService))
 	{
-		apps.GET("/", WrapHandler(analyticsHandler.GetApps))
-		apps.POST("/", WrapHandler(analyticsHandler.CreateApp))
-		apps.PATCH("/:trackingID", WrapHandler(analyticsHandler.UpdateApp))
-		apps.DELETE("/:trackingID", WrapHandler(analyticsHandler.UpdateApp))
+		apps.GET("/", manageApps, WrapHandler(analyticsHandler.GetApps))
+		apps.POST("/", manageApps, WrapHandler(analyticsHandler.CreateApp))
+		apps.PATCH("/:trackingID", manageApps, WrapHandler(analyticsHandler.UpdateApp))
+		apps.DELETE("/:trackingID", manageApps, WrapHandler(analyticsHandler.DeleteApp))
+		apps.GET("/:trackingID/export", readStats, analyticsHandler.ExportEvents)
+		apps.POST("/:trackingID/imports", ingest, WrapHandler(analyticsHandler.CreateImport))
+		apps.GET("/:trackingID/imports", ScopeMiddleware(scopeIngest, scopeManageApps), WrapHandler(analyticsHandler.GetImports))
+		apps.GET("/:trackingID/imports/:importID", ScopeMiddleware(scopeIngest, scopeManageApps), WrapHandler(analyticsHandler.GetImport))
+		apps.POST("/:trackingID/deletions", manageApps, WrapHandler(analyticsHandler.CreateDeletion))
+		apps.GET("/:trackingID/deletions", manageApps, WrapHandler(analyticsHandler.GetDeletions))
+		apps.GET("/:trackingID/deletions/:deletionID", manageApps, WrapHandler(analyticsHandler.GetDeletion))
+		apps.POST("/:trackingID/shares", manageApps, WrapHandler(analyticsHandler.CreateShare))
+		apps.GET("/:trackingID/shares", manageApps, WrapHandler(analyticsHandler.GetShares))
+		apps.DELETE("/:trackingID/shares/:shareID", manageApps, WrapHandler(analyticsHandler.RevokeShare))
+		apps.GET("/:trackingID/members", manageApps, WrapHandler(analyticsHandler.GetMembers))
+		apps.PATCH("/:trackingID/members/:userID", manageApps, WrapHandler(analyticsHandler.UpdateMember))
+		apps.DELETE("/:trackingID/members/:userID", manageApps, WrapHandler(analyticsHandler.RemoveMember))
+		apps.POST("/:trackingID/transfer", manageApps, WrapHandler(analyticsHandler.TransferApp))
+		apps.POST("/:trackingID/invitations", manageApps, WrapHandler(analyticsHandler.CreateInvitation))
+		apps.GET("/:trackingID/invitations", manageApps, WrapHandler(analyticsHandler.GetInvitations))
+		apps.DELETE("/:trackingID/invitations/:invitationID", manageApps, WrapHandler(analyticsHandler.RevokeInvitation))
 	}
 
-	analytics := s.router.Group("analytics")
-	analytics.Use(JWTMiddleware())
-	analytics.Use(AppAccessMiddleware(analyticsService))
+	invitations := s.router.Group("invitations")
+	invitations.Use(JWTMiddleware(analyticsService), manageApps)
+	{
+		invitations.GET("/", WrapHandler(analyticsHandler.GetUserInvitations))
+		invitations.POST("/:invitationID/accept", WrapHandler(analyticsHandler.AcceptInvitation))
+	}
+
+	organizations := s.router.Group("organizations")
+	organizations.Use(JWTMiddleware(analyticsService), manageApps)
 	{
-		analytics.GET("referrals", WrapHandler(analyticsHandler.GetReferrals))
-		analytics.GET("pages", WrapHandler(analyticsHandler.GetPages))
-		analytics.GET("browsers", WrapHandler(analyticsHandler.GetBrowsers))
-		analytics.GET("countries", WrapHandler(analyticsHandler.GetCountries))
-		analytics.GET("devices", WrapHandler(analyticsHandler.GetDevices))
-		analytics.GET("os", WrapHandler(analyticsHandler.GetOS))
-		analytics.GET("visitors", WrapHandler(analyticsHandler.GetVisitors))
-		analytics.GET("pageviews", WrapHandler(analyticsHandler.GetPageViews))
+		organizations.GET("/", WrapHandler(analyticsHandler.GetOrganizations))
+		organizations.POST("/", WrapHandler(analyticsHandler.CreateOrganization))
+		organizations.GET("/:orgID/members", WrapHandler(analyticsHandler.GetOrganizationMembers))
+		organizations.PUT("/:orgID/members", WrapHandler(analyticsHandler.SetOrganizationMember))
+		organizations.DELETE("/:orgID/members/:userID", WrapHandler(analyticsHandler.RemoveOrganizationMember))
 	}
 
+	// API tokens are managed with a session only, so that they can't make more
+	tokens := s.router.Group("tokens")
+	tokens.Use(JWTMiddleware(analyticsService), ScopeMiddleware())
+	{
+		tokens.GET("/", WrapHandler(analyticsHandler.GetAPITokens))
+		tokens.POST("/", WrapHandler(analyticsHandler.CreateAPIToken))
+		tokens.DELETE("/:tokenID", WrapHandler(analyticsHandler.RevokeAPIToken))
+	}
+
+	analytics := s.router.Group("analytics")
+	analytics.Use(JWTMiddleware(analyticsService), readStats)
+	analytics.Use(AppAccessMiddleware(analyticsService))
+	registerAnalyticsRoutes(analytics, analyticsHandler)
+
+	// share links open up the same endpoints, read-only, for one app
+	s.router.POST("shared/:token/session", WrapHandler(analyticsHandler.CreateShareSession))
+	shared := s.router.Group("shared/:token")
+	shared.Use(ShareMiddleware(analyticsService))
+	registerAnalyticsRoutes(shared, analyticsHandler)
+
 	port := s.config.Port
 	s.logger.Info("Starting server", zap.String("port", port))
 	s.router.Run(":" + port)
 }
 
+// registerAnalyticsRoutes registers the analytics endpoints on a group whose
+// middleware sets the app they are for.
+func registerAnalyticsRoutes(analytics *gin.RouterGroup, analyticsHandler *AnalyticsHandler) {
+	analytics.GET("referrals", WrapHandler(analyticsHandler.GetReferrals))
+	analytics.GET("pages", WrapHandler(analyticsHandler.GetPages))
+	analytics.GET("browsers", WrapHandler(analyticsHandler.GetBrowsers))
+	analytics.GET("countries", WrapHandler(analyticsHandler.GetCountries))
+	analytics.GET("devices", WrapHandler(analyticsHandler.GetDevices))
+	analytics.GET("os", WrapHandler(analyticsHandler.GetOS))
+	analytics.GET("visitors", WrapHandler(analyticsHandler.GetVisitors))
+	analytics.GET("pageviews", WrapHandler(analyticsHandler.GetPageViews))
+	analytics.GET("overview", WrapHandler(analyticsHandler.GetOverview))
+	analytics.GET("events", WrapHandler(analyticsHandler.GetEventTypes))
+	analytics.GET("realtime", WrapHandler(analyticsHandler.GetRealtime))
+	analytics.GET("realtime/stream", analyticsHandler.StreamEvents)
+	analytics.GET("retention", WrapHandler(analyticsHandler.GetRetention))
+	analytics.GET("flow", WrapHandler(analyticsHandler.GetUserFlow))
+	analytics.GET("export", analyticsHandler.ExportStats)
+}
+
+// newCache returns the cache for report results, in Redis when REDIS_URL is set
+// so every server shares it, and in memory otherwise.
+func (s *Server) newCache(ctx context.Context) (cache.Cache, error) {
+	if s.config.RedisURL == "" {
+		return cache.NewLRU(defaultCacheSize), nil
+	}
+
+	redisCache, err := cache.NewRedis(s.config.RedisURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := redisCache.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return redisCache, nil
+}
+
 func (s *Server) migrateDB() error {
 	m, err := migrate.New("file://database/migrations", s.config.DatabaseURL)
 	if err != nil {
@@ -124,3 +232,22 @@ func (s *Server) migrateDB() error {
 	s.logger.Info("migrations applied successfully")
 	return nil
 }
+
+// applyStoragePolicies schedules compression and, when EVENTS_RETENTION is set,
+// removal of old events chunks. Both are applied on every start so changes to
+// the config take effect.
+func (s *Server) applyStoragePolicies(ctx context.Context, querier database.Querier) error {
+	compressAfter := s.config.EventsCompressAfter
+	if compressAfter == "" {
+		compressAfter = defaultEventsCompressAfter
+	}
+	if err := querier.SetEventsCompressionPolicy(ctx, compressAfter); err != nil {
+		return err
+	}
+
+	var dropAfter *string
+	if s.config.EventsRetention != "" {
+		dropAfter = &s.config.EventsRetention
+	}
+	return querier.SetEventsRetentionPolicy(ctx, dropAfter)
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var (
+	ErrForbidden            = errors.New("your role in this app does not allow this")
+	ErrInvalidRole          = errors.New("role must be one of admin, editor or viewer")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrTransferOrganization = errors.New("apps can only be moved into an organization the new owner owns")
+)
+
+// Roles of the members of an app, each allowed what the ones below it are.
+// Viewers read the stats of the app, editors manage its settings and imports,
+// admins its members, invitations, share links and deletions, and owners
+// delete or transfer it.
+const (
+	roleOwner  = "owner"
+	roleAdmin  = "admin"
+	roleEditor = "editor"
+	roleViewer = "viewer"
+)
+
+var roleRanks = map[string]int{roleViewer: 1, roleEditor: 2, roleAdmin: 3, roleOwner: 4}
+
+// authorizeApp returns the app if the user is a member of it, or of its
+// organization, with role or a role above it. Apps the user isn't a member of
+// are not found.
+func (s *analyticsService) authorizeApp(ctx context.Context, userID, trackingID uuid.UUID, role string) (*types.App, error) {
+	row, err := s.Querier.GetAppByTrackingID(ctx, trackingID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAppNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner of the app needs no lookup
+	memberRole := roleOwner
+	if row.UserID != userID {
+		memberRole, err = s.Querier.GetAppMember(ctx, database.GetAppMemberParams{TrackingID: trackingID, UserID: userID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAppNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if roleRanks[memberRole] < roleRanks[role] {
+		return nil, ErrForbidden
+	}
+
+	app := newApp(row)
+	app.Role = memberRole
+	return app, nil
+}
+
+// validInvitedRole reports whether members can be given role. There is only
+// one owner, whom apps are transferred to.
+func validInvitedRole(role string) bool {
+	return role == roleAdmin || role == roleEditor || role == roleViewer
+}
+
+func (s *analyticsService) GetMembers(ctx context.Context, userID, trackingID uuid.UUID) ([]types.Member, error) {
+	if _, err := s.ValidateAppAccess(ctx, userID, trackingID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetAppMembers(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]types.Member, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, types.Member{
+			UserID:    row.UserID,
+			Email:     row.Email,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return members, nil
+}
+
+// UpdateMember changes the role of a member other than the owner.
+func (s *analyticsService) UpdateMember(ctx context.Context, userID, trackingID, memberID uuid.UUID, role string) error {
+	if !validInvitedRole(role) {
+		return ErrInvalidRole
+	}
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return err
+	}
+
+	updated, err := s.Querier.UpdateAppMemberRole(ctx, database.UpdateAppMemberRoleParams{
+		Role:       role,
+		TrackingID: trackingID,
+		UserID:     memberID,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes a member other than the owner from an app. Admins remove
+// anyone, and the other members only themselves.
+func (s *analyticsService) RemoveMember(ctx context.Context, userID, trackingID, memberID uuid.UUID) error {
+	role := roleAdmin
+	if memberID == userID {
+		role = roleViewer
+	}
+	if _, err := s.authorizeApp(ctx, userID, trackingID, role); err != nil {
+		return err
+	}
+
+	removed, err := s.Querier.DeleteAppMember(ctx, database.DeleteAppMemberParams{TrackingID: trackingID, UserID: memberID})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// TransferApp makes a member the owner of an app, and its previous owner an
+// admin. Roles in organizations carry over to their apps, so the app is moved
+// into orgID, which the member must own, or into their personal organization,
+// lest the previous owner keep owning it through its organization.
+func (s *analyticsService) TransferApp(ctx context.Context, userID, trackingID, memberID, orgID uuid.UUID) (*types.App, error) {
+	app, err := s.authorizeApp(ctx, userID, trackingID, roleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if memberID == userID {
+		return app, nil
+	}
+
+	_, err = s.Querier.GetAppMember(ctx, database.GetAppMemberParams{TrackingID: trackingID, UserID: memberID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if orgID == uuid.Nil {
+		orgID, err = s.Querier.GetOrCreatePersonalOrganization(ctx, memberID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		role, err := s.Querier.GetOrganizationMember(ctx, database.GetOrganizationMemberParams{OrgID: orgID, UserID: memberID})
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && role != roleOwner) {
+			return nil, ErrTransferOrganization
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if orgID != app.OrgID {
+		existing, err := s.Querier.CheckAppExists(ctx, database.CheckAppExistsParams{OrgID: orgID, Name: app.Name})
+		if err == nil && existing.TrackingID != uuid.Nil {
+			return nil, ErrAppExists
+		}
+	}
+
+	row, err := s.Querier.TransferApp(ctx, database.TransferAppParams{TrackingID: trackingID, UserID: memberID, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	app = newApp(row)
+	app.Role = roleAdmin
+	return app, nil
+}
+
+func newInvitation(row database.AppInvitation) types.Invitation {
+	return types.Invitation{
+		ID:         row.ID,
+		TrackingID: row.TrackingID,
+		Email:      row.Email,
+		Role:       row.Role,
+		InvitedBy:  row.InvitedBy,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+}
+
+// CreateInvitation invites someone to an app by email. Inviting the same email
+// again replaces the invitation.
+func (s *analyticsService) CreateInvitation(ctx context.Context, data types.InvitationPayload) (*types.Invitation, error) {
+	if !validInvitedRole(data.Role) {
+		return nil, ErrInvalidRole
+	}
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	row, err := s.Querier.CreateAppInvitation(ctx, database.CreateAppInvitationParams{
+		TrackingID: data.TrackingID,
+		Email:      data.Email,
+		Role:       data.Role,
+		InvitedBy:  data.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := newInvitation(row)
+	return &invitation, nil
+}
+
+func (s *analyticsService) GetInvitations(ctx context.Context, userID, trackingID uuid.UUID) ([]types.Invitation, error) {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetAppInvitations(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]types.Invitation, 0, len(rows))
+	for _, row := range rows {
+		invitations = append(invitations, newInvitation(row))
+	}
+	return invitations, nil
+}
+
+func (s *analyticsService) RevokeInvitation(ctx context.Context, userID, trackingID, invitationID uuid.UUID) error {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return err
+	}
+
+	deleted, err := s.Querier.DeleteAppInvitation(ctx, database.DeleteAppInvitationParams{ID: invitationID, TrackingID: trackingID})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// GetUserInvitations returns the pending invitations to the email the user
+// signed in with.
+func (s *analyticsService) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]types.Invitation, error) {
+	rows, err := s.Querier.GetUserInvitations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]types.Invitation, 0, len(rows))
+	for _, row := range rows {
+		invitations = append(invitations, types.Invitation{
+			ID:         row.ID,
+			TrackingID: row.TrackingID,
+			AppName:    row.AppName,
+			Email:      row.Email,
+			Role:       row.Role,
+			InvitedBy:  row.InvitedBy,
+			CreatedAt:  row.CreatedAt.Time,
+		})
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation makes the user a member of the app they were invited to,
+// and returns the app.
+func (s *analyticsService) AcceptInvitation(ctx context.Context, userID, invitationID uuid.UUID) (*types.App, error) {
+	trackingID, err := s.Querier.AcceptAppInvitation(ctx, database.AcceptAppInvitationParams{ID: invitationID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.ValidateAppAccess(ctx, userID, trackingID)
+}
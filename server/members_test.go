@@ -0,0 +1,205 @@
+package server
+
+import (
+	"database/sql"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *ServiceSuite) TestAppRoles() {
+	ownerID, trackingID := uuid.New(), uuid.New()
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: ownerID, TrackingID: trackingID}, nil)
+
+	readStats := func(userID uuid.UUID) error {
+		_, err := suite.service.ValidateAppAccess(suite.ctx, userID, trackingID)
+		return err
+	}
+	changeSettings := func(userID uuid.UUID) error {
+		_, err := suite.service.UpdateApp(suite.ctx, types.AppPayload{UserID: userID, TrackingID: trackingID, Name: "Blog"})
+		return err
+	}
+	inviteMember := func(userID uuid.UUID) error {
+		_, err := suite.service.CreateInvitation(suite.ctx, types.InvitationPayload{UserID: userID, TrackingID: trackingID, Email: "ada@example.com", Role: roleViewer})
+		return err
+	}
+	deleteApp := func(userID uuid.UUID) error {
+		return suite.service.DeleteApp(suite.ctx, types.AppPayload{UserID: userID, TrackingID: trackingID})
+	}
+
+	testCases := []struct {
+		name       string
+		memberRole string
+		action     func(uuid.UUID) error
+		mockSetup  func()
+		err        error
+	}{
+		{name: "viewer reads stats", memberRole: roleViewer, action: readStats},
+		{name: "viewer can't change settings", memberRole: roleViewer, action: changeSettings, err: ErrForbidden},
+		{
+			name:       "editor changes settings",
+			memberRole: roleEditor,
+			action:     changeSettings,
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().UpdateApp(mock.Anything, mock.Anything).Return(database.App{TrackingID: trackingID}, nil).Once()
+			},
+		},
+		{name: "editor can't invite members", memberRole: roleEditor, action: inviteMember, err: ErrForbidden},
+		{
+			name:       "admin invites members",
+			memberRole: roleAdmin,
+			action:     inviteMember,
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().CreateAppInvitation(mock.Anything, mock.Anything).Return(database.AppInvitation{}, nil).Once()
+			},
+		},
+		{name: "admin can't delete the app", memberRole: roleAdmin, action: deleteApp, err: ErrForbidden},
+		{name: "not a member", action: readStats, err: ErrAppNotFound},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			userID := uuid.New()
+			params := database.GetAppMemberParams{TrackingID: trackingID, UserID: userID}
+			if tc.memberRole == "" {
+				suite.mockRepo.EXPECT().GetAppMember(mock.Anything, params).Return("", pgx.ErrNoRows).Once()
+			} else {
+				suite.mockRepo.EXPECT().GetAppMember(mock.Anything, params).Return(tc.memberRole, nil).Once()
+			}
+			if tc.mockSetup != nil {
+				tc.mockSetup()
+			}
+
+			err := tc.action(userID)
+			if tc.err != nil {
+				suite.ErrorIs(err, tc.err)
+				return
+			}
+			suite.NoError(err)
+		})
+	}
+
+	// the owner needs no membership lookup
+	suite.mockRepo.EXPECT().DeleteApp(mock.Anything, trackingID).Return(nil).Once()
+	suite.NoError(deleteApp(ownerID))
+	app, err := suite.service.ValidateAppAccess(suite.ctx, ownerID, trackingID)
+	suite.NoError(err)
+	suite.Equal(roleOwner, app.Role)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestUpdateMember() {
+	ownerID, trackingID, memberID := uuid.New(), uuid.New(), uuid.New()
+
+	// there is one owner, whom apps are transferred to
+	suite.ErrorIs(suite.service.UpdateMember(suite.ctx, ownerID, trackingID, memberID, roleOwner), ErrInvalidRole)
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: ownerID, TrackingID: trackingID}, nil).Twice()
+	suite.mockRepo.EXPECT().UpdateAppMemberRole(mock.Anything, database.UpdateAppMemberRoleParams{Role: roleEditor, TrackingID: trackingID, UserID: memberID}).Return(1, nil).Once()
+	suite.NoError(suite.service.UpdateMember(suite.ctx, ownerID, trackingID, memberID, roleEditor))
+
+	// the owner's row isn't updated
+	suite.mockRepo.EXPECT().UpdateAppMemberRole(mock.Anything, mock.Anything).Return(0, nil).Once()
+	suite.ErrorIs(suite.service.UpdateMember(suite.ctx, ownerID, trackingID, ownerID, roleViewer), ErrMemberNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestRemoveMember() {
+	ownerID, trackingID, viewerID := uuid.New(), uuid.New(), uuid.New()
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: ownerID, TrackingID: trackingID}, nil).Times(3)
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: viewerID}).Return(roleViewer, nil).Twice()
+
+	// viewers only remove themselves
+	suite.ErrorIs(suite.service.RemoveMember(suite.ctx, viewerID, trackingID, uuid.New()), ErrForbidden)
+
+	suite.mockRepo.EXPECT().DeleteAppMember(mock.Anything, database.DeleteAppMemberParams{TrackingID: trackingID, UserID: viewerID}).Return(1, nil).Twice()
+	suite.NoError(suite.service.RemoveMember(suite.ctx, viewerID, trackingID, viewerID))
+	suite.NoError(suite.service.RemoveMember(suite.ctx, ownerID, trackingID, viewerID))
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestTransferApp() {
+	ownerID, trackingID, adminID := uuid.New(), uuid.New(), uuid.New()
+	teamID, personalID, otherID := uuid.New(), uuid.New(), uuid.New()
+	app := database.App{UserID: ownerID, TrackingID: trackingID, OrgID: teamID, Name: "Blog"}
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(app, nil).Times(5)
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: adminID}).Return(roleAdmin, nil)
+
+	// only owners transfer apps
+	_, err := suite.service.TransferApp(suite.ctx, adminID, trackingID, adminID, uuid.Nil)
+	suite.ErrorIs(err, ErrForbidden)
+
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, mock.Anything).Return("", pgx.ErrNoRows).Once()
+	_, err = suite.service.TransferApp(suite.ctx, ownerID, trackingID, uuid.New(), uuid.Nil)
+	suite.ErrorIs(err, ErrMemberNotFound)
+
+	// apps only move into organizations the new owner owns
+	suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, database.GetOrganizationMemberParams{OrgID: otherID, UserID: adminID}).Return(roleAdmin, nil).Once()
+	_, err = suite.service.TransferApp(suite.ctx, ownerID, trackingID, adminID, otherID)
+	suite.ErrorIs(err, ErrTransferOrganization)
+
+	suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, database.GetOrganizationMemberParams{OrgID: otherID, UserID: adminID}).Return(roleOwner, nil).Once()
+	suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, database.CheckAppExistsParams{OrgID: otherID, Name: "Blog"}).Return(database.App{TrackingID: uuid.New()}, nil).Once()
+	_, err = suite.service.TransferApp(suite.ctx, ownerID, trackingID, adminID, otherID)
+	suite.ErrorIs(err, ErrAppExists)
+
+	// by default the app moves into the new owner's personal organization
+	suite.mockRepo.EXPECT().GetOrCreatePersonalOrganization(mock.Anything, adminID).Return(personalID, nil).Once()
+	suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, database.CheckAppExistsParams{OrgID: personalID, Name: "Blog"}).Return(database.App{}, pgx.ErrNoRows).Once()
+	suite.mockRepo.EXPECT().TransferApp(mock.Anything, database.TransferAppParams{TrackingID: trackingID, UserID: adminID, OrgID: personalID}).
+		Return(database.App{UserID: adminID, TrackingID: trackingID, OrgID: personalID, Name: "Blog"}, nil).Once()
+	transferred, err := suite.service.TransferApp(suite.ctx, ownerID, trackingID, adminID, uuid.Nil)
+	suite.NoError(err)
+	// the previous owner stays on as an admin
+	suite.Equal(roleAdmin, transferred.Role)
+	suite.Equal(personalID, transferred.OrgID)
+
+	// and can no longer delete it
+	moved := database.App{UserID: adminID, TrackingID: trackingID, OrgID: personalID, Name: "Blog"}
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(moved, nil).Once()
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: ownerID}).Return(roleAdmin, nil).Once()
+	suite.ErrorIs(suite.service.DeleteApp(suite.ctx, types.AppPayload{UserID: ownerID, TrackingID: trackingID}), ErrForbidden)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestInvitations() {
+	ownerID, trackingID, userID, invitationID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: ownerID, TrackingID: trackingID, Timezone: "UTC"}, nil)
+
+	_, err := suite.service.CreateInvitation(suite.ctx, types.InvitationPayload{UserID: ownerID, TrackingID: trackingID, Email: "ada@example.com", Role: roleOwner})
+	suite.ErrorIs(err, ErrInvalidRole)
+
+	suite.mockRepo.EXPECT().CreateAppInvitation(mock.Anything, database.CreateAppInvitationParams{
+		TrackingID: trackingID, Email: "ada@example.com", Role: roleViewer, InvitedBy: ownerID,
+	}).Return(database.AppInvitation{ID: invitationID, TrackingID: trackingID, Email: "ada@example.com", Role: roleViewer, InvitedBy: ownerID}, nil).Once()
+	invitation, err := suite.service.CreateInvitation(suite.ctx, types.InvitationPayload{UserID: ownerID, TrackingID: trackingID, Email: "ada@example.com", Role: roleViewer})
+	suite.NoError(err)
+	suite.Equal(invitationID, invitation.ID)
+
+	suite.mockRepo.EXPECT().GetUserInvitations(mock.Anything, userID).Return([]database.GetUserInvitationsRow{
+		{ID: invitationID, TrackingID: trackingID, Email: "ada@example.com", Role: roleViewer, AppName: "Blog", CreatedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+	}, nil).Once()
+	invitations, err := suite.service.GetUserInvitations(suite.ctx, userID)
+	suite.NoError(err)
+	suite.Len(invitations, 1)
+	suite.Equal("Blog", invitations[0].AppName)
+
+	suite.mockRepo.EXPECT().AcceptAppInvitation(mock.Anything, database.AcceptAppInvitationParams{ID: invitationID, UserID: userID}).Return(trackingID, nil).Once()
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: userID}).Return(roleViewer, nil).Once()
+	app, err := suite.service.AcceptInvitation(suite.ctx, userID, invitationID)
+	suite.NoError(err)
+	suite.Equal(roleViewer, app.Role)
+
+	// invitations to other emails can't be accepted
+	suite.mockRepo.EXPECT().AcceptAppInvitation(mock.Anything, mock.Anything).Return(uuid.Nil, pgx.ErrNoRows).Once()
+	_, err = suite.service.AcceptInvitation(suite.ctx, uuid.New(), invitationID)
+	suite.ErrorIs(err, ErrInvitationNotFound)
+
+	suite.mockRepo.EXPECT().DeleteAppInvitation(mock.Anything, database.DeleteAppInvitationParams{ID: invitationID, TrackingID: trackingID}).Return(0, nil).Once()
+	suite.ErrorIs(suite.service.RevokeInvitation(suite.ctx, ownerID, trackingID, invitationID), ErrInvitationNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
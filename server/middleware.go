@@ -1,6 +1,10 @@
 package server
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -9,7 +13,10 @@ import (
 	"github.com/google/uuid"
 )
 
-func JWTMiddleware() gin.HandlerFunc {
+// JWTMiddleware authenticates requests by the JWT handed out on sign in, or by
+// an API token, which starts with mk_. The API token is set as "apiToken" for
+// ScopeMiddleware to check.
+func JWTMiddleware(s types.AnalyticsService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,6 +32,24 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if strings.HasPrefix(token, apiTokenPrefix) {
+			apiToken, err := s.ValidateAPIToken(ctx, token, ctx.ClientIP())
+			if err != nil {
+				if errors.Is(err, ErrInvalidAPIToken) {
+					ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				} else {
+					ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to validate API token"})
+				}
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set("userID", apiToken.UserID)
+			ctx.Set("apiToken", apiToken)
+			ctx.Next()
+			return
+		}
+
 		claims, err := VerifyJWT(token)
 		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
@@ -45,6 +70,35 @@ func JWTMiddleware() gin.HandlerFunc {
 	}
 }
 
+// ScopeMiddleware lets requests made with an API token through only if the
+// token has one of scopes and is allowed the app the request is for, if it is
+// limited to apps. Requests made with a JWT always go through. Without scopes,
+// it lets through requests made with a JWT only.
+func ScopeMiddleware(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		value, exists := ctx.Get("apiToken")
+		if !exists {
+			ctx.Next()
+			return
+		}
+		apiToken := value.(*types.APIToken)
+
+		trackingID_ := ctx.Param("trackingID")
+		if trackingID_ == "" {
+			trackingID_ = ctx.Query("trackingID")
+		}
+		// requests that aren't for an app have no tracking ID
+		trackingID, _ := uuid.Parse(trackingID_)
+
+		if !tokenAllows(apiToken, scopes, trackingID) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "the API token does not allow this"})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
 func AppAccessMiddleware(s types.AnalyticsService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		userID, exists := ctx.Get("userID")
@@ -64,21 +118,106 @@ func AppAccessMiddleware(s types.AnalyticsService) gin.HandlerFunc {
 
 		trackingID := uuid.MustParse(trackingID_)
 
-		err := s.ValidateAppAccess(ctx, user, trackingID)
+		app, err := s.ValidateAppAccess(ctx, user, trackingID)
 		if err != nil {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			ctx.Abort()
 			return
 		}
 
-		ctx.Set("trackingID", trackingID)
+		setAppContext(ctx, app)
+		ctx.Next()
+	}
+}
+
+// ShareMiddleware is the alternative to JWTMiddleware and AppAccessMiddleware
+// for routes under /shared/:token, which open up the analytics endpoints of
+// the app a share link is for without an account. Links with a password need
+// a session started with it, kept in a cookie or sent in the X-Share-Session
+// header.
+func ShareMiddleware(s types.AnalyticsService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		_, endpoint, _ := strings.Cut(ctx.FullPath(), "/:token/")
+		session := ctx.GetHeader("X-Share-Session")
+		if session == "" {
+			session, _ = ctx.Cookie(shareSessionCookie)
+		}
+		app, err := s.ValidateShareAccess(ctx, ctx.Param("token"), session, endpoint)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, ErrShareNotFound), errors.Is(err, ErrAppNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, ErrShareExpired):
+				status = http.StatusGone
+			case errors.Is(err, ErrShareSessionRequired):
+				status = http.StatusUnauthorized
+			case errors.Is(err, ErrEndpointNotShared):
+				status = http.StatusForbidden
+			}
+			message := err.Error()
+			if status == http.StatusInternalServerError {
+				message = "failed to validate share link"
+			}
+			ctx.JSON(status, gin.H{"error": message})
+			ctx.Abort()
+			return
+		}
+
+		setAppContext(ctx, app)
 		ctx.Next()
 	}
 }
 
+// setAppContext sets what the analytics handlers need to know of the app whose
+// stats are asked for.
+func setAppContext(ctx *gin.Context, app *types.App) {
+	ctx.Set("trackingID", app.TrackingID)
+	ctx.Set("timezone", app.Timezone)
+	// the "all" period starts with the app's data, imported data included
+	createdAt := app.CreatedAt
+	if app.ImportedSince != nil && app.ImportedSince.Before(createdAt) {
+		createdAt = *app.ImportedSince
+	}
+	ctx.Set("createdAt", createdAt)
+}
+
+// WrapHandler writes the response of handler as JSON. Successful responses the
+// handler set a Cache-Control header on get an ETag, and requests whose
+// If-None-Match already has it are answered with 304 Not Modified.
 func WrapHandler(handler func(*gin.Context) types.APIResponse) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		response := handler(ctx)
-		ctx.JSON(response.StatusCode, response)
+		if response.StatusCode != http.StatusOK || ctx.Writer.Header().Get("Cache-Control") == "" {
+			ctx.JSON(response.StatusCode, response)
+			return
+		}
+
+		body, err := json.Marshal(response)
+		if err != nil {
+			ctx.JSON(response.StatusCode, response)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		ctx.Header("ETag", etag)
+		if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+			ctx.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 asks for.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var (
+	ErrOrganizationNotFound    = errors.New("organization not found")
+	ErrOrganizationForbidden   = errors.New("your role in this organization does not allow this")
+	ErrInvalidOrganizationRole = errors.New("role must be one of owner, admin, editor or viewer")
+	ErrUserNotFound            = errors.New("no user has signed in with this email")
+	ErrLastOwner               = errors.New("an organization needs at least one owner")
+)
+
+// authorizeOrganization returns the role of the user in an organization if it
+// is role or a role above it. The members of an organization have their role
+// in every app of it, and organizations the user isn't a member of are not
+// found.
+func (s *analyticsService) authorizeOrganization(ctx context.Context, userID, orgID uuid.UUID, role string) (string, error) {
+	memberRole, err := s.Querier.GetOrganizationMember(ctx, database.GetOrganizationMemberParams{OrgID: orgID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrOrganizationNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if roleRanks[memberRole] < roleRanks[role] {
+		return "", ErrOrganizationForbidden
+	}
+	return memberRole, nil
+}
+
+// checkOtherOwner returns ErrLastOwner unless an organization has owners
+// besides the one about to go.
+func (s *analyticsService) checkOtherOwner(ctx context.Context, orgID uuid.UUID) error {
+	owners, err := s.Querier.CountOrganizationOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners < 2 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// CreateOrganization creates an organization owned by the user.
+func (s *analyticsService) CreateOrganization(ctx context.Context, userID uuid.UUID, name string) (*types.Organization, error) {
+	row, err := s.Querier.CreateOrganization(ctx, database.CreateOrganizationParams{Name: name, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Organization{
+		ID:        row.ID,
+		Name:      row.Name,
+		Role:      roleOwner,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
+
+// GetOrganizations returns the organizations the user is a member of, their
+// personal organization first.
+func (s *analyticsService) GetOrganizations(ctx context.Context, userID uuid.UUID) ([]types.Organization, error) {
+	rows, err := s.Querier.GetOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]types.Organization, 0, len(rows))
+	for _, row := range rows {
+		orgs = append(orgs, types.Organization{
+			ID:        row.ID,
+			Name:      row.Name,
+			Personal:  row.PersonalUserID.Valid && row.PersonalUserID.UUID == userID,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return orgs, nil
+}
+
+func (s *analyticsService) GetOrganizationMembers(ctx context.Context, userID, orgID uuid.UUID) ([]types.Member, error) {
+	if _, err := s.authorizeOrganization(ctx, userID, orgID, roleViewer); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]types.Member, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, types.Member{
+			UserID:    row.UserID,
+			Email:     row.Email,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return members, nil
+}
+
+// SetOrganizationMember adds a user to an organization, or changes their role
+// in it. Admins manage the members below owner, and only owners make or
+// unmake owners.
+func (s *analyticsService) SetOrganizationMember(ctx context.Context, data types.OrganizationMemberPayload) (*types.Member, error) {
+	if _, ok := roleRanks[data.Role]; !ok {
+		return nil, ErrInvalidOrganizationRole
+	}
+	userRole, err := s.authorizeOrganization(ctx, data.UserID, data.OrgID, roleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.Querier.GetUserByEmail(ctx, data.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	currentRole, err := s.Querier.GetOrganizationMember(ctx, database.GetOrganizationMemberParams{OrgID: data.OrgID, UserID: user.ID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if (data.Role == roleOwner || currentRole == roleOwner) && userRole != roleOwner {
+		return nil, ErrOrganizationForbidden
+	}
+	if currentRole == roleOwner && data.Role != roleOwner {
+		if err := s.checkOtherOwner(ctx, data.OrgID); err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := s.Querier.UpsertOrganizationMember(ctx, database.UpsertOrganizationMemberParams{
+		OrgID:  data.OrgID,
+		UserID: user.ID,
+		Role:   data.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Member{
+		UserID:    row.UserID,
+		Email:     user.Email,
+		Role:      row.Role,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
+
+// RemoveOrganizationMember removes a member from an organization, along with
+// their access to its apps. The apps they owned go to another owner of the
+// organization, so that they stay in it. Admins remove anyone but owners, and
+// the other members only themselves.
+func (s *analyticsService) RemoveOrganizationMember(ctx context.Context, userID, orgID, memberID uuid.UUID) error {
+	role := roleAdmin
+	if memberID == userID {
+		role = roleViewer
+	}
+	userRole, err := s.authorizeOrganization(ctx, userID, orgID, role)
+	if err != nil {
+		return err
+	}
+
+	memberRole, err := s.Querier.GetOrganizationMember(ctx, database.GetOrganizationMemberParams{OrgID: orgID, UserID: memberID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if memberRole == roleOwner {
+		if userRole != roleOwner {
+			return ErrOrganizationForbidden
+		}
+		if err := s.checkOtherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.Querier.DeleteOrganizationMember(ctx, database.DeleteOrganizationMemberParams{OrgID: orgID, UserID: memberID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrMemberNotFound
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+// inOrganization matches the role lookups in the organization with orgID.
+func inOrganization(orgID uuid.UUID) func(database.GetOrganizationMemberParams) bool {
+	return func(arg database.GetOrganizationMemberParams) bool { return arg.OrgID == orgID }
+}
+
+func (suite *ServiceSuite) TestGetOrganizations() {
+	userID, personalID, teamID := uuid.New(), uuid.New(), uuid.New()
+	suite.mockRepo.EXPECT().GetOrganizations(mock.Anything, userID).Return([]database.GetOrganizationsRow{
+		{ID: personalID, Name: "Personal", PersonalUserID: uuid.NullUUID{UUID: userID, Valid: true}, Role: roleOwner},
+		{ID: teamID, Name: "Acme", Role: roleEditor, CreatedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+	}, nil).Once()
+
+	orgs, err := suite.service.GetOrganizations(suite.ctx, userID)
+	suite.NoError(err)
+	suite.Len(orgs, 2)
+	suite.True(orgs[0].Personal)
+	suite.False(orgs[1].Personal)
+	suite.Equal(roleEditor, orgs[1].Role)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestOrganizationRoles() {
+	orgID, trackingID, userID := uuid.New(), uuid.New(), uuid.New()
+
+	// members of the organization have their role in its apps
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: uuid.New(), TrackingID: trackingID, OrgID: orgID}, nil).Once()
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: userID}).Return(roleEditor, nil).Once()
+	app, err := suite.service.ValidateAppAccess(suite.ctx, userID, trackingID)
+	suite.NoError(err)
+	suite.Equal(roleEditor, app.Role)
+	suite.Equal(orgID, app.OrgID)
+
+	suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, database.GetOrganizationMemberParams{OrgID: orgID, UserID: userID}).Return("", pgx.ErrNoRows).Once()
+	_, err = suite.service.GetOrganizationMembers(suite.ctx, userID, orgID)
+	suite.ErrorIs(err, ErrOrganizationNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestSetOrganizationMember() {
+	orgID, ownerID, adminID, memberID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	roles := map[uuid.UUID]string{ownerID: roleOwner, adminID: roleAdmin}
+	suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, mock.MatchedBy(inOrganization(orgID))).RunAndReturn(func(_ context.Context, arg database.GetOrganizationMemberParams) (string, error) {
+		if role, ok := roles[arg.UserID]; ok {
+			return role, nil
+		}
+		return "", pgx.ErrNoRows
+	})
+	suite.mockRepo.EXPECT().GetUserByEmail(mock.Anything, "ada@example.com").Return(database.User{ID: memberID, Email: "ada@example.com"}, nil)
+	suite.mockRepo.EXPECT().GetUserByEmail(mock.Anything, "owner@example.com").Return(database.User{ID: ownerID, Email: "owner@example.com"}, nil)
+	payload := func(userID uuid.UUID, email, role string) types.OrganizationMemberPayload {
+		return types.OrganizationMemberPayload{UserID: userID, OrgID: orgID, Email: email, Role: role}
+	}
+
+	_, err := suite.service.SetOrganizationMember(suite.ctx, payload(adminID, "ada@example.com", "superuser"))
+	suite.ErrorIs(err, ErrInvalidOrganizationRole)
+
+	// only owners make owners
+	_, err = suite.service.SetOrganizationMember(suite.ctx, payload(adminID, "ada@example.com", roleOwner))
+	suite.ErrorIs(err, ErrOrganizationForbidden)
+
+	suite.mockRepo.EXPECT().GetUserByEmail(mock.Anything, "nobody@example.com").Return(database.User{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.SetOrganizationMember(suite.ctx, payload(adminID, "nobody@example.com", roleViewer))
+	suite.ErrorIs(err, ErrUserNotFound)
+
+	suite.mockRepo.EXPECT().UpsertOrganizationMember(mock.Anything, database.UpsertOrganizationMemberParams{OrgID: orgID, UserID: memberID, Role: roleEditor}).
+		Return(database.OrganizationMember{OrgID: orgID, UserID: memberID, Role: roleEditor}, nil).Once()
+	member, err := suite.service.SetOrganizationMember(suite.ctx, payload(adminID, "ada@example.com", roleEditor))
+	suite.NoError(err)
+	suite.Equal("ada@example.com", member.Email)
+	suite.Equal(roleEditor, member.Role)
+
+	// the last owner can't step down
+	suite.mockRepo.EXPECT().CountOrganizationOwners(mock.Anything, orgID).Return(1, nil).Once()
+	_, err = suite.service.SetOrganizationMember(suite.ctx, payload(ownerID, "owner@example.com", roleAdmin))
+	suite.ErrorIs(err, ErrLastOwner)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestRemoveOrganizationMember() {
+	orgID, ownerID, adminID, viewerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	roles := map[uuid.UUID]string{ownerID: roleOwner, adminID: roleAdmin, viewerID: roleViewer}
+	suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, mock.MatchedBy(inOrganization(orgID))).RunAndReturn(func(_ context.Context, arg database.GetOrganizationMemberParams) (string, error) {
+		if role, ok := roles[arg.UserID]; ok {
+			return role, nil
+		}
+		return "", pgx.ErrNoRows
+	})
+
+	// viewers only remove themselves, and admins can't remove owners
+	suite.ErrorIs(suite.service.RemoveOrganizationMember(suite.ctx, viewerID, orgID, adminID), ErrOrganizationForbidden)
+	suite.ErrorIs(suite.service.RemoveOrganizationMember(suite.ctx, adminID, orgID, ownerID), ErrOrganizationForbidden)
+	suite.ErrorIs(suite.service.RemoveOrganizationMember(suite.ctx, adminID, orgID, uuid.New()), ErrMemberNotFound)
+
+	suite.mockRepo.EXPECT().DeleteOrganizationMember(mock.Anything, database.DeleteOrganizationMemberParams{OrgID: orgID, UserID: viewerID}).Return(viewerID, nil).Twice()
+	suite.NoError(suite.service.RemoveOrganizationMember(suite.ctx, viewerID, orgID, viewerID))
+	suite.NoError(suite.service.RemoveOrganizationMember(suite.ctx, adminID, orgID, viewerID))
+
+	// the last owner can't leave
+	suite.mockRepo.EXPECT().CountOrganizationOwners(mock.Anything, orgID).Return(1, nil).Once()
+	suite.ErrorIs(suite.service.RemoveOrganizationMember(suite.ctx, ownerID, orgID, ownerID), ErrLastOwner)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
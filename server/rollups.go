@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// rollupMinRange is the shortest range read from the hourly rows kept with
+// the sketches, pageviews from their counts and exact visitors from the hourly
+// visitors. Shorter ranges are cheap enough over raw events, and are usually
+// the most recent ones.
+const rollupMinRange = 7 * 24 * time.Hour
+
+// usesRollups reports whether exact visitors for data are counted from the
+// hourly visitors up to the sketch watermark, and from raw events after it.
+func usesRollups(data types.RequestPayload, series bool) bool {
+	return data.Precision != precisionApprox && longRange(data) && coveredByHours(data, series)
+}
+
+func longRange(data types.RequestPayload) bool {
+	return data.EndDate.Time.Sub(data.StartDate.Time) >= rollupMinRange
+}
+
+// coveredByHours reports whether a query over data can be answered from hourly
+// buckets. Buckets don't hold event details, so filtered queries always run
+// over raw events, and the range has to start and end on the hour. Time series
+// buckets also have to be made of whole hours in the app's timezone.
+func coveredByHours(data types.RequestPayload, series bool) bool {
+	if len(data.Filters) > 0 || !data.StartDate.Valid || !data.EndDate.Valid {
+		return false
+	}
+
+	start, end := data.StartDate.Time, data.EndDate.Time
+	if !alignedTo(start, time.Hour) || !alignedTo(end, time.Hour) {
+		return false
+	}
+
+	location, err := time.LoadLocation(data.Timezone)
+	if err != nil {
+		return false
+	}
+
+	if series {
+		if data.BucketSize == "1 minute" {
+			return false
+		}
+		// local hours have to start on UTC hours for hourly rows to add up to them
+		if utcOffset(start, location)%3600 != 0 || utcOffset(end, location)%3600 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// alignedTo reports whether t falls on a UTC boundary of d.
+func alignedTo(t time.Time, d time.Duration) bool {
+	return t.Equal(t.Truncate(d))
+}
+
+func utcOffset(t time.Time, location *time.Location) int {
+	_, offset := t.In(location).Zone()
+	return offset
+}
+
+func (s *analyticsService) getVisitors(ctx context.Context, params database.GetVisitorsParams, data types.RequestPayload) ([]database.GetVisitorsRow, error) {
+	if usesSketches(data, true) {
+		return s.estimateVisitors(ctx, data)
+	}
+	params.Rollup = usesRollups(data, true)
+	return s.Querier.GetVisitors(ctx, params)
+}
+
+// getPageViews reads long ranges from the hourly counts kept with the
+// sketches, which are exact, so only unique visitors depend on the precision.
+func (s *analyticsService) getPageViews(ctx context.Context, params database.GetPageViewsParams, data types.RequestPayload) ([]database.GetPageViewsRow, error) {
+	if usesSketches(data, true) || (longRange(data) && coveredByHours(data, true)) {
+		return s.sketchPageViews(ctx, data)
+	}
+	return s.Querier.GetPageViews(ctx, params)
+}
+
+// getOverview reads the overview from raw events. When visitors are estimated,
+// visitors, pageviews and events are taken from sketches instead, so that
+// hours past the data retention count, while visits and what is derived from
+// them can only count the events that are kept.
+func (s *analyticsService) getOverview(ctx context.Context, params database.GetOverviewParams, data types.RequestPayload) (*types.OverviewStats, error) {
+	row, err := s.Querier.GetOverview(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	overview := newOverviewStats(row)
+	if !usesSketches(data, false) {
+		return overview, nil
+	}
+
+	totals, err := s.sketchTotals(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	overview.Visitors = int(totals.estimate())
+	overview.PageViews, overview.Events = int(totals.pageviews), int(totals.events)
+	return overview, nil
+}
+
+// breakdown runs query, which reads long ranges from the hourly visitors and
+// others from raw events, or estimates the breakdown of dimension from
+// sketches when visitors are estimated. row shapes the estimated rows like the
+// rows of query.
+func breakdown[P, R any](ctx context.Context, s *analyticsService, dimension string, data types.RequestPayload, page sketchPage, query func(context.Context, P) ([]R, error), params P, row func(sketchRow) R) ([]R, error) {
+	if !usesSketches(data, false) {
+		return query(ctx, params)
+	}
+
+	rows, err := s.estimateBreakdown(ctx, dimension, data, page)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]R, 0, len(rows))
+	for _, r := range rows {
+		stats = append(stats, row(r))
+	}
+	return stats, nil
+}
+
+func (s *analyticsService) getReferrals(ctx context.Context, params database.GetReferralsParams, data types.RequestPayload) ([]database.GetReferralsRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "referrer", data, page, s.Querier.GetReferrals, params, func(row sketchRow) database.GetReferralsRow {
+		return database.GetReferralsRow{
+			Referrer: &row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
+
+func (s *analyticsService) getPages(ctx context.Context, params database.GetPagesParams, data types.RequestPayload) ([]database.GetPagesRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "url", data, page, s.Querier.GetPages, params, func(row sketchRow) database.GetPagesRow {
+		return database.GetPagesRow{
+			Url: &row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
+
+func (s *analyticsService) getBrowsers(ctx context.Context, params database.GetBrowsersParams, data types.RequestPayload) ([]database.GetBrowsersRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "browser", data, page, s.Querier.GetBrowsers, params, func(row sketchRow) database.GetBrowsersRow {
+		return database.GetBrowsersRow{
+			Browser: row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
+
+func (s *analyticsService) getCountries(ctx context.Context, params database.GetCountriesParams, data types.RequestPayload) ([]database.GetCountriesRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "country", data, page, s.Querier.GetCountries, params, func(row sketchRow) database.GetCountriesRow {
+		return database.GetCountriesRow{
+			Country: row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
+
+func (s *analyticsService) getDevices(ctx context.Context, params database.GetDevicesParams, data types.RequestPayload) ([]database.GetDevicesRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "device", data, page, s.Querier.GetDevices, params, func(row sketchRow) database.GetDevicesRow {
+		return database.GetDevicesRow{
+			Device: row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
+
+func (s *analyticsService) getOS(ctx context.Context, params database.GetOSParams, data types.RequestPayload) ([]database.GetOSRow, error) {
+	params.Rollup = usesRollups(data, false)
+	page := sketchPage{params.Sort, params.Keys, params.PageOffset, params.PageLimit, params.IncludeOther}
+	return breakdown(ctx, s, "operating_system", data, page, s.Querier.GetOS, params, func(row sketchRow) database.GetOSRow {
+		return database.GetOSRow{
+			OperatingSystem: row.value, Visitors: row.visitors, Pageviews: row.pageviews, Events: row.events,
+			TotalVisitors: row.totalVisitors, TotalRows: row.totalRows, Position: row.position, Other: row.other,
+		}
+	})
+}
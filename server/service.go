@@ -2,35 +2,75 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mileusna/useragent"
 	"github.com/oschwald/geoip2-golang"
 	"github.com/spf13/viper"
 
+	"github.com/ScMofeoluwa/minalytics/cache"
 	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/export"
 	types "github.com/ScMofeoluwa/minalytics/shared"
 )
 
 var ErrInvalidToken = errors.New("invalid token")
 var ErrAppNotFound = errors.New("app not found")
 var ErrAppExists = errors.New("app already exists")
+var ErrRetentionDisabled = errors.New("retention tracking is not enabled for this app")
+var ErrInvalidComparison = errors.New("invalid comparison range")
+var ErrInvalidRegex = errors.New("invalid regex")
+
+// flowNodesPerStep caps how many distinct pages or events are kept at each
+// step of a user flow, the long tail is dropped along with its links.
+const flowNodesPerStep = 10
+
+// realtimeTopRows caps how many pages and sources the realtime report returns.
+const realtimeTopRows = 10
+
+// otherRowName labels the row that aggregates everything after the requested page of a breakdown.
+const otherRowName = "Other"
 
 type analyticsService struct {
 	Querier database.Querier
 	GeoDB   *geoip2.Reader
+	// DB streams exports, which read events through a cursor the Querier
+	// can't hold.
+	DB     export.DB
+	broker *eventBroker
+	// shareAttempts limits the wrong passwords of share links.
+	shareAttempts *shareAttempts
 }
 
-func NewAnalyticsService(querier database.Querier, geoDB *geoip2.Reader) types.AnalyticsService {
-	return &analyticsService{
+// NewAnalyticsService creates the service, serving reports from resultCache
+// when it isn't nil.
+func NewAnalyticsService(querier database.Querier, geoDB *geoip2.Reader, resultCache cache.Cache, db export.DB) types.AnalyticsService {
+	service := &analyticsService{
 		Querier: querier,
 		GeoDB:   geoDB,
+		DB:      db,
+		broker:  newEventBroker(),
+		// wrong passwords are counted per server
+		shareAttempts: newShareAttempts(),
+	}
+	if resultCache == nil {
+		return service
 	}
+	return &cachedAnalyticsService{AnalyticsService: service, cache: resultCache, now: time.Now}
 }
 
 func (s *analyticsService) SignIn(ctx context.Context, email string) (string, error) {
@@ -42,12 +82,20 @@ func (s *analyticsService) SignIn(ctx context.Context, email string) (string, er
 }
 
 func (s *analyticsService) TrackEvent(ctx context.Context, data types.EventPayload) error {
-	if _, err := s.Querier.GetAppByTrackingID(ctx, data.Tracking.TrackingID); err != nil {
+	app, err := s.Querier.GetAppByTrackingID(ctx, data.Tracking.TrackingID)
+	if err != nil {
 		return err
 	}
 
 	uaDetails := s.ParseUserAgent(data.Tracking.Ua)
 
+	// the long-lived identifier is only kept for apps that opted in to retention tracking
+	var persistentID *string
+	if app.RetentionTracking && data.Tracking.PersistentID != "" {
+		hashed := hashPersistentID(data.Tracking.TrackingID, data.Tracking.PersistentID)
+		persistentID = &hashed
+	}
+
 	params := database.CreateEventParams{
 		VisitorID:       data.Tracking.VisitorID,
 		TrackingID:      data.Tracking.TrackingID,
@@ -59,18 +107,44 @@ func (s *analyticsService) TrackEvent(ctx context.Context, data types.EventPaylo
 		Device:          uaDetails.Device,
 		OperatingSystem: uaDetails.OperatingSystem,
 		Details:         data.Tracking.Details,
+		PersistentID:    persistentID,
 	}
 
 	if err := s.Querier.CreateEvent(ctx, params); err != nil {
 		return err
 	}
+
+	// a malformed url is still stored, it just has no path on the live stream
+	path, _ := pagePath(data.Tracking.Url)
+	s.broker.Publish(data.Tracking.TrackingID, data.Tracking.VisitorID, types.LiveEvent{
+		Type:      data.Type,
+		Path:      path,
+		Referrer:  data.Tracking.Referrer,
+		Country:   data.Tracking.Country,
+		Browser:   uaDetails.Browser,
+		Device:    uaDetails.Device,
+		OS:        uaDetails.OperatingSystem,
+		Timestamp: time.Now().UTC(),
+	})
 	return nil
 }
 
-func (s *analyticsService) CreateApp(ctx context.Context, userID uuid.UUID, name string) (*types.App, error) {
+// CreateApp creates an app in an organization the user is an admin of, or in
+// their personal organization when orgID is uuid.Nil.
+func (s *analyticsService) CreateApp(ctx context.Context, userID, orgID uuid.UUID, name string) (*types.App, error) {
+	var err error
+	if orgID == uuid.Nil {
+		orgID, err = s.Querier.GetOrCreatePersonalOrganization(ctx, userID)
+	} else {
+		_, err = s.authorizeOrganization(ctx, userID, orgID, roleAdmin)
+	}
+	if err != nil {
+		return &types.App{}, err
+	}
+
 	params := database.CheckAppExistsParams{
-		UserID: userID,
-		Name:   name,
+		OrgID: orgID,
+		Name:  name,
 	}
 	if app, err := s.Querier.CheckAppExists(ctx, params); err == nil && app.TrackingID != uuid.Nil {
 		return &types.App{}, ErrAppExists
@@ -78,6 +152,7 @@ func (s *analyticsService) CreateApp(ctx context.Context, userID uuid.UUID, name
 
 	createParams := database.CreateAppParams{
 		UserID: userID,
+		OrgID:  orgID,
 		Name:   name,
 	}
 
@@ -87,13 +162,21 @@ func (s *analyticsService) CreateApp(ctx context.Context, userID uuid.UUID, name
 	}
 
 	app := &types.App{
-		Name:       app_.Name,
-		TrackingID: app_.TrackingID,
-		CreatedAt:  app_.CreatedAt.Time,
+		Name:              app_.Name,
+		TrackingID:        app_.TrackingID,
+		OrgID:             app_.OrgID,
+		RetentionTracking: app_.RetentionTracking,
+		Timezone:          app_.Timezone,
+		DataRetention:     dataRetention(app_.DataRetention),
+		CreatedAt:         app_.CreatedAt.Time,
+		ImportedSince:     nullTime(app_.ImportedSince),
+		Role:              roleOwner,
 	}
 	return app, nil
 }
 
+// GetApps returns the apps the user is a member of, themselves or through the
+// organizations they belong to.
 func (s *analyticsService) GetApps(ctx context.Context, userID uuid.UUID) ([]types.App, error) {
 	apps_, err := s.Querier.GetApps(ctx, userID)
 	if err != nil {
@@ -103,22 +186,33 @@ func (s *analyticsService) GetApps(ctx context.Context, userID uuid.UUID) ([]typ
 	apps := make([]types.App, 0, len(apps_))
 	for _, row := range apps_ {
 		apps = append(apps, types.App{
-			Name:       row.Name,
-			CreatedAt:  row.CreatedAt.Time,
-			TrackingID: row.TrackingID,
+			Name:              row.Name,
+			CreatedAt:         row.CreatedAt.Time,
+			TrackingID:        row.TrackingID,
+			OrgID:             row.OrgID,
+			RetentionTracking: row.RetentionTracking,
+			Timezone:          row.Timezone,
+			DataRetention:     dataRetention(row.DataRetention),
+			ImportedSince:     nullTime(row.ImportedSince),
+			Role:              row.Role,
 		})
 	}
 	return apps, nil
 }
 
 func (s *analyticsService) UpdateApp(ctx context.Context, data types.AppPayload) (*types.App, error) {
-	if err := s.ValidateAppAccess(ctx, data.UserID, data.TrackingID); err != nil {
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleEditor); err != nil {
 		return &types.App{}, err
 	}
 
 	params := database.UpdateAppParams{
-		TrackingID: data.TrackingID,
-		Name:       data.Name,
+		TrackingID:        data.TrackingID,
+		RetentionTracking: data.RetentionTracking,
+		Timezone:          data.Timezone,
+		DataRetention:     data.DataRetention,
+	}
+	if data.Name != "" {
+		params.Name = &data.Name
 	}
 
 	app_, err := s.Querier.UpdateApp(ctx, params)
@@ -127,178 +221,537 @@ func (s *analyticsService) UpdateApp(ctx context.Context, data types.AppPayload)
 	}
 
 	app := &types.App{
-		Name:       app_.Name,
-		TrackingID: app_.TrackingID,
-		CreatedAt:  app_.CreatedAt.Time,
+		Name:              app_.Name,
+		TrackingID:        app_.TrackingID,
+		OrgID:             app_.OrgID,
+		RetentionTracking: app_.RetentionTracking,
+		Timezone:          app_.Timezone,
+		DataRetention:     dataRetention(app_.DataRetention),
+		CreatedAt:         app_.CreatedAt.Time,
+		ImportedSince:     nullTime(app_.ImportedSince),
 	}
 	return app, nil
 }
 
 func (s *analyticsService) DeleteApp(ctx context.Context, data types.AppPayload) error {
-	if err := s.ValidateAppAccess(ctx, data.UserID, data.TrackingID); err != nil {
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleOwner); err != nil {
 		return err
 	}
 
 	return s.Querier.DeleteApp(ctx, data.TrackingID)
 }
 
-func (s *analyticsService) GetReferrals(ctx context.Context, data types.RequestPayload) ([]types.ReferralStats, error) {
+func (s *analyticsService) GetReferrals(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.ReferralStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetReferralsParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetReferrals(ctx, params)
+	stats, err := s.getReferrals(ctx, params, current)
 	if err != nil {
-		return []types.ReferralStats{}, err
+		return nil, err
 	}
 
-	referralStats := make([]types.ReferralStats, 0, len(stats))
+	breakdown := newBreakdown[types.ReferralStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		referralStats = append(referralStats, types.ReferralStats{
-			Referrer:     *row.Referrer,
-			VisitorCount: int(row.VisitorCount),
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.ReferralStats{
+				Referrer:       otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
+		}
+
+		breakdown.Results = append(breakdown.Results, types.ReferralStats{
+			Referrer:       *row.Referrer,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, *row.Referrer)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getReferrals(ctx, params, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		previousValues[*row.Referrer] = int(row.Visitors)
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].Referrer]))
 	}
 
-	return referralStats, nil
+	return breakdown, nil
 }
 
-func (s *analyticsService) GetPages(ctx context.Context, data types.RequestPayload) ([]types.PageStats, error) {
+func (s *analyticsService) GetPages(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.PageStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetPagesParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetPages(ctx, params)
+	stats, err := s.getPages(ctx, params, current)
 	if err != nil {
-		return []types.PageStats{}, err
+		return nil, err
 	}
 
-	pageStats := make([]types.PageStats, 0, len(stats))
+	breakdown := newBreakdown[types.PageStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		url, err := url.Parse(*row.Url)
-		if err != nil {
-			return nil, err
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.PageStats{
+				Path:           otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
 		}
 
-		path := url.Path
-		if url.Path == "" {
-			path = "/"
+		path, err := pagePath(*row.Url)
+		if err != nil {
+			return nil, err
 		}
 
-		pageStats = append(pageStats, types.PageStats{
-			Path:         path,
-			VisitorCount: int(row.VisitorCount),
+		breakdown.Results = append(breakdown.Results, types.PageStats{
+			Path:           path,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, *row.Url)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getPages(ctx, params, previous)
+	if err != nil {
+		return nil, err
 	}
 
-	return pageStats, nil
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		path, err := pagePath(*row.Url)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := previousValues[path]; !exists {
+			previousValues[path] = int(row.Visitors)
+		}
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].Path]))
+	}
+
+	return breakdown, nil
 }
 
-func (s *analyticsService) GetBrowsers(ctx context.Context, data types.RequestPayload) ([]types.BrowserStats, error) {
+func (s *analyticsService) GetBrowsers(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.BrowserStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetBrowsersParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetBrowsers(ctx, params)
+	stats, err := s.getBrowsers(ctx, params, current)
 	if err != nil {
-		return []types.BrowserStats{}, err
+		return nil, err
 	}
 
-	browserStats := make([]types.BrowserStats, 0, len(stats))
+	breakdown := newBreakdown[types.BrowserStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		browserStats = append(browserStats, types.BrowserStats{
-			Browser:    row.Browser,
-			Percentage: int(row.Percentage),
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.BrowserStats{
+				Browser:        otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
+		}
+
+		breakdown.Results = append(breakdown.Results, types.BrowserStats{
+			Browser:        row.Browser,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, row.Browser)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getBrowsers(ctx, params, previous)
+	if err != nil {
+		return nil, err
 	}
 
-	return browserStats, nil
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		previousValues[row.Browser] = int(row.Visitors)
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].Browser]))
+	}
+
+	return breakdown, nil
 }
 
-func (s *analyticsService) GetCountries(ctx context.Context, data types.RequestPayload) ([]types.CountryStats, error) {
+func (s *analyticsService) GetCountries(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.CountryStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetCountriesParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetCountries(ctx, params)
+	stats, err := s.getCountries(ctx, params, current)
 	if err != nil {
-		return []types.CountryStats{}, err
+		return nil, err
 	}
 
-	countryStats := make([]types.CountryStats, 0, len(stats))
+	breakdown := newBreakdown[types.CountryStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		countryStats = append(countryStats, types.CountryStats{
-			Country:    row.Country,
-			Percentage: int(row.Percentage),
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.CountryStats{
+				Country:        otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
+		}
+
+		breakdown.Results = append(breakdown.Results, types.CountryStats{
+			Country:        row.Country,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, row.Country)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
 	}
 
-	return countryStats, nil
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getCountries(ctx, params, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		previousValues[row.Country] = int(row.Visitors)
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].Country]))
+	}
+
+	return breakdown, nil
 }
 
-func (s *analyticsService) GetDevices(ctx context.Context, data types.RequestPayload) ([]types.DeviceStats, error) {
+func (s *analyticsService) GetDevices(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.DeviceStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetDevicesParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetDevices(ctx, params)
+	stats, err := s.getDevices(ctx, params, current)
 	if err != nil {
-		return []types.DeviceStats{}, err
+		return nil, err
 	}
 
-	deviceStats := make([]types.DeviceStats, 0, len(stats))
+	breakdown := newBreakdown[types.DeviceStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		deviceStats = append(deviceStats, types.DeviceStats{
-			Device:     row.Device,
-			Percentage: int(row.Percentage),
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.DeviceStats{
+				Device:         otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
+		}
+
+		breakdown.Results = append(breakdown.Results, types.DeviceStats{
+			Device:         row.Device,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, row.Device)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getDevices(ctx, params, previous)
+	if err != nil {
+		return nil, err
 	}
 
-	return deviceStats, nil
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		previousValues[row.Device] = int(row.Visitors)
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].Device]))
+	}
+
+	return breakdown, nil
 }
 
-func (s *analyticsService) GetOS(ctx context.Context, data types.RequestPayload) ([]types.OSStats, error) {
+func (s *analyticsService) GetOS(ctx context.Context, data types.BreakdownPayload) (*types.Breakdown[types.OSStats], error) {
+	current, previous, err := resolveComparison(data.RequestPayload, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	current, previous, err = s.applyRetention(ctx, current, previous)
+	if err != nil {
+		return nil, err
+	}
+	data.Precision = current.Precision
+
+	filters, err := encodeFilters(current.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := breakdownPage(data)
 	params := database.GetOSParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
+		TrackingID:   current.TrackingID,
+		Filters:      filters,
+		StartDate:    current.StartDate,
+		EndDate:      current.EndDate,
+		Sort:         data.Sort,
+		PageOffset:   offset,
+		PageLimit:    limit,
+		IncludeOther: data.Other,
 	}
 
-	stats, err := s.Querier.GetOS(ctx, params)
+	stats, err := s.getOS(ctx, params, current)
 	if err != nil {
-		return []types.OSStats{}, err
+		return nil, err
 	}
 
-	osstats := make([]types.OSStats, 0, len(stats))
+	breakdown := newBreakdown[types.OSStats](data, len(stats))
+	keys := make([]string, 0, len(stats))
 	for _, row := range stats {
-		osstats = append(osstats, types.OSStats{
-			OS:         row.OperatingSystem,
-			Percentage: int(row.Percentage),
+		breakdown.Meta.Total, breakdown.Meta.TotalVisitors = int(row.TotalRows), int(row.TotalVisitors)
+		if row.Position == 0 {
+			// the totals row, there even when the page is past the last row
+			continue
+		}
+		if row.Other {
+			breakdown.Other = &types.OSStats{
+				OS:             otherRowName,
+				BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
+			}
+			continue
+		}
+
+		breakdown.Results = append(breakdown.Results, types.OSStats{
+			OS:             row.OperatingSystem,
+			BreakdownStats: newBreakdownStats(row.Visitors, row.Pageviews, row.Events, row.TotalVisitors),
 		})
+		keys = append(keys, row.OperatingSystem)
+	}
+
+	if current.Compare == "" || len(keys) == 0 {
+		return breakdown, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	params.Keys, params.IncludeOther = keys, false
+	previousStats, err := s.getOS(ctx, params, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	previousValues := make(map[string]int, len(previousStats))
+	for _, row := range previousStats {
+		previousValues[row.OperatingSystem] = int(row.Visitors)
+	}
+	for i := range breakdown.Results {
+		breakdown.Results[i].Comparison = newComparison(float64(breakdown.Results[i].Visitors), float64(previousValues[breakdown.Results[i].OS]))
 	}
 
-	return osstats, nil
+	return breakdown, nil
 }
 
 func (s *analyticsService) GetVisitors(ctx context.Context, data types.RequestPayload) ([]types.VisitorStats, error) {
+	// gap filling needs concrete bounds
+	now := time.Now()
+	data, previous, err := resolveComparison(resolveRange(data, now), now)
+	if err != nil {
+		return []types.VisitorStats{}, err
+	}
+	data, previous, err = s.applyRetention(ctx, data, previous)
+	if err != nil {
+		return []types.VisitorStats{}, err
+	}
+
+	location, err := time.LoadLocation(data.Timezone)
+	if err != nil {
+		return []types.VisitorStats{}, err
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return []types.VisitorStats{}, err
+	}
+
 	params := database.GetVisitorsParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
 		TimeBucket: data.BucketSize,
+		Timezone:   location.String(),
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
 	}
 
-	stats, err := s.Querier.GetVisitors(ctx, params)
+	stats, err := s.getVisitors(ctx, params, data)
 	if err != nil {
 		return []types.VisitorStats{}, err
 	}
@@ -306,23 +759,69 @@ func (s *analyticsService) GetVisitors(ctx context.Context, data types.RequestPa
 	visitorStats := make([]types.VisitorStats, 0, len(stats))
 	for _, row := range stats {
 		visitorStats = append(visitorStats, types.VisitorStats{
-			Time:     row.Time.Time.String(),
+			Time:     row.Time.Time.In(location).Format(time.RFC3339),
 			Visitors: int(row.Visitors),
 		})
 	}
 
+	if data.Compare == "" {
+		return visitorStats, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	previousStats, err := s.getVisitors(ctx, params, previous)
+	if err != nil {
+		return []types.VisitorStats{}, err
+	}
+
+	// buckets are aligned by their offset from the start of each range
+	previousRows := make(map[int64]database.GetVisitorsRow, len(previousStats))
+	for _, row := range previousStats {
+		previousRows[bucketOffset(row.Time.Time, previous.StartDate.Time, data.BucketSize, location)] = row
+	}
+	for i, row := range stats {
+		previousRow := previousRows[bucketOffset(row.Time.Time, data.StartDate.Time, data.BucketSize, location)]
+		visitorStats[i].Comparison = newComparison(float64(row.Visitors), float64(previousRow.Visitors))
+		if previousRow.Time.Valid {
+			visitorStats[i].Comparison.Time = previousRow.Time.Time.In(location).Format(time.RFC3339)
+		}
+	}
+
 	return visitorStats, nil
 }
 
 func (s *analyticsService) GetPageViews(ctx context.Context, data types.RequestPayload) ([]types.PageViewStats, error) {
+	// gap filling needs concrete bounds
+	now := time.Now()
+	data, previous, err := resolveComparison(resolveRange(data, now), now)
+	if err != nil {
+		return []types.PageViewStats{}, err
+	}
+	data, previous, err = s.applyRetention(ctx, data, previous)
+	if err != nil {
+		return []types.PageViewStats{}, err
+	}
+
+	location, err := time.LoadLocation(data.Timezone)
+	if err != nil {
+		return []types.PageViewStats{}, err
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return []types.PageViewStats{}, err
+	}
+
 	params := database.GetPageViewsParams{
-		TrackingID: data.TrackingID,
-		Column2:    data.StartDate,
-		Column3:    data.EndDate,
 		TimeBucket: data.BucketSize,
+		Timezone:   location.String(),
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
 	}
 
-	stats, err := s.Querier.GetPageViews(ctx, params)
+	stats, err := s.getPageViews(ctx, params, data)
 	if err != nil {
 		return []types.PageViewStats{}, err
 	}
@@ -330,14 +829,349 @@ func (s *analyticsService) GetPageViews(ctx context.Context, data types.RequestP
 	pageViewStats := make([]types.PageViewStats, 0, len(stats))
 	for _, row := range stats {
 		pageViewStats = append(pageViewStats, types.PageViewStats{
-			Time:  row.Time.Time.String(),
+			Time:  row.Time.Time.In(location).Format(time.RFC3339),
 			Views: int(row.Views),
 		})
 	}
 
+	if data.Compare == "" {
+		return pageViewStats, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	previousStats, err := s.getPageViews(ctx, params, previous)
+	if err != nil {
+		return []types.PageViewStats{}, err
+	}
+
+	// buckets are aligned by their offset from the start of each range
+	previousRows := make(map[int64]database.GetPageViewsRow, len(previousStats))
+	for _, row := range previousStats {
+		previousRows[bucketOffset(row.Time.Time, previous.StartDate.Time, data.BucketSize, location)] = row
+	}
+	for i, row := range stats {
+		previousRow := previousRows[bucketOffset(row.Time.Time, data.StartDate.Time, data.BucketSize, location)]
+		pageViewStats[i].Comparison = newComparison(float64(row.Views), float64(previousRow.Views))
+		if previousRow.Time.Valid {
+			pageViewStats[i].Comparison.Time = previousRow.Time.Time.In(location).Format(time.RFC3339)
+		}
+	}
+
 	return pageViewStats, nil
 }
 
+// GetEventTypes counts the events of each type in a range, most frequent
+// first. Comparisons aren't supported.
+func (s *analyticsService) GetEventTypes(ctx context.Context, data types.RequestPayload) ([]types.EventTypeStats, error) {
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetEventTypes(ctx, database.GetEventTypesParams{
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]types.EventTypeStats, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, types.EventTypeStats{
+			EventType: row.EventType,
+			Visitors:  int(row.Visitors),
+			Events:    int(row.Events),
+		})
+	}
+	return stats, nil
+}
+
+func (s *analyticsService) GetOverview(ctx context.Context, data types.RequestPayload) (*types.OverviewStats, error) {
+	data, previous, err := resolveComparison(data, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	data, previous, err = s.applyRetention(ctx, data, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	params := database.GetOverviewParams{
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+	}
+
+	overview, err := s.getOverview(ctx, params, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Compare == "" {
+		return overview, nil
+	}
+
+	params.StartDate, params.EndDate = previous.StartDate, previous.EndDate
+	previousOverview, err := s.getOverview(ctx, params, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	overview.Comparison = map[string]*types.Comparison{
+		"visitors":        newComparison(float64(overview.Visitors), float64(previousOverview.Visitors)),
+		"pageviews":       newComparison(float64(overview.PageViews), float64(previousOverview.PageViews)),
+		"visits":          newComparison(float64(overview.Visits), float64(previousOverview.Visits)),
+		"views_per_visit": newComparison(overview.ViewsPerVisit, previousOverview.ViewsPerVisit),
+		"bounce_rate":     newComparison(overview.BounceRate, previousOverview.BounceRate),
+		"visit_duration":  newComparison(overview.VisitDuration, previousOverview.VisitDuration),
+		"events":          newComparison(float64(overview.Events), float64(previousOverview.Events)),
+	}
+
+	return overview, nil
+}
+
+func (s *analyticsService) GetRealtime(ctx context.Context, trackingID uuid.UUID) (*types.RealtimeStats, error) {
+	now := time.Now()
+	visitors, err := s.Querier.GetActiveVisitors(ctx, database.GetActiveVisitorsParams{
+		TrackingID: trackingID,
+		Timestamp:  sql.NullTime{Time: now.Add(-realtimeWindow), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	window := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: trackingID,
+			StartDate:  sql.NullTime{Time: now.Add(-realtimeWindow), Valid: true},
+			EndDate:    sql.NullTime{Time: now, Valid: true},
+		},
+		Limit: realtimeTopRows,
+	}
+
+	pages, err := s.GetPages(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.GetReferrals(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.RealtimeStats{
+		CurrentVisitors: int(visitors),
+		Pages:           pages.Results,
+		Sources:         sources.Results,
+	}, nil
+}
+
+// SubscribeEvents subscribes to the live events of an app. The broker only
+// tracks apps while they have subscribers, so the visitors seen within the
+// realtime window are loaded for it to count from.
+func (s *analyticsService) SubscribeEvents(ctx context.Context, trackingID uuid.UUID) (<-chan types.LiveUpdate, func(), error) {
+	updates, unsubscribe := s.broker.Subscribe(trackingID)
+
+	rows, err := s.Querier.GetActiveVisitorIDs(ctx, database.GetActiveVisitorIDsParams{
+		TrackingID: trackingID,
+		Timestamp:  sql.NullTime{Time: time.Now().Add(-realtimeWindow), Valid: true},
+	})
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+
+	lastSeen := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		lastSeen[row.VisitorID] = row.LastSeen.Time
+	}
+	s.broker.Seed(trackingID, lastSeen)
+	return updates, unsubscribe, nil
+}
+
+func (s *analyticsService) GetRetention(ctx context.Context, data types.RetentionPayload) ([]types.RetentionCohort, error) {
+	app, err := s.Querier.GetAppByTrackingID(ctx, data.TrackingID)
+	if err != nil {
+		return []types.RetentionCohort{}, err
+	}
+	if !app.RetentionTracking {
+		return []types.RetentionCohort{}, ErrRetentionDisabled
+	}
+
+	startDate, endDate := data.StartDate, data.EndDate
+	if !startDate.Valid || !endDate.Valid {
+		now := time.Now().UTC()
+		endDate = sql.NullTime{Time: now, Valid: true}
+		if data.Period == "month" {
+			startDate = sql.NullTime{Time: now.AddDate(0, -12, 0), Valid: true}
+		} else {
+			startDate = sql.NullTime{Time: now.AddDate(0, 0, -12*7), Valid: true}
+		}
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return []types.RetentionCohort{}, err
+	}
+
+	location, err := time.LoadLocation(data.Timezone)
+	if err != nil {
+		return []types.RetentionCohort{}, err
+	}
+
+	params := database.GetRetentionCohortsParams{
+		Period:     data.Period,
+		Timezone:   location.String(),
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  startDate,
+		EndDate:    endDate,
+	}
+
+	stats, err := s.Querier.GetRetentionCohorts(ctx, params)
+	if err != nil {
+		return []types.RetentionCohort{}, err
+	}
+
+	// rows are ordered by cohort and period offset, so offset 0 carries the cohort size
+	cohorts := make([]types.RetentionCohort, 0)
+	for _, row := range stats {
+		cohort := row.Cohort.Time.In(location).String()
+		if len(cohorts) == 0 || cohorts[len(cohorts)-1].Cohort != cohort {
+			cohorts = append(cohorts, types.RetentionCohort{
+				Cohort:    cohort,
+				Visitors:  int(row.Visitors),
+				Retention: []types.RetentionPeriod{},
+			})
+		}
+
+		current := &cohorts[len(cohorts)-1]
+		percentage := 0.0
+		if current.Visitors > 0 {
+			percentage = float64(row.Visitors) * 100 / float64(current.Visitors)
+		}
+		current.Retention = append(current.Retention, types.RetentionPeriod{
+			Period:     int(row.PeriodOffset),
+			Visitors:   int(row.Visitors),
+			Percentage: percentage,
+		})
+	}
+
+	return cohorts, nil
+}
+
+func (s *analyticsService) GetUserFlow(ctx context.Context, data types.FlowPayload) (*types.FlowStats, error) {
+	minDepth, maxDepth := 0, data.Steps
+	if data.Direction == "previous" {
+		minDepth, maxDepth = -data.Steps, 0
+	}
+
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return &types.FlowStats{}, err
+	}
+
+	params := database.GetUserFlowParams{
+		TrackingID: data.TrackingID,
+		Filters:    filters,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+		Step:       data.Step,
+		MinDepth:   int32(minDepth),
+		MaxDepth:   int32(maxDepth),
+	}
+
+	stats, err := s.Querier.GetUserFlow(ctx, params)
+	if err != nil {
+		return &types.FlowStats{}, err
+	}
+
+	nodeID := func(depth int, step string) string {
+		return fmt.Sprintf("%d:%s", depth, step)
+	}
+
+	// every visitor has a single row per depth, so node counts are the sum of their incoming rows
+	nodes := make([]types.FlowNode, 0)
+	nodeIndex := make(map[string]int)
+	for _, row := range stats {
+		id := nodeID(int(row.Depth), row.Step)
+		if i, ok := nodeIndex[id]; ok {
+			nodes[i].Visitors += int(row.Visitors)
+			continue
+		}
+		nodeIndex[id] = len(nodes)
+		nodes = append(nodes, types.FlowNode{
+			ID:       id,
+			Step:     row.Step,
+			Depth:    int(row.Depth),
+			Visitors: int(row.Visitors),
+		})
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		return nodes[i].Visitors > nodes[j].Visitors
+	})
+
+	kept := make(map[string]bool)
+	flow := &types.FlowStats{Nodes: []types.FlowNode{}, Links: []types.FlowLink{}}
+	perDepth := make(map[int]int)
+	for _, node := range nodes {
+		if perDepth[node.Depth] >= flowNodesPerStep {
+			continue
+		}
+		perDepth[node.Depth]++
+		kept[node.ID] = true
+		flow.Nodes = append(flow.Nodes, node)
+	}
+
+	for _, row := range stats {
+		if row.PreviousStep == "" || int(row.Depth) == minDepth {
+			continue
+		}
+		source := nodeID(int(row.Depth)-1, row.PreviousStep)
+		target := nodeID(int(row.Depth), row.Step)
+		if !kept[source] || !kept[target] {
+			continue
+		}
+		flow.Links = append(flow.Links, types.FlowLink{
+			Source:   source,
+			Target:   target,
+			Visitors: int(row.Visitors),
+		})
+	}
+
+	return flow, nil
+}
+
+// ExportEvents streams the raw events of an app to w and returns how many were
+// written. Callers check the user can access the app.
+func (s *analyticsService) ExportEvents(ctx context.Context, data types.ExportPayload, w io.Writer) (int64, error) {
+	filters, err := encodeFilters(data.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	return export.Events(ctx, s.DB, export.Request{
+		TrackingID: data.TrackingID,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+		Filters:    filters,
+		Columns:    data.Columns,
+		Format:     export.Format(data.Format),
+	}, w)
+}
+
 func (s *analyticsService) ResolveGeoLocation(remoteAddr string) (*types.GeoLocation, error) {
 	ip := net.ParseIP(remoteAddr)
 	record, err := s.GeoDB.City(ip)
@@ -364,17 +1198,230 @@ func (s *analyticsService) ParseUserAgent(ua string) *types.UserAgentDetails {
 	}
 }
 
-func (s *analyticsService) ValidateAppAccess(ctx context.Context, userID, trackingID uuid.UUID) error {
-	app, err := s.Querier.GetAppByTrackingID(ctx, trackingID)
-	if err != nil {
-		return err
+// ValidateAppAccess returns the app if the user is a member of it, in any role.
+func (s *analyticsService) ValidateAppAccess(ctx context.Context, userID, trackingID uuid.UUID) (*types.App, error) {
+	return s.authorizeApp(ctx, userID, trackingID, roleViewer)
+}
+
+func newApp(row database.App) *types.App {
+	return &types.App{
+		Name:              row.Name,
+		TrackingID:        row.TrackingID,
+		OrgID:             row.OrgID,
+		RetentionTracking: row.RetentionTracking,
+		Timezone:          row.Timezone,
+		DataRetention:     dataRetention(row.DataRetention),
+		CreatedAt:         row.CreatedAt.Time,
+		ImportedSince:     nullTime(row.ImportedSince),
 	}
-	if app.UserID != userID {
-		return ErrAppNotFound
+}
+
+// ValidateRegexFilters checks that Postgres, which runs the regex filters,
+// compiles their patterns. Its regular expressions aren't Go's, so a pattern
+// one accepts may not compile in the other.
+func (s *analyticsService) ValidateRegexFilters(ctx context.Context, filters []types.Filter) error {
+	return validateRegexFilters(ctx, s.Querier, filters)
+}
+
+// invalidRegularExpression is the SQLSTATE of patterns Postgres can't compile.
+const invalidRegularExpression = "2201B"
+
+func validateRegexFilters(ctx context.Context, querier database.Querier, filters []types.Filter) error {
+	for _, filter := range filters {
+		if filter.Operator != "regex" || len(filter.Values) == 0 {
+			continue
+		}
+
+		err := querier.CheckRegex(ctx, filter.Values[0])
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == invalidRegularExpression {
+			return fmt.Errorf("%w for %q filter: %s", ErrInvalidRegex, filter.Dimension, pgErr.Message)
+		}
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// encodeFilters serializes filters into the JSON array understood by the
+// event_matches_filters SQL function. No filters encode to NULL, which matches every event.
+func encodeFilters(filters []types.Filter) ([]byte, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(filters)
+}
+
+// resolveComparison returns the payload with an explicit current range and a copy
+// of it covering the range to compare against. Without a compare option the
+// payload is returned unchanged.
+func resolveComparison(data types.RequestPayload, now time.Time) (types.RequestPayload, types.RequestPayload, error) {
+	if data.Compare == "" {
+		return data, data, nil
+	}
+
+	// the default range needs concrete bounds to be shifted
+	data = resolveRange(data, now)
+
+	start, end := data.StartDate.Time, data.EndDate.Time
+	previous := data
+	switch data.Compare {
+	case "previous_period":
+		// whole calendar months are compared with the months before them rather
+		// than the same number of days, so March is compared with all of February
+		if months := calendarMonths(start, end); months > 0 {
+			previous.StartDate = sql.NullTime{Time: start.AddDate(0, -months, 0), Valid: true}
+			previous.EndDate = sql.NullTime{Time: start, Valid: true}
+		} else {
+			previous.StartDate = sql.NullTime{Time: start.Add(-end.Sub(start)), Valid: true}
+			previous.EndDate = sql.NullTime{Time: start, Valid: true}
+		}
+	case "year_over_year":
+		previous.StartDate = sql.NullTime{Time: start.AddDate(-1, 0, 0), Valid: true}
+		previous.EndDate = sql.NullTime{Time: end.AddDate(-1, 0, 0), Valid: true}
+	case "custom":
+		if !data.CompareStartDate.Valid || !data.CompareEndDate.Valid {
+			return data, data, ErrInvalidComparison
+		}
+		previous.StartDate, previous.EndDate = data.CompareStartDate, data.CompareEndDate
+	default:
+		return data, data, ErrInvalidComparison
+	}
+
+	return data, previous, nil
+}
+
+// resolveRange replaces the default range, the last 24 hours, with concrete bounds.
+func resolveRange(data types.RequestPayload, now time.Time) types.RequestPayload {
+	if !data.StartDate.Valid || !data.EndDate.Valid {
+		data.StartDate = sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true}
+		data.EndDate = sql.NullTime{Time: now, Valid: true}
+	}
+	return data
+}
+
+// calendarMonths returns how many whole calendar months [start, end) spans, or
+// 0 when either bound isn't midnight on the first of a month.
+func calendarMonths(start, end time.Time) int {
+	isMonthStart := func(t time.Time) bool {
+		return t.Day() == 1 && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+	}
+	if !isMonthStart(start) || !isMonthStart(end) {
+		return 0
+	}
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+}
+
+// bucketOffset returns the index of the bucket containing t, counted from the
+// bucket containing start. Days, weeks and months follow the calendar in location,
+// matching how time_bucket_gapfill aligns buckets to a timezone.
+func bucketOffset(t, start time.Time, bucketSize string, location *time.Location) int64 {
+	t, start = t.In(location), start.In(location)
+	switch bucketSize {
+	case "1 minute":
+		return int64(t.Sub(start.Truncate(time.Minute)) / time.Minute)
+	case "1 hour":
+		startHour := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, location)
+		return int64(t.Sub(startHour) / time.Hour)
+	case "1 month":
+		return int64((t.Year()-start.Year())*12 + int(t.Month()-start.Month()))
+	}
+
+	days := calendarDays(start, t)
+	if bucketSize == "1 week" {
+		// weeks start on Monday, like time_bucket's
+		days += (int(start.Weekday()) + 6) % 7
+		return int64(math.Floor(float64(days) / 7))
+	}
+	return int64(days)
+}
+
+// calendarDays counts the calendar days between the dates of from and to,
+// ignoring the time of day and daylight saving changes.
+func calendarDays(from, to time.Time) int {
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDay.Sub(fromDay) / (24 * time.Hour))
+}
+
+// breakdownPage converts the requested page into the offset and limit pushed down to SQL.
+func breakdownPage(data types.BreakdownPayload) (offset, limit int64) {
+	page := max(data.Page, 1)
+	return int64((page - 1) * data.Limit), int64(data.Limit)
+}
+
+func newBreakdown[T any](data types.BreakdownPayload, size int) *types.Breakdown[T] {
+	return &types.Breakdown[T]{
+		Results: make([]T, 0, size),
+		Meta: types.BreakdownMeta{
+			Page:      max(data.Page, 1),
+			Limit:     data.Limit,
+			Precision: breakdownPrecision(data.RequestPayload),
+		},
+	}
+}
+
+func newBreakdownStats(visitors, pageviews, events, totalVisitors int64) types.BreakdownStats {
+	stats := types.BreakdownStats{
+		Visitors:  int(visitors),
+		Pageviews: int(pageviews),
+		Events:    int(events),
+	}
+	if totalVisitors > 0 {
+		stats.Percentage = float64(visitors) * 100 / float64(totalVisitors)
+	}
+	return stats
+}
+
+func newComparison(current, previous float64) *types.Comparison {
+	comparison := &types.Comparison{
+		Value:  previous,
+		Change: current - previous,
+	}
+	if previous != 0 {
+		percentage := (current - previous) * 100 / previous
+		comparison.PercentageChange = &percentage
+	}
+	return comparison
+}
+
+// newOverviewStats maps an overview row. Imported aggregates add to visitors
+// and pageviews, but have no visits, so the visit metrics only count events.
+func newOverviewStats(row database.GetOverviewRow) *types.OverviewStats {
+	overview := &types.OverviewStats{
+		Visitors:  int(row.Visitors + row.ImportedVisitors),
+		PageViews: int(row.Pageviews + row.ImportedPageviews),
+		Visits:    int(row.Visits),
+		Events:    int(row.Events),
+	}
+	if row.Visits > 0 {
+		visits := float64(row.Visits)
+		overview.ViewsPerVisit = float64(row.Pageviews) / visits
+		overview.BounceRate = float64(row.Bounces) * 100 / visits
+		overview.VisitDuration = float64(row.TotalDuration) / visits
+	}
+	return overview
+}
+
+func pagePath(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Path == "" {
+		return "/", nil
+	}
+	return parsed.Path, nil
+}
+
+// hashPersistentID scopes the browser identifier to a single app so the same
+// visitor can't be linked across apps.
+func hashPersistentID(trackingID uuid.UUID, persistentID string) string {
+	hash := sha256.Sum256([]byte(trackingID.String() + "|" + persistentID))
+	return hex.EncodeToString(hash[:])
+}
+
 func CreateJWT(userId string) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": userId,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,6 +14,8 @@ import (
 	types "github.com/ScMofeoluwa/minalytics/shared"
 	"github.com/go-faker/faker/v4"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/oschwald/geoip2-golang"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -23,6 +27,10 @@ type ServiceSuite struct {
 	geoDB    *geoip2.Reader
 	service  types.AnalyticsService
 	ctx      context.Context
+	// cutoffs are the retention cutoffs of apps with expired events
+	cutoffs map[uuid.UUID]time.Time
+	// imported are the imported aggregates of apps, by dimension
+	imported map[uuid.UUID]map[string][]database.GetImportedAggregatesRow
 }
 
 func (suite *ServiceSuite) SetupSuite() {
@@ -36,7 +44,26 @@ func (suite *ServiceSuite) SetupSuite() {
 	suite.geoDB = geoDB
 
 	suite.mockRepo = mocks.NewQuerier(suite.T())
-	suite.service = NewAnalyticsService(suite.mockRepo, geoDB)
+	suite.service = NewAnalyticsService(suite.mockRepo, geoDB, nil, nil)
+
+	// apps keep their raw events unless a test expires them
+	suite.cutoffs = make(map[uuid.UUID]time.Time)
+	suite.mockRepo.EXPECT().GetRetentionCutoff(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, trackingID uuid.UUID) (sql.NullTime, error) {
+		cutoff, expired := suite.cutoffs[trackingID]
+		return sql.NullTime{Time: cutoff, Valid: expired}, nil
+	}).Maybe()
+
+	// and have nothing imported unless a test imports aggregates
+	suite.imported = make(map[uuid.UUID]map[string][]database.GetImportedAggregatesRow)
+	suite.mockRepo.EXPECT().GetImportedAggregates(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, params database.GetImportedAggregatesParams) ([]database.GetImportedAggregatesRow, error) {
+		rows := []database.GetImportedAggregatesRow{}
+		for _, row := range suite.imported[params.TrackingID][params.Dimension] {
+			if !row.Bucket.Time.Before(params.StartDate.Time) && row.Bucket.Time.Before(params.EndDate.Time) {
+				rows = append(rows, row)
+			}
+		}
+		return rows, nil
+	}).Maybe()
 }
 
 func (suite *ServiceSuite) TearDownSuite() {
@@ -87,6 +114,52 @@ func (suite *ServiceSuite) TestTrackEvent() {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "persistent ID hashed for retention tracking apps",
+			data: types.EventPayload{
+				Type: "pageview",
+				Tracking: types.TrackingData{
+					TrackingID:   uuid.New(),
+					VisitorID:    faker.UUIDDigit(),
+					PersistentID: "persistent-id",
+					Ua:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3",
+					Url:          faker.URL(),
+					Referrer:     faker.URL(),
+					Country:      faker.GetCountryInfo().Name,
+					Details:      map[string]interface{}{},
+				},
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{RetentionTracking: true}, nil).Once()
+				suite.mockRepo.EXPECT().CreateEvent(mock.Anything, mock.MatchedBy(func(params database.CreateEventParams) bool {
+					return params.PersistentID != nil && len(*params.PersistentID) == 64
+				})).Return(nil).Once()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "persistent ID dropped when retention tracking is disabled",
+			data: types.EventPayload{
+				Type: "pageview",
+				Tracking: types.TrackingData{
+					TrackingID:   uuid.New(),
+					VisitorID:    faker.UUIDDigit(),
+					PersistentID: "persistent-id",
+					Ua:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3",
+					Url:          faker.URL(),
+					Referrer:     faker.URL(),
+					Country:      faker.GetCountryInfo().Name,
+					Details:      map[string]interface{}{},
+				},
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{}, nil).Once()
+				suite.mockRepo.EXPECT().CreateEvent(mock.Anything, mock.MatchedBy(func(params database.CreateEventParams) bool {
+					return params.PersistentID == nil
+				})).Return(nil).Once()
+			},
+			expectedErr: nil,
+		},
 		{
 			name: "app not found",
 			data: types.EventPayload{
@@ -144,9 +217,11 @@ func (suite *ServiceSuite) TestTrackEvent() {
 }
 
 func (suite *ServiceSuite) TestCreateApp() {
+	personalOrgID, orgID := uuid.New(), uuid.New()
 	testCases := []struct {
 		name        string
 		userID      uuid.UUID
+		orgID       uuid.UUID
 		appName     string
 		mockSetup   func()
 		expectedErr error
@@ -156,20 +231,52 @@ func (suite *ServiceSuite) TestCreateApp() {
 			userID:  uuid.New(),
 			appName: "Test App",
 			mockSetup: func() {
-				suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, mock.Anything).Return(database.App{}, nil).Once()
+				suite.mockRepo.EXPECT().GetOrCreatePersonalOrganization(mock.Anything, mock.Anything).Return(personalOrgID, nil).Once()
+				suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, database.CheckAppExistsParams{OrgID: personalOrgID, Name: "Test App"}).Return(database.App{}, nil).Once()
+				suite.mockRepo.EXPECT().CreateApp(mock.Anything, mock.MatchedBy(func(arg database.CreateAppParams) bool {
+					return arg.OrgID == personalOrgID
+				})).Return(database.App{
+					Name:       "Test App",
+					TrackingID: uuid.New(),
+					OrgID:      personalOrgID,
+					CreatedAt:  sql.NullTime{},
+				}, nil).Once()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "app created in an organization",
+			userID:  uuid.New(),
+			orgID:   orgID,
+			appName: "Test App",
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, mock.Anything).Return(roleAdmin, nil).Once()
+				suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, database.CheckAppExistsParams{OrgID: orgID, Name: "Test App"}).Return(database.App{}, nil).Once()
 				suite.mockRepo.EXPECT().CreateApp(mock.Anything, mock.Anything).Return(database.App{
 					Name:       "Test App",
 					TrackingID: uuid.New(),
+					OrgID:      orgID,
 					CreatedAt:  sql.NullTime{},
 				}, nil).Once()
 			},
 			expectedErr: nil,
 		},
+		{
+			name:    "editors of the organization can't create apps",
+			userID:  uuid.New(),
+			orgID:   orgID,
+			appName: "Test App",
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOrganizationMember(mock.Anything, mock.Anything).Return(roleEditor, nil).Once()
+			},
+			expectedErr: ErrOrganizationForbidden,
+		},
 		{
 			name:    "app already exists",
 			userID:  uuid.New(),
 			appName: "Test App",
 			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOrCreatePersonalOrganization(mock.Anything, mock.Anything).Return(personalOrgID, nil).Once()
 				suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, mock.Anything).Return(database.App{
 					Name:       "Test App",
 					TrackingID: uuid.New(),
@@ -183,6 +290,7 @@ func (suite *ServiceSuite) TestCreateApp() {
 			userID:  uuid.New(),
 			appName: "Test App",
 			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOrCreatePersonalOrganization(mock.Anything, mock.Anything).Return(personalOrgID, nil).Once()
 				suite.mockRepo.EXPECT().CheckAppExists(mock.Anything, mock.Anything).Return(database.App{}, nil).Once()
 				suite.mockRepo.EXPECT().CreateApp(mock.Anything, mock.Anything).Return(database.App{}, errors.New("create app failed")).Once()
 			},
@@ -193,7 +301,7 @@ func (suite *ServiceSuite) TestCreateApp() {
 	for _, tc := range testCases {
 		suite.Run(tc.name, func() {
 			tc.mockSetup()
-			app, err := suite.service.CreateApp(suite.ctx, tc.userID, tc.appName)
+			app, err := suite.service.CreateApp(suite.ctx, tc.userID, tc.orgID, tc.appName)
 			if tc.expectedErr != nil {
 				suite.Error(err)
 				suite.Equal(tc.expectedErr.Error(), err.Error())
@@ -202,6 +310,7 @@ func (suite *ServiceSuite) TestCreateApp() {
 			suite.NoError(err)
 			suite.Equal(tc.appName, app.Name)
 			suite.NotEmpty(app.TrackingID)
+			suite.NotEqual(uuid.Nil, app.OrgID)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -218,16 +327,18 @@ func (suite *ServiceSuite) TestGetApps() {
 			name:   "app successfully retrieved",
 			userID: uuid.New(),
 			mockSetup: func() {
-				suite.mockRepo.EXPECT().GetApps(mock.Anything, mock.Anything).Return([]database.App{
+				suite.mockRepo.EXPECT().GetApps(mock.Anything, mock.Anything).Return([]database.GetAppsRow{
 					{
 						Name:       faker.Name(),
 						TrackingID: uuid.New(),
 						CreatedAt:  sql.NullTime{},
+						Role:       roleOwner,
 					},
 					{
 						Name:       faker.Name(),
 						TrackingID: uuid.New(),
 						CreatedAt:  sql.NullTime{},
+						Role:       roleViewer,
 					},
 				}, nil).Once()
 			},
@@ -237,7 +348,7 @@ func (suite *ServiceSuite) TestGetApps() {
 			name:   "failed to fetch apps",
 			userID: uuid.New(),
 			mockSetup: func() {
-				suite.mockRepo.EXPECT().GetApps(mock.Anything, mock.Anything).Return([]database.App{}, errors.New("failed to fetch apps")).Once()
+				suite.mockRepo.EXPECT().GetApps(mock.Anything, mock.Anything).Return([]database.GetAppsRow{}, errors.New("failed to fetch apps")).Once()
 			},
 			expectedErr: errors.New("failed to fetch apps"),
 		},
@@ -353,26 +464,31 @@ func (suite *ServiceSuite) TestDeleteApp() {
 func (suite *ServiceSuite) TestGetReferrals() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "referrals successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetReferrals(mock.Anything, mock.Anything).Return([]database.GetReferralsRow{
 					{
-						Referrer:     stringPtr(faker.URL()),
-						VisitorCount: 10,
+						Referrer: stringPtr(faker.URL()),
+						Visitors: 10,
+						Position: 1,
 					},
 					{
-						Referrer:     stringPtr(faker.URL()),
-						VisitorCount: 20,
+						Referrer: stringPtr(faker.URL()),
+						Visitors: 20,
+						Position: 2,
 					},
 				}, nil).Once()
 			},
@@ -380,10 +496,13 @@ func (suite *ServiceSuite) TestGetReferrals() {
 		},
 		{
 			name: "failed to fetch referrals",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetReferrals(mock.Anything, mock.Anything).Return([]database.GetReferralsRow{}, errors.New("failed to fetch referrals")).Once()
@@ -402,7 +521,7 @@ func (suite *ServiceSuite) TestGetReferrals() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(referrals)
+			suite.NotEmpty(referrals.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -411,26 +530,31 @@ func (suite *ServiceSuite) TestGetReferrals() {
 func (suite *ServiceSuite) TestGetPages() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "pages successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetPages(mock.Anything, mock.Anything).Return([]database.GetPagesRow{
 					{
-						Url:          stringPtr(faker.URL()),
-						VisitorCount: 10,
+						Url:      stringPtr(faker.URL()),
+						Visitors: 10,
+						Position: 1,
 					},
 					{
-						Url:          stringPtr(faker.URL()),
-						VisitorCount: 20,
+						Url:      stringPtr(faker.URL()),
+						Visitors: 20,
+						Position: 2,
 					},
 				}, nil).Once()
 			},
@@ -438,10 +562,13 @@ func (suite *ServiceSuite) TestGetPages() {
 		},
 		{
 			name: "failed to fetch pages",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetPages(mock.Anything, mock.Anything).Return([]database.GetPagesRow{}, errors.New("failed to fetch pages")).Once()
@@ -460,7 +587,7 @@ func (suite *ServiceSuite) TestGetPages() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(pages)
+			suite.NotEmpty(pages.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -469,26 +596,31 @@ func (suite *ServiceSuite) TestGetPages() {
 func (suite *ServiceSuite) TestGetBrowsers() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "browsers successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetBrowsers(mock.Anything, mock.Anything).Return([]database.GetBrowsersRow{
 					{
-						Browser:    "Chrome",
-						Percentage: 50,
+						Browser:  "Chrome",
+						Visitors: 50,
+						Position: 1,
 					},
 					{
-						Browser:    "Firefox",
-						Percentage: 30,
+						Browser:  "Firefox",
+						Visitors: 30,
+						Position: 2,
 					},
 				}, nil).Once()
 			},
@@ -496,10 +628,13 @@ func (suite *ServiceSuite) TestGetBrowsers() {
 		},
 		{
 			name: "failed to fetch browsers",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetBrowsers(mock.Anything, mock.Anything).Return([]database.GetBrowsersRow{}, errors.New("failed to fetch browsers")).Once()
@@ -518,7 +653,7 @@ func (suite *ServiceSuite) TestGetBrowsers() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(browsers)
+			suite.NotEmpty(browsers.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -527,26 +662,57 @@ func (suite *ServiceSuite) TestGetBrowsers() {
 func (suite *ServiceSuite) TestGetCountries() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "countries successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetCountries(mock.Anything, mock.Anything).Return([]database.GetCountriesRow{
 					{
-						Country:    faker.GetCountryInfo().Name,
-						Percentage: 50,
+						Country:  faker.GetCountryInfo().Name,
+						Visitors: 50,
+						Position: 1,
 					},
 					{
-						Country:    faker.GetCountryInfo().Name,
-						Percentage: 30,
+						Country:  faker.GetCountryInfo().Name,
+						Visitors: 30,
+						Position: 2,
+					},
+				}, nil).Once()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "filters forwarded as JSON",
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+					Filters: []types.Filter{
+						{Dimension: "browser", Operator: "is", Values: []string{"Firefox"}},
+					},
+				},
+				Limit: 100,
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetCountries(mock.Anything, mock.MatchedBy(func(arg database.GetCountriesParams) bool {
+					return string(arg.Filters) == `[{"dimension":"browser","operator":"is","values":["Firefox"]}]`
+				})).Return([]database.GetCountriesRow{
+					{
+						Country:  faker.GetCountryInfo().Name,
+						Visitors: 100,
+						Position: 1,
 					},
 				}, nil).Once()
 			},
@@ -554,10 +720,13 @@ func (suite *ServiceSuite) TestGetCountries() {
 		},
 		{
 			name: "failed to fetch countries",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetCountries(mock.Anything, mock.Anything).Return([]database.GetCountriesRow{}, errors.New("failed to fetch countries")).Once()
@@ -576,7 +745,7 @@ func (suite *ServiceSuite) TestGetCountries() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(countries)
+			suite.NotEmpty(countries.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -585,26 +754,31 @@ func (suite *ServiceSuite) TestGetCountries() {
 func (suite *ServiceSuite) TestGetDevices() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "devices successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetDevices(mock.Anything, mock.Anything).Return([]database.GetDevicesRow{
 					{
-						Device:     "Desktop",
-						Percentage: 50,
+						Device:   "Desktop",
+						Visitors: 50,
+						Position: 1,
 					},
 					{
-						Device:     "Mobile",
-						Percentage: 30,
+						Device:   "Mobile",
+						Visitors: 30,
+						Position: 2,
 					},
 				}, nil).Once()
 			},
@@ -612,10 +786,13 @@ func (suite *ServiceSuite) TestGetDevices() {
 		},
 		{
 			name: "failed to fetch devices",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetDevices(mock.Anything, mock.Anything).Return([]database.GetDevicesRow{}, errors.New("failed to fetch devices")).Once()
@@ -634,7 +811,7 @@ func (suite *ServiceSuite) TestGetDevices() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(devices)
+			suite.NotEmpty(devices.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -643,26 +820,31 @@ func (suite *ServiceSuite) TestGetDevices() {
 func (suite *ServiceSuite) TestGetOS() {
 	testCases := []struct {
 		name        string
-		data        types.RequestPayload
+		data        types.BreakdownPayload
 		mockSetup   func()
 		expectedErr error
 	}{
 		{
 			name: "os successfully retrieved",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetOS(mock.Anything, mock.Anything).Return([]database.GetOSRow{
 					{
 						OperatingSystem: "Windows",
-						Percentage:      50,
+						Visitors:        50,
+						Position:        1,
 					},
 					{
 						OperatingSystem: "MacOS",
-						Percentage:      30,
+						Visitors:        30,
+						Position:        2,
 					},
 				}, nil).Once()
 			},
@@ -670,10 +852,13 @@ func (suite *ServiceSuite) TestGetOS() {
 		},
 		{
 			name: "failed to fetch os",
-			data: types.RequestPayload{
-				TrackingID: uuid.New(),
-				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
-				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			data: types.BreakdownPayload{
+				RequestPayload: types.RequestPayload{
+					TrackingID: uuid.New(),
+					StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+					EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+				},
+				Limit: 100,
 			},
 			mockSetup: func() {
 				suite.mockRepo.EXPECT().GetOS(mock.Anything, mock.Anything).Return([]database.GetOSRow{}, errors.New("failed to fetch os")).Once()
@@ -692,7 +877,7 @@ func (suite *ServiceSuite) TestGetOS() {
 				return
 			}
 			suite.NoError(err)
-			suite.NotEmpty(osStats)
+			suite.NotEmpty(osStats.Results)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -818,6 +1003,640 @@ func (suite *ServiceSuite) TestGetPageViews() {
 	}
 }
 
+func (suite *ServiceSuite) TestResolveComparison() {
+	date := func(year int, month time.Month, day int) sql.NullTime {
+		return sql.NullTime{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		data          types.RequestPayload
+		expectedStart sql.NullTime
+		expectedEnd   sql.NullTime
+		expectedErr   error
+	}{
+		{
+			name:          "previous period of the same length",
+			data:          types.RequestPayload{Compare: "previous_period", StartDate: date(2025, 3, 10), EndDate: date(2025, 3, 17)},
+			expectedStart: date(2025, 3, 3),
+			expectedEnd:   date(2025, 3, 10),
+		},
+		{
+			name:          "previous period of a whole month",
+			data:          types.RequestPayload{Compare: "previous_period", StartDate: date(2025, 3, 1), EndDate: date(2025, 4, 1)},
+			expectedStart: date(2025, 2, 1),
+			expectedEnd:   date(2025, 3, 1),
+		},
+		{
+			name:          "previous period of several months",
+			data:          types.RequestPayload{Compare: "previous_period", StartDate: date(2025, 1, 1), EndDate: date(2025, 4, 1)},
+			expectedStart: date(2024, 10, 1),
+			expectedEnd:   date(2025, 1, 1),
+		},
+		{
+			name:          "previous period of the default range",
+			data:          types.RequestPayload{Compare: "previous_period"},
+			expectedStart: sql.NullTime{Time: now.Add(-48 * time.Hour), Valid: true},
+			expectedEnd:   sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true},
+		},
+		{
+			name:          "year over year",
+			data:          types.RequestPayload{Compare: "year_over_year", StartDate: date(2025, 3, 1), EndDate: date(2025, 4, 1)},
+			expectedStart: date(2024, 3, 1),
+			expectedEnd:   date(2024, 4, 1),
+		},
+		{
+			name:          "custom range",
+			data:          types.RequestPayload{Compare: "custom", StartDate: date(2025, 3, 1), EndDate: date(2025, 3, 8), CompareStartDate: date(2024, 12, 24), CompareEndDate: date(2024, 12, 31)},
+			expectedStart: date(2024, 12, 24),
+			expectedEnd:   date(2024, 12, 31),
+		},
+		{
+			name:        "custom range without dates",
+			data:        types.RequestPayload{Compare: "custom", StartDate: date(2025, 3, 1), EndDate: date(2025, 3, 8)},
+			expectedErr: ErrInvalidComparison,
+		},
+		{
+			name:        "unsupported comparison",
+			data:        types.RequestPayload{Compare: "last_week"},
+			expectedErr: ErrInvalidComparison,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			_, previous, err := resolveComparison(tc.data, now)
+			if tc.expectedErr != nil {
+				suite.ErrorIs(err, tc.expectedErr)
+				return
+			}
+			suite.NoError(err)
+			suite.Equal(tc.expectedStart, previous.StartDate)
+			suite.Equal(tc.expectedEnd, previous.EndDate)
+		})
+	}
+}
+
+func (suite *ServiceSuite) TestBucketOffset() {
+	start := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		bucketSize string
+		bucket     time.Time
+		expected   int64
+	}{
+		{name: "minute", bucketSize: "1 minute", bucket: time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC), expected: 15},
+		{name: "hour", bucketSize: "1 hour", bucket: time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC), expected: 3},
+		{name: "day", bucketSize: "1 day", bucket: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), expected: 2},
+		// 2025-01-13 and 2025-01-27 are Mondays
+		{name: "week", bucketSize: "1 week", bucket: time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), expected: 2},
+		{name: "month", bucketSize: "1 month", bucket: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), expected: 2},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, bucketOffset(tc.bucket, start, tc.bucketSize, time.UTC))
+		})
+	}
+
+	suite.Run("day in a timezone", func() {
+		lagos, err := time.LoadLocation("Africa/Lagos")
+		suite.Require().NoError(err)
+
+		// 23:30 UTC is already the next day in Lagos
+		start := time.Date(2025, 3, 1, 0, 0, 0, 0, lagos)
+		bucket := time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)
+		suite.Equal(int64(1), bucketOffset(bucket, start, "1 day", lagos))
+		suite.Equal(int64(0), bucketOffset(bucket, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), "1 day", time.UTC))
+	})
+
+	suite.Run("week across a daylight saving change", func() {
+		newYork, err := time.LoadLocation("America/New_York")
+		suite.Require().NoError(err)
+
+		// clocks moved forward on 2025-03-09, both are Monday midnights
+		start := time.Date(2025, 3, 3, 0, 0, 0, 0, newYork)
+		bucket := time.Date(2025, 3, 10, 0, 0, 0, 0, newYork)
+		suite.Equal(int64(1), bucketOffset(bucket, start, "1 week", newYork))
+	})
+}
+
+func (suite *ServiceSuite) TestGetVisitorsComparison() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	data := types.RequestPayload{
+		TrackingID: uuid.New(),
+		BucketSize: "1 day",
+		StartDate:  sql.NullTime{Time: start, Valid: true},
+		EndDate:    sql.NullTime{Time: start.AddDate(0, 1, 0), Valid: true},
+		Compare:    "previous_period",
+	}
+
+	suite.mockRepo.EXPECT().GetVisitors(mock.Anything, mock.MatchedBy(func(arg database.GetVisitorsParams) bool {
+		return arg.TrackingID == data.TrackingID && arg.StartDate.Time.Equal(start)
+	})).Return([]database.GetVisitorsRow{
+		{Time: sql.NullTime{Time: start, Valid: true}, Visitors: 30},
+		{Time: sql.NullTime{Time: start.AddDate(0, 0, 1), Valid: true}, Visitors: 10},
+	}, nil).Once()
+	suite.mockRepo.EXPECT().GetVisitors(mock.Anything, mock.MatchedBy(func(arg database.GetVisitorsParams) bool {
+		return arg.TrackingID == data.TrackingID && arg.StartDate.Time.Equal(start.AddDate(0, -1, 0)) && arg.EndDate.Time.Equal(start)
+	})).Return([]database.GetVisitorsRow{
+		{Time: sql.NullTime{Time: start.AddDate(0, -1, 0), Valid: true}, Visitors: 20},
+	}, nil).Once()
+
+	visitors, err := suite.service.GetVisitors(suite.ctx, data)
+	suite.NoError(err)
+	suite.Len(visitors, 2)
+
+	suite.Equal("2025-03-01T00:00:00Z", visitors[0].Time)
+	suite.Equal("2025-02-01T00:00:00Z", visitors[0].Comparison.Time)
+	suite.Equal(20.0, visitors[0].Comparison.Value)
+	suite.Equal(10.0, visitors[0].Comparison.Change)
+	suite.InDelta(50.0, *visitors[0].Comparison.PercentageChange, 0.001)
+
+	// no visitors on the matching day of the previous period
+	suite.Equal(0.0, visitors[1].Comparison.Value)
+	suite.Equal(10.0, visitors[1].Comparison.Change)
+	suite.Nil(visitors[1].Comparison.PercentageChange)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetReferralsComparison() {
+	data := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: uuid.New(),
+			StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+			EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			Compare:    "year_over_year",
+		},
+		Limit: 2,
+		Other: true,
+	}
+	google, twitter := "google.com", "twitter.com"
+
+	suite.mockRepo.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(arg database.GetReferralsParams) bool {
+		return arg.Keys == nil
+	})).Return([]database.GetReferralsRow{
+		{Referrer: &google, Visitors: 40, TotalRows: 5, Position: 1},
+		{Referrer: &twitter, Visitors: 10, TotalRows: 5, Position: 2},
+		{Visitors: 6, TotalRows: 5, Position: 3, Other: true},
+	}, nil).Once()
+	suite.mockRepo.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(arg database.GetReferralsParams) bool {
+		return slices.Equal(arg.Keys, []string{google, twitter}) && !arg.IncludeOther
+	})).Return([]database.GetReferralsRow{
+		{Referrer: &google, Visitors: 50, Position: 1},
+	}, nil).Once()
+
+	referrals, err := suite.service.GetReferrals(suite.ctx, data)
+	suite.NoError(err)
+	suite.Len(referrals.Results, 2)
+	suite.Equal(50.0, referrals.Results[0].Comparison.Value)
+	suite.Equal(-10.0, referrals.Results[0].Comparison.Change)
+	suite.InDelta(-20.0, *referrals.Results[0].Comparison.PercentageChange, 0.001)
+	suite.Equal(0.0, referrals.Results[1].Comparison.Value)
+	suite.Nil(referrals.Results[1].Comparison.PercentageChange)
+	suite.Nil(referrals.Other.Comparison)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestValidateRegexFilters() {
+	filters := []types.Filter{
+		{Dimension: "country", Operator: "is", Values: []string{"Nigeria"}},
+		{Dimension: "page", Operator: "regex", Values: []string{`\mblog\M`}},
+	}
+	// only regex patterns are compiled, by Postgres
+	suite.mockRepo.EXPECT().CheckRegex(mock.Anything, `\mblog\M`).Return(nil).Once()
+	suite.NoError(suite.service.ValidateRegexFilters(suite.ctx, filters))
+
+	filters[1].Values = []string{"(?P<name>blog)"}
+	suite.mockRepo.EXPECT().CheckRegex(mock.Anything, "(?P<name>blog)").Return(&pgconn.PgError{Code: "2201B", Message: "invalid regular expression: quantifier operand invalid"}).Once()
+	err := suite.service.ValidateRegexFilters(suite.ctx, filters)
+	suite.ErrorIs(err, ErrInvalidRegex)
+	suite.ErrorContains(err, "quantifier operand invalid")
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetBreakdownPaging() {
+	data := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: uuid.New(),
+			StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+			EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+		},
+		Limit: 2,
+		Page:  3,
+		Sort:  "name:asc",
+		Other: true,
+	}
+
+	suite.mockRepo.EXPECT().GetBrowsers(mock.Anything, mock.MatchedBy(func(arg database.GetBrowsersParams) bool {
+		return arg.PageOffset == 4 && arg.PageLimit == 2 && arg.Sort == "name:asc" && arg.IncludeOther && arg.Keys == nil
+	})).Return([]database.GetBrowsersRow{
+		{TotalVisitors: 80, TotalRows: 9},
+		{Browser: "Edge", Visitors: 10, Pageviews: 25, Events: 2, TotalVisitors: 80, TotalRows: 9, Position: 5},
+		{Browser: "Firefox", Visitors: 8, Pageviews: 12, TotalVisitors: 80, TotalRows: 9, Position: 6},
+		{Browser: "", Visitors: 12, Pageviews: 30, Events: 4, TotalVisitors: 80, TotalRows: 9, Position: 7, Other: true},
+	}, nil).Once()
+
+	browsers, err := suite.service.GetBrowsers(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(types.BreakdownMeta{Total: 9, Page: 3, Limit: 2, TotalVisitors: 80, Precision: "exact"}, browsers.Meta)
+	suite.Equal([]types.BrowserStats{
+		{Browser: "Edge", BreakdownStats: types.BreakdownStats{Visitors: 10, Pageviews: 25, Events: 2, Percentage: 12.5}},
+		{Browser: "Firefox", BreakdownStats: types.BreakdownStats{Visitors: 8, Pageviews: 12, Percentage: 10}},
+	}, browsers.Results)
+	suite.Equal(&types.BrowserStats{Browser: "Other", BreakdownStats: types.BreakdownStats{Visitors: 12, Pageviews: 30, Events: 4, Percentage: 15}}, browsers.Other)
+
+	// a page past the last row is empty, but still has the totals
+	data.Page = 6
+	suite.mockRepo.EXPECT().GetBrowsers(mock.Anything, mock.MatchedBy(func(arg database.GetBrowsersParams) bool {
+		return arg.PageOffset == 10 && arg.Keys == nil
+	})).Return([]database.GetBrowsersRow{
+		{TotalVisitors: 80, TotalRows: 9},
+	}, nil).Once()
+
+	browsers, err = suite.service.GetBrowsers(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(types.BreakdownMeta{Total: 9, Page: 6, Limit: 2, TotalVisitors: 80, Precision: "exact"}, browsers.Meta)
+	suite.Empty(browsers.Results)
+	suite.Nil(browsers.Other)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetEventTypes() {
+	data := types.RequestPayload{
+		TrackingID: uuid.New(),
+		StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+		EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	suite.mockRepo.EXPECT().GetEventTypes(mock.Anything, database.GetEventTypesParams{
+		TrackingID: data.TrackingID,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+	}).Return([]database.GetEventTypesRow{
+		{EventType: "pageview", Visitors: 8, Events: 20},
+		{EventType: "signup", Visitors: 2, Events: 2},
+	}, nil).Once()
+
+	stats, err := suite.service.GetEventTypes(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal([]types.EventTypeStats{
+		{EventType: "pageview", Visitors: 8, Events: 20},
+		{EventType: "signup", Visitors: 2, Events: 2},
+	}, stats)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetOverview() {
+	testCases := []struct {
+		name        string
+		data        types.RequestPayload
+		mockSetup   func()
+		expected    *types.OverviewStats
+		expectedErr error
+	}{
+		{
+			name: "overview successfully retrieved",
+			data: types.RequestPayload{
+				TrackingID: uuid.New(),
+				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{
+					Visitors:      40,
+					Pageviews:     150,
+					Visits:        50,
+					Bounces:       20,
+					TotalDuration: 6000,
+					Events:        12,
+				}, nil).Once()
+			},
+			expected: &types.OverviewStats{
+				Visitors:      40,
+				PageViews:     150,
+				Visits:        50,
+				ViewsPerVisit: 3,
+				BounceRate:    40,
+				VisitDuration: 120,
+				Events:        12,
+			},
+		},
+		{
+			name: "imported aggregates add visitors and pageviews",
+			data: types.RequestPayload{
+				TrackingID: uuid.New(),
+				StartDate:  sql.NullTime{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+				EndDate:    sql.NullTime{Time: time.Now(), Valid: true},
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{
+					Visitors:          4,
+					Pageviews:         10,
+					Visits:            5,
+					Bounces:           1,
+					ImportedVisitors:  30,
+					ImportedPageviews: 90,
+				}, nil).Once()
+			},
+			expected: &types.OverviewStats{
+				Visitors:      34,
+				PageViews:     100,
+				Visits:        5,
+				ViewsPerVisit: 2,
+				BounceRate:    20,
+			},
+		},
+		{
+			name: "no visits in range",
+			data: types.RequestPayload{
+				TrackingID: uuid.New(),
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{}, nil).Once()
+			},
+			expected: &types.OverviewStats{},
+		},
+		{
+			name: "failed to fetch overview",
+			data: types.RequestPayload{
+				TrackingID: uuid.New(),
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{}, errors.New("failed to fetch overview")).Once()
+			},
+			expectedErr: errors.New("failed to fetch overview"),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+			overview, err := suite.service.GetOverview(suite.ctx, tc.data)
+			if tc.expectedErr != nil {
+				suite.Error(err)
+				suite.Equal(tc.expectedErr.Error(), err.Error())
+				return
+			}
+			suite.NoError(err)
+			suite.Equal(tc.expected, overview)
+			suite.mockRepo.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *ServiceSuite) TestGetOverviewComparison() {
+	data := types.RequestPayload{
+		TrackingID: uuid.New(),
+		Compare:    "previous_period",
+	}
+
+	suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{
+		Visitors: 30, Pageviews: 60, Visits: 40, Bounces: 10, TotalDuration: 2000, Events: 5,
+	}, nil).Once()
+	suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.Anything).Return(database.GetOverviewRow{
+		Visitors: 20, Pageviews: 60, Visits: 30, Bounces: 15, TotalDuration: 3000, Events: 0,
+	}, nil).Once()
+
+	overview, err := suite.service.GetOverview(suite.ctx, data)
+	suite.NoError(err)
+	suite.Len(overview.Comparison, 7)
+	suite.Equal(20.0, overview.Comparison["visitors"].Value)
+	suite.InDelta(50.0, *overview.Comparison["visitors"].PercentageChange, 0.001)
+	suite.Equal(0.0, overview.Comparison["pageviews"].Change)
+	suite.InDelta(-25.0, overview.Comparison["bounce_rate"].Change, 0.001)
+	suite.InDelta(-50.0, overview.Comparison["visit_duration"].Change, 0.001)
+	suite.Nil(overview.Comparison["events"].PercentageChange)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestTrackEventPublishesLiveUpdate() {
+	trackingID := uuid.New()
+	// a visitor seen before the subscription still counts
+	suite.mockRepo.EXPECT().GetActiveVisitorIDs(mock.Anything, mock.MatchedBy(func(arg database.GetActiveVisitorIDsParams) bool {
+		return arg.TrackingID == trackingID
+	})).Return([]database.GetActiveVisitorIDsRow{{VisitorID: "earlier", LastSeen: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}}}, nil).Once()
+	updates, unsubscribe, err := suite.service.SubscribeEvents(suite.ctx, trackingID)
+	suite.Require().NoError(err)
+	defer unsubscribe()
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{TrackingID: trackingID}, nil).Once()
+	suite.mockRepo.EXPECT().CreateEvent(mock.Anything, mock.Anything).Return(nil).Once()
+
+	err = suite.service.TrackEvent(suite.ctx, types.EventPayload{
+		Type: "pageview",
+		Tracking: types.TrackingData{
+			TrackingID: trackingID,
+			VisitorID:  faker.UUIDDigit(),
+			Url:        "https://example.com/pricing?plan=pro",
+			Referrer:   "https://news.ycombinator.com",
+			Country:    "Nigeria",
+		},
+	})
+	suite.NoError(err)
+
+	select {
+	case update := <-updates:
+		suite.Equal("pageview", update.Event.Type)
+		suite.Equal("/pricing", update.Event.Path)
+		suite.Equal("Nigeria", update.Event.Country)
+		suite.Equal(2, update.CurrentVisitors)
+	default:
+		suite.Fail("expected a live update")
+	}
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetRealtime() {
+	pages := make([]database.GetPagesRow, 0, realtimeTopRows)
+	for i := range realtimeTopRows {
+		url := fmt.Sprintf("https://example.com/page-%d", i)
+		pages = append(pages, database.GetPagesRow{Url: &url, Visitors: int64(20 - i), Position: int64(i + 1)})
+	}
+	referrer := "https://google.com"
+
+	suite.mockRepo.EXPECT().GetActiveVisitors(mock.Anything, mock.MatchedBy(func(arg database.GetActiveVisitorsParams) bool {
+		return time.Since(arg.Timestamp.Time) >= realtimeWindow
+	})).Return(int64(12), nil).Once()
+	suite.mockRepo.EXPECT().GetPages(mock.Anything, mock.MatchedBy(func(arg database.GetPagesParams) bool {
+		return arg.PageLimit == realtimeTopRows && arg.PageOffset == 0
+	})).Return(pages, nil).Once()
+	suite.mockRepo.EXPECT().GetReferrals(mock.Anything, mock.MatchedBy(func(arg database.GetReferralsParams) bool {
+		return arg.PageLimit == realtimeTopRows
+	})).Return([]database.GetReferralsRow{
+		{Referrer: &referrer, Visitors: 5, Position: 1},
+	}, nil).Once()
+
+	stats, err := suite.service.GetRealtime(suite.ctx, uuid.New())
+	suite.NoError(err)
+	suite.Equal(12, stats.CurrentVisitors)
+	suite.Len(stats.Pages, realtimeTopRows)
+	suite.Equal("/page-0", stats.Pages[0].Path)
+	suite.Len(stats.Sources, 1)
+
+	suite.mockRepo.EXPECT().GetActiveVisitors(mock.Anything, mock.Anything).Return(int64(0), errors.New("database error")).Once()
+	_, err = suite.service.GetRealtime(suite.ctx, uuid.New())
+	suite.Error(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestGetRetention() {
+	testCases := []struct {
+		name        string
+		data        types.RetentionPayload
+		mockSetup   func()
+		expectedErr error
+	}{
+		{
+			name: "retention successfully retrieved",
+			data: types.RetentionPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Period:         "week",
+			},
+			mockSetup: func() {
+				cohort := sql.NullTime{Time: time.Now().AddDate(0, 0, -14), Valid: true}
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{RetentionTracking: true}, nil).Once()
+				suite.mockRepo.EXPECT().GetRetentionCohorts(mock.Anything, mock.Anything).Return([]database.GetRetentionCohortsRow{
+					{
+						Cohort:       cohort,
+						PeriodOffset: 0,
+						Visitors:     40,
+					},
+					{
+						Cohort:       cohort,
+						PeriodOffset: 1,
+						Visitors:     10,
+					},
+				}, nil).Once()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "retention tracking disabled",
+			data: types.RetentionPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Period:         "week",
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{}, nil).Once()
+			},
+			expectedErr: ErrRetentionDisabled,
+		},
+		{
+			name: "failed to fetch retention",
+			data: types.RetentionPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Period:         "month",
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{RetentionTracking: true}, nil).Once()
+				suite.mockRepo.EXPECT().GetRetentionCohorts(mock.Anything, mock.Anything).Return([]database.GetRetentionCohortsRow{}, errors.New("failed to fetch retention")).Once()
+			},
+			expectedErr: errors.New("failed to fetch retention"),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+			cohorts, err := suite.service.GetRetention(suite.ctx, tc.data)
+			if tc.expectedErr != nil {
+				suite.Error(err)
+				suite.Equal(tc.expectedErr.Error(), err.Error())
+				return
+			}
+			suite.NoError(err)
+			suite.Len(cohorts, 1)
+			suite.Equal(40, cohorts[0].Visitors)
+			suite.Len(cohorts[0].Retention, 2)
+			suite.Equal(25.0, cohorts[0].Retention[1].Percentage)
+			suite.mockRepo.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *ServiceSuite) TestGetUserFlow() {
+	testCases := []struct {
+		name          string
+		data          types.FlowPayload
+		mockSetup     func()
+		expectedNodes int
+		expectedLinks int
+		expectedErr   error
+	}{
+		{
+			name: "next steps successfully retrieved",
+			data: types.FlowPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Step:           "/pricing",
+				Direction:      "next",
+				Steps:          2,
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetUserFlow(mock.Anything, mock.MatchedBy(func(params database.GetUserFlowParams) bool {
+					return params.MinDepth == 0 && params.MaxDepth == 2
+				})).Return([]database.GetUserFlowRow{
+					{Depth: 0, Step: "/pricing", Visitors: 30},
+					{Depth: 1, PreviousStep: "/pricing", Step: "/signup", Visitors: 20},
+					{Depth: 1, PreviousStep: "/pricing", Step: "/docs", Visitors: 5},
+					{Depth: 2, PreviousStep: "/signup", Step: "signup_completed", Visitors: 10},
+				}, nil).Once()
+			},
+			expectedNodes: 4,
+			expectedLinks: 3,
+		},
+		{
+			name: "previous steps successfully retrieved",
+			data: types.FlowPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Step:           "/pricing",
+				Direction:      "previous",
+				Steps:          1,
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetUserFlow(mock.Anything, mock.MatchedBy(func(params database.GetUserFlowParams) bool {
+					return params.MinDepth == -1 && params.MaxDepth == 0
+				})).Return([]database.GetUserFlowRow{
+					{Depth: -1, Step: "/", Visitors: 12},
+					{Depth: 0, PreviousStep: "/", Step: "/pricing", Visitors: 12},
+				}, nil).Once()
+			},
+			expectedNodes: 2,
+			expectedLinks: 1,
+		},
+		{
+			name: "failed to fetch user flow",
+			data: types.FlowPayload{
+				RequestPayload: types.RequestPayload{TrackingID: uuid.New()},
+				Step:           "/pricing",
+				Direction:      "next",
+				Steps:          3,
+			},
+			mockSetup: func() {
+				suite.mockRepo.EXPECT().GetUserFlow(mock.Anything, mock.Anything).Return([]database.GetUserFlowRow{}, errors.New("failed to fetch user flow")).Once()
+			},
+			expectedErr: errors.New("failed to fetch user flow"),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockSetup()
+			flow, err := suite.service.GetUserFlow(suite.ctx, tc.data)
+			if tc.expectedErr != nil {
+				suite.Error(err)
+				suite.Equal(tc.expectedErr.Error(), err.Error())
+				return
+			}
+			suite.NoError(err)
+			suite.Len(flow.Nodes, tc.expectedNodes)
+			suite.Len(flow.Links, tc.expectedLinks)
+			suite.mockRepo.AssertExpectations(suite.T())
+		})
+	}
+}
+
 func (suite *ServiceSuite) TestResolveGeoLocation() {
 	testCases := []struct {
 		name        string
@@ -903,10 +1722,20 @@ func (suite *ServiceSuite) TestValidateAppAccess() {
 			userID:     uuid.New(),
 			trackingID: uuid.New(),
 			mockSetup: func(userID uuid.UUID) {
-				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{UserID: userID}, nil).Once()
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{UserID: userID, Timezone: "Africa/Lagos"}, nil).Once()
 			},
 			expectedErr: nil,
 		},
+		{
+			name:       "app owned by another user",
+			userID:     uuid.New(),
+			trackingID: uuid.New(),
+			mockSetup: func(userID uuid.UUID) {
+				suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, mock.Anything).Return(database.App{UserID: uuid.New()}, nil).Once()
+				suite.mockRepo.EXPECT().GetAppMember(mock.Anything, mock.Anything).Return("", pgx.ErrNoRows).Once()
+			},
+			expectedErr: ErrAppNotFound,
+		},
 		{
 			name:       "app not found",
 			userID:     uuid.New(),
@@ -921,13 +1750,14 @@ func (suite *ServiceSuite) TestValidateAppAccess() {
 	for _, tc := range testCases {
 		suite.Run(tc.name, func() {
 			tc.mockSetup(tc.userID)
-			err := suite.service.ValidateAppAccess(suite.ctx, tc.userID, tc.trackingID)
+			app, err := suite.service.ValidateAppAccess(suite.ctx, tc.userID, tc.trackingID)
 			if tc.expectedErr != nil {
 				suite.Error(err)
 				suite.Equal(tc.expectedErr.Error(), err.Error())
 				return
 			}
 			suite.NoError(err)
+			suite.Equal("Africa/Lagos", app.Timezone)
 			suite.mockRepo.AssertExpectations(suite.T())
 		})
 	}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var (
+	ErrShareNotFound        = errors.New("share link not found")
+	ErrShareExpired         = errors.New("share link has expired")
+	ErrInvalidSharePassword = errors.New("invalid share link password")
+	ErrShareSessionRequired = errors.New("the share link is password protected, start a session with its password")
+	ErrTooManyShareAttempts = errors.New("too many wrong passwords for this share link, try again later")
+	ErrEndpointNotShared    = errors.New("the share link does not grant access to this endpoint")
+	ErrInvalidShareExpiry   = errors.New("expires_at must be in the future")
+	ErrUnsupportedEndpoint  = errors.New("unsupported endpoint")
+)
+
+// shareSessionTTL is how long a session started with the password of a share
+// link lasts, and shareSessionCookie the cookie it is kept in.
+const (
+	shareSessionTTL    = time.Hour
+	shareSessionCookie = "share_session"
+)
+
+// maxShareFailures wrong passwords within shareFailureWindow lock a share
+// link's sessions until the window is over.
+const (
+	maxShareFailures   = 10
+	shareFailureWindow = 15 * time.Minute
+)
+
+// shareAttempts counts the wrong passwords of share links, per server.
+type shareAttempts struct {
+	mu       sync.Mutex
+	failures map[uuid.UUID]*failureWindow
+}
+
+type failureWindow struct {
+	start time.Time
+	count int
+}
+
+func newShareAttempts() *shareAttempts {
+	return &shareAttempts{failures: make(map[uuid.UUID]*failureWindow)}
+}
+
+// allowed reports whether a share link has wrong passwords left.
+func (a *shareAttempts) allowed(shareID uuid.UUID, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	window := a.failures[shareID]
+	return window == nil || now.Sub(window.start) >= shareFailureWindow || window.count < maxShareFailures
+}
+
+// fail records a wrong password, dropping the windows that are over.
+func (a *shareAttempts) fail(shareID uuid.UUID, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, window := range a.failures {
+		if now.Sub(window.start) >= shareFailureWindow {
+			delete(a.failures, id)
+		}
+	}
+	window := a.failures[shareID]
+	if window == nil {
+		window = &failureWindow{start: now}
+		a.failures[shareID] = window
+	}
+	window.count++
+}
+
+// shareableEndpoints are the read-only analytics endpoints share links can
+// open up, named by their path under /analytics.
+var shareableEndpoints = []string{
+	"overview", "events", "visitors", "pageviews", "referrals", "pages", "browsers", "countries",
+	"devices", "os", "realtime", "realtime/stream", "retention", "flow", "export",
+}
+
+func newShare(row database.Share) types.Share {
+	return types.Share{
+		ID:                row.ID,
+		TrackingID:        row.TrackingID,
+		UserID:            row.UserID,
+		Name:              row.Name,
+		Endpoints:         row.Endpoints,
+		PasswordProtected: row.PasswordHash != nil,
+		ExpiresAt:         nullTime(row.ExpiresAt),
+		RevokedAt:         nullTime(row.RevokedAt),
+		CreatedAt:         row.CreatedAt.Time,
+	}
+}
+
+// newToken returns a random token for a share link or an API token.
+func newToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashToken returns the hash of the token of a share link or an API token,
+// which is what is stored in place of the token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateShare creates a share link to the stats of an app. The token of the
+// link is only returned here, as just its hash is kept.
+func (s *analyticsService) CreateShare(ctx context.Context, data types.SharePayload) (*types.Share, error) {
+	if _, err := s.authorizeApp(ctx, data.UserID, data.TrackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	endpoints := data.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = shareableEndpoints
+	}
+	for _, endpoint := range endpoints {
+		if !slices.Contains(shareableEndpoints, endpoint) {
+			return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnsupportedEndpoint, endpoint, strings.Join(shareableEndpoints, ", "))
+		}
+	}
+
+	params := database.CreateShareParams{
+		TrackingID: data.TrackingID,
+		UserID:     data.UserID,
+		Name:       data.Name,
+		Endpoints:  endpoints,
+	}
+	if data.ExpiresAt != nil {
+		if !data.ExpiresAt.After(time.Now()) {
+			return nil, ErrInvalidShareExpiry
+		}
+		params.ExpiresAt.Time, params.ExpiresAt.Valid = *data.ExpiresAt, true
+	}
+	if data.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		params.PasswordHash = optionalString(string(hash))
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	params.TokenHash = hashToken(token)
+
+	row, err := s.Querier.CreateShare(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	share := newShare(row)
+	share.Token = token
+	return &share, nil
+}
+
+func (s *analyticsService) GetShares(ctx context.Context, userID, trackingID uuid.UUID) ([]types.Share, error) {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Querier.GetShares(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]types.Share, 0, len(rows))
+	for _, row := range rows {
+		shares = append(shares, newShare(row))
+	}
+	return shares, nil
+}
+
+// RevokeShare revokes a share link for good. Revoking it again changes nothing.
+func (s *analyticsService) RevokeShare(ctx context.Context, userID, trackingID, shareID uuid.UUID) (*types.Share, error) {
+	if _, err := s.authorizeApp(ctx, userID, trackingID, roleAdmin); err != nil {
+		return nil, err
+	}
+
+	row, err := s.Querier.RevokeShare(ctx, database.RevokeShareParams{ID: shareID, TrackingID: trackingID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	share := newShare(row)
+	return &share, nil
+}
+
+// liveShare returns the share link with token, unless it was revoked or has
+// expired.
+func (s *analyticsService) liveShare(ctx context.Context, token string) (database.Share, error) {
+	share, err := s.Querier.GetShareByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return database.Share{}, ErrShareNotFound
+	}
+	if err != nil {
+		return database.Share{}, err
+	}
+	if share.RevokedAt.Valid {
+		return database.Share{}, ErrShareNotFound
+	}
+	if share.ExpiresAt.Valid && !share.ExpiresAt.Time.After(time.Now()) {
+		return database.Share{}, ErrShareExpired
+	}
+	return share, nil
+}
+
+// CreateShareSession checks the password of the share link with token and
+// starts a session of it, signed so that the requests made with it skip the
+// password. Sessions last shareSessionTTL, or until the link expires.
+func (s *analyticsService) CreateShareSession(ctx context.Context, token, password string) (*types.ShareSession, error) {
+	share, err := s.liveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if share.PasswordHash != nil {
+		if !s.shareAttempts.allowed(share.ID, now) {
+			return nil, ErrTooManyShareAttempts
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(*share.PasswordHash), []byte(password)); err != nil {
+			s.shareAttempts.fail(share.ID, now)
+			return nil, ErrInvalidSharePassword
+		}
+	}
+
+	expiresAt := now.Add(shareSessionTTL)
+	if share.ExpiresAt.Valid && share.ExpiresAt.Time.Before(expiresAt) {
+		expiresAt = share.ExpiresAt.Time
+	}
+	claims := jwt.MapClaims{"share": share.ID.String(), "exp": expiresAt.Unix()}
+	session, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(viper.GetString("TokenSecret")))
+	if err != nil {
+		return nil, err
+	}
+	return &types.ShareSession{Token: session, ExpiresAt: expiresAt}, nil
+}
+
+// ValidateShareAccess returns the app whose stats the share link with token
+// opens up, provided the link is live, session is a session of it, if it has
+// a password, and it grants access to endpoint.
+func (s *analyticsService) ValidateShareAccess(ctx context.Context, token, session, endpoint string) (*types.App, error) {
+	share, err := s.liveShare(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if share.PasswordHash != nil {
+		claims, err := VerifyJWT(session)
+		if err != nil || claims["share"] != share.ID.String() {
+			return nil, ErrShareSessionRequired
+		}
+	}
+	if !slices.Contains(share.Endpoints, endpoint) {
+		return nil, ErrEndpointNotShared
+	}
+
+	app, err := s.Querier.GetAppByTrackingID(ctx, share.TrackingID)
+	if err != nil {
+		return nil, err
+	}
+	return newApp(app), nil
+}
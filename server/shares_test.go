@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (suite *ServiceSuite) TestCreateShare() {
+	userID, trackingID := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Times(4)
+
+	_, err := suite.service.CreateShare(suite.ctx, types.SharePayload{UserID: userID, TrackingID: trackingID, Name: "client", Endpoints: []string{"goals"}})
+	suite.ErrorIs(err, ErrUnsupportedEndpoint)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = suite.service.CreateShare(suite.ctx, types.SharePayload{UserID: userID, TrackingID: trackingID, Name: "client", ExpiresAt: &past})
+	suite.ErrorIs(err, ErrInvalidShareExpiry)
+
+	var tokenHash string
+	suite.mockRepo.EXPECT().CreateShare(mock.Anything, mock.MatchedBy(func(arg database.CreateShareParams) bool {
+		return arg.Name == "client" && len(arg.Endpoints) == 2 && arg.ExpiresAt.Time.Equal(expiresAt) &&
+			arg.PasswordHash != nil && bcrypt.CompareHashAndPassword([]byte(*arg.PasswordHash), []byte("hunter2")) == nil
+	})).RunAndReturn(func(_ context.Context, arg database.CreateShareParams) (database.Share, error) {
+		tokenHash = arg.TokenHash
+		return database.Share{ID: uuid.New(), TrackingID: trackingID, Name: arg.Name, TokenHash: arg.TokenHash, PasswordHash: arg.PasswordHash, Endpoints: arg.Endpoints, ExpiresAt: arg.ExpiresAt}, nil
+	}).Once()
+
+	share, err := suite.service.CreateShare(suite.ctx, types.SharePayload{
+		UserID:     userID,
+		TrackingID: trackingID,
+		Name:       "client",
+		Endpoints:  []string{"overview", "pages"},
+		Password:   "hunter2",
+		ExpiresAt:  &expiresAt,
+	})
+	suite.NoError(err)
+	suite.True(share.PasswordProtected)
+	// only the hash of the token is stored
+	suite.NotEmpty(share.Token)
+	suite.Equal(hashToken(share.Token), tokenHash)
+
+	// without endpoints the link opens up all of them
+	suite.mockRepo.EXPECT().CreateShare(mock.Anything, mock.MatchedBy(func(arg database.CreateShareParams) bool {
+		return len(arg.Endpoints) == len(shareableEndpoints) && arg.PasswordHash == nil && !arg.ExpiresAt.Valid
+	})).Return(database.Share{ID: uuid.New(), Endpoints: shareableEndpoints}, nil).Once()
+
+	share, err = suite.service.CreateShare(suite.ctx, types.SharePayload{UserID: userID, TrackingID: trackingID, Name: "public"})
+	suite.NoError(err)
+	suite.False(share.PasswordProtected)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestRevokeShare() {
+	userID, trackingID, shareID := uuid.New(), uuid.New(), uuid.New()
+	revokedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: userID, TrackingID: trackingID}, nil).Times(3)
+	suite.mockRepo.EXPECT().RevokeShare(mock.Anything, database.RevokeShareParams{ID: shareID, TrackingID: trackingID}).Return(database.Share{ID: shareID, RevokedAt: revokedAt}, nil).Once()
+
+	share, err := suite.service.RevokeShare(suite.ctx, userID, trackingID, shareID)
+	suite.NoError(err)
+	suite.NotNil(share.RevokedAt)
+
+	suite.mockRepo.EXPECT().RevokeShare(mock.Anything, mock.Anything).Return(database.Share{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.RevokeShare(suite.ctx, userID, trackingID, uuid.New())
+	suite.ErrorIs(err, ErrShareNotFound)
+
+	// apps of other users are out of reach
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, mock.Anything).Return("", pgx.ErrNoRows).Once()
+	_, err = suite.service.RevokeShare(suite.ctx, uuid.New(), trackingID, shareID)
+	suite.ErrorIs(err, ErrAppNotFound)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestValidateShareAccess() {
+	trackingID := uuid.New()
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	hash := string(passwordHash)
+
+	shares := map[string]database.Share{
+		"live":      {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, ExpiresAt: at(time.Now().Add(time.Hour))},
+		"protected": {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, PasswordHash: &hash},
+		"other":     {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, PasswordHash: &hash},
+		"locked":    {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, PasswordHash: &hash},
+		"revoked":   {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, RevokedAt: at(time.Now())},
+		"expired":   {ID: uuid.New(), TrackingID: trackingID, Endpoints: []string{"overview"}, ExpiresAt: at(time.Now().Add(-time.Hour))},
+	}
+	suite.mockRepo.EXPECT().GetShareByTokenHash(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, tokenHash string) (database.Share, error) {
+		for token, share := range shares {
+			if hashToken(token) == tokenHash {
+				return share, nil
+			}
+		}
+		return database.Share{}, pgx.ErrNoRows
+	})
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{TrackingID: trackingID, Timezone: "Africa/Lagos"}, nil)
+
+	// sessions are started with the password, and last no longer than the link
+	_, err = suite.service.CreateShareSession(suite.ctx, "protected", "hunter3")
+	suite.ErrorIs(err, ErrInvalidSharePassword)
+	_, err = suite.service.CreateShareSession(suite.ctx, "expired", "")
+	suite.ErrorIs(err, ErrShareExpired)
+	session, err := suite.service.CreateShareSession(suite.ctx, "protected", "hunter2")
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now().Add(shareSessionTTL), session.ExpiresAt, time.Second)
+	live, err := suite.service.CreateShareSession(suite.ctx, "live", "")
+	suite.Require().NoError(err)
+	suite.WithinDuration(shares["live"].ExpiresAt.Time, live.ExpiresAt, time.Second)
+	userToken, err := CreateJWT(uuid.NewString())
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name     string
+		token    string
+		session  string
+		endpoint string
+		err      error
+	}{
+		{name: "live link", token: "live", endpoint: "overview"},
+		{name: "unknown token", token: "unknown", endpoint: "overview", err: ErrShareNotFound},
+		{name: "revoked link", token: "revoked", endpoint: "overview", err: ErrShareNotFound},
+		{name: "expired link", token: "expired", endpoint: "overview", err: ErrShareExpired},
+		{name: "endpoint not shared", token: "live", endpoint: "pages", err: ErrEndpointNotShared},
+		{name: "missing session", token: "protected", endpoint: "overview", err: ErrShareSessionRequired},
+		{name: "session of another link", token: "other", session: session.Token, endpoint: "overview", err: ErrShareSessionRequired},
+		{name: "user token", token: "protected", session: userToken, endpoint: "overview", err: ErrShareSessionRequired},
+		{name: "session of the link", token: "protected", session: session.Token, endpoint: "overview"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			app, err := suite.service.ValidateShareAccess(suite.ctx, tc.token, tc.session, tc.endpoint)
+			if tc.err != nil {
+				suite.ErrorIs(err, tc.err)
+				return
+			}
+			suite.NoError(err)
+			suite.Equal(trackingID, app.TrackingID)
+			suite.Equal("Africa/Lagos", app.Timezone)
+		})
+	}
+
+	// wrong passwords lock the link, not the others
+	for range maxShareFailures {
+		_, err = suite.service.CreateShareSession(suite.ctx, "locked", "hunter3")
+		suite.ErrorIs(err, ErrInvalidSharePassword)
+	}
+	_, err = suite.service.CreateShareSession(suite.ctx, "locked", "hunter2")
+	suite.ErrorIs(err, ErrTooManyShareAttempts)
+	_, err = suite.service.CreateShareSession(suite.ctx, "other", "hunter2")
+	suite.NoError(err)
+}
+
+func (suite *ServiceSuite) TestShareAttempts() {
+	attempts, shareID, now := newShareAttempts(), uuid.New(), time.Now()
+	for range maxShareFailures {
+		suite.True(attempts.allowed(shareID, now))
+		attempts.fail(shareID, now)
+	}
+	suite.False(attempts.allowed(shareID, now.Add(time.Minute)))
+
+	// the window ends, and is dropped with the next failure
+	later := now.Add(shareFailureWindow)
+	suite.True(attempts.allowed(shareID, later))
+	attempts.fail(uuid.New(), later)
+	suite.Len(attempts.failures, 1)
+}
@@ -0,0 +1,721 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/hll"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// precisionExact and precisionApprox are the accepted values of the precision
+// query parameter. Approximate requests estimate unique visitors from sketches.
+const (
+	precisionExact  = "exact"
+	precisionApprox = "approx"
+)
+
+// sketchInterval is how often the builder looks for finished hours to sketch.
+const sketchInterval = 5 * time.Minute
+
+// sketchAll is the dimension whose single "" value covers every visitor.
+const sketchAll = "all"
+
+// sketchDimensions are the dimensions sketches are kept for, named after their
+// events columns.
+var sketchDimensions = []string{sketchAll, "url", "referrer", "country", "browser", "device", "operating_system"}
+
+// usesSketches reports whether visitors for data are estimated from sketches.
+// Approximate requests that sketches can't answer, like filtered ones, are
+// counted exactly instead.
+func usesSketches(data types.RequestPayload, series bool) bool {
+	return data.Precision == precisionApprox && coveredByHours(data, series)
+}
+
+// applyRetention has requests that reach into hours past the app's data
+// retention estimate visitors from sketches, as those hours have no raw events
+// left, only their sketches and counts. The previous range of a
+// comparison is counted the same way as the current one.
+func (s *analyticsService) applyRetention(ctx context.Context, data, previous types.RequestPayload) (types.RequestPayload, types.RequestPayload, error) {
+	if data.Precision == precisionApprox {
+		return data, previous, nil
+	}
+
+	start := data.StartDate
+	if data.Compare != "" && previous.StartDate.Valid && start.Valid && previous.StartDate.Time.Before(start.Time) {
+		start = previous.StartDate
+	}
+	cutoff, err := s.Querier.GetRetentionCutoff(ctx, data.TrackingID)
+	if err != nil {
+		return data, previous, err
+	}
+	if cutoff.Valid && (!start.Valid || start.Time.Before(cutoff.Time)) {
+		data.Precision, previous.Precision = precisionApprox, precisionApprox
+	}
+	return data, previous, nil
+}
+
+func breakdownPrecision(data types.RequestPayload) string {
+	if usesSketches(data, false) {
+		return precisionApprox
+	}
+	return precisionExact
+}
+
+// sketchValue returns the value of dimension in row, or false when the row
+// has none, like a pageview without a referrer.
+func sketchValue(row database.GetSketchInputsRow, dimension string) (string, bool) {
+	value := func(v *string) (string, bool) {
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	}
+
+	switch dimension {
+	case "url":
+		// pages only count pageviews, like the pages breakdown
+		if row.Pageviews == 0 {
+			return "", false
+		}
+		return value(row.Url)
+	case "referrer":
+		return value(row.Referrer)
+	case "country":
+		return row.Country, true
+	case "browser":
+		return row.Browser, true
+	case "device":
+		return row.Device, true
+	case "operating_system":
+		return row.OperatingSystem, true
+	}
+	return "", true
+}
+
+type sketchKey struct {
+	trackingID uuid.UUID
+	bucket     time.Time
+	dimension  string
+	value      string
+}
+
+// sketchAggregate is the visitors sketch and exact counts of a dimension value.
+// Imported aggregates have no sketch, so their visitors are kept apart and
+// added to the estimate.
+type sketchAggregate struct {
+	visitors  *hll.Sketch
+	imported  int64
+	pageviews int64
+	events    int64
+}
+
+func newSketchAggregate() *sketchAggregate {
+	return &sketchAggregate{visitors: hll.New()}
+}
+
+func (a *sketchAggregate) merge(other *sketchAggregate) {
+	a.visitors.Merge(other.visitors)
+	a.imported += other.imported
+	a.pageviews += other.pageviews
+	a.events += other.events
+}
+
+// estimate returns the estimated visitors plus the imported ones.
+func (a *sketchAggregate) estimate() int64 {
+	return int64(a.visitors.Estimate()) + a.imported
+}
+
+// buildSketches sketches the visitors of every dimension value in hourly rows
+// of raw events.
+// Only the given dimensions are kept.
+func buildSketches(rows []database.GetSketchInputsRow, dimensions ...string) map[sketchKey]*sketchAggregate {
+	aggregates := make(map[sketchKey]*sketchAggregate)
+	for _, row := range rows {
+		for _, dimension := range dimensions {
+			value, ok := sketchValue(row, dimension)
+			if !ok {
+				continue
+			}
+
+			key := sketchKey{trackingID: row.TrackingID, bucket: row.Bucket.Time, dimension: dimension, value: value}
+			aggregate, exists := aggregates[key]
+			if !exists {
+				aggregate = newSketchAggregate()
+				aggregates[key] = aggregate
+			}
+			aggregate.visitors.Insert(row.VisitorID)
+			aggregate.pageviews += row.Pageviews
+			if dimension != "url" {
+				aggregate.events += row.Events
+			}
+		}
+	}
+	return aggregates
+}
+
+// visitorKey is a visitor of a dimension value in an hour, the rows of the
+// exact visitors kept next to the sketches.
+type visitorKey struct {
+	sketchKey
+	visitorID string
+}
+
+// visitorCounts are the pageviews and events of a visitor of a value.
+type visitorCounts struct {
+	pageviews int64
+	events    int64
+}
+
+// buildVisitors counts the pageviews and events of every visitor of every
+// dimension value in hourly rows of raw events. Values are those of
+// buildSketches, so exact and estimated breakdowns list the same rows.
+func buildVisitors(rows []database.GetSketchInputsRow) map[visitorKey]*visitorCounts {
+	visitors := make(map[visitorKey]*visitorCounts)
+	for _, row := range rows {
+		for _, dimension := range sketchDimensions {
+			value, ok := sketchValue(row, dimension)
+			if !ok {
+				continue
+			}
+
+			key := visitorKey{
+				sketchKey: sketchKey{trackingID: row.TrackingID, bucket: row.Bucket.Time, dimension: dimension, value: value},
+				visitorID: row.VisitorID,
+			}
+			counts, exists := visitors[key]
+			if !exists {
+				counts = &visitorCounts{}
+				visitors[key] = counts
+			}
+			counts.pageviews += row.Pageviews
+			if dimension != "url" {
+				counts.events += row.Events
+			}
+		}
+	}
+	return visitors
+}
+
+// sketchBuilder stores sketches for every finished hour after the watermark.
+// Progress lives in the database, so a restarted builder resumes where it
+// stopped, and rebuilding an hour overwrites its sketches.
+type sketchBuilder struct {
+	querier database.Querier
+	logger  *zap.Logger
+}
+
+func newSketchBuilder(querier database.Querier, logger *zap.Logger) *sketchBuilder {
+	return &sketchBuilder{querier: querier, logger: logger}
+}
+
+func (b *sketchBuilder) Run(ctx context.Context) {
+	ticker := time.NewTicker(sketchInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.build(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			b.logger.Error("failed to build visitor sketches", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *sketchBuilder) build(ctx context.Context, now time.Time) error {
+	watermark, err := b.querier.GetSketchWatermark(ctx)
+	if err != nil {
+		return err
+	}
+
+	for hour := watermark.Time; !hour.Add(time.Hour).After(now); hour = hour.Add(time.Hour) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := b.buildHour(ctx, hour); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *sketchBuilder) buildHour(ctx context.Context, hour time.Time) error {
+	end := sql.NullTime{Time: hour.Add(time.Hour), Valid: true}
+	rows, err := b.querier.GetSketchInputs(ctx, database.GetSketchInputsParams{
+		StartDate: sql.NullTime{Time: hour, Valid: true},
+		EndDate:   end,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := storeHours(ctx, b.querier, rows); err != nil {
+		return err
+	}
+
+	return b.querier.SetSketchWatermark(ctx, end)
+}
+
+// storeHours stores the sketches and visitors of hourly rows of raw events.
+func storeHours(ctx context.Context, querier database.Querier, rows []database.GetSketchInputsRow) error {
+	if err := upsertSketches(ctx, querier, buildSketches(rows, sketchDimensions...)); err != nil {
+		return err
+	}
+	return upsertVisitors(ctx, querier, buildVisitors(rows))
+}
+
+// upsertSketches stores aggregates, one upsert per hour.
+func upsertSketches(ctx context.Context, querier database.Querier, aggregates map[sketchKey]*sketchAggregate) error {
+	buckets := make(map[time.Time]*database.UpsertEventSketchesParams)
+	for key, aggregate := range aggregates {
+		sketch, err := aggregate.visitors.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		params, exists := buckets[key.bucket]
+		if !exists {
+			params = &database.UpsertEventSketchesParams{Bucket: sql.NullTime{Time: key.bucket, Valid: true}}
+			buckets[key.bucket] = params
+		}
+		params.TrackingIds = append(params.TrackingIds, key.trackingID)
+		params.Dimensions = append(params.Dimensions, key.dimension)
+		params.DimensionValues = append(params.DimensionValues, key.value)
+		params.Sketches = append(params.Sketches, sketch)
+		params.Pageviews = append(params.Pageviews, aggregate.pageviews)
+		params.Events = append(params.Events, aggregate.events)
+	}
+
+	for _, params := range buckets {
+		if err := querier.UpsertEventSketches(ctx, *params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertVisitors stores visitors, one upsert per hour.
+func upsertVisitors(ctx context.Context, querier database.Querier, visitors map[visitorKey]*visitorCounts) error {
+	buckets := make(map[time.Time]*database.UpsertEventVisitorsParams)
+	for key, counts := range visitors {
+		params, exists := buckets[key.bucket]
+		if !exists {
+			params = &database.UpsertEventVisitorsParams{Bucket: sql.NullTime{Time: key.bucket, Valid: true}}
+			buckets[key.bucket] = params
+		}
+		params.TrackingIds = append(params.TrackingIds, key.trackingID)
+		params.Dimensions = append(params.Dimensions, key.dimension)
+		params.DimensionValues = append(params.DimensionValues, key.value)
+		params.VisitorIds = append(params.VisitorIds, key.visitorID)
+		params.Pageviews = append(params.Pageviews, counts.pageviews)
+		params.Events = append(params.Events, counts.events)
+	}
+
+	for _, params := range buckets {
+		if err := querier.UpsertEventVisitors(ctx, *params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildAppSketches replaces the sketches and visitors of an app in the range
+// with ones built from raw events, for ranges whose events changed after the builder
+// passed them. Hours after the watermark are left to the builder.
+func rebuildAppSketches(ctx context.Context, querier database.Querier, trackingID uuid.UUID, start, end time.Time) error {
+	watermark, err := querier.GetSketchWatermark(ctx)
+	if err != nil {
+		return err
+	}
+
+	// a day at a time, so a long range isn't held in memory at once
+	start, end = start.Truncate(time.Hour), minTime(end, watermark.Time)
+	for ; start.Before(end); start = start.Add(24 * time.Hour) {
+		dayEnd := minTime(start.Add(24*time.Hour), end)
+		err := querier.DeleteAppSketches(ctx, database.DeleteAppSketchesParams{
+			TrackingID: trackingID,
+			StartDate:  sql.NullTime{Time: start, Valid: true},
+			EndDate:    sql.NullTime{Time: dayEnd, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		rows, err := querier.GetAppSketchInputs(ctx, database.GetAppSketchInputsParams{
+			TrackingID: trackingID,
+			StartDate:  sql.NullTime{Time: start, Valid: true},
+			EndDate:    sql.NullTime{Time: dayEnd, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		inputs := make([]database.GetSketchInputsRow, 0, len(rows))
+		for _, row := range rows {
+			inputs = append(inputs, database.GetSketchInputsRow(row))
+		}
+		if err := storeHours(ctx, querier, inputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSketches returns the hourly sketches of dimension in the range. Hours
+// the builder hasn't reached yet are sketched from raw events on the fly, and
+// imported daily aggregates are added to the hour their day starts in.
+func (s *analyticsService) readSketches(ctx context.Context, trackingID uuid.UUID, dimension string, start, end time.Time) (map[sketchKey]*sketchAggregate, error) {
+	watermark, err := s.Querier.GetSketchWatermark(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := make(map[sketchKey]*sketchAggregate)
+	if start.Before(watermark.Time) {
+		rows, err := s.Querier.GetEventSketches(ctx, database.GetEventSketchesParams{
+			TrackingID: trackingID,
+			Dimension:  dimension,
+			StartDate:  sql.NullTime{Time: start, Valid: true},
+			EndDate:    sql.NullTime{Time: minTime(end, watermark.Time), Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			visitors, err := hll.Parse(row.Visitors)
+			if err != nil {
+				return nil, err
+			}
+			key := sketchKey{trackingID: trackingID, bucket: row.Bucket.Time, dimension: dimension, value: row.Value}
+			aggregates[key] = &sketchAggregate{visitors: visitors, pageviews: row.Pageviews, events: row.Events}
+		}
+	}
+
+	if end.After(watermark.Time) {
+		rows, err := s.Querier.GetAppSketchInputs(ctx, database.GetAppSketchInputsParams{
+			TrackingID: trackingID,
+			StartDate:  sql.NullTime{Time: maxTime(start, watermark.Time), Valid: true},
+			EndDate:    sql.NullTime{Time: end, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		inputs := make([]database.GetSketchInputsRow, 0, len(rows))
+		for _, row := range rows {
+			inputs = append(inputs, database.GetSketchInputsRow(row))
+		}
+		for key, aggregate := range buildSketches(inputs, dimension) {
+			aggregates[key] = aggregate
+		}
+	}
+
+	imported, err := s.Querier.GetImportedAggregates(ctx, database.GetImportedAggregatesParams{
+		TrackingID: trackingID,
+		Dimension:  dimension,
+		StartDate:  sql.NullTime{Time: start, Valid: true},
+		EndDate:    sql.NullTime{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range imported {
+		key := sketchKey{trackingID: trackingID, bucket: row.Bucket.Time, dimension: dimension, value: row.Value}
+		aggregate, exists := aggregates[key]
+		if !exists {
+			aggregate = newSketchAggregate()
+			aggregates[key] = aggregate
+		}
+		aggregate.imported += row.Visitors
+		aggregate.pageviews += row.Pageviews
+	}
+	return aggregates, nil
+}
+
+// importedVisitors returns the visitors of the imported aggregates in the
+// range, which breakdowns add to the visitors of their sketches.
+func (s *analyticsService) importedVisitors(ctx context.Context, trackingID uuid.UUID, start, end time.Time) (int64, error) {
+	rows, err := s.Querier.GetImportedAggregates(ctx, database.GetImportedAggregatesParams{
+		TrackingID: trackingID,
+		Dimension:  sketchAll,
+		StartDate:  sql.NullTime{Time: start, Valid: true},
+		EndDate:    sql.NullTime{Time: end, Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var visitors int64
+	for _, row := range rows {
+		visitors += row.Visitors
+	}
+	return visitors, nil
+}
+
+// sketchRow is a row of a breakdown estimated from sketches, shaped like the
+// rows of the breakdown queries.
+type sketchRow struct {
+	value         string
+	visitors      int64
+	pageviews     int64
+	events        int64
+	totalVisitors int64
+	totalRows     int64
+	position      int64
+	other         bool
+}
+
+// sketchPage is the paging of a breakdown, as passed to the breakdown queries.
+type sketchPage struct {
+	sort         string
+	keys         []string
+	offset       int64
+	limit        int64
+	includeOther bool
+}
+
+// estimateBreakdown estimates a breakdown of dimension from sketches. Sketches
+// can't be estimated in SQL, so values are merged, sorted and paged here the
+// same way the breakdown queries do it.
+func (s *analyticsService) estimateBreakdown(ctx context.Context, dimension string, data types.RequestPayload, page sketchPage) ([]sketchRow, error) {
+	aggregates, err := s.readSketches(ctx, data.TrackingID, dimension, data.StartDate.Time, data.EndDate.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]*sketchAggregate)
+	total := hll.New()
+	for key, aggregate := range aggregates {
+		if _, exists := values[key.value]; !exists {
+			values[key.value] = newSketchAggregate()
+		}
+		values[key.value].merge(aggregate)
+		total.Merge(aggregate.visitors)
+	}
+	imported, err := s.importedVisitors(ctx, data.TrackingID, data.StartDate.Time, data.EndDate.Time)
+	if err != nil {
+		return nil, err
+	}
+	totalVisitors := int64(total.Estimate()) + imported
+
+	rows := make([]sketchRow, 0, len(values))
+	for value, aggregate := range values {
+		rows = append(rows, sketchRow{
+			value:         value,
+			visitors:      aggregate.estimate(),
+			pageviews:     aggregate.pageviews,
+			events:        aggregate.events,
+			totalVisitors: totalVisitors,
+			totalRows:     int64(len(values)),
+		})
+	}
+	sortSketchRows(rows, page.sort)
+	for i := range rows {
+		rows[i].position = int64(i + 1)
+	}
+
+	if page.keys != nil {
+		keys := make(map[string]bool, len(page.keys))
+		for _, key := range page.keys {
+			keys[key] = true
+		}
+		selected := make([]sketchRow, 0, len(page.keys))
+		for _, row := range rows {
+			if keys[row.value] {
+				selected = append(selected, row)
+			}
+		}
+		return selected, nil
+	}
+
+	// the totals row comes first, like in the breakdown queries
+	totals := sketchRow{totalVisitors: totalVisitors, totalRows: int64(len(rows))}
+	start := min(page.offset, int64(len(rows)))
+	end := min(page.offset+page.limit, int64(len(rows)))
+	selected := append([]sketchRow{totals}, rows[start:end]...)
+	if !page.includeOther || end == int64(len(rows)) {
+		return selected, nil
+	}
+
+	other := newSketchAggregate()
+	for _, row := range rows[end:] {
+		other.merge(values[row.value])
+	}
+	return append(selected, sketchRow{
+		visitors:      other.estimate(),
+		pageviews:     other.pageviews,
+		events:        other.events,
+		totalVisitors: totalVisitors,
+		totalRows:     int64(len(rows)),
+		position:      end + 1,
+		other:         true,
+	}), nil
+}
+
+// sortSketchRows orders rows like the ROW_NUMBER of the breakdown queries.
+func sortSketchRows(rows []sketchRow, order string) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch order {
+		case "visitors:asc":
+			if a.visitors != b.visitors {
+				return a.visitors < b.visitors
+			}
+		case "name:asc":
+			if a.value != b.value {
+				return a.value < b.value
+			}
+		case "name:desc":
+			if a.value != b.value {
+				return a.value > b.value
+			}
+		}
+		if a.visitors != b.visitors {
+			return a.visitors > b.visitors
+		}
+		return a.value < b.value
+	})
+}
+
+// sketchBucket is a bucket of a series merged from hourly sketches.
+type sketchBucket struct {
+	time      time.Time
+	aggregate *sketchAggregate
+}
+
+// sketchSeries merges the hourly sketches of every visitor into the buckets of
+// the series of data, filling empty buckets like time_bucket_gapfill.
+func (s *analyticsService) sketchSeries(ctx context.Context, data types.RequestPayload) ([]sketchBucket, error) {
+	location, err := time.LoadLocation(data.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := data.StartDate.Time, data.EndDate.Time
+	aggregates, err := s.readSketches(ctx, data.TrackingID, sketchAll, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[int64]*sketchAggregate)
+	for key, aggregate := range aggregates {
+		offset := bucketOffset(key.bucket, start, data.BucketSize, location)
+		if _, exists := merged[offset]; !exists {
+			merged[offset] = newSketchAggregate()
+		}
+		merged[offset].merge(aggregate)
+	}
+
+	buckets := []sketchBucket{}
+	for bucket, offset := bucketStart(start, data.BucketSize, location), int64(0); bucket.Before(end); bucket, offset = nextBucket(bucket, data.BucketSize), offset+1 {
+		aggregate, exists := merged[offset]
+		if !exists {
+			aggregate = newSketchAggregate()
+		}
+		buckets = append(buckets, sketchBucket{time: bucket, aggregate: aggregate})
+	}
+	return buckets, nil
+}
+
+// estimateVisitors estimates the visitors series from hourly sketches.
+func (s *analyticsService) estimateVisitors(ctx context.Context, data types.RequestPayload) ([]database.GetVisitorsRow, error) {
+	buckets, err := s.sketchSeries(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]database.GetVisitorsRow, 0, len(buckets))
+	for _, bucket := range buckets {
+		rows = append(rows, database.GetVisitorsRow{
+			Time:     sql.NullTime{Time: bucket.time, Valid: true},
+			Visitors: bucket.aggregate.estimate(),
+		})
+	}
+	return rows, nil
+}
+
+// sketchPageViews returns the pageviews series counted along with the hourly
+// sketches, which is exact.
+func (s *analyticsService) sketchPageViews(ctx context.Context, data types.RequestPayload) ([]database.GetPageViewsRow, error) {
+	buckets, err := s.sketchSeries(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]database.GetPageViewsRow, 0, len(buckets))
+	for _, bucket := range buckets {
+		rows = append(rows, database.GetPageViewsRow{
+			Time:  sql.NullTime{Time: bucket.time, Valid: true},
+			Views: bucket.aggregate.pageviews,
+		})
+	}
+	return rows, nil
+}
+
+// sketchTotals merges the hourly sketches of every visitor in the range of
+// data.
+func (s *analyticsService) sketchTotals(ctx context.Context, data types.RequestPayload) (*sketchAggregate, error) {
+	aggregates, err := s.readSketches(ctx, data.TrackingID, sketchAll, data.StartDate.Time, data.EndDate.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := newSketchAggregate()
+	for _, aggregate := range aggregates {
+		totals.merge(aggregate)
+	}
+	return totals, nil
+}
+
+// bucketStart returns the start of the bucket containing t, aligned to the
+// calendar in location like bucketOffset.
+func bucketStart(t time.Time, bucketSize string, location *time.Location) time.Time {
+	t = t.In(location)
+	switch bucketSize {
+	case "1 hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, location)
+	case "1 week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+		return day.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case "1 month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, location)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+func nextBucket(bucket time.Time, bucketSize string) time.Time {
+	switch bucketSize {
+	case "1 hour":
+		return bucket.Add(time.Hour)
+	case "1 week":
+		return bucket.AddDate(0, 0, 7)
+	case "1 month":
+		return bucket.AddDate(0, 1, 0)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	"github.com/ScMofeoluwa/minalytics/hll"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// sketchOf serializes a sketch of the visitors prefix-from to prefix-(to-1).
+func sketchOf(prefix string, from, to int) []byte {
+	sketch := hll.New()
+	for i := from; i < to; i++ {
+		sketch.Insert(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	data, _ := sketch.MarshalBinary()
+	return data
+}
+
+func (suite *ServiceSuite) TestBuildSketches() {
+	trackingID := uuid.New()
+	watermark := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	hour := func(h int) sql.NullTime {
+		return sql.NullTime{Time: watermark.Add(time.Duration(h) * time.Hour), Valid: true}
+	}
+	google := "https://google.com"
+	home := "/"
+
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(hour(0), nil).Once()
+	suite.mockRepo.EXPECT().GetSketchInputs(mock.Anything, database.GetSketchInputsParams{StartDate: hour(0), EndDate: hour(1)}).Return([]database.GetSketchInputsRow{
+		{TrackingID: trackingID, Bucket: hour(0), VisitorID: "first", Url: &home, Country: "Nigeria", Browser: "Chrome", Device: "Desktop", OperatingSystem: "Linux", Pageviews: 2},
+		{TrackingID: trackingID, Bucket: hour(0), VisitorID: "second", Url: &home, Referrer: &google, Country: "Nigeria", Browser: "Chrome", Device: "Desktop", OperatingSystem: "Linux", Pageviews: 1, Events: 1},
+	}, nil).Once()
+	suite.mockRepo.EXPECT().UpsertEventSketches(mock.Anything, mock.MatchedBy(func(arg database.UpsertEventSketchesParams) bool {
+		if !arg.Bucket.Time.Equal(hour(0).Time) || len(arg.Dimensions) != len(sketchDimensions) {
+			return false
+		}
+		for i, dimension := range arg.Dimensions {
+			if dimension != "url" {
+				continue
+			}
+			// pages only count pageviews, like the pages breakdown
+			sketch, err := hll.Parse(arg.Sketches[i])
+			return err == nil && arg.DimensionValues[i] == home && sketch.Estimate() == 2 && arg.Pageviews[i] == 3 && arg.Events[i] == 0
+		}
+		return false
+	})).Return(nil).Once()
+	// a row per visitor of every value, the first visitor having no referrer
+	suite.mockRepo.EXPECT().UpsertEventVisitors(mock.Anything, mock.MatchedBy(func(arg database.UpsertEventVisitorsParams) bool {
+		if !arg.Bucket.Time.Equal(hour(0).Time) || len(arg.VisitorIds) != 2*len(sketchDimensions)-1 {
+			return false
+		}
+		for i, dimension := range arg.Dimensions {
+			if dimension == "referrer" && arg.VisitorIds[i] != "second" {
+				return false
+			}
+			if dimension == "url" && arg.VisitorIds[i] == "second" && (arg.Pageviews[i] != 1 || arg.Events[i] != 0) {
+				return false
+			}
+		}
+		return true
+	})).Return(nil).Once()
+	suite.mockRepo.EXPECT().SetSketchWatermark(mock.Anything, hour(1)).Return(nil).Once()
+	// an hour without events still moves the watermark
+	suite.mockRepo.EXPECT().GetSketchInputs(mock.Anything, database.GetSketchInputsParams{StartDate: hour(1), EndDate: hour(2)}).Return([]database.GetSketchInputsRow{}, nil).Once()
+	suite.mockRepo.EXPECT().SetSketchWatermark(mock.Anything, hour(2)).Return(nil).Once()
+
+	// the hour in progress is left for the next run
+	err := newSketchBuilder(suite.mockRepo, zap.NewNop()).build(suite.ctx, hour(2).Time.Add(10*time.Minute))
+	suite.NoError(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestEstimateBreakdown() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	watermark := start.AddDate(0, 0, 1)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	data := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: uuid.New(),
+			StartDate:  at(start),
+			EndDate:    at(start.AddDate(0, 0, 2)),
+			Timezone:   "UTC",
+			Precision:  precisionApprox,
+		},
+		Limit: 2,
+		Sort:  "visitors:desc",
+		Other: true,
+	}
+	google, twitter := "https://google.com", "https://twitter.com"
+
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(watermark), nil).Once()
+	suite.mockRepo.EXPECT().GetEventSketches(mock.Anything, database.GetEventSketchesParams{
+		TrackingID: data.TrackingID,
+		Dimension:  "referrer",
+		StartDate:  data.StartDate,
+		EndDate:    at(watermark),
+	}).Return([]database.GetEventSketchesRow{
+		{Bucket: at(start), Value: google, Visitors: sketchOf("google", 0, 100), Pageviews: 120},
+		{Bucket: at(start.Add(time.Hour)), Value: google, Visitors: sketchOf("google", 50, 150), Pageviews: 110},
+		{Bucket: at(start), Value: "https://bing.com", Visitors: sketchOf("bing", 0, 10), Pageviews: 10},
+		{Bucket: at(start), Value: "https://duckduckgo.com", Visitors: sketchOf("duck", 0, 3), Pageviews: 3},
+	}, nil).Once()
+	// hours after the watermark are sketched from raw events
+	suite.mockRepo.EXPECT().GetAppSketchInputs(mock.Anything, database.GetAppSketchInputsParams{
+		TrackingID: data.TrackingID,
+		StartDate:  at(watermark),
+		EndDate:    data.EndDate,
+	}).Return([]database.GetAppSketchInputsRow{
+		{TrackingID: data.TrackingID, Bucket: at(watermark), VisitorID: "google-0", Referrer: &google, Pageviews: 1},
+		{TrackingID: data.TrackingID, Bucket: at(watermark), VisitorID: "twitter-0", Referrer: &twitter, Pageviews: 2},
+		{TrackingID: data.TrackingID, Bucket: at(watermark), VisitorID: "direct-0", Pageviews: 1},
+	}, nil).Once()
+
+	referrals, err := suite.service.GetReferrals(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(types.BreakdownMeta{Total: 4, Page: 1, Limit: 2, TotalVisitors: 164, Precision: precisionApprox}, referrals.Meta)
+	suite.Len(referrals.Results, 2)
+	suite.Equal(google, referrals.Results[0].Referrer)
+	suite.Equal(150, referrals.Results[0].Visitors)
+	suite.Equal(231, referrals.Results[0].Pageviews)
+	suite.Equal("https://bing.com", referrals.Results[1].Referrer)
+	suite.Equal(10, referrals.Results[1].Visitors)
+	suite.Equal(otherRowName, referrals.Other.Referrer)
+	suite.Equal(types.BreakdownStats{Visitors: 4, Pageviews: 5, Percentage: 4 * 100.0 / 164}, referrals.Other.BreakdownStats)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestEstimateVisitors() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	data := types.RequestPayload{
+		TrackingID: uuid.New(),
+		StartDate:  at(start),
+		EndDate:    at(end),
+		Timezone:   "UTC",
+		BucketSize: "1 day",
+		Precision:  precisionApprox,
+	}
+
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(end), nil).Once()
+	suite.mockRepo.EXPECT().GetEventSketches(mock.Anything, mock.MatchedBy(func(arg database.GetEventSketchesParams) bool {
+		return arg.Dimension == sketchAll && arg.TrackingID == data.TrackingID
+	})).Return([]database.GetEventSketchesRow{
+		{Bucket: at(start), Visitors: sketchOf("visitor", 0, 20)},
+		{Bucket: at(start.Add(5 * time.Hour)), Visitors: sketchOf("visitor", 10, 30)},
+		{Bucket: at(start.AddDate(0, 0, 2).Add(2 * time.Hour)), Visitors: sketchOf("visitor", 0, 7)},
+	}, nil).Once()
+
+	visitors, err := suite.service.GetVisitors(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal([]types.VisitorStats{
+		{Time: start.Format(time.RFC3339), Visitors: 30},
+		{Time: start.AddDate(0, 0, 1).Format(time.RFC3339), Visitors: 0},
+		{Time: start.AddDate(0, 0, 2).Format(time.RFC3339), Visitors: 7},
+	}, visitors)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestEstimateBreakdownAddsImports() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	data := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: uuid.New(),
+			StartDate:  at(start),
+			EndDate:    at(start.AddDate(0, 0, 1)),
+			Timezone:   "UTC",
+			Precision:  precisionApprox,
+		},
+		Limit: 10,
+		Sort:  "visitors:desc",
+	}
+	google, yahoo := "https://google.com", "https://yahoo.com"
+
+	// imported days have counts but no sketches, so their visitors are added
+	suite.imported[data.TrackingID] = map[string][]database.GetImportedAggregatesRow{
+		sketchAll:  {{Bucket: at(start), Visitors: 60, Pageviews: 70}},
+		"referrer": {{Bucket: at(start), Value: google, Visitors: 40, Pageviews: 50}, {Bucket: at(start), Value: yahoo, Visitors: 5, Pageviews: 5}},
+	}
+	defer delete(suite.imported, data.TrackingID)
+
+	suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(data.EndDate.Time), nil).Once()
+	suite.mockRepo.EXPECT().GetEventSketches(mock.Anything, mock.MatchedBy(func(arg database.GetEventSketchesParams) bool {
+		return arg.TrackingID == data.TrackingID
+	})).Return([]database.GetEventSketchesRow{
+		{Bucket: at(start), Value: google, Visitors: sketchOf("google", 0, 10), Pageviews: 120},
+	}, nil).Once()
+
+	referrals, err := suite.service.GetReferrals(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(70, referrals.Meta.TotalVisitors)
+	suite.Len(referrals.Results, 2)
+	suite.Equal(google, referrals.Results[0].Referrer)
+	suite.Equal(50, referrals.Results[0].Visitors)
+	suite.Equal(170, referrals.Results[0].Pageviews)
+	suite.Equal(yahoo, referrals.Results[1].Referrer)
+	suite.Equal(5, referrals.Results[1].Visitors)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestApproximatePrecisionFallsBackToExact() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	data := types.BreakdownPayload{
+		RequestPayload: types.RequestPayload{
+			TrackingID: uuid.New(),
+			StartDate:  sql.NullTime{Time: start, Valid: true},
+			EndDate:    sql.NullTime{Time: start.Add(36 * time.Hour), Valid: true},
+			Timezone:   "UTC",
+			Filters:    []types.Filter{{Dimension: "country", Operator: "is", Values: []string{"Nigeria"}}},
+			Precision:  precisionApprox,
+		},
+		Limit: 10,
+	}
+
+	suite.mockRepo.EXPECT().GetCountries(mock.Anything, mock.Anything).Return([]database.GetCountriesRow{
+		{Country: "Nigeria", Visitors: 3, TotalVisitors: 3, TotalRows: 1, Position: 1},
+	}, nil).Once()
+
+	countries, err := suite.service.GetCountries(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(precisionExact, countries.Meta.Precision)
+	suite.Equal(3, countries.Results[0].Visitors)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestExpiredRangesUseSketches() {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	data := types.RequestPayload{
+		TrackingID: uuid.New(),
+		StartDate:  at(start),
+		EndDate:    at(end),
+		Timezone:   "UTC",
+		BucketSize: "1 day",
+	}
+	// the first day is past the app's data retention
+	suite.cutoffs[data.TrackingID] = start.AddDate(0, 0, 1)
+	sketches := func(dimension string, rows ...database.GetEventSketchesRow) {
+		suite.mockRepo.EXPECT().GetSketchWatermark(mock.Anything).Return(at(end), nil).Once()
+		suite.mockRepo.EXPECT().GetEventSketches(mock.Anything, mock.MatchedBy(func(arg database.GetEventSketchesParams) bool {
+			return arg.Dimension == dimension && arg.TrackingID == data.TrackingID
+		})).Return(rows, nil).Once()
+	}
+	all := []database.GetEventSketchesRow{
+		{Bucket: at(start), Visitors: sketchOf("visitor", 0, 20), Pageviews: 40, Events: 5},
+		{Bucket: at(start.AddDate(0, 0, 1)), Visitors: sketchOf("visitor", 10, 30), Pageviews: 25, Events: 2},
+	}
+
+	// breakdowns asked for exact counts are estimated, and say so
+	sketches("url", database.GetEventSketchesRow{Bucket: at(start), Value: "/pricing", Visitors: sketchOf("visitor", 0, 4), Pageviews: 6})
+	pages, err := suite.service.GetPages(suite.ctx, types.BreakdownPayload{RequestPayload: data, Limit: 10})
+	suite.NoError(err)
+	suite.Equal(precisionApprox, pages.Meta.Precision)
+	suite.Equal(4, pages.Results[0].Visitors)
+
+	// pageviews are counted along with the sketches
+	sketches(sketchAll, all...)
+	pageviews, err := suite.service.GetPageViews(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal([]types.PageViewStats{
+		{Time: start.Format(time.RFC3339), Views: 40},
+		{Time: start.AddDate(0, 0, 1).Format(time.RFC3339), Views: 25},
+	}, pageviews)
+
+	// visits can only be counted over the events that are kept
+	suite.mockRepo.EXPECT().GetOverview(mock.Anything, mock.MatchedBy(func(arg database.GetOverviewParams) bool {
+		return arg.TrackingID == data.TrackingID
+	})).Return(database.GetOverviewRow{Visitors: 20, Pageviews: 25, Visits: 10, Bounces: 5, Events: 2}, nil).Once()
+	sketches(sketchAll, all...)
+	overview, err := suite.service.GetOverview(suite.ctx, data)
+	suite.NoError(err)
+	suite.Equal(30, overview.Visitors)
+	suite.Equal(65, overview.PageViews)
+	suite.Equal(7, overview.Events)
+	suite.Equal(10, overview.Visits)
+	suite.Equal(50.0, overview.BounceRate)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
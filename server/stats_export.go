@@ -0,0 +1,159 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+// statsReport is a CSV file of the stats export.
+type statsReport struct {
+	name  string
+	build func(context.Context, types.AnalyticsService, types.RequestPayload) ([][]string, error)
+}
+
+// statsReports are the files of the stats export, in the order they are zipped.
+var statsReports = []statsReport{
+	{name: "overview.csv", build: overviewReport},
+	{name: "visitors.csv", build: visitorsReport},
+	{name: "events.csv", build: eventTypesReport},
+	{name: "pages.csv", build: breakdownReport("path", types.AnalyticsService.GetPages, func(row types.PageStats) (string, types.BreakdownStats) {
+		return row.Path, row.BreakdownStats
+	})},
+	{name: "referrers.csv", build: breakdownReport("referrer", types.AnalyticsService.GetReferrals, func(row types.ReferralStats) (string, types.BreakdownStats) {
+		return row.Referrer, row.BreakdownStats
+	})},
+	{name: "countries.csv", build: breakdownReport("country", types.AnalyticsService.GetCountries, func(row types.CountryStats) (string, types.BreakdownStats) {
+		return row.Country, row.BreakdownStats
+	})},
+	{name: "browsers.csv", build: breakdownReport("browser", types.AnalyticsService.GetBrowsers, func(row types.BrowserStats) (string, types.BreakdownStats) {
+		return row.Browser, row.BreakdownStats
+	})},
+	{name: "devices.csv", build: breakdownReport("device", types.AnalyticsService.GetDevices, func(row types.DeviceStats) (string, types.BreakdownStats) {
+		return row.Device, row.BreakdownStats
+	})},
+	{name: "operating_systems.csv", build: breakdownReport("operating_system", types.AnalyticsService.GetOS, func(row types.OSStats) (string, types.BreakdownStats) {
+		return row.OS, row.BreakdownStats
+	})},
+}
+
+// writeStatsZip writes a zip of every stats report of the range to w. The
+// reports come from the same service methods as the JSON endpoints, without
+// comparisons.
+func writeStatsZip(ctx context.Context, service types.AnalyticsService, data types.RequestPayload, w io.Writer) error {
+	data.Compare, data.CompareStartDate, data.CompareEndDate = "", sql.NullTime{}, sql.NullTime{}
+
+	archive := zip.NewWriter(w)
+	for _, report := range statsReports {
+		records, err := report.build(ctx, service, data)
+		if err != nil {
+			return err
+		}
+
+		file, err := archive.Create(report.name)
+		if err != nil {
+			return err
+		}
+		if err := csv.NewWriter(file).WriteAll(records); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func overviewReport(ctx context.Context, service types.AnalyticsService, data types.RequestPayload) ([][]string, error) {
+	overview, err := service.GetOverview(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]string{
+		{"metric", "value"},
+		{"visitors", strconv.Itoa(overview.Visitors)},
+		{"pageviews", strconv.Itoa(overview.PageViews)},
+		{"visits", strconv.Itoa(overview.Visits)},
+		{"views_per_visit", formatFloat(overview.ViewsPerVisit)},
+		{"bounce_rate", formatFloat(overview.BounceRate)},
+		{"visit_duration", formatFloat(overview.VisitDuration)},
+		{"events", strconv.Itoa(overview.Events)},
+	}, nil
+}
+
+// visitorsReport joins the visitors and pageviews time series, which share
+// their buckets.
+func visitorsReport(ctx context.Context, service types.AnalyticsService, data types.RequestPayload) ([][]string, error) {
+	visitors, err := service.GetVisitors(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	pageviews, err := service.GetPageViews(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make(map[string]int, len(pageviews))
+	for _, bucket := range pageviews {
+		views[bucket.Time] = bucket.Views
+	}
+
+	records := [][]string{{"time", "visitors", "pageviews"}}
+	for _, bucket := range visitors {
+		records = append(records, []string{bucket.Time, strconv.Itoa(bucket.Visitors), strconv.Itoa(views[bucket.Time])})
+	}
+	return records, nil
+}
+
+func eventTypesReport(ctx context.Context, service types.AnalyticsService, data types.RequestPayload) ([][]string, error) {
+	stats, err := service.GetEventTypes(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	records := [][]string{{"event_type", "visitors", "events"}}
+	for _, row := range stats {
+		records = append(records, []string{row.EventType, strconv.Itoa(row.Visitors), strconv.Itoa(row.Events)})
+	}
+	return records, nil
+}
+
+// breakdownReport builds the report of a breakdown from all of its pages.
+func breakdownReport[T any](
+	dimension string,
+	fetch func(types.AnalyticsService, context.Context, types.BreakdownPayload) (*types.Breakdown[T], error),
+	row func(T) (string, types.BreakdownStats),
+) func(context.Context, types.AnalyticsService, types.RequestPayload) ([][]string, error) {
+	return func(ctx context.Context, service types.AnalyticsService, data types.RequestPayload) ([][]string, error) {
+		payload := types.BreakdownPayload{RequestPayload: data, Limit: maxBreakdownLimit, Sort: "visitors:desc"}
+		records := [][]string{{dimension, "visitors", "pageviews", "events", "percentage"}}
+
+		for payload.Page = 1; ; payload.Page++ {
+			breakdown, err := fetch(service, ctx, payload)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, result := range breakdown.Results {
+				name, stats := row(result)
+				records = append(records, []string{
+					name,
+					strconv.Itoa(stats.Visitors),
+					strconv.Itoa(stats.Pageviews),
+					strconv.Itoa(stats.Events),
+					formatFloat(stats.Percentage),
+				})
+			}
+			if len(breakdown.Results) < payload.Limit || payload.Page*payload.Limit >= breakdown.Meta.Total {
+				return records, nil
+			}
+		}
+	}
+}
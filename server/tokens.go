@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+)
+
+var (
+	ErrAPITokenNotFound   = errors.New("API token not found")
+	ErrInvalidAPIToken    = errors.New("invalid, expired or revoked API token")
+	ErrInvalidTokenExpiry = errors.New("expires_at must be in the future")
+	ErrUnsupportedScope   = errors.New("unsupported scope")
+)
+
+// apiTokenPrefix starts every API token, which tells them apart from JWTs.
+const apiTokenPrefix = "mk_"
+
+// apiTokenTouchInterval is how often the last use of an API token is written,
+// so that BI tools polling the stats don't write a row on every request.
+const apiTokenTouchInterval = time.Minute
+
+// Scopes of API tokens. Read-stats reads the stats and exports of apps,
+// manage-apps manages apps and organizations, and ingest imports data into
+// apps.
+const (
+	scopeReadStats  = "read-stats"
+	scopeManageApps = "manage-apps"
+	scopeIngest     = "ingest"
+)
+
+var apiTokenScopes = []string{scopeReadStats, scopeManageApps, scopeIngest}
+
+func newAPIToken(row database.ApiToken) types.APIToken {
+	token := types.APIToken{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Name:        row.Name,
+		Scopes:      row.Scopes,
+		TrackingIDs: row.TrackingIds,
+		ExpiresAt:   nullTime(row.ExpiresAt),
+		RevokedAt:   nullTime(row.RevokedAt),
+		LastUsedAt:  nullTime(row.LastUsedAt),
+		CreatedAt:   row.CreatedAt.Time,
+	}
+	if token.TrackingIDs == nil {
+		token.TrackingIDs = []uuid.UUID{}
+	}
+	if row.LastUsedIp != nil {
+		token.LastUsedIP = *row.LastUsedIp
+	}
+	return token
+}
+
+// CreateAPIToken creates an API token for the user, limited to apps they are a
+// member of. The token itself is only returned here, as just its hash is kept.
+func (s *analyticsService) CreateAPIToken(ctx context.Context, data types.APITokenPayload) (*types.APIToken, error) {
+	for _, scope := range data.Scopes {
+		if !slices.Contains(apiTokenScopes, scope) {
+			return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnsupportedScope, scope, strings.Join(apiTokenScopes, ", "))
+		}
+	}
+	for _, trackingID := range data.TrackingIDs {
+		if _, err := s.authorizeApp(ctx, data.UserID, trackingID, roleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	params := database.CreateAPITokenParams{
+		UserID:      data.UserID,
+		Name:        data.Name,
+		Scopes:      data.Scopes,
+		TrackingIds: data.TrackingIDs,
+	}
+	if params.TrackingIds == nil {
+		params.TrackingIds = []uuid.UUID{}
+	}
+	if data.ExpiresAt != nil {
+		if !data.ExpiresAt.After(time.Now()) {
+			return nil, ErrInvalidTokenExpiry
+		}
+		params.ExpiresAt.Time, params.ExpiresAt.Valid = *data.ExpiresAt, true
+	}
+
+	secret, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	token := apiTokenPrefix + secret
+	params.TokenHash = hashToken(token)
+
+	row, err := s.Querier.CreateAPIToken(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	apiToken := newAPIToken(row)
+	apiToken.Token = token
+	return &apiToken, nil
+}
+
+func (s *analyticsService) GetAPITokens(ctx context.Context, userID uuid.UUID) ([]types.APIToken, error) {
+	rows, err := s.Querier.GetAPITokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]types.APIToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, newAPIToken(row))
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes an API token of the user for good. Revoking it again
+// changes nothing.
+func (s *analyticsService) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) (*types.APIToken, error) {
+	row, err := s.Querier.RevokeAPIToken(ctx, database.RevokeAPITokenParams{ID: tokenID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPITokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	apiToken := newAPIToken(row)
+	return &apiToken, nil
+}
+
+// ValidateAPIToken returns the API token whose token is token, provided it is
+// neither expired nor revoked, and records that it was just used from ip. The
+// use is only written when the IP changed or the last one written is older
+// than apiTokenTouchInterval.
+func (s *analyticsService) ValidateAPIToken(ctx context.Context, token, ip string) (*types.APIToken, error) {
+	row, err := s.Querier.GetAPITokenByHash(ctx, hashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	apiToken := newAPIToken(row)
+	if !row.LastUsedAt.Valid || time.Since(row.LastUsedAt.Time) >= apiTokenTouchInterval || apiToken.LastUsedIP != ip {
+		params := database.TouchAPITokenParams{ID: row.ID, LastUsedIp: optionalString(ip)}
+		if err := s.Querier.TouchAPIToken(ctx, params); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		apiToken.LastUsedAt, apiToken.LastUsedIP = &now, ip
+	}
+	return &apiToken, nil
+}
+
+// tokenAllows reports whether an API token has one of scopes and, when the
+// request is for an app, is allowed that app. Tokens limited to apps aren't
+// allowed requests that aren't for one of them.
+func tokenAllows(token *types.APIToken, scopes []string, trackingID uuid.UUID) bool {
+	if !slices.ContainsFunc(scopes, func(scope string) bool { return slices.Contains(token.Scopes, scope) }) {
+		return false
+	}
+	if len(token.TrackingIDs) == 0 {
+		return true
+	}
+	return trackingID != uuid.Nil && slices.Contains(token.TrackingIDs, trackingID)
+}
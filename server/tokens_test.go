@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	database "github.com/ScMofeoluwa/minalytics/database/sqlc"
+	types "github.com/ScMofeoluwa/minalytics/shared"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *ServiceSuite) TestCreateAPIToken() {
+	userID, trackingID := uuid.New(), uuid.New()
+
+	_, err := suite.service.CreateAPIToken(suite.ctx, types.APITokenPayload{UserID: userID, Name: "metabase", Scopes: []string{"admin"}})
+	suite.ErrorIs(err, ErrUnsupportedScope)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = suite.service.CreateAPIToken(suite.ctx, types.APITokenPayload{UserID: userID, Name: "metabase", Scopes: []string{scopeReadStats}, ExpiresAt: &past})
+	suite.ErrorIs(err, ErrInvalidTokenExpiry)
+
+	// tokens are limited to apps the user is a member of
+	suite.mockRepo.EXPECT().GetAppByTrackingID(mock.Anything, trackingID).Return(database.App{UserID: uuid.New(), TrackingID: trackingID}, nil).Once()
+	suite.mockRepo.EXPECT().GetAppMember(mock.Anything, database.GetAppMemberParams{TrackingID: trackingID, UserID: userID}).Return("", pgx.ErrNoRows).Once()
+	_, err = suite.service.CreateAPIToken(suite.ctx, types.APITokenPayload{UserID: userID, Name: "metabase", Scopes: []string{scopeReadStats}, TrackingIDs: []uuid.UUID{trackingID}})
+	suite.ErrorIs(err, ErrAppNotFound)
+
+	var tokenHash string
+	suite.mockRepo.EXPECT().CreateAPIToken(mock.Anything, mock.MatchedBy(func(arg database.CreateAPITokenParams) bool {
+		return arg.UserID == userID && arg.Name == "ingest" && len(arg.TrackingIds) == 0 && !arg.ExpiresAt.Valid
+	})).RunAndReturn(func(_ context.Context, arg database.CreateAPITokenParams) (database.ApiToken, error) {
+		tokenHash = arg.TokenHash
+		return database.ApiToken{ID: uuid.New(), UserID: arg.UserID, Name: arg.Name, TokenHash: arg.TokenHash, Scopes: arg.Scopes, TrackingIds: arg.TrackingIds}, nil
+	}).Once()
+
+	token, err := suite.service.CreateAPIToken(suite.ctx, types.APITokenPayload{UserID: userID, Name: "ingest", Scopes: []string{scopeIngest}})
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(token.Token, apiTokenPrefix))
+	// only the hash of the token is stored
+	suite.Equal(hashToken(token.Token), tokenHash)
+	suite.NotNil(token.TrackingIDs)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestValidateAPIToken() {
+	userID, tokenID := uuid.New(), uuid.New()
+	lastUsedIP := "203.0.113.7"
+	usedAt := func(ago time.Duration) sql.NullTime { return sql.NullTime{Time: time.Now().Add(-ago), Valid: true} }
+
+	// a use from the same IP within a minute isn't written
+	suite.mockRepo.EXPECT().GetAPITokenByHash(mock.Anything, hashToken("mk_live")).
+		Return(database.ApiToken{ID: tokenID, UserID: userID, Scopes: []string{scopeReadStats}, LastUsedAt: usedAt(10 * time.Second), LastUsedIp: &lastUsedIP}, nil).Once()
+	token, err := suite.service.ValidateAPIToken(suite.ctx, "mk_live", lastUsedIP)
+	suite.NoError(err)
+	suite.Equal(userID, token.UserID)
+	suite.Equal(lastUsedIP, token.LastUsedIP)
+
+	// older uses, or uses from another IP, are
+	suite.mockRepo.EXPECT().GetAPITokenByHash(mock.Anything, hashToken("mk_live")).
+		Return(database.ApiToken{ID: tokenID, UserID: userID, Scopes: []string{scopeReadStats}, LastUsedAt: usedAt(2 * time.Minute), LastUsedIp: &lastUsedIP}, nil).Once()
+	suite.mockRepo.EXPECT().TouchAPIToken(mock.Anything, database.TouchAPITokenParams{ID: tokenID, LastUsedIp: &lastUsedIP}).Return(nil).Once()
+	token, err = suite.service.ValidateAPIToken(suite.ctx, "mk_live", lastUsedIP)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now(), *token.LastUsedAt, time.Second)
+
+	otherIP := "198.51.100.4"
+	suite.mockRepo.EXPECT().GetAPITokenByHash(mock.Anything, hashToken("mk_live")).
+		Return(database.ApiToken{ID: tokenID, UserID: userID, Scopes: []string{scopeReadStats}, LastUsedAt: usedAt(10 * time.Second), LastUsedIp: &lastUsedIP}, nil).Once()
+	suite.mockRepo.EXPECT().TouchAPIToken(mock.Anything, database.TouchAPITokenParams{ID: tokenID, LastUsedIp: &otherIP}).Return(nil).Once()
+	token, err = suite.service.ValidateAPIToken(suite.ctx, "mk_live", otherIP)
+	suite.NoError(err)
+	suite.Equal(otherIP, token.LastUsedIP)
+
+	// expired and revoked tokens aren't matched
+	suite.mockRepo.EXPECT().GetAPITokenByHash(mock.Anything, hashToken("mk_revoked")).Return(database.ApiToken{}, pgx.ErrNoRows).Once()
+	_, err = suite.service.ValidateAPIToken(suite.ctx, "mk_revoked", lastUsedIP)
+	suite.ErrorIs(err, ErrInvalidAPIToken)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceSuite) TestTokenAllows() {
+	trackingID := uuid.New()
+	testCases := []struct {
+		name       string
+		token      types.APIToken
+		scopes     []string
+		trackingID uuid.UUID
+		allowed    bool
+	}{
+		{name: "scope held", token: types.APIToken{Scopes: []string{scopeReadStats}}, scopes: []string{scopeReadStats}, trackingID: trackingID, allowed: true},
+		{name: "any of the scopes", token: types.APIToken{Scopes: []string{scopeIngest}}, scopes: []string{scopeIngest, scopeManageApps}, allowed: true},
+		{name: "scope not held", token: types.APIToken{Scopes: []string{scopeIngest}}, scopes: []string{scopeReadStats}, trackingID: trackingID},
+		{name: "no scopes admit no tokens", token: types.APIToken{Scopes: apiTokenScopes}},
+		{name: "app of the token", token: types.APIToken{Scopes: []string{scopeReadStats}, TrackingIDs: []uuid.UUID{trackingID}}, scopes: []string{scopeReadStats}, trackingID: trackingID, allowed: true},
+		{name: "other app", token: types.APIToken{Scopes: []string{scopeReadStats}, TrackingIDs: []uuid.UUID{trackingID}}, scopes: []string{scopeReadStats}, trackingID: uuid.New()},
+		{name: "no app", token: types.APIToken{Scopes: []string{scopeManageApps}, TrackingIDs: []uuid.UUID{trackingID}}, scopes: []string{scopeManageApps}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.allowed, tokenAllows(&tc.token, tc.scopes, tc.trackingID))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"database/sql"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,31 +12,69 @@ import (
 type AnalyticsService interface {
 	SignIn(context.Context, string) (string, error)
 	TrackEvent(context.Context, EventPayload) error
-	CreateApp(context.Context, uuid.UUID, string) (*App, error)
+	CreateApp(context.Context, uuid.UUID, uuid.UUID, string) (*App, error)
 	UpdateApp(context.Context, AppPayload) (*App, error)
 	DeleteApp(context.Context, AppPayload) error
 	GetApps(context.Context, uuid.UUID) ([]App, error)
-	GetReferrals(context.Context, RequestPayload) ([]ReferralStats, error)
-	GetPages(context.Context, RequestPayload) ([]PageStats, error)
-	GetBrowsers(context.Context, RequestPayload) ([]BrowserStats, error)
-	GetCountries(context.Context, RequestPayload) ([]CountryStats, error)
-	GetDevices(context.Context, RequestPayload) ([]DeviceStats, error)
-	GetOS(context.Context, RequestPayload) ([]OSStats, error)
+	GetReferrals(context.Context, BreakdownPayload) (*Breakdown[ReferralStats], error)
+	GetPages(context.Context, BreakdownPayload) (*Breakdown[PageStats], error)
+	GetBrowsers(context.Context, BreakdownPayload) (*Breakdown[BrowserStats], error)
+	GetCountries(context.Context, BreakdownPayload) (*Breakdown[CountryStats], error)
+	GetDevices(context.Context, BreakdownPayload) (*Breakdown[DeviceStats], error)
+	GetOS(context.Context, BreakdownPayload) (*Breakdown[OSStats], error)
 	GetVisitors(context.Context, RequestPayload) ([]VisitorStats, error)
 	GetPageViews(context.Context, RequestPayload) ([]PageViewStats, error)
-	ValidateAppAccess(context.Context, uuid.UUID, uuid.UUID) error
+	GetOverview(context.Context, RequestPayload) (*OverviewStats, error)
+	GetEventTypes(context.Context, RequestPayload) ([]EventTypeStats, error)
+	GetRealtime(context.Context, uuid.UUID) (*RealtimeStats, error)
+	SubscribeEvents(context.Context, uuid.UUID) (<-chan LiveUpdate, func(), error)
+	GetRetention(context.Context, RetentionPayload) ([]RetentionCohort, error)
+	GetUserFlow(context.Context, FlowPayload) (*FlowStats, error)
+	ExportEvents(context.Context, ExportPayload, io.Writer) (int64, error)
+	CreateImport(context.Context, ImportPayload, io.Reader) (*Import, error)
+	GetImports(context.Context, uuid.UUID, uuid.UUID) ([]Import, error)
+	GetImport(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*Import, error)
+	CreateDeletion(context.Context, DeletionPayload) (*Deletion, error)
+	GetDeletions(context.Context, uuid.UUID, uuid.UUID) ([]Deletion, error)
+	GetDeletion(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*Deletion, error)
+	CreateShare(context.Context, SharePayload) (*Share, error)
+	GetShares(context.Context, uuid.UUID, uuid.UUID) ([]Share, error)
+	RevokeShare(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (*Share, error)
+	CreateShareSession(context.Context, string, string) (*ShareSession, error)
+	ValidateShareAccess(context.Context, string, string, string) (*App, error)
+	GetMembers(context.Context, uuid.UUID, uuid.UUID) ([]Member, error)
+	UpdateMember(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, string) error
+	RemoveMember(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error
+	TransferApp(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, uuid.UUID) (*App, error)
+	CreateInvitation(context.Context, InvitationPayload) (*Invitation, error)
+	GetInvitations(context.Context, uuid.UUID, uuid.UUID) ([]Invitation, error)
+	RevokeInvitation(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error
+	GetUserInvitations(context.Context, uuid.UUID) ([]Invitation, error)
+	AcceptInvitation(context.Context, uuid.UUID, uuid.UUID) (*App, error)
+	CreateOrganization(context.Context, uuid.UUID, string) (*Organization, error)
+	GetOrganizations(context.Context, uuid.UUID) ([]Organization, error)
+	GetOrganizationMembers(context.Context, uuid.UUID, uuid.UUID) ([]Member, error)
+	SetOrganizationMember(context.Context, OrganizationMemberPayload) (*Member, error)
+	RemoveOrganizationMember(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error
+	CreateAPIToken(context.Context, APITokenPayload) (*APIToken, error)
+	GetAPITokens(context.Context, uuid.UUID) ([]APIToken, error)
+	RevokeAPIToken(context.Context, uuid.UUID, uuid.UUID) (*APIToken, error)
+	ValidateAPIToken(context.Context, string, string) (*APIToken, error)
+	ValidateAppAccess(context.Context, uuid.UUID, uuid.UUID) (*App, error)
+	ValidateRegexFilters(context.Context, []Filter) error
 	ResolveGeoLocation(string) (*GeoLocation, error)
 	ParseUserAgent(string) *UserAgentDetails
 }
 
 type TrackingData struct {
-	VisitorID  string                 `json:"visitorID"`
-	TrackingID uuid.UUID              `json:"trackingID"`
-	Url        string                 `json:"url"`
-	Referrer   string                 `json:"referrer"`
-	Country    string                 `json:"country"`
-	Ua         string                 `json:"ua"`
-	Details    map[string]interface{} `json:"details"`
+	VisitorID    string                 `json:"visitorID"`
+	PersistentID string                 `json:"persistentID"`
+	TrackingID   uuid.UUID              `json:"trackingID"`
+	Url          string                 `json:"url"`
+	Referrer     string                 `json:"referrer"`
+	Country      string                 `json:"country"`
+	Ua           string                 `json:"ua"`
+	Details      map[string]interface{} `json:"details"`
 }
 
 type EventPayload struct {
@@ -44,9 +83,12 @@ type EventPayload struct {
 }
 
 type AppPayload struct {
-	Name       string
-	TrackingID uuid.UUID
-	UserID     uuid.UUID
+	Name              string
+	TrackingID        uuid.UUID
+	UserID            uuid.UUID
+	RetentionTracking *bool
+	Timezone          *string
+	DataRetention     *string
 }
 
 type GeoLocation struct {
@@ -63,56 +105,259 @@ type UserAgentDetails struct {
 }
 
 type App struct {
-	Name       string    `json:"name"`
-	TrackingID uuid.UUID `json:"trackingID"`
-	CreatedAt  time.Time `json:"created_at"`
+	Name              string    `json:"name"`
+	TrackingID        uuid.UUID `json:"trackingID"`
+	OrgID             uuid.UUID `json:"org_id"`
+	RetentionTracking bool      `json:"retention_tracking"`
+	Timezone          string    `json:"timezone"`
+	DataRetention     string    `json:"data_retention"`
+	CreatedAt         time.Time `json:"created_at"`
+	// ImportedSince is the start of the data imported into the app, when it
+	// goes back further than CreatedAt.
+	ImportedSince *time.Time `json:"imported_since,omitempty"`
+	// Role is the role in the app of the user asking for it.
+	Role string `json:"role,omitempty"`
+}
+
+// BreakdownStats holds the counts of a breakdown row. Percentage is the share
+// of visitors, out of the total visitors in the breakdown's metadata.
+type BreakdownStats struct {
+	Visitors   int     `json:"visitors"`
+	Pageviews  int     `json:"pageviews"`
+	Events     int     `json:"events"`
+	Percentage float64 `json:"percentage"`
 }
 
 type ReferralStats struct {
-	Referrer     string `json:"referrer"`
-	VisitorCount int    `json:"visitor_count"`
+	Referrer string `json:"referrer"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type PageStats struct {
-	Path         string `json:"path"`
-	VisitorCount int    `json:"visitor_count"`
+	Path string `json:"path"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type BrowserStats struct {
-	Browser    string `json:"browser"`
-	Percentage int    `json:"percentage"`
+	Browser string `json:"browser"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type CountryStats struct {
-	Country    string `json:"country"`
-	Percentage int    `json:"percentage"`
+	Country string `json:"country"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type DeviceStats struct {
-	Device     string `json:"device"`
-	Percentage int    `json:"percentage"`
+	Device string `json:"device"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type OSStats struct {
-	OS         string `json:"operating_system"`
-	Percentage int    `json:"percentage"`
+	OS string `json:"operating_system"`
+	BreakdownStats
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type VisitorStats struct {
-	Time     string `json:"time"`
-	Visitors int    `json:"visitors"`
+	Time       string      `json:"time"`
+	Visitors   int         `json:"visitors"`
+	Comparison *Comparison `json:"comparison,omitempty"`
 }
 
 type PageViewStats struct {
-	Time  string `json:"time"`
-	Views int    `json:"views"`
+	Time       string      `json:"time"`
+	Views      int         `json:"views"`
+	Comparison *Comparison `json:"comparison,omitempty"`
+}
+
+// Breakdown is one page of a breakdown report.
+type Breakdown[T any] struct {
+	Results []T           `json:"results"`
+	Other   *T            `json:"other,omitempty"`
+	Meta    BreakdownMeta `json:"meta"`
+}
+
+// BreakdownMeta describes a page of a breakdown. Total counts the rows across
+// every page, and TotalVisitors is the number of distinct visitors in the
+// range, the denominator of every row's percentage. Precision tells whether
+// visitor counts are exact or estimated from sketches.
+type BreakdownMeta struct {
+	Total         int    `json:"total"`
+	Page          int    `json:"page"`
+	Limit         int    `json:"limit"`
+	TotalVisitors int    `json:"total_visitors"`
+	Precision     string `json:"precision"`
+}
+
+// OverviewStats holds the headline numbers for a range. A visit is a run of
+// events from one visitor without a gap longer than 30 minutes, and a bounce
+// is a visit with a single event. VisitDuration is the average in seconds.
+type OverviewStats struct {
+	Visitors      int                    `json:"visitors"`
+	PageViews     int                    `json:"pageviews"`
+	Visits        int                    `json:"visits"`
+	ViewsPerVisit float64                `json:"views_per_visit"`
+	BounceRate    float64                `json:"bounce_rate"`
+	VisitDuration float64                `json:"visit_duration"`
+	Events        int                    `json:"events"`
+	Comparison    map[string]*Comparison `json:"comparison,omitempty"`
+}
+
+// EventTypeStats counts the events of a type in a range, pageviews included,
+// and the visitors who sent them.
+type EventTypeStats struct {
+	EventType string `json:"event_type"`
+	Visitors  int    `json:"visitors"`
+	Events    int    `json:"events"`
+}
+
+// RealtimeStats describes visitors active in the last five minutes.
+type RealtimeStats struct {
+	CurrentVisitors int             `json:"current_visitors"`
+	Pages           []PageStats     `json:"pages"`
+	Sources         []ReferralStats `json:"sources"`
+}
+
+type LiveEvent struct {
+	Type      string    `json:"type"`
+	Path      string    `json:"path"`
+	Referrer  string    `json:"referrer"`
+	Country   string    `json:"country"`
+	Browser   string    `json:"browser"`
+	Device    string    `json:"device"`
+	OS        string    `json:"operating_system"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LiveUpdate is pushed to live stream subscribers for every tracked event.
+type LiveUpdate struct {
+	Event           LiveEvent `json:"event"`
+	CurrentVisitors int       `json:"current_visitors"`
+}
+
+type RetentionPeriod struct {
+	Period     int     `json:"period"`
+	Visitors   int     `json:"visitors"`
+	Percentage float64 `json:"percentage"`
+}
+
+type RetentionCohort struct {
+	Cohort    string            `json:"cohort"`
+	Visitors  int               `json:"visitors"`
+	Retention []RetentionPeriod `json:"retention"`
+}
+
+type FlowNode struct {
+	ID       string `json:"id"`
+	Step     string `json:"step"`
+	Depth    int    `json:"depth"`
+	Visitors int    `json:"visitors"`
+}
+
+type FlowLink struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Visitors int    `json:"visitors"`
+}
+
+type FlowStats struct {
+	Nodes []FlowNode `json:"nodes"`
+	Links []FlowLink `json:"links"`
+}
+
+type Filter struct {
+	Dimension string   `json:"dimension"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+}
+
+// Comparison holds the value of a row or time bucket in the comparison range
+// and how the current value changed from it. PercentageChange is nil when the
+// comparison value is zero.
+type Comparison struct {
+	Time             string   `json:"time,omitempty"`
+	Value            float64  `json:"value"`
+	Change           float64  `json:"change"`
+	PercentageChange *float64 `json:"percentage_change"`
 }
 
 type RequestPayload struct {
+	TrackingID       uuid.UUID
+	BucketSize       string
+	StartDate        sql.NullTime
+	EndDate          sql.NullTime
+	Timezone         string
+	Filters          []Filter
+	Compare          string
+	CompareStartDate sql.NullTime
+	CompareEndDate   sql.NullTime
+	Precision        string
+}
+
+type RetentionPayload struct {
+	RequestPayload
+	Period string
+}
+
+// BreakdownPayload selects a page of a breakdown report. Sort is one of
+// visitors:desc, visitors:asc, name:asc or name:desc, and Other adds a row
+// aggregating every row after the page.
+type BreakdownPayload struct {
+	RequestPayload
+	Limit int
+	Page  int
+	Sort  string
+	Other bool
+}
+
+// ExportPayload selects the raw events to export, the columns to export and
+// the format, one of csv, ndjson or parquet.
+type ExportPayload struct {
+	RequestPayload
+	Format  string
+	Columns []string
+}
+
+// ImportPayload describes an upload of another tool's export into an app.
+// Hostname completes page paths of exports that have none, and WebsiteID picks
+// the website of an Umami dump that holds several.
+type ImportPayload struct {
+	UserID     uuid.UUID
 	TrackingID uuid.UUID
-	BucketSize string
-	StartDate  sql.NullTime
-	EndDate    sql.NullTime
+	Source     string
+	Hostname   string
+	WebsiteID  string
+	LogFormat  string
+}
+
+// Import is the state of an import. Progress goes from 0 to 1, and StartDate
+// and EndDate bound the events imported so far.
+type Import struct {
+	ID             uuid.UUID  `json:"id"`
+	TrackingID     uuid.UUID  `json:"trackingID"`
+	Source         string     `json:"source"`
+	Status         string     `json:"status"`
+	Progress       float64    `json:"progress"`
+	ImportedEvents int64      `json:"imported_events"`
+	SkippedEvents  int64      `json:"skipped_events"`
+	StartDate      *time.Time `json:"start_date,omitempty"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+type FlowPayload struct {
+	RequestPayload
+	Step      string
+	Direction string
+	Steps     int
 }
 
 type APIResponse struct {
@@ -131,33 +376,194 @@ type AppResponse struct {
 	APIStatus
 }
 
+type ImportResponse struct {
+	Data Import
+	APIStatus
+}
+
+type ImportsResponse struct {
+	Data []Import
+	APIStatus
+}
+
+// DeletionPayload describes the events of an app to delete: those in the range
+// from StartDate to EndDate that match Filters and VisitorID, of which at least
+// one is set. A dry run only counts them.
+type DeletionPayload struct {
+	UserID     uuid.UUID
+	TrackingID uuid.UUID
+	StartDate  sql.NullTime
+	EndDate    sql.NullTime
+	Filters    []Filter
+	VisitorID  string
+	DryRun     bool
+}
+
+type DeletionRequest struct {
+	// StartDate and EndDate are YYYY-MM-DD dates, the end one included, or
+	// RFC3339 times, the end one excluded.
+	StartDate string   `json:"start_date"`
+	EndDate   string   `json:"end_date"`
+	Filters   []Filter `json:"filters"`
+	VisitorID string   `json:"visitor_id"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// Deletion is the audit record of a deletion. It outlives the app and the
+// user, whose name and email are kept; UserID is unset once the user is gone.
+type Deletion struct {
+	ID              uuid.UUID  `json:"id"`
+	TrackingID      uuid.UUID  `json:"trackingID"`
+	AppName         string     `json:"app_name"`
+	UserID          *uuid.UUID `json:"user_id,omitempty"`
+	UserEmail       string     `json:"user_email"`
+	Status          string     `json:"status"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	EndDate         *time.Time `json:"end_date,omitempty"`
+	Filters         []Filter   `json:"filters,omitempty"`
+	VisitorID       string     `json:"visitor_id,omitempty"`
+	MatchedEvents   int64      `json:"matched_events"`
+	MatchedVisitors int64      `json:"matched_visitors"`
+	DeletedEvents   int64      `json:"deleted_events"`
+	FirstEvent      *time.Time `json:"first_event,omitempty"`
+	LastEvent       *time.Time `json:"last_event,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+type DeletionResponse struct {
+	Data Deletion
+	APIStatus
+}
+type DeletionsResponse struct {
+	Data []Deletion
+	APIStatus
+}
+
+// SharePayload describes a share link to the stats of an app. Endpoints are
+// the analytics endpoints it opens up, all of them when empty.
+type SharePayload struct {
+	UserID     uuid.UUID
+	TrackingID uuid.UUID
+	Name       string
+	Endpoints  []string
+	Password   string
+	ExpiresAt  *time.Time
+}
+
+type ShareRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Endpoints []string   `json:"endpoints"`
+	Password  string     `json:"password" binding:"max=72"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type Share struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"trackingID"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	// Token is the secret of the link, only returned when it is created.
+	Token             string     `json:"token,omitempty"`
+	Endpoints         []string   `json:"endpoints"`
+	PasswordProtected bool       `json:"password_protected"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+type ShareResponse struct {
+	Data Share
+	APIStatus
+}
+
+type ShareSessionRequest struct {
+	Password string `json:"password"`
+}
+
+// ShareSession opens up a password protected share link until ExpiresAt. It
+// is also set as a cookie, or it can be sent in the X-Share-Session header.
+type ShareSession struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+type ShareSessionResponse struct {
+	Data ShareSession
+	APIStatus
+}
+type SharesResponse struct {
+	Data []Share
+	APIStatus
+}
+
+// APITokenPayload describes an API token of a user. Scopes are what it is
+// allowed, out of read-stats, manage-apps and ingest, and TrackingIDs the apps
+// it is limited to, all of the user's when empty.
+type APITokenPayload struct {
+	UserID      uuid.UUID
+	Name        string
+	Scopes      []string
+	TrackingIDs []uuid.UUID
+	ExpiresAt   *time.Time
+}
+
+type APITokenRequest struct {
+	Name        string      `json:"name" binding:"required"`
+	Scopes      []string    `json:"scopes" binding:"required,min=1"`
+	TrackingIDs []uuid.UUID `json:"tracking_ids"`
+	ExpiresAt   *time.Time  `json:"expires_at"`
+}
+
+type APIToken struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// Token is the secret of the token, starting with mk_, only returned when
+	// it is created.
+	Token       string      `json:"token,omitempty"`
+	Scopes      []string    `json:"scopes"`
+	TrackingIDs []uuid.UUID `json:"tracking_ids"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time  `json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time  `json:"last_used_at,omitempty"`
+	LastUsedIP  string      `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+type APITokenResponse struct {
+	Data APIToken
+	APIStatus
+}
+type APITokensResponse struct {
+	Data []APIToken
+	APIStatus
+}
+
 type ReferralResponse struct {
-	Data ReferralStats
+	Data Breakdown[ReferralStats]
 	APIStatus
 }
 
 type PageResponse struct {
-	Data PageStats
+	Data Breakdown[PageStats]
 	APIStatus
 }
 
 type BrowserResponse struct {
-	Data BrowserStats
+	Data Breakdown[BrowserStats]
 	APIStatus
 }
 
 type CountryResponse struct {
-	Data CountryStats
+	Data Breakdown[CountryStats]
 	APIStatus
 }
 
 type DeviceResponse struct {
-	Data DeviceStats
+	Data Breakdown[DeviceStats]
 	APIStatus
 }
 
 type OSResponse struct {
-	Data OSStats
+	Data Breakdown[OSStats]
 	APIStatus
 }
 
@@ -171,8 +577,142 @@ type PageViewResponse struct {
 	APIStatus
 }
 
+type OverviewResponse struct {
+	Data OverviewStats
+	APIStatus
+}
+type EventTypesResponse struct {
+	Data []EventTypeStats
+	APIStatus
+}
+
+type RealtimeResponse struct {
+	Data RealtimeStats
+	APIStatus
+}
+
+type RetentionResponse struct {
+	Data []RetentionCohort
+	APIStatus
+}
+
+type FlowResponse struct {
+	Data FlowStats
+	APIStatus
+}
+
 type CreateAppRequest struct {
 	Name string `json:"name" binding:"required"`
+	// OrgID is the organization the app goes into, the personal organization
+	// of the user when unset.
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+type Member struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type MemberResponse struct {
+	Data Member
+	APIStatus
+}
+type MembersResponse struct {
+	Data []Member
+	APIStatus
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type TransferAppRequest struct {
+	// UserID is the member the app is transferred to.
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	// OrgID is an organization the member owns to move the app into, their
+	// personal organization when left out.
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+// InvitationPayload invites the person with Email to an app with Role, which
+// is admin, editor or viewer.
+type InvitationPayload struct {
+	UserID     uuid.UUID
+	TrackingID uuid.UUID
+	Email      string
+	Role       string
+}
+
+type InvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type Invitation struct {
+	ID         uuid.UUID `json:"id"`
+	TrackingID uuid.UUID `json:"trackingID"`
+	// AppName is set on the invitations of the user asking for them.
+	AppName   string    `json:"app_name,omitempty"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type InvitationResponse struct {
+	Data Invitation
+	APIStatus
+}
+type InvitationsResponse struct {
+	Data []Invitation
+	APIStatus
+}
+
+type Organization struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Personal is set on the personal organization of the user asking for it,
+	// which their apps go into by default.
+	Personal bool `json:"personal"`
+	// Role is the role in the organization of the user asking for it.
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+type OrganizationResponse struct {
+	Data Organization
+	APIStatus
+}
+type OrganizationsResponse struct {
+	Data []Organization
+	APIStatus
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// OrganizationMemberPayload gives the user signed in with Email Role in an
+// organization, adding them to it if they aren't a member yet.
+type OrganizationMemberPayload struct {
+	UserID uuid.UUID
+	OrgID  uuid.UUID
+	Email  string
+	Role   string
+}
+
+type OrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type UpdateAppRequest struct {
+	Name              *string `json:"name"`
+	RetentionTracking *bool   `json:"retention_tracking"`
+	Timezone          *string `json:"timezone"`
+	// DataRetention is how long raw events are kept, such as "30 days" or
+	// "13 months", or "forever". EVENTS_RETENTION still drops older events, so
+	// forever is only as long as it.
+	DataRetention *string `json:"data_retention"`
 }
 
 func NewSuccessResponse(data interface{}, code int, message string) APIResponse {
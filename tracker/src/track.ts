@@ -1,8 +1,11 @@
 export const ENDPOINT_URL: string = "http://localhost:3000/track"
+// retention identifiers are rotated after this many days
+export const RETENTION_HORIZON_DAYS: number = 365
 
 export interface ITrackingData {
   url?: string;
   visitorId: string;
+  persistentId?: string;
   trackingId: string;
   referrer: string | null;
   ua: string;
@@ -18,13 +21,15 @@ export class Analytics {
   private visitorId: string | null = null;
   private trackingId: string;
   private referrer: string | null;
+  private retention: boolean;
 
 
-  constructor(trackingId: string) {
+  constructor(trackingId: string, retention: boolean = false) {
     if (!trackingId) {
       throw new Error("Tracking ID is required");
     }
     this.trackingId = trackingId;
+    this.retention = retention;
     this.referrer = this.getReferrer();
   }
 
@@ -38,6 +43,9 @@ export class Analytics {
       referrer: this.referrer,
       ua: navigator.userAgent,
     };
+    if (this.retention) {
+      trackingData.persistentId = this.getPersistentId();
+    }
     if (type === "pageview") {
       trackingData.url = window.location.href;
     }
@@ -73,6 +81,24 @@ export class Analytics {
     return this.createHash(components);
   }
 
+  // opt-in only: a random per-app identifier kept in localStorage so that
+  // returning visitors can be grouped into retention cohorts
+  private getPersistentId(): string {
+    const key = `minalytics_${this.trackingId}`;
+    const horizon = RETENTION_HORIZON_DAYS * 24 * 60 * 60 * 1000;
+    try {
+      const stored = JSON.parse(localStorage.getItem(key) || "null");
+      if (stored && Date.now() - stored.createdAt < horizon) {
+        return stored.id;
+      }
+      const id = crypto.randomUUID();
+      localStorage.setItem(key, JSON.stringify({ id, createdAt: Date.now() }));
+      return id;
+    } catch {
+      return "";
+    }
+  }
+
   private async createHash(input: string): Promise<string> {
     const encoder = new TextEncoder();
     const data = encoder.encode(input);
@@ -107,7 +133,8 @@ export class Analytics {
     console.error("Analytics: No tracking ID provided");
     return;
   }
-  const analytics = new Analytics(trackingId);
+  const retention = script?.getAttribute("retention") === "true";
+  const analytics = new Analytics(trackingId, retention);
   await analytics.track();
   analytics.trackSubsequentPages();
   w._analytics = analytics;